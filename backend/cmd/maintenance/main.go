@@ -16,13 +16,39 @@ import (
 
 // Database maintenance script
 // Purpose: Clean up old refresh tokens and maintain database health
-// Usage: go run cmd/maintenance/main.go
+// Usage: go run ./cmd/maintenance
 // Or schedule as cron job: 0 2 * * * /path/to/maintenance
+//
+// Subcommands:
+//   reconcile-stock  Compare stock figures with movement history (see reconcile_stock.go)
+//                    go run ./cmd/maintenance reconcile-stock -company=<id> [-repair] [-dry-run]
+
+// TokenStats - refresh token counts before and after cleanup
+type TokenStats struct {
+	Total   int64
+	Active  int64
+	Revoked int64
+	Users   int64
+}
 
 func main() {
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	title := "Refresh Token Cleanup"
+	switch command {
+	case "":
+	case "reconcile-stock":
+		title = "Stock Reconciliation"
+	default:
+		log.Fatalf("Unknown command %q (available: reconcile-stock)", command)
+	}
+
 	fmt.Println("═══════════════════════════════════════════════")
 	fmt.Println("  ERP Database Maintenance Script")
-	fmt.Println("  Refresh Token Cleanup")
+	fmt.Printf("  %s\n", title)
 	fmt.Println("═══════════════════════════════════════════════")
 	fmt.Println()
 
@@ -51,8 +77,12 @@ func main() {
 	log.Println("✅ Connected to database")
 	fmt.Println()
 
-	// Run cleanup operations
-	if err := cleanupRefreshTokens(db); err != nil {
+	// Run the requested operation
+	if command == "reconcile-stock" {
+		if err := reconcileStock(db, os.Args[2:]); err != nil {
+			log.Fatalf("Stock reconciliation failed: %v", err)
+		}
+	} else if err := cleanupRefreshTokens(db); err != nil {
 		log.Fatalf("Cleanup failed: %v", err)
 	}
 
@@ -67,13 +97,6 @@ func cleanupRefreshTokens(db *gorm.DB) error {
 	fmt.Println()
 
 	// Step 1: Show statistics BEFORE cleanup
-	type TokenStats struct {
-		Total   int64
-		Active  int64
-		Revoked int64
-		Users   int64
-	}
-
 	var beforeStats TokenStats
 	db.Model(&auth.RefreshToken{}).Count(&beforeStats.Total)
 	db.Model(&auth.RefreshToken{}).Where("is_revoked = ?", false).Count(&beforeStats.Active)
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"backend/internal/service/audit"
+	"backend/internal/service/inventory"
+	"backend/models"
+)
+
+// reconcileStock compares WarehouseStock, batch totals and Product.CurrentStock with the
+// InventoryMovement history, per company, and optionally posts correcting movements.
+//
+// Flags:
+//
+//	-company=<id>    Company to check (or -all for every active company)
+//	-warehouse=<id>  Limit to one warehouse
+//	-product=<id>    Limit to one product
+//	-repair          Post correcting ADJUSTMENT movements and re-sync Product.CurrentStock
+//	-dry-run         With -repair: show what would be corrected without writing
+//	-notes=<text>    Notes recorded on the correcting movements and the audit log
+func reconcileStock(db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("reconcile-stock", flag.ContinueOnError)
+	companyID := flags.String("company", "", "company ID to check")
+	all := flags.Bool("all", false, "check every active company")
+	warehouseID := flags.String("warehouse", "", "limit to one warehouse ID")
+	productID := flags.String("product", "", "limit to one product ID")
+	repair := flags.Bool("repair", false, "post correcting movements")
+	dryRun := flags.Bool("dry-run", false, "with -repair: report corrections without writing")
+	notes := flags.String("notes", "", "notes for the correcting movements")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *companyID == "" && !*all {
+		return fmt.Errorf("either -company=<id> or -all is required")
+	}
+
+	var companies []models.Company
+	query := db.Model(&models.Company{})
+	if *all {
+		query = query.Where("is_active = ?", true)
+	} else {
+		query = query.Where("id = ?", *companyID)
+	}
+	if err := query.Order("name ASC").Find(&companies).Error; err != nil {
+		return fmt.Errorf("failed to load companies: %w", err)
+	}
+	if len(companies) == 0 {
+		return fmt.Errorf("no company found")
+	}
+
+	opts := &inventory.ReconciliationOptions{
+		Repair: *repair,
+		DryRun: *dryRun,
+	}
+	if *warehouseID != "" {
+		opts.WarehouseID = warehouseID
+	}
+	if *productID != "" {
+		opts.ProductID = productID
+	}
+	if *notes != "" {
+		opts.Notes = notes
+	}
+
+	service := inventory.NewStockReconciliationService(db, inventory.NewStockPostingService(db), audit.NewAuditService(db))
+	for _, company := range companies {
+		log.Printf("🔍 Reconciling stock of %s...", company.Name)
+
+		report, err := service.Reconcile(context.Background(), company.TenantID, company.ID, opts)
+		if err != nil {
+			return fmt.Errorf("company %s: %w", company.Name, err)
+		}
+
+		fmt.Printf("   Stock rows checked: %d\n", report.StockRows)
+		if len(report.Discrepancies) == 0 {
+			fmt.Println("   ✅ No discrepancies")
+			fmt.Println()
+			continue
+		}
+
+		fmt.Printf("   ⚠️  %d discrepancies:\n", len(report.Discrepancies))
+		for _, d := range report.Discrepancies {
+			location := d.WarehouseCode
+			if location == "" {
+				location = "(all warehouses)"
+			}
+			status := ""
+			switch {
+			case d.Repaired:
+				status = " [REPAIRED]"
+			case report.DryRun && d.Repairable:
+				status = " [WOULD REPAIR]"
+			case !d.Repairable:
+				status = " [NOT REPAIRABLE]"
+			}
+			fmt.Printf("   • %-21s %s %s: expected %s, found %s (diff %s)%s\n",
+				d.Type, location, d.ProductCode, d.Expected, d.Actual, d.Difference, status)
+			fmt.Printf("     cause: %s\n", d.LikelyCause)
+		}
+		if report.ReferenceNumber != nil {
+			fmt.Printf("   ✅ Repaired %d discrepancies (%s)\n", report.RepairedCount, *report.ReferenceNumber)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -27,7 +27,7 @@ func main() {
 
 	// TODO: Implement background job scheduler
 	// - Subscription billing
-	// - Batch expiry monitoring
+	// - Batch expiry monitoring (runs in the server scheduler, see internal/jobs/inventory.go)
 	// - Outstanding amount recalculation
 	// - Notification sending
 
@@ -138,12 +138,23 @@ func AutoMigrateAuth(db *gorm.DB) error {
 // CRITICAL: Order matters - parent tables before child tables
 func AutoMigratePhase4(db *gorm.DB) error {
 	if err := db.AutoMigrate(
+		// Bin locations (must exist before movements reference them)
+		&models.WarehouseBin{},
+		&models.BinStock{},
+
 		// Inventory tracking
 		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.LandedCostAllocation{},
+		&models.StockSnapshot{},
+		&models.StockSnapshotItem{},
 
 		// Stock opname (physical count)
+		&models.CycleCountPlan{},
 		&models.StockOpname{},
 		&models.StockOpnameItem{},
+		&models.CycleCountCoverage{},
 
 		// Inter-warehouse transfer
 		&models.StockTransfer{},
@@ -153,6 +164,41 @@ func AutoMigratePhase4(db *gorm.DB) error {
 		&models.InventoryAdjustment{},
 		&models.InventoryAdjustmentItem{},
 
+		// Product recall (batch hold and traceability)
+		&models.ProductRecall{},
+		&models.ProductRecallBatch{},
+		&models.ProductRecallDelivery{},
+
+		// Quality hold (quarantine until release or scrap)
+		&models.QualityHold{},
+		&models.QualityHoldAction{},
+
+		// Customer returns (RMA) and sales credit notes
+		&models.CustomerReturn{},
+		&models.CustomerReturnItem{},
+		&models.SalesCreditNote{},
+		&models.SalesCreditNoteItem{},
+		&models.SalesCreditNoteApplication{},
+		&models.SalesCreditNoteRefund{},
+
+		// Sales debit notes
+		&models.SalesDebitNote{},
+		&models.SalesDebitNoteItem{},
+
+		// Consignment sell-through settlements
+		&models.ConsignmentSettlement{},
+		&models.ConsignmentSettlementItem{},
+
+		// Serial number tracking
+		&models.ProductSerial{},
+		&models.ProductSerialEvent{},
+		&models.DocumentLineSerial{},
+
+		// Bill of materials and assembly orders
+		&models.ProductBOMComponent{},
+		&models.AssemblyOrder{},
+		&models.AssemblyOrderItem{},
+
 		// Cash book (Buku Kas)
 		&models.CashTransaction{},
 
@@ -170,5 +216,9 @@ func AutoMigratePhase4(db *gorm.DB) error {
 	// This is needed because we use empty string pattern for unique index instead of NULL
 	db.Exec("ALTER TABLE IF EXISTS delivery_tolerances DROP CONSTRAINT IF EXISTS fk_delivery_tolerances_product")
 
+	// Batches are unique per product per warehouse (idx_batch_product_stock) so transfers can
+	// move a batch between warehouses; drop the old product-wide unique index
+	db.Exec("DROP INDEX IF EXISTS idx_batch_product")
+
 	return nil
 }
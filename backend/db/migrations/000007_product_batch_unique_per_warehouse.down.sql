@@ -0,0 +1,5 @@
+-- Restore batch number uniqueness per product
+-- Note: This will only work if no batch number exists in more than one warehouse
+
+DROP INDEX IF EXISTS idx_batch_product_stock;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_product ON product_batches (batch_number, product_id);
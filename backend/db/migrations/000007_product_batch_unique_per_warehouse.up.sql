@@ -0,0 +1,6 @@
+-- Allow the same batch number to exist in several warehouses
+-- Stock transfers move batch-tracked stock between warehouses, so a batch is now
+-- unique per product per warehouse stock row instead of per product only
+
+DROP INDEX IF EXISTS idx_batch_product;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_product_stock ON product_batches (batch_number, product_id, warehouse_stock_id);
@@ -0,0 +1,6 @@
+ALTER TABLE invoice_items DROP COLUMN IF EXISTS base_quantity;
+ALTER TABLE delivery_items DROP COLUMN IF EXISTS base_quantity;
+ALTER TABLE goods_receipt_items DROP COLUMN IF EXISTS base_accepted_qty;
+ALTER TABLE goods_receipt_items DROP COLUMN IF EXISTS base_received_qty;
+ALTER TABLE purchase_order_items DROP COLUMN IF EXISTS base_quantity;
+ALTER TABLE sales_order_items DROP COLUMN IF EXISTS base_quantity;
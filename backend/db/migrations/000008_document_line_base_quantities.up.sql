@@ -0,0 +1,18 @@
+-- Store the base-unit quantity next to the entered quantity/unit on document lines
+-- Stock postings, reservations, tolerance checks and reports use the base quantity;
+-- existing lines are backfilled with their unit's current conversion rate
+
+ALTER TABLE sales_order_items ADD COLUMN IF NOT EXISTS base_quantity DECIMAL(15,3) DEFAULT 0;
+ALTER TABLE purchase_order_items ADD COLUMN IF NOT EXISTS base_quantity DECIMAL(15,3) DEFAULT 0;
+ALTER TABLE goods_receipt_items ADD COLUMN IF NOT EXISTS base_received_qty DECIMAL(15,3) DEFAULT 0;
+ALTER TABLE goods_receipt_items ADD COLUMN IF NOT EXISTS base_accepted_qty DECIMAL(15,3) DEFAULT 0;
+ALTER TABLE delivery_items ADD COLUMN IF NOT EXISTS base_quantity DECIMAL(15,3) DEFAULT 0;
+ALTER TABLE invoice_items ADD COLUMN IF NOT EXISTS base_quantity DECIMAL(15,3) DEFAULT 0;
+
+UPDATE sales_order_items i SET base_quantity = i.quantity * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1);
+UPDATE purchase_order_items i SET base_quantity = i.quantity * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1);
+UPDATE goods_receipt_items i SET
+    base_received_qty = i.received_qty * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1),
+    base_accepted_qty = i.accepted_qty * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1);
+UPDATE delivery_items i SET base_quantity = i.quantity * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1);
+UPDATE invoice_items i SET base_quantity = i.quantity * COALESCE((SELECT pu.conversion_rate FROM product_units pu WHERE pu.id = i.product_unit_id), 1);
@@ -182,6 +182,9 @@ type JobConfig struct {
 	EmailCleanup           string
 	PasswordCleanup        string
 	LoginCleanup           string
+	BatchExpiryMonitor     string
+	StockSnapshot          string
+	CycleCountPlans        string
 }
 
 // Validate validates the configuration
@@ -141,6 +141,9 @@ func Load() (*Config, error) {
 			EmailCleanup:        getEnv("JOB_EMAIL_CLEANUP", "0 5 * * * *"),            // Hourly at :05
 			PasswordCleanup:     getEnv("JOB_PASSWORD_CLEANUP", "0 10 * * * *"),        // Hourly at :10
 			LoginCleanup:        getEnv("JOB_LOGIN_CLEANUP", "0 0 2 * * *"),            // Daily at 2 AM
+			BatchExpiryMonitor:  getEnv("JOB_BATCH_EXPIRY_MONITOR", "0 30 0 * * *"),    // Daily at 00:30
+			StockSnapshot:       getEnv("JOB_STOCK_SNAPSHOT", "0 45 0 * * *"),          // Daily at 00:45
+			CycleCountPlans:     getEnv("JOB_CYCLE_COUNT_PLANS", "0 15 1 * * *"),       // Daily at 01:15
 		},
 	}
 
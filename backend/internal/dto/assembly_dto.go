@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// BILL OF MATERIALS & ASSEMBLY ORDER DTOs
+// Products built from other products (gift parcels, hampers, bundles)
+// ============================================================================
+
+// SetProductBOMRequest - Replaces the bill of materials of a product (empty list removes it)
+type SetProductBOMRequest struct {
+	Components []ProductBOMComponentRequest `json:"components" binding:"omitempty,dive"`
+}
+
+// ProductBOMComponentRequest - Component quantity per one base unit of the finished product
+type ProductBOMComponentRequest struct {
+	ComponentProductID string  `json:"componentProductId" binding:"required,uuid"`
+	ProductUnitID      *string `json:"productUnitId" binding:"omitempty,uuid"` // Default: component base unit
+	Quantity           string  `json:"quantity" binding:"required"`            // decimal as string
+	Notes              *string `json:"notes" binding:"omitempty"`
+}
+
+// ProductBOMComponentResponse - Response DTO for a bill of materials component
+type ProductBOMComponentResponse struct {
+	ID               string                `json:"id"`
+	ComponentProduct *ProductBasicResponse `json:"componentProduct,omitempty"`
+	ProductUnitID    *string               `json:"productUnitId,omitempty"`
+	UnitName         string                `json:"unitName"`
+	Quantity         string                `json:"quantity"`
+	BaseQuantity     string                `json:"baseQuantity"` // Quantity in component base unit
+	Notes            *string               `json:"notes,omitempty"`
+}
+
+// CreateAssemblyOrderRequest - Request to create an assembly or disassembly order (DRAFT)
+// Components are taken from the finished product's bill of materials
+type CreateAssemblyOrderRequest struct {
+	Type        string  `json:"type" binding:"required,oneof=ASSEMBLY DISASSEMBLY"`
+	OrderDate   string  `json:"orderDate" binding:"required"` // YYYY-MM-DD
+	WarehouseID string  `json:"warehouseId" binding:"required,uuid"`
+	ProductID   string  `json:"productId" binding:"required,uuid"`       // Finished product
+	Quantity    string  `json:"quantity" binding:"required"`             // Finished product, base unit
+	BatchID     *string `json:"batchId" binding:"omitempty,uuid"`        // DISASSEMBLY: finished batch to take apart (default FEFO)
+	BatchNumber *string `json:"batchNumber" binding:"omitempty,max=100"` // ASSEMBLY: batch of a batch-tracked finished product (default order number)
+	ExpiryDate  *string `json:"expiryDate" binding:"omitempty"`          // ASSEMBLY: YYYY-MM-DD (default earliest component expiry)
+	Notes       *string `json:"notes" binding:"omitempty"`
+}
+
+// AssemblyOrderQuery - Query parameters for listing assembly orders
+type AssemblyOrderQuery struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search      string `form:"search" binding:"omitempty"`
+	Type        string `form:"type" binding:"omitempty,oneof=ASSEMBLY DISASSEMBLY"`
+	Status      string `form:"status" binding:"omitempty,oneof=DRAFT COMPLETED CANCELLED"`
+	WarehouseID string `form:"warehouse_id" binding:"omitempty,uuid"`
+	ProductID   string `form:"product_id" binding:"omitempty,uuid"`
+}
+
+// AssemblyOrderResponse - Response DTO for an assembly order
+type AssemblyOrderResponse struct {
+	ID            string                      `json:"id"`
+	OrderNumber   string                      `json:"orderNumber"`
+	OrderDate     string                      `json:"orderDate"`
+	Type          string                      `json:"type"`
+	Status        string                      `json:"status"`
+	WarehouseID   string                      `json:"warehouseId"`
+	WarehouseName string                      `json:"warehouseName"`
+	Product       *ProductBasicResponse       `json:"product,omitempty"`
+	Quantity      string                      `json:"quantity"`
+	BatchID       *string                     `json:"batchId,omitempty"`
+	BatchNumber   *string                     `json:"batchNumber,omitempty"`
+	ExpiryDate    *string                     `json:"expiryDate,omitempty"`
+	UnitCost      string                      `json:"unitCost"` // Rolled-up cost per finished base unit
+	TotalCost     string                      `json:"totalCost"`
+	Notes         *string                     `json:"notes,omitempty"`
+	CompletedBy   *string                     `json:"completedBy,omitempty"`
+	CompletedAt   *time.Time                  `json:"completedAt,omitempty"`
+	CreatedBy     *string                     `json:"createdBy,omitempty"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	UpdatedAt     time.Time                   `json:"updatedAt"`
+	Items         []AssemblyOrderItemResponse `json:"items,omitempty"`
+}
+
+// AssemblyOrderItemResponse - Component consumed or recovered by an assembly order
+type AssemblyOrderItemResponse struct {
+	ID            string                `json:"id"`
+	Product       *ProductBasicResponse `json:"product,omitempty"`
+	ProductUnitID *string               `json:"productUnitId,omitempty"`
+	UnitName      string                `json:"unitName"`
+	BatchID       *string               `json:"batchId,omitempty"`
+	BatchNumber   *string               `json:"batchNumber,omitempty"`
+	Quantity      string                `json:"quantity"`
+	BaseQuantity  string                `json:"baseQuantity"`
+	UnitCost      string                `json:"unitCost"` // Per base unit
+	TotalCost     string                `json:"totalCost"`
+}
+
+// AssemblyOrderListResponse - Response DTO for assembly order list with pagination
+type AssemblyOrderListResponse struct {
+	Success    bool                    `json:"success"`
+	Data       []AssemblyOrderResponse `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
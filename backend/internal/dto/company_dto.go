@@ -23,6 +23,14 @@ type CompanyResponse struct {
 	// Purchase Invoice Settings (3-way matching)
 	InvoiceControlPolicy string  `json:"invoiceControlPolicy,omitempty"` // ORDERED or RECEIVED
 	InvoiceTolerancePct  float64 `json:"invoiceTolerancePct"`            // Tolerance % for over-invoicing
+	// Inventory Valuation Settings
+	CostingMethod        string  `json:"costingMethod,omitempty"`        // AVERAGE or FIFO
+	// Negative Stock Settings
+	NegativeStockPolicy  string  `json:"negativeStockPolicy,omitempty"`  // ALLOW, WARN or BLOCK
+	// Stock Transfer Settings
+	TransferLossReason   string  `json:"transferLossReason,omitempty"`   // Default reason for transfer discrepancies
+	// Batch Expiry Settings
+	ExpiryAlertDays      []int   `json:"expiryAlertDays"`                // Near-expiry horizons in days
 	IsActive             bool    `json:"isActive"`
 	Banks                []CompanyBankInfo  `json:"banks,omitempty"`
 }
@@ -64,6 +72,14 @@ type UpdateCompanyRequest struct {
 	// Purchase Invoice Settings (3-way matching like SAP/Odoo)
 	InvoiceControlPolicy *string  `json:"invoiceControlPolicy" binding:"omitempty,oneof=ORDERED RECEIVED" validate:"omitempty,oneof=ORDERED RECEIVED"`
 	InvoiceTolerancePct  *float64 `json:"invoiceTolerancePct" binding:"omitempty,min=0,max=100" validate:"omitempty,min=0,max=100"`
+	// Inventory Valuation Settings
+	CostingMethod        *string  `json:"costingMethod" binding:"omitempty,oneof=AVERAGE FIFO" validate:"omitempty,oneof=AVERAGE FIFO"`
+	// Negative Stock Settings
+	NegativeStockPolicy  *string  `json:"negativeStockPolicy" binding:"omitempty,oneof=ALLOW WARN BLOCK" validate:"omitempty,oneof=ALLOW WARN BLOCK"`
+	// Stock Transfer Settings
+	TransferLossReason   *string  `json:"transferLossReason" binding:"omitempty,oneof=SHRINKAGE DAMAGE EXPIRED THEFT OTHER" validate:"omitempty,oneof=SHRINKAGE DAMAGE EXPIRED THEFT OTHER"`
+	// Batch Expiry Settings
+	ExpiryAlertDays      []int    `json:"expiryAlertDays" binding:"omitempty,min=1,max=5,dive,min=1,max=730" validate:"omitempty,min=1,max=5,dive,min=1,max=730"`
 }
 
 // AddBankAccountRequest represents bank account addition request
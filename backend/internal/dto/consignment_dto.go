@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// CONSIGNMENT DTOs
+// Stock held at customer sites (CONSIGNMENT warehouses) and sell-through settlements
+// ============================================================================
+
+// CreateConsignmentSettlementRequest - Customer's report for a consignment warehouse
+// reportType SOLD: quantities sold since the last settlement
+// reportType ON_HAND: quantities the customer still holds; the rest of the listed stock was sold
+type CreateConsignmentSettlementRequest struct {
+	WarehouseID    string                                   `json:"warehouseId" binding:"required,uuid"`
+	SettlementDate string                                   `json:"settlementDate" binding:"required"` // YYYY-MM-DD
+	ReportType     string                                   `json:"reportType" binding:"required,oneof=SOLD ON_HAND"`
+	DueDate        *string                                  `json:"dueDate" binding:"omitempty"` // YYYY-MM-DD, default settlementDate + customer payment term
+	Notes          *string                                  `json:"notes" binding:"omitempty"`
+	Items          []CreateConsignmentSettlementItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateConsignmentSettlementItemRequest - Reported quantity of one product (and batch)
+type CreateConsignmentSettlementItemRequest struct {
+	ProductID string  `json:"productId" binding:"required,uuid"`
+	BatchID   *string `json:"batchId" binding:"omitempty,uuid"`
+	Quantity  string  `json:"quantity" binding:"required"`   // Base unit
+	UnitPrice *string `json:"unitPrice" binding:"omitempty"` // Default: customer price list, then product base price
+}
+
+// ConsignmentSettlementResponse - Response DTO for consignment settlement
+type ConsignmentSettlementResponse struct {
+	ID               string                              `json:"id"`
+	SettlementNumber string                              `json:"settlementNumber"`
+	SettlementDate   string                              `json:"settlementDate"`
+	CustomerID       string                              `json:"customerId"`
+	CustomerCode     string                              `json:"customerCode"`
+	CustomerName     string                              `json:"customerName"`
+	WarehouseID      string                              `json:"warehouseId"`
+	WarehouseCode    string                              `json:"warehouseCode"`
+	WarehouseName    string                              `json:"warehouseName"`
+	ReportType       string                              `json:"reportType"`
+	InvoiceID        *string                             `json:"invoiceId,omitempty"`
+	InvoiceNumber    *string                             `json:"invoiceNumber,omitempty"`
+	Subtotal         string                              `json:"subtotal"`
+	TaxAmount        string                              `json:"taxAmount"`
+	TotalAmount      string                              `json:"totalAmount"`
+	CostAmount       string                              `json:"costAmount"`
+	Notes            *string                             `json:"notes,omitempty"`
+	CreatedBy        *string                             `json:"createdBy,omitempty"`
+	CreatedAt        time.Time                           `json:"createdAt"`
+	UpdatedAt        time.Time                           `json:"updatedAt"`
+	Items            []ConsignmentSettlementItemResponse `json:"items,omitempty"`
+}
+
+// ConsignmentSettlementItemResponse - Response DTO for consignment settlement item
+type ConsignmentSettlementItemResponse struct {
+	ID          string                `json:"id"`
+	ProductID   string                `json:"productId"`
+	Product     *ProductBasicResponse `json:"product,omitempty"`
+	BatchID     *string               `json:"batchId,omitempty"`
+	BatchNumber *string               `json:"batchNumber,omitempty"`
+	ReportedQty string                `json:"reportedQty"`
+	StockQty    string                `json:"stockQty"` // Consignment stock before the settlement
+	SoldQty     string                `json:"soldQty"`
+	UnitPrice   string                `json:"unitPrice"`
+	Subtotal    string                `json:"subtotal"`
+	UnitCost    string                `json:"unitCost"`
+}
+
+// ConsignmentSettlementListResponse - Response DTO for consignment settlement list with pagination
+type ConsignmentSettlementListResponse struct {
+	Success    bool                            `json:"success"`
+	Data       []ConsignmentSettlementResponse `json:"data"`
+	Pagination PaginationInfo                  `json:"pagination"`
+}
+
+// ConsignmentSettlementQuery - Query parameters for listing consignment settlements
+type ConsignmentSettlementQuery struct {
+	Page        int     `form:"page" binding:"omitempty,min=1"`
+	PageSize    int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search      string  `form:"search" binding:"omitempty"`
+	CustomerID  *string `form:"customer_id" binding:"omitempty,uuid"`
+	WarehouseID *string `form:"warehouse_id" binding:"omitempty,uuid"`
+	DateFrom    *string `form:"date_from" binding:"omitempty"` // YYYY-MM-DD
+	DateTo      *string `form:"date_to" binding:"omitempty"`   // YYYY-MM-DD
+}
+
+// ConsignmentBalanceQuery - Query parameters for the consignment balance report
+type ConsignmentBalanceQuery struct {
+	CustomerID *string `form:"customer_id" binding:"omitempty,uuid"`
+}
+
+// ConsignmentBalanceLine - Consignment stock of one product in one warehouse
+type ConsignmentBalanceLine struct {
+	WarehouseID   string `json:"warehouseId"`
+	WarehouseCode string `json:"warehouseCode"`
+	WarehouseName string `json:"warehouseName"`
+	ProductID     string `json:"productId"`
+	ProductCode   string `json:"productCode"`
+	ProductName   string `json:"productName"`
+	BaseUnit      string `json:"baseUnit"`
+	Quantity      string `json:"quantity"`   // Still our inventory, not invoiced
+	UnitCost      string `json:"unitCost"`   // Average cost per base unit
+	StockValue    string `json:"stockValue"` // Quantity * UnitCost
+}
+
+// ConsignmentCustomerBalance - Consignment stock and settlements of one customer
+type ConsignmentCustomerBalance struct {
+	CustomerID           string                   `json:"customerId"`
+	CustomerCode         string                   `json:"customerCode"`
+	CustomerName         string                   `json:"customerName"`
+	TotalQuantity        string                   `json:"totalQuantity"`
+	TotalStockValue      string                   `json:"totalStockValue"`
+	SettlementCount      int                      `json:"settlementCount"`
+	SettledAmount        string                   `json:"settledAmount"` // Total of settlement invoices
+	LastSettlementNumber *string                  `json:"lastSettlementNumber,omitempty"`
+	LastSettlementDate   *string                  `json:"lastSettlementDate,omitempty"`
+	Lines                []ConsignmentBalanceLine `json:"lines"`
+}
+
+// ConsignmentBalanceResponse - Consignment balance report per customer
+type ConsignmentBalanceResponse struct {
+	Customers       []ConsignmentCustomerBalance `json:"customers"`
+	TotalStockValue string                       `json:"totalStockValue"`
+}
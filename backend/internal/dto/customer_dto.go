@@ -70,6 +70,7 @@ type CustomerResponse struct {
 	CreditLimit        string                   `json:"creditLimit"`
 	CurrentOutstanding string                   `json:"currentOutstanding"`
 	OverdueAmount      string                   `json:"overdueAmount"`
+	CreditBalance      string                   `json:"creditBalance"` // Unapplied sales credit notes
 	LastTransactionAt  *time.Time               `json:"lastTransactionAt,omitempty"`
 	Notes              *string                  `json:"notes,omitempty"`
 	IsActive           bool                     `json:"isActive"`
@@ -154,8 +155,9 @@ type CustomerCreditInfoResponse struct {
 	CustomerCode       string `json:"customerCode"`
 	CreditLimit        string `json:"creditLimit"`        // Credit limit (decimal as string)
 	OutstandingAmount  string `json:"outstandingAmount"`  // Total unpaid invoices (decimal)
-	AvailableCredit    string `json:"availableCredit"`    // Credit limit - outstanding (decimal)
+	AvailableCredit    string `json:"availableCredit"`    // Credit limit - outstanding + credit balance (decimal)
 	OverdueAmount      string `json:"overdueAmount"`      // Overdue invoices (decimal)
+	CreditBalance      string `json:"creditBalance"`      // Unapplied sales credit notes (decimal)
 	PaymentTermDays    int    `json:"paymentTermDays"`    // Payment terms in days
 	IsExceedingLimit   bool   `json:"isExceedingLimit"`   // True if outstanding > credit limit
 	UtilizationPercent string `json:"utilizationPercent"` // (Outstanding / Credit Limit) * 100
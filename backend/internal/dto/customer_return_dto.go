@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// CUSTOMER RETURN DTOs
+// Goods returned against a delivery, inspected into sellable, quarantine or scrap
+// ============================================================================
+
+// CreateCustomerReturnRequest - Request to record goods returned by a customer
+// InvoiceID defaults to the invoice raised for the delivery, when there is one
+type CreateCustomerReturnRequest struct {
+	DeliveryID string                            `json:"deliveryId" binding:"required,uuid"`
+	InvoiceID  *string                           `json:"invoiceId" binding:"omitempty,uuid"`
+	ReturnDate string                            `json:"returnDate" binding:"required"` // YYYY-MM-DD
+	Reason     string                            `json:"reason" binding:"required,min=3"`
+	Notes      *string                           `json:"notes" binding:"omitempty"`
+	Items      []CreateCustomerReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateCustomerReturnItemRequest - Returned quantity of one delivery line
+type CreateCustomerReturnItemRequest struct {
+	DeliveryItemID string   `json:"deliveryItemId" binding:"required,uuid"`
+	InvoiceItemID  *string  `json:"invoiceItemId" binding:"omitempty,uuid"` // Defaults to the invoice line of the delivery line
+	Quantity       string   `json:"quantity" binding:"required"`            // In the delivery line's unit
+	SerialNumbers  []string `json:"serialNumbers" binding:"omitempty"`      // Required for serial-tracked products
+	Notes          *string  `json:"notes" binding:"omitempty"`
+}
+
+// InspectCustomerReturnRequest - Inspection outcome for every line of a return
+type InspectCustomerReturnRequest struct {
+	Items []InspectCustomerReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// InspectCustomerReturnItemRequest - Disposition of one returned line
+type InspectCustomerReturnItemRequest struct {
+	ItemID         string  `json:"itemId" binding:"required,uuid"`
+	Disposition    string  `json:"disposition" binding:"required,oneof=SELLABLE QUARANTINE SCRAP"`
+	InspectionNote *string `json:"inspectionNote" binding:"omitempty"`
+}
+
+// CustomerReturnItemResponse - Response DTO for customer return line
+type CustomerReturnItemResponse struct {
+	ID                string                `json:"id"`
+	DeliveryItemID    string                `json:"deliveryItemId"`
+	InvoiceItemID     *string               `json:"invoiceItemId,omitempty"`
+	ProductID         string                `json:"productId"`
+	Product           *ProductBasicResponse `json:"product,omitempty"`
+	ProductUnitID     *string               `json:"productUnitId,omitempty"`
+	BatchID           *string               `json:"batchId,omitempty"`
+	BatchNumber       *string               `json:"batchNumber,omitempty"`
+	Quantity          string                `json:"quantity"`
+	BaseQuantity      string                `json:"baseQuantity"`
+	UnitPrice         string                `json:"unitPrice"`
+	Amount            string                `json:"amount"`
+	Disposition       *string               `json:"disposition,omitempty"`
+	InspectionNote    *string               `json:"inspectionNote,omitempty"`
+	ResultBatchID     *string               `json:"resultBatchId,omitempty"`
+	ResultBatchNumber *string               `json:"resultBatchNumber,omitempty"`
+	Notes             *string               `json:"notes,omitempty"`
+}
+
+// CustomerReturnResponse - Response DTO for customer return
+type CustomerReturnResponse struct {
+	ID               string                       `json:"id"`
+	ReturnNumber     string                       `json:"returnNumber"`
+	ReturnDate       string                       `json:"returnDate"`
+	CustomerID       string                       `json:"customerId"`
+	CustomerCode     string                       `json:"customerCode,omitempty"`
+	CustomerName     string                       `json:"customerName,omitempty"`
+	DeliveryID       string                       `json:"deliveryId"`
+	DeliveryNumber   string                       `json:"deliveryNumber,omitempty"`
+	InvoiceID        *string                      `json:"invoiceId,omitempty"`
+	InvoiceNumber    *string                      `json:"invoiceNumber,omitempty"`
+	WarehouseID      string                       `json:"warehouseId"`
+	Warehouse        *WarehouseBasicResponse      `json:"warehouse,omitempty"`
+	Status           string                       `json:"status"`
+	Reason           string                       `json:"reason"`
+	Notes            *string                      `json:"notes,omitempty"`
+	Items            []CustomerReturnItemResponse `json:"items"`
+	CreditNoteID     *string                      `json:"creditNoteId,omitempty"`
+	CreditNoteNumber *string                      `json:"creditNoteNumber,omitempty"`
+	InspectedBy      *string                      `json:"inspectedBy,omitempty"`
+	InspectedAt      *time.Time                   `json:"inspectedAt,omitempty"`
+	CompletedBy      *string                      `json:"completedBy,omitempty"`
+	CompletedAt      *time.Time                   `json:"completedAt,omitempty"`
+	CreatedBy        *string                      `json:"createdBy,omitempty"`
+	CreatedAt        time.Time                    `json:"createdAt"`
+	UpdatedAt        time.Time                    `json:"updatedAt"`
+}
+
+// CustomerReturnListResponse - Response DTO for customer return list with pagination
+type CustomerReturnListResponse struct {
+	Success    bool                     `json:"success"`
+	Data       []CustomerReturnResponse `json:"data"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
+
+// CustomerReturnQuery - Query parameters for listing customer returns
+type CustomerReturnQuery struct {
+	Page       int     `form:"page" binding:"omitempty,min=1"`
+	PageSize   int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search     string  `form:"search" binding:"omitempty"`
+	Status     *string `form:"status" binding:"omitempty,oneof=DRAFT INSPECTED COMPLETED CANCELLED"`
+	CustomerID *string `form:"customer_id" binding:"omitempty,uuid"`
+	DeliveryID *string `form:"delivery_id" binding:"omitempty,uuid"`
+}
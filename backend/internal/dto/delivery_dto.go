@@ -30,6 +30,10 @@ type CreateDeliveryItemRequest struct {
 	BatchId          *string `json:"batchId" binding:"omitempty"`
 	Quantity         string  `json:"quantity" binding:"required"` // decimal as string
 	Notes            *string `json:"notes" binding:"omitempty"`
+
+	// One serial per base unit for serial-tracked products: units in the warehouse for
+	// NORMAL/REPLACEMENT deliveries, units held by the customer for RETURN deliveries
+	SerialNumbers []string `json:"serialNumbers" binding:"omitempty,dive,required,max=100"`
 }
 
 // UpdateDeliveryStatusRequest represents delivery status update
@@ -119,6 +123,7 @@ type DeliveryItemResponse struct {
 	ProductUnitId    *string           `json:"productUnitId,omitempty"`
 	BatchId          *string           `json:"batchId,omitempty"`
 	Quantity         string            `json:"quantity"` // decimal as string
+	BaseQuantity     string            `json:"baseQuantity"` // Quantity in product base unit
 	Notes            *string           `json:"notes,omitempty"`
 	CreatedAt        time.Time         `json:"createdAt"`
 	UpdatedAt        time.Time         `json:"updatedAt"`
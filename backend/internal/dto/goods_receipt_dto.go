@@ -46,6 +46,7 @@ type UpdateGoodsReceiptItemRequest struct {
 	ExpiryDate      *string `json:"expiryDate" binding:"omitempty"`
 	RejectionReason *string `json:"rejectionReason" binding:"omitempty"`
 	QualityNote     *string `json:"qualityNote" binding:"omitempty"`
+	QualityHold     *bool   `json:"qualityHold" binding:"omitempty"` // Inspection: put the accepted quantity on quality hold (defaults to the product setting)
 	Notes           *string `json:"notes" binding:"omitempty"`
 }
 
@@ -70,8 +71,30 @@ type InspectGoodsRequest struct {
 }
 
 // AcceptGoodsRequest - Request to accept goods (stock will be updated)
+// Items without a chosen bin are put away to the suggested bin (see PutAwaySuggestionResponse)
 type AcceptGoodsRequest struct {
-	Notes *string `json:"notes" binding:"omitempty"`
+	Notes *string                   `json:"notes" binding:"omitempty"`
+	Items []AcceptGoodsItemRequest `json:"items" binding:"omitempty,dive"`
+}
+
+// AcceptGoodsItemRequest - Put-away bin and serials for an accepted goods receipt item
+// Serial-tracked products need one serial number per accepted base unit
+type AcceptGoodsItemRequest struct {
+	ItemID        string   `json:"itemId" binding:"required,uuid"`
+	BinID         string   `json:"binId" binding:"omitempty,uuid"`
+	SerialNumbers []string `json:"serialNumbers" binding:"omitempty,dive,required,max=100"`
+}
+
+// PutAwaySuggestionResponse - Suggested put-away bin for an item of a goods receipt
+type PutAwaySuggestionResponse struct {
+	ItemID      string  `json:"itemId"`
+	ProductID   string  `json:"productId"`
+	ProductCode string  `json:"productCode"`
+	ProductName string  `json:"productName"`
+	BatchNumber *string `json:"batchNumber,omitempty"`
+	Quantity    string  `json:"quantity"` // Accepted qty once inspected, otherwise received qty
+	BinID       *string `json:"binId,omitempty"`
+	BinCode     *string `json:"binCode,omitempty"`
 }
 
 // RejectGoodsRequest - Request to reject goods
@@ -127,6 +150,8 @@ type GoodsReceiptItemResponse struct {
 	OrderedQty          string                           `json:"orderedQty"`
 	ReceivedQty         string                           `json:"receivedQty"`
 	AcceptedQty         string                           `json:"acceptedQty"`
+	BaseReceivedQty     string                           `json:"baseReceivedQty"` // ReceivedQty in product base unit
+	BaseAcceptedQty     string                           `json:"baseAcceptedQty"` // AcceptedQty in product base unit
 	InvoicedQty         string                           `json:"invoicedQty"` // Qty already invoiced for this GRN item
 	RejectedQty              string                           `json:"rejectedQty"`
 	RejectionReason          *string                          `json:"rejectionReason,omitempty"`
@@ -138,6 +163,8 @@ type GoodsReceiptItemResponse struct {
 	DispositionNotes         *string                          `json:"dispositionNotes,omitempty"`
 	DispositionResolvedNotes *string                          `json:"dispositionResolvedNotes,omitempty"`
 	QualityNote              *string                          `json:"qualityNote,omitempty"`
+	QualityHold              bool                             `json:"qualityHold"` // Accepted quantity goes to quarantine
+	PutAwayBinID             *string                          `json:"putAwayBinId,omitempty"`
 	Notes               *string                          `json:"notes,omitempty"`
 	CreatedAt           time.Time                        `json:"createdAt"`
 	UpdatedAt           time.Time                        `json:"updatedAt"`
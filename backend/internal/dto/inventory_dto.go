@@ -0,0 +1,290 @@
+package dto
+
+// ============================================================================
+// INVENTORY VALUATION DTOs
+// ============================================================================
+
+// InventoryValuationQuery - Query parameters for the inventory valuation report
+type InventoryValuationQuery struct {
+	AsOf        string  `form:"asOf" binding:"omitempty"` // YYYY-MM-DD, defaults to today
+	WarehouseID *string `form:"warehouseID" binding:"omitempty,uuid"`
+}
+
+// ProductValuation - Quantity and value of one product in a warehouse
+type ProductValuation struct {
+	ProductID   string `json:"productId"`
+	ProductCode string `json:"productCode"`
+	ProductName string `json:"productName"`
+	BaseUnit    string `json:"baseUnit"`
+	Quantity    string `json:"quantity"`
+	UnitCost    string `json:"unitCost"` // Average cost per base unit
+	Value       string `json:"value"`    // Quantity x unit cost
+}
+
+// WarehouseValuation - Valuation of all products in a warehouse
+type WarehouseValuation struct {
+	WarehouseID   string             `json:"warehouseId"`
+	WarehouseCode string             `json:"warehouseCode"`
+	WarehouseName string             `json:"warehouseName"`
+	TotalValue    string             `json:"totalValue"`
+	Items         []ProductValuation `json:"items"`
+}
+
+// InventoryValuationResponse - Inventory valuation report as of a date
+type InventoryValuationResponse struct {
+	AsOf       string               `json:"asOf"`
+	TotalValue string               `json:"totalValue"`
+	Warehouses []WarehouseValuation `json:"warehouses"`
+}
+
+// ============================================================================
+// FIFO COST LAYER DTOs
+// ============================================================================
+
+// CostLayerListQuery - Query parameters for inspecting FIFO cost layers
+type CostLayerListQuery struct {
+	Page            int     `form:"page" binding:"omitempty,min=1"`
+	PageSize        int     `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	WarehouseID     *string `form:"warehouseID" binding:"omitempty,uuid"`
+	ProductID       *string `form:"productID" binding:"omitempty,uuid"`
+	IncludeConsumed bool    `form:"includeConsumed"` // Include fully consumed layers
+}
+
+// CostLayerResponse - A single FIFO cost layer
+type CostLayerResponse struct {
+	ID                string  `json:"id"`
+	WarehouseID       string  `json:"warehouseId"`
+	WarehouseCode     string  `json:"warehouseCode"`
+	WarehouseName     string  `json:"warehouseName"`
+	ProductID         string  `json:"productId"`
+	ProductCode       string  `json:"productCode"`
+	ProductName       string  `json:"productName"`
+	MovementID        string  `json:"movementId"`
+	LayerDate         string  `json:"layerDate"`
+	OriginalQuantity  string  `json:"originalQuantity"`
+	RemainingQuantity string  `json:"remainingQuantity"`
+	UnitCost          string  `json:"unitCost"`
+	RemainingValue    string  `json:"remainingValue"` // Remaining quantity x unit cost
+	ReferenceType     *string `json:"referenceType,omitempty"`
+	ReferenceNumber   *string `json:"referenceNumber,omitempty"`
+}
+
+// CostLayerListResponse - Paginated FIFO cost layers with remaining totals
+type CostLayerListResponse struct {
+	Success           bool                `json:"success"`
+	Data              []CostLayerResponse `json:"data"`
+	RemainingQuantity string              `json:"remainingQuantity"` // Across all matching layers
+	RemainingValue    string              `json:"remainingValue"`
+	Pagination        PaginationInfo      `json:"pagination"`
+}
+
+// ============================================================================
+// BATCH EXPIRY DTOs
+// ============================================================================
+
+// NearExpiryQuery - Query parameters for the near-expiry report
+type NearExpiryQuery struct {
+	WarehouseID *string `form:"warehouseID" binding:"omitempty,uuid"`
+	ProductID   *string `form:"productID" binding:"omitempty,uuid"`
+}
+
+// NearExpiryBatch - A batch with stock that is expired or expires within a horizon
+type NearExpiryBatch struct {
+	BatchID       string `json:"batchId"`
+	BatchNumber   string `json:"batchNumber"`
+	ProductID     string `json:"productId"`
+	ProductCode   string `json:"productCode"`
+	ProductName   string `json:"productName"`
+	WarehouseID   string `json:"warehouseId"`
+	WarehouseCode string `json:"warehouseCode"`
+	WarehouseName string `json:"warehouseName"`
+	ExpiryDate    string `json:"expiryDate"`   // YYYY-MM-DD
+	DaysToExpiry  int    `json:"daysToExpiry"` // Negative when already expired
+	Status        string `json:"status"`
+	Quantity      string `json:"quantity"`
+	UnitCost      string `json:"unitCost"`    // Warehouse average cost
+	ValueAtRisk   string `json:"valueAtRisk"` // Quantity x unit cost
+}
+
+// NearExpiryBucket - Batches grouped by expiry horizon
+type NearExpiryBucket struct {
+	Label         string            `json:"label"`       // e.g. "EXPIRED", "1-30", "31-60"
+	HorizonDays   int               `json:"horizonDays"` // Upper bound in days (0 for expired)
+	TotalQuantity string            `json:"totalQuantity"`
+	TotalValue    string            `json:"totalValue"`
+	Batches       []NearExpiryBatch `json:"batches"`
+}
+
+// NearExpiryResponse - Near-expiry report using the company's horizons
+type NearExpiryResponse struct {
+	AsOf       string             `json:"asOf"`
+	Horizons   []int              `json:"horizons"`
+	TotalValue string             `json:"totalValue"`
+	Buckets    []NearExpiryBucket `json:"buckets"`
+}
+
+// ============================================================================
+// STOCK CARD (KARTU STOK) DTOs
+// ============================================================================
+
+// StockCardQuery - Query parameters for the stock card of one product in one warehouse
+type StockCardQuery struct {
+	WarehouseID string  `form:"warehouseID" binding:"required,uuid"`
+	ProductID   string  `form:"productID" binding:"required,uuid"`
+	DateFrom    string  `form:"dateFrom" binding:"omitempty"` // YYYY-MM-DD, defaults to the first day of this month
+	DateTo      string  `form:"dateTo" binding:"omitempty"`   // YYYY-MM-DD, defaults to today
+	BatchID     *string `form:"batchID" binding:"omitempty,uuid"`
+	ByBatch     bool    `form:"byBatch"` // Running balance and summary per batch
+	Page        int     `form:"page" binding:"omitempty,min=1"`
+	PageSize    int     `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	Format      string  `form:"format" binding:"omitempty,oneof=csv pdf"` // Export only, defaults to csv
+}
+
+// StockCardEntry - One movement line on the stock card
+type StockCardEntry struct {
+	MovementID      string  `json:"movementId"`
+	MovementDate    string  `json:"movementDate"`
+	MovementType    string  `json:"movementType"`
+	ReferenceType   *string `json:"referenceType,omitempty"`
+	ReferenceID     *string `json:"referenceId,omitempty"`
+	ReferenceNumber *string `json:"referenceNumber,omitempty"`
+	BatchID         *string `json:"batchId,omitempty"`
+	BatchNumber     string  `json:"batchNumber,omitempty"`
+	BinCode         string  `json:"binCode,omitempty"`
+	QuantityIn      string  `json:"quantityIn"`
+	QuantityOut     string  `json:"quantityOut"`
+	Balance         string  `json:"balance"`                // Running balance after this movement
+	BatchBalance    string  `json:"batchBalance,omitempty"` // Running balance of the batch (byBatch only)
+	UnitCost        string  `json:"unitCost"`
+	Notes           *string `json:"notes,omitempty"`
+	PostedBy        *string `json:"postedBy,omitempty"`
+	PostedByName    string  `json:"postedByName,omitempty"`
+}
+
+// StockCardBatchSummary - Opening, in, out and closing of one batch over the period
+type StockCardBatchSummary struct {
+	BatchID        *string `json:"batchId,omitempty"` // nil = movements without a batch
+	BatchNumber    string  `json:"batchNumber"`
+	ExpiryDate     string  `json:"expiryDate,omitempty"`
+	OpeningBalance string  `json:"openingBalance"`
+	TotalIn        string  `json:"totalIn"`
+	TotalOut       string  `json:"totalOut"`
+	ClosingBalance string  `json:"closingBalance"`
+}
+
+// StockCardResponse - Stock card with opening balance, paginated movements and period totals
+type StockCardResponse struct {
+	Success        bool                    `json:"success"`
+	WarehouseID    string                  `json:"warehouseId"`
+	WarehouseCode  string                  `json:"warehouseCode"`
+	WarehouseName  string                  `json:"warehouseName"`
+	ProductID      string                  `json:"productId"`
+	ProductCode    string                  `json:"productCode"`
+	ProductName    string                  `json:"productName"`
+	BaseUnit       string                  `json:"baseUnit"`
+	DateFrom       string                  `json:"dateFrom"`
+	DateTo         string                  `json:"dateTo"`
+	OpeningBalance string                  `json:"openingBalance"`
+	TotalIn        string                  `json:"totalIn"`  // Across the whole period, not only the current page
+	TotalOut       string                  `json:"totalOut"` // Across the whole period, not only the current page
+	ClosingBalance string                  `json:"closingBalance"`
+	Batches        []StockCardBatchSummary `json:"batches,omitempty"` // byBatch only
+	Data           []StockCardEntry        `json:"data"`
+	Pagination     PaginationInfo          `json:"pagination"`
+}
+
+// ============================================================================
+// POINT-IN-TIME STOCK (AS-OF) DTOs
+// ============================================================================
+
+// StockAsOfQuery - Query parameters for stock held at a past moment
+type StockAsOfQuery struct {
+	AsOf        string  `form:"asOf" binding:"required"` // YYYY-MM-DD (end of that day) or RFC3339 timestamp (movements before it)
+	WarehouseID *string `form:"warehouseID" binding:"omitempty,uuid"`
+	ProductID   *string `form:"productID" binding:"omitempty,uuid"`
+}
+
+// StockAsOfItem - Quantity and value of one product (and batch) in one warehouse
+type StockAsOfItem struct {
+	WarehouseID   string  `json:"warehouseId"`
+	WarehouseCode string  `json:"warehouseCode"`
+	WarehouseName string  `json:"warehouseName"`
+	ProductID     string  `json:"productId"`
+	ProductCode   string  `json:"productCode"`
+	ProductName   string  `json:"productName"`
+	BaseUnit      string  `json:"baseUnit"`
+	BatchID       *string `json:"batchId,omitempty"`
+	BatchNumber   string  `json:"batchNumber,omitempty"`
+	Quantity      string  `json:"quantity"`
+	Value         string  `json:"value"` // Sum of movement total cost
+}
+
+// StockAsOfResponse - Stock rebuilt from the latest snapshot before the cutoff plus later movements
+type StockAsOfResponse struct {
+	AsOf       string          `json:"asOf"`                 // Exclusive cutoff, RFC3339
+	SnapshotID *string         `json:"snapshotId,omitempty"` // Snapshot the replay started from, nil = full history
+	SnapshotAt *string         `json:"snapshotAt,omitempty"`
+	TotalValue string          `json:"totalValue"`
+	Items      []StockAsOfItem `json:"items"`
+}
+
+// CreateStockSnapshotRequest - Request body for creating a month-end stock snapshot manually
+type CreateStockSnapshotRequest struct {
+	Period string `json:"period" binding:"required"` // YYYY-MM, must be a completed month
+}
+
+// StockSnapshotResponse - Stored month-end stock snapshot
+type StockSnapshotResponse struct {
+	ID         string  `json:"id"`
+	Period     string  `json:"period"`
+	SnapshotAt string  `json:"snapshotAt"`
+	ItemCount  int     `json:"itemCount"`
+	TotalValue string  `json:"totalValue"`
+	CreatedBy  *string `json:"createdBy,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+// ============================================================================
+// STOCK RECONCILIATION DTOs
+// ============================================================================
+
+// StockReconciliationQuery - Query parameters for the stock reconciliation report
+type StockReconciliationQuery struct {
+	WarehouseID *string `form:"warehouseID" binding:"omitempty,uuid"`
+	ProductID   *string `form:"productID" binding:"omitempty,uuid"`
+}
+
+// RepairStockReconciliationRequest - Request body for repairing stock ledger drift
+type RepairStockReconciliationRequest struct {
+	WarehouseID *string `json:"warehouseId" binding:"omitempty,uuid"`
+	ProductID   *string `json:"productId" binding:"omitempty,uuid"`
+	DryRun      bool    `json:"dryRun"` // Report the corrections without posting them
+	Notes       *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+// StockDiscrepancy - One figure that disagrees with the figure it is checked against
+type StockDiscrepancy struct {
+	Type          string  `json:"type"` // LEDGER, BATCH, PRODUCT_CURRENT_STOCK
+	WarehouseID   *string `json:"warehouseId,omitempty"`
+	WarehouseCode string  `json:"warehouseCode,omitempty"`
+	ProductID     string  `json:"productId"`
+	ProductCode   string  `json:"productCode"`
+	ProductName   string  `json:"productName"`
+	Expected      string  `json:"expected"`   // Figure being checked against (e.g. warehouse stock)
+	Actual        string  `json:"actual"`     // Figure that drifted (e.g. sum of movements)
+	Difference    string  `json:"difference"` // Expected - Actual
+	LikelyCause   string  `json:"likelyCause"`
+	Repairable    bool    `json:"repairable"`
+	Repaired      bool    `json:"repaired"`
+	MovementID    *string `json:"movementId,omitempty"` // Correcting ADJUSTMENT movement
+}
+
+// StockReconciliationResponse - Result of checking (and optionally repairing) a company's stock figures
+type StockReconciliationResponse struct {
+	CheckedAt       string             `json:"checkedAt"`
+	DryRun          bool               `json:"dryRun"`
+	ReferenceNumber *string            `json:"referenceNumber,omitempty"` // Set when corrections were posted
+	StockRows       int                `json:"stockRows"`
+	Discrepancies   []StockDiscrepancy `json:"discrepancies"`
+	RepairedCount   int                `json:"repairedCount"`
+}
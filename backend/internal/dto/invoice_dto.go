@@ -94,7 +94,9 @@ type InvoiceResponse struct {
 	TaxAmount       string                  `json:"taxAmount"`       // decimal as string
 	TotalAmount     string                  `json:"totalAmount"`     // decimal as string
 	PaidAmount      string                  `json:"paidAmount"`      // decimal as string
-	RemainingAmount string                  `json:"remainingAmount"` // calculated: totalAmount - paidAmount
+	CreditedAmount  string                  `json:"creditedAmount"`  // decimal as string, settled by credit notes
+	DebitedAmount   string                  `json:"debitedAmount"`   // decimal as string, added by debit notes
+	RemainingAmount string                  `json:"remainingAmount"` // calculated: totalAmount + debitedAmount - paidAmount - creditedAmount
 	PaymentStatus   string                  `json:"paymentStatus"`
 	Notes           *string                 `json:"notes,omitempty"`
 	FakturPajakNo   *string                 `json:"fakturPajakNo,omitempty"`
@@ -116,6 +118,7 @@ type InvoiceItemResponse struct {
 	ProductUnitID    *string `json:"productUnitId,omitempty"`
 	UnitName         string  `json:"unitName"`
 	Quantity         string  `json:"quantity"`    // decimal as string
+	BaseQuantity     string  `json:"baseQuantity"` // Quantity in product base unit
 	UnitPrice        string  `json:"unitPrice"`   // decimal as string
 	DiscountPct      string  `json:"discountPct"` // decimal as string
 	DiscountAmt      string  `json:"discountAmt"` // decimal as string
@@ -9,37 +9,41 @@ import "time"
 // CreateProductRequest represents product creation request
 // Reference: 02-MASTER-DATA-MANAGEMENT.md lines 260-282
 type CreateProductRequest struct {
-	Code           string                     `json:"code" binding:"required,min=1,max=100"`
-	Name           string                     `json:"name" binding:"required,min=2,max=255"`
-	Category       *string                    `json:"category" binding:"omitempty,max=100"`
-	BaseUnit       string                     `json:"baseUnit" binding:"required,max=20"`
-	BaseCost       string                     `json:"baseCost" binding:"required"` // decimal as string
-	BasePrice      string                     `json:"basePrice" binding:"required"` // decimal as string
-	MinimumStock   string                     `json:"minimumStock" binding:"omitempty"` // decimal as string, default 0
-	Description    *string                    `json:"description" binding:"omitempty"`
-	Barcode        *string                    `json:"barcode" binding:"omitempty,max=100"`
-	IsBatchTracked bool                       `json:"isBatchTracked"`
-	IsPerishable   bool                       `json:"isPerishable"`
-	Units          []CreateProductUnitRequest `json:"units" binding:"omitempty,dive"`
+	Code            string                     `json:"code" binding:"required,min=1,max=100"`
+	Name            string                     `json:"name" binding:"required,min=2,max=255"`
+	Category        *string                    `json:"category" binding:"omitempty,max=100"`
+	BaseUnit        string                     `json:"baseUnit" binding:"required,max=20"`
+	BaseCost        string                     `json:"baseCost" binding:"required"`      // decimal as string
+	BasePrice       string                     `json:"basePrice" binding:"required"`     // decimal as string
+	MinimumStock    string                     `json:"minimumStock" binding:"omitempty"` // decimal as string, default 0
+	Description     *string                    `json:"description" binding:"omitempty"`
+	Barcode         *string                    `json:"barcode" binding:"omitempty,max=100"`
+	IsBatchTracked  bool                       `json:"isBatchTracked"`
+	IsSerialTracked bool                       `json:"isSerialTracked"`
+	HoldOnReceipt   bool                       `json:"holdOnReceipt"` // Quarantine received batches until released
+	IsPerishable    bool                       `json:"isPerishable"`
+	Units           []CreateProductUnitRequest `json:"units" binding:"omitempty,dive"`
 }
 
 // UpdateProductRequest represents product update request
 // Reference: 02-MASTER-DATA-MANAGEMENT.md lines 310-325
 type UpdateProductRequest struct {
-	Code           *string                         `json:"code" binding:"omitempty,min=1,max=100"` // Added: Allow code updates with validation
-	Name           *string                         `json:"name" binding:"omitempty,min=2,max=255"`
-	Category       *string                         `json:"category" binding:"omitempty,max=100"`
-	BaseUnit       *string                         `json:"baseUnit" binding:"omitempty,max=20"` // Added: Allow baseUnit updates
-	BaseCost       *string                         `json:"baseCost" binding:"omitempty"` // decimal as string
-	BasePrice      *string                         `json:"basePrice" binding:"omitempty"` // decimal as string
-	MinimumStock   *string                         `json:"minimumStock" binding:"omitempty"` // decimal as string
-	Description    *string                         `json:"description" binding:"omitempty"`
-	Barcode        *string                         `json:"barcode" binding:"omitempty,max=100"`
-	IsBatchTracked *bool                           `json:"isBatchTracked" binding:"omitempty"`
-	IsPerishable   *bool                           `json:"isPerishable" binding:"omitempty"`
-	IsActive       *bool                           `json:"isActive" binding:"omitempty"`
-	Suppliers      *UpdateProductSuppliersRequest  `json:"suppliers" binding:"omitempty"` // Optional: supplier changes
-	Units          *UpdateProductUnitsRequest      `json:"units" binding:"omitempty"` // Optional: unit changes
+	Code            *string                        `json:"code" binding:"omitempty,min=1,max=100"` // Added: Allow code updates with validation
+	Name            *string                        `json:"name" binding:"omitempty,min=2,max=255"`
+	Category        *string                        `json:"category" binding:"omitempty,max=100"`
+	BaseUnit        *string                        `json:"baseUnit" binding:"omitempty,max=20"` // Added: Allow baseUnit updates
+	BaseCost        *string                        `json:"baseCost" binding:"omitempty"`        // decimal as string
+	BasePrice       *string                        `json:"basePrice" binding:"omitempty"`       // decimal as string
+	MinimumStock    *string                        `json:"minimumStock" binding:"omitempty"`    // decimal as string
+	Description     *string                        `json:"description" binding:"omitempty"`
+	Barcode         *string                        `json:"barcode" binding:"omitempty,max=100"`
+	IsBatchTracked  *bool                          `json:"isBatchTracked" binding:"omitempty"`
+	IsSerialTracked *bool                          `json:"isSerialTracked" binding:"omitempty"`
+	HoldOnReceipt   *bool                          `json:"holdOnReceipt" binding:"omitempty"`
+	IsPerishable    *bool                          `json:"isPerishable" binding:"omitempty"`
+	IsActive        *bool                          `json:"isActive" binding:"omitempty"`
+	Suppliers       *UpdateProductSuppliersRequest `json:"suppliers" binding:"omitempty"` // Optional: supplier changes
+	Units           *UpdateProductUnitsRequest     `json:"units" binding:"omitempty"`     // Optional: unit changes
 }
 
 // UpdateProductSuppliersRequest represents supplier changes in product update
@@ -75,16 +79,17 @@ type UpdateProductUnitItem struct {
 
 // ProductFilters represents product list filters
 type ProductFilters struct {
-	Search         string  `form:"search"`
-	Category       string  `form:"category"`
-	SupplierID     string  `form:"supplier_id"` // Filter products by supplier
-	IsActive       *bool   `form:"is_active"`
-	IsBatchTracked *bool   `form:"is_batch_tracked"`
-	IsPerishable   *bool   `form:"is_perishable"`
-	Page           int     `form:"page" binding:"omitempty,min=1"`
-	Limit          int     `form:"page_size" binding:"omitempty,min=1,max=100"`
-	SortBy         string  `form:"sort_by" binding:"omitempty,oneof=code name createdAt"`
-	SortOrder      string  `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Search          string `form:"search"`
+	Category        string `form:"category"`
+	SupplierID      string `form:"supplier_id"` // Filter products by supplier
+	IsActive        *bool  `form:"is_active"`
+	IsBatchTracked  *bool  `form:"is_batch_tracked"`
+	IsSerialTracked *bool  `form:"is_serial_tracked"`
+	IsPerishable    *bool  `form:"is_perishable"`
+	Page            int    `form:"page" binding:"omitempty,min=1"`
+	Limit           int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	SortBy          string `form:"sort_by" binding:"omitempty,oneof=code name createdAt"`
+	SortOrder       string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
 }
 
 // ============================================================================
@@ -144,24 +149,26 @@ type UpdateProductSupplierRequest struct {
 // ProductResponse represents product information response
 // Reference: 02-MASTER-DATA-MANAGEMENT.md lines 133-178
 type ProductResponse struct {
-	ID             string                    `json:"id"`
-	Code           string                    `json:"code"`
-	Name           string                    `json:"name"`
-	Category       *string                   `json:"category,omitempty"`
-	BaseUnit       string                    `json:"baseUnit"`
-	BaseCost       string                    `json:"baseCost"` // decimal as string
-	BasePrice      string                    `json:"basePrice"` // decimal as string
-	MinimumStock   string                    `json:"minimumStock"` // decimal as string
-	Description    *string                   `json:"description,omitempty"`
-	Barcode        *string                   `json:"barcode,omitempty"`
-	IsBatchTracked bool                      `json:"isBatchTracked"`
-	IsPerishable   bool                      `json:"isPerishable"`
-	IsActive       bool                      `json:"isActive"`
-	Units          []ProductUnitResponse     `json:"units,omitempty"`
-	Suppliers      []ProductSupplierResponse `json:"suppliers,omitempty"`
-	CurrentStock   *CurrentStockResponse     `json:"currentStock,omitempty"`
-	CreatedAt      time.Time                 `json:"createdAt"`
-	UpdatedAt      time.Time                 `json:"updatedAt"`
+	ID              string                    `json:"id"`
+	Code            string                    `json:"code"`
+	Name            string                    `json:"name"`
+	Category        *string                   `json:"category,omitempty"`
+	BaseUnit        string                    `json:"baseUnit"`
+	BaseCost        string                    `json:"baseCost"`     // decimal as string
+	BasePrice       string                    `json:"basePrice"`    // decimal as string
+	MinimumStock    string                    `json:"minimumStock"` // decimal as string
+	Description     *string                   `json:"description,omitempty"`
+	Barcode         *string                   `json:"barcode,omitempty"`
+	IsBatchTracked  bool                      `json:"isBatchTracked"`
+	IsSerialTracked bool                      `json:"isSerialTracked"`
+	HoldOnReceipt   bool                      `json:"holdOnReceipt"`
+	IsPerishable    bool                      `json:"isPerishable"`
+	IsActive        bool                      `json:"isActive"`
+	Units           []ProductUnitResponse     `json:"units,omitempty"`
+	Suppliers       []ProductSupplierResponse `json:"suppliers,omitempty"`
+	CurrentStock    *CurrentStockResponse     `json:"currentStock,omitempty"`
+	CreatedAt       time.Time                 `json:"createdAt"`
+	UpdatedAt       time.Time                 `json:"updatedAt"`
 }
 
 // ProductUnitResponse represents product unit information
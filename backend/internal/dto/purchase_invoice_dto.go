@@ -76,7 +76,8 @@ type UpdatePurchaseInvoiceRequest struct {
 
 // ApprovePurchaseInvoiceRequest represents approval request
 type ApprovePurchaseInvoiceRequest struct {
-	Notes *string `json:"notes" binding:"omitempty"`
+	Notes            *string `json:"notes" binding:"omitempty"`
+	LandedCostMethod *string `json:"landedCostMethod" binding:"omitempty,oneof=VALUE QUANTITY WEIGHT"` // Defaults to VALUE
 }
 
 // RejectPurchaseInvoiceRequest represents rejection request
@@ -202,6 +203,25 @@ type PurchaseInvoicePaymentResponse struct {
 	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
+// LandedCostAllocationResponse represents landed cost allocated to one goods receipt line
+type LandedCostAllocationResponse struct {
+	ID                 string    `json:"id"`
+	GoodsReceiptID     string    `json:"goodsReceiptId"`
+	GoodsReceiptItemID string    `json:"goodsReceiptItemId"`
+	WarehouseID        string    `json:"warehouseId"`
+	ProductID          string    `json:"productId"`
+	ProductCode        string    `json:"productCode"`
+	ProductName        string    `json:"productName"`
+	AllocationMethod   string    `json:"allocationMethod"`
+	Quantity           string    `json:"quantity"`          // decimal as string
+	BasisValue         string    `json:"basisValue"`        // decimal as string
+	AllocatedAmount    string    `json:"allocatedAmount"`   // decimal as string
+	CapitalizedAmount  string    `json:"capitalizedAmount"` // Added to on-hand inventory cost
+	ExpensedAmount     string    `json:"expensedAmount"`    // For quantity already consumed
+	MovementID         *string   `json:"movementId,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
 // PurchaseInvoiceListResponse represents paginated list of purchase invoices
 type PurchaseInvoiceListResponse struct {
 	Data       []PurchaseInvoiceResponse `json:"data"`
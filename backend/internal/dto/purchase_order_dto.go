@@ -91,6 +91,7 @@ type PurchaseOrderItemResponse struct {
 	ProductUnitID *string                           `json:"productUnitId,omitempty"`
 	ProductUnit   *PurchaseOrderProductUnitResponse `json:"productUnit,omitempty"`
 	Quantity      string                            `json:"quantity"`
+	BaseQuantity  string                            `json:"baseQuantity"` // Quantity in product base unit
 	UnitPrice     string                            `json:"unitPrice"`
 	DiscountPct   string                            `json:"discountPct"`
 	DiscountAmt   string                            `json:"discountAmt"`
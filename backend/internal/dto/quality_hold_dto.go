@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// QUALITY HOLD DTOs
+// Quarantine of batches pending a quality decision (release or scrap)
+// ============================================================================
+
+// CreateQualityHoldRequest - Request to put an available batch on quality hold
+type CreateQualityHoldRequest struct {
+	BatchID string  `json:"batchId" binding:"required,uuid"`
+	Reason  string  `json:"reason" binding:"required,min=3"`
+	Notes   *string `json:"notes" binding:"omitempty"`
+}
+
+// ReleaseQualityHoldRequest - Request to release held stock back to available stock
+type ReleaseQualityHoldRequest struct {
+	Reason string  `json:"reason" binding:"required,min=3"`
+	Notes  *string `json:"notes" binding:"omitempty"`
+}
+
+// ScrapQualityHoldRequest - Request to write off held stock
+// Quantity is in base units; leave it empty to scrap everything still on hold
+type ScrapQualityHoldRequest struct {
+	Quantity *string `json:"quantity" binding:"omitempty"`
+	Reason   string  `json:"reason" binding:"required,min=3"`
+	Notes    *string `json:"notes" binding:"omitempty"`
+}
+
+// QualityHoldActionResponse - A release or scrap decision with its approver
+type QualityHoldActionResponse struct {
+	ID         string             `json:"id"`
+	Action     string             `json:"action"`
+	Quantity   string             `json:"quantity"`
+	Reason     string             `json:"reason"`
+	Notes      *string            `json:"notes,omitempty"`
+	MovementID *string            `json:"movementId,omitempty"`
+	ApprovedBy string             `json:"approvedBy"`
+	Approver   *UserBasicResponse `json:"approver,omitempty"`
+	ApprovedAt time.Time          `json:"approvedAt"`
+}
+
+// QualityHoldResponse - Response DTO for quality hold
+type QualityHoldResponse struct {
+	ID                string                      `json:"id"`
+	HoldNumber        string                      `json:"holdNumber"`
+	HoldDate          string                      `json:"holdDate"`
+	WarehouseID       string                      `json:"warehouseId"`
+	Warehouse         *WarehouseBasicResponse     `json:"warehouse,omitempty"`
+	ProductID         string                      `json:"productId"`
+	Product           *ProductBasicResponse       `json:"product,omitempty"`
+	BatchID           string                      `json:"batchId"`
+	BatchNumber       string                      `json:"batchNumber,omitempty"`
+	ExpiryDate        *time.Time                  `json:"expiryDate,omitempty"`
+	Source            string                      `json:"source"`
+	GoodsReceiptID    *string                     `json:"goodsReceiptId,omitempty"`
+	CustomerReturnID  *string                     `json:"customerReturnId,omitempty"`
+	Status            string                      `json:"status"`
+	HeldQuantity      string                      `json:"heldQuantity"`
+	ReleasedQuantity  string                      `json:"releasedQuantity"`
+	ScrappedQuantity  string                      `json:"scrappedQuantity"`
+	RemainingQuantity string                      `json:"remainingQuantity"`
+	Reason            string                      `json:"reason"`
+	Notes             *string                     `json:"notes,omitempty"`
+	Actions           []QualityHoldActionResponse `json:"actions"`
+	ClosedAt          *time.Time                  `json:"closedAt,omitempty"`
+	CreatedBy         *string                     `json:"createdBy,omitempty"`
+	CreatedAt         time.Time                   `json:"createdAt"`
+	UpdatedAt         time.Time                   `json:"updatedAt"`
+}
+
+// QualityHoldListResponse - Response DTO for quality hold list with pagination
+type QualityHoldListResponse struct {
+	Success    bool                  `json:"success"`
+	Data       []QualityHoldResponse `json:"data"`
+	Pagination PaginationInfo        `json:"pagination"`
+}
+
+// QualityHoldQuery - Query parameters for listing quality holds
+type QualityHoldQuery struct {
+	Page        int     `form:"page" binding:"omitempty,min=1"`
+	PageSize    int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search      string  `form:"search" binding:"omitempty"`
+	Status      *string `form:"status" binding:"omitempty,oneof=OPEN RELEASED SCRAPPED"`
+	Source      *string `form:"source" binding:"omitempty,oneof=GOODS_RECEIPT CUSTOMER_RETURN MANUAL"`
+	WarehouseID *string `form:"warehouse_id" binding:"omitempty,uuid"`
+	ProductID   *string `form:"product_id" binding:"omitempty,uuid"`
+}
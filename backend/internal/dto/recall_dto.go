@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// PRODUCT RECALL DTOs
+// Batch hold, forward/backward traceability and customer returns
+// ============================================================================
+
+// CreateProductRecallRequest - Request to recall a lot
+// Identify the lot by batchId, by productId + batchNumber, or by supplierReference
+// (the supplier's lot number, optionally narrowed by supplierId/productId)
+type CreateProductRecallRequest struct {
+	BatchID           *string `json:"batchId" binding:"omitempty,uuid"`
+	ProductID         *string `json:"productId" binding:"omitempty,uuid"`
+	BatchNumber       *string `json:"batchNumber" binding:"omitempty,max=100"`
+	SupplierID        *string `json:"supplierId" binding:"omitempty,uuid"`
+	SupplierReference *string `json:"supplierReference" binding:"omitempty,max=100"`
+	Reason            string  `json:"reason" binding:"required,min=3"`
+	Notes             *string `json:"notes" binding:"omitempty"`
+}
+
+// CloseProductRecallRequest - Request to close a recall (OPEN → CLOSED)
+type CloseProductRecallRequest struct {
+	Notes *string `json:"notes" binding:"omitempty"`
+}
+
+// ProductRecallResponse - Response DTO for product recall
+type ProductRecallResponse struct {
+	ID                  string                `json:"id"`
+	RecallNumber        string                `json:"recallNumber"`
+	RecallDate          string                `json:"recallDate"`
+	ProductID           *string               `json:"productId,omitempty"`
+	Product             *ProductBasicResponse `json:"product,omitempty"`
+	BatchNumber         *string               `json:"batchNumber,omitempty"`
+	SupplierID          *string               `json:"supplierId,omitempty"`
+	SupplierReference   *string               `json:"supplierReference,omitempty"`
+	Reason              string                `json:"reason"`
+	Status              string                `json:"status"`
+	HeldQuantity        string                `json:"heldQuantity"`
+	DeliveredQuantity   string                `json:"deliveredQuantity"`
+	BatchCount          int                   `json:"batchCount"`
+	CustomerCount       int                   `json:"customerCount"`
+	ReturnDeliveryCount int                   `json:"returnDeliveryCount"`
+	ClosedBy            *string               `json:"closedBy,omitempty"`
+	ClosedAt            *time.Time            `json:"closedAt,omitempty"`
+	Notes               *string               `json:"notes,omitempty"`
+	CreatedBy           *string               `json:"createdBy,omitempty"`
+	CreatedAt           time.Time             `json:"createdAt"`
+	UpdatedAt           time.Time             `json:"updatedAt"`
+}
+
+// ProductRecallListResponse - Response DTO for product recall list with pagination
+type ProductRecallListResponse struct {
+	Success    bool                    `json:"success"`
+	Data       []ProductRecallResponse `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+// ProductRecallQuery - Query parameters for listing product recalls
+type ProductRecallQuery struct {
+	Page      int     `form:"page" binding:"omitempty,min=1"`
+	PageSize  int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search    string  `form:"search" binding:"omitempty"`
+	Status    *string `form:"status" binding:"omitempty,oneof=OPEN CLOSED"`
+	ProductID *string `form:"product_id" binding:"omitempty,uuid"`
+}
+
+// RecallHoldLine - Recalled batch stock held in one warehouse
+type RecallHoldLine struct {
+	BatchID         string     `json:"batchId"`
+	BatchNumber     string     `json:"batchNumber"`
+	ProductID       string     `json:"productId"`
+	ProductCode     string     `json:"productCode"`
+	ProductName     string     `json:"productName"`
+	WarehouseID     string     `json:"warehouseId"`
+	WarehouseCode   string     `json:"warehouseCode"`
+	WarehouseName   string     `json:"warehouseName"`
+	ExpiryDate      *time.Time `json:"expiryDate,omitempty"`
+	HeldQuantity    string     `json:"heldQuantity"`    // On hand when the recall was opened
+	CurrentQuantity string     `json:"currentQuantity"` // On hand now (includes returned goods)
+}
+
+// RecallSourceLine - Goods receipt and supplier a recalled lot came from (backward trace)
+type RecallSourceLine struct {
+	GoodsReceiptID    *string  `json:"goodsReceiptId,omitempty"`
+	GRNNumber         *string  `json:"grnNumber,omitempty"`
+	GRNDate           *string  `json:"grnDate,omitempty"`
+	SupplierID        *string  `json:"supplierId,omitempty"`
+	SupplierCode      *string  `json:"supplierCode,omitempty"`
+	SupplierName      *string  `json:"supplierName,omitempty"`
+	SupplierReference *string  `json:"supplierReference,omitempty"`
+	BatchNumbers      []string `json:"batchNumbers"`
+	ReceivedQuantity  string   `json:"receivedQuantity"` // Accepted quantity of the recalled products on the receipt
+}
+
+// RecallDeliveryLine - One delivery line that shipped a recalled batch
+type RecallDeliveryLine struct {
+	DeliveryID           string  `json:"deliveryId"`
+	DeliveryNumber       string  `json:"deliveryNumber"`
+	DeliveryDate         string  `json:"deliveryDate"`
+	ProductID            string  `json:"productId"`
+	ProductCode          string  `json:"productCode"`
+	BatchNumber          string  `json:"batchNumber"`
+	Quantity             string  `json:"quantity"` // Base unit
+	ReturnDeliveryID     *string `json:"returnDeliveryId,omitempty"`
+	ReturnDeliveryNumber *string `json:"returnDeliveryNumber,omitempty"`
+	ReturnStatus         *string `json:"returnStatus,omitempty"`
+}
+
+// RecallCustomerLine - Customer that received a recalled lot (forward trace)
+type RecallCustomerLine struct {
+	CustomerID    string               `json:"customerId"`
+	CustomerCode  string               `json:"customerCode"`
+	CustomerName  string               `json:"customerName"`
+	Phone         *string              `json:"phone,omitempty"`
+	TotalQuantity string               `json:"totalQuantity"`
+	Deliveries    []RecallDeliveryLine `json:"deliveries"`
+}
+
+// RecallReportResponse - Recall report: stock on hold, sources and affected customers
+type RecallReportResponse struct {
+	Recall    ProductRecallResponse `json:"recall"`
+	Holds     []RecallHoldLine      `json:"holds"`
+	Sources   []RecallSourceLine    `json:"sources"`
+	Customers []RecallCustomerLine  `json:"customers"`
+}
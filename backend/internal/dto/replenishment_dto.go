@@ -0,0 +1,87 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// REPLENISHMENT DTOs
+// Reorder suggestions from stock levels and draft purchase orders created from them
+// ============================================================================
+
+// ReplenishmentQuery - Query parameters for a replenishment run
+type ReplenishmentQuery struct {
+	WarehouseID *string `form:"warehouseId" binding:"omitempty,uuid"`
+	SupplierID  *string `form:"supplierId" binding:"omitempty,uuid"`
+	UsageDays   int     `form:"usageDays" binding:"omitempty,min=1,max=365"` // Sales history used for average daily usage (default 30)
+}
+
+// ReplenishmentLine - Reorder suggestion for one product in one warehouse
+type ReplenishmentLine struct {
+	WarehouseID   string `json:"warehouseId"`
+	WarehouseCode string `json:"warehouseCode"`
+	WarehouseName string `json:"warehouseName"`
+	ProductID     string `json:"productId"`
+	ProductCode   string `json:"productCode"`
+	ProductName   string `json:"productName"`
+	BaseUnit      string `json:"baseUnit"`
+	OnHand        string `json:"onHand"`
+	Reserved      string `json:"reserved"`
+	InTransit     string `json:"inTransit"`
+	OnOrder       string `json:"onOrder"`   // Open DRAFT/CONFIRMED purchase order quantity not yet received
+	Projected     string `json:"projected"` // onHand - reserved + inTransit + onOrder
+	DailyUsage    string `json:"dailyUsage"`
+	LeadTimeDays  int    `json:"leadTimeDays"`
+	MinimumStock  string `json:"minimumStock"`
+	MaximumStock  string `json:"maximumStock"`
+	ReorderPoint  string `json:"reorderPoint"` // minimumStock + dailyUsage x leadTimeDays
+	TargetStock   string `json:"targetStock"`
+	SuggestedQty  string `json:"suggestedQty"` // Base unit
+	UnitPrice     string `json:"unitPrice"`    // Supplier price per base unit
+	Amount        string `json:"amount"`
+}
+
+// ReplenishmentSupplierGroup - Suggestions for one supplier (nil supplier = product has no supplier)
+type ReplenishmentSupplierGroup struct {
+	SupplierID   *string             `json:"supplierId,omitempty"`
+	SupplierCode string              `json:"supplierCode,omitempty"`
+	SupplierName string              `json:"supplierName,omitempty"`
+	TotalAmount  string              `json:"totalAmount"`
+	Lines        []ReplenishmentLine `json:"lines"`
+}
+
+// ReplenishmentResponse - Result of a replenishment run
+type ReplenishmentResponse struct {
+	GeneratedAt time.Time                    `json:"generatedAt"`
+	UsageDays   int                          `json:"usageDays"`
+	Groups      []ReplenishmentSupplierGroup `json:"groups"`
+}
+
+// CreateReplenishmentOrdersRequest - Reviewed suggestions to turn into DRAFT purchase orders
+// One purchase order is created per supplier and warehouse
+type CreateReplenishmentOrdersRequest struct {
+	PODate *string                        `json:"poDate" binding:"omitempty"` // YYYY-MM-DD, defaults to today
+	Notes  *string                        `json:"notes" binding:"omitempty"`
+	Lines  []CreateReplenishmentOrderLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// CreateReplenishmentOrderLine - One reviewed suggestion line
+type CreateReplenishmentOrderLine struct {
+	SupplierID  string  `json:"supplierId" binding:"required,uuid"`
+	WarehouseID string  `json:"warehouseId" binding:"required,uuid"`
+	ProductID   string  `json:"productId" binding:"required,uuid"`
+	Quantity    string  `json:"quantity" binding:"required"`   // Base unit
+	UnitPrice   *string `json:"unitPrice" binding:"omitempty"` // Defaults to the supplier price
+	Notes       *string `json:"notes" binding:"omitempty"`
+}
+
+// CreateReplenishmentOrdersResponse - Draft purchase orders created from a replenishment run
+type CreateReplenishmentOrdersResponse struct {
+	PurchaseOrders []PurchaseOrderResponse   `json:"purchaseOrders"`
+	Errors         []ReplenishmentOrderError `json:"errors"` // Supplier and warehouse groups whose order was not created
+}
+
+// ReplenishmentOrderError describes a supplier and warehouse group whose purchase order was not created
+type ReplenishmentOrderError struct {
+	SupplierID  string `json:"supplierId"`
+	WarehouseID string `json:"warehouseId"`
+	Message     string `json:"message"`
+}
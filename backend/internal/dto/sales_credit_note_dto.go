@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// SALES CREDIT NOTE DTOs
+// Credit given to customers against invoices (returned goods, price corrections,
+// short shipments)
+// ============================================================================
+
+// CreateSalesCreditNoteRequest - Request to credit part of an invoice
+// Credit notes for returned goods are issued by completing a customer return
+type CreateSalesCreditNoteRequest struct {
+	InvoiceID      string                             `json:"invoiceId" binding:"required,uuid"`
+	CreditNoteDate string                             `json:"creditNoteDate" binding:"required"` // YYYY-MM-DD
+	Category       string                             `json:"category" binding:"required,oneof=PRICE_CORRECTION SHORT_SHIPMENT OTHER"`
+	Reason         string                             `json:"reason" binding:"required,min=3"`
+	Notes          *string                            `json:"notes" binding:"omitempty"`
+	Items          []CreateSalesCreditNoteItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateSalesCreditNoteItemRequest - One credited line
+// With an invoice line, quantity is in the product base unit and the unit price
+// defaults to the line's net price per base unit
+type CreateSalesCreditNoteItemRequest struct {
+	InvoiceItemID *string `json:"invoiceItemId" binding:"omitempty,uuid"`
+	Description   *string `json:"description" binding:"omitempty,max=500"` // Defaults to the product name
+	Quantity      string  `json:"quantity" binding:"required"`
+	UnitPrice     *string `json:"unitPrice" binding:"omitempty"` // Required without an invoice line
+}
+
+// ApplySalesCreditNoteRequest - Request to use unapplied credit against an open invoice
+type ApplySalesCreditNoteRequest struct {
+	InvoiceID string  `json:"invoiceId" binding:"required,uuid"`
+	Amount    *string `json:"amount" binding:"omitempty"` // Defaults to the smaller of unapplied credit and invoice balance
+}
+
+// RefundSalesCreditNoteRequest - Request to pay unapplied credit back to the customer
+type RefundSalesCreditNoteRequest struct {
+	RefundDate    string  `json:"refundDate" binding:"required"` // YYYY-MM-DD
+	Amount        *string `json:"amount" binding:"omitempty"`    // Defaults to all unapplied credit
+	PaymentMethod string  `json:"paymentMethod" binding:"required,oneof=CASH BANK_TRANSFER CHECK GIRO CREDIT_CARD DEBIT_CARD E_WALLET OTHER"`
+	Reference     *string `json:"reference" binding:"omitempty,max=100"`
+	BankAccountID *string `json:"bankAccountId" binding:"omitempty,uuid"`
+	Notes         *string `json:"notes" binding:"omitempty"`
+}
+
+// SalesCreditNoteItemResponse - Response DTO for credited line
+type SalesCreditNoteItemResponse struct {
+	ID                   string                `json:"id"`
+	InvoiceItemID        *string               `json:"invoiceItemId,omitempty"`
+	CustomerReturnItemID *string               `json:"customerReturnItemId,omitempty"`
+	ProductID            *string               `json:"productId,omitempty"`
+	Product              *ProductBasicResponse `json:"product,omitempty"`
+	Description          string                `json:"description"`
+	Quantity             string                `json:"quantity"`
+	UnitPrice            string                `json:"unitPrice"`
+	Subtotal             string                `json:"subtotal"`
+}
+
+// SalesCreditNoteApplicationResponse - Part of a credit note used against an invoice
+type SalesCreditNoteApplicationResponse struct {
+	ID            string    `json:"id"`
+	InvoiceID     string    `json:"invoiceId"`
+	InvoiceNumber string    `json:"invoiceNumber,omitempty"`
+	Amount        string    `json:"amount"`
+	AppliedAt     time.Time `json:"appliedAt"`
+	AppliedBy     *string   `json:"appliedBy,omitempty"`
+}
+
+// SalesCreditNoteRefundResponse - Unapplied credit paid back to the customer
+type SalesCreditNoteRefundResponse struct {
+	ID            string    `json:"id"`
+	RefundDate    string    `json:"refundDate"`
+	Amount        string    `json:"amount"`
+	PaymentMethod string    `json:"paymentMethod"`
+	Reference     *string   `json:"reference,omitempty"`
+	BankAccountID *string   `json:"bankAccountId,omitempty"`
+	Notes         *string   `json:"notes,omitempty"`
+	CreatedBy     *string   `json:"createdBy,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// SalesCreditNoteResponse - Response DTO for sales credit note
+type SalesCreditNoteResponse struct {
+	ID               string                               `json:"id"`
+	CreditNoteNumber string                               `json:"creditNoteNumber"`
+	CreditNoteDate   string                               `json:"creditNoteDate"`
+	CustomerID       string                               `json:"customerId"`
+	CustomerCode     string                               `json:"customerCode,omitempty"`
+	CustomerName     string                               `json:"customerName,omitempty"`
+	InvoiceID        *string                              `json:"invoiceId,omitempty"`
+	InvoiceNumber    *string                              `json:"invoiceNumber,omitempty"`
+	CustomerReturnID *string                              `json:"customerReturnId,omitempty"`
+	Category         string                               `json:"category"`
+	Reason           string                               `json:"reason"`
+	Subtotal         string                               `json:"subtotal"`
+	DiscountAmount   string                               `json:"discountAmount"`
+	TaxAmount        string                               `json:"taxAmount"`
+	TotalAmount      string                               `json:"totalAmount"`
+	AppliedAmount    string                               `json:"appliedAmount"`
+	RefundedAmount   string                               `json:"refundedAmount"`
+	UnappliedAmount  string                               `json:"unappliedAmount"` // Still available as customer credit
+	Status           string                               `json:"status"`
+	Notes            *string                              `json:"notes,omitempty"`
+	Items            []SalesCreditNoteItemResponse        `json:"items"`
+	Applications     []SalesCreditNoteApplicationResponse `json:"applications"`
+	Refunds          []SalesCreditNoteRefundResponse      `json:"refunds"`
+	CreatedBy        *string                              `json:"createdBy,omitempty"`
+	CreatedAt        time.Time                            `json:"createdAt"`
+	UpdatedAt        time.Time                            `json:"updatedAt"`
+}
+
+// SalesCreditNoteListResponse - Response DTO for sales credit note list with pagination
+type SalesCreditNoteListResponse struct {
+	Success    bool                      `json:"success"`
+	Data       []SalesCreditNoteResponse `json:"data"`
+	Pagination PaginationInfo            `json:"pagination"`
+}
+
+// SalesCreditNoteQuery - Query parameters for listing sales credit notes
+type SalesCreditNoteQuery struct {
+	Page       int     `form:"page" binding:"omitempty,min=1"`
+	PageSize   int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search     string  `form:"search" binding:"omitempty"`
+	Status     *string `form:"status" binding:"omitempty,oneof=OPEN APPLIED"`
+	Category   *string `form:"category" binding:"omitempty,oneof=RETURN PRICE_CORRECTION SHORT_SHIPMENT OTHER"`
+	CustomerID *string `form:"customer_id" binding:"omitempty,uuid"`
+	InvoiceID  *string `form:"invoice_id" binding:"omitempty,uuid"`
+}
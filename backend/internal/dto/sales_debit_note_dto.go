@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// SALES DEBIT NOTE DTOs
+// Additional amounts charged to customers against invoices
+// ============================================================================
+
+// CreateSalesDebitNoteRequest - Request to charge an additional amount on an invoice
+type CreateSalesDebitNoteRequest struct {
+	InvoiceID     string                            `json:"invoiceId" binding:"required,uuid"`
+	DebitNoteDate string                            `json:"debitNoteDate" binding:"required"` // YYYY-MM-DD
+	Category      string                            `json:"category" binding:"required,oneof=PRICE_CORRECTION ADDITIONAL_CHARGE OTHER"`
+	Reason        string                            `json:"reason" binding:"required,min=3"`
+	Notes         *string                           `json:"notes" binding:"omitempty"`
+	Items         []CreateSalesDebitNoteItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateSalesDebitNoteItemRequest - One debited line
+// With an invoice line, quantity is in the product base unit
+type CreateSalesDebitNoteItemRequest struct {
+	InvoiceItemID *string `json:"invoiceItemId" binding:"omitempty,uuid"`
+	Description   *string `json:"description" binding:"omitempty,max=500"` // Defaults to the product name
+	Quantity      string  `json:"quantity" binding:"required"`
+	UnitPrice     string  `json:"unitPrice" binding:"required"`
+}
+
+// SalesDebitNoteItemResponse - Response DTO for debited line
+type SalesDebitNoteItemResponse struct {
+	ID            string                `json:"id"`
+	InvoiceItemID *string               `json:"invoiceItemId,omitempty"`
+	ProductID     *string               `json:"productId,omitempty"`
+	Product       *ProductBasicResponse `json:"product,omitempty"`
+	Description   string                `json:"description"`
+	Quantity      string                `json:"quantity"`
+	UnitPrice     string                `json:"unitPrice"`
+	Subtotal      string                `json:"subtotal"`
+}
+
+// SalesDebitNoteResponse - Response DTO for sales debit note
+type SalesDebitNoteResponse struct {
+	ID              string                       `json:"id"`
+	DebitNoteNumber string                       `json:"debitNoteNumber"`
+	DebitNoteDate   string                       `json:"debitNoteDate"`
+	CustomerID      string                       `json:"customerId"`
+	CustomerCode    string                       `json:"customerCode,omitempty"`
+	CustomerName    string                       `json:"customerName,omitempty"`
+	InvoiceID       string                       `json:"invoiceId"`
+	InvoiceNumber   string                       `json:"invoiceNumber,omitempty"`
+	Category        string                       `json:"category"`
+	Reason          string                       `json:"reason"`
+	Subtotal        string                       `json:"subtotal"`
+	DiscountAmount  string                       `json:"discountAmount"`
+	TaxAmount       string                       `json:"taxAmount"`
+	TotalAmount     string                       `json:"totalAmount"`
+	Notes           *string                      `json:"notes,omitempty"`
+	Items           []SalesDebitNoteItemResponse `json:"items"`
+	CreatedBy       *string                      `json:"createdBy,omitempty"`
+	CreatedAt       time.Time                    `json:"createdAt"`
+	UpdatedAt       time.Time                    `json:"updatedAt"`
+}
+
+// SalesDebitNoteListResponse - Response DTO for sales debit note list with pagination
+type SalesDebitNoteListResponse struct {
+	Success    bool                     `json:"success"`
+	Data       []SalesDebitNoteResponse `json:"data"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
+
+// SalesDebitNoteQuery - Query parameters for listing sales debit notes
+type SalesDebitNoteQuery struct {
+	Page       int     `form:"page" binding:"omitempty,min=1"`
+	PageSize   int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search     string  `form:"search" binding:"omitempty"`
+	Category   *string `form:"category" binding:"omitempty,oneof=PRICE_CORRECTION ADDITIONAL_CHARGE OTHER"`
+	CustomerID *string `form:"customer_id" binding:"omitempty,uuid"`
+	InvoiceID  *string `form:"invoice_id" binding:"omitempty,uuid"`
+}
@@ -121,6 +121,7 @@ type SalesOrderItemResponse struct {
 	UnitId       *string `json:"unitId,omitempty"`
 	UnitName     string  `json:"unitName"` // Always present (base unit if no unit specified)
 	OrderedQty   string  `json:"orderedQty"` // decimal as string
+	BaseQty      string  `json:"baseQty"` // OrderedQty in product base unit
 	UnitPrice    string  `json:"unitPrice"` // decimal as string
 	Discount     string  `json:"discount"` // decimal as string
 	LineTotal    string  `json:"lineTotal"` // decimal as string
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+)
+
+// ============================================================================
+// SERIAL NUMBER DTOs
+// Unit-level tracking of serial-tracked products from supplier to customer
+// ============================================================================
+
+// SerialListQuery - Query parameters for listing serials
+type SerialListQuery struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search      string `form:"search" binding:"omitempty"` // Serial number
+	ProductID   string `form:"product_id" binding:"omitempty,uuid"`
+	Status      string `form:"status" binding:"omitempty,oneof=IN_STOCK IN_TRANSIT DELIVERED LOST"`
+	WarehouseID string `form:"warehouse_id" binding:"omitempty,uuid"`
+	CustomerID  string `form:"customer_id" binding:"omitempty,uuid"`
+}
+
+// SerialResponse - Response DTO for a serial number
+type SerialResponse struct {
+	ID                string                `json:"id"`
+	SerialNumber      string                `json:"serialNumber"`
+	Status            string                `json:"status"`
+	Product           *ProductBasicResponse `json:"product,omitempty"`
+	WarehouseID       *string               `json:"warehouseId,omitempty"`
+	WarehouseName     *string               `json:"warehouseName,omitempty"`
+	BatchID           *string               `json:"batchId,omitempty"`
+	BatchNumber       *string               `json:"batchNumber,omitempty"`
+	SupplierID        *string               `json:"supplierId,omitempty"`
+	SupplierName      *string               `json:"supplierName,omitempty"`
+	GoodsReceiptID    *string               `json:"goodsReceiptId,omitempty"`
+	GRNNumber         *string               `json:"grnNumber,omitempty"`
+	ReceivedAt        time.Time             `json:"receivedAt"`
+	CustomerID        *string               `json:"customerId,omitempty"`
+	CustomerName      *string               `json:"customerName,omitempty"`
+	DeliveryID        *string               `json:"deliveryId,omitempty"`
+	DeliveryNumber    *string               `json:"deliveryNumber,omitempty"`
+	WarrantyStartDate *string               `json:"warrantyStartDate,omitempty"` // YYYY-MM-DD, date of the last delivery
+}
+
+// SerialListResponse - Response DTO for serial list with pagination
+type SerialListResponse struct {
+	Success    bool             `json:"success"`
+	Data       []SerialResponse `json:"data"`
+	Pagination PaginationInfo   `json:"pagination"`
+}
+
+// SerialEventResponse - One step in the history of a serial
+type SerialEventResponse struct {
+	ID              string    `json:"id"`
+	EventType       string    `json:"eventType"`
+	EventDate       time.Time `json:"eventDate"`
+	Status          string    `json:"status"`
+	WarehouseID     *string   `json:"warehouseId,omitempty"`
+	WarehouseName   *string   `json:"warehouseName,omitempty"`
+	CustomerID      *string   `json:"customerId,omitempty"`
+	CustomerName    *string   `json:"customerName,omitempty"`
+	SupplierID      *string   `json:"supplierId,omitempty"`
+	SupplierName    *string   `json:"supplierName,omitempty"`
+	ReferenceType   string    `json:"referenceType"`
+	ReferenceID     string    `json:"referenceId"`
+	ReferenceNumber string    `json:"referenceNumber"`
+	Notes           *string   `json:"notes,omitempty"`
+	CreatedBy       *string   `json:"createdBy,omitempty"`
+}
+
+// SerialHistoryResponse - Serial with its path from supplier to customer, oldest event first
+type SerialHistoryResponse struct {
+	Serial SerialResponse        `json:"serial"`
+	Events []SerialEventResponse `json:"events"`
+}
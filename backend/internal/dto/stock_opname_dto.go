@@ -10,15 +10,18 @@ import "time"
 type CreateStockOpnameRequest struct {
 	OpnameDate  string                         `json:"opnameDate" binding:"required"`
 	WarehouseID string                         `json:"warehouseId" binding:"required"`
+	BlindCount  bool                           `json:"blindCount"`                                                    // Hide system quantities from counters until COMPLETED
+	FreezeScope string                         `json:"freezeScope" binding:"omitempty,oneof=NONE WAREHOUSE PRODUCTS"` // Block stock postings while IN_PROGRESS, default NONE
 	Notes       *string                        `json:"notes" binding:"omitempty"`
 	Items       []CreateStockOpnameItemRequest `json:"items" binding:"required,min=1,dive"`
 }
 
 // UpdateStockOpnameRequest represents stock opname update request
 type UpdateStockOpnameRequest struct {
-	OpnameDate *string `json:"opnameDate" binding:"omitempty"`
-	Status     *string `json:"status" binding:"omitempty,oneof=draft in_progress completed"`
-	Notes      *string `json:"notes" binding:"omitempty"`
+	OpnameDate  *string `json:"opnameDate" binding:"omitempty"`
+	Status      *string `json:"status" binding:"omitempty,oneof=draft in_progress completed"`
+	FreezeScope *string `json:"freezeScope" binding:"omitempty,oneof=NONE WAREHOUSE PRODUCTS"`
+	Notes       *string `json:"notes" binding:"omitempty"`
 }
 
 // ApproveStockOpnameRequest represents stock opname approval request
@@ -29,8 +32,8 @@ type ApproveStockOpnameRequest struct {
 // CreateStockOpnameItemRequest represents stock opname item creation
 type CreateStockOpnameItemRequest struct {
 	ProductID   string  `json:"productId" binding:"required"`
-	ExpectedQty string  `json:"expectedQty" binding:"required"` // decimal as string
-	ActualQty   string  `json:"actualQty" binding:"required"`   // decimal as string
+	ExpectedQty string  `json:"expectedQty" binding:"omitempty"` // decimal as string; required unless blind count (taken from stock)
+	ActualQty   string  `json:"actualQty" binding:"required"`    // decimal as string
 	Notes       *string `json:"notes" binding:"omitempty"`
 }
 
@@ -79,10 +82,14 @@ type StockOpnameResponse struct {
 	WarehouseID      string                    `json:"warehouseId"`
 	WarehouseName    *string                   `json:"warehouseName,omitempty"`
 	Status           string                    `json:"status"`
+	BlindCount       bool                      `json:"blindCount"`
+	SystemQtyHidden  bool                      `json:"systemQtyHidden"` // Blind count not yet COMPLETED: expected qty and differences omitted
+	CycleCountPlanID *string                   `json:"cycleCountPlanId,omitempty"`
+	FreezeScope      string                    `json:"freezeScope"`
 	TotalItems       int                       `json:"totalItems"`
-	TotalExpectedQty string                    `json:"totalExpectedQty"` // decimal as string
-	TotalActualQty   string                    `json:"totalActualQty"`   // decimal as string
-	TotalDifference  string                    `json:"totalDifference"`  // decimal as string
+	TotalExpectedQty string                    `json:"totalExpectedQty,omitempty"` // decimal as string
+	TotalActualQty   string                    `json:"totalActualQty"`             // decimal as string
+	TotalDifference  string                    `json:"totalDifference,omitempty"`  // decimal as string
 	Notes            *string                   `json:"notes,omitempty"`
 	CreatedBy        string                    `json:"createdBy"`
 	CreatedByName    *string                   `json:"createdByName,omitempty"`
@@ -96,15 +103,20 @@ type StockOpnameResponse struct {
 
 // StockOpnameItemResponse represents stock opname item information
 type StockOpnameItemResponse struct {
-	ID          string  `json:"id"`
-	OpnameID    string  `json:"opnameId"`
-	ProductID   string  `json:"productId"`
-	ProductCode *string `json:"productCode,omitempty"`
-	ProductName *string `json:"productName,omitempty"`
-	ExpectedQty string  `json:"expectedQty"` // decimal as string
-	ActualQty   string  `json:"actualQty"`   // decimal as string
-	Difference  string  `json:"difference"`  // decimal as string
-	Notes       *string `json:"notes,omitempty"`
+	ID          string     `json:"id"`
+	OpnameID    string     `json:"opnameId"`
+	ProductID   string     `json:"productId"`
+	ProductCode *string    `json:"productCode,omitempty"`
+	ProductName *string    `json:"productName,omitempty"`
+	BatchID     *string    `json:"batchId,omitempty"`
+	BatchNumber *string    `json:"batchNumber,omitempty"`
+	BinID       *string    `json:"binId,omitempty"`
+	BinCode     *string    `json:"binCode,omitempty"`
+	ExpectedQty string     `json:"expectedQty,omitempty"` // decimal as string, omitted while a blind count is hidden
+	ActualQty   string     `json:"actualQty"`             // decimal as string
+	Difference  string     `json:"difference,omitempty"`  // decimal as string, omitted while a blind count is hidden
+	CountedAt   *time.Time `json:"countedAt,omitempty"`   // Approval values expectedQty as of this instant
+	Notes       *string    `json:"notes,omitempty"`
 }
 
 // ============================================================================
@@ -136,3 +148,80 @@ type ImportProductsResponse struct {
 	ItemsAdded int    `json:"itemsAdded"`
 	Message    string `json:"message"`
 }
+
+// ImportStockOpnameCountsResponse reports a count import from CSV or handheld file
+type ImportStockOpnameCountsResponse struct {
+	TotalRows    int                           `json:"totalRows"`    // Non-blank data rows
+	ImportedRows int                           `json:"importedRows"` // Rows applied (several rows may add up into one item)
+	UpdatedItems int                           `json:"updatedItems"`
+	Errors       []StockOpnameCountImportError `json:"errors"`
+}
+
+// StockOpnameCountImportError describes a CSV row that was not applied
+type StockOpnameCountImportError struct {
+	Row        int    `json:"row"` // Line number in the file
+	Identifier string `json:"identifier"`
+	Message    string `json:"message"`
+}
+
+// ============================================================================
+// CYCLE COUNT PLAN DTOs
+// ============================================================================
+
+// CreateCycleCountPlanRequest represents cycle-count plan creation
+type CreateCycleCountPlanRequest struct {
+	Name          string  `json:"name" binding:"required,max=255"`
+	WarehouseID   string  `json:"warehouseId" binding:"required,uuid"`
+	Method        string  `json:"method" binding:"required,oneof=ABC LOCATION"`
+	FrequencyDays int     `json:"frequencyDays" binding:"omitempty,min=1,max=365"` // Default 7
+	ClassADays    int     `json:"classADays" binding:"omitempty,min=1,max=3650"`   // ABC, default 30
+	ClassBDays    int     `json:"classBDays" binding:"omitempty,min=1,max=3650"`   // ABC, default 90
+	ClassCDays    int     `json:"classCDays" binding:"omitempty,min=1,max=3650"`   // ABC, default 180
+	CycleDays     int     `json:"cycleDays" binding:"omitempty,min=1,max=3650"`    // LOCATION, default 90
+	BlindCount    *bool   `json:"blindCount"`                                      // Default true
+	StartDate     *string `json:"startDate" binding:"omitempty"`                   // YYYY-MM-DD first run, defaults to today
+	Notes         *string `json:"notes" binding:"omitempty"`
+}
+
+// UpdateCycleCountPlanRequest represents cycle-count plan update
+type UpdateCycleCountPlanRequest struct {
+	Name          *string `json:"name" binding:"omitempty,max=255"`
+	FrequencyDays *int    `json:"frequencyDays" binding:"omitempty,min=1,max=365"`
+	ClassADays    *int    `json:"classADays" binding:"omitempty,min=1,max=3650"`
+	ClassBDays    *int    `json:"classBDays" binding:"omitempty,min=1,max=3650"`
+	ClassCDays    *int    `json:"classCDays" binding:"omitempty,min=1,max=3650"`
+	CycleDays     *int    `json:"cycleDays" binding:"omitempty,min=1,max=3650"`
+	BlindCount    *bool   `json:"blindCount"`
+	NextRunDate   *string `json:"nextRunDate" binding:"omitempty"` // YYYY-MM-DD
+	IsActive      *bool   `json:"isActive"`
+	Notes         *string `json:"notes" binding:"omitempty"`
+}
+
+// CycleCountPlanResponse represents a cycle-count plan
+type CycleCountPlanResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	WarehouseID   string     `json:"warehouseId"`
+	WarehouseName *string    `json:"warehouseName,omitempty"`
+	Method        string     `json:"method"`
+	FrequencyDays int        `json:"frequencyDays"`
+	ClassADays    int        `json:"classADays"`
+	ClassBDays    int        `json:"classBDays"`
+	ClassCDays    int        `json:"classCDays"`
+	CycleDays     int        `json:"cycleDays"`
+	BlindCount    bool       `json:"blindCount"`
+	IsActive      bool       `json:"isActive"`
+	NextRunDate   string     `json:"nextRunDate"`
+	LastRunAt     *time.Time `json:"lastRunAt,omitempty"`
+	Notes         *string    `json:"notes,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// RunCycleCountPlanResponse represents the result of running a cycle-count plan
+type RunCycleCountPlanResponse struct {
+	StockOpnameID *string `json:"stockOpnameId,omitempty"` // nil when nothing was due
+	OpnameNumber  string  `json:"opnameNumber,omitempty"`
+	ItemCount     int     `json:"itemCount"`
+	NextRunDate   string  `json:"nextRunDate"`
+}
@@ -19,11 +19,13 @@ type CreateStockTransferRequest struct {
 }
 
 // CreateStockTransferItemRequest - Stock transfer item
+// Serial-tracked products need one serial per unit, in stock at the source warehouse
 type CreateStockTransferItemRequest struct {
-	ProductID string  `json:"productId" binding:"required,uuid"`
-	Quantity  string  `json:"quantity" binding:"required"`
-	BatchID   *string `json:"batchId" binding:"omitempty"`
-	Notes     *string `json:"notes" binding:"omitempty"`
+	ProductID     string   `json:"productId" binding:"required,uuid"`
+	Quantity      string   `json:"quantity" binding:"required"`
+	BatchID       *string  `json:"batchId" binding:"omitempty"`
+	Notes         *string  `json:"notes" binding:"omitempty"`
+	SerialNumbers []string `json:"serialNumbers" binding:"omitempty,dive,required,max=100"`
 }
 
 // UpdateStockTransferRequest - Request to update existing stock transfer (DRAFT only)
@@ -41,8 +43,21 @@ type ShipTransferRequest struct {
 }
 
 // ReceiveTransferRequest - Request to receive a transfer (SHIPPED → RECEIVED)
+// Items is optional; lines not listed are received in full
 type ReceiveTransferRequest struct {
-	Notes *string `json:"notes" binding:"omitempty"`
+	Notes *string                      `json:"notes" binding:"omitempty"`
+	Items []ReceiveTransferItemRequest `json:"items" binding:"omitempty,dive"`
+}
+
+// ReceiveTransferItemRequest - Quantity actually received for one transfer line
+// Any shortfall is recorded as a discrepancy and written off with Reason
+// (defaults to the company's transfer loss reason). For serial-tracked lines the
+// shortfall must name the serials that did not arrive; they are marked LOST.
+type ReceiveTransferItemRequest struct {
+	ItemID               string   `json:"itemId" binding:"required,uuid"`
+	ReceivedQuantity     string   `json:"receivedQuantity" binding:"required"`
+	Reason               *string  `json:"reason" binding:"omitempty,oneof=SHRINKAGE DAMAGE EXPIRED THEFT OTHER"`
+	MissingSerialNumbers []string `json:"missingSerialNumbers" binding:"omitempty,dive,required,max=100"`
 }
 
 // CancelTransferRequest - Request to cancel a transfer (SHIPPED → CANCELLED)
@@ -52,14 +67,20 @@ type CancelTransferRequest struct {
 
 // StockTransferItemResponse - Response DTO for stock transfer item
 type StockTransferItemResponse struct {
-	ID       string                  `json:"id"`
-	ProductID string                 `json:"productId"`
-	Product  *ProductBasicResponse   `json:"product,omitempty"`
-	Quantity string                  `json:"quantity"`
-	BatchID  *string                 `json:"batchId,omitempty"`
-	Notes    *string                 `json:"notes,omitempty"`
-	CreatedAt time.Time              `json:"createdAt"`
-	UpdatedAt time.Time              `json:"updatedAt"`
+	ID                  string                `json:"id"`
+	ProductID           string                `json:"productId"`
+	Product             *ProductBasicResponse `json:"product,omitempty"`
+	Quantity            string                `json:"quantity"` // Shipped quantity
+	BatchID             *string               `json:"batchId,omitempty"`
+	BatchNumber         *string               `json:"batchNumber,omitempty"`
+	ExpiryDate          *time.Time            `json:"expiryDate,omitempty"`
+	DestBatchID         *string               `json:"destBatchId,omitempty"`
+	ReceivedQuantity    *string               `json:"receivedQuantity,omitempty"`
+	DiscrepancyQuantity string                `json:"discrepancyQuantity"`
+	DiscrepancyReason   *string               `json:"discrepancyReason,omitempty"`
+	Notes               *string               `json:"notes,omitempty"`
+	CreatedAt           time.Time             `json:"createdAt"`
+	UpdatedAt           time.Time             `json:"updatedAt"`
 }
 
 // ProductBasicResponse - Basic product info for transfers
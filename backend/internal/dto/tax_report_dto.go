@@ -0,0 +1,50 @@
+package dto
+
+// ============================================================================
+// SALES TAX REPORT DTOs
+// Output tax (PPN Keluaran) from invoices, sales debit notes and credit notes
+// ============================================================================
+
+// SalesTaxReportQuery - Query parameters for the sales tax report
+type SalesTaxReportQuery struct {
+	DateFrom   string  `form:"dateFrom" binding:"omitempty"` // YYYY-MM-DD, defaults to the first day of this month
+	DateTo     string  `form:"dateTo" binding:"omitempty"`   // YYYY-MM-DD, defaults to today
+	CustomerID *string `form:"customerID" binding:"omitempty,uuid"`
+}
+
+// SalesTaxReportLine - Tax base and tax of one document
+// Credit notes reduce output tax, so their amounts are negative
+type SalesTaxReportLine struct {
+	DocumentType   string  `json:"documentType"` // INVOICE, DEBIT_NOTE, CREDIT_NOTE
+	DocumentID     string  `json:"documentId"`
+	DocumentNumber string  `json:"documentNumber"`
+	DocumentDate   string  `json:"documentDate"`
+	CustomerID     string  `json:"customerId"`
+	CustomerCode   string  `json:"customerCode"`
+	CustomerName   string  `json:"customerName"`
+	CustomerNPWP   *string `json:"customerNpwp,omitempty"`
+	InvoiceNumber  string  `json:"invoiceNumber,omitempty"` // Invoice the note adjusts
+	FakturPajakNo  *string `json:"fakturPajakNo,omitempty"` // Of the invoice
+	TaxBase        string  `json:"taxBase"`                 // DPP: subtotal after discount
+	TaxAmount      string  `json:"taxAmount"`               // PPN
+	TotalAmount    string  `json:"totalAmount"`
+}
+
+// SalesTaxReportSummary - Totals of one document type over the period
+type SalesTaxReportSummary struct {
+	DocumentType string `json:"documentType"`
+	Count        int    `json:"count"`
+	TaxBase      string `json:"taxBase"`
+	TaxAmount    string `json:"taxAmount"`
+}
+
+// SalesTaxReportResponse - Sales tax report over a period
+type SalesTaxReportResponse struct {
+	Success      bool                    `json:"success"`
+	DateFrom     string                  `json:"dateFrom"`
+	DateTo       string                  `json:"dateTo"`
+	Summary      []SalesTaxReportSummary `json:"summary"`
+	TotalTaxBase string                  `json:"totalTaxBase"` // Net of credit notes
+	TotalTax     string                  `json:"totalTax"`     // Net of credit notes
+	Data         []SalesTaxReportLine    `json:"data"`
+}
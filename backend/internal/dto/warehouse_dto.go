@@ -21,7 +21,8 @@ type CreateWarehouseRequest struct {
 	Phone      *string `json:"phone" binding:"omitempty,max=50"`
 	Email      *string `json:"email" binding:"omitempty,email,max=255"`
 	ManagerID  *string `json:"managerID" binding:"omitempty"`
-	Capacity   *string `json:"capacity" binding:"omitempty"` // decimal as string
+	CustomerID *string `json:"customerID" binding:"omitempty"` // Required for CONSIGNMENT warehouses
+	Capacity   *string `json:"capacity" binding:"omitempty"`   // decimal as string
 }
 
 // UpdateWarehouseRequest - Request to update an existing warehouse
@@ -36,6 +37,7 @@ type UpdateWarehouseRequest struct {
 	Phone      *string `json:"phone" binding:"omitempty,max=50"`
 	Email      *string `json:"email" binding:"omitempty,email,max=255"`
 	ManagerID  *string `json:"managerID" binding:"omitempty"`
+	CustomerID *string `json:"customerID" binding:"omitempty"`
 	Capacity   *string `json:"capacity" binding:"omitempty"`
 	IsActive   *bool   `json:"isActive" binding:"omitempty"`
 }
@@ -53,6 +55,7 @@ type WarehouseResponse struct {
 	Phone      *string    `json:"phone,omitempty"`
 	Email      *string    `json:"email,omitempty"`
 	ManagerID  *string    `json:"managerID,omitempty"`
+	CustomerID *string    `json:"customerID,omitempty"`
 	Capacity   *string    `json:"capacity,omitempty"`
 	IsActive   bool       `json:"isActive"`
 	CreatedAt  time.Time  `json:"createdAt"`
@@ -93,7 +96,11 @@ type WarehouseStockResponse struct {
 	ProductID     string     `json:"productID"`
 	ProductCode   string     `json:"productCode"`
 	ProductName   string     `json:"productName"`
-	Quantity      string     `json:"quantity"`
+	Quantity      string     `json:"quantity"`          // On hand
+	ReservedQty   string     `json:"reservedQuantity"`  // Reserved for approved sales orders
+	AvailableQty  string     `json:"availableQuantity"` // On hand - reserved - quarantine
+	QuarantineQty string     `json:"quarantineQuantity"` // On quality hold, not sellable
+	InTransitQty  string     `json:"inTransitQuantity"` // Shipped here by stock transfers, not yet received
 	MinimumStock  string     `json:"minimumStock"`
 	MaximumStock  string     `json:"maximumStock"`
 	Location      *string    `json:"location,omitempty"`
@@ -154,6 +161,11 @@ type WarehouseStockStatusResponse struct {
 	HasInitialStock bool      `json:"hasInitialStock"`
 	TotalProducts  int        `json:"totalProducts"`
 	TotalValue     string     `json:"totalValue"`
+	OnHandQty      string     `json:"onHandQuantity"`
+	ReservedQty    string     `json:"reservedQuantity"`
+	AvailableQty   string     `json:"availableQuantity"`
+	QuarantineQty  string     `json:"quarantineQuantity"`
+	InTransitQty   string     `json:"inTransitQuantity"`
 	LastUpdated    *time.Time `json:"lastUpdated,omitempty"`
 }
 
@@ -194,3 +206,106 @@ type InitialStockSetupResponse struct {
 	CreatedStocks int    `json:"createdStocks"`
 	UpdatedStocks int    `json:"updatedStocks"`
 }
+
+// ============================================================================
+// WAREHOUSE BIN DTOs
+// ============================================================================
+
+// CreateWarehouseBinRequest - Request to create a bin location in a warehouse
+type CreateWarehouseBinRequest struct {
+	Code      string  `json:"code" binding:"required,min=1,max=50"`
+	Zone      *string `json:"zone" binding:"omitempty,max=50"`
+	Aisle     *string `json:"aisle" binding:"omitempty,max=20"`
+	Rack      *string `json:"rack" binding:"omitempty,max=20"`
+	Level     *string `json:"level" binding:"omitempty,max=20"`
+	IsDefault bool    `json:"isDefault"`
+	Notes     *string `json:"notes" binding:"omitempty"`
+}
+
+// UpdateWarehouseBinRequest - Request to update a bin location
+type UpdateWarehouseBinRequest struct {
+	Code      *string `json:"code" binding:"omitempty,min=1,max=50"`
+	Zone      *string `json:"zone" binding:"omitempty,max=50"`
+	Aisle     *string `json:"aisle" binding:"omitempty,max=20"`
+	Rack      *string `json:"rack" binding:"omitempty,max=20"`
+	Level     *string `json:"level" binding:"omitempty,max=20"`
+	IsDefault *bool   `json:"isDefault" binding:"omitempty"`
+	IsActive  *bool   `json:"isActive" binding:"omitempty"`
+	Notes     *string `json:"notes" binding:"omitempty"`
+}
+
+// WarehouseBinListQuery - Query parameters for listing the bins of a warehouse
+type WarehouseBinListQuery struct {
+	Search   string  `form:"search" binding:"omitempty"` // Search by bin code
+	Zone     *string `form:"zone" binding:"omitempty"`
+	IsActive *bool   `form:"isActive" binding:"omitempty"`
+}
+
+// WarehouseBinResponse - Response DTO for a bin location
+type WarehouseBinResponse struct {
+	ID          string    `json:"id"`
+	WarehouseID string    `json:"warehouseID"`
+	Code        string    `json:"code"`
+	Zone        *string   `json:"zone,omitempty"`
+	Aisle       *string   `json:"aisle,omitempty"`
+	Rack        *string   `json:"rack,omitempty"`
+	Level       *string   `json:"level,omitempty"`
+	IsDefault   bool      `json:"isDefault"`
+	IsActive    bool      `json:"isActive"`
+	Notes       *string   `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// BinStockListQuery - Query parameters for listing the bin stock of a warehouse
+type BinStockListQuery struct {
+	Page      int     `form:"page" binding:"omitempty,min=1"`
+	PageSize  int     `form:"pageSize" binding:"omitempty,min=1,max=1000"`
+	BinID     *string `form:"binID" binding:"omitempty"`
+	ProductID *string `form:"productID" binding:"omitempty"`
+	Search    string  `form:"search" binding:"omitempty"` // Search by product code or name
+}
+
+// BinStockResponse - Stock of one product (or batch) in one bin
+type BinStockResponse struct {
+	ID          string    `json:"id"`
+	BinID       string    `json:"binID"`
+	BinCode     string    `json:"binCode"`
+	ProductID   string    `json:"productID"`
+	ProductCode string    `json:"productCode"`
+	ProductName string    `json:"productName"`
+	BatchID     *string   `json:"batchID,omitempty"`
+	BatchNumber *string   `json:"batchNumber,omitempty"`
+	Quantity    string    `json:"quantity"` // Base unit
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// BinStockListResponse - Response DTO for bin stock list with pagination
+type BinStockListResponse struct {
+	Stocks     []BinStockResponse `json:"stocks"`
+	TotalCount int64              `json:"totalCount"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	TotalPages int                `json:"totalPages"`
+}
+
+// MoveBinStockRequest - Request to move stock between bins of a warehouse
+// Leave fromBinID empty to put away unbinned stock, or toBinID empty to take stock out of its bin
+type MoveBinStockRequest struct {
+	ProductID string  `json:"productID" binding:"required,uuid"`
+	BatchID   *string `json:"batchID" binding:"omitempty,uuid"`
+	FromBinID *string `json:"fromBinID" binding:"omitempty,uuid"`
+	ToBinID   *string `json:"toBinID" binding:"omitempty,uuid"`
+	Quantity  string  `json:"quantity" binding:"required"` // decimal as string, base unit
+	Notes     *string `json:"notes" binding:"omitempty"`
+}
+
+// BinMoveResponse - Result of a bin-to-bin move
+type BinMoveResponse struct {
+	ProductID   string   `json:"productID"`
+	BatchID     *string  `json:"batchID,omitempty"`
+	FromBinID   *string  `json:"fromBinID,omitempty"`
+	ToBinID     *string  `json:"toBinID,omitempty"`
+	Quantity    string   `json:"quantity"`
+	MovementIDs []string `json:"movementIDs"`
+}
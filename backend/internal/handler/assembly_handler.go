@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/assembly"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// AssemblyHandler - HTTP handlers for assembly and disassembly order endpoints
+type AssemblyHandler struct {
+	assemblyService *assembly.AssemblyService
+}
+
+// NewAssemblyHandler creates a new assembly handler instance
+func NewAssemblyHandler(assemblyService *assembly.AssemblyService) *AssemblyHandler {
+	return &AssemblyHandler{
+		assemblyService: assemblyService,
+	}
+}
+
+// ============================================================================
+// ASSEMBLY ORDER ENDPOINTS
+// ============================================================================
+
+// CreateAssemblyOrder handles POST /api/v1/assembly-orders
+// Creates a DRAFT order with the components of the product's bill of materials
+func (h *AssemblyHandler) CreateAssemblyOrder(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateAssemblyOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	order, err := h.assemblyService.CreateAssemblyOrder(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapAssemblyOrderToResponse(order),
+	})
+}
+
+// ListAssemblyOrders handles GET /api/v1/assembly-orders
+func (h *AssemblyHandler) ListAssemblyOrders(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.AssemblyOrderQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	orders, pagination, err := h.assemblyService.ListAssemblyOrders(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.AssemblyOrderResponse, len(orders))
+	for i := range orders {
+		responses[i] = mapAssemblyOrderToResponse(&orders[i])
+	}
+
+	c.JSON(http.StatusOK, dto.AssemblyOrderListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetAssemblyOrder handles GET /api/v1/assembly-orders/:id
+func (h *AssemblyHandler) GetAssemblyOrder(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	order, err := h.assemblyService.GetAssemblyOrderByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapAssemblyOrderToResponse(order),
+	})
+}
+
+// CompleteAssemblyOrder handles POST /api/v1/assembly-orders/:id/complete
+// Posts the component and finished product movements (DRAFT → COMPLETED)
+func (h *AssemblyHandler) CompleteAssemblyOrder(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	order, err := h.assemblyService.CompleteAssemblyOrder(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userIDStr)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapAssemblyOrderToResponse(order),
+	})
+}
+
+// CancelAssemblyOrder handles POST /api/v1/assembly-orders/:id/cancel
+func (h *AssemblyHandler) CancelAssemblyOrder(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	order, err := h.assemblyService.CancelAssemblyOrder(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapAssemblyOrderToResponse(order),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *AssemblyHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapAssemblyOrderToResponse(order *models.AssemblyOrder) dto.AssemblyOrderResponse {
+	response := dto.AssemblyOrderResponse{
+		ID:            order.ID,
+		OrderNumber:   order.OrderNumber,
+		OrderDate:     order.OrderDate.Format("2006-01-02"),
+		Type:          string(order.Type),
+		Status:        string(order.Status),
+		WarehouseID:   order.WarehouseID,
+		WarehouseName: order.Warehouse.Name,
+		Quantity:      order.Quantity.String(),
+		BatchID:       order.BatchID,
+		BatchNumber:   order.BatchNumber,
+		UnitCost:      order.UnitCost.String(),
+		TotalCost:     order.TotalCost.String(),
+		Notes:         order.Notes,
+		CompletedBy:   order.CompletedBy,
+		CompletedAt:   order.CompletedAt,
+		CreatedBy:     order.CreatedBy,
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+	}
+
+	if order.Product.ID != "" {
+		response.Product = &dto.ProductBasicResponse{
+			ID:   order.Product.ID,
+			Code: order.Product.Code,
+			Name: order.Product.Name,
+		}
+	}
+	if order.Batch != nil {
+		response.BatchNumber = &order.Batch.BatchNumber
+	}
+	if order.ExpiryDate != nil {
+		expiry := order.ExpiryDate.Format("2006-01-02")
+		response.ExpiryDate = &expiry
+	}
+
+	for _, item := range order.Items {
+		itemResponse := dto.AssemblyOrderItemResponse{
+			ID:            item.ID,
+			ProductUnitID: item.ProductUnitID,
+			UnitName:      item.Product.BaseUnit,
+			BatchID:       item.BatchID,
+			Quantity:      item.Quantity.String(),
+			BaseQuantity:  item.BaseQuantity.String(),
+			UnitCost:      item.UnitCost.String(),
+			TotalCost:     item.TotalCost.String(),
+		}
+		if item.Product.ID != "" {
+			itemResponse.Product = &dto.ProductBasicResponse{
+				ID:   item.Product.ID,
+				Code: item.Product.Code,
+				Name: item.Product.Name,
+			}
+		}
+		if item.ProductUnit != nil {
+			itemResponse.UnitName = item.ProductUnit.UnitName
+		}
+		if item.Batch != nil {
+			itemResponse.BatchNumber = &item.Batch.BatchNumber
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -145,6 +147,26 @@ func (h *CompanyHandler) UpdateCompanyProfile(c *gin.Context) {
 	if req.InvoiceTolerancePct != nil {
 		updates["invoice_tolerance_pct"] = *req.InvoiceTolerancePct
 	}
+	// Inventory Valuation Settings
+	if req.CostingMethod != nil {
+		updates["costing_method"] = *req.CostingMethod
+	}
+	// Negative Stock Settings
+	if req.NegativeStockPolicy != nil {
+		updates["negative_stock_policy"] = *req.NegativeStockPolicy
+	}
+	// Stock Transfer Settings
+	if req.TransferLossReason != nil {
+		updates["transfer_loss_reason"] = *req.TransferLossReason
+	}
+	// Batch Expiry Settings
+	if len(req.ExpiryAlertDays) > 0 {
+		days := make([]string, len(req.ExpiryAlertDays))
+		for i, d := range req.ExpiryAlertDays {
+			days[i] = strconv.Itoa(d)
+		}
+		updates["expiry_alert_days"] = strings.Join(days, ",")
+	}
 
 	// Call service with company ID (using MultiCompanyService for PHASE 5)
 	updatedCompany, err := h.multiCompanyService.UpdateCompany(c.Request.Context(), companyID.(string), updates)
@@ -430,6 +452,10 @@ func (h *CompanyHandler) mapCompanyToResponse(companyData interface{}) *dto.Comp
 		// Purchase Invoice Settings (3-way matching)
 		InvoiceControlPolicy: string(companyModel.InvoiceControlPolicy),
 		InvoiceTolerancePct:  companyModel.InvoiceTolerancePct.InexactFloat64(),
+		CostingMethod:        string(companyModel.CostingMethod),
+		NegativeStockPolicy:  string(companyModel.NegativeStockPolicy),
+		TransferLossReason:   string(companyModel.TransferLossReason),
+		ExpiryAlertDays:      companyModel.ExpiryAlertHorizons(),
 		IsActive:             companyModel.IsActive,
 	}
 
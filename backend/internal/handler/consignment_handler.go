@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/consignment"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ConsignmentHandler - HTTP handlers for consignment settlement and balance endpoints
+type ConsignmentHandler struct {
+	consignmentService *consignment.ConsignmentService
+}
+
+// NewConsignmentHandler creates a new consignment handler instance
+func NewConsignmentHandler(consignmentService *consignment.ConsignmentService) *ConsignmentHandler {
+	return &ConsignmentHandler{
+		consignmentService: consignmentService,
+	}
+}
+
+// ============================================================================
+// CONSIGNMENT SETTLEMENT ENDPOINTS
+// ============================================================================
+
+// CreateSettlement handles POST /api/v1/consignments/settlements
+// Settles a customer's sold/on-hand report: creates the sales invoice and the stock-out
+func (h *ConsignmentHandler) CreateSettlement(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateConsignmentSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	settlement, err := h.consignmentService.CreateSettlement(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapConsignmentSettlementToResponse(settlement),
+	})
+}
+
+// ListSettlements handles GET /api/v1/consignments/settlements
+func (h *ConsignmentHandler) ListSettlements(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.ConsignmentSettlementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	settlements, pagination, err := h.consignmentService.ListSettlements(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.ConsignmentSettlementResponse, len(settlements))
+	for i := range settlements {
+		responses[i] = mapConsignmentSettlementToResponse(&settlements[i])
+	}
+
+	c.JSON(http.StatusOK, dto.ConsignmentSettlementListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetSettlement handles GET /api/v1/consignments/settlements/:id
+func (h *ConsignmentHandler) GetSettlement(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	settlement, err := h.consignmentService.GetSettlementByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapConsignmentSettlementToResponse(settlement),
+	})
+}
+
+// GetBalanceReport handles GET /api/v1/consignments/balance
+// Stock still held per customer in their consignment warehouses, with settlement totals
+func (h *ConsignmentHandler) GetBalanceReport(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.ConsignmentBalanceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	report, err := h.consignmentService.GetBalanceReport(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *ConsignmentHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapConsignmentSettlementToResponse(settlement *models.ConsignmentSettlement) dto.ConsignmentSettlementResponse {
+	response := dto.ConsignmentSettlementResponse{
+		ID:               settlement.ID,
+		SettlementNumber: settlement.SettlementNumber,
+		SettlementDate:   settlement.SettlementDate.Format("2006-01-02"),
+		CustomerID:       settlement.CustomerID,
+		CustomerCode:     settlement.Customer.Code,
+		CustomerName:     settlement.Customer.Name,
+		WarehouseID:      settlement.WarehouseID,
+		WarehouseCode:    settlement.Warehouse.Code,
+		WarehouseName:    settlement.Warehouse.Name,
+		ReportType:       string(settlement.ReportType),
+		InvoiceID:        settlement.InvoiceID,
+		Subtotal:         settlement.Subtotal.String(),
+		TaxAmount:        settlement.TaxAmount.String(),
+		TotalAmount:      settlement.TotalAmount.String(),
+		CostAmount:       settlement.CostAmount.String(),
+		Notes:            settlement.Notes,
+		CreatedBy:        settlement.CreatedBy,
+		CreatedAt:        settlement.CreatedAt,
+		UpdatedAt:        settlement.UpdatedAt,
+	}
+
+	if settlement.Invoice != nil {
+		response.InvoiceNumber = &settlement.Invoice.InvoiceNumber
+	}
+
+	for _, item := range settlement.Items {
+		itemResponse := dto.ConsignmentSettlementItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			BatchID:     item.BatchID,
+			ReportedQty: item.ReportedQty.String(),
+			StockQty:    item.StockQty.String(),
+			SoldQty:     item.SoldQty.String(),
+			UnitPrice:   item.UnitPrice.String(),
+			Subtotal:    item.Subtotal.String(),
+			UnitCost:    item.UnitCost.String(),
+		}
+		if item.Product.ID != "" {
+			itemResponse.Product = &dto.ProductBasicResponse{
+				ID:   item.Product.ID,
+				Code: item.Product.Code,
+				Name: item.Product.Name,
+			}
+		}
+		if item.Batch != nil {
+			itemResponse.BatchNumber = &item.Batch.BatchNumber
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
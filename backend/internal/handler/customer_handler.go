@@ -540,6 +540,7 @@ func mapCustomerToResponse(customer *models.Customer) dto.CustomerResponse {
 		CreditLimit:        customer.CreditLimit.String(),
 		CurrentOutstanding: customer.CurrentOutstanding.String(),
 		OverdueAmount:      customer.OverdueAmount.String(),
+		CreditBalance:      customer.CreditBalance.String(),
 		LastTransactionAt:  customer.LastTransactionAt,
 		Notes:              customer.Notes,
 		IsActive:           customer.IsActive,
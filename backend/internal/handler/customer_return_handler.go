@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/customerreturn"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// CustomerReturnHandler - HTTP handlers for customer return (RMA) endpoints
+type CustomerReturnHandler struct {
+	customerReturnService *customerreturn.CustomerReturnService
+}
+
+// NewCustomerReturnHandler creates a new customer return handler instance
+func NewCustomerReturnHandler(customerReturnService *customerreturn.CustomerReturnService) *CustomerReturnHandler {
+	return &CustomerReturnHandler{
+		customerReturnService: customerReturnService,
+	}
+}
+
+// ============================================================================
+// CUSTOMER RETURN ENDPOINTS
+// ============================================================================
+
+// CreateCustomerReturn handles POST /api/v1/customer-returns
+// Records goods returned against a delivery (DRAFT)
+func (h *CustomerReturnHandler) CreateCustomerReturn(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateCustomerReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	customerReturn, err := h.customerReturnService.CreateReturn(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapCustomerReturnToResponse(customerReturn),
+	})
+}
+
+// ListCustomerReturns handles GET /api/v1/customer-returns
+func (h *CustomerReturnHandler) ListCustomerReturns(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.CustomerReturnQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	returns, pagination, err := h.customerReturnService.ListReturns(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.CustomerReturnResponse, len(returns))
+	for i := range returns {
+		responses[i] = mapCustomerReturnToResponse(&returns[i])
+	}
+
+	c.JSON(http.StatusOK, dto.CustomerReturnListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetCustomerReturn handles GET /api/v1/customer-returns/:id
+func (h *CustomerReturnHandler) GetCustomerReturn(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	customerReturn, err := h.customerReturnService.GetReturnByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapCustomerReturnToResponse(customerReturn),
+	})
+}
+
+// InspectCustomerReturn handles POST /api/v1/customer-returns/:id/inspect
+// Records sellable, quarantine or scrap for every returned line
+func (h *CustomerReturnHandler) InspectCustomerReturn(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, pkgerrors.NewAuthenticationError("User context not found"))
+		return
+	}
+
+	var req dto.InspectCustomerReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	customerReturn, err := h.customerReturnService.InspectReturn(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userID.(string), &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapCustomerReturnToResponse(customerReturn),
+	})
+}
+
+// CompleteCustomerReturn handles POST /api/v1/customer-returns/:id/complete
+// Posts the returned stock and issues the sales credit note
+func (h *CustomerReturnHandler) CompleteCustomerReturn(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, pkgerrors.NewAuthenticationError("User context not found"))
+		return
+	}
+
+	customerReturn, err := h.customerReturnService.CompleteReturn(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userID.(string))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapCustomerReturnToResponse(customerReturn),
+	})
+}
+
+// CancelCustomerReturn handles POST /api/v1/customer-returns/:id/cancel
+func (h *CustomerReturnHandler) CancelCustomerReturn(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	customerReturn, err := h.customerReturnService.CancelReturn(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapCustomerReturnToResponse(customerReturn),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *CustomerReturnHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapCustomerReturnToResponse(customerReturn *models.CustomerReturn) dto.CustomerReturnResponse {
+	response := dto.CustomerReturnResponse{
+		ID:           customerReturn.ID,
+		ReturnNumber: customerReturn.ReturnNumber,
+		ReturnDate:   customerReturn.ReturnDate.Format("2006-01-02"),
+		CustomerID:   customerReturn.CustomerID,
+		DeliveryID:   customerReturn.DeliveryID,
+		InvoiceID:    customerReturn.InvoiceID,
+		WarehouseID:  customerReturn.WarehouseID,
+		Status:       string(customerReturn.Status),
+		Reason:       customerReturn.Reason,
+		Notes:        customerReturn.Notes,
+		Items:        make([]dto.CustomerReturnItemResponse, 0, len(customerReturn.Items)),
+		CreditNoteID: customerReturn.CreditNoteID,
+		InspectedBy:  customerReturn.InspectedBy,
+		InspectedAt:  customerReturn.InspectedAt,
+		CompletedBy:  customerReturn.CompletedBy,
+		CompletedAt:  customerReturn.CompletedAt,
+		CreatedBy:    customerReturn.CreatedBy,
+		CreatedAt:    customerReturn.CreatedAt,
+		UpdatedAt:    customerReturn.UpdatedAt,
+	}
+
+	if customerReturn.Customer.ID != "" {
+		response.CustomerCode = customerReturn.Customer.Code
+		response.CustomerName = customerReturn.Customer.Name
+	}
+	if customerReturn.Delivery.ID != "" {
+		response.DeliveryNumber = customerReturn.Delivery.DeliveryNumber
+	}
+	if customerReturn.Invoice != nil {
+		response.InvoiceNumber = &customerReturn.Invoice.InvoiceNumber
+	}
+	if customerReturn.Warehouse.ID != "" {
+		response.Warehouse = &dto.WarehouseBasicResponse{
+			ID:   customerReturn.Warehouse.ID,
+			Code: customerReturn.Warehouse.Code,
+			Name: customerReturn.Warehouse.Name,
+		}
+	}
+	if customerReturn.CreditNote != nil {
+		response.CreditNoteNumber = &customerReturn.CreditNote.CreditNoteNumber
+	}
+
+	for _, item := range customerReturn.Items {
+		itemResponse := dto.CustomerReturnItemResponse{
+			ID:             item.ID,
+			DeliveryItemID: item.DeliveryItemID,
+			InvoiceItemID:  item.InvoiceItemID,
+			ProductID:      item.ProductID,
+			ProductUnitID:  item.ProductUnitID,
+			BatchID:        item.BatchID,
+			Quantity:       item.Quantity.String(),
+			BaseQuantity:   item.BaseQuantity.String(),
+			UnitPrice:      item.UnitPrice.String(),
+			Amount:         item.Amount.String(),
+			InspectionNote: item.InspectionNote,
+			ResultBatchID:  item.ResultBatchID,
+			Notes:          item.Notes,
+		}
+		if item.Disposition != nil {
+			disposition := string(*item.Disposition)
+			itemResponse.Disposition = &disposition
+		}
+		if item.Product.ID != "" {
+			itemResponse.Product = &dto.ProductBasicResponse{
+				ID:   item.Product.ID,
+				Code: item.Product.Code,
+				Name: item.Product.Name,
+			}
+		}
+		if item.Batch != nil {
+			itemResponse.BatchNumber = &item.Batch.BatchNumber
+		}
+		if item.ResultBatch != nil {
+			itemResponse.ResultBatchNumber = &item.ResultBatch.BatchNumber
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
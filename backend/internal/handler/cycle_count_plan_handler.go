@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/models"
+	"backend/pkg/errors"
+)
+
+// ============================================================================
+// CYCLE COUNT PLAN ENDPOINTS
+// ============================================================================
+
+// ListCycleCountPlans retrieves the cycle-count plans of the company
+// GET /api/v1/cycle-count-plans
+func (h *StockOpnameHandler) ListCycleCountPlans(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	plans, err := h.cycleCountService.ListPlans(c.Request.Context(), tenantID.(string), companyID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]*dto.CycleCountPlanResponse, len(plans))
+	for i := range plans {
+		responses[i] = h.mapCycleCountPlanToResponse(&plans[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    responses,
+	})
+}
+
+// GetCycleCountPlan retrieves a cycle-count plan by ID
+// GET /api/v1/cycle-count-plans/:id
+func (h *StockOpnameHandler) GetCycleCountPlan(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	plan, err := h.cycleCountService.GetPlan(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.mapCycleCountPlanToResponse(plan),
+	})
+}
+
+// CreateCycleCountPlan creates a cycle-count plan
+// POST /api/v1/cycle-count-plans
+func (h *StockOpnameHandler) CreateCycleCountPlan(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	// Get user ID from JWT middleware
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateCycleCountPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	plan, err := h.cycleCountService.CreatePlan(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    h.mapCycleCountPlanToResponse(plan),
+	})
+}
+
+// UpdateCycleCountPlan updates a cycle-count plan
+// PUT /api/v1/cycle-count-plans/:id
+func (h *StockOpnameHandler) UpdateCycleCountPlan(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var req dto.UpdateCycleCountPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	plan, err := h.cycleCountService.UpdatePlan(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.mapCycleCountPlanToResponse(plan),
+		"message": "Cycle count plan updated successfully",
+	})
+}
+
+// DeleteCycleCountPlan deletes a cycle-count plan; stock opnames it created are kept
+// DELETE /api/v1/cycle-count-plans/:id
+func (h *StockOpnameHandler) DeleteCycleCountPlan(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	if err := h.cycleCountService.DeletePlan(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cycle count plan deleted successfully",
+	})
+}
+
+// RunCycleCountPlan creates the plan's next DRAFT stock opname now
+// POST /api/v1/cycle-count-plans/:id/run
+func (h *StockOpnameHandler) RunCycleCountPlan(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	opname, plan, err := h.cycleCountService.RunPlan(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := &dto.RunCycleCountPlanResponse{
+		NextRunDate: plan.NextRunDate.Format("2006-01-02"),
+	}
+	message := "Nothing to count for this plan"
+	if opname != nil {
+		response.StockOpnameID = &opname.ID
+		response.OpnameNumber = opname.OpnameNumber
+		response.ItemCount = len(opname.Items)
+		message = "Cycle count stock opname created"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"message": message,
+	})
+}
+
+// mapCycleCountPlanToResponse converts a cycle-count plan model to response DTO
+func (h *StockOpnameHandler) mapCycleCountPlanToResponse(plan *models.CycleCountPlan) *dto.CycleCountPlanResponse {
+	response := &dto.CycleCountPlanResponse{
+		ID:            plan.ID,
+		Name:          plan.Name,
+		WarehouseID:   plan.WarehouseID,
+		Method:        string(plan.Method),
+		FrequencyDays: plan.FrequencyDays,
+		ClassADays:    plan.ClassADays,
+		ClassBDays:    plan.ClassBDays,
+		ClassCDays:    plan.ClassCDays,
+		CycleDays:     plan.CycleDays,
+		BlindCount:    plan.BlindCount,
+		IsActive:      plan.IsActive,
+		NextRunDate:   plan.NextRunDate.Format("2006-01-02"),
+		LastRunAt:     plan.LastRunAt,
+		Notes:         plan.Notes,
+		CreatedAt:     plan.CreatedAt,
+		UpdatedAt:     plan.UpdatedAt,
+	}
+	if plan.Warehouse.ID != "" {
+		response.WarehouseName = &plan.Warehouse.Name
+	}
+	return response
+}
@@ -186,7 +186,7 @@ func (h *DeliveryHandler) ListDeliveries(c *gin.Context) {
 // UpdateDeliveryStatus updates delivery status and related fields
 // PUT /api/v1/deliveries/:id/status
 func (h *DeliveryHandler) UpdateDeliveryStatus(c *gin.Context) {
-	companyID, tenantID, _, _, _, deliveryID, ok := h.getContextInfo(c)
+	companyID, tenantID, userID, _, _, deliveryID, ok := h.getContextInfo(c)
 	if !ok {
 		return
 	}
@@ -197,7 +197,7 @@ func (h *DeliveryHandler) UpdateDeliveryStatus(c *gin.Context) {
 		return
 	}
 
-	deliveryModel, err := h.deliveryService.UpdateDeliveryStatus(c.Request.Context(), companyID, tenantID, deliveryID, &req)
+	deliveryModel, err := h.deliveryService.UpdateDeliveryStatus(c.Request.Context(), companyID, tenantID, userID, deliveryID, &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -214,7 +214,7 @@ func (h *DeliveryHandler) UpdateDeliveryStatus(c *gin.Context) {
 // StartDelivery moves delivery from PREPARED to IN_TRANSIT
 // POST /api/v1/deliveries/:id/start
 func (h *DeliveryHandler) StartDelivery(c *gin.Context) {
-	companyID, tenantID, _, _, _, deliveryID, ok := h.getContextInfo(c)
+	companyID, tenantID, userID, _, _, deliveryID, ok := h.getContextInfo(c)
 	if !ok {
 		return
 	}
@@ -225,7 +225,7 @@ func (h *DeliveryHandler) StartDelivery(c *gin.Context) {
 		return
 	}
 
-	deliveryModel, err := h.deliveryService.StartDelivery(c.Request.Context(), companyID, tenantID, deliveryID, &req)
+	deliveryModel, err := h.deliveryService.StartDelivery(c.Request.Context(), companyID, tenantID, userID, deliveryID, &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -292,7 +292,7 @@ func (h *DeliveryHandler) ConfirmDelivery(c *gin.Context) {
 // CancelDelivery cancels a delivery
 // POST /api/v1/deliveries/:id/cancel
 func (h *DeliveryHandler) CancelDelivery(c *gin.Context) {
-	companyID, tenantID, _, _, _, deliveryID, ok := h.getContextInfo(c)
+	companyID, tenantID, userID, _, _, deliveryID, ok := h.getContextInfo(c)
 	if !ok {
 		return
 	}
@@ -303,7 +303,7 @@ func (h *DeliveryHandler) CancelDelivery(c *gin.Context) {
 		return
 	}
 
-	deliveryModel, err := h.deliveryService.CancelDelivery(c.Request.Context(), companyID, tenantID, deliveryID, &req)
+	deliveryModel, err := h.deliveryService.CancelDelivery(c.Request.Context(), companyID, tenantID, userID, deliveryID, &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -438,6 +438,7 @@ func (h *DeliveryHandler) mapDeliveryToResponse(delivery *models.Delivery) dto.D
 				ProductUnitId:    item.ProductUnitID,
 				BatchId:          item.BatchID,
 				Quantity:         item.Quantity.String(),
+				BaseQuantity:     item.BaseQuantity.String(),
 				Notes:            item.Notes,
 				CreatedAt:        item.CreatedAt,
 				UpdatedAt:        item.UpdatedAt,
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -418,7 +419,10 @@ func (h *GoodsReceiptHandler) AcceptGoods(c *gin.Context) {
 
 	// Parse optional request body
 	var req dto.AcceptGoodsRequest
-	c.ShouldBindJSON(&req)
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.handleValidationError(c, err)
+		return
+	}
 
 	// Get IP and User-Agent for audit
 	ipAddress := c.ClientIP()
@@ -445,6 +449,45 @@ func (h *GoodsReceiptHandler) AcceptGoods(c *gin.Context) {
 	})
 }
 
+// GetPutAwaySuggestions handles GET /api/v1/goods-receipts/:id/put-away-suggestions
+func (h *GoodsReceiptHandler) GetPutAwaySuggestions(c *gin.Context) {
+	// Get tenant ID from context
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	// Get company ID from context
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	// Get goods receipt ID from path
+	goodsReceiptID := c.Param("id")
+	if goodsReceiptID == "" {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Goods Receipt ID is required"))
+		return
+	}
+
+	suggestions, err := h.goodsReceiptService.GetPutAwaySuggestions(c.Request.Context(), tenantID.(string), companyID.(string), goodsReceiptID)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    suggestions,
+	})
+}
+
 // RejectGoods handles POST /api/v1/goods-receipts/:id/reject
 func (h *GoodsReceiptHandler) RejectGoods(c *gin.Context) {
 	// Get tenant ID from context
@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	pkgerrors "backend/pkg/errors"
+)
+
+// InventoryHandler - HTTP handlers for inventory reporting endpoints
+type InventoryHandler struct {
+	valuationService   *inventory.ValuationService
+	batchExpiryService *inventory.BatchExpiryService
+	stockCardService   *inventory.StockCardService
+	snapshotService    *inventory.StockSnapshotService
+}
+
+// NewInventoryHandler creates a new inventory handler instance
+func NewInventoryHandler(valuationService *inventory.ValuationService, batchExpiryService *inventory.BatchExpiryService, stockCardService *inventory.StockCardService, snapshotService *inventory.StockSnapshotService) *InventoryHandler {
+	return &InventoryHandler{
+		valuationService:   valuationService,
+		batchExpiryService: batchExpiryService,
+		stockCardService:   stockCardService,
+		snapshotService:    snapshotService,
+	}
+}
+
+// ============================================================================
+// INVENTORY VALUATION
+// ============================================================================
+
+// GetInventoryValuation handles GET /api/v1/inventory/valuation
+// Query: asOf (YYYY-MM-DD, defaults to today), warehouseID (optional)
+func (h *InventoryHandler) GetInventoryValuation(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.InventoryValuationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	valuation, err := h.valuationService.GetInventoryValuation(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    valuation,
+	})
+}
+
+// ============================================================================
+// FIFO COST LAYERS
+// ============================================================================
+
+// ListCostLayers handles GET /api/v1/inventory/cost-layers
+// Query: warehouseID, productID, includeConsumed (optional), page, pageSize
+func (h *InventoryHandler) ListCostLayers(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.CostLayerListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	response, err := h.valuationService.ListCostLayers(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ============================================================================
+// BATCH EXPIRY
+// ============================================================================
+
+// GetNearExpiryReport handles GET /api/v1/inventory/near-expiry
+// Query: warehouseID, productID (optional)
+// Buckets use the company's expiry alert horizons (default 30/60/90 days)
+func (h *InventoryHandler) GetNearExpiryReport(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.NearExpiryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	report, err := h.batchExpiryService.GetNearExpiryReport(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// ============================================================================
+// STOCK CARD (KARTU STOK)
+// ============================================================================
+
+// GetStockCard handles GET /api/v1/inventory/stock-card
+// Query: warehouseID, productID, dateFrom, dateTo, batchID, byBatch (optional), page, pageSize
+func (h *InventoryHandler) GetStockCard(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.StockCardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	response, err := h.stockCardService.GetStockCard(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportStockCard handles GET /api/v1/inventory/stock-card/export
+// Query: same as GetStockCard plus format (csv or pdf, defaults to csv); every movement in the period is exported
+func (h *InventoryHandler) ExportStockCard(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.StockCardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	card, err := h.stockCardService.GetStockCardForExport(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	contentType := "text/csv"
+	extension := "csv"
+	var content []byte
+	if query.Format == "pdf" {
+		contentType = "application/pdf"
+		extension = "pdf"
+		content, err = h.stockCardService.GenerateStockCardPDF(card)
+	} else {
+		content, err = h.stockCardService.GenerateStockCardCSV(card)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	filename := fmt.Sprintf("kartu-stok-%s-%s-%s_%s.%s", card.WarehouseCode, card.ProductCode, card.DateFrom, card.DateTo, extension)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// ============================================================================
+// POINT-IN-TIME STOCK (AS-OF) AND MONTH-END SNAPSHOTS
+// ============================================================================
+
+// GetStockAsOf handles GET /api/v1/inventory/stock-as-of
+// Query: asOf (YYYY-MM-DD for end of day, or RFC3339), warehouseID, productID (optional)
+func (h *InventoryHandler) GetStockAsOf(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.StockAsOfQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	response, err := h.snapshotService.GetStockAsOf(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// ListStockSnapshots handles GET /api/v1/inventory/snapshots
+func (h *InventoryHandler) ListStockSnapshots(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	snapshots, err := h.snapshotService.ListSnapshots(c.Request.Context(), tenantID.(string), companyID.(string))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    snapshots,
+	})
+}
+
+// CreateStockSnapshot handles POST /api/v1/inventory/snapshots
+// Stores a month-end snapshot on demand (the scheduler creates them automatically)
+func (h *InventoryHandler) CreateStockSnapshot(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var userID *string
+	if value, exists := c.Get("user_id"); exists && value != nil {
+		userIDStr := value.(string)
+		userID = &userIDStr
+	}
+
+	var req dto.CreateStockSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid request body"))
+		return
+	}
+
+	snapshot, err := h.snapshotService.CreateMonthEndSnapshot(c.Request.Context(), tenantID.(string), companyID.(string), req.Period, userID)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    inventory.MapStockSnapshotToResponse(snapshot),
+		"message": "Stock snapshot created",
+	})
+}
@@ -590,28 +590,117 @@ func (h *ProductHandler) DeleteProductSupplier(c *gin.Context) {
 	})
 }
 
+// GetProductBOM retrieves the bill of materials of a product
+// GET /api/v1/products/:id/bom
+func (h *ProductHandler) GetProductBOM(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	components, err := h.productService.GetProductBOM(c.Request.Context(), companyID.(string), tenantID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.mapBOMToResponse(components),
+	})
+}
+
+// SetProductBOM replaces the bill of materials of a product
+// PUT /api/v1/products/:id/bom
+func (h *ProductHandler) SetProductBOM(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var req dto.SetProductBOMRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	components, err := h.productService.SetProductBOM(c.Request.Context(), companyID.(string), tenantID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.mapBOMToResponse(components),
+		"message": "Bill of materials updated successfully",
+	})
+}
+
 // ============================================================================
 // MAPPER FUNCTIONS
 // ============================================================================
 
+// mapBOMToResponse converts bill of materials components to response DTOs
+func (h *ProductHandler) mapBOMToResponse(components []models.ProductBOMComponent) []dto.ProductBOMComponentResponse {
+	responses := make([]dto.ProductBOMComponentResponse, len(components))
+	for i, component := range components {
+		responses[i] = dto.ProductBOMComponentResponse{
+			ID:            component.ID,
+			ProductUnitID: component.ProductUnitID,
+			UnitName:      component.ComponentProduct.BaseUnit,
+			Quantity:      component.Quantity.String(),
+			BaseQuantity:  component.BaseQuantity.String(),
+			Notes:         component.Notes,
+		}
+		if component.ComponentProduct.ID != "" {
+			responses[i].ComponentProduct = &dto.ProductBasicResponse{
+				ID:   component.ComponentProduct.ID,
+				Code: component.ComponentProduct.Code,
+				Name: component.ComponentProduct.Name,
+			}
+		}
+		if component.ProductUnit != nil {
+			responses[i].UnitName = component.ProductUnit.UnitName
+		}
+	}
+	return responses
+}
+
 // mapProductToResponse converts product model to response DTO
 func (h *ProductHandler) mapProductToResponse(product *models.Product) *dto.ProductResponse {
 	response := &dto.ProductResponse{
-		ID:             product.ID,
-		Code:           product.Code,
-		Name:           product.Name,
-		Category:       product.Category,
-		BaseUnit:       product.BaseUnit,
-		BaseCost:       product.BaseCost.String(),
-		BasePrice:      product.BasePrice.String(),
-		MinimumStock:   product.MinimumStock.String(),
-		Description:    product.Description,
-		Barcode:        product.Barcode,
-		IsBatchTracked: product.IsBatchTracked,
-		IsPerishable:   product.IsPerishable,
-		IsActive:       product.IsActive,
-		CreatedAt:      product.CreatedAt,
-		UpdatedAt:      product.UpdatedAt,
+		ID:              product.ID,
+		Code:            product.Code,
+		Name:            product.Name,
+		Category:        product.Category,
+		BaseUnit:        product.BaseUnit,
+		BaseCost:        product.BaseCost.String(),
+		BasePrice:       product.BasePrice.String(),
+		MinimumStock:    product.MinimumStock.String(),
+		Description:     product.Description,
+		Barcode:         product.Barcode,
+		IsBatchTracked:  product.IsBatchTracked,
+		IsSerialTracked: product.IsSerialTracked,
+		HoldOnReceipt:   product.HoldOnReceipt,
+		IsPerishable:    product.IsPerishable,
+		IsActive:        product.IsActive,
+		CreatedAt:       product.CreatedAt,
+		UpdatedAt:       product.UpdatedAt,
 	}
 
 	// Map units - always initialize the array, even if empty
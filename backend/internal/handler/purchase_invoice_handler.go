@@ -140,6 +140,57 @@ func (h *PurchaseInvoiceHandler) GetPurchaseInvoice(c *gin.Context) {
 	})
 }
 
+// GetLandedCostAllocations handles GET /api/v1/purchase-invoices/:id/landed-costs
+func (h *PurchaseInvoiceHandler) GetLandedCostAllocations(c *gin.Context) {
+	// Get tenant and company context
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Tenant context not found",
+		})
+		return
+	}
+
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Company context not found",
+		})
+		return
+	}
+
+	invoiceID := c.Param("id")
+
+	allocations, err := h.service.GetLandedCostAllocations(c.Request.Context(), tenantID.(string), companyID.(string), invoiceID)
+	if err != nil {
+		if err.Error() == "purchase invoice not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Purchase invoice not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to retrieve landed cost allocations",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.LandedCostAllocationResponse, len(allocations))
+	for i := range allocations {
+		response[i] = convertToLandedCostResponse(&allocations[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
 // ============================================================================
 // CREATE Handler
 // ============================================================================
@@ -833,3 +884,24 @@ func convertToPaymentResponse(payment *models.PurchaseInvoicePayment) dto.Purcha
 		UpdatedAt:     payment.UpdatedAt,
 	}
 }
+
+// convertToLandedCostResponse converts landed cost allocation model to response DTO
+func convertToLandedCostResponse(allocation *models.LandedCostAllocation) dto.LandedCostAllocationResponse {
+	return dto.LandedCostAllocationResponse{
+		ID:                 allocation.ID,
+		GoodsReceiptID:     allocation.GoodsReceiptID,
+		GoodsReceiptItemID: allocation.GoodsReceiptItemID,
+		WarehouseID:        allocation.WarehouseID,
+		ProductID:          allocation.ProductID,
+		ProductCode:        allocation.Product.Code,
+		ProductName:        allocation.Product.Name,
+		AllocationMethod:   string(allocation.AllocationMethod),
+		Quantity:           allocation.Quantity.String(),
+		BasisValue:         allocation.BasisValue.String(),
+		AllocatedAmount:    allocation.AllocatedAmount.String(),
+		CapitalizedAmount:  allocation.CapitalizedAmount.String(),
+		ExpensedAmount:     allocation.ExpensedAmount.String(),
+		MovementID:         allocation.MovementID,
+		CreatedAt:          allocation.CreatedAt,
+	}
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/qualityhold"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// QualityHoldHandler - HTTP handlers for quality hold endpoints
+type QualityHoldHandler struct {
+	qualityHoldService *qualityhold.QualityHoldService
+}
+
+// NewQualityHoldHandler creates a new quality hold handler instance
+func NewQualityHoldHandler(qualityHoldService *qualityhold.QualityHoldService) *QualityHoldHandler {
+	return &QualityHoldHandler{
+		qualityHoldService: qualityHoldService,
+	}
+}
+
+// ============================================================================
+// QUALITY HOLD ENDPOINTS
+// ============================================================================
+
+// CreateQualityHold handles POST /api/v1/quality-holds
+// Puts an available batch in quarantine
+func (h *QualityHoldHandler) CreateQualityHold(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateQualityHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	hold, err := h.qualityHoldService.HoldBatch(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapQualityHoldToResponse(hold),
+	})
+}
+
+// ListQualityHolds handles GET /api/v1/quality-holds
+func (h *QualityHoldHandler) ListQualityHolds(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.QualityHoldQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	holds, pagination, err := h.qualityHoldService.ListQualityHolds(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.QualityHoldResponse, len(holds))
+	for i := range holds {
+		responses[i] = mapQualityHoldToResponse(&holds[i])
+	}
+
+	c.JSON(http.StatusOK, dto.QualityHoldListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetQualityHold handles GET /api/v1/quality-holds/:id
+func (h *QualityHoldHandler) GetQualityHold(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	hold, err := h.qualityHoldService.GetQualityHoldByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapQualityHoldToResponse(hold),
+	})
+}
+
+// ReleaseQualityHold handles POST /api/v1/quality-holds/:id/release
+// Returns held stock to available stock; the caller is recorded as approver
+func (h *QualityHoldHandler) ReleaseQualityHold(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, pkgerrors.NewAuthenticationError("User context not found"))
+		return
+	}
+
+	var req dto.ReleaseQualityHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	hold, err := h.qualityHoldService.ReleaseHold(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userID.(string), &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapQualityHoldToResponse(hold),
+	})
+}
+
+// ScrapQualityHold handles POST /api/v1/quality-holds/:id/scrap
+// Writes off all or part of the held stock; the caller is recorded as approver
+func (h *QualityHoldHandler) ScrapQualityHold(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, pkgerrors.NewAuthenticationError("User context not found"))
+		return
+	}
+
+	var req dto.ScrapQualityHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	hold, err := h.qualityHoldService.ScrapHold(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userID.(string), &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapQualityHoldToResponse(hold),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *QualityHoldHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapQualityHoldToResponse(hold *models.QualityHold) dto.QualityHoldResponse {
+	response := dto.QualityHoldResponse{
+		ID:                hold.ID,
+		HoldNumber:        hold.HoldNumber,
+		HoldDate:          hold.HoldDate.Format("2006-01-02"),
+		WarehouseID:       hold.WarehouseID,
+		ProductID:         hold.ProductID,
+		BatchID:           hold.BatchID,
+		Source:            string(hold.Source),
+		GoodsReceiptID:    hold.GoodsReceiptID,
+		CustomerReturnID:  hold.CustomerReturnID,
+		Status:            string(hold.Status),
+		HeldQuantity:      hold.HeldQuantity.String(),
+		ReleasedQuantity:  hold.ReleasedQuantity.String(),
+		ScrappedQuantity:  hold.ScrappedQuantity.String(),
+		RemainingQuantity: hold.RemainingQuantity().String(),
+		Reason:            hold.Reason,
+		Notes:             hold.Notes,
+		Actions:           make([]dto.QualityHoldActionResponse, 0, len(hold.Actions)),
+		ClosedAt:          hold.ClosedAt,
+		CreatedBy:         hold.CreatedBy,
+		CreatedAt:         hold.CreatedAt,
+		UpdatedAt:         hold.UpdatedAt,
+	}
+
+	if hold.Warehouse.ID != "" {
+		response.Warehouse = &dto.WarehouseBasicResponse{
+			ID:   hold.Warehouse.ID,
+			Code: hold.Warehouse.Code,
+			Name: hold.Warehouse.Name,
+		}
+	}
+	if hold.Product.ID != "" {
+		response.Product = &dto.ProductBasicResponse{
+			ID:   hold.Product.ID,
+			Code: hold.Product.Code,
+			Name: hold.Product.Name,
+		}
+	}
+	if hold.Batch.ID != "" {
+		response.BatchNumber = hold.Batch.BatchNumber
+		response.ExpiryDate = hold.Batch.ExpiryDate
+	}
+
+	for _, action := range hold.Actions {
+		actionResponse := dto.QualityHoldActionResponse{
+			ID:         action.ID,
+			Action:     string(action.Action),
+			Quantity:   action.Quantity.String(),
+			Reason:     action.Reason,
+			Notes:      action.Notes,
+			MovementID: action.MovementID,
+			ApprovedBy: action.ApprovedBy,
+			ApprovedAt: action.ApprovedAt,
+		}
+		if action.Approver != nil {
+			actionResponse.Approver = &dto.UserBasicResponse{
+				ID:       action.Approver.ID,
+				Email:    action.Approver.Email,
+				FullName: action.Approver.FullName,
+			}
+		}
+		response.Actions = append(response.Actions, actionResponse)
+	}
+
+	return response
+}
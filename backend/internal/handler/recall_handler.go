@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/recall"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// RecallHandler - HTTP handlers for product recall endpoints
+type RecallHandler struct {
+	recallService *recall.RecallService
+}
+
+// NewRecallHandler creates a new product recall handler instance
+func NewRecallHandler(recallService *recall.RecallService) *RecallHandler {
+	return &RecallHandler{
+		recallService: recallService,
+	}
+}
+
+// ============================================================================
+// PRODUCT RECALL ENDPOINTS
+// ============================================================================
+
+// CreateRecall handles POST /api/v1/recalls
+// Holds all stock of the lot, traces it to customers and opens RETURN deliveries
+func (h *RecallHandler) CreateRecall(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateProductRecallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	productRecall, err := h.recallService.CreateRecall(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapProductRecallToResponse(productRecall),
+	})
+}
+
+// ListRecalls handles GET /api/v1/recalls
+func (h *RecallHandler) ListRecalls(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.ProductRecallQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	recalls, pagination, err := h.recallService.ListRecalls(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.ProductRecallResponse, len(recalls))
+	for i := range recalls {
+		responses[i] = mapProductRecallToResponse(&recalls[i])
+	}
+
+	c.JSON(http.StatusOK, dto.ProductRecallListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetRecall handles GET /api/v1/recalls/:id
+func (h *RecallHandler) GetRecall(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	productRecall, err := h.recallService.GetRecallByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapProductRecallToResponse(productRecall),
+	})
+}
+
+// GetRecallReport handles GET /api/v1/recalls/:id/report
+// Stock on hold per warehouse, source goods receipts/suppliers and affected customers
+func (h *RecallHandler) GetRecallReport(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	productRecall, report, err := h.recallService.GetRecallReport(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+	report.Recall = mapProductRecallToResponse(productRecall)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// CloseRecall handles POST /api/v1/recalls/:id/close
+func (h *RecallHandler) CloseRecall(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	// Body is optional; reject it only when it is present but invalid
+	var req dto.CloseProductRecallRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	productRecall, err := h.recallService.CloseRecall(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapProductRecallToResponse(productRecall),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *RecallHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapProductRecallToResponse(productRecall *models.ProductRecall) dto.ProductRecallResponse {
+	response := dto.ProductRecallResponse{
+		ID:                productRecall.ID,
+		RecallNumber:      productRecall.RecallNumber,
+		RecallDate:        productRecall.RecallDate.Format("2006-01-02"),
+		ProductID:         productRecall.ProductID,
+		BatchNumber:       productRecall.BatchNumber,
+		SupplierID:        productRecall.SupplierID,
+		SupplierReference: productRecall.SupplierReference,
+		Reason:            productRecall.Reason,
+		Status:            string(productRecall.Status),
+		HeldQuantity:      productRecall.HeldQuantity.String(),
+		DeliveredQuantity: productRecall.DeliveredQuantity.String(),
+		BatchCount:        len(productRecall.Batches),
+		ClosedBy:          productRecall.ClosedBy,
+		ClosedAt:          productRecall.ClosedAt,
+		Notes:             productRecall.Notes,
+		CreatedBy:         productRecall.CreatedBy,
+		CreatedAt:         productRecall.CreatedAt,
+		UpdatedAt:         productRecall.UpdatedAt,
+	}
+
+	if productRecall.Product != nil {
+		response.Product = &dto.ProductBasicResponse{
+			ID:   productRecall.Product.ID,
+			Code: productRecall.Product.Code,
+			Name: productRecall.Product.Name,
+		}
+	}
+
+	customers := make(map[string]bool)
+	returns := make(map[string]bool)
+	for _, delivery := range productRecall.Deliveries {
+		customers[delivery.CustomerID] = true
+		if delivery.ReturnDeliveryID != nil {
+			returns[*delivery.ReturnDeliveryID] = true
+		}
+	}
+	response.CustomerCount = len(customers)
+	response.ReturnDeliveryCount = len(returns)
+
+	return response
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/purchase"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ReplenishmentHandler - HTTP handlers for replenishment (reorder suggestions) endpoints
+type ReplenishmentHandler struct {
+	replenishmentService *purchase.ReplenishmentService
+	purchaseOrderService *purchase.PurchaseOrderService
+}
+
+// NewReplenishmentHandler creates a new replenishment handler instance
+func NewReplenishmentHandler(replenishmentService *purchase.ReplenishmentService, purchaseOrderService *purchase.PurchaseOrderService) *ReplenishmentHandler {
+	return &ReplenishmentHandler{
+		replenishmentService: replenishmentService,
+		purchaseOrderService: purchaseOrderService,
+	}
+}
+
+// GetSuggestions handles GET /api/v1/replenishment/suggestions
+func (h *ReplenishmentHandler) GetSuggestions(c *gin.Context) {
+	// Get tenant ID from context
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	// Get company ID from context
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	var query dto.ReplenishmentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	response, err := h.replenishmentService.GetSuggestions(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// CreatePurchaseOrders handles POST /api/v1/replenishment/purchase-orders
+func (h *ReplenishmentHandler) CreatePurchaseOrders(c *gin.Context) {
+	// Get tenant ID from context
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	// Get company ID from context
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	// Get user ID from JWT middleware
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateReplenishmentOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	purchaseOrders, failures, err := h.replenishmentService.CreatePurchaseOrders(c.Request.Context(), tenantID.(string), companyID.(string), userIDStr, &req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	response := dto.CreateReplenishmentOrdersResponse{
+		PurchaseOrders: make([]dto.PurchaseOrderResponse, len(purchaseOrders)),
+		Errors:         failures,
+	}
+	for i, purchaseOrder := range purchaseOrders {
+		response.PurchaseOrders[i] = h.purchaseOrderService.MapToResponse(purchaseOrder, true)
+	}
+
+	message := "Draft purchase orders created"
+	if len(failures) > 0 {
+		message = fmt.Sprintf("%d of %d draft purchase orders created", len(purchaseOrders), len(purchaseOrders)+len(failures))
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    response,
+		"message": message,
+	})
+}
+
+// handleValidationError handles validation errors from request binding
+func (h *ReplenishmentHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
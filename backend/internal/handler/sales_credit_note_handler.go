@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/creditnote"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// SalesCreditNoteHandler - HTTP handlers for sales credit note endpoints
+type SalesCreditNoteHandler struct {
+	creditNoteService *creditnote.CreditNoteService
+}
+
+// NewSalesCreditNoteHandler creates a new sales credit note handler instance
+func NewSalesCreditNoteHandler(creditNoteService *creditnote.CreditNoteService) *SalesCreditNoteHandler {
+	return &SalesCreditNoteHandler{
+		creditNoteService: creditNoteService,
+	}
+}
+
+// ============================================================================
+// SALES CREDIT NOTE ENDPOINTS
+// ============================================================================
+
+// CreateCreditNote handles POST /api/v1/credit-notes
+// Credits part of an invoice (price correction, short shipment); returns are credited by completing a customer return
+func (h *SalesCreditNoteHandler) CreateCreditNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateSalesCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	creditNote, err := h.creditNoteService.CreateCreditNote(c.Request.Context(), tenantID.(string), companyID.(string), &req, userIDStr)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapSalesCreditNoteToResponse(creditNote),
+	})
+}
+
+// ListCreditNotes handles GET /api/v1/credit-notes
+func (h *SalesCreditNoteHandler) ListCreditNotes(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.SalesCreditNoteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	creditNotes, pagination, err := h.creditNoteService.ListCreditNotes(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.SalesCreditNoteResponse, len(creditNotes))
+	for i := range creditNotes {
+		responses[i] = mapSalesCreditNoteToResponse(&creditNotes[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SalesCreditNoteListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetCreditNote handles GET /api/v1/credit-notes/:id
+func (h *SalesCreditNoteHandler) GetCreditNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	creditNote, err := h.creditNoteService.GetCreditNoteByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapSalesCreditNoteToResponse(creditNote),
+	})
+}
+
+// ApplyCreditNote handles POST /api/v1/credit-notes/:id/apply
+// Uses unapplied credit against another open invoice of the customer
+func (h *SalesCreditNoteHandler) ApplyCreditNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.ApplySalesCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	creditNote, err := h.creditNoteService.ApplyCreditNote(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &req, userIDStr)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapSalesCreditNoteToResponse(creditNote),
+	})
+}
+
+// RefundCreditNote handles POST /api/v1/credit-notes/:id/refund
+// Pays unapplied credit back to the customer
+func (h *SalesCreditNoteHandler) RefundCreditNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.RefundSalesCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	creditNote, err := h.creditNoteService.RefundCreditNote(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &req, userIDStr)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapSalesCreditNoteToResponse(creditNote),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *SalesCreditNoteHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapSalesCreditNoteToResponse(creditNote *models.SalesCreditNote) dto.SalesCreditNoteResponse {
+	response := dto.SalesCreditNoteResponse{
+		ID:               creditNote.ID,
+		CreditNoteNumber: creditNote.CreditNoteNumber,
+		CreditNoteDate:   creditNote.CreditNoteDate.Format("2006-01-02"),
+		CustomerID:       creditNote.CustomerID,
+		InvoiceID:        creditNote.InvoiceID,
+		CustomerReturnID: creditNote.CustomerReturnID,
+		Category:         string(creditNote.Category),
+		Reason:           creditNote.Reason,
+		Subtotal:         creditNote.Subtotal.String(),
+		DiscountAmount:   creditNote.DiscountAmount.String(),
+		TaxAmount:        creditNote.TaxAmount.String(),
+		TotalAmount:      creditNote.TotalAmount.String(),
+		AppliedAmount:    creditNote.AppliedAmount.String(),
+		RefundedAmount:   creditNote.RefundedAmount.String(),
+		UnappliedAmount:  creditNote.UnappliedAmount().String(),
+		Status:           string(creditNote.Status),
+		Notes:            creditNote.Notes,
+		Items:            make([]dto.SalesCreditNoteItemResponse, 0, len(creditNote.Items)),
+		Applications:     make([]dto.SalesCreditNoteApplicationResponse, 0, len(creditNote.Applications)),
+		Refunds:          make([]dto.SalesCreditNoteRefundResponse, 0, len(creditNote.Refunds)),
+		CreatedBy:        creditNote.CreatedBy,
+		CreatedAt:        creditNote.CreatedAt,
+		UpdatedAt:        creditNote.UpdatedAt,
+	}
+
+	if creditNote.Customer.ID != "" {
+		response.CustomerCode = creditNote.Customer.Code
+		response.CustomerName = creditNote.Customer.Name
+	}
+	if creditNote.Invoice != nil {
+		response.InvoiceNumber = &creditNote.Invoice.InvoiceNumber
+	}
+
+	for _, item := range creditNote.Items {
+		itemResponse := dto.SalesCreditNoteItemResponse{
+			ID:                   item.ID,
+			InvoiceItemID:        item.InvoiceItemID,
+			CustomerReturnItemID: item.CustomerReturnItemID,
+			ProductID:            item.ProductID,
+			Description:          item.Description,
+			Quantity:             item.Quantity.String(),
+			UnitPrice:            item.UnitPrice.String(),
+			Subtotal:             item.Subtotal.String(),
+		}
+		if item.Product != nil {
+			itemResponse.Product = &dto.ProductBasicResponse{
+				ID:   item.Product.ID,
+				Code: item.Product.Code,
+				Name: item.Product.Name,
+			}
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	for _, application := range creditNote.Applications {
+		response.Applications = append(response.Applications, dto.SalesCreditNoteApplicationResponse{
+			ID:            application.ID,
+			InvoiceID:     application.InvoiceID,
+			InvoiceNumber: application.Invoice.InvoiceNumber,
+			Amount:        application.Amount.String(),
+			AppliedAt:     application.AppliedAt,
+			AppliedBy:     application.AppliedBy,
+		})
+	}
+
+	for _, refund := range creditNote.Refunds {
+		response.Refunds = append(response.Refunds, dto.SalesCreditNoteRefundResponse{
+			ID:            refund.ID,
+			RefundDate:    refund.RefundDate.Format("2006-01-02"),
+			Amount:        refund.Amount.String(),
+			PaymentMethod: string(refund.PaymentMethod),
+			Reference:     refund.Reference,
+			BankAccountID: refund.BankAccountID,
+			Notes:         refund.Notes,
+			CreatedBy:     refund.CreatedBy,
+			CreatedAt:     refund.CreatedAt,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"backend/internal/dto"
+	"backend/internal/service/debitnote"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// SalesDebitNoteHandler - HTTP handlers for sales debit note endpoints
+type SalesDebitNoteHandler struct {
+	debitNoteService *debitnote.DebitNoteService
+}
+
+// NewSalesDebitNoteHandler creates a new sales debit note handler instance
+func NewSalesDebitNoteHandler(debitNoteService *debitnote.DebitNoteService) *SalesDebitNoteHandler {
+	return &SalesDebitNoteHandler{
+		debitNoteService: debitNoteService,
+	}
+}
+
+// ============================================================================
+// SALES DEBIT NOTE ENDPOINTS
+// ============================================================================
+
+// CreateDebitNote handles POST /api/v1/debit-notes
+// Charges an additional amount on an invoice (price correction, extra charges)
+func (h *SalesDebitNoteHandler) CreateDebitNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	var req dto.CreateSalesDebitNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	debitNote, err := h.debitNoteService.CreateDebitNote(c.Request.Context(), tenantID.(string), companyID.(string), &req, userIDStr)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapSalesDebitNoteToResponse(debitNote),
+	})
+}
+
+// ListDebitNotes handles GET /api/v1/debit-notes
+func (h *SalesDebitNoteHandler) ListDebitNotes(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.SalesDebitNoteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	debitNotes, pagination, err := h.debitNoteService.ListDebitNotes(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.SalesDebitNoteResponse, len(debitNotes))
+	for i := range debitNotes {
+		responses[i] = mapSalesDebitNoteToResponse(&debitNotes[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SalesDebitNoteListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetDebitNote handles GET /api/v1/debit-notes/:id
+func (h *SalesDebitNoteHandler) GetDebitNote(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	debitNote, err := h.debitNoteService.GetDebitNoteByID(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapSalesDebitNoteToResponse(debitNote),
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func (h *SalesDebitNoteHandler) handleValidationError(c *gin.Context, err error) {
+	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := make([]pkgerrors.ValidationError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			formattedErrors = append(formattedErrors, pkgerrors.ValidationError{
+				Field:   fieldErr.Field(),
+				Message: fieldErr.Error(),
+			})
+		}
+		c.JSON(http.StatusBadRequest, pkgerrors.NewValidationError(formattedErrors))
+		return
+	}
+	c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError(err.Error()))
+}
+
+func mapSalesDebitNoteToResponse(debitNote *models.SalesDebitNote) dto.SalesDebitNoteResponse {
+	response := dto.SalesDebitNoteResponse{
+		ID:              debitNote.ID,
+		DebitNoteNumber: debitNote.DebitNoteNumber,
+		DebitNoteDate:   debitNote.DebitNoteDate.Format("2006-01-02"),
+		CustomerID:      debitNote.CustomerID,
+		InvoiceID:       debitNote.InvoiceID,
+		Category:        string(debitNote.Category),
+		Reason:          debitNote.Reason,
+		Subtotal:        debitNote.Subtotal.String(),
+		DiscountAmount:  debitNote.DiscountAmount.String(),
+		TaxAmount:       debitNote.TaxAmount.String(),
+		TotalAmount:     debitNote.TotalAmount.String(),
+		Notes:           debitNote.Notes,
+		Items:           make([]dto.SalesDebitNoteItemResponse, 0, len(debitNote.Items)),
+		CreatedBy:       debitNote.CreatedBy,
+		CreatedAt:       debitNote.CreatedAt,
+		UpdatedAt:       debitNote.UpdatedAt,
+	}
+
+	if debitNote.Customer.ID != "" {
+		response.CustomerCode = debitNote.Customer.Code
+		response.CustomerName = debitNote.Customer.Name
+	}
+	if debitNote.Invoice.ID != "" {
+		response.InvoiceNumber = debitNote.Invoice.InvoiceNumber
+	}
+
+	for _, item := range debitNote.Items {
+		itemResponse := dto.SalesDebitNoteItemResponse{
+			ID:            item.ID,
+			InvoiceItemID: item.InvoiceItemID,
+			ProductID:     item.ProductID,
+			Description:   item.Description,
+			Quantity:      item.Quantity.String(),
+			UnitPrice:     item.UnitPrice.String(),
+			Subtotal:      item.Subtotal.String(),
+		}
+		if item.Product != nil {
+			itemResponse.Product = &dto.ProductBasicResponse{
+				ID:   item.Product.ID,
+				Code: item.Product.Code,
+				Name: item.Product.Name,
+			}
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
@@ -455,6 +455,7 @@ func (h *SalesOrderHandler) mapSalesOrderToResponse(so *models.SalesOrder) dto.S
 				Id:         item.ID,
 				ProductId:  item.ProductID,
 				OrderedQty: item.Quantity.String(),
+				BaseQty:    item.BaseQuantity.String(),
 				UnitPrice:  item.UnitPrice.String(),
 				Discount:   item.DiscountAmt.String(),
 				LineTotal:  item.Subtotal.String(),
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/internal/service/serial"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// SerialHandler - HTTP handlers for serial number lookup and history endpoints
+type SerialHandler struct {
+	serialService *serial.SerialService
+}
+
+// NewSerialHandler creates a new serial handler instance
+func NewSerialHandler(serialService *serial.SerialService) *SerialHandler {
+	return &SerialHandler{
+		serialService: serialService,
+	}
+}
+
+// ListSerials handles GET /api/v1/serials
+func (h *SerialHandler) ListSerials(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.SerialListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	serials, pagination, err := h.serialService.ListSerials(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.SerialResponse, len(serials))
+	for i := range serials {
+		responses[i] = mapSerialToResponse(&serials[i])
+	}
+
+	c.JSON(http.StatusOK, dto.SerialListResponse{
+		Success:    true,
+		Data:       responses,
+		Pagination: *pagination,
+	})
+}
+
+// GetSerialHistory handles GET /api/v1/serials/:id/history
+// Path of the unit from supplier to customer, with the warranty start date of its delivery
+func (h *SerialHandler) GetSerialHistory(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	productSerial, events, err := h.serialService.GetSerialHistory(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	response := dto.SerialHistoryResponse{
+		Serial: mapSerialToResponse(productSerial),
+		Events: make([]dto.SerialEventResponse, len(events)),
+	}
+	for i, event := range events {
+		eventResponse := dto.SerialEventResponse{
+			ID:              event.ID,
+			EventType:       string(event.EventType),
+			EventDate:       event.EventDate,
+			Status:          string(event.Status),
+			WarehouseID:     event.WarehouseID,
+			CustomerID:      event.CustomerID,
+			SupplierID:      event.SupplierID,
+			ReferenceType:   event.ReferenceType,
+			ReferenceID:     event.ReferenceID,
+			ReferenceNumber: event.ReferenceNumber,
+			Notes:           event.Notes,
+			CreatedBy:       event.CreatedBy,
+		}
+		if event.Warehouse != nil {
+			eventResponse.WarehouseName = &event.Warehouse.Name
+		}
+		if event.Customer != nil {
+			eventResponse.CustomerName = &event.Customer.Name
+		}
+		if event.Supplier != nil {
+			eventResponse.SupplierName = &event.Supplier.Name
+		}
+		response.Events[i] = eventResponse
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+func mapSerialToResponse(productSerial *models.ProductSerial) dto.SerialResponse {
+	response := dto.SerialResponse{
+		ID:             productSerial.ID,
+		SerialNumber:   productSerial.SerialNumber,
+		Status:         string(productSerial.Status),
+		WarehouseID:    productSerial.WarehouseID,
+		BatchID:        productSerial.BatchID,
+		SupplierID:     productSerial.SupplierID,
+		GoodsReceiptID: productSerial.GoodsReceiptID,
+		ReceivedAt:     productSerial.ReceivedAt,
+		CustomerID:     productSerial.CustomerID,
+		DeliveryID:     productSerial.DeliveryID,
+	}
+
+	if productSerial.Product.ID != "" {
+		response.Product = &dto.ProductBasicResponse{
+			ID:   productSerial.Product.ID,
+			Code: productSerial.Product.Code,
+			Name: productSerial.Product.Name,
+		}
+	}
+	if productSerial.Warehouse != nil {
+		response.WarehouseName = &productSerial.Warehouse.Name
+	}
+	if productSerial.Batch != nil {
+		response.BatchNumber = &productSerial.Batch.BatchNumber
+	}
+	if productSerial.Supplier != nil {
+		response.SupplierName = &productSerial.Supplier.Name
+	}
+	if productSerial.GoodsReceipt != nil {
+		response.GRNNumber = &productSerial.GoodsReceipt.GRNNumber
+	}
+	if productSerial.Customer != nil {
+		response.CustomerName = &productSerial.Customer.Name
+	}
+	if productSerial.Delivery != nil {
+		response.DeliveryNumber = &productSerial.Delivery.DeliveryNumber
+	}
+	if productSerial.WarrantyStartDate != nil {
+		warrantyStart := productSerial.WarrantyStartDate.Format("2006-01-02")
+		response.WarrantyStartDate = &warrantyStart
+	}
+
+	return response
+}
@@ -14,15 +14,20 @@ import (
 	"backend/pkg/errors"
 )
 
+// maxCountImportSize limits uploaded count files
+const maxCountImportSize = 5 << 20
+
 // StockOpnameHandler handles HTTP requests for stock opname management
 type StockOpnameHandler struct {
 	stockOpnameService *stockopname.StockOpnameService
+	cycleCountService  *stockopname.CycleCountService
 }
 
 // NewStockOpnameHandler creates a new stock opname handler
-func NewStockOpnameHandler(stockOpnameService *stockopname.StockOpnameService) *StockOpnameHandler {
+func NewStockOpnameHandler(stockOpnameService *stockopname.StockOpnameService, cycleCountService *stockopname.CycleCountService) *StockOpnameHandler {
 	return &StockOpnameHandler{
 		stockOpnameService: stockOpnameService,
+		cycleCountService:  cycleCountService,
 	}
 }
 
@@ -365,7 +370,7 @@ func (h *StockOpnameHandler) AddStockOpnameItem(c *gin.Context) {
 	}
 
 	// Map to response
-	response := h.mapStockOpnameItemToResponse(item)
+	response := h.mapStockOpnameItemToResponse(item, item.StockOpname.HidesSystemQty())
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
@@ -425,7 +430,7 @@ func (h *StockOpnameHandler) UpdateStockOpnameItem(c *gin.Context) {
 	}
 
 	// Map to response
-	response := h.mapStockOpnameItemToResponse(item)
+	response := h.mapStockOpnameItemToResponse(item, item.StockOpname.HidesSystemQty())
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -481,7 +486,7 @@ func (h *StockOpnameHandler) BatchUpdateStockOpnameItems(c *gin.Context) {
 	// Map to response
 	var responses []*dto.StockOpnameItemResponse
 	for _, item := range items {
-		responses = append(responses, h.mapStockOpnameItemToResponse(&item))
+		responses = append(responses, h.mapStockOpnameItemToResponse(&item, item.StockOpname.HidesSystemQty()))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -566,6 +571,112 @@ func (h *StockOpnameHandler) ImportWarehouseProducts(c *gin.Context) {
 	})
 }
 
+// GetCountSheet downloads the printable count sheet of a stock opname as PDF
+// GET /api/v1/stock-opnames/:id/count-sheet
+func (h *StockOpnameHandler) GetCountSheet(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	opnameID := c.Param("id")
+	if opnameID == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Stock Opname ID is required"))
+		return
+	}
+
+	opnameModel, err := h.stockOpnameService.GetStockOpname(c.Request.Context(), companyID.(string), tenantID.(string), opnameID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	pdfBytes, err := h.stockOpnameService.GenerateCountSheetPDF(c.Request.Context(), tenantID.(string), opnameModel)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Set headers for PDF download
+	filename := fmt.Sprintf("Lembar_Hitung_%s.pdf", opnameModel.OpnameNumber)
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// ImportCounts applies counted quantities from an uploaded CSV or handheld file (multipart field "file")
+// POST /api/v1/stock-opnames/:id/import-counts
+func (h *StockOpnameHandler) ImportCounts(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Company context not found."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	// Get user ID from JWT middleware
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	opnameID := c.Param("id")
+	if opnameID == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("Stock Opname ID is required"))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   errors.NewBadRequestError("CSV file is required (form field \"file\")"),
+		})
+		return
+	}
+	if fileHeader.Size > maxCountImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   errors.NewBadRequestError("CSV file must not exceed 5 MB"),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.stockOpnameService.ImportCountsCSV(c.Request.Context(), companyID.(string), tenantID.(string), opnameID, userIDStr, file, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": fmt.Sprintf("%d of %d rows imported", result.ImportedRows, result.TotalRows),
+	})
+}
+
 // ============================================================================
 // MAPPER FUNCTIONS
 // ============================================================================
@@ -585,6 +696,13 @@ func (h *StockOpnameHandler) mapStockOpnameToResponse(opname *models.StockOpname
 		UpdatedAt:    opname.UpdatedAt,
 	}
 
+	// Blind counts keep system quantities from counters until COMPLETED
+	hideSystemQty := opname.HidesSystemQty()
+	response.BlindCount = opname.IsBlindCount
+	response.SystemQtyHidden = hideSystemQty
+	response.CycleCountPlanID = opname.CycleCountPlanID
+	response.FreezeScope = string(opname.FreezeScope)
+
 	// Map warehouse name if available
 	if opname.Warehouse.Name != "" {
 		response.WarehouseName = &opname.Warehouse.Name
@@ -617,7 +735,7 @@ func (h *StockOpnameHandler) mapStockOpnameToResponse(opname *models.StockOpname
 		// Map items
 		response.Items = make([]dto.StockOpnameItemResponse, 0, len(opname.Items))
 		for _, item := range opname.Items {
-			response.Items = append(response.Items, *h.mapStockOpnameItemToResponse(&item))
+			response.Items = append(response.Items, *h.mapStockOpnameItemToResponse(&item, hideSystemQty))
 		}
 	} else {
 		response.TotalItems = 0
@@ -626,21 +744,42 @@ func (h *StockOpnameHandler) mapStockOpnameToResponse(opname *models.StockOpname
 		response.TotalDifference = "0"
 	}
 
+	if hideSystemQty {
+		response.TotalExpectedQty = ""
+		response.TotalDifference = ""
+	}
+
 	return response
 }
 
 // mapStockOpnameItemToResponse converts stock opname item model to response DTO
-func (h *StockOpnameHandler) mapStockOpnameItemToResponse(item *models.StockOpnameItem) *dto.StockOpnameItemResponse {
+// hideSystemQty omits the expected quantity and difference (blind count not yet COMPLETED)
+func (h *StockOpnameHandler) mapStockOpnameItemToResponse(item *models.StockOpnameItem, hideSystemQty bool) *dto.StockOpnameItemResponse {
 	response := &dto.StockOpnameItemResponse{
 		ID:          item.ID,
 		OpnameID:    item.StockOpnameID,
 		ProductID:   item.ProductID,
+		BatchID:     item.BatchID,
+		BinID:       item.BinID,
 		ExpectedQty: item.SystemQty.String(),
 		ActualQty:   item.PhysicalQty.String(),
 		Difference:  item.DifferenceQty.String(),
+		CountedAt:   item.CountedAt,
 		Notes:       item.Notes,
 	}
 
+	if hideSystemQty {
+		response.ExpectedQty = ""
+		response.Difference = ""
+	}
+
+	if item.Batch != nil {
+		response.BatchNumber = &item.Batch.BatchNumber
+	}
+	if item.Bin != nil {
+		response.BinCode = &item.Bin.Code
+	}
+
 	// Map product info if preloaded
 	if item.Product.Code != "" {
 		response.ProductCode = &item.Product.Code
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	pkgerrors "backend/pkg/errors"
+)
+
+// StockReconciliationHandler - HTTP handlers for checking and repairing stock ledger drift
+type StockReconciliationHandler struct {
+	reconciliationService *inventory.StockReconciliationService
+}
+
+// NewStockReconciliationHandler creates a new stock reconciliation handler instance
+func NewStockReconciliationHandler(reconciliationService *inventory.StockReconciliationService) *StockReconciliationHandler {
+	return &StockReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// GetReconciliation handles GET /api/v1/inventory/reconciliation
+// Compares warehouse stock, batch totals and Product.CurrentStock with the movement history
+func (h *StockReconciliationHandler) GetReconciliation(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.StockReconciliationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	report, err := h.reconciliationService.Reconcile(c.Request.Context(), tenantID.(string), companyID.(string), &inventory.ReconciliationOptions{
+		WarehouseID: query.WarehouseID,
+		ProductID:   query.ProductID,
+	})
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// RepairReconciliation handles POST /api/v1/inventory/reconciliation/repair
+// Posts correcting ADJUSTMENT movements (dryRun: only report them)
+func (h *StockReconciliationHandler) RepairReconciliation(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var userID *string
+	if value, exists := c.Get("user_id"); exists && value != nil {
+		userIDStr := value.(string)
+		userID = &userIDStr
+	}
+
+	var req dto.RepairStockReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid request body"))
+		return
+	}
+
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+	report, err := h.reconciliationService.Reconcile(c.Request.Context(), tenantID.(string), companyID.(string), &inventory.ReconciliationOptions{
+		WarehouseID: req.WarehouseID,
+		ProductID:   req.ProductID,
+		Repair:      true,
+		DryRun:      req.DryRun,
+		Notes:       req.Notes,
+		UserID:      userID,
+		IPAddress:   &ipAddress,
+		UserAgent:   &userAgent,
+	})
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
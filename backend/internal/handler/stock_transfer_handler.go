@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"log"
 	"net/http"
 
@@ -415,8 +416,12 @@ func (h *StockTransferHandler) ReceiveStockTransfer(c *gin.Context) {
 		return
 	}
 
+	// Body is optional (full receipt); reject it only when it is present but invalid
 	var req dto.ReceiveTransferRequest
-	_ = c.ShouldBindJSON(&req)
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.handleValidationError(c, err)
+		return
+	}
 
 	transfer, err := h.stockTransferService.ReceiveStockTransfer(
 		c.Request.Context(),
@@ -562,13 +567,27 @@ func mapStockTransferToResponse(transfer *models.StockTransfer) dto.StockTransfe
 		response.Items = make([]dto.StockTransferItemResponse, len(transfer.Items))
 		for i, item := range transfer.Items {
 			response.Items[i] = dto.StockTransferItemResponse{
-				ID:        item.ID,
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity.String(),
-				BatchID:   item.BatchID,
-				Notes:     item.Notes,
-				CreatedAt: item.CreatedAt,
-				UpdatedAt: item.UpdatedAt,
+				ID:                  item.ID,
+				ProductID:           item.ProductID,
+				Quantity:            item.Quantity.String(),
+				BatchID:             item.BatchID,
+				DestBatchID:         item.DestBatchID,
+				DiscrepancyQuantity: item.DiscrepancyQuantity.String(),
+				Notes:               item.Notes,
+				CreatedAt:           item.CreatedAt,
+				UpdatedAt:           item.UpdatedAt,
+			}
+			if item.Batch != nil {
+				response.Items[i].BatchNumber = &item.Batch.BatchNumber
+				response.Items[i].ExpiryDate = item.Batch.ExpiryDate
+			}
+			if item.ReceivedQuantity != nil {
+				receivedQty := item.ReceivedQuantity.String()
+				response.Items[i].ReceivedQuantity = &receivedQty
+			}
+			if item.DiscrepancyReason != nil {
+				reason := string(*item.DiscrepancyReason)
+				response.Items[i].DiscrepancyReason = &reason
 			}
 
 			// Map product if available
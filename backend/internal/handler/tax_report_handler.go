@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/internal/service/taxreport"
+	pkgerrors "backend/pkg/errors"
+)
+
+// TaxReportHandler - HTTP handlers for tax report endpoints
+type TaxReportHandler struct {
+	taxReportService *taxreport.TaxReportService
+}
+
+// NewTaxReportHandler creates a new tax report handler instance
+func NewTaxReportHandler(taxReportService *taxreport.TaxReportService) *TaxReportHandler {
+	return &TaxReportHandler{
+		taxReportService: taxReportService,
+	}
+}
+
+// GetSalesTaxReport handles GET /api/v1/tax-reports/sales
+// Query: dateFrom, dateTo (YYYY-MM-DD, default this month to date), customerID
+// Lists output tax of invoices and debit notes, less credit notes, dated in the period
+func (h *TaxReportHandler) GetSalesTaxReport(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found"))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found"))
+		return
+	}
+
+	var query dto.SalesTaxReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Invalid query parameters"))
+		return
+	}
+
+	response, err := h.taxReportService.GetSalesTaxReport(c.Request.Context(), tenantID.(string), companyID.(string), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
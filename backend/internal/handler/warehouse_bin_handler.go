@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ============================================================================
+// WAREHOUSE BIN ENDPOINTS
+// ============================================================================
+
+// ListWarehouseBins handles GET /api/v1/warehouses/:id/bins
+// @Summary List the bin locations of a warehouse
+// @Tags Warehouses
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {array} dto.WarehouseBinResponse
+// @Failure 404 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bins [get]
+// @Security BearerAuth
+func (h *WarehouseHandler) ListWarehouseBins(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var query dto.WarehouseBinListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	bins, err := h.warehouseService.ListWarehouseBins(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	responses := make([]dto.WarehouseBinResponse, len(bins))
+	for i := range bins {
+		responses[i] = mapWarehouseBinToResponse(&bins[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    responses,
+	})
+}
+
+// CreateWarehouseBin handles POST /api/v1/warehouses/:id/bins
+// @Summary Create a bin location in a warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param request body dto.CreateWarehouseBinRequest true "Bin creation request"
+// @Success 201 {object} dto.WarehouseBinResponse
+// @Failure 400 {object} pkgerrors.ErrorResponse
+// @Failure 409 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bins [post]
+// @Security BearerAuth
+func (h *WarehouseHandler) CreateWarehouseBin(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var req dto.CreateWarehouseBinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	bin, err := h.warehouseService.CreateWarehouseBin(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    mapWarehouseBinToResponse(bin),
+	})
+}
+
+// UpdateWarehouseBin handles PUT /api/v1/warehouses/:id/bins/:binId
+// @Summary Update a bin location
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param binId path string true "Bin ID"
+// @Param request body dto.UpdateWarehouseBinRequest true "Bin update request"
+// @Success 200 {object} dto.WarehouseBinResponse
+// @Failure 400 {object} pkgerrors.ErrorResponse
+// @Failure 404 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bins/{binId} [put]
+// @Security BearerAuth
+func (h *WarehouseHandler) UpdateWarehouseBin(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var req dto.UpdateWarehouseBinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	bin, err := h.warehouseService.UpdateWarehouseBin(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), c.Param("binId"), &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapWarehouseBinToResponse(bin),
+	})
+}
+
+// DeleteWarehouseBin handles DELETE /api/v1/warehouses/:id/bins/:binId
+// @Summary Deactivate an empty bin location
+// @Tags Warehouses
+// @Param id path string true "Warehouse ID"
+// @Param binId path string true "Bin ID"
+// @Success 204
+// @Failure 400 {object} pkgerrors.ErrorResponse
+// @Failure 404 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bins/{binId} [delete]
+// @Security BearerAuth
+func (h *WarehouseHandler) DeleteWarehouseBin(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	err := h.warehouseService.DeleteWarehouseBin(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), c.Param("binId"))
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListBinStocks handles GET /api/v1/warehouses/:id/bin-stocks
+// @Summary List the stock held in the bins of a warehouse
+// @Tags Warehouses
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} dto.BinStockListResponse
+// @Failure 404 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bin-stocks [get]
+// @Security BearerAuth
+func (h *WarehouseHandler) ListBinStocks(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var query dto.BinStockListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	response, err := h.warehouseService.ListBinStocks(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), &query)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MoveBinStock handles POST /api/v1/warehouses/:id/bin-moves
+// @Summary Move stock between bins of a warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param request body dto.MoveBinStockRequest true "Bin move request"
+// @Success 201 {object} dto.BinMoveResponse
+// @Failure 400 {object} pkgerrors.ErrorResponse
+// @Failure 404 {object} pkgerrors.ErrorResponse
+// @Router /api/v1/warehouses/{id}/bin-moves [post]
+// @Security BearerAuth
+func (h *WarehouseHandler) MoveBinStock(c *gin.Context) {
+	companyID, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Company context not found. Please set X-Company-ID header."))
+		return
+	}
+
+	tenantID, exists := c.Get("tenant_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, pkgerrors.NewBadRequestError("Tenant context not found."))
+		return
+	}
+
+	var req dto.MoveBinStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDStr := ""
+	if userID != nil {
+		userIDStr = userID.(string)
+	}
+
+	response, err := h.warehouseService.MoveBinStock(c.Request.Context(), tenantID.(string), companyID.(string), c.Param("id"), userIDStr, &req)
+	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			c.JSON(appErr.StatusCode, appErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, pkgerrors.NewInternalError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// mapWarehouseBinToResponse maps a WarehouseBin model to its response DTO
+func mapWarehouseBinToResponse(bin *models.WarehouseBin) dto.WarehouseBinResponse {
+	return dto.WarehouseBinResponse{
+		ID:          bin.ID,
+		WarehouseID: bin.WarehouseID,
+		Code:        bin.Code,
+		Zone:        bin.Zone,
+		Aisle:       bin.Aisle,
+		Rack:        bin.Rack,
+		Level:       bin.Level,
+		IsDefault:   bin.IsDefault,
+		IsActive:    bin.IsActive,
+		Notes:       bin.Notes,
+		CreatedAt:   bin.CreatedAt,
+		UpdatedAt:   bin.UpdatedAt,
+	}
+}
@@ -615,6 +615,7 @@ func mapWarehouseToResponse(warehouse *models.Warehouse) dto.WarehouseResponse {
 		Phone:      warehouse.Phone,
 		Email:      warehouse.Email,
 		ManagerID:  warehouse.ManagerID,
+		CustomerID: warehouse.CustomerID,
 		Capacity:   capacity,
 		IsActive:   warehouse.IsActive,
 		CreatedAt:  warehouse.CreatedAt,
@@ -644,6 +645,10 @@ func mapWarehouseStockToResponse(stock *models.WarehouseStock) dto.WarehouseStoc
 		ProductCode:   productCode,
 		ProductName:   productName,
 		Quantity:      stock.Quantity.String(),
+		ReservedQty:   stock.ReservedQuantity.String(),
+		AvailableQty:  stock.AvailableQuantity().String(),
+		QuarantineQty: stock.QuarantineQuantity.String(),
+		InTransitQty:  stock.InTransitQuantity.String(),
 		MinimumStock:  stock.MinimumStock.String(),
 		MaximumStock:  stock.MaximumStock.String(),
 		Location:      stock.Location,
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"backend/internal/service/inventory"
+	"backend/internal/service/stockopname"
+)
+
+// expireBatches marks batches whose expiry date has passed as EXPIRED so they are no longer picked
+// Runs daily at 00:30 by default (JOB_BATCH_EXPIRY_MONITOR)
+func (s *Scheduler) expireBatches() {
+	defer s.recoverFromPanic("expireBatches")
+
+	start := time.Now()
+	now := time.Now().UTC()
+
+	expired, err := inventory.NewBatchExpiryService(s.db).ExpireBatches(context.Background(), now)
+	if err != nil {
+		log.Printf("[ERROR][INVENTORY] Batch expiry failed: %v", err)
+		return
+	}
+
+	log.Printf("[INFO][INVENTORY] Batch expiry: marked %d batches expired (duration: %v)",
+		expired, time.Since(start))
+}
+
+// createMonthEndStockSnapshots stores last month's stock snapshot for every company that lacks one
+// Runs daily at 00:45 by default (JOB_STOCK_SNAPSHOT) so a missed month end is caught up the next day
+func (s *Scheduler) createMonthEndStockSnapshots() {
+	defer s.recoverFromPanic("createMonthEndStockSnapshots")
+
+	start := time.Now()
+	now := time.Now().UTC()
+
+	created, err := inventory.NewStockSnapshotService(s.db).CreateMonthEndSnapshots(context.Background(), now)
+	if err != nil {
+		log.Printf("[ERROR][INVENTORY] Month-end stock snapshot failed: %v", err)
+		return
+	}
+
+	log.Printf("[INFO][INVENTORY] Month-end stock snapshot: created %d snapshots (duration: %v)",
+		created, time.Since(start))
+}
+
+// runCycleCountPlans creates DRAFT stock opnames for every active cycle-count plan that is due
+// Runs daily at 01:15 by default (JOB_CYCLE_COUNT_PLANS)
+func (s *Scheduler) runCycleCountPlans() {
+	defer s.recoverFromPanic("runCycleCountPlans")
+
+	start := time.Now()
+	now := time.Now().UTC()
+
+	// Only opname numbering is used from the stock opname service; no audit or posting needed
+	stockOpnameService := stockopname.NewStockOpnameService(s.db, nil, nil)
+	created, err := stockopname.NewCycleCountService(s.db, stockOpnameService).RunDuePlans(context.Background(), now)
+	if err != nil {
+		log.Printf("[ERROR][INVENTORY] Cycle count plans failed: %v", err)
+		return
+	}
+
+	log.Printf("[INFO][INVENTORY] Cycle count plans: created %d stock opnames (duration: %v)",
+		created, time.Since(start))
+}
@@ -59,6 +59,25 @@ func (s *Scheduler) Start() error {
 		return err
 	}
 
+	// Register inventory jobs
+	if s.config.Job.BatchExpiryMonitor != "" {
+		if _, err := s.cron.AddFunc(s.config.Job.BatchExpiryMonitor, s.expireBatches); err != nil {
+			return err
+		}
+	}
+
+	if s.config.Job.StockSnapshot != "" {
+		if _, err := s.cron.AddFunc(s.config.Job.StockSnapshot, s.createMonthEndStockSnapshots); err != nil {
+			return err
+		}
+	}
+
+	if s.config.Job.CycleCountPlans != "" {
+		if _, err := s.cron.AddFunc(s.config.Job.CycleCountPlans, s.runCycleCountPlans); err != nil {
+			return err
+		}
+	}
+
 	// Start the scheduler
 	s.cron.Start()
 	s.isRunning = true
@@ -68,6 +87,15 @@ func (s *Scheduler) Start() error {
 	log.Printf("[JOB] Email cleanup: %s", s.config.Job.EmailCleanup)
 	log.Printf("[JOB] Password cleanup: %s", s.config.Job.PasswordCleanup)
 	log.Printf("[JOB] Login cleanup: %s", s.config.Job.LoginCleanup)
+	if s.config.Job.BatchExpiryMonitor != "" {
+		log.Printf("[JOB] Batch expiry monitor: %s", s.config.Job.BatchExpiryMonitor)
+	}
+	if s.config.Job.StockSnapshot != "" {
+		log.Printf("[JOB] Month-end stock snapshot: %s", s.config.Job.StockSnapshot)
+	}
+	if s.config.Job.CycleCountPlans != "" {
+		log.Printf("[JOB] Cycle count plans: %s", s.config.Job.CycleCountPlans)
+	}
 
 	return nil
 }
@@ -9,13 +9,19 @@ import (
 	"backend/internal/handler"
 	"backend/internal/jobs"
 	"backend/internal/middleware"
+	"backend/internal/service/assembly"
 	"backend/internal/service/audit"
 	"backend/internal/service/auth"
 	"backend/internal/service/company"
+	"backend/internal/service/consignment"
+	"backend/internal/service/creditnote"
 	"backend/internal/service/customer"
+	"backend/internal/service/customerreturn"
+	"backend/internal/service/debitnote"
 	"backend/internal/service/deliverytolerance"
 	"backend/internal/service/document"
 	"backend/internal/service/goodsreceipt"
+	"backend/internal/service/inventory"
 	"backend/internal/service/inventoryadjustment"
 	"backend/internal/service/invoice"
 	"backend/internal/service/payment"
@@ -23,11 +29,15 @@ import (
 	"backend/internal/service/product"
 	"backend/internal/service/purchase"
 	"backend/internal/service/purchaseinvoice"
+	"backend/internal/service/qualityhold"
+	"backend/internal/service/recall"
 	"backend/internal/service/sales"
+	"backend/internal/service/serial"
 	"backend/internal/service/stock_transfer"
 	"backend/internal/service/stockopname"
 	"backend/internal/service/supplier"
 	"backend/internal/service/supplierpayment"
+	"backend/internal/service/taxreport"
 	"backend/internal/service/tenant"
 	"backend/internal/service/warehouse"
 	"backend/pkg/email"
@@ -342,6 +352,10 @@ func setupProtectedRoutes(
 			productGroup.POST("/:id/suppliers", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), productHandler.AddProductSupplier)
 			productGroup.PUT("/:id/suppliers/:supplierId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), productHandler.UpdateProductSupplier)
 			productGroup.DELETE("/:id/suppliers/:supplierId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), productHandler.DeleteProductSupplier)
+
+			// Bill of materials - view for all, replace OWNER/ADMIN only
+			productGroup.GET("/:id/bom", productHandler.GetProductBOM)
+			productGroup.PUT("/:id/bom", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), productHandler.SetProductBOM)
 		}
 
 		// ============================================================================
@@ -390,7 +404,10 @@ func setupProtectedRoutes(
 		// WAREHOUSE MANAGEMENT ROUTES (PHASE 2 - Master Data Management)
 		// Reference: 02-MASTER-DATA-MANAGEMENT.md Module 4: Warehouse Management
 		// ============================================================================
-		warehouseService := warehouse.NewWarehouseService(db, auditService)
+		// Stock posting service - single entry point for stock changes + inventory movements
+		stockPostingService := inventory.NewStockPostingService(db)
+
+		warehouseService := warehouse.NewWarehouseService(db, auditService, stockPostingService)
 		warehouseHandler := handler.NewWarehouseHandler(warehouseService)
 
 		warehouseGroup := businessProtected.Group("/warehouses")
@@ -400,11 +417,19 @@ func setupProtectedRoutes(
 			warehouseGroup.GET("", warehouseHandler.ListWarehouses)
 			warehouseGroup.GET("/stock-status", warehouseHandler.GetWarehouseStockStatus)
 			warehouseGroup.GET("/:id", warehouseHandler.GetWarehouse)
+			warehouseGroup.GET("/:id/bins", warehouseHandler.ListWarehouseBins)
+			warehouseGroup.GET("/:id/bin-stocks", warehouseHandler.ListBinStocks)
 
 			// POST/PUT/DELETE endpoints - OWNER/ADMIN only
 			warehouseGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.CreateWarehouse)
 			warehouseGroup.PUT("/:id", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.UpdateWarehouse)
 			warehouseGroup.DELETE("/:id", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.DeleteWarehouse)
+
+			// Bin locations and bin-to-bin moves - OWNER/ADMIN only
+			warehouseGroup.POST("/:id/bins", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.CreateWarehouseBin)
+			warehouseGroup.PUT("/:id/bins/:binId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.UpdateWarehouseBin)
+			warehouseGroup.DELETE("/:id/bins/:binId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.DeleteWarehouseBin)
+			warehouseGroup.POST("/:id/bin-moves", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), warehouseHandler.MoveBinStock)
 		}
 
 		// Warehouse Stock routes
@@ -425,7 +450,7 @@ func setupProtectedRoutes(
 		// STOCK TRANSFER MANAGEMENT ROUTES (PHASE 2 - Inventory Management)
 		// Reference: Inter-warehouse stock transfer operations
 		// ============================================================================
-		stockTransferService := stock_transfer.NewStockTransferService(db, auditService)
+		stockTransferService := stock_transfer.NewStockTransferService(db, auditService, stockPostingService)
 		stockTransferHandler := handler.NewStockTransferHandler(stockTransferService)
 
 		stockTransferGroup := businessProtected.Group("/stock-transfers")
@@ -446,12 +471,94 @@ func setupProtectedRoutes(
 			stockTransferGroup.POST("/:id/cancel", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockTransferHandler.CancelStockTransfer)
 		}
 
+		// ============================================================================
+		// DOCUMENT NUMBER GENERATOR (Shared service for all document types)
+		// Reference: Auto-generate document numbers based on company format settings
+		// ============================================================================
+		docNumberGen := document.NewDocumentNumberGenerator(db)
+
+		// ============================================================================
+		// PRODUCT RECALL ROUTES (PHASE 2 - Inventory Management)
+		// Reference: Lot recall with stock hold, batch traceability and customer returns
+		// ============================================================================
+		recallService := recall.NewRecallService(db, docNumberGen)
+		recallHandler := handler.NewRecallHandler(recallService)
+
+		recallGroup := businessProtected.Group("/recalls")
+		recallGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			recallGroup.GET("", recallHandler.ListRecalls)
+			recallGroup.GET("/:id", recallHandler.GetRecall)
+			recallGroup.GET("/:id/report", recallHandler.GetRecallReport)
+
+			// POST endpoints - OWNER/ADMIN only
+			recallGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), recallHandler.CreateRecall)
+			recallGroup.POST("/:id/close", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), recallHandler.CloseRecall)
+		}
+
+		// ============================================================================
+		// QUALITY HOLD ROUTES (PHASE 2 - Inventory Management)
+		// Reference: Quarantine of batches until quality releases or scraps them
+		// ============================================================================
+		qualityHoldService := qualityhold.NewQualityHoldService(db, stockPostingService)
+		qualityHoldHandler := handler.NewQualityHoldHandler(qualityHoldService)
+
+		qualityHoldGroup := businessProtected.Group("/quality-holds")
+		qualityHoldGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			qualityHoldGroup.GET("", qualityHoldHandler.ListQualityHolds)
+			qualityHoldGroup.GET("/:id", qualityHoldHandler.GetQualityHold)
+
+			// POST endpoints - OWNER/ADMIN only (release and scrap record the approver)
+			qualityHoldGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), qualityHoldHandler.CreateQualityHold)
+			qualityHoldGroup.POST("/:id/release", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), qualityHoldHandler.ReleaseQualityHold)
+			qualityHoldGroup.POST("/:id/scrap", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), qualityHoldHandler.ScrapQualityHold)
+		}
+
+		// ============================================================================
+		// SERIAL NUMBER ROUTES (PHASE 2 - Inventory Management)
+		// Reference: Serial-tracked units from goods receipt to customer, with warranty start
+		// ============================================================================
+		serialService := serial.NewSerialService(db)
+		serialHandler := handler.NewSerialHandler(serialService)
+
+		serialGroup := businessProtected.Group("/serials")
+		serialGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			serialGroup.GET("", serialHandler.ListSerials)
+			serialGroup.GET("/:id/history", serialHandler.GetSerialHistory)
+		}
+
+		// ============================================================================
+		// ASSEMBLY ORDER ROUTES (PHASE 2 - Inventory Management)
+		// Reference: Build products from their bill of materials, or take them apart
+		// ============================================================================
+		assemblyService := assembly.NewAssemblyService(db, stockPostingService)
+		assemblyHandler := handler.NewAssemblyHandler(assemblyService)
+
+		assemblyGroup := businessProtected.Group("/assembly-orders")
+		assemblyGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			assemblyGroup.GET("", assemblyHandler.ListAssemblyOrders)
+			assemblyGroup.GET("/:id", assemblyHandler.GetAssemblyOrder)
+
+			// POST endpoints - OWNER/ADMIN only
+			assemblyGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), assemblyHandler.CreateAssemblyOrder)
+			assemblyGroup.POST("/:id/complete", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), assemblyHandler.CompleteAssemblyOrder)
+			assemblyGroup.POST("/:id/cancel", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), assemblyHandler.CancelAssemblyOrder)
+		}
+
 		// ============================================================================
 		// STOCK OPNAME MANAGEMENT ROUTES (PHASE 2 - Inventory Management)
 		// Reference: Physical inventory count and stock adjustment operations
 		// ============================================================================
-		stockOpnameService := stockopname.NewStockOpnameService(db, auditService)
-		stockOpnameHandler := handler.NewStockOpnameHandler(stockOpnameService)
+		stockOpnameService := stockopname.NewStockOpnameService(db, auditService, stockPostingService)
+		cycleCountService := stockopname.NewCycleCountService(db, stockOpnameService)
+		stockOpnameHandler := handler.NewStockOpnameHandler(stockOpnameService, cycleCountService)
 
 		stockOpnameGroup := businessProtected.Group("/stock-opnames")
 		stockOpnameGroup.Use(middleware.CompanyContextMiddleware(db))
@@ -459,6 +566,7 @@ func setupProtectedRoutes(
 			// GET endpoints - all authenticated users can view
 			stockOpnameGroup.GET("", stockOpnameHandler.ListStockOpnames)
 			stockOpnameGroup.GET("/:id", stockOpnameHandler.GetStockOpname)
+			stockOpnameGroup.GET("/:id/count-sheet", stockOpnameHandler.GetCountSheet)
 
 			// POST/PUT/DELETE endpoints - OWNER/ADMIN only
 			stockOpnameGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.CreateStockOpname)
@@ -471,6 +579,7 @@ func setupProtectedRoutes(
 			// Item management endpoints - OWNER/ADMIN only
 			stockOpnameGroup.POST("/:id/items", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.AddStockOpnameItem)
 			stockOpnameGroup.PUT("/:id/items/batch", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.BatchUpdateStockOpnameItems)
+			stockOpnameGroup.POST("/:id/import-counts", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.ImportCounts)
 			stockOpnameGroup.PUT("/:id/items/:itemId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.UpdateStockOpnameItem)
 			stockOpnameGroup.DELETE("/:id/items/:itemId", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.DeleteStockOpnameItem)
 
@@ -478,11 +587,26 @@ func setupProtectedRoutes(
 			stockOpnameGroup.POST("/:id/import-products", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.ImportWarehouseProducts)
 		}
 
+		// Cycle-count plans create DRAFT stock opnames on a rolling calendar
+		cycleCountGroup := businessProtected.Group("/cycle-count-plans")
+		cycleCountGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			cycleCountGroup.GET("", stockOpnameHandler.ListCycleCountPlans)
+			cycleCountGroup.GET("/:id", stockOpnameHandler.GetCycleCountPlan)
+
+			// POST/PUT/DELETE endpoints - OWNER/ADMIN only
+			cycleCountGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.CreateCycleCountPlan)
+			cycleCountGroup.PUT("/:id", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.UpdateCycleCountPlan)
+			cycleCountGroup.DELETE("/:id", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.DeleteCycleCountPlan)
+			cycleCountGroup.POST("/:id/run", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockOpnameHandler.RunCycleCountPlan)
+		}
+
 		// ============================================================================
 		// INVENTORY ADJUSTMENT MANAGEMENT ROUTES (PHASE 2 - Inventory Management)
 		// Reference: Manual stock adjustments (increase/decrease) for various reasons
 		// ============================================================================
-		inventoryAdjustmentService := inventoryadjustment.NewInventoryAdjustmentService(db, auditService)
+		inventoryAdjustmentService := inventoryadjustment.NewInventoryAdjustmentService(db, auditService, stockPostingService)
 		inventoryAdjustmentHandler := handler.NewInventoryAdjustmentHandler(inventoryAdjustmentService)
 
 		inventoryAdjustmentGroup := businessProtected.Group("/inventory-adjustments")
@@ -503,10 +627,36 @@ func setupProtectedRoutes(
 		}
 
 		// ============================================================================
-		// DOCUMENT NUMBER GENERATOR (Shared service for all document types)
-		// Reference: Auto-generate document numbers based on company format settings
+		// INVENTORY REPORTING ROUTES (PHASE 2 - Inventory Management)
+		// Reference: Valuation and reports built from inventory movements
 		// ============================================================================
-		docNumberGen := document.NewDocumentNumberGenerator(db)
+		valuationService := inventory.NewValuationService(db)
+		batchExpiryService := inventory.NewBatchExpiryService(db)
+		stockCardService := inventory.NewStockCardService(db)
+		stockSnapshotService := inventory.NewStockSnapshotService(db)
+		inventoryHandler := handler.NewInventoryHandler(valuationService, batchExpiryService, stockCardService, stockSnapshotService)
+		stockReconciliationService := inventory.NewStockReconciliationService(db, stockPostingService, auditService)
+		stockReconciliationHandler := handler.NewStockReconciliationHandler(stockReconciliationService)
+
+		inventoryGroup := businessProtected.Group("/inventory")
+		inventoryGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			inventoryGroup.GET("/valuation", inventoryHandler.GetInventoryValuation)
+			inventoryGroup.GET("/cost-layers", inventoryHandler.ListCostLayers)
+			inventoryGroup.GET("/near-expiry", inventoryHandler.GetNearExpiryReport)
+			inventoryGroup.GET("/stock-card", inventoryHandler.GetStockCard)
+			inventoryGroup.GET("/stock-card/export", inventoryHandler.ExportStockCard)
+			inventoryGroup.GET("/stock-as-of", inventoryHandler.GetStockAsOf)
+			inventoryGroup.GET("/snapshots", inventoryHandler.ListStockSnapshots)
+
+			// POST endpoints - OWNER/ADMIN only
+			inventoryGroup.POST("/snapshots", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), inventoryHandler.CreateStockSnapshot)
+
+			// Stock reconciliation - OWNER/ADMIN only
+			inventoryGroup.GET("/reconciliation", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockReconciliationHandler.GetReconciliation)
+			inventoryGroup.POST("/reconciliation/repair", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), stockReconciliationHandler.RepairReconciliation)
+		}
 
 		// ============================================================================
 		// PURCHASE ORDER MANAGEMENT ROUTES (PHASE 3 - Procurement)
@@ -534,6 +684,23 @@ func setupProtectedRoutes(
 			purchaseOrderGroup.POST("/:id/short-close", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), purchaseOrderHandler.ShortClosePurchaseOrder)
 		}
 
+		// ============================================================================
+		// REPLENISHMENT ROUTES (PHASE 3 - Procurement)
+		// Reference: Reorder suggestions from stock levels, turned into DRAFT purchase orders
+		// ============================================================================
+		replenishmentService := purchase.NewReplenishmentService(db, purchaseOrderService)
+		replenishmentHandler := handler.NewReplenishmentHandler(replenishmentService, purchaseOrderService)
+
+		replenishmentGroup := businessProtected.Group("/replenishment")
+		replenishmentGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			replenishmentGroup.GET("/suggestions", replenishmentHandler.GetSuggestions)
+
+			// POST endpoints - OWNER/ADMIN only
+			replenishmentGroup.POST("/purchase-orders", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), replenishmentHandler.CreatePurchaseOrders)
+		}
+
 		// ============================================================================
 		// DELIVERY TOLERANCE SERVICE (Used by Goods Receipt for validation)
 		// Reference: Hierarchical tolerance configuration for under/over delivery
@@ -544,7 +711,7 @@ func setupProtectedRoutes(
 		// GOODS RECEIPT MANAGEMENT ROUTES (PHASE 3 - Procurement)
 		// Reference: Goods receipt (penerimaan barang) management for procurement workflow
 		// ============================================================================
-		goodsReceiptService := goodsreceipt.NewGoodsReceiptService(db, auditService, deliveryToleranceService)
+		goodsReceiptService := goodsreceipt.NewGoodsReceiptService(db, auditService, deliveryToleranceService, stockPostingService)
 		goodsReceiptHandler := handler.NewGoodsReceiptHandler(goodsReceiptService)
 
 		goodsReceiptGroup := businessProtected.Group("/goods-receipts")
@@ -553,6 +720,7 @@ func setupProtectedRoutes(
 			// GET endpoints - all authenticated users can view
 			goodsReceiptGroup.GET("", goodsReceiptHandler.ListGoodsReceipts)
 			goodsReceiptGroup.GET("/:id", goodsReceiptHandler.GetGoodsReceipt)
+			goodsReceiptGroup.GET("/:id/put-away-suggestions", goodsReceiptHandler.GetPutAwaySuggestions)
 
 			// POST/PUT/DELETE endpoints - OWNER/ADMIN only
 			goodsReceiptGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), goodsReceiptHandler.CreateGoodsReceipt)
@@ -595,7 +763,7 @@ func setupProtectedRoutes(
 		// PURCHASE INVOICE MANAGEMENT ROUTES (PHASE 3 - Procurement)
 		// Reference: Purchase invoice (faktur pembelian) management for supplier invoices
 		// ============================================================================
-		purchaseInvoiceService := purchaseinvoice.NewPurchaseInvoiceService(db, docNumberGen, auditService)
+		purchaseInvoiceService := purchaseinvoice.NewPurchaseInvoiceService(db, docNumberGen, auditService, stockPostingService)
 		purchaseInvoiceHandler := handler.NewPurchaseInvoiceHandler(purchaseInvoiceService)
 
 		purchaseInvoiceGroup := businessProtected.Group("/purchase-invoices")
@@ -604,6 +772,7 @@ func setupProtectedRoutes(
 			// GET endpoints - all authenticated users can view
 			purchaseInvoiceGroup.GET("", purchaseInvoiceHandler.ListPurchaseInvoices)
 			purchaseInvoiceGroup.GET("/:id", purchaseInvoiceHandler.GetPurchaseInvoice)
+			purchaseInvoiceGroup.GET("/:id/landed-costs", purchaseInvoiceHandler.GetLandedCostAllocations)
 
 			// POST/PUT/DELETE endpoints - OWNER/ADMIN only
 			purchaseInvoiceGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), purchaseInvoiceHandler.CreatePurchaseInvoice)
@@ -646,7 +815,7 @@ func setupProtectedRoutes(
 		// Reference: Sales order management for sales workflow with 8-state lifecycle
 		// Status flow: DRAFT → PENDING → APPROVED → PROCESSING → SHIPPED → DELIVERED → COMPLETED
 		// ============================================================================
-		salesOrderService := sales.NewSalesOrderService(db, docNumberGen)
+		salesOrderService := sales.NewSalesOrderService(db, docNumberGen, stockPostingService)
 		salesOrderHandler := handler.NewSalesOrderHandler(salesOrderService)
 
 		salesOrderGroup := businessProtected.Group("/sales-orders")
@@ -676,7 +845,7 @@ func setupProtectedRoutes(
 		// Reference: Delivery order management for distribution workflow with 5-state lifecycle
 		// Status flow: PREPARED → IN_TRANSIT → DELIVERED → CONFIRMED
 		// ============================================================================
-		deliveryService := sales.NewDeliveryService(db, docNumberGen)
+		deliveryService := sales.NewDeliveryService(db, docNumberGen, stockPostingService)
 		deliveryHandler := handler.NewDeliveryHandler(deliveryService)
 
 		deliveryGroup := businessProtected.Group("/deliveries")
@@ -723,6 +892,100 @@ func setupProtectedRoutes(
 			invoiceGroup.POST("/:id/payments", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), invoiceHandler.RecordPayment)
 		}
 
+		// ============================================================================
+		// CUSTOMER RETURN ROUTES (PHASE 4 - Sales Invoice Management)
+		// Reference: Returns against deliveries, inspected into sellable, quarantine or scrap
+		// Status flow: DRAFT → INSPECTED → COMPLETED (posts stock, issues credit note)
+		// ============================================================================
+		customerReturnService := customerreturn.NewCustomerReturnService(db, docNumberGen, stockPostingService)
+		customerReturnHandler := handler.NewCustomerReturnHandler(customerReturnService)
+
+		customerReturnGroup := businessProtected.Group("/customer-returns")
+		customerReturnGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			customerReturnGroup.GET("", customerReturnHandler.ListCustomerReturns)
+			customerReturnGroup.GET("/:id", customerReturnHandler.GetCustomerReturn)
+
+			// POST endpoints - OWNER/ADMIN only
+			customerReturnGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), customerReturnHandler.CreateCustomerReturn)
+			customerReturnGroup.POST("/:id/inspect", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), customerReturnHandler.InspectCustomerReturn)
+			customerReturnGroup.POST("/:id/complete", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), customerReturnHandler.CompleteCustomerReturn)
+			customerReturnGroup.POST("/:id/cancel", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), customerReturnHandler.CancelCustomerReturn)
+		}
+
+		// ============================================================================
+		// SALES CREDIT NOTE ROUTES (PHASE 4 - Sales Invoice Management)
+		// Reference: Credit notes against invoices; unapplied credit stays with the customer
+		// until it is applied to another invoice or refunded
+		// ============================================================================
+		creditNoteService := creditnote.NewCreditNoteService(db, docNumberGen)
+		salesCreditNoteHandler := handler.NewSalesCreditNoteHandler(creditNoteService)
+
+		creditNoteGroup := businessProtected.Group("/credit-notes")
+		creditNoteGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			creditNoteGroup.GET("", salesCreditNoteHandler.ListCreditNotes)
+			creditNoteGroup.GET("/:id", salesCreditNoteHandler.GetCreditNote)
+
+			// POST endpoints - OWNER/ADMIN only
+			creditNoteGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), salesCreditNoteHandler.CreateCreditNote)
+			creditNoteGroup.POST("/:id/apply", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), salesCreditNoteHandler.ApplyCreditNote)
+			creditNoteGroup.POST("/:id/refund", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), salesCreditNoteHandler.RefundCreditNote)
+		}
+
+		// ============================================================================
+		// SALES DEBIT NOTE ROUTES (PHASE 4 - Sales Invoice Management)
+		// Reference: Additional charges against invoices, added to what the customer owes
+		// ============================================================================
+		debitNoteService := debitnote.NewDebitNoteService(db, docNumberGen)
+		salesDebitNoteHandler := handler.NewSalesDebitNoteHandler(debitNoteService)
+
+		debitNoteGroup := businessProtected.Group("/debit-notes")
+		debitNoteGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			debitNoteGroup.GET("", salesDebitNoteHandler.ListDebitNotes)
+			debitNoteGroup.GET("/:id", salesDebitNoteHandler.GetDebitNote)
+
+			// POST endpoints - OWNER/ADMIN only
+			debitNoteGroup.POST("", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), salesDebitNoteHandler.CreateDebitNote)
+		}
+
+		// ============================================================================
+		// TAX REPORT ROUTES (PHASE 4 - Sales Invoice Management)
+		// Reference: Output tax from invoices, debit notes and credit notes per period
+		// ============================================================================
+		taxReportService := taxreport.NewTaxReportService(db)
+		taxReportHandler := handler.NewTaxReportHandler(taxReportService)
+
+		taxReportGroup := businessProtected.Group("/tax-reports")
+		taxReportGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			taxReportGroup.GET("/sales", taxReportHandler.GetSalesTaxReport)
+		}
+
+		// ============================================================================
+		// CONSIGNMENT ROUTES (PHASE 4 - Sales Invoice Management)
+		// Reference: Stock at customer sites (CONSIGNMENT warehouses) settled by sell-through reports
+		// ============================================================================
+		consignmentService := consignment.NewConsignmentService(db, docNumberGen, stockPostingService)
+		consignmentHandler := handler.NewConsignmentHandler(consignmentService)
+
+		consignmentGroup := businessProtected.Group("/consignments")
+		consignmentGroup.Use(middleware.CompanyContextMiddleware(db))
+		{
+			// GET endpoints - all authenticated users can view
+			consignmentGroup.GET("/balance", consignmentHandler.GetBalanceReport)
+			consignmentGroup.GET("/settlements", consignmentHandler.ListSettlements)
+			consignmentGroup.GET("/settlements/:id", consignmentHandler.GetSettlement)
+
+			// POST endpoints - OWNER/ADMIN only
+			consignmentGroup.POST("/settlements", middleware.RequireRoleMiddleware("OWNER", "ADMIN"), consignmentHandler.CreateSettlement)
+		}
+
 		// ============================================================================
 		// PAYMENT MANAGEMENT ROUTES (PHASE 4 - Customer Payment Management)
 		// Reference: Customer payment (invoice payment) management for sales
@@ -0,0 +1,638 @@
+package assembly
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/internal/service/uom"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// AssemblyService - Assembly and disassembly orders.
+// An ASSEMBLY order consumes the components of a product's bill of materials from one
+// warehouse and produces the finished product at the rolled-up cost of what it consumed.
+// A DISASSEMBLY order takes finished units apart and books the components back in,
+// spreading the finished product's cost over them.
+type AssemblyService struct {
+	db                  *gorm.DB
+	stockPostingService *inventory.StockPostingService
+	uomService          *uom.UOMService
+}
+
+// NewAssemblyService creates a new assembly service instance
+func NewAssemblyService(db *gorm.DB, stockPostingService *inventory.StockPostingService) *AssemblyService {
+	return &AssemblyService{
+		db:                  db,
+		stockPostingService: stockPostingService,
+		uomService:          uom.NewUOMService(db),
+	}
+}
+
+// consumption is the stock taken out by one outbound posting
+type consumption struct {
+	BatchID    *string
+	ExpiryDate *time.Time
+	Quantity   decimal.Decimal // Base unit
+	Cost       decimal.Decimal
+}
+
+// ============================================================================
+// ASSEMBLY ORDER OPERATIONS
+// ============================================================================
+
+// CreateAssemblyOrder creates a DRAFT order with the components of the product's bill of materials
+func (s *AssemblyService) CreateAssemblyOrder(
+	ctx context.Context,
+	tenantID, companyID, userID string,
+	req *dto.CreateAssemblyOrderRequest,
+) (*models.AssemblyOrder, error) {
+	orderDate, err := time.Parse("2006-01-02", req.OrderDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid orderDate format, expected YYYY-MM-DD")
+	}
+
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("quantity must be greater than 0")
+	}
+
+	var expiryDate *time.Time
+	if req.ExpiryDate != nil && *req.ExpiryDate != "" {
+		parsed, err := time.Parse("2006-01-02", *req.ExpiryDate)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid expiryDate format, expected YYYY-MM-DD")
+		}
+		expiryDate = &parsed
+	}
+
+	var order *models.AssemblyOrder
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var warehouse models.Warehouse
+		if err := tx.Where("id = ? AND company_id = ?", req.WarehouseID, companyID).First(&warehouse).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("warehouse not found")
+			}
+			return fmt.Errorf("failed to load warehouse: %w", err)
+		}
+		if warehouse.Type == models.WarehouseTypeConsignment {
+			return pkgerrors.NewBadRequestError("stock in a CONSIGNMENT warehouse cannot be assembled")
+		}
+
+		var product models.Product
+		if err := tx.Where("id = ? AND company_id = ?", req.ProductID, companyID).First(&product).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("product not found")
+			}
+			return fmt.Errorf("failed to load product: %w", err)
+		}
+		if product.IsSerialTracked {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("serial-tracked product %s cannot be assembled", product.Code))
+		}
+
+		var components []models.ProductBOMComponent
+		if err := tx.Preload("ComponentProduct").Preload("ProductUnit").
+			Where("product_id = ?", product.ID).
+			Order("created_at ASC").
+			Find(&components).Error; err != nil {
+			return fmt.Errorf("failed to load bill of materials: %w", err)
+		}
+		if len(components) == 0 {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s has no bill of materials", product.Code))
+		}
+
+		orderType := models.AssemblyOrderType(req.Type)
+		orderNumber, err := s.generateOrderNumber(tx, tenantID, companyID, orderType, orderDate)
+		if err != nil {
+			return err
+		}
+
+		order = &models.AssemblyOrder{
+			TenantID:    tenantID,
+			CompanyID:   companyID,
+			OrderNumber: orderNumber,
+			OrderDate:   orderDate,
+			Type:        orderType,
+			Status:      models.AssemblyOrderStatusDraft,
+			WarehouseID: warehouse.ID,
+			ProductID:   product.ID,
+			Quantity:    quantity,
+			ExpiryDate:  expiryDate,
+			Notes:       req.Notes,
+			CreatedBy:   &userID,
+		}
+		if orderType == models.AssemblyOrderTypeAssembly {
+			order.BatchNumber = req.BatchNumber
+		} else {
+			order.BatchID = req.BatchID
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create assembly order: %w", err)
+		}
+
+		for _, component := range components {
+			if component.ComponentProduct.IsSerialTracked {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("serial-tracked component %s cannot be assembled", component.ComponentProduct.Code))
+			}
+
+			baseQty := component.BaseQuantity.Mul(quantity)
+			item := &models.AssemblyOrderItem{
+				AssemblyOrderID: order.ID,
+				ProductID:       component.ComponentProductID,
+				ProductUnitID:   component.ProductUnitID,
+				Quantity:        uom.FromProductUnit(component.ProductUnit).FromBase(baseQty),
+				BaseQuantity:    baseQty,
+			}
+			if err := tx.Create(item).Error; err != nil {
+				return fmt.Errorf("failed to create assembly order item: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAssemblyOrderByID(ctx, tenantID, companyID, order.ID)
+}
+
+// ListAssemblyOrders lists assembly orders with filters and pagination
+func (s *AssemblyService) ListAssemblyOrders(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.AssemblyOrderQuery,
+) ([]models.AssemblyOrder, *dto.PaginationInfo, error) {
+	var orders []models.AssemblyOrder
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("company_id = ?", companyID)
+
+	if query.Type != "" {
+		db = db.Where("type = ?", query.Type)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.WarehouseID != "" {
+		db = db.Where("warehouse_id = ?", query.WarehouseID)
+	}
+	if query.ProductID != "" {
+		db = db.Where("product_id = ?", query.ProductID)
+	}
+	if query.Search != "" {
+		db = db.Where("order_number LIKE ?", "%"+query.Search+"%")
+	}
+
+	if err := db.Model(&models.AssemblyOrder{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Order("order_date DESC, order_number DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Warehouse").
+		Preload("Product").
+		Preload("Batch").
+		Find(&orders).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return orders, pagination, nil
+}
+
+// GetAssemblyOrderByID returns an assembly order with its components
+func (s *AssemblyService) GetAssemblyOrderByID(ctx context.Context, tenantID, companyID, orderID string) (*models.AssemblyOrder, error) {
+	var order models.AssemblyOrder
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Warehouse").
+		Preload("Product").
+		Preload("Batch").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Items.Product").
+		Preload("Items.ProductUnit").
+		Preload("Items.Batch").
+		Where("id = ? AND company_id = ?", orderID, companyID).
+		First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("assembly order not found")
+		}
+		return nil, fmt.Errorf("failed to get assembly order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// CompleteAssemblyOrder posts the stock of a DRAFT order (DRAFT → COMPLETED)
+func (s *AssemblyService) CompleteAssemblyOrder(ctx context.Context, tenantID, companyID, orderID, userID string) (*models.AssemblyOrder, error) {
+	order, err := s.GetAssemblyOrderByID(ctx, tenantID, companyID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.AssemblyOrderStatusDraft {
+		return nil, pkgerrors.NewBadRequestError("only DRAFT assembly orders can be completed")
+	}
+
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		// Re-check under the row lock so two requests cannot both post the order's stock
+		if err := s.lockDraftOrder(tx, companyID, orderID, "completed"); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var err error
+		if order.Type == models.AssemblyOrderTypeAssembly {
+			err = s.postAssembly(tx, order, now, userID)
+		} else {
+			err = s.postDisassembly(tx, order, now, userID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AssemblyOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"status":       models.AssemblyOrderStatusCompleted,
+			"batch_id":     order.BatchID,
+			"expiry_date":  order.ExpiryDate,
+			"unit_cost":    order.UnitCost,
+			"total_cost":   order.TotalCost,
+			"completed_by": userID,
+			"completed_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to complete assembly order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAssemblyOrderByID(ctx, tenantID, companyID, orderID)
+}
+
+// CancelAssemblyOrder cancels a DRAFT order (DRAFT → CANCELLED). No stock has been posted yet.
+func (s *AssemblyService) CancelAssemblyOrder(ctx context.Context, tenantID, companyID, orderID string) (*models.AssemblyOrder, error) {
+	order, err := s.GetAssemblyOrderByID(ctx, tenantID, companyID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.AssemblyOrderStatusDraft {
+		return nil, pkgerrors.NewBadRequestError("only DRAFT assembly orders can be cancelled")
+	}
+
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockDraftOrder(tx, companyID, orderID, "cancelled"); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AssemblyOrder{}).
+			Where("id = ?", order.ID).
+			Update("status", models.AssemblyOrderStatusCancelled).Error; err != nil {
+			return fmt.Errorf("failed to cancel assembly order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAssemblyOrderByID(ctx, tenantID, companyID, orderID)
+}
+
+// lockDraftOrder locks an assembly order row for the rest of the transaction
+// and checks it is still DRAFT
+func (s *AssemblyService) lockDraftOrder(tx *gorm.DB, companyID, orderID, action string) error {
+	var order models.AssemblyOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		Where("id = ? AND company_id = ?", orderID, companyID).
+		First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return pkgerrors.NewNotFoundError("assembly order not found")
+		}
+		return fmt.Errorf("failed to lock assembly order: %w", err)
+	}
+	if order.Status != models.AssemblyOrderStatusDraft {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("only DRAFT assembly orders can be %s", action))
+	}
+	return nil
+}
+
+// ============================================================================
+// STOCK POSTING
+// ============================================================================
+
+// postAssembly consumes the components and produces the finished product at their total cost.
+// A batch-tracked finished product goes into the order's batch number (default: the order
+// number) and expires with the earliest component consumed unless an expiry date was given.
+func (s *AssemblyService) postAssembly(tx *gorm.DB, order *models.AssemblyOrder, movementDate time.Time, userID string) error {
+	totalCost := decimal.Zero
+	var earliestExpiry *time.Time
+	for i := range order.Items {
+		item := &order.Items[i]
+		consumed, err := s.consume(tx, order, item.ProductID, item.Product.IsBatchTracked, item.BatchID, item.BaseQuantity, movementDate, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.recordConsumption(tx, item, consumed); err != nil {
+			return err
+		}
+
+		for _, c := range consumed {
+			totalCost = totalCost.Add(c.Cost)
+			if c.ExpiryDate != nil && (earliestExpiry == nil || c.ExpiryDate.Before(*earliestExpiry)) {
+				earliestExpiry = c.ExpiryDate
+			}
+		}
+	}
+
+	unitCost := totalCost.Div(order.Quantity).Round(4)
+	posting := &inventory.StockPosting{
+		TenantID:        order.TenantID,
+		CompanyID:       order.CompanyID,
+		WarehouseID:     order.WarehouseID,
+		ProductID:       order.ProductID,
+		MovementType:    models.MovementTypeAssembly,
+		Quantity:        order.Quantity,
+		UnitCost:        &unitCost,
+		MovementDate:    movementDate,
+		ReferenceType:   inventory.ReferenceTypeAssemblyOrder,
+		ReferenceID:     order.ID,
+		ReferenceNumber: order.OrderNumber,
+		Notes:           order.Notes,
+		CreatedBy:       userID,
+	}
+	if order.Product.IsBatchTracked {
+		batchNumber := order.OrderNumber
+		if order.BatchNumber != nil && *order.BatchNumber != "" {
+			batchNumber = *order.BatchNumber
+		}
+		if order.ExpiryDate == nil {
+			order.ExpiryDate = earliestExpiry
+		}
+		posting.Batch = &inventory.BatchDetails{
+			BatchNumber: batchNumber,
+			ExpiryDate:  order.ExpiryDate,
+		}
+	}
+
+	result, err := s.stockPostingService.Post(tx, posting)
+	if err != nil {
+		return err
+	}
+	if result.Batch != nil {
+		order.BatchID = &result.Batch.ID
+	}
+
+	order.UnitCost = unitCost
+	order.TotalCost = totalCost.Round(2)
+	return nil
+}
+
+// postDisassembly takes the finished units out and books the components back in.
+// The finished product's cost is spread over the components by their current value
+// (quantity × average cost in the warehouse, falling back to the product base cost),
+// or by quantity when none of them has a cost yet.
+func (s *AssemblyService) postDisassembly(tx *gorm.DB, order *models.AssemblyOrder, movementDate time.Time, userID string) error {
+	consumed, err := s.consume(tx, order, order.ProductID, order.Product.IsBatchTracked, order.BatchID, order.Quantity, movementDate, userID)
+	if err != nil {
+		return err
+	}
+
+	totalCost := decimal.Zero
+	var expiryDate *time.Time
+	for _, c := range consumed {
+		totalCost = totalCost.Add(c.Cost)
+		if expiryDate == nil {
+			expiryDate = c.ExpiryDate
+		}
+	}
+	if len(consumed) == 1 {
+		order.BatchID = consumed[0].BatchID
+	}
+
+	weights := make([]decimal.Decimal, len(order.Items))
+	totalWeight := decimal.Zero
+	for i, item := range order.Items {
+		cost, err := s.componentCost(tx, order.WarehouseID, &item.Product)
+		if err != nil {
+			return err
+		}
+		weights[i] = item.BaseQuantity.Mul(cost)
+		totalWeight = totalWeight.Add(weights[i])
+	}
+	if totalWeight.IsZero() {
+		for i, item := range order.Items {
+			weights[i] = item.BaseQuantity
+			totalWeight = totalWeight.Add(weights[i])
+		}
+	}
+
+	remaining := totalCost.Round(2)
+	for i := range order.Items {
+		item := &order.Items[i]
+
+		// The last component takes the rounding difference
+		allocated := remaining
+		if i < len(order.Items)-1 {
+			allocated = totalCost.Mul(weights[i]).Div(totalWeight).Round(2)
+			remaining = remaining.Sub(allocated)
+		}
+		unitCost := allocated.Div(item.BaseQuantity).Round(4)
+
+		posting := &inventory.StockPosting{
+			TenantID:        order.TenantID,
+			CompanyID:       order.CompanyID,
+			WarehouseID:     order.WarehouseID,
+			ProductID:       item.ProductID,
+			MovementType:    models.MovementTypeAssembly,
+			Quantity:        item.BaseQuantity,
+			UnitCost:        &unitCost,
+			MovementDate:    movementDate,
+			ReferenceType:   inventory.ReferenceTypeAssemblyOrder,
+			ReferenceID:     order.ID,
+			ReferenceNumber: order.OrderNumber,
+			Notes:           order.Notes,
+			CreatedBy:       userID,
+		}
+		// Recovered batch-tracked components go into a batch named after the order
+		if item.Product.IsBatchTracked {
+			posting.Batch = &inventory.BatchDetails{
+				BatchNumber: order.OrderNumber,
+				ExpiryDate:  expiryDate,
+			}
+		}
+
+		result, err := s.stockPostingService.Post(tx, posting)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"unit_cost":  unitCost,
+			"total_cost": allocated,
+		}
+		if result.Batch != nil {
+			updates["batch_id"] = result.Batch.ID
+		}
+		if err := tx.Model(&models.AssemblyOrderItem{}).Where("id = ?", item.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update assembly order item: %w", err)
+		}
+	}
+
+	order.UnitCost = totalCost.Div(order.Quantity).Round(4)
+	order.TotalCost = totalCost.Round(2)
+	return nil
+}
+
+// consume takes qty of a product out of the order's warehouse. Batch-tracked products
+// without a batch are picked FEFO; stock is picked from bins in pick order.
+func (s *AssemblyService) consume(
+	tx *gorm.DB,
+	order *models.AssemblyOrder,
+	productID string,
+	isBatchTracked bool,
+	batchID *string,
+	qty decimal.Decimal,
+	movementDate time.Time,
+	userID string,
+) ([]consumption, error) {
+	type pick struct {
+		BatchID  *string
+		Quantity decimal.Decimal
+	}
+	picks := []pick{{BatchID: batchID, Quantity: qty}}
+	if batchID == nil && isBatchTracked {
+		allocations, err := s.stockPostingService.PickBatchesFEFO(tx, order.WarehouseID, productID, qty)
+		if err != nil {
+			return nil, err
+		}
+		picks = picks[:0]
+		for _, alloc := range allocations {
+			id := alloc.Batch.ID
+			picks = append(picks, pick{BatchID: &id, Quantity: alloc.Quantity})
+		}
+	}
+
+	consumed := make([]consumption, 0, len(picks))
+	for _, p := range picks {
+		results, err := s.stockPostingService.PostFromBins(tx, &inventory.StockPosting{
+			TenantID:        order.TenantID,
+			CompanyID:       order.CompanyID,
+			WarehouseID:     order.WarehouseID,
+			ProductID:       productID,
+			MovementType:    models.MovementTypeAssembly,
+			Quantity:        p.Quantity.Neg(),
+			MovementDate:    movementDate,
+			BatchID:         p.BatchID,
+			ReferenceType:   inventory.ReferenceTypeAssemblyOrder,
+			ReferenceID:     order.ID,
+			ReferenceNumber: order.OrderNumber,
+			Notes:           order.Notes,
+			CreatedBy:       userID,
+
+			RespectReservations: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		c := consumption{BatchID: p.BatchID, Quantity: p.Quantity, Cost: decimal.Zero}
+		for _, result := range results {
+			c.Cost = c.Cost.Add(result.Movement.TotalCost.Abs())
+			if result.Batch != nil {
+				c.ExpiryDate = result.Batch.ExpiryDate
+			}
+		}
+		consumed = append(consumed, c)
+	}
+
+	return consumed, nil
+}
+
+// recordConsumption stores the batch and cost consumed for a component line, splitting the
+// line when FEFO picked more than one batch so each row points at exactly one batch
+func (s *AssemblyService) recordConsumption(tx *gorm.DB, item *models.AssemblyOrderItem, consumed []consumption) error {
+	unit := uom.FromProductUnit(item.ProductUnit)
+	for i, c := range consumed {
+		unitCost := c.Cost.Div(c.Quantity).Round(4)
+		if i == 0 {
+			if err := tx.Model(&models.AssemblyOrderItem{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+				"batch_id":      c.BatchID,
+				"quantity":      unit.FromBase(c.Quantity),
+				"base_quantity": c.Quantity,
+				"unit_cost":     unitCost,
+				"total_cost":    c.Cost.Round(2),
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update assembly order item: %w", err)
+			}
+			continue
+		}
+
+		splitItem := &models.AssemblyOrderItem{
+			AssemblyOrderID: item.AssemblyOrderID,
+			ProductID:       item.ProductID,
+			ProductUnitID:   item.ProductUnitID,
+			BatchID:         c.BatchID,
+			Quantity:        unit.FromBase(c.Quantity),
+			BaseQuantity:    c.Quantity,
+			UnitCost:        unitCost,
+			TotalCost:       c.Cost.Round(2),
+		}
+		if err := tx.Create(splitItem).Error; err != nil {
+			return fmt.Errorf("failed to split assembly order item by batch: %w", err)
+		}
+	}
+	return nil
+}
+
+// componentCost returns the current cost per base unit of a component in the warehouse,
+// or the product base cost when the warehouse holds none
+func (s *AssemblyService) componentCost(tx *gorm.DB, warehouseID string, product *models.Product) (decimal.Decimal, error) {
+	var stock models.WarehouseStock
+	err := tx.Where("warehouse_id = ? AND product_id = ?", warehouseID, product.ID).First(&stock).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return decimal.Zero, fmt.Errorf("failed to load component stock: %w", err)
+	}
+	if err == nil && stock.AverageCost.IsPositive() {
+		return stock.AverageCost, nil
+	}
+	return product.BaseCost, nil
+}
+
+// generateOrderNumber returns the next order number: ASM-YYYY-00001 for assembly,
+// DSM-YYYY-00001 for disassembly
+func (s *AssemblyService) generateOrderNumber(tx *gorm.DB, tenantID, companyID string, orderType models.AssemblyOrderType, orderDate time.Time) (string, error) {
+	code := "ASM"
+	if orderType == models.AssemblyOrderTypeDisassembly {
+		code = "DSM"
+	}
+	prefix := fmt.Sprintf("%s-%d-", code, orderDate.Year())
+
+	var count int64
+	if err := tx.Model(&models.AssemblyOrder{}).
+		Where("company_id = ? AND tenant_id = ? AND order_number LIKE ?", companyID, tenantID, prefix+"%").
+		Count(&count).Error; err != nil {
+		return "", pkgerrors.NewInternalError(err)
+	}
+
+	return fmt.Sprintf("%s%05d", prefix, count+1), nil
+}
@@ -0,0 +1,174 @@
+package assembly
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssemblyService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.ProductBOMComponent{},
+		&models.AssemblyOrder{},
+		&models.AssemblyOrderItem{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+
+	hamper := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "HMP001", Name: "Parcel Lebaran", BaseUnit: "PCS", IsBatchTracked: true, IsActive: true}
+	require.NoError(t, db.Create(hamper).Error)
+	biscuit := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD001", Name: "Biskuit Kaleng", BaseUnit: "PCS", IsBatchTracked: true, IsActive: true}
+	require.NoError(t, db.Create(biscuit).Error)
+	syrup := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD002", Name: "Sirup Melon", BaseUnit: "BTL", IsActive: true}
+	require.NoError(t, db.Create(syrup).Error)
+	loose := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD003", Name: "Kurma 500g", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(loose).Error)
+
+	require.NoError(t, db.Create(&models.ProductBOMComponent{ProductID: hamper.ID, ComponentProductID: biscuit.ID, Quantity: decimal.NewFromInt(2), BaseQuantity: decimal.NewFromInt(2)}).Error)
+	require.NoError(t, db.Create(&models.ProductBOMComponent{ProductID: hamper.ID, ComponentProductID: syrup.ID, Quantity: decimal.NewFromInt(1), BaseQuantity: decimal.NewFromInt(1)}).Error)
+
+	stockPostingService := inventory.NewStockPostingService(db)
+	receive := func(productID string, qty, cost int64, batchNumber string, expiry *time.Time) {
+		unitCost := decimal.NewFromInt(cost)
+		posting := &inventory.StockPosting{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			WarehouseID:   warehouse.ID,
+			ProductID:     productID,
+			MovementType:  models.MovementTypeIn,
+			Quantity:      decimal.NewFromInt(qty),
+			UnitCost:      &unitCost,
+			ReferenceType: inventory.ReferenceTypeGoodsReceipt,
+			ReferenceID:   "grn-1",
+		}
+		if batchNumber != "" {
+			posting.Batch = &inventory.BatchDetails{BatchNumber: batchNumber, ExpiryDate: expiry}
+		}
+		_, err := stockPostingService.Post(db, posting)
+		require.NoError(t, err)
+	}
+	earlyExpiry := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	lateExpiry := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	receive(biscuit.ID, 3, 10000, "BSK-01", &earlyExpiry)
+	receive(biscuit.ID, 10, 10000, "BSK-02", &lateExpiry)
+	receive(syrup.ID, 10, 20000, "", nil)
+
+	service := NewAssemblyService(db, stockPostingService)
+
+	stockOf := func(productID string) decimal.Decimal {
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, productID).First(&stock).Error)
+		return stock.Quantity
+	}
+
+	create := func(orderType string, productID string, qty string) (*models.AssemblyOrder, error) {
+		return service.CreateAssemblyOrder(ctx, company.TenantID, company.ID, "user-1", &dto.CreateAssemblyOrderRequest{
+			Type:        orderType,
+			OrderDate:   "2026-10-01",
+			WarehouseID: warehouse.ID,
+			ProductID:   productID,
+			Quantity:    qty,
+		})
+	}
+
+	t.Run("error - product without a bill of materials", func(t *testing.T) {
+		_, err := create("ASSEMBLY", loose.ID, "1")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has no bill of materials")
+	})
+
+	t.Run("error - insufficient components leave the order in DRAFT", func(t *testing.T) {
+		order, err := create("ASSEMBLY", hamper.ID, "20")
+		require.NoError(t, err)
+
+		_, err = service.CompleteAssemblyOrder(ctx, company.TenantID, company.ID, order.ID, "user-1")
+		require.Error(t, err)
+
+		order, err = service.CancelAssemblyOrder(ctx, company.TenantID, company.ID, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AssemblyOrderStatusCancelled, order.Status)
+		assert.Equal(t, "13", stockOf(biscuit.ID).String())
+		assert.Equal(t, "10", stockOf(syrup.ID).String())
+	})
+
+	var assembled *models.AssemblyOrder
+	t.Run("success - assembly consumes components FEFO and rolls up their cost", func(t *testing.T) {
+		order, err := create("ASSEMBLY", hamper.ID, "2")
+		require.NoError(t, err)
+		assert.Equal(t, "ASM-2026-00002", order.OrderNumber)
+		require.Len(t, order.Items, 2)
+		assert.Equal(t, "4", order.Items[0].BaseQuantity.String())
+
+		assembled, err = service.CompleteAssemblyOrder(ctx, company.TenantID, company.ID, order.ID, "user-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, models.AssemblyOrderStatusCompleted, assembled.Status)
+		// Biscuits: 3 from BSK-01 + 1 from BSK-02 at 10.000, syrup: 2 × 20.000
+		assert.Equal(t, "80000", assembled.TotalCost.String())
+		assert.Equal(t, "40000", assembled.UnitCost.String())
+		require.Len(t, assembled.Items, 3)
+		require.NotNil(t, assembled.Batch)
+		assert.Equal(t, assembled.OrderNumber, assembled.Batch.BatchNumber)
+		require.NotNil(t, assembled.ExpiryDate)
+		assert.Equal(t, "2027-01-31", assembled.ExpiryDate.Format("2006-01-02"))
+
+		assert.Equal(t, "2", stockOf(hamper.ID).String())
+		assert.Equal(t, "9", stockOf(biscuit.ID).String())
+		assert.Equal(t, "8", stockOf(syrup.ID).String())
+
+		var movements int64
+		require.NoError(t, db.Model(&models.InventoryMovement{}).
+			Where("reference_type = ? AND reference_id = ?", inventory.ReferenceTypeAssemblyOrder, order.ID).
+			Count(&movements).Error)
+		assert.Equal(t, int64(4), movements)
+	})
+
+	t.Run("error - completed order cannot be completed again", func(t *testing.T) {
+		_, err := service.CompleteAssemblyOrder(ctx, company.TenantID, company.ID, assembled.ID, "user-1")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only DRAFT")
+	})
+
+	t.Run("success - disassembly returns the components at the finished cost", func(t *testing.T) {
+		order, err := create("DISASSEMBLY", hamper.ID, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "DSM-2026-00001", order.OrderNumber)
+
+		order, err = service.CompleteAssemblyOrder(ctx, company.TenantID, company.ID, order.ID, "user-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "40000", order.TotalCost.String())
+		require.NotNil(t, order.BatchID)
+		assert.Equal(t, *assembled.BatchID, *order.BatchID)
+
+		allocated := decimal.Zero
+		for _, item := range order.Items {
+			allocated = allocated.Add(item.TotalCost)
+		}
+		assert.Equal(t, "40000", allocated.String())
+		require.NotNil(t, order.Items[0].Batch)
+		assert.Equal(t, order.OrderNumber, order.Items[0].Batch.BatchNumber)
+
+		assert.Equal(t, "1", stockOf(hamper.ID).String())
+		assert.Equal(t, "11", stockOf(biscuit.ID).String())
+		assert.Equal(t, "9", stockOf(syrup.ID).String())
+	})
+}
@@ -2251,6 +2251,48 @@ func (s *AuditService) LogInventoryAdjustmentOperationFailed(
 	return db.Create(auditLog).Error
 }
 
+// ==================== Stock Reconciliation Audit Methods ====================
+
+// LogStockReconciliationRepaired logs the corrections posted by a stock reconciliation run
+func (s *AuditService) LogStockReconciliationRepaired(
+	ctx context.Context,
+	auditCtx *AuditContext,
+	runID string,
+	referenceNumber string,
+	corrections []map[string]interface{},
+	runNotes *string,
+) error {
+	newValuesJSON, _ := json.Marshal(map[string]interface{}{"corrections": corrections})
+	newValuesStr := string(newValuesJSON)
+	entityType := "STOCK_RECONCILIATION"
+
+	notes := fmt.Sprintf("Stock reconciliation %s repaired %d discrepancies", referenceNumber, len(corrections))
+	if runNotes != nil && *runNotes != "" {
+		notes = fmt.Sprintf("%s. Notes: %s", notes, *runNotes)
+	}
+
+	auditLog := &models.AuditLog{
+		TenantID:   auditCtx.TenantID,
+		CompanyID:  auditCtx.CompanyID,
+		UserID:     auditCtx.UserID,
+		RequestID:  auditCtx.RequestID,
+		Action:     "STOCK_RECONCILIATION_REPAIRED",
+		EntityType: &entityType,
+		EntityID:   &runID,
+		NewValues:  &newValuesStr,
+		Status:     StatusSuccess,
+		IPAddress:  auditCtx.IPAddress,
+		UserAgent:  auditCtx.UserAgent,
+		Notes:      &notes,
+	}
+
+	db := s.db.WithContext(ctx)
+	if auditCtx.TenantID != nil {
+		db = db.Set("tenant_id", *auditCtx.TenantID)
+	}
+	return db.Create(auditLog).Error
+}
+
 // ==================== Purchase Order Audit Methods ====================
 
 // LogPurchaseOrderCreated logs when a purchase order is created
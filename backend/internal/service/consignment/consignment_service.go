@@ -0,0 +1,639 @@
+package consignment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ConsignmentService - Stock held at customer sites and sell-through settlements.
+// Stock moved to a CONSIGNMENT warehouse (by stock transfer) stays our inventory and is not invoiced.
+// The customer periodically reports what they sold or still hold; settling that report invoices the
+// sold quantity and posts the matching stock-out from the consignment warehouse.
+type ConsignmentService struct {
+	db                  *gorm.DB
+	docNumberGen        *document.DocumentNumberGenerator
+	stockPostingService *inventory.StockPostingService
+}
+
+// NewConsignmentService creates a new consignment service instance
+func NewConsignmentService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator, stockPostingService *inventory.StockPostingService) *ConsignmentService {
+	return &ConsignmentService{
+		db:                  db,
+		docNumberGen:        docNumberGen,
+		stockPostingService: stockPostingService,
+	}
+}
+
+// settlementLine - A validated report line with the quantity to invoice and take out of stock
+type settlementLine struct {
+	Product     models.Product
+	BatchID     *string
+	ReportedQty decimal.Decimal
+	StockQty    decimal.Decimal
+	SoldQty     decimal.Decimal
+	UnitPrice   decimal.Decimal
+}
+
+// ============================================================================
+// SETTLEMENTS
+// ============================================================================
+
+// CreateSettlement settles a customer's report for a consignment warehouse.
+// SOLD reports give the quantity sold; ON_HAND reports give what the customer still holds, and the
+// rest of the listed product's consignment stock is taken as sold. Products not listed are left as they are.
+// The sold quantity is invoiced to the warehouse's customer and posted out of the warehouse.
+func (s *ConsignmentService) CreateSettlement(
+	ctx context.Context,
+	tenantID, companyID, userID string,
+	req *dto.CreateConsignmentSettlementRequest,
+) (*models.ConsignmentSettlement, error) {
+	settlementDate, err := time.Parse("2006-01-02", req.SettlementDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid settlementDate format, expected YYYY-MM-DD")
+	}
+	reportType := models.ConsignmentReportType(req.ReportType)
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).Session(&gorm.Session{})
+
+	var warehouse models.Warehouse
+	if err := db.Preload("Customer").
+		Where("id = ? AND company_id = ?", req.WarehouseID, companyID).
+		First(&warehouse).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Warehouse")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	if warehouse.Type != models.WarehouseTypeConsignment || warehouse.Customer == nil {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Warehouse %s is not a consignment warehouse linked to a customer", warehouse.Code))
+	}
+	customer := warehouse.Customer
+
+	dueDate := settlementDate.AddDate(0, 0, customer.PaymentTerm)
+	if req.DueDate != nil && *req.DueDate != "" {
+		dueDate, err = time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid dueDate format, expected YYYY-MM-DD")
+		}
+	}
+
+	var company models.Company
+	if err := db.Where("id = ?", companyID).First(&company).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	lines, err := s.settlementLines(db, &warehouse, reportType, settlementDate, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	subtotal := decimal.Zero
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.SoldQty.Mul(line.UnitPrice).Round(2))
+	}
+	taxAmount := decimal.Zero
+	if company.IsPKP {
+		taxAmount = subtotal.Mul(company.PPNRate).Div(decimal.NewFromInt(100)).Round(2)
+	}
+
+	var invoiceNumber string
+	if hasSoldQty(lines) {
+		invoiceNumber, err = s.docNumberGen.GenerateNumber(ctx, tenantID, companyID, document.DocTypeSalesInvoice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate invoice number: %w", err)
+		}
+	}
+
+	var settlement *models.ConsignmentSettlement
+	err = db.Transaction(func(tx *gorm.DB) error {
+		settlementNumber, err := s.generateSettlementNumber(tx, tenantID, companyID, settlementDate)
+		if err != nil {
+			return err
+		}
+
+		settlement = &models.ConsignmentSettlement{
+			TenantID:         tenantID,
+			CompanyID:        companyID,
+			SettlementNumber: settlementNumber,
+			SettlementDate:   settlementDate,
+			CustomerID:       customer.ID,
+			WarehouseID:      warehouse.ID,
+			ReportType:       reportType,
+			Subtotal:         subtotal,
+			TaxAmount:        taxAmount,
+			TotalAmount:      subtotal.Add(taxAmount),
+			Notes:            req.Notes,
+		}
+		if userID != "" {
+			settlement.CreatedBy = &userID
+		}
+		if err := tx.Create(settlement).Error; err != nil {
+			return pkgerrors.NewInternalError(err)
+		}
+
+		// 1. Settlement sales invoice for the sold quantity
+		var invoice *models.Invoice
+		if invoiceNumber != "" {
+			invoiceNotes := fmt.Sprintf("Consignment settlement %s (%s)", settlement.SettlementNumber, warehouse.Code)
+			invoice = &models.Invoice{
+				TenantID:      tenantID,
+				CompanyID:     companyID,
+				InvoiceNumber: invoiceNumber,
+				InvoiceDate:   settlementDate,
+				DueDate:       dueDate,
+				CustomerID:    customer.ID,
+				Subtotal:      settlement.Subtotal,
+				TaxAmount:     settlement.TaxAmount,
+				TotalAmount:   settlement.TotalAmount,
+				PaidAmount:    decimal.Zero,
+				PaymentStatus: models.PaymentStatusUnpaid,
+				Notes:         &invoiceNotes,
+			}
+			if err := tx.Create(invoice).Error; err != nil {
+				return fmt.Errorf("failed to create settlement invoice: %w", err)
+			}
+			settlement.InvoiceID = &invoice.ID
+		}
+
+		// 2. Items: invoice line and stock-out of the sold quantity
+		costAmount := decimal.Zero
+		for _, line := range lines {
+			item := &models.ConsignmentSettlementItem{
+				ConsignmentSettlementID: settlement.ID,
+				ProductID:               line.Product.ID,
+				BatchID:                 line.BatchID,
+				ReportedQty:             line.ReportedQty,
+				StockQty:                line.StockQty,
+				SoldQty:                 line.SoldQty,
+				UnitPrice:               line.UnitPrice,
+				Subtotal:                line.SoldQty.Mul(line.UnitPrice).Round(2),
+			}
+
+			if line.SoldQty.IsPositive() {
+				invoiceItem := &models.InvoiceItem{
+					InvoiceID:    invoice.ID,
+					ProductID:    line.Product.ID,
+					Quantity:     line.SoldQty,
+					BaseQuantity: line.SoldQty,
+					UnitPrice:    line.UnitPrice,
+					Subtotal:     item.Subtotal,
+				}
+				if err := tx.Create(invoiceItem).Error; err != nil {
+					return fmt.Errorf("failed to create settlement invoice item: %w", err)
+				}
+				item.InvoiceItemID = &invoiceItem.ID
+
+				lineCost, err := s.postStockOut(tx, settlement, &warehouse, line)
+				if err != nil {
+					return err
+				}
+				item.UnitCost = lineCost.Div(line.SoldQty).Round(4)
+				costAmount = costAmount.Add(lineCost)
+			}
+
+			if err := tx.Create(item).Error; err != nil {
+				return pkgerrors.NewInternalError(err)
+			}
+		}
+
+		return tx.Model(settlement).Updates(map[string]interface{}{
+			"invoice_id":  settlement.InvoiceID,
+			"cost_amount": costAmount.Round(2),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSettlementByID(ctx, tenantID, companyID, settlement.ID)
+}
+
+// settlementLines validates the reported lines against the consignment stock and works out the sold quantity
+func (s *ConsignmentService) settlementLines(
+	db *gorm.DB,
+	warehouse *models.Warehouse,
+	reportType models.ConsignmentReportType,
+	settlementDate time.Time,
+	items []dto.CreateConsignmentSettlementItemRequest,
+) ([]settlementLine, error) {
+	lines := make([]settlementLine, 0, len(items))
+	seen := make(map[string]bool, len(items))
+
+	for _, itemReq := range items {
+		reportedQty, err := decimal.NewFromString(itemReq.Quantity)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid quantity %q", itemReq.Quantity))
+		}
+		if reportedQty.IsNegative() {
+			return nil, pkgerrors.NewBadRequestError("quantity cannot be negative")
+		}
+
+		var product models.Product
+		if err := db.Where("id = ? AND company_id = ?", itemReq.ProductID, warehouse.CompanyID).First(&product).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, pkgerrors.NewNotFoundError("Product")
+			}
+			return nil, pkgerrors.NewInternalError(err)
+		}
+
+		key := product.ID
+		if itemReq.BatchID != nil && *itemReq.BatchID != "" {
+			key += "/" + *itemReq.BatchID
+		}
+		if seen[key] {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Product %s is reported more than once", product.Code))
+		}
+		seen[key] = true
+
+		line := settlementLine{Product: product, ReportedQty: reportedQty}
+
+		// Consignment stock of the product (or batch) before the settlement
+		if itemReq.BatchID != nil && *itemReq.BatchID != "" {
+			var batch models.ProductBatch
+			if err := db.Model(&models.ProductBatch{}).
+				Select("product_batches.*").
+				Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+				Where("product_batches.id = ? AND product_batches.product_id = ? AND warehouse_stocks.warehouse_id = ?",
+					*itemReq.BatchID, product.ID, warehouse.ID).
+				First(&batch).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Batch of product %s is not in warehouse %s", product.Code, warehouse.Code))
+				}
+				return nil, pkgerrors.NewInternalError(err)
+			}
+			line.BatchID = &batch.ID
+			line.StockQty = batch.Quantity
+		} else {
+			var stock models.WarehouseStock
+			err := db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return nil, pkgerrors.NewInternalError(err)
+			}
+			line.StockQty = stock.Quantity
+		}
+
+		switch reportType {
+		case models.ConsignmentReportTypeSold:
+			if reportedQty.GreaterThan(line.StockQty) {
+				return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Sold qty %s of product %s exceeds consignment stock %s",
+					reportedQty.String(), product.Code, line.StockQty.String()))
+			}
+			line.SoldQty = reportedQty
+		case models.ConsignmentReportTypeOnHand:
+			if reportedQty.GreaterThan(line.StockQty) {
+				return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("On-hand qty %s of product %s exceeds consignment stock %s",
+					reportedQty.String(), product.Code, line.StockQty.String()))
+			}
+			line.SoldQty = line.StockQty.Sub(reportedQty)
+		default:
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("unsupported report type %s", reportType))
+		}
+
+		if itemReq.UnitPrice != nil && *itemReq.UnitPrice != "" {
+			line.UnitPrice, err = decimal.NewFromString(*itemReq.UnitPrice)
+			if err != nil || line.UnitPrice.IsNegative() {
+				return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid unit price %q", *itemReq.UnitPrice))
+			}
+		} else {
+			line.UnitPrice, err = s.unitPrice(db, *warehouse.CustomerID, &product, line.SoldQty, settlementDate)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// postStockOut takes the sold quantity out of the consignment warehouse and returns its inventory cost.
+// Batch-tracked products reported without a batch are picked FEFO.
+func (s *ConsignmentService) postStockOut(tx *gorm.DB, settlement *models.ConsignmentSettlement, warehouse *models.Warehouse, line settlementLine) (decimal.Decimal, error) {
+	notes := fmt.Sprintf("Consignment sale reported by customer (%s)", settlement.ReportType)
+	posting := &inventory.StockPosting{
+		TenantID:        settlement.TenantID,
+		CompanyID:       settlement.CompanyID,
+		WarehouseID:     warehouse.ID,
+		ProductID:       line.Product.ID,
+		MovementType:    models.MovementTypeOut,
+		Quantity:        line.SoldQty.Neg(),
+		MovementDate:    settlement.SettlementDate,
+		BatchID:         line.BatchID,
+		ReferenceType:   inventory.ReferenceTypeConsignmentSettlement,
+		ReferenceID:     settlement.ID,
+		ReferenceNumber: settlement.SettlementNumber,
+		Notes:           &notes,
+	}
+	if settlement.CreatedBy != nil {
+		posting.CreatedBy = *settlement.CreatedBy
+	}
+
+	var results []*inventory.PostingResult
+	if line.BatchID != nil || !line.Product.IsBatchTracked {
+		posted, err := s.stockPostingService.PostFromBins(tx, posting)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		results = append(results, posted...)
+	} else {
+		allocations, err := s.stockPostingService.PickBatchesFEFO(tx, warehouse.ID, line.Product.ID, line.SoldQty)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		for _, alloc := range allocations {
+			batchID := alloc.Batch.ID
+			posting.BatchID = &batchID
+			posting.Quantity = alloc.Quantity.Neg()
+			posted, err := s.stockPostingService.PostFromBins(tx, posting)
+			if err != nil {
+				return decimal.Zero, err
+			}
+			results = append(results, posted...)
+		}
+	}
+
+	cost := decimal.Zero
+	for _, result := range results {
+		cost = cost.Sub(result.Movement.TotalCost) // Outbound cost is signed negative
+	}
+	return cost, nil
+}
+
+// unitPrice returns the customer's price list price for the product on the date, then the
+// default price list price, then the product's base price
+func (s *ConsignmentService) unitPrice(db *gorm.DB, customerID string, product *models.Product, qty decimal.Decimal, date time.Time) (decimal.Decimal, error) {
+	var prices []models.PriceList
+	if err := db.Where("product_id = ? AND is_active = ? AND (customer_id = ? OR customer_id IS NULL)", product.ID, true, customerID).
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)", date, date).
+		Where("min_qty <= ?", qty).
+		Order("min_qty DESC, effective_from DESC").
+		Find(&prices).Error; err != nil {
+		return decimal.Zero, pkgerrors.NewInternalError(err)
+	}
+
+	for _, price := range prices {
+		if price.CustomerID != nil {
+			return price.Price, nil
+		}
+	}
+	if len(prices) > 0 {
+		return prices[0].Price, nil
+	}
+	return product.BasePrice, nil
+}
+
+// GetSettlementByID retrieves a settlement with its items and invoice
+func (s *ConsignmentService) GetSettlementByID(ctx context.Context, tenantID, companyID, settlementID string) (*models.ConsignmentSettlement, error) {
+	var settlement models.ConsignmentSettlement
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Customer").
+		Preload("Warehouse").
+		Preload("Invoice").
+		Preload("Items.Product").
+		Preload("Items.Batch").
+		Where("id = ? AND company_id = ?", settlementID, companyID).
+		First(&settlement).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Consignment settlement")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	return &settlement, nil
+}
+
+// ListSettlements retrieves settlements with filtering and pagination
+func (s *ConsignmentService) ListSettlements(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.ConsignmentSettlementQuery,
+) ([]models.ConsignmentSettlement, *dto.PaginationInfo, error) {
+	var settlements []models.ConsignmentSettlement
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("company_id = ?", companyID)
+
+	if query.CustomerID != nil {
+		db = db.Where("customer_id = ?", *query.CustomerID)
+	}
+	if query.WarehouseID != nil {
+		db = db.Where("warehouse_id = ?", *query.WarehouseID)
+	}
+	if query.DateFrom != nil && *query.DateFrom != "" {
+		dateFrom, err := time.Parse("2006-01-02", *query.DateFrom)
+		if err != nil {
+			return nil, nil, pkgerrors.NewBadRequestError("invalid date_from format, expected YYYY-MM-DD")
+		}
+		db = db.Where("settlement_date >= ?", dateFrom)
+	}
+	if query.DateTo != nil && *query.DateTo != "" {
+		dateTo, err := time.Parse("2006-01-02", *query.DateTo)
+		if err != nil {
+			return nil, nil, pkgerrors.NewBadRequestError("invalid date_to format, expected YYYY-MM-DD")
+		}
+		db = db.Where("settlement_date < ?", dateTo.AddDate(0, 0, 1))
+	}
+	if query.Search != "" {
+		db = db.Where("settlement_number LIKE ?", "%"+query.Search+"%")
+	}
+
+	if err := db.Model(&models.ConsignmentSettlement{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Order("settlement_date DESC, settlement_number DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Customer").
+		Preload("Warehouse").
+		Preload("Invoice").
+		Find(&settlements).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return settlements, pagination, nil
+}
+
+// ============================================================================
+// BALANCE REPORT
+// ============================================================================
+
+// GetBalanceReport lists per customer the stock still held in their consignment warehouses,
+// valued at average cost, with a summary of their settlements
+func (s *ConsignmentService) GetBalanceReport(ctx context.Context, tenantID, companyID string, query *dto.ConsignmentBalanceQuery) (*dto.ConsignmentBalanceResponse, error) {
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).Session(&gorm.Session{})
+
+	warehouseQuery := db.Preload("Customer").
+		Where("company_id = ? AND type = ? AND customer_id IS NOT NULL", companyID, models.WarehouseTypeConsignment)
+	if query != nil && query.CustomerID != nil {
+		warehouseQuery = warehouseQuery.Where("customer_id = ?", *query.CustomerID)
+	}
+	var warehouses []models.Warehouse
+	if err := warehouseQuery.Order("code ASC").Find(&warehouses).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	response := &dto.ConsignmentBalanceResponse{
+		Customers:       []dto.ConsignmentCustomerBalance{},
+		TotalStockValue: "0",
+	}
+	if len(warehouses) == 0 {
+		return response, nil
+	}
+
+	warehouseIDs := make([]string, len(warehouses))
+	warehouseByID := make(map[string]*models.Warehouse, len(warehouses))
+	for i := range warehouses {
+		warehouseIDs[i] = warehouses[i].ID
+		warehouseByID[warehouses[i].ID] = &warehouses[i]
+	}
+
+	var stocks []models.WarehouseStock
+	if err := db.Preload("Product").
+		Joins("JOIN products ON products.id = warehouse_stocks.product_id").
+		Where("warehouse_stocks.warehouse_id IN ? AND warehouse_stocks.quantity <> 0", warehouseIDs).
+		Order("products.code ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	type customerTotals struct {
+		balance    dto.ConsignmentCustomerBalance
+		quantity   decimal.Decimal
+		stockValue decimal.Decimal
+	}
+	byCustomer := map[string]*customerTotals{}
+	for _, warehouse := range warehouses {
+		customerID := *warehouse.CustomerID
+		if _, ok := byCustomer[customerID]; ok {
+			continue
+		}
+		totals := &customerTotals{balance: dto.ConsignmentCustomerBalance{
+			CustomerID:    customerID,
+			SettledAmount: "0",
+			Lines:         []dto.ConsignmentBalanceLine{},
+		}}
+		if warehouse.Customer != nil {
+			totals.balance.CustomerCode = warehouse.Customer.Code
+			totals.balance.CustomerName = warehouse.Customer.Name
+		}
+		byCustomer[customerID] = totals
+	}
+
+	grandTotal := decimal.Zero
+	for _, stock := range stocks {
+		warehouse := warehouseByID[stock.WarehouseID]
+		totals := byCustomer[*warehouse.CustomerID]
+		value := stock.Quantity.Mul(stock.AverageCost).Round(2)
+
+		totals.balance.Lines = append(totals.balance.Lines, dto.ConsignmentBalanceLine{
+			WarehouseID:   warehouse.ID,
+			WarehouseCode: warehouse.Code,
+			WarehouseName: warehouse.Name,
+			ProductID:     stock.ProductID,
+			ProductCode:   stock.Product.Code,
+			ProductName:   stock.Product.Name,
+			BaseUnit:      stock.Product.BaseUnit,
+			Quantity:      stock.Quantity.String(),
+			UnitCost:      stock.AverageCost.String(),
+			StockValue:    value.String(),
+		})
+		totals.quantity = totals.quantity.Add(stock.Quantity)
+		totals.stockValue = totals.stockValue.Add(value)
+		grandTotal = grandTotal.Add(value)
+	}
+
+	// Settlement summary per customer
+	var summaries []struct {
+		CustomerID    string
+		Count         int
+		SettledAmount decimal.Decimal
+	}
+	if err := db.Model(&models.ConsignmentSettlement{}).
+		Select("customer_id, COUNT(*) as count, COALESCE(SUM(total_amount), 0) as settled_amount").
+		Where("company_id = ? AND warehouse_id IN ?", companyID, warehouseIDs).
+		Group("customer_id").
+		Scan(&summaries).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	for _, summary := range summaries {
+		if totals, ok := byCustomer[summary.CustomerID]; ok {
+			totals.balance.SettlementCount = summary.Count
+			totals.balance.SettledAmount = summary.SettledAmount.String()
+		}
+	}
+
+	for customerID, totals := range byCustomer {
+		var last models.ConsignmentSettlement
+		err := db.Where("company_id = ? AND customer_id = ?", companyID, customerID).
+			Order("settlement_date DESC, settlement_number DESC").
+			First(&last).Error
+		if err == nil {
+			lastDate := last.SettlementDate.Format("2006-01-02")
+			totals.balance.LastSettlementNumber = &last.SettlementNumber
+			totals.balance.LastSettlementDate = &lastDate
+		} else if err != gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewInternalError(err)
+		}
+
+		totals.balance.TotalQuantity = totals.quantity.String()
+		totals.balance.TotalStockValue = totals.stockValue.String()
+		response.Customers = append(response.Customers, totals.balance)
+	}
+
+	sort.Slice(response.Customers, func(i, j int) bool {
+		return response.Customers[i].CustomerCode < response.Customers[j].CustomerCode
+	})
+	response.TotalStockValue = grandTotal.String()
+
+	return response, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// generateSettlementNumber generates unique settlement number for company
+func (s *ConsignmentService) generateSettlementNumber(tx *gorm.DB, tenantID, companyID string, settlementDate time.Time) (string, error) {
+	var count int64
+	prefix := fmt.Sprintf("CSG-%d-", settlementDate.Year())
+
+	if err := tx.Model(&models.ConsignmentSettlement{}).
+		Where("company_id = ? AND tenant_id = ? AND settlement_number LIKE ?", companyID, tenantID, prefix+"%").
+		Count(&count).Error; err != nil {
+		return "", pkgerrors.NewInternalError(err)
+	}
+
+	return fmt.Sprintf("%s%05d", prefix, count+1), nil
+}
+
+// hasSoldQty reports whether any line sells stock
+func hasSoldQty(lines []settlementLine) bool {
+	for _, line := range lines {
+		if line.SoldQty.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
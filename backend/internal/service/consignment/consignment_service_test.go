@@ -0,0 +1,209 @@
+package consignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsignmentService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.PriceList{},
+		&models.Invoice{},
+		&models.InvoiceItem{},
+		&models.ConsignmentSettlement{},
+		&models.ConsignmentSettlementItem{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	// Invoice-format numbers end in the month and year issued
+	period := time.Now().Format("01/2006")
+
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", PaymentTerm: 30, IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+
+	mainWarehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	consignmentWarehouse := &models.Warehouse{
+		TenantID:   company.TenantID,
+		CompanyID:  company.ID,
+		Code:       "CSG-MAKMUR",
+		Name:       "Titipan Toko Makmur",
+		Type:       models.WarehouseTypeConsignment,
+		CustomerID: &customer.ID,
+		IsActive:   true,
+	}
+	require.NoError(t, db.Create(consignmentWarehouse).Error)
+
+	product := &models.Product{
+		TenantID:  company.TenantID,
+		CompanyID: company.ID,
+		Code:      "PROD001",
+		Name:      "Minyak Goreng 1L",
+		BaseUnit:  "PCS",
+		BasePrice: decimal.NewFromInt(10000),
+		IsActive:  true,
+	}
+	require.NoError(t, db.Create(product).Error)
+	require.NoError(t, db.Create(&models.PriceList{
+		ProductID:     product.ID,
+		CustomerID:    &customer.ID,
+		Price:         decimal.NewFromInt(12000),
+		EffectiveFrom: time.Now().AddDate(0, -1, 0),
+		IsActive:      true,
+	}).Error)
+
+	stockPostingService := inventory.NewStockPostingService(db)
+	unitCost := decimal.NewFromInt(5000)
+	_, err := stockPostingService.Post(db, &inventory.StockPosting{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		WarehouseID:   consignmentWarehouse.ID,
+		ProductID:     product.ID,
+		MovementType:  models.MovementTypeTransfer,
+		Quantity:      decimal.NewFromInt(10),
+		UnitCost:      &unitCost,
+		ReferenceType: inventory.ReferenceTypeStockTransfer,
+		ReferenceID:   "transfer-1",
+	})
+	require.NoError(t, err)
+
+	service := NewConsignmentService(db, document.NewDocumentNumberGenerator(db), stockPostingService)
+	today := time.Now().Format("2006-01-02")
+
+	stockQty := func() string {
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", consignmentWarehouse.ID, product.ID).First(&stock).Error)
+		return stock.Quantity.String()
+	}
+
+	t.Run("success - sold report invoices the customer and takes the stock out", func(t *testing.T) {
+		settlement, err := service.CreateSettlement(ctx, company.TenantID, company.ID, "user-1", &dto.CreateConsignmentSettlementRequest{
+			WarehouseID:    consignmentWarehouse.ID,
+			SettlementDate: today,
+			ReportType:     string(models.ConsignmentReportTypeSold),
+			Items:          []dto.CreateConsignmentSettlementItemRequest{{ProductID: product.ID, Quantity: "3"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "CSG-"+today[:4]+"-00001", settlement.SettlementNumber)
+		assert.Equal(t, customer.ID, settlement.CustomerID)
+		assert.Equal(t, "36000", settlement.Subtotal.String())
+		assert.Equal(t, "3960", settlement.TaxAmount.String()) // PPN 11%
+		assert.Equal(t, "39960", settlement.TotalAmount.String())
+		assert.Equal(t, "15000", settlement.CostAmount.String())
+		require.Len(t, settlement.Items, 1)
+		assert.Equal(t, "10", settlement.Items[0].StockQty.String())
+		assert.Equal(t, "3", settlement.Items[0].SoldQty.String())
+		assert.Equal(t, "12000", settlement.Items[0].UnitPrice.String()) // Customer price list
+		assert.Equal(t, "5000", settlement.Items[0].UnitCost.String())
+
+		require.NotNil(t, settlement.Invoice)
+		assert.Equal(t, "INV/0001/"+period, settlement.Invoice.InvoiceNumber)
+		assert.Equal(t, customer.ID, settlement.Invoice.CustomerID)
+		assert.Equal(t, "39960", settlement.Invoice.TotalAmount.String())
+		assert.Equal(t, settlement.SettlementDate.AddDate(0, 0, 30), settlement.Invoice.DueDate)
+		var invoiceItems []models.InvoiceItem
+		require.NoError(t, db.Where("invoice_id = ?", settlement.Invoice.ID).Find(&invoiceItems).Error)
+		require.Len(t, invoiceItems, 1)
+		assert.Equal(t, "3", invoiceItems[0].Quantity.String())
+
+		assert.Equal(t, "7", stockQty())
+		var movement models.InventoryMovement
+		require.NoError(t, db.Where("reference_type = ? AND reference_id = ?", inventory.ReferenceTypeConsignmentSettlement, settlement.ID).First(&movement).Error)
+		assert.Equal(t, models.MovementTypeOut, movement.MovementType)
+		assert.Equal(t, "-3", movement.Quantity.String())
+	})
+
+	t.Run("success - on-hand report sells the difference", func(t *testing.T) {
+		price := "11000"
+		settlement, err := service.CreateSettlement(ctx, company.TenantID, company.ID, "user-1", &dto.CreateConsignmentSettlementRequest{
+			WarehouseID:    consignmentWarehouse.ID,
+			SettlementDate: today,
+			ReportType:     string(models.ConsignmentReportTypeOnHand),
+			Items:          []dto.CreateConsignmentSettlementItemRequest{{ProductID: product.ID, Quantity: "5", UnitPrice: &price}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, settlement.Items, 1)
+		assert.Equal(t, "5", settlement.Items[0].ReportedQty.String())
+		assert.Equal(t, "2", settlement.Items[0].SoldQty.String())
+		assert.Equal(t, "22000", settlement.Subtotal.String())
+		require.NotNil(t, settlement.Invoice)
+		assert.Equal(t, "INV/0002/"+period, settlement.Invoice.InvoiceNumber)
+		assert.Equal(t, "5", stockQty())
+	})
+
+	t.Run("success - on-hand report matching stock creates no invoice", func(t *testing.T) {
+		settlement, err := service.CreateSettlement(ctx, company.TenantID, company.ID, "user-1", &dto.CreateConsignmentSettlementRequest{
+			WarehouseID:    consignmentWarehouse.ID,
+			SettlementDate: today,
+			ReportType:     string(models.ConsignmentReportTypeOnHand),
+			Items:          []dto.CreateConsignmentSettlementItemRequest{{ProductID: product.ID, Quantity: "5"}},
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, settlement.InvoiceID)
+		assert.True(t, settlement.TotalAmount.IsZero())
+		assert.Equal(t, "5", stockQty())
+	})
+
+	t.Run("error - sold qty exceeds consignment stock", func(t *testing.T) {
+		_, err := service.CreateSettlement(ctx, company.TenantID, company.ID, "user-1", &dto.CreateConsignmentSettlementRequest{
+			WarehouseID:    consignmentWarehouse.ID,
+			SettlementDate: today,
+			ReportType:     string(models.ConsignmentReportTypeSold),
+			Items:          []dto.CreateConsignmentSettlementItemRequest{{ProductID: product.ID, Quantity: "6"}},
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds consignment stock")
+		assert.Equal(t, "5", stockQty())
+	})
+
+	t.Run("error - warehouse is not a consignment warehouse", func(t *testing.T) {
+		_, err := service.CreateSettlement(ctx, company.TenantID, company.ID, "user-1", &dto.CreateConsignmentSettlementRequest{
+			WarehouseID:    mainWarehouse.ID,
+			SettlementDate: today,
+			ReportType:     string(models.ConsignmentReportTypeSold),
+			Items:          []dto.CreateConsignmentSettlementItemRequest{{ProductID: product.ID, Quantity: "1"}},
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a consignment warehouse")
+	})
+
+	t.Run("success - balance report per customer", func(t *testing.T) {
+		report, err := service.GetBalanceReport(ctx, company.TenantID, company.ID, &dto.ConsignmentBalanceQuery{})
+
+		require.NoError(t, err)
+		require.Len(t, report.Customers, 1)
+		balance := report.Customers[0]
+		assert.Equal(t, "CUST001", balance.CustomerCode)
+		assert.Equal(t, "5", balance.TotalQuantity)
+		assert.Equal(t, "25000", balance.TotalStockValue)
+		assert.Equal(t, 3, balance.SettlementCount)
+		assert.Equal(t, "64380", balance.SettledAmount) // 39960 + 24420
+		require.NotNil(t, balance.LastSettlementDate)
+		assert.Equal(t, today, *balance.LastSettlementDate)
+		require.Len(t, balance.Lines, 1)
+		assert.Equal(t, "CSG-MAKMUR", balance.Lines[0].WarehouseCode)
+		assert.Equal(t, "PROD001", balance.Lines[0].ProductCode)
+		assert.Equal(t, "25000", report.TotalStockValue)
+	})
+}
@@ -0,0 +1,682 @@
+package creditnote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// CreditNoteService - Sales credit notes against invoices.
+// A credit note takes its discount and tax share from the invoice it credits. On issue
+// it settles what is still open on that invoice; the rest becomes customer credit, which
+// can later be applied to another open invoice of the customer or refunded.
+type CreditNoteService struct {
+	db           *gorm.DB
+	docNumberGen *document.DocumentNumberGenerator
+}
+
+// NewCreditNoteService creates a new credit note service instance
+func NewCreditNoteService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator) *CreditNoteService {
+	return &CreditNoteService{
+		db:           db,
+		docNumberGen: docNumberGen,
+	}
+}
+
+// InvoiceCredit describes a credit note to issue against an invoice
+type InvoiceCredit struct {
+	TenantID         string
+	CompanyID        string
+	InvoiceID        string
+	CreditNoteNumber string // From NextNumber, generated before the caller's transaction
+	CustomerReturnID *string
+	Category         models.SalesNoteCategory
+	CreditNoteDate   time.Time
+	Reason           string
+	Notes            *string
+	Lines            []CreditLine
+	CreatedBy        string
+}
+
+// CreditLine - One credited invoice line
+type CreditLine struct {
+	InvoiceItemID        *string
+	CustomerReturnItemID *string
+	ProductID            *string
+	Description          string
+	Quantity             decimal.Decimal // Base unit
+	UnitPrice            decimal.Decimal // Per base unit
+	Subtotal             decimal.Decimal // Net of line discount, before invoice discount and tax
+}
+
+// NextNumber generates the next credit note number
+func (s *CreditNoteService) NextNumber(ctx context.Context, tenantID, companyID string) (string, error) {
+	number, err := s.docNumberGen.GenerateNumber(ctx, tenantID, companyID, document.DocTypeSalesCreditNote)
+	if err != nil {
+		return "", pkgerrors.NewInternalError(fmt.Errorf("failed to generate credit note number: %w", err))
+	}
+	return number, nil
+}
+
+// ============================================================================
+// ISSUE
+// ============================================================================
+
+// CreateCreditNote credits part of an invoice for a price correction, short shipment or
+// other reason, within what IssueForInvoice allows.
+// Returned goods are credited by completing a customer return instead.
+func (s *CreditNoteService) CreateCreditNote(
+	ctx context.Context,
+	tenantID, companyID string,
+	req *dto.CreateSalesCreditNoteRequest,
+	userID string,
+) (*models.SalesCreditNote, error) {
+	creditNoteDate, err := time.Parse("2006-01-02", req.CreditNoteDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid credit note date format, use YYYY-MM-DD")
+	}
+
+	creditNoteNumber, err := s.NextNumber(ctx, tenantID, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var creditNote *models.SalesCreditNote
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND company_id = ?", req.InvoiceID, companyID).
+			First(&invoice).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("Invoice")
+			}
+			return fmt.Errorf("failed to load invoice: %w", err)
+		}
+
+		var invoiceItems []models.InvoiceItem
+		if err := tx.Preload("Product").Where("invoice_id = ?", invoice.ID).Find(&invoiceItems).Error; err != nil {
+			return fmt.Errorf("failed to load invoice items: %w", err)
+		}
+
+		lines := make([]CreditLine, 0, len(req.Items))
+		for i, itemReq := range req.Items {
+			line, err := buildCreditLine(invoiceItems, &itemReq, i)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, *line)
+		}
+
+		creditNote, err = s.IssueForInvoice(tx, &InvoiceCredit{
+			TenantID:         tenantID,
+			CompanyID:        companyID,
+			InvoiceID:        invoice.ID,
+			CreditNoteNumber: creditNoteNumber,
+			Category:         models.SalesNoteCategory(req.Category),
+			CreditNoteDate:   creditNoteDate,
+			Reason:           req.Reason,
+			Notes:            req.Notes,
+			Lines:            lines,
+			CreatedBy:        userID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCreditNoteByID(ctx, tenantID, companyID, creditNote.ID)
+}
+
+// IssueForInvoice creates a credit note against an invoice and applies it.
+// Each invoice line can be credited up to its subtotal plus what debit notes added to it,
+// and the invoice as a whole up to its subtotal plus debit notes, counting every earlier
+// credit note whether manual or from a customer return.
+// Runs inside the caller's transaction. The invoice is locked while its credited
+// amount and payment status are updated.
+func (s *CreditNoteService) IssueForInvoice(tx *gorm.DB, credit *InvoiceCredit) (*models.SalesCreditNote, error) {
+	if credit.CreditNoteNumber == "" {
+		return nil, pkgerrors.NewInternalError(fmt.Errorf("credit note number is required"))
+	}
+
+	var invoice models.Invoice
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND company_id = ?", credit.InvoiceID, credit.CompanyID).
+		First(&invoice).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Invoice")
+		}
+		return nil, fmt.Errorf("failed to load invoice: %w", err)
+	}
+
+	subtotal := decimal.Zero
+	for _, line := range credit.Lines {
+		subtotal = subtotal.Add(line.Subtotal)
+	}
+	if !subtotal.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("credit note amount must be greater than zero")
+	}
+	if err := checkCreditable(tx, &invoice, credit.Lines, subtotal); err != nil {
+		return nil, err
+	}
+	discountAmount, taxAmount := invoice.AdjustmentShare(subtotal)
+
+	category := credit.Category
+	if category == "" {
+		category = models.SalesNoteCategoryOther
+	}
+
+	creditNote := &models.SalesCreditNote{
+		TenantID:         credit.TenantID,
+		CompanyID:        credit.CompanyID,
+		CreditNoteNumber: credit.CreditNoteNumber,
+		CreditNoteDate:   credit.CreditNoteDate,
+		CustomerID:       invoice.CustomerID,
+		InvoiceID:        &invoice.ID,
+		CustomerReturnID: credit.CustomerReturnID,
+		Category:         category,
+		Reason:           credit.Reason,
+		Subtotal:         subtotal,
+		DiscountAmount:   discountAmount,
+		TaxAmount:        taxAmount,
+		TotalAmount:      subtotal.Sub(discountAmount).Add(taxAmount),
+		Status:           models.SalesCreditNoteStatusOpen,
+		Notes:            credit.Notes,
+	}
+	if credit.CreatedBy != "" {
+		creditNote.CreatedBy = &credit.CreatedBy
+	}
+	if err := tx.Create(creditNote).Error; err != nil {
+		return nil, fmt.Errorf("failed to create credit note: %w", err)
+	}
+
+	for _, line := range credit.Lines {
+		item := &models.SalesCreditNoteItem{
+			CreditNoteID:         creditNote.ID,
+			InvoiceItemID:        line.InvoiceItemID,
+			CustomerReturnItemID: line.CustomerReturnItemID,
+			ProductID:            line.ProductID,
+			Description:          line.Description,
+			Quantity:             line.Quantity,
+			UnitPrice:            line.UnitPrice,
+			Subtotal:             line.Subtotal,
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return nil, fmt.Errorf("failed to create credit note item: %w", err)
+		}
+		creditNote.Items = append(creditNote.Items, *item)
+	}
+
+	// Settle what is open on the credited invoice; the rest becomes customer credit
+	amount := decimal.Min(creditNote.TotalAmount, invoice.RemainingAmount())
+	if amount.IsPositive() {
+		if err := s.settle(tx, creditNote, &invoice, amount, credit.CreatedBy); err != nil {
+			return nil, err
+		}
+	}
+	if unapplied := creditNote.UnappliedAmount(); unapplied.IsPositive() {
+		if err := s.adjustCustomerCredit(tx, creditNote.CustomerID, unapplied); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.saveSettlement(tx, creditNote); err != nil {
+		return nil, err
+	}
+
+	return creditNote, nil
+}
+
+// ============================================================================
+// APPLICATION & REFUND
+// ============================================================================
+
+// ApplyCreditNote uses unapplied credit to settle another open invoice of the same customer
+func (s *CreditNoteService) ApplyCreditNote(
+	ctx context.Context,
+	tenantID, companyID, creditNoteID string,
+	req *dto.ApplySalesCreditNoteRequest,
+	userID string,
+) (*models.SalesCreditNote, error) {
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		creditNote, err := s.lockCreditNote(tx, companyID, creditNoteID)
+		if err != nil {
+			return err
+		}
+
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND company_id = ?", req.InvoiceID, companyID).
+			First(&invoice).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("Invoice")
+			}
+			return fmt.Errorf("failed to load invoice: %w", err)
+		}
+		if invoice.CustomerID != creditNote.CustomerID {
+			return pkgerrors.NewBadRequestError("invoice belongs to a different customer")
+		}
+
+		remaining := invoice.RemainingAmount()
+		if !remaining.IsPositive() {
+			return pkgerrors.NewBadRequestError("invoice has no open balance")
+		}
+
+		amount, err := settlementAmount(req.Amount, decimal.Min(creditNote.UnappliedAmount(), remaining))
+		if err != nil {
+			return err
+		}
+		if amount.GreaterThan(creditNote.UnappliedAmount()) {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf(
+				"amount exceeds unapplied credit %s", creditNote.UnappliedAmount().StringFixed(2)))
+		}
+		if amount.GreaterThan(remaining) {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf(
+				"amount exceeds invoice balance %s", remaining.StringFixed(2)))
+		}
+
+		if err := s.settle(tx, creditNote, &invoice, amount, userID); err != nil {
+			return err
+		}
+		if err := s.adjustCustomerCredit(tx, creditNote.CustomerID, amount.Neg()); err != nil {
+			return err
+		}
+		return s.saveSettlement(tx, creditNote)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCreditNoteByID(ctx, tenantID, companyID, creditNoteID)
+}
+
+// RefundCreditNote pays unapplied credit back to the customer
+func (s *CreditNoteService) RefundCreditNote(
+	ctx context.Context,
+	tenantID, companyID, creditNoteID string,
+	req *dto.RefundSalesCreditNoteRequest,
+	userID string,
+) (*models.SalesCreditNote, error) {
+	refundDate, err := time.Parse("2006-01-02", req.RefundDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid refund date format, use YYYY-MM-DD")
+	}
+
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		creditNote, err := s.lockCreditNote(tx, companyID, creditNoteID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := settlementAmount(req.Amount, creditNote.UnappliedAmount())
+		if err != nil {
+			return err
+		}
+		if amount.GreaterThan(creditNote.UnappliedAmount()) {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf(
+				"amount exceeds unapplied credit %s", creditNote.UnappliedAmount().StringFixed(2)))
+		}
+
+		refund := &models.SalesCreditNoteRefund{
+			CreditNoteID:  creditNote.ID,
+			RefundDate:    refundDate,
+			Amount:        amount,
+			PaymentMethod: models.PaymentMethod(req.PaymentMethod),
+			Reference:     req.Reference,
+			BankAccountID: req.BankAccountID,
+			Notes:         req.Notes,
+		}
+		if userID != "" {
+			refund.CreatedBy = &userID
+		}
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to record refund: %w", err)
+		}
+
+		creditNote.RefundedAmount = creditNote.RefundedAmount.Add(amount)
+		if err := s.adjustCustomerCredit(tx, creditNote.CustomerID, amount.Neg()); err != nil {
+			return err
+		}
+		return s.saveSettlement(tx, creditNote)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCreditNoteByID(ctx, tenantID, companyID, creditNoteID)
+}
+
+// settle applies part of a credit note to an invoice and updates the invoice's credited
+// amount and payment status
+func (s *CreditNoteService) settle(tx *gorm.DB, creditNote *models.SalesCreditNote, invoice *models.Invoice, amount decimal.Decimal, userID string) error {
+	application := &models.SalesCreditNoteApplication{
+		CreditNoteID: creditNote.ID,
+		InvoiceID:    invoice.ID,
+		Amount:       amount,
+		AppliedAt:    time.Now(),
+	}
+	if userID != "" {
+		application.AppliedBy = &userID
+	}
+	if err := tx.Create(application).Error; err != nil {
+		return fmt.Errorf("failed to apply credit note: %w", err)
+	}
+	creditNote.Applications = append(creditNote.Applications, *application)
+
+	invoice.CreditedAmount = invoice.CreditedAmount.Add(amount)
+	invoice.UpdatePaymentStatus()
+	if err := tx.Model(invoice).Updates(map[string]interface{}{
+		"credited_amount": invoice.CreditedAmount,
+		"payment_status":  invoice.PaymentStatus,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+
+	creditNote.AppliedAmount = creditNote.AppliedAmount.Add(amount)
+	return nil
+}
+
+// saveSettlement stores the applied and refunded amounts of a credit note and closes it
+// once no credit is left
+func (s *CreditNoteService) saveSettlement(tx *gorm.DB, creditNote *models.SalesCreditNote) error {
+	creditNote.Status = models.SalesCreditNoteStatusOpen
+	if !creditNote.UnappliedAmount().IsPositive() {
+		creditNote.Status = models.SalesCreditNoteStatusApplied
+	}
+
+	if err := tx.Model(creditNote).Updates(map[string]interface{}{
+		"applied_amount":  creditNote.AppliedAmount,
+		"refunded_amount": creditNote.RefundedAmount,
+		"status":          creditNote.Status,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update credit note: %w", err)
+	}
+	return nil
+}
+
+// adjustCustomerCredit moves the customer's credit balance by delta
+func (s *CreditNoteService) adjustCustomerCredit(tx *gorm.DB, customerID string, delta decimal.Decimal) error {
+	if err := tx.Model(&models.Customer{}).Where("id = ?", customerID).
+		Update("credit_balance", gorm.Expr("credit_balance + ?", delta)).Error; err != nil {
+		return fmt.Errorf("failed to update customer credit balance: %w", err)
+	}
+	return nil
+}
+
+// lockCreditNote loads a credit note for update and checks it still has credit left
+func (s *CreditNoteService) lockCreditNote(tx *gorm.DB, companyID, creditNoteID string) (*models.SalesCreditNote, error) {
+	var creditNote models.SalesCreditNote
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND company_id = ?", creditNoteID, companyID).
+		First(&creditNote).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Credit note")
+		}
+		return nil, fmt.Errorf("failed to load credit note: %w", err)
+	}
+	if !creditNote.UnappliedAmount().IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("credit note has no unapplied credit")
+	}
+	return &creditNote, nil
+}
+
+// ============================================================================
+// QUERIES
+// ============================================================================
+
+// GetCreditNoteByID retrieves a credit note with its lines and applications
+func (s *CreditNoteService) GetCreditNoteByID(ctx context.Context, tenantID, companyID, creditNoteID string) (*models.SalesCreditNote, error) {
+	var creditNote models.SalesCreditNote
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Customer").
+		Preload("Invoice").
+		Preload("Items.Product").
+		Preload("Applications", func(db *gorm.DB) *gorm.DB {
+			return db.Order("applied_at ASC")
+		}).
+		Preload("Applications.Invoice").
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB {
+			return db.Order("refund_date ASC, created_at ASC")
+		}).
+		Where("id = ? AND company_id = ?", creditNoteID, companyID).
+		First(&creditNote).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Credit note")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	return &creditNote, nil
+}
+
+// ListCreditNotes lists credit notes with filters and pagination
+func (s *CreditNoteService) ListCreditNotes(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.SalesCreditNoteQuery,
+) ([]models.SalesCreditNote, *dto.PaginationInfo, error) {
+	var creditNotes []models.SalesCreditNote
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("sales_credit_notes.company_id = ?", companyID)
+
+	if query.Status != nil {
+		db = db.Where("sales_credit_notes.status = ?", *query.Status)
+	}
+	if query.Category != nil {
+		db = db.Where("sales_credit_notes.category = ?", *query.Category)
+	}
+	if query.CustomerID != nil {
+		db = db.Where("sales_credit_notes.customer_id = ?", *query.CustomerID)
+	}
+	if query.InvoiceID != nil {
+		db = db.Where("sales_credit_notes.invoice_id = ?", *query.InvoiceID)
+	}
+	if query.Search != "" {
+		db = db.Where("sales_credit_notes.credit_note_number LIKE ?", "%"+query.Search+"%")
+	}
+
+	if err := db.Session(&gorm.Session{}).Model(&models.SalesCreditNote{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Session(&gorm.Session{}).
+		Order("sales_credit_notes.credit_note_date DESC, sales_credit_notes.credit_note_number DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Customer").
+		Preload("Invoice").
+		Preload("Items.Product").
+		Preload("Applications.Invoice").
+		Preload("Refunds").
+		Find(&creditNotes).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return creditNotes, pagination, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// buildCreditLine turns a requested line into a credit line. Lines on an invoice line are
+// in the product base unit and default to the line's net price per base unit.
+func buildCreditLine(invoiceItems []models.InvoiceItem, req *dto.CreateSalesCreditNoteItemRequest, index int) (*CreditLine, error) {
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: quantity must be greater than zero", index+1))
+	}
+
+	var unitPrice *decimal.Decimal
+	if req.UnitPrice != nil {
+		price, err := decimal.NewFromString(*req.UnitPrice)
+		if err != nil || !price.IsPositive() {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: unit price must be greater than zero", index+1))
+		}
+		unitPrice = &price
+	}
+
+	if req.InvoiceItemID == nil {
+		if unitPrice == nil || req.Description == nil || *req.Description == "" {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf(
+				"item %d: description and unit price are required without an invoice line", index+1))
+		}
+		return &CreditLine{
+			Description: *req.Description,
+			Quantity:    quantity,
+			UnitPrice:   *unitPrice,
+			Subtotal:    quantity.Mul(*unitPrice).Round(2),
+		}, nil
+	}
+
+	var invoiceItem *models.InvoiceItem
+	for i := range invoiceItems {
+		if invoiceItems[i].ID == *req.InvoiceItemID {
+			invoiceItem = &invoiceItems[i]
+			break
+		}
+	}
+	if invoiceItem == nil {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: invoice line not found on this invoice", index+1))
+	}
+
+	invoicedQuantity := invoiceItem.BaseQuantity
+	if !invoicedQuantity.IsPositive() {
+		invoicedQuantity = invoiceItem.Quantity
+	}
+	if quantity.GreaterThan(invoicedQuantity) {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf(
+			"item %d: quantity exceeds the invoiced quantity %s", index+1, invoicedQuantity.String()))
+	}
+
+	line := &CreditLine{
+		InvoiceItemID: &invoiceItem.ID,
+		ProductID:     &invoiceItem.ProductID,
+		Description:   invoiceItem.Product.Name,
+		Quantity:      quantity,
+	}
+	if req.Description != nil && *req.Description != "" {
+		line.Description = *req.Description
+	}
+	if unitPrice != nil {
+		line.UnitPrice = *unitPrice
+		line.Subtotal = quantity.Mul(*unitPrice).Round(2)
+	} else if quantity.Equal(invoicedQuantity) {
+		line.UnitPrice = invoiceItem.Subtotal.Div(invoicedQuantity).Round(2)
+		line.Subtotal = invoiceItem.Subtotal
+	} else {
+		line.Subtotal = invoiceItem.Subtotal.Mul(quantity).Div(invoicedQuantity).Round(2)
+		line.UnitPrice = line.Subtotal.Div(quantity).Round(2)
+	}
+	return line, nil
+}
+
+// checkCreditable rejects credit lines beyond what their invoice lines, or the invoice,
+// can still be credited
+func checkCreditable(tx *gorm.DB, invoice *models.Invoice, lines []CreditLine, subtotal decimal.Decimal) error {
+	lineTotals := make(map[string]decimal.Decimal)
+	for _, line := range lines {
+		if line.InvoiceItemID != nil {
+			lineTotals[*line.InvoiceItemID] = lineTotals[*line.InvoiceItemID].Add(line.Subtotal)
+		}
+	}
+
+	if len(lineTotals) > 0 {
+		var invoiceItems []models.InvoiceItem
+		if err := tx.Preload("Product").Where("invoice_id = ?", invoice.ID).Find(&invoiceItems).Error; err != nil {
+			return fmt.Errorf("failed to load invoice items: %w", err)
+		}
+		for i := range invoiceItems {
+			credited, ok := lineTotals[invoiceItems[i].ID]
+			if !ok {
+				continue
+			}
+			available, err := creditableLineAmount(tx, &invoiceItems[i])
+			if err != nil {
+				return err
+			}
+			if credited.GreaterThan(available) {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf(
+					"credit for invoice line %s exceeds its remaining creditable amount %s",
+					invoiceItems[i].Product.Name, available.StringFixed(2)))
+			}
+		}
+	}
+
+	available, err := creditableInvoiceAmount(tx, invoice)
+	if err != nil {
+		return err
+	}
+	if subtotal.GreaterThan(available) {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf(
+			"credit subtotal exceeds the invoice's remaining creditable amount %s", available.StringFixed(2)))
+	}
+	return nil
+}
+
+// creditableLineAmount returns how much of an invoice line can still be credited: its
+// subtotal plus debit notes on the line, less earlier credit notes on the line
+func creditableLineAmount(tx *gorm.DB, invoiceItem *models.InvoiceItem) (decimal.Decimal, error) {
+	var credited, debited decimal.Decimal
+	if err := tx.Model(&models.SalesCreditNoteItem{}).
+		Select("COALESCE(SUM(subtotal), 0)").
+		Where("invoice_item_id = ?", invoiceItem.ID).
+		Scan(&credited).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum credited amount: %w", err)
+	}
+	if err := tx.Model(&models.SalesDebitNoteItem{}).
+		Select("COALESCE(SUM(subtotal), 0)").
+		Where("invoice_item_id = ?", invoiceItem.ID).
+		Scan(&debited).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum debited amount: %w", err)
+	}
+	return invoiceItem.Subtotal.Add(debited).Sub(credited), nil
+}
+
+// creditableInvoiceAmount returns how much of an invoice's subtotal can still be
+// credited: its subtotal plus debit notes, less earlier credit notes
+func creditableInvoiceAmount(tx *gorm.DB, invoice *models.Invoice) (decimal.Decimal, error) {
+	var credited, debited decimal.Decimal
+	if err := tx.Model(&models.SalesCreditNote{}).
+		Select("COALESCE(SUM(subtotal), 0)").
+		Where("invoice_id = ?", invoice.ID).
+		Scan(&credited).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum credited amount: %w", err)
+	}
+	if err := tx.Model(&models.SalesDebitNote{}).
+		Select("COALESCE(SUM(subtotal), 0)").
+		Where("invoice_id = ?", invoice.ID).
+		Scan(&debited).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum debited amount: %w", err)
+	}
+	return invoice.Subtotal.Add(debited).Sub(credited), nil
+}
+
+// settlementAmount parses a requested amount, defaulting to fallback when none is given
+func settlementAmount(requested *string, fallback decimal.Decimal) (decimal.Decimal, error) {
+	if requested == nil || *requested == "" {
+		return fallback, nil
+	}
+	amount, err := decimal.NewFromString(*requested)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Zero, pkgerrors.NewBadRequestError("amount must be greater than zero")
+	}
+	return amount, nil
+}
@@ -0,0 +1,247 @@
+package creditnote
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditNoteService_ManualCreditApplyAndRefund(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.Invoice{},
+		&models.InvoiceItem{},
+		&models.SalesCreditNote{},
+		&models.SalesCreditNoteItem{},
+		&models.SalesCreditNoteApplication{},
+		&models.SalesCreditNoteRefund{},
+		&models.SalesDebitNote{},
+		&models.SalesDebitNoteItem{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	// Invoice-format numbers end in the month and year issued
+	period := time.Now().Format("01/2006")
+	user := testutil.CreateTestUser(t, db, "finance@example.com")
+
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+	otherCustomer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST002", Name: "Toko Sejahtera", IsActive: true}
+	require.NoError(t, db.Create(otherCustomer).Error)
+
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD001", Name: "Minyak Goreng 2L", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+
+	service := NewCreditNoteService(db, document.NewDocumentNumberGenerator(db))
+
+	// 1.000.000 less 10% discount, plus 11% tax on 900.000
+	invoice := &models.Invoice{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		InvoiceNumber:  "INV/0001",
+		InvoiceDate:    time.Now().AddDate(0, 0, -5),
+		DueDate:        time.Now().AddDate(0, 0, 25),
+		CustomerID:     customer.ID,
+		Subtotal:       decimal.NewFromInt(1000000),
+		DiscountAmount: decimal.NewFromInt(100000),
+		TaxAmount:      decimal.NewFromInt(99000),
+		TotalAmount:    decimal.NewFromInt(999000),
+		PaymentStatus:  models.PaymentStatusUnpaid,
+	}
+	require.NoError(t, db.Create(invoice).Error)
+	lineA := &models.InvoiceItem{
+		InvoiceID:    invoice.ID,
+		ProductID:    product.ID,
+		Quantity:     decimal.NewFromInt(10),
+		BaseQuantity: decimal.NewFromInt(10),
+		UnitPrice:    decimal.NewFromInt(60000),
+		Subtotal:     decimal.NewFromInt(600000),
+	}
+	require.NoError(t, db.Create(lineA).Error)
+	lineB := &models.InvoiceItem{
+		InvoiceID:    invoice.ID,
+		ProductID:    product.ID,
+		Quantity:     decimal.NewFromInt(4),
+		BaseQuantity: decimal.NewFromInt(4),
+		UnitPrice:    decimal.NewFromInt(100000),
+		Subtotal:     decimal.NewFromInt(400000),
+	}
+	require.NoError(t, db.Create(lineB).Error)
+
+	stringPtr := func(s string) *string { return &s }
+	reloadInvoice := func(id string) models.Invoice {
+		var current models.Invoice
+		require.NoError(t, db.First(&current, "id = ?", id).Error)
+		return current
+	}
+	creditBalance := func() string {
+		var current models.Customer
+		require.NoError(t, db.First(&current, "id = ?", customer.ID).Error)
+		return current.CreditBalance.String()
+	}
+	assertBadRequest := func(t *testing.T, err error) {
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	}
+	creditRequest := func(category models.SalesNoteCategory, items ...dto.CreateSalesCreditNoteItemRequest) *dto.CreateSalesCreditNoteRequest {
+		return &dto.CreateSalesCreditNoteRequest{
+			InvoiceID:      invoice.ID,
+			CreditNoteDate: time.Now().Format("2006-01-02"),
+			Category:       string(category),
+			Reason:         "Koreksi tagihan",
+			Items:          items,
+		}
+	}
+
+	t.Run("success - price correction takes the invoice's discount and tax share", func(t *testing.T) {
+		creditNote, err := service.CreateCreditNote(ctx, company.TenantID, company.ID, creditRequest(models.SalesNoteCategoryPriceCorrection,
+			dto.CreateSalesCreditNoteItemRequest{InvoiceItemID: &lineA.ID, Quantity: "10", UnitPrice: stringPtr("5000")},
+		), user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "CN/0001/"+period, creditNote.CreditNoteNumber)
+		assert.Equal(t, models.SalesNoteCategoryPriceCorrection, creditNote.Category)
+		assert.Equal(t, "50000", creditNote.Subtotal.String())
+		assert.Equal(t, "5000", creditNote.DiscountAmount.String())
+		assert.Equal(t, "4950", creditNote.TaxAmount.String())
+		assert.Equal(t, "49950", creditNote.TotalAmount.String())
+		assert.Equal(t, models.SalesCreditNoteStatusApplied, creditNote.Status)
+		require.Len(t, creditNote.Items, 1)
+		assert.Equal(t, product.Name, creditNote.Items[0].Description)
+
+		current := reloadInvoice(invoice.ID)
+		assert.Equal(t, "49950", current.CreditedAmount.String())
+		assert.Equal(t, models.PaymentStatusPartial, current.PaymentStatus)
+		assert.Equal(t, "949050", current.RemainingAmount().String())
+	})
+
+	t.Run("error - more than the invoiced quantity", func(t *testing.T) {
+		_, err := service.CreateCreditNote(ctx, company.TenantID, company.ID, creditRequest(models.SalesNoteCategoryShortShipment,
+			dto.CreateSalesCreditNoteItemRequest{InvoiceItemID: &lineB.ID, Quantity: "5"},
+		), user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("error - more than the invoice line is worth", func(t *testing.T) {
+		_, err := service.CreateCreditNote(ctx, company.TenantID, company.ID, creditRequest(models.SalesNoteCategoryPriceCorrection,
+			dto.CreateSalesCreditNoteItemRequest{InvoiceItemID: &lineB.ID, Quantity: "4", UnitPrice: stringPtr("150000")},
+		), user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("success - short shipment defaults to the line's net unit price", func(t *testing.T) {
+		creditNote, err := service.CreateCreditNote(ctx, company.TenantID, company.ID, creditRequest(models.SalesNoteCategoryShortShipment,
+			dto.CreateSalesCreditNoteItemRequest{InvoiceItemID: &lineB.ID, Quantity: "1"},
+		), user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "CN/0002/"+period, creditNote.CreditNoteNumber)
+		assert.Equal(t, "100000", creditNote.Items[0].UnitPrice.String())
+		assert.Equal(t, "99900", creditNote.TotalAmount.String())
+		assert.Equal(t, "149850", reloadInvoice(invoice.ID).CreditedAmount.String())
+	})
+
+	var goodwillID string
+	t.Run("success - credit on a settled invoice becomes customer credit", func(t *testing.T) {
+		current := reloadInvoice(invoice.ID)
+		require.NoError(t, db.Model(&current).Updates(map[string]interface{}{
+			"paid_amount":    current.RemainingAmount(),
+			"payment_status": models.PaymentStatusPaid,
+		}).Error)
+
+		creditNote, err := service.CreateCreditNote(ctx, company.TenantID, company.ID, creditRequest(models.SalesNoteCategoryOther,
+			dto.CreateSalesCreditNoteItemRequest{Description: stringPtr("Kompensasi keterlambatan"), Quantity: "1", UnitPrice: stringPtr("20000")},
+		), user.ID)
+
+		require.NoError(t, err)
+		goodwillID = creditNote.ID
+		assert.Equal(t, "19980", creditNote.TotalAmount.String())
+		assert.Equal(t, "0", creditNote.AppliedAmount.String())
+		assert.Equal(t, models.SalesCreditNoteStatusOpen, creditNote.Status)
+		assert.Equal(t, "19980", creditBalance())
+	})
+
+	t.Run("error - apply to another customer's invoice", func(t *testing.T) {
+		foreign := &models.Invoice{
+			TenantID: company.TenantID, CompanyID: company.ID, InvoiceNumber: "INV/0002",
+			InvoiceDate: time.Now(), DueDate: time.Now().AddDate(0, 0, 30), CustomerID: otherCustomer.ID,
+			Subtotal: decimal.NewFromInt(5000), TotalAmount: decimal.NewFromInt(5000), PaymentStatus: models.PaymentStatusUnpaid,
+		}
+		require.NoError(t, db.Create(foreign).Error)
+
+		_, err := service.ApplyCreditNote(ctx, company.TenantID, company.ID, goodwillID, &dto.ApplySalesCreditNoteRequest{InvoiceID: foreign.ID}, user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("success - apply unapplied credit to another open invoice", func(t *testing.T) {
+		next := &models.Invoice{
+			TenantID: company.TenantID, CompanyID: company.ID, InvoiceNumber: "INV/0003",
+			InvoiceDate: time.Now(), DueDate: time.Now().AddDate(0, 0, 30), CustomerID: customer.ID,
+			Subtotal: decimal.NewFromInt(15000), TotalAmount: decimal.NewFromInt(15000), PaymentStatus: models.PaymentStatusUnpaid,
+		}
+		require.NoError(t, db.Create(next).Error)
+
+		creditNote, err := service.ApplyCreditNote(ctx, company.TenantID, company.ID, goodwillID, &dto.ApplySalesCreditNoteRequest{InvoiceID: next.ID}, user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "15000", creditNote.AppliedAmount.String())
+		assert.Equal(t, "4980", creditNote.UnappliedAmount().String())
+		require.Len(t, creditNote.Applications, 1)
+		assert.Equal(t, "INV/0003", creditNote.Applications[0].Invoice.InvoiceNumber)
+		assert.Equal(t, models.PaymentStatusPaid, reloadInvoice(next.ID).PaymentStatus)
+		assert.Equal(t, "4980", creditBalance())
+	})
+
+	t.Run("error - refund more than is unapplied", func(t *testing.T) {
+		_, err := service.RefundCreditNote(ctx, company.TenantID, company.ID, goodwillID, &dto.RefundSalesCreditNoteRequest{
+			RefundDate:    time.Now().Format("2006-01-02"),
+			Amount:        stringPtr("5000"),
+			PaymentMethod: string(models.PaymentMethodBankTransfer),
+		}, user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("success - refund the rest closes the credit note", func(t *testing.T) {
+		creditNote, err := service.RefundCreditNote(ctx, company.TenantID, company.ID, goodwillID, &dto.RefundSalesCreditNoteRequest{
+			RefundDate:    time.Now().Format("2006-01-02"),
+			PaymentMethod: string(models.PaymentMethodBankTransfer),
+			Reference:     stringPtr("TRF-778"),
+		}, user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "4980", creditNote.RefundedAmount.String())
+		assert.True(t, creditNote.UnappliedAmount().IsZero())
+		assert.Equal(t, models.SalesCreditNoteStatusApplied, creditNote.Status)
+		require.Len(t, creditNote.Refunds, 1)
+		assert.Equal(t, "0", creditBalance())
+
+		_, err = service.RefundCreditNote(ctx, company.TenantID, company.ID, goodwillID, &dto.RefundSalesCreditNoteRequest{
+			RefundDate:    time.Now().Format("2006-01-02"),
+			PaymentMethod: string(models.PaymentMethodCash),
+		}, user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("success - list by category", func(t *testing.T) {
+		category := string(models.SalesNoteCategoryPriceCorrection)
+		creditNotes, pagination, err := service.ListCreditNotes(ctx, company.TenantID, company.ID, &dto.SalesCreditNoteQuery{Page: 1, PageSize: 20, Category: &category})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, pagination.Total)
+		assert.Equal(t, "CN/0001/"+period, creditNotes[0].CreditNoteNumber)
+	})
+}
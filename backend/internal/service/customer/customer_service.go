@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -523,7 +524,7 @@ func (s *CustomerService) GetFrequentProducts(ctx context.Context, tenantID, com
 			p.code as product_code,
 			p.name as product_name,
 			COUNT(DISTINCT so.id) as frequency,
-			SUM(soi.quantity) as total_qty,
+			SUM(soi.base_quantity) as total_qty,
 			MAX(so.so_date) as last_order_date,
 			p.base_unit,
 			(SELECT soi2.unit_price
@@ -645,6 +646,8 @@ func (s *CustomerService) GetCustomerCreditInfo(ctx context.Context, tenantID, c
 	}
 
 	// Calculate outstanding amount from unpaid/partially paid sales invoices
+	// Outstanding: invoice total plus debit notes, less payments and applied credit notes
+	// Overdue: the same for invoices past their due date
 	type OutstandingResult struct {
 		TotalOutstanding decimal.Decimal
 		TotalOverdue     decimal.Decimal
@@ -652,43 +655,40 @@ func (s *CustomerService) GetCustomerCreditInfo(ctx context.Context, tenantID, c
 
 	var result OutstandingResult
 
-	// TEMPORARY SOLUTION: Use sales_orders as estimation until sales_invoices is implemented
-	// Query to calculate outstanding and overdue amounts from sales orders
-	// Outstanding: Total amount from orders with status APPROVED or COMPLETED (not yet invoiced/paid)
-	// Overdue: Orders where required_date has passed and still not DELIVERED
 	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).
-		Table("sales_orders so").
+		Table("invoices inv").
 		Select(`
-			COALESCE(SUM(so.total_amount), 0) as total_outstanding,
+			COALESCE(SUM(inv.total_amount + inv.debited_amount - inv.paid_amount - inv.credited_amount), 0) as total_outstanding,
 			COALESCE(SUM(
 				CASE
-					WHEN so.required_date < NOW() AND so.status NOT IN ('DELIVERED', 'CANCELLED')
-					THEN so.total_amount
+					WHEN inv.due_date < ?
+					THEN inv.total_amount + inv.debited_amount - inv.paid_amount - inv.credited_amount
 					ELSE 0
 				END
 			), 0) as total_overdue
-		`).
-		Where("so.customer_id = ?", customerID).
-		Where("so.company_id = ?", companyID).
-		Where("so.status IN ('APPROVED', 'COMPLETED')"). // Orders that are approved but not yet delivered
+		`, time.Now()).
+		Where("inv.customer_id = ?", customerID).
+		Where("inv.company_id = ?", companyID).
+		Where("inv.payment_status IN ?", []models.PaymentStatus{models.PaymentStatusUnpaid, models.PaymentStatusPartial}).
 		Scan(&result).Error
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate customer outstanding: %w", err)
 	}
 
-	// Calculate available credit (credit limit - outstanding)
+	// Calculate available credit (credit limit - outstanding + unapplied credit notes)
 	creditLimit := customer.CreditLimit
 	outstandingAmount := result.TotalOutstanding
-	availableCredit := creditLimit.Sub(outstandingAmount)
+	exposure := outstandingAmount.Sub(customer.CreditBalance)
+	availableCredit := creditLimit.Sub(exposure)
 
 	// Check if exceeding limit
-	isExceedingLimit := outstandingAmount.GreaterThan(creditLimit)
+	isExceedingLimit := exposure.GreaterThan(creditLimit)
 
 	// Calculate utilization percentage
 	var utilizationPercent decimal.Decimal
 	if creditLimit.GreaterThan(decimal.Zero) {
-		utilizationPercent = outstandingAmount.Div(creditLimit).Mul(decimal.NewFromInt(100))
+		utilizationPercent = exposure.Div(creditLimit).Mul(decimal.NewFromInt(100))
 	} else {
 		utilizationPercent = decimal.Zero
 	}
@@ -702,6 +702,7 @@ func (s *CustomerService) GetCustomerCreditInfo(ctx context.Context, tenantID, c
 		OutstandingAmount:  outstandingAmount.String(),
 		AvailableCredit:    availableCredit.String(),
 		OverdueAmount:      result.TotalOverdue.String(),
+		CreditBalance:      customer.CreditBalance.String(),
 		PaymentTermDays:    customer.PaymentTerm,
 		IsExceedingLimit:   isExceedingLimit,
 		UtilizationPercent: utilizationPercent.StringFixed(2),
@@ -735,6 +736,7 @@ func mapCustomerToResponse(customer *models.Customer) dto.CustomerResponse {
 		CreditLimit:        customer.CreditLimit.String(),
 		CurrentOutstanding: customer.CurrentOutstanding.String(),
 		OverdueAmount:      customer.OverdueAmount.String(),
+		CreditBalance:      customer.CreditBalance.String(),
 		LastTransactionAt:  customer.LastTransactionAt,
 		Notes:              customer.Notes,
 		IsActive:           customer.IsActive,
@@ -0,0 +1,769 @@
+package customerreturn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dto"
+	"backend/internal/service/creditnote"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/service/qualityhold"
+	"backend/internal/service/serial"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// CustomerReturnService - Customer returns (RMA) against deliveries.
+// A return is recorded against the delivery lines (and their invoice lines), each line is
+// inspected into sellable stock, quarantine or scrap, and completing the return posts the
+// stock back at the cost it left with and issues a sales credit note for the invoiced value.
+type CustomerReturnService struct {
+	db                  *gorm.DB
+	stockPostingService *inventory.StockPostingService
+	qualityHoldService  *qualityhold.QualityHoldService
+	creditNoteService   *creditnote.CreditNoteService
+	serialService       *serial.SerialService
+}
+
+// NewCustomerReturnService creates a new customer return service instance
+func NewCustomerReturnService(
+	db *gorm.DB,
+	docNumberGen *document.DocumentNumberGenerator,
+	stockPostingService *inventory.StockPostingService,
+) *CustomerReturnService {
+	return &CustomerReturnService{
+		db:                  db,
+		stockPostingService: stockPostingService,
+		qualityHoldService:  qualityhold.NewQualityHoldService(db, stockPostingService),
+		creditNoteService:   creditnote.NewCreditNoteService(db, docNumberGen),
+		serialService:       serial.NewSerialService(db),
+	}
+}
+
+// ============================================================================
+// RETURN OPERATIONS
+// ============================================================================
+
+// CreateReturn records goods returned against a delivery (status DRAFT).
+// Nothing is posted yet; the returned quantity is reserved against the delivery lines
+// so the same goods cannot be returned twice.
+func (s *CustomerReturnService) CreateReturn(
+	ctx context.Context,
+	tenantID, companyID, userID string,
+	req *dto.CreateCustomerReturnRequest,
+) (*models.CustomerReturn, error) {
+	returnDate, err := time.Parse("2006-01-02", req.ReturnDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid returnDate format (use YYYY-MM-DD)")
+	}
+
+	var customerReturn *models.CustomerReturn
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var delivery models.Delivery
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND company_id = ?", req.DeliveryID, companyID).
+			First(&delivery).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("Delivery")
+			}
+			return fmt.Errorf("failed to load delivery: %w", err)
+		}
+		if delivery.Type != models.DeliveryTypeNormal {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Only NORMAL deliveries can be returned, delivery %s is %s", delivery.DeliveryNumber, delivery.Type))
+		}
+		if delivery.Status != models.DeliveryStatusDelivered && delivery.Status != models.DeliveryStatusConfirmed {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Delivery %s has not reached the customer: %s", delivery.DeliveryNumber, delivery.Status))
+		}
+
+		invoice, err := s.findInvoice(tx, &delivery, req.InvoiceID)
+		if err != nil {
+			return err
+		}
+
+		returnNumber, err := s.generateReturnNumber(tx, tenantID, companyID)
+		if err != nil {
+			return err
+		}
+
+		customerReturn = &models.CustomerReturn{
+			TenantID:     tenantID,
+			CompanyID:    companyID,
+			ReturnNumber: returnNumber,
+			ReturnDate:   returnDate,
+			CustomerID:   delivery.CustomerID,
+			DeliveryID:   delivery.ID,
+			WarehouseID:  delivery.WarehouseID,
+			Status:       models.CustomerReturnStatusDraft,
+			Reason:       req.Reason,
+			Notes:        req.Notes,
+		}
+		if invoice != nil {
+			customerReturn.InvoiceID = &invoice.ID
+		}
+		if userID != "" {
+			customerReturn.CreatedBy = &userID
+		}
+		if err := tx.Create(customerReturn).Error; err != nil {
+			return fmt.Errorf("failed to create customer return: %w", err)
+		}
+
+		requested := make(map[string]decimal.Decimal)
+		for _, itemReq := range req.Items {
+			item, err := s.createItem(tx, customerReturn, &delivery, invoice, &itemReq, requested)
+			if err != nil {
+				return err
+			}
+			customerReturn.Items = append(customerReturn.Items, *item)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReturnByID(ctx, tenantID, companyID, customerReturn.ID)
+}
+
+// InspectReturn records the disposition of every returned line (DRAFT/INSPECTED → INSPECTED).
+// Inspection can be repeated until the return is completed.
+func (s *CustomerReturnService) InspectReturn(
+	ctx context.Context,
+	tenantID, companyID, returnID, userID string,
+	req *dto.InspectCustomerReturnRequest,
+) (*models.CustomerReturn, error) {
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		customerReturn, err := s.lockReturn(tx, companyID, returnID)
+		if err != nil {
+			return err
+		}
+		if customerReturn.Status != models.CustomerReturnStatusDraft && customerReturn.Status != models.CustomerReturnStatusInspected {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Customer return is already %s", customerReturn.Status))
+		}
+
+		var items []models.CustomerReturnItem
+		if err := tx.Preload("Product").Where("customer_return_id = ?", customerReturn.ID).Find(&items).Error; err != nil {
+			return fmt.Errorf("failed to load customer return items: %w", err)
+		}
+
+		dispositions := make(map[string]dto.InspectCustomerReturnItemRequest, len(req.Items))
+		for _, itemReq := range req.Items {
+			dispositions[itemReq.ItemID] = itemReq
+		}
+		if len(dispositions) != len(items) {
+			return pkgerrors.NewBadRequestError("every return line needs exactly one disposition")
+		}
+
+		for _, item := range items {
+			itemReq, ok := dispositions[item.ID]
+			if !ok {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("missing disposition for product %s", item.Product.Code))
+			}
+			disposition := models.ReturnDisposition(itemReq.Disposition)
+			if disposition == models.ReturnDispositionQuarantine && (!item.Product.IsBatchTracked || item.BatchID == nil) {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not batch-tracked and cannot be quarantined; use SELLABLE or SCRAP", item.Product.Code))
+			}
+
+			if err := tx.Model(&models.CustomerReturnItem{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+				"disposition":     disposition,
+				"inspection_note": itemReq.InspectionNote,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update customer return item: %w", err)
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(customerReturn).Updates(map[string]interface{}{
+			"status":       models.CustomerReturnStatusInspected,
+			"inspected_by": userID,
+			"inspected_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update customer return: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReturnByID(ctx, tenantID, companyID, returnID)
+}
+
+// CompleteReturn posts the inspected goods and issues the credit note (INSPECTED → COMPLETED).
+// Sellable goods go back into the delivered batch, quarantined goods into a batch of their
+// own on quality hold, and scrapped goods are booked in and written off with a DAMAGED movement.
+func (s *CustomerReturnService) CompleteReturn(
+	ctx context.Context,
+	tenantID, companyID, returnID, userID string,
+) (*models.CustomerReturn, error) {
+	var current models.CustomerReturn
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Select("id", "invoice_id").
+		Where("id = ? AND company_id = ?", returnID, companyID).
+		First(&current).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Customer return")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	var creditNoteNumber string
+	if current.InvoiceID != nil {
+		number, err := s.creditNoteService.NextNumber(ctx, tenantID, companyID)
+		if err != nil {
+			return nil, err
+		}
+		creditNoteNumber = number
+	}
+
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		customerReturn, err := s.lockReturn(tx, companyID, returnID)
+		if err != nil {
+			return err
+		}
+		if customerReturn.Status != models.CustomerReturnStatusInspected {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Only INSPECTED returns can be completed, return is %s", customerReturn.Status))
+		}
+
+		var items []models.CustomerReturnItem
+		if err := tx.Preload("Product").Preload("Batch").
+			Where("customer_return_id = ?", customerReturn.ID).
+			Find(&items).Error; err != nil {
+			return fmt.Errorf("failed to load customer return items: %w", err)
+		}
+
+		now := time.Now()
+		var lines []creditnote.CreditLine
+		for i := range items {
+			if err := s.postItem(tx, customerReturn, &items[i], userID, now); err != nil {
+				return err
+			}
+
+			item := &items[i]
+			if item.InvoiceItemID != nil && item.Amount.IsPositive() {
+				lines = append(lines, creditnote.CreditLine{
+					InvoiceItemID:        item.InvoiceItemID,
+					CustomerReturnItemID: &item.ID,
+					ProductID:            &item.ProductID,
+					Description:          item.Product.Name,
+					Quantity:             item.BaseQuantity,
+					UnitPrice:            item.UnitPrice,
+					Subtotal:             item.Amount,
+				})
+			}
+		}
+
+		updates := map[string]interface{}{
+			"status":       models.CustomerReturnStatusCompleted,
+			"completed_by": userID,
+			"completed_at": now,
+		}
+		if customerReturn.InvoiceID != nil && len(lines) > 0 {
+			creditNote, err := s.creditNoteService.IssueForInvoice(tx, &creditnote.InvoiceCredit{
+				TenantID:         customerReturn.TenantID,
+				CompanyID:        customerReturn.CompanyID,
+				InvoiceID:        *customerReturn.InvoiceID,
+				CreditNoteNumber: creditNoteNumber,
+				CustomerReturnID: &customerReturn.ID,
+				Category:         models.SalesNoteCategoryReturn,
+				CreditNoteDate:   customerReturn.ReturnDate,
+				Reason:           fmt.Sprintf("Return %s: %s", customerReturn.ReturnNumber, customerReturn.Reason),
+				Lines:            lines,
+				CreatedBy:        userID,
+			})
+			if err != nil {
+				return err
+			}
+			updates["credit_note_id"] = creditNote.ID
+		}
+
+		if err := tx.Model(customerReturn).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update customer return: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReturnByID(ctx, tenantID, companyID, returnID)
+}
+
+// CancelReturn cancels a return that has not been posted yet and frees its serials
+func (s *CustomerReturnService) CancelReturn(
+	ctx context.Context,
+	tenantID, companyID, returnID string,
+) (*models.CustomerReturn, error) {
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		customerReturn, err := s.lockReturn(tx, companyID, returnID)
+		if err != nil {
+			return err
+		}
+		if customerReturn.Status != models.CustomerReturnStatusDraft && customerReturn.Status != models.CustomerReturnStatusInspected {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Customer return is already %s", customerReturn.Status))
+		}
+
+		if err := s.serialService.Release(tx, inventory.ReferenceTypeCustomerReturn, customerReturn.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(customerReturn).Update("status", models.CustomerReturnStatusCancelled).Error; err != nil {
+			return fmt.Errorf("failed to cancel customer return: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReturnByID(ctx, tenantID, companyID, returnID)
+}
+
+// ============================================================================
+// QUERIES
+// ============================================================================
+
+// GetReturnByID retrieves a customer return with its lines
+func (s *CustomerReturnService) GetReturnByID(ctx context.Context, tenantID, companyID, returnID string) (*models.CustomerReturn, error) {
+	var customerReturn models.CustomerReturn
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Customer").
+		Preload("Delivery").
+		Preload("Invoice").
+		Preload("Warehouse").
+		Preload("CreditNote").
+		Preload("Items.Product").
+		Preload("Items.Batch").
+		Preload("Items.ResultBatch").
+		Where("id = ? AND company_id = ?", returnID, companyID).
+		First(&customerReturn).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Customer return")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	return &customerReturn, nil
+}
+
+// ListReturns lists customer returns with filters and pagination
+func (s *CustomerReturnService) ListReturns(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.CustomerReturnQuery,
+) ([]models.CustomerReturn, *dto.PaginationInfo, error) {
+	var returns []models.CustomerReturn
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("customer_returns.company_id = ?", companyID)
+
+	if query.Status != nil {
+		db = db.Where("customer_returns.status = ?", *query.Status)
+	}
+	if query.CustomerID != nil {
+		db = db.Where("customer_returns.customer_id = ?", *query.CustomerID)
+	}
+	if query.DeliveryID != nil {
+		db = db.Where("customer_returns.delivery_id = ?", *query.DeliveryID)
+	}
+	if query.Search != "" {
+		db = db.Where("customer_returns.return_number LIKE ?", "%"+query.Search+"%")
+	}
+
+	if err := db.Session(&gorm.Session{}).Model(&models.CustomerReturn{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Session(&gorm.Session{}).
+		Order("customer_returns.return_date DESC, customer_returns.return_number DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Customer").
+		Preload("Delivery").
+		Preload("Invoice").
+		Preload("Warehouse").
+		Preload("CreditNote").
+		Preload("Items.Product").
+		Preload("Items.Batch").
+		Preload("Items.ResultBatch").
+		Find(&returns).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return returns, pagination, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// findInvoice returns the invoice the returned goods are credited on: the one given,
+// or else the invoice raised for the delivery (nil when the delivery is not invoiced)
+func (s *CustomerReturnService) findInvoice(tx *gorm.DB, delivery *models.Delivery, invoiceID *string) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if invoiceID != nil {
+		if err := tx.Where("id = ? AND company_id = ?", *invoiceID, delivery.CompanyID).First(&invoice).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, pkgerrors.NewNotFoundError("Invoice")
+			}
+			return nil, fmt.Errorf("failed to load invoice: %w", err)
+		}
+		if invoice.CustomerID != delivery.CustomerID {
+			return nil, pkgerrors.NewBadRequestError("invoice belongs to another customer than the delivery")
+		}
+		return &invoice, nil
+	}
+
+	err := tx.Where("company_id = ? AND delivery_id = ?", delivery.CompanyID, delivery.ID).
+		Order("invoice_date ASC").
+		First(&invoice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find delivery invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// createItem validates one returned delivery line, prices it from its invoice line
+// and picks its serials. requested tracks base quantity already taken by earlier
+// lines of the same request.
+func (s *CustomerReturnService) createItem(
+	tx *gorm.DB,
+	customerReturn *models.CustomerReturn,
+	delivery *models.Delivery,
+	invoice *models.Invoice,
+	itemReq *dto.CreateCustomerReturnItemRequest,
+	requested map[string]decimal.Decimal,
+) (*models.CustomerReturnItem, error) {
+	var deliveryItem models.DeliveryItem
+	if err := tx.Preload("Product").
+		Where("id = ? AND delivery_id = ?", itemReq.DeliveryItemID, delivery.ID).
+		First(&deliveryItem).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("delivery item %s is not on delivery %s", itemReq.DeliveryItemID, delivery.DeliveryNumber))
+		}
+		return nil, fmt.Errorf("failed to load delivery item: %w", err)
+	}
+	product := deliveryItem.Product
+
+	quantity, err := decimal.NewFromString(itemReq.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid return quantity for product %s", product.Code))
+	}
+
+	// Same unit as the delivery line
+	deliveredBase := deliveryItem.BaseQuantity
+	if deliveredBase.IsZero() {
+		deliveredBase = deliveryItem.Quantity
+	}
+	baseQuantity := quantity
+	if !deliveryItem.Quantity.IsZero() {
+		baseQuantity = quantity.Mul(deliveredBase).Div(deliveryItem.Quantity).Round(3)
+	}
+
+	var alreadyReturned decimal.Decimal
+	if err := tx.Model(&models.CustomerReturnItem{}).
+		Joins("JOIN customer_returns ON customer_returns.id = customer_return_items.customer_return_id").
+		Where("customer_return_items.delivery_item_id = ? AND customer_returns.status <> ?", deliveryItem.ID, models.CustomerReturnStatusCancelled).
+		Where("customer_returns.id <> ?", customerReturn.ID).
+		Select("COALESCE(SUM(customer_return_items.base_quantity), 0)").
+		Scan(&alreadyReturned).Error; err != nil {
+		return nil, fmt.Errorf("failed to check returned quantity: %w", err)
+	}
+	// Units a recall already called back come home on its RETURN delivery
+	var recallReturned decimal.Decimal
+	if err := tx.Model(&models.ProductRecallDelivery{}).
+		Joins("JOIN deliveries ON deliveries.id = product_recall_deliveries.return_delivery_id").
+		Where("product_recall_deliveries.delivery_item_id = ? AND deliveries.status <> ?", deliveryItem.ID, models.DeliveryStatusCancelled).
+		Select("COALESCE(SUM(product_recall_deliveries.quantity), 0)").
+		Scan(&recallReturned).Error; err != nil {
+		return nil, fmt.Errorf("failed to check recalled quantity: %w", err)
+	}
+	returnable := deliveredBase.Sub(alreadyReturned).Sub(recallReturned).Sub(requested[deliveryItem.ID])
+	if baseQuantity.GreaterThan(returnable) {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("return quantity for product %s exceeds what is left to return (%s)", product.Code, returnable.String()))
+	}
+	requested[deliveryItem.ID] = requested[deliveryItem.ID].Add(baseQuantity)
+
+	item := &models.CustomerReturnItem{
+		CustomerReturnID: customerReturn.ID,
+		DeliveryItemID:   deliveryItem.ID,
+		ProductID:        deliveryItem.ProductID,
+		ProductUnitID:    deliveryItem.ProductUnitID,
+		BatchID:          deliveryItem.BatchID,
+		Quantity:         quantity,
+		BaseQuantity:     baseQuantity,
+		Notes:            itemReq.Notes,
+	}
+
+	if invoice != nil {
+		invoiceItem, err := s.findInvoiceItem(tx, invoice, &deliveryItem, itemReq.InvoiceItemID)
+		if err != nil {
+			return nil, err
+		}
+		invoicedBase := invoiceItem.BaseQuantity
+		if invoicedBase.IsZero() {
+			invoicedBase = invoiceItem.Quantity
+		}
+		if invoicedBase.IsPositive() {
+			item.Amount = invoiceItem.Subtotal.Mul(baseQuantity).Div(invoicedBase).Round(2)
+			item.UnitPrice = item.Amount.Div(baseQuantity).Round(2)
+		}
+		item.InvoiceItemID = &invoiceItem.ID
+	}
+
+	if err := tx.Create(item).Error; err != nil {
+		return nil, fmt.Errorf("failed to create customer return item: %w", err)
+	}
+
+	if !product.IsSerialTracked {
+		if len(itemReq.SerialNumbers) > 0 {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not serial-tracked", product.Code))
+		}
+		return item, nil
+	}
+	if _, err := s.serialService.Assign(tx, &serial.SerialAssignment{
+		CompanyID:     customerReturn.CompanyID,
+		ProductID:     item.ProductID,
+		ReferenceType: inventory.ReferenceTypeCustomerReturn,
+		ReferenceID:   customerReturn.ID,
+		LineID:        item.ID,
+		Quantity:      item.BaseQuantity,
+		SerialNumbers: itemReq.SerialNumbers,
+		Status:        models.SerialStatusDelivered,
+		CustomerID:    &customerReturn.CustomerID,
+		BatchID:       item.BatchID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// findInvoiceItem resolves the invoice line a delivery line was billed on: the line
+// given, else the line raised from the delivery line, else the line for its sales order line
+func (s *CustomerReturnService) findInvoiceItem(tx *gorm.DB, invoice *models.Invoice, deliveryItem *models.DeliveryItem, invoiceItemID *string) (*models.InvoiceItem, error) {
+	conditions := [][]interface{}{
+		{"delivery_item_id = ?", deliveryItem.ID},
+		{"delivery_item_id IS NULL AND sales_order_item_id = ?", deliveryItem.SalesOrderItemID},
+	}
+	if invoiceItemID != nil {
+		conditions = [][]interface{}{{"id = ?", *invoiceItemID}}
+	}
+
+	for _, condition := range conditions {
+		var invoiceItem models.InvoiceItem
+		err := tx.Where("invoice_id = ? AND product_id = ?", invoice.ID, deliveryItem.ProductID).
+			Where(condition[0], condition[1:]...).
+			First(&invoiceItem).Error
+		if err == nil {
+			return &invoiceItem, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("failed to load invoice item: %w", err)
+		}
+	}
+
+	return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not invoiced on invoice %s", deliveryItem.Product.Code, invoice.InvoiceNumber))
+}
+
+// postItem books one inspected line back into stock according to its disposition
+// and moves its serials. Items must have Product and Batch loaded.
+func (s *CustomerReturnService) postItem(tx *gorm.DB, customerReturn *models.CustomerReturn, item *models.CustomerReturnItem, userID string, now time.Time) error {
+	if item.Disposition == nil {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s has not been inspected", item.Product.Code))
+	}
+
+	// Goods come back at the cost they left with
+	unitCost, err := s.stockPostingService.OutboundUnitCost(tx, inventory.ReferenceTypeDelivery, customerReturn.DeliveryID, customerReturn.WarehouseID, item.ProductID)
+	if err != nil {
+		return err
+	}
+
+	posting := &inventory.StockPosting{
+		TenantID:        customerReturn.TenantID,
+		CompanyID:       customerReturn.CompanyID,
+		WarehouseID:     customerReturn.WarehouseID,
+		ProductID:       item.ProductID,
+		MovementType:    models.MovementTypeReturn,
+		Quantity:        item.BaseQuantity,
+		MovementDate:    now,
+		UnitCost:        unitCost,
+		BatchID:         item.BatchID,
+		ReferenceType:   inventory.ReferenceTypeCustomerReturn,
+		ReferenceID:     customerReturn.ID,
+		ReferenceNumber: customerReturn.ReturnNumber,
+		Notes:           item.InspectionNote,
+		CreatedBy:       userID,
+	}
+
+	// Quarantined goods get a batch of their own so the delivered batch stays sellable
+	disposition := *item.Disposition
+	if disposition == models.ReturnDispositionQuarantine {
+		if item.Batch == nil {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s has no batch and cannot be quarantined", item.Product.Code))
+		}
+		posting.BatchID = nil
+		posting.Batch = &inventory.BatchDetails{
+			BatchNumber:     fmt.Sprintf("%s/%s", item.Batch.BatchNumber, customerReturn.ReturnNumber),
+			ManufactureDate: item.Batch.ManufactureDate,
+			ExpiryDate:      item.Batch.ExpiryDate,
+			SupplierID:      item.Batch.SupplierID,
+			GoodsReceiptID:  item.Batch.GoodsReceiptID,
+			ReferenceNumber: item.Batch.ReferenceNumber,
+			Quarantine:      true,
+		}
+	}
+
+	result, err := s.stockPostingService.Post(tx, posting)
+	if err != nil {
+		return err
+	}
+
+	var resultBatchID *string
+	if result.Batch != nil {
+		resultBatchID = &result.Batch.ID
+	}
+
+	switch disposition {
+	case models.ReturnDispositionQuarantine:
+		reason := ""
+		if item.InspectionNote != nil {
+			reason = *item.InspectionNote
+		}
+		if _, err := s.qualityHoldService.HoldReceipt(tx, &qualityhold.ReceiptHold{
+			TenantID:         customerReturn.TenantID,
+			CompanyID:        customerReturn.CompanyID,
+			WarehouseID:      customerReturn.WarehouseID,
+			ProductID:        item.ProductID,
+			BatchID:          result.Batch.ID,
+			Source:           models.QualityHoldSourceCustomerReturn,
+			CustomerReturnID: &customerReturn.ID,
+			ReferenceNumber:  customerReturn.ReturnNumber,
+			Quantity:         item.BaseQuantity,
+			Reason:           reason,
+			CreatedBy:        userID,
+		}); err != nil {
+			return err
+		}
+
+	case models.ReturnDispositionScrap:
+		notes := fmt.Sprintf("Scrapped on inspection of return %s", customerReturn.ReturnNumber)
+		if item.InspectionNote != nil && *item.InspectionNote != "" {
+			notes = fmt.Sprintf("%s: %s", notes, *item.InspectionNote)
+		}
+		if _, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:        customerReturn.TenantID,
+			CompanyID:       customerReturn.CompanyID,
+			WarehouseID:     customerReturn.WarehouseID,
+			ProductID:       item.ProductID,
+			MovementType:    models.MovementTypeDamaged,
+			Quantity:        item.BaseQuantity.Neg(),
+			MovementDate:    now,
+			BatchID:         item.BatchID,
+			ReferenceType:   inventory.ReferenceTypeCustomerReturn,
+			ReferenceID:     customerReturn.ID,
+			ReferenceNumber: customerReturn.ReturnNumber,
+			Notes:           &notes,
+			CreatedBy:       userID,
+		}); err != nil {
+			return err
+		}
+		resultBatchID = nil
+	}
+
+	if resultBatchID != nil {
+		if err := tx.Model(&models.CustomerReturnItem{}).Where("id = ?", item.ID).
+			Update("result_batch_id", *resultBatchID).Error; err != nil {
+			return fmt.Errorf("failed to update customer return item: %w", err)
+		}
+	}
+
+	if !item.Product.IsSerialTracked {
+		return nil
+	}
+	return s.moveSerials(tx, customerReturn, item, resultBatchID, userID, now)
+}
+
+// moveSerials brings the serials of a posted line back in stock, then writes off
+// those of a scrapped line
+func (s *CustomerReturnService) moveSerials(tx *gorm.DB, customerReturn *models.CustomerReturn, item *models.CustomerReturnItem, batchID *string, userID string, now time.Time) error {
+	move := &serial.SerialMove{
+		TenantID:        customerReturn.TenantID,
+		CompanyID:       customerReturn.CompanyID,
+		EventType:       models.SerialEventReturned,
+		EventDate:       now,
+		FromStatus:      models.SerialStatusDelivered,
+		Status:          models.SerialStatusInStock,
+		WarehouseID:     &customerReturn.WarehouseID,
+		BatchID:         batchID,
+		ReferenceType:   inventory.ReferenceTypeCustomerReturn,
+		ReferenceID:     customerReturn.ID,
+		ReferenceNumber: customerReturn.ReturnNumber,
+		Notes:           item.InspectionNote,
+	}
+	if userID != "" {
+		move.CreatedBy = &userID
+	}
+	if err := s.serialService.MoveLine(tx, item.ID, move); err != nil {
+		return err
+	}
+
+	if *item.Disposition != models.ReturnDispositionScrap {
+		return nil
+	}
+	scrap := *move
+	scrap.EventType = models.SerialEventScrapped
+	scrap.FromStatus = models.SerialStatusInStock
+	scrap.FromWarehouseID = &customerReturn.WarehouseID
+	scrap.Status = models.SerialStatusScrapped
+	scrap.WarehouseID = nil
+	scrap.BatchID = nil
+	return s.serialService.MoveLine(tx, item.ID, &scrap)
+}
+
+// lockReturn loads a customer return of the company for update
+func (s *CustomerReturnService) lockReturn(tx *gorm.DB, companyID, returnID string) (*models.CustomerReturn, error) {
+	var customerReturn models.CustomerReturn
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND company_id = ?", returnID, companyID).
+		First(&customerReturn).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Customer return")
+		}
+		return nil, fmt.Errorf("failed to load customer return: %w", err)
+	}
+	return &customerReturn, nil
+}
+
+// generateReturnNumber generates unique customer return number for company
+func (s *CustomerReturnService) generateReturnNumber(tx *gorm.DB, tenantID, companyID string) (string, error) {
+	var count int64
+	currentYear := time.Now().Year()
+	prefix := fmt.Sprintf("RMA-%d-", currentYear)
+
+	if err := tx.Model(&models.CustomerReturn{}).
+		Where("company_id = ? AND tenant_id = ? AND return_number LIKE ?", companyID, tenantID, prefix+"%").
+		Count(&count).Error; err != nil {
+		return "", pkgerrors.NewInternalError(err)
+	}
+
+	return fmt.Sprintf("%s%05d", prefix, count+1), nil
+}
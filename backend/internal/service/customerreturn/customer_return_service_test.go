@@ -0,0 +1,388 @@
+package customerreturn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/creditnote"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/service/recall"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerReturnService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.Delivery{},
+		&models.DeliveryItem{},
+		&models.Invoice{},
+		&models.InvoiceItem{},
+		&models.QualityHold{},
+		&models.QualityHoldAction{},
+		&models.DocumentLineSerial{},
+		&models.CustomerReturn{},
+		&models.CustomerReturnItem{},
+		&models.SalesCreditNote{},
+		&models.SalesCreditNoteItem{},
+		&models.SalesCreditNoteApplication{},
+		&models.SalesCreditNoteRefund{},
+		&models.SalesDebitNote{},
+		&models.SalesDebitNoteItem{},
+		&models.ProductRecall{},
+		&models.ProductRecallBatch{},
+		&models.ProductRecallDelivery{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	user := testutil.CreateTestUser(t, db, "gudang@example.com")
+
+	product := &models.Product{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		Code:           "PROD001",
+		Name:           "Minyak Goreng 2L",
+		BaseUnit:       "PCS",
+		IsBatchTracked: true,
+		IsActive:       true,
+	}
+	require.NoError(t, db.Create(product).Error)
+
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+
+	postingService := inventory.NewStockPostingService(db)
+	service := NewCustomerReturnService(db, document.NewDocumentNumberGenerator(db), postingService)
+
+	unitCost := decimal.NewFromInt(5000)
+	received, err := postingService.Post(db, &inventory.StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeIn,
+		Quantity:     decimal.NewFromInt(20),
+		UnitCost:     &unitCost,
+		Batch:        &inventory.BatchDetails{BatchNumber: "B-001"},
+	})
+	require.NoError(t, err)
+	batch := received.Batch
+
+	// Delivered 10 units, invoiced at 10.000 + 11% tax
+	delivery := &models.Delivery{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		DeliveryNumber: "DEL/2026/10/0001",
+		DeliveryDate:   time.Now().AddDate(0, 0, -3),
+		SalesOrderID:   "so-1",
+		WarehouseID:    warehouse.ID,
+		CustomerID:     customer.ID,
+		Type:           models.DeliveryTypeNormal,
+		Status:         models.DeliveryStatusDelivered,
+	}
+	require.NoError(t, db.Create(delivery).Error)
+	deliveryItem := &models.DeliveryItem{
+		DeliveryID:       delivery.ID,
+		SalesOrderItemID: "so-item-1",
+		ProductID:        product.ID,
+		BatchID:          &batch.ID,
+		Quantity:         decimal.NewFromInt(10),
+		BaseQuantity:     decimal.NewFromInt(10),
+	}
+	require.NoError(t, db.Create(deliveryItem).Error)
+	_, err = postingService.Post(db, &inventory.StockPosting{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		WarehouseID:   warehouse.ID,
+		ProductID:     product.ID,
+		MovementType:  models.MovementTypeOut,
+		Quantity:      decimal.NewFromInt(-10),
+		BatchID:       &batch.ID,
+		ReferenceType: inventory.ReferenceTypeDelivery,
+		ReferenceID:   delivery.ID,
+	})
+	require.NoError(t, err)
+
+	invoice := &models.Invoice{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		InvoiceNumber: "INV-2026-00001",
+		InvoiceDate:   time.Now().AddDate(0, 0, -3),
+		DueDate:       time.Now().AddDate(0, 0, 27),
+		CustomerID:    customer.ID,
+		DeliveryID:    &delivery.ID,
+		Subtotal:      decimal.NewFromInt(100000),
+		TaxAmount:     decimal.NewFromInt(11000),
+		TotalAmount:   decimal.NewFromInt(111000),
+		PaymentStatus: models.PaymentStatusUnpaid,
+	}
+	require.NoError(t, db.Create(invoice).Error)
+	invoiceItem := &models.InvoiceItem{
+		InvoiceID:      invoice.ID,
+		DeliveryItemID: &deliveryItem.ID,
+		ProductID:      product.ID,
+		Quantity:       decimal.NewFromInt(10),
+		BaseQuantity:   decimal.NewFromInt(10),
+		UnitPrice:      decimal.NewFromInt(10000),
+		Subtotal:       decimal.NewFromInt(100000),
+	}
+	require.NoError(t, db.Create(invoiceItem).Error)
+
+	stock := func() models.WarehouseStock {
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		return stock
+	}
+	reloadInvoice := func() models.Invoice {
+		var current models.Invoice
+		require.NoError(t, db.First(&current, "id = ?", invoice.ID).Error)
+		return current
+	}
+	returnRequest := func(quantities ...string) *dto.CreateCustomerReturnRequest {
+		req := &dto.CreateCustomerReturnRequest{
+			DeliveryID: delivery.ID,
+			ReturnDate: time.Now().Format("2006-01-02"),
+			Reason:     "Kemasan penyok",
+		}
+		for _, quantity := range quantities {
+			req.Items = append(req.Items, dto.CreateCustomerReturnItemRequest{
+				DeliveryItemID: deliveryItem.ID,
+				Quantity:       quantity,
+			})
+		}
+		return req
+	}
+
+	var firstReturnID string
+	t.Run("success - return priced from the delivery's invoice", func(t *testing.T) {
+		customerReturn, err := service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, returnRequest("2", "2"))
+
+		require.NoError(t, err)
+		firstReturnID = customerReturn.ID
+		assert.Equal(t, models.CustomerReturnStatusDraft, customerReturn.Status)
+		require.NotNil(t, customerReturn.InvoiceID)
+		assert.Equal(t, invoice.ID, *customerReturn.InvoiceID)
+		require.Len(t, customerReturn.Items, 2)
+		assert.Equal(t, "20000", customerReturn.Items[0].Amount.String())
+		assert.Equal(t, "10000", customerReturn.Items[0].UnitPrice.String())
+		assert.Equal(t, batch.ID, *customerReturn.Items[0].BatchID)
+	})
+
+	t.Run("error - returning more than was delivered", func(t *testing.T) {
+		_, err := service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, returnRequest("7"))
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	})
+
+	t.Run("error - complete before inspection", func(t *testing.T) {
+		_, err := service.CompleteReturn(ctx, company.TenantID, company.ID, firstReturnID, user.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("success - complete posts sellable and quarantined goods and credits the invoice", func(t *testing.T) {
+		current, err := service.GetReturnByID(ctx, company.TenantID, company.ID, firstReturnID)
+		require.NoError(t, err)
+		inspected, err := service.InspectReturn(ctx, company.TenantID, company.ID, firstReturnID, user.ID, &dto.InspectCustomerReturnRequest{
+			Items: []dto.InspectCustomerReturnItemRequest{
+				{ItemID: current.Items[0].ID, Disposition: string(models.ReturnDispositionSellable)},
+				{ItemID: current.Items[1].ID, Disposition: string(models.ReturnDispositionQuarantine)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.CustomerReturnStatusInspected, inspected.Status)
+
+		completed, err := service.CompleteReturn(ctx, company.TenantID, company.ID, firstReturnID, user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.CustomerReturnStatusCompleted, completed.Status)
+		require.NotNil(t, completed.CreditNote)
+		assert.Equal(t, "40000", completed.CreditNote.Subtotal.String())
+		assert.Equal(t, "4400", completed.CreditNote.TaxAmount.String())
+		assert.Equal(t, "44400", completed.CreditNote.TotalAmount.String())
+		assert.Equal(t, models.SalesCreditNoteStatusApplied, completed.CreditNote.Status)
+
+		current2 := stock()
+		assert.Equal(t, "14", current2.Quantity.String())
+		assert.Equal(t, "2", current2.QuarantineQuantity.String())
+
+		var quarantineItem models.CustomerReturnItem
+		for _, item := range completed.Items {
+			if *item.Disposition == models.ReturnDispositionQuarantine {
+				quarantineItem = item
+			}
+		}
+		require.NotNil(t, quarantineItem.ResultBatch)
+		assert.Equal(t, "B-001/"+completed.ReturnNumber, quarantineItem.ResultBatch.BatchNumber)
+		assert.Equal(t, models.BatchStatusQuarantine, quarantineItem.ResultBatch.Status)
+
+		var hold models.QualityHold
+		require.NoError(t, db.Where("batch_id = ?", quarantineItem.ResultBatch.ID).First(&hold).Error)
+		assert.Equal(t, models.QualityHoldSourceCustomerReturn, hold.Source)
+		assert.Equal(t, firstReturnID, *hold.CustomerReturnID)
+
+		var movements []models.InventoryMovement
+		require.NoError(t, db.Where("reference_type = ? AND reference_id = ?", inventory.ReferenceTypeCustomerReturn, firstReturnID).
+			Find(&movements).Error)
+		require.Len(t, movements, 2)
+		assert.Equal(t, "5000", movements[0].UnitCost.String())
+
+		updated := reloadInvoice()
+		assert.Equal(t, "44400", updated.CreditedAmount.String())
+		assert.Equal(t, "66600", updated.RemainingAmount().String())
+		assert.Equal(t, models.PaymentStatusPartial, updated.PaymentStatus)
+	})
+
+	t.Run("success - scrap after the invoice is paid becomes customer credit", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.Invoice{}).Where("id = ?", invoice.ID).
+			Updates(map[string]interface{}{"paid_amount": decimal.NewFromInt(66600), "payment_status": models.PaymentStatusPaid}).Error)
+
+		customerReturn, err := service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, returnRequest("1"))
+		require.NoError(t, err)
+		_, err = service.InspectReturn(ctx, company.TenantID, company.ID, customerReturn.ID, user.ID, &dto.InspectCustomerReturnRequest{
+			Items: []dto.InspectCustomerReturnItemRequest{
+				{ItemID: customerReturn.Items[0].ID, Disposition: string(models.ReturnDispositionScrap)},
+			},
+		})
+		require.NoError(t, err)
+
+		completed, err := service.CompleteReturn(ctx, company.TenantID, company.ID, customerReturn.ID, user.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, completed.CreditNote)
+		assert.Equal(t, "11100", completed.CreditNote.TotalAmount.String())
+		assert.True(t, completed.CreditNote.AppliedAmount.IsZero())
+		assert.Equal(t, models.SalesCreditNoteStatusOpen, completed.CreditNote.Status)
+
+		// Booked in and written off again
+		assert.Equal(t, "14", stock().Quantity.String())
+
+		var updatedCustomer models.Customer
+		require.NoError(t, db.First(&updatedCustomer, "id = ?", customer.ID).Error)
+		assert.Equal(t, "11100", updatedCustomer.CreditBalance.String())
+		assert.Equal(t, "44400", reloadInvoice().CreditedAmount.String())
+	})
+
+	var pendingReturnID string
+	t.Run("success - cancelled return frees the delivered quantity", func(t *testing.T) {
+		// 5 of 10 already returned
+		customerReturn, err := service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, returnRequest("5"))
+		require.NoError(t, err)
+
+		cancelled, err := service.CancelReturn(ctx, company.TenantID, company.ID, customerReturn.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.CustomerReturnStatusCancelled, cancelled.Status)
+
+		pending, err := service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, returnRequest("5"))
+		require.NoError(t, err)
+		pendingReturnID = pending.ID
+	})
+
+	t.Run("error - return credit beyond what a price correction left on the line", func(t *testing.T) {
+		// 50.000 of the line already credited by returns; the correction takes another 20.000
+		creditNoteService := creditnote.NewCreditNoteService(db, document.NewDocumentNumberGenerator(db))
+		unitPrice := "2000"
+		_, err := creditNoteService.CreateCreditNote(ctx, company.TenantID, company.ID, &dto.CreateSalesCreditNoteRequest{
+			InvoiceID:      invoice.ID,
+			CreditNoteDate: time.Now().Format("2006-01-02"),
+			Category:       string(models.SalesNoteCategoryPriceCorrection),
+			Reason:         "Koreksi harga",
+			Items: []dto.CreateSalesCreditNoteItemRequest{
+				{InvoiceItemID: &invoiceItem.ID, Quantity: "10", UnitPrice: &unitPrice},
+			},
+		}, user.ID)
+		require.NoError(t, err)
+
+		pending, err := service.GetReturnByID(ctx, company.TenantID, company.ID, pendingReturnID)
+		require.NoError(t, err)
+		_, err = service.InspectReturn(ctx, company.TenantID, company.ID, pendingReturnID, user.ID, &dto.InspectCustomerReturnRequest{
+			Items: []dto.InspectCustomerReturnItemRequest{
+				{ItemID: pending.Items[0].ID, Disposition: string(models.ReturnDispositionSellable)},
+			},
+		})
+		require.NoError(t, err)
+		before := stock().Quantity
+
+		_, err = service.CompleteReturn(ctx, company.TenantID, company.ID, pendingReturnID, user.ID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds its remaining creditable amount 30000.00")
+		assert.Equal(t, before.String(), stock().Quantity.String())
+	})
+
+	t.Run("success - list returns", func(t *testing.T) {
+		status := string(models.CustomerReturnStatusCompleted)
+		returns, pagination, err := service.ListReturns(ctx, company.TenantID, company.ID, &dto.CustomerReturnQuery{
+			Page:     1,
+			PageSize: 20,
+			Status:   &status,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, pagination.Total)
+		assert.Len(t, returns, 2)
+	})
+
+	t.Run("error - units already called back by a recall cannot be returned again", func(t *testing.T) {
+		recalledDelivery := &models.Delivery{
+			TenantID:       company.TenantID,
+			CompanyID:      company.ID,
+			DeliveryNumber: "DEL/2026/10/0002",
+			DeliveryDate:   time.Now().AddDate(0, 0, -1),
+			SalesOrderID:   "so-2",
+			WarehouseID:    warehouse.ID,
+			CustomerID:     customer.ID,
+			Type:           models.DeliveryTypeNormal,
+			Status:         models.DeliveryStatusDelivered,
+		}
+		require.NoError(t, db.Create(recalledDelivery).Error)
+		recalledItem := &models.DeliveryItem{
+			DeliveryID:       recalledDelivery.ID,
+			SalesOrderItemID: "so-item-2",
+			ProductID:        product.ID,
+			BatchID:          &batch.ID,
+			Quantity:         decimal.NewFromInt(4),
+			BaseQuantity:     decimal.NewFromInt(4),
+		}
+		require.NoError(t, db.Create(recalledItem).Error)
+
+		recallService := recall.NewRecallService(db, document.NewDocumentNumberGenerator(db))
+		productRecall, err := recallService.CreateRecall(ctx, company.TenantID, company.ID, user.ID, &dto.CreateProductRecallRequest{
+			BatchID: &batch.ID,
+			Reason:  "Supplier contamination notice",
+		})
+		require.NoError(t, err)
+		// The first delivery is fully covered by customer returns, so only the new one is called back
+		require.Len(t, productRecall.Deliveries, 1)
+		assert.Equal(t, recalledItem.ID, productRecall.Deliveries[0].DeliveryItemID)
+		assert.Equal(t, "4", productRecall.DeliveredQuantity.String())
+
+		_, err = service.CreateReturn(ctx, company.TenantID, company.ID, user.ID, &dto.CreateCustomerReturnRequest{
+			DeliveryID: recalledDelivery.ID,
+			ReturnDate: time.Now().Format("2006-01-02"),
+			Reason:     "Kemasan penyok",
+			Items: []dto.CreateCustomerReturnItemRequest{
+				{DeliveryItemID: recalledItem.ID, Quantity: "1"},
+			},
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds what is left to return (0)")
+	})
+}
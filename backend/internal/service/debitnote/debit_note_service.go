@@ -0,0 +1,251 @@
+package debitnote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// DebitNoteService - Sales debit notes against invoices.
+// A debit note charges the customer more on an invoice (undercharged prices, extra
+// charges). It takes its discount and tax share from the invoice and adds its total to
+// what is due on the invoice.
+type DebitNoteService struct {
+	db           *gorm.DB
+	docNumberGen *document.DocumentNumberGenerator
+}
+
+// NewDebitNoteService creates a new debit note service instance
+func NewDebitNoteService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator) *DebitNoteService {
+	return &DebitNoteService{
+		db:           db,
+		docNumberGen: docNumberGen,
+	}
+}
+
+// ============================================================================
+// ISSUE
+// ============================================================================
+
+// CreateDebitNote issues a debit note against an invoice and reopens the invoice for
+// the added amount
+func (s *DebitNoteService) CreateDebitNote(
+	ctx context.Context,
+	tenantID, companyID string,
+	req *dto.CreateSalesDebitNoteRequest,
+	userID string,
+) (*models.SalesDebitNote, error) {
+	debitNoteDate, err := time.Parse("2006-01-02", req.DebitNoteDate)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid debit note date format, use YYYY-MM-DD")
+	}
+
+	debitNoteNumber, err := s.docNumberGen.GenerateNumber(ctx, tenantID, companyID, document.DocTypeSalesDebitNote)
+	if err != nil {
+		return nil, pkgerrors.NewInternalError(fmt.Errorf("failed to generate debit note number: %w", err))
+	}
+
+	var debitNote *models.SalesDebitNote
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND company_id = ?", req.InvoiceID, companyID).
+			First(&invoice).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("Invoice")
+			}
+			return fmt.Errorf("failed to load invoice: %w", err)
+		}
+
+		var invoiceItems []models.InvoiceItem
+		if err := tx.Preload("Product").Where("invoice_id = ?", invoice.ID).Find(&invoiceItems).Error; err != nil {
+			return fmt.Errorf("failed to load invoice items: %w", err)
+		}
+
+		items := make([]models.SalesDebitNoteItem, 0, len(req.Items))
+		subtotal := decimal.Zero
+		for i, itemReq := range req.Items {
+			item, err := buildDebitItem(invoiceItems, &itemReq, i)
+			if err != nil {
+				return err
+			}
+			subtotal = subtotal.Add(item.Subtotal)
+			items = append(items, *item)
+		}
+		discountAmount, taxAmount := invoice.AdjustmentShare(subtotal)
+
+		debitNote = &models.SalesDebitNote{
+			TenantID:        tenantID,
+			CompanyID:       companyID,
+			DebitNoteNumber: debitNoteNumber,
+			DebitNoteDate:   debitNoteDate,
+			CustomerID:      invoice.CustomerID,
+			InvoiceID:       invoice.ID,
+			Category:        models.SalesNoteCategory(req.Category),
+			Reason:          req.Reason,
+			Subtotal:        subtotal,
+			DiscountAmount:  discountAmount,
+			TaxAmount:       taxAmount,
+			TotalAmount:     subtotal.Sub(discountAmount).Add(taxAmount),
+			Notes:           req.Notes,
+		}
+		if userID != "" {
+			debitNote.CreatedBy = &userID
+		}
+		if err := tx.Create(debitNote).Error; err != nil {
+			return fmt.Errorf("failed to create debit note: %w", err)
+		}
+
+		for i := range items {
+			items[i].DebitNoteID = debitNote.ID
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return fmt.Errorf("failed to create debit note item: %w", err)
+			}
+		}
+
+		invoice.DebitedAmount = invoice.DebitedAmount.Add(debitNote.TotalAmount)
+		invoice.UpdatePaymentStatus()
+		if err := tx.Model(&invoice).Updates(map[string]interface{}{
+			"debited_amount": invoice.DebitedAmount,
+			"payment_status": invoice.PaymentStatus,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update invoice: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetDebitNoteByID(ctx, tenantID, companyID, debitNote.ID)
+}
+
+// ============================================================================
+// QUERIES
+// ============================================================================
+
+// GetDebitNoteByID retrieves a debit note with its lines
+func (s *DebitNoteService) GetDebitNoteByID(ctx context.Context, tenantID, companyID, debitNoteID string) (*models.SalesDebitNote, error) {
+	var debitNote models.SalesDebitNote
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Customer").
+		Preload("Invoice").
+		Preload("Items.Product").
+		Where("id = ? AND company_id = ?", debitNoteID, companyID).
+		First(&debitNote).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Debit note")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+	return &debitNote, nil
+}
+
+// ListDebitNotes lists debit notes with filters and pagination
+func (s *DebitNoteService) ListDebitNotes(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.SalesDebitNoteQuery,
+) ([]models.SalesDebitNote, *dto.PaginationInfo, error) {
+	var debitNotes []models.SalesDebitNote
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("sales_debit_notes.company_id = ?", companyID)
+
+	if query.Category != nil {
+		db = db.Where("sales_debit_notes.category = ?", *query.Category)
+	}
+	if query.CustomerID != nil {
+		db = db.Where("sales_debit_notes.customer_id = ?", *query.CustomerID)
+	}
+	if query.InvoiceID != nil {
+		db = db.Where("sales_debit_notes.invoice_id = ?", *query.InvoiceID)
+	}
+	if query.Search != "" {
+		db = db.Where("sales_debit_notes.debit_note_number LIKE ?", "%"+query.Search+"%")
+	}
+
+	if err := db.Session(&gorm.Session{}).Model(&models.SalesDebitNote{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Session(&gorm.Session{}).
+		Order("sales_debit_notes.debit_note_date DESC, sales_debit_notes.debit_note_number DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Customer").
+		Preload("Invoice").
+		Preload("Items.Product").
+		Find(&debitNotes).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return debitNotes, pagination, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// buildDebitItem turns a requested line into a debit note item. Lines on an invoice line
+// are in the product base unit and must belong to the debited invoice.
+func buildDebitItem(invoiceItems []models.InvoiceItem, req *dto.CreateSalesDebitNoteItemRequest, index int) (*models.SalesDebitNoteItem, error) {
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: quantity must be greater than zero", index+1))
+	}
+	unitPrice, err := decimal.NewFromString(req.UnitPrice)
+	if err != nil || !unitPrice.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: unit price must be greater than zero", index+1))
+	}
+
+	item := &models.SalesDebitNoteItem{
+		Quantity:  quantity,
+		UnitPrice: unitPrice,
+		Subtotal:  quantity.Mul(unitPrice).Round(2),
+	}
+	if req.Description != nil {
+		item.Description = *req.Description
+	}
+
+	if req.InvoiceItemID != nil {
+		var invoiceItem *models.InvoiceItem
+		for i := range invoiceItems {
+			if invoiceItems[i].ID == *req.InvoiceItemID {
+				invoiceItem = &invoiceItems[i]
+				break
+			}
+		}
+		if invoiceItem == nil {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: invoice line not found on this invoice", index+1))
+		}
+		item.InvoiceItemID = &invoiceItem.ID
+		item.ProductID = &invoiceItem.ProductID
+		if item.Description == "" {
+			item.Description = invoiceItem.Product.Name
+		}
+	}
+
+	if item.Description == "" {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %d: description is required without an invoice line", index+1))
+	}
+	return item, nil
+}
@@ -0,0 +1,127 @@
+package debitnote
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebitNoteService_CreateDebitNote(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.Invoice{},
+		&models.InvoiceItem{},
+		&models.SalesDebitNote{},
+		&models.SalesDebitNoteItem{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	// Invoice-format numbers end in the month and year issued
+	period := time.Now().Format("01/2006")
+	user := testutil.CreateTestUser(t, db, "finance@example.com")
+
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD001", Name: "Minyak Goreng 2L", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+
+	service := NewDebitNoteService(db, document.NewDocumentNumberGenerator(db))
+
+	// Fully paid: 600.000 less 10% discount, plus 11% tax on 540.000
+	invoice := &models.Invoice{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		InvoiceNumber:  "INV/0001",
+		InvoiceDate:    time.Now().AddDate(0, 0, -5),
+		DueDate:        time.Now().AddDate(0, 0, 25),
+		CustomerID:     customer.ID,
+		Subtotal:       decimal.NewFromInt(600000),
+		DiscountAmount: decimal.NewFromInt(60000),
+		TaxAmount:      decimal.NewFromInt(59400),
+		TotalAmount:    decimal.NewFromInt(599400),
+		PaidAmount:     decimal.NewFromInt(599400),
+		PaymentStatus:  models.PaymentStatusPaid,
+	}
+	require.NoError(t, db.Create(invoice).Error)
+	line := &models.InvoiceItem{
+		InvoiceID:    invoice.ID,
+		ProductID:    product.ID,
+		Quantity:     decimal.NewFromInt(10),
+		BaseQuantity: decimal.NewFromInt(10),
+		UnitPrice:    decimal.NewFromInt(60000),
+		Subtotal:     decimal.NewFromInt(600000),
+	}
+	require.NoError(t, db.Create(line).Error)
+
+	stringPtr := func(s string) *string { return &s }
+	debitRequest := func(items ...dto.CreateSalesDebitNoteItemRequest) *dto.CreateSalesDebitNoteRequest {
+		return &dto.CreateSalesDebitNoteRequest{
+			InvoiceID:     invoice.ID,
+			DebitNoteDate: time.Now().Format("2006-01-02"),
+			Category:      string(models.SalesNoteCategoryPriceCorrection),
+			Reason:        "Harga kurang tagih",
+			Items:         items,
+		}
+	}
+	assertBadRequest := func(t *testing.T, err error) {
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	}
+
+	t.Run("success - debit note reopens a paid invoice", func(t *testing.T) {
+		debitNote, err := service.CreateDebitNote(ctx, company.TenantID, company.ID, debitRequest(
+			dto.CreateSalesDebitNoteItemRequest{InvoiceItemID: &line.ID, Quantity: "10", UnitPrice: "2000"},
+		), user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "DN/0001/"+period, debitNote.DebitNoteNumber)
+		assert.Equal(t, "20000", debitNote.Subtotal.String())
+		assert.Equal(t, "2000", debitNote.DiscountAmount.String())
+		assert.Equal(t, "1980", debitNote.TaxAmount.String())
+		assert.Equal(t, "19980", debitNote.TotalAmount.String())
+		require.Len(t, debitNote.Items, 1)
+		assert.Equal(t, product.Name, debitNote.Items[0].Description)
+
+		var current models.Invoice
+		require.NoError(t, db.First(&current, "id = ?", invoice.ID).Error)
+		assert.Equal(t, "19980", current.DebitedAmount.String())
+		assert.Equal(t, "19980", current.RemainingAmount().String())
+		assert.Equal(t, models.PaymentStatusPartial, current.PaymentStatus)
+	})
+
+	t.Run("error - invoice line from another invoice", func(t *testing.T) {
+		_, err := service.CreateDebitNote(ctx, company.TenantID, company.ID, debitRequest(
+			dto.CreateSalesDebitNoteItemRequest{InvoiceItemID: stringPtr("00000000-0000-0000-0000-000000000000"), Quantity: "1", UnitPrice: "1000"},
+		), user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("error - charge without invoice line needs a description", func(t *testing.T) {
+		_, err := service.CreateDebitNote(ctx, company.TenantID, company.ID, debitRequest(
+			dto.CreateSalesDebitNoteItemRequest{Quantity: "1", UnitPrice: "15000"},
+		), user.ID)
+		assertBadRequest(t, err)
+	})
+
+	t.Run("success - list by invoice", func(t *testing.T) {
+		debitNotes, pagination, err := service.ListDebitNotes(ctx, company.TenantID, company.ID, &dto.SalesDebitNoteQuery{Page: 1, PageSize: 20, InvoiceID: &invoice.ID})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, pagination.Total)
+		assert.Equal(t, "INV/0001", debitNotes[0].Invoice.InvoiceNumber)
+	})
+}
@@ -24,13 +24,16 @@ type DocumentNumberGenerator struct {
 type DocumentType string
 
 const (
-	DocTypePurchaseOrder    DocumentType = "purchase_order"
-	DocTypeSalesOrder       DocumentType = "sales_order"
-	DocTypePurchaseInvoice  DocumentType = "purchase_invoice"
-	DocTypeSalesInvoice     DocumentType = "sales_invoice"
-	DocTypeSupplierPayment  DocumentType = "supplier_payment"
-	DocTypeCustomerPayment  DocumentType = "customer_payment"
-	DocTypeDelivery         DocumentType = "delivery"
+	DocTypePurchaseOrder   DocumentType = "purchase_order"
+	DocTypeSalesOrder      DocumentType = "sales_order"
+	DocTypePurchaseInvoice DocumentType = "purchase_invoice"
+	DocTypeSalesInvoice    DocumentType = "sales_invoice"
+	DocTypeSupplierPayment DocumentType = "supplier_payment"
+	DocTypeCustomerPayment DocumentType = "customer_payment"
+	DocTypeDelivery        DocumentType = "delivery"
+	DocTypeSalesCreditNote DocumentType = "sales_credit_note"
+	DocTypeSalesDebitNote  DocumentType = "sales_debit_note"
+	DocTypeProductRecall   DocumentType = "product_recall"
 )
 
 // NewDocumentNumberGenerator creates a new document number generator
@@ -40,12 +43,38 @@ func NewDocumentNumberGenerator(db *gorm.DB) *DocumentNumberGenerator {
 	}
 }
 
-// GenerateNumber generates document number based on company format settings
+// GenerateNumber generates document number based on company format settings.
+// It counts the existing documents through the generator's own connection, not the caller's
+// transaction, so generate the number before opening the transaction that uses it.
 func (g *DocumentNumberGenerator) GenerateNumber(
 	ctx context.Context,
 	tenantID string,
 	companyID string,
 	docType DocumentType,
+) (string, error) {
+	return g.generateNumber(ctx, g.db, tenantID, companyID, docType)
+}
+
+// GenerateNumberTx generates a document number counting through the caller's transaction,
+// so documents it already created are included. Use it when one transaction creates several
+// documents of the same type.
+func (g *DocumentNumberGenerator) GenerateNumberTx(
+	ctx context.Context,
+	tx *gorm.DB,
+	tenantID string,
+	companyID string,
+	docType DocumentType,
+) (string, error) {
+	return g.generateNumber(ctx, tx, tenantID, companyID, docType)
+}
+
+// generateNumber builds the next number of the document type, reading through db
+func (g *DocumentNumberGenerator) generateNumber(
+	ctx context.Context,
+	db *gorm.DB,
+	tenantID string,
+	companyID string,
+	docType DocumentType,
 ) (string, error) {
 	log.Printf("🔍 DEBUG [DocNumberGen]: Starting - tenantID=%s, companyID=%s, docType=%s", tenantID, companyID, docType)
 	g.mu.Lock()
@@ -54,7 +83,7 @@ func (g *DocumentNumberGenerator) GenerateNumber(
 	// 1. Get company settings
 	log.Printf("🔍 DEBUG [DocNumberGen]: Getting company settings...")
 	var company models.Company
-	if err := g.db.WithContext(ctx).
+	if err := db.WithContext(ctx).
 		Set("tenant_id", tenantID).
 		First(&company, "id = ?", companyID).Error; err != nil {
 		log.Printf("❌ DEBUG [DocNumberGen]: Failed to get company: %v", err)
@@ -82,6 +111,16 @@ func (g *DocumentNumberGenerator) GenerateNumber(
 		// Use SO prefix for deliveries or create separate if needed
 		prefix = "DEL"
 		format = "{PREFIX}/{YEAR}/{MONTH}/{NUMBER}"
+	case DocTypeSalesCreditNote:
+		// Credit and debit notes follow the invoice format with their own prefix
+		prefix = "CN"
+		format = company.InvoiceNumberFormat
+	case DocTypeSalesDebitNote:
+		prefix = "DN"
+		format = company.InvoiceNumberFormat
+	case DocTypeProductRecall:
+		prefix = "RCL"
+		format = "{PREFIX}/{YEAR}/{MONTH}/{NUMBER}"
 	default:
 		log.Printf("❌ DEBUG [DocNumberGen]: Unsupported document type: %s", docType)
 		return "", fmt.Errorf("unsupported document type: %s", docType)
@@ -90,7 +129,7 @@ func (g *DocumentNumberGenerator) GenerateNumber(
 
 	// 3. Get next sequence number
 	log.Printf("🔍 DEBUG [DocNumberGen]: Getting next sequence...")
-	sequence, err := g.getNextSequence(ctx, tenantID, companyID, docType, format)
+	sequence, err := g.getNextSequence(ctx, db, tenantID, companyID, docType, format)
 	if err != nil {
 		log.Printf("❌ DEBUG [DocNumberGen]: Failed to get sequence: %v", err)
 		return "", err
@@ -121,6 +160,7 @@ func (g *DocumentNumberGenerator) buildNumber(prefix, format string, sequence in
 // getNextSequence gets the next sequence number for the document type
 func (g *DocumentNumberGenerator) getNextSequence(
 	ctx context.Context,
+	db *gorm.DB,
 	tenantID string,
 	companyID string,
 	docType DocumentType,
@@ -139,45 +179,64 @@ func (g *DocumentNumberGenerator) getNextSequence(
 	switch docType {
 	case DocTypePurchaseOrder:
 		log.Printf("🔍 DEBUG [getNextSequence]: Building query for PurchaseOrder...")
-		query = g.db.WithContext(ctx).
+		query = db.WithContext(ctx).
 			Set("tenant_id", tenantID).
 			Unscoped(). // Include soft-deleted records to avoid duplicate numbers
 			Model(&models.PurchaseOrder{}).
 			Where("company_id = ?", companyID)
 
 	case DocTypePurchaseInvoice:
-		query = g.db.WithContext(ctx).
+		query = db.WithContext(ctx).
 			Set("tenant_id", tenantID).
 			Unscoped(). // Include soft-deleted records to avoid duplicate numbers
 			Model(&models.PurchaseInvoice{}).
 			Where("company_id = ?", companyID)
 
 	case DocTypeSalesOrder:
-		query = g.db.WithContext(ctx).
+		query = db.WithContext(ctx).
 			Set("tenant_id", tenantID).
 			Unscoped(). // Include soft-deleted records to avoid duplicate numbers
 			Model(&models.SalesOrder{}).
 			Where("company_id = ?", companyID)
 
 	case DocTypeDelivery:
-		query = g.db.WithContext(ctx).
+		query = db.WithContext(ctx).
 			Set("tenant_id", tenantID).
 			Unscoped(). // Include soft-deleted records to avoid duplicate numbers
 			Model(&models.Delivery{}).
 			Where("company_id = ?", companyID)
 
 	case DocTypeSalesInvoice:
-		// TODO: Implement when sales invoice model is ready
-		log.Printf("❌ DEBUG [getNextSequence]: Sales invoice not implemented")
-		return 0, fmt.Errorf("sales invoice not implemented yet")
+		query = db.WithContext(ctx).
+			Set("tenant_id", tenantID).
+			Model(&models.Invoice{}).
+			Where("company_id = ?", companyID)
 
 	case DocTypeCustomerPayment, DocTypeSupplierPayment:
-		query = g.db.WithContext(ctx).
+		query = db.WithContext(ctx).
 			Set("tenant_id", tenantID).
 			Unscoped(). // Include soft-deleted records to avoid duplicate numbers
 			Model(&models.Payment{}).
 			Where("company_id = ?", companyID)
 
+	case DocTypeSalesCreditNote:
+		query = db.WithContext(ctx).
+			Set("tenant_id", tenantID).
+			Model(&models.SalesCreditNote{}).
+			Where("company_id = ?", companyID)
+
+	case DocTypeSalesDebitNote:
+		query = db.WithContext(ctx).
+			Set("tenant_id", tenantID).
+			Model(&models.SalesDebitNote{}).
+			Where("company_id = ?", companyID)
+
+	case DocTypeProductRecall:
+		query = db.WithContext(ctx).
+			Set("tenant_id", tenantID).
+			Model(&models.ProductRecall{}).
+			Where("company_id = ?", companyID)
+
 	default:
 		log.Printf("❌ DEBUG [getNextSequence]: Unsupported document type: %s", docType)
 		return 0, fmt.Errorf("unsupported document type: %s", docType)
@@ -189,12 +248,12 @@ func (g *DocumentNumberGenerator) getNextSequence(
 		year := now.Year()
 		month := int(now.Month())
 		log.Printf("🔍 DEBUG [getNextSequence]: Adding monthly filter - year=%d, month=%d", year, month)
-		query = query.Where("EXTRACT(YEAR FROM created_at) = ? AND EXTRACT(MONTH FROM created_at) = ?",
+		query = query.Where(yearOf(db, "created_at")+" = ? AND "+monthOf(db, "created_at")+" = ?",
 			year, month)
 	} else if shouldResetYearly {
 		year := now.Year()
 		log.Printf("🔍 DEBUG [getNextSequence]: Adding yearly filter - year=%d", year)
-		query = query.Where("EXTRACT(YEAR FROM created_at) = ?", year)
+		query = query.Where(yearOf(db, "created_at")+" = ?", year)
 	}
 	// else: never reset (continuous sequence)
 
@@ -208,6 +267,22 @@ func (g *DocumentNumberGenerator) getNextSequence(
 	return int(count) + 1, nil
 }
 
+// yearOf and monthOf return the SQL for the year and month of a timestamp column.
+// SQLite (used by the tests) has no EXTRACT.
+func yearOf(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "CAST(strftime('%Y', " + column + ") AS INTEGER)"
+	}
+	return "EXTRACT(YEAR FROM " + column + ")"
+}
+
+func monthOf(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "CAST(strftime('%m', " + column + ") AS INTEGER)"
+	}
+	return "EXTRACT(MONTH FROM " + column + ")"
+}
+
 // GeneratePaymentNumber generates payment document number
 func (g *DocumentNumberGenerator) GeneratePaymentNumber(
 	ctx context.Context,
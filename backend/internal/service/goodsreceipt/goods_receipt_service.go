@@ -14,23 +14,35 @@ import (
 	"backend/internal/dto"
 	"backend/internal/service/audit"
 	"backend/internal/service/deliverytolerance"
+	"backend/internal/service/inventory"
+	"backend/internal/service/qualityhold"
+	"backend/internal/service/serial"
+	"backend/internal/service/uom"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 // GoodsReceiptService - Business logic for goods receipt management
 type GoodsReceiptService struct {
-	db               *gorm.DB
-	auditService     *audit.AuditService
-	toleranceService *deliverytolerance.DeliveryToleranceService
+	db                  *gorm.DB
+	auditService        *audit.AuditService
+	toleranceService    *deliverytolerance.DeliveryToleranceService
+	stockPostingService *inventory.StockPostingService
+	uomService          *uom.UOMService
+	serialService       *serial.SerialService
+	qualityHoldService  *qualityhold.QualityHoldService
 }
 
 // NewGoodsReceiptService creates a new goods receipt service instance
-func NewGoodsReceiptService(db *gorm.DB, auditService *audit.AuditService, toleranceService *deliverytolerance.DeliveryToleranceService) *GoodsReceiptService {
+func NewGoodsReceiptService(db *gorm.DB, auditService *audit.AuditService, toleranceService *deliverytolerance.DeliveryToleranceService, stockPostingService *inventory.StockPostingService) *GoodsReceiptService {
 	return &GoodsReceiptService{
-		db:               db,
-		auditService:     auditService,
-		toleranceService: toleranceService,
+		db:                  db,
+		auditService:        auditService,
+		toleranceService:    toleranceService,
+		stockPostingService: stockPostingService,
+		uomService:          uom.NewUOMService(db),
+		serialService:       serial.NewSerialService(db),
+		qualityHoldService:  qualityhold.NewQualityHoldService(db, stockPostingService),
 	}
 }
 
@@ -247,9 +259,15 @@ func (s *GoodsReceiptService) CreateGoodsReceipt(ctx context.Context, tenantID,
 				))
 			}
 
+			// Received in the PO line's unit; tolerance is checked on base-unit quantities
+			unit, err := s.uomService.LineUnit(tx, poItem.ProductUnitID)
+			if err != nil {
+				return err
+			}
+
 			// Validate delivery tolerance (SAP Model)
 			// Check if received quantity is within configured tolerance for this product
-			toleranceResult, toleranceErr := s.validateDeliveryTolerance(ctx, tenantID, companyID, poItem.ProductID, remainingQty, receivedQty)
+			toleranceResult, toleranceErr := s.validateDeliveryTolerance(ctx, tenantID, companyID, poItem.ProductID, unit.ToBase(remainingQty), unit.ToBase(receivedQty))
 			if toleranceErr != nil {
 				log.Printf("Warning: Tolerance validation error for product %s: %v", poItem.ProductID, toleranceErr)
 				// Continue without tolerance validation if there's an error
@@ -306,6 +324,8 @@ func (s *GoodsReceiptService) CreateGoodsReceipt(ctx context.Context, tenantID,
 				OrderedQty:          poItem.Quantity,
 				ReceivedQty:         receivedQty,
 				AcceptedQty:         acceptedQty,
+				BaseReceivedQty:     unit.ToBase(receivedQty),
+				BaseAcceptedQty:     unit.ToBase(acceptedQty),
 				RejectedQty:         rejectedQty,
 				RejectionReason:     itemReq.RejectionReason,
 				QualityNote:         itemReq.QualityNote,
@@ -706,6 +726,10 @@ func (s *GoodsReceiptService) UpdateGoodsReceipt(ctx context.Context, tenantID,
 						item.Notes = itemReq.Notes
 					}
 
+					if err := s.setBaseQuantities(tx, &item); err != nil {
+						return err
+					}
+
 					if err := tx.Save(&item).Error; err != nil {
 						return fmt.Errorf("failed to update item: %w", err)
 					}
@@ -851,6 +875,19 @@ func (s *GoodsReceiptService) InspectGoods(ctx context.Context, tenantID, compan
 	}
 
 	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		// Quality hold follows the product setting unless inspection overrides it per item
+		products := make(map[string]models.Product, len(goodsReceipt.Items))
+		for _, item := range goodsReceipt.Items {
+			products[item.ID] = item.Product
+			hold := item.Product.HoldOnReceipt && item.Product.IsBatchTracked
+			if hold != item.QualityHold {
+				if err := tx.Model(&models.GoodsReceiptItem{}).Where("id = ?", item.ID).
+					Update("quality_hold", hold).Error; err != nil {
+					return err
+				}
+			}
+		}
+
 		// Update items if provided
 		if req != nil && req.Items != nil {
 			for _, itemReq := range req.Items {
@@ -874,6 +911,16 @@ func (s *GoodsReceiptService) InspectGoods(ctx context.Context, tenantID, compan
 					if itemReq.QualityNote != nil {
 						item.QualityNote = itemReq.QualityNote
 					}
+					if itemReq.QualityHold != nil {
+						if *itemReq.QualityHold && !products[item.ID].IsBatchTracked {
+							return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not batch-tracked: only batch-tracked products can be held", products[item.ID].Code))
+						}
+						item.QualityHold = *itemReq.QualityHold
+					}
+
+					if err := s.setBaseQuantities(tx, &item); err != nil {
+						return err
+					}
 
 					if err := tx.Save(&item).Error; err != nil {
 						return err
@@ -928,6 +975,7 @@ func (s *GoodsReceiptService) InspectGoods(ctx context.Context, tenantID, compan
 			RejectedQty     string  `json:"rejected_qty"`
 			RejectionReason *string `json:"rejection_reason,omitempty"`
 			QualityNote     *string `json:"quality_note,omitempty"`
+			QualityHold     bool    `json:"quality_hold"`
 		}
 		newItemStates := make([]newItemState, len(updatedGoodsReceipt.Items))
 		for i, item := range updatedGoodsReceipt.Items {
@@ -940,6 +988,7 @@ func (s *GoodsReceiptService) InspectGoods(ctx context.Context, tenantID, compan
 				RejectedQty:     item.RejectedQty.String(),
 				RejectionReason: item.RejectionReason,
 				QualityNote:     item.QualityNote,
+				QualityHold:     item.QualityHold,
 			}
 		}
 
@@ -964,6 +1013,48 @@ func (s *GoodsReceiptService) InspectGoods(ctx context.Context, tenantID, compan
 	return updatedGoodsReceipt, nil
 }
 
+// GetPutAwaySuggestions proposes a put-away bin for every item of a goods receipt that has
+// stock to put away. Items get no bin when the warehouse has no usable bins.
+func (s *GoodsReceiptService) GetPutAwaySuggestions(ctx context.Context, tenantID, companyID, goodsReceiptID string) ([]dto.PutAwaySuggestionResponse, error) {
+	goodsReceipt, err := s.GetGoodsReceiptByID(ctx, tenantID, companyID, goodsReceiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.db.WithContext(ctx)
+	suggestions := make([]dto.PutAwaySuggestionResponse, 0, len(goodsReceipt.Items))
+	for _, item := range goodsReceipt.Items {
+		qty := item.BaseReceivedQty
+		if goodsReceipt.Status != models.GoodsReceiptStatusPending && goodsReceipt.Status != models.GoodsReceiptStatusReceived {
+			qty = item.BaseAcceptedQty
+		}
+		if !qty.IsPositive() {
+			continue
+		}
+
+		suggestion := dto.PutAwaySuggestionResponse{
+			ItemID:      item.ID,
+			ProductID:   item.ProductID,
+			ProductCode: item.Product.Code,
+			ProductName: item.Product.Name,
+			BatchNumber: item.BatchNumber,
+			Quantity:    qty.String(),
+		}
+
+		bin, err := s.stockPostingService.SuggestPutAwayBin(db, goodsReceipt.WarehouseID, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if bin != nil {
+			suggestion.BinID = &bin.ID
+			suggestion.BinCode = &bin.Code
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
+
 // AcceptGoods accepts goods and updates stock (INSPECTED → ACCEPTED)
 func (s *GoodsReceiptService) AcceptGoods(ctx context.Context, tenantID, companyID, goodsReceiptID, userID string, req *dto.AcceptGoodsRequest, ipAddress, userAgent string) (*models.GoodsReceipt, error) {
 	goodsReceipt, err := s.GetGoodsReceiptByID(ctx, tenantID, companyID, goodsReceiptID)
@@ -982,79 +1073,145 @@ func (s *GoodsReceiptService) AcceptGoods(ctx context.Context, tenantID, company
 	var poCompleted bool
 	var poNumber string
 
+	// Put-away bins and serials entered by the user, keyed by goods receipt item
+	chosenBins := make(map[string]string)
+	serialNumbers := make(map[string][]string)
+	if req != nil {
+		for _, itemReq := range req.Items {
+			if itemReq.BinID != "" {
+				chosenBins[itemReq.ItemID] = itemReq.BinID
+			}
+			serialNumbers[itemReq.ItemID] = itemReq.SerialNumbers
+		}
+	}
+	for itemID := range serialNumbers {
+		found := false
+		for _, item := range goodsReceipt.Items {
+			if item.ID == itemID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("item %s does not belong to this goods receipt", itemID))
+		}
+	}
+
 	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
 		// Update warehouse stock for each accepted item
 		for _, item := range goodsReceipt.Items {
-			if item.AcceptedQty.GreaterThan(decimal.Zero) {
+			if item.BaseAcceptedQty.GreaterThan(decimal.Zero) {
 				// Load product to check tracking flags
 				var product models.Product
 				if err := tx.Where("id = ?", item.ProductID).First(&product).Error; err != nil {
 					return fmt.Errorf("failed to load product: %w", err)
 				}
 
-				var stock models.WarehouseStock
-				err := tx.Where("warehouse_id = ? AND product_id = ?", goodsReceipt.WarehouseID, item.ProductID).
-					First(&stock).Error
-
-				if err == gorm.ErrRecordNotFound {
-					// Create new stock record
-					stock = models.WarehouseStock{
-						ID:          uuid.New().String(),
-						WarehouseID: goodsReceipt.WarehouseID,
-						ProductID:   item.ProductID,
-						Quantity:    item.AcceptedQty,
-					}
-					if err := tx.Create(&stock).Error; err != nil {
-						return fmt.Errorf("failed to create stock: %w", err)
+				// Value the receipt at the PO line's net price per base unit
+				var poItem models.PurchaseOrderItem
+				if err := tx.Preload("ProductUnit").Where("id = ?", item.PurchaseOrderItemID).First(&poItem).Error; err != nil {
+					return fmt.Errorf("failed to load PO item: %w", err)
+				}
+				unitCost := purchaseUnitCost(&poItem)
+
+				// Post stock in (updates WarehouseStock, ProductBatch and writes the movement)
+				posting := &inventory.StockPosting{
+					TenantID:        tenantID,
+					CompanyID:       companyID,
+					WarehouseID:     goodsReceipt.WarehouseID,
+					ProductID:       item.ProductID,
+					MovementType:    models.MovementTypeIn,
+					Quantity:        item.BaseAcceptedQty,
+					UnitCost:        &unitCost,
+					ReferenceType:   inventory.ReferenceTypeGoodsReceipt,
+					ReferenceID:     goodsReceipt.ID,
+					ReferenceNumber: goodsReceipt.GRNNumber,
+					CreatedBy:       userID,
+				}
+				if product.IsBatchTracked && item.BatchNumber != nil && *item.BatchNumber != "" {
+					posting.Batch = &inventory.BatchDetails{
+						BatchNumber:     *item.BatchNumber,
+						ManufactureDate: item.ManufactureDate,
+						ExpiryDate:      item.ExpiryDate,
+						SupplierID:      &goodsReceipt.SupplierID,
+						GoodsReceiptID:  &goodsReceipt.ID,
+						Quarantine:      item.QualityHold,
 					}
-				} else if err != nil {
-					return fmt.Errorf("failed to get stock: %w", err)
+				} else if item.QualityHold {
+					return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is held for quality: a batch number is required", product.Code))
+				}
+
+				// Put the stock away to the chosen bin, or the suggested one when the warehouse uses bins
+				if binID, ok := chosenBins[item.ID]; ok {
+					posting.BinID = &binID
 				} else {
-					// Update existing stock
-					stock.Quantity = stock.Quantity.Add(item.AcceptedQty)
-					if err := tx.Save(&stock).Error; err != nil {
-						return fmt.Errorf("failed to update stock: %w", err)
+					bin, err := s.stockPostingService.SuggestPutAwayBin(tx, goodsReceipt.WarehouseID, item.ProductID)
+					if err != nil {
+						return err
+					}
+					if bin != nil {
+						posting.BinID = &bin.ID
 					}
 				}
 
-				// Create or update ProductBatch record for batch-tracked products
-				if product.IsBatchTracked && item.BatchNumber != nil && *item.BatchNumber != "" {
-					// Check if batch already exists for this product and batch number
-					var existingBatch models.ProductBatch
-					err := tx.Where("product_id = ? AND batch_number = ?", item.ProductID, *item.BatchNumber).
-						First(&existingBatch).Error
-
-					if err == gorm.ErrRecordNotFound {
-						// Create new batch
-						qualityStatus := "GOOD"
-						batch := models.ProductBatch{
-							ID:               uuid.New().String(),
-							BatchNumber:      *item.BatchNumber,
-							ProductID:        item.ProductID,
-							WarehouseStockID: stock.ID,
-							ManufactureDate:  item.ManufactureDate,
-							ExpiryDate:       item.ExpiryDate,
-							Quantity:         item.AcceptedQty,
-							GoodsReceiptID:   &goodsReceipt.ID,
-							ReceiptDate:      time.Now(),
-							Status:           "AVAILABLE",
-							QualityStatus:    &qualityStatus,
-						}
-						if err := tx.Create(&batch).Error; err != nil {
-							return fmt.Errorf("failed to create product batch: %w", err)
-						}
-					} else if err != nil {
-						return fmt.Errorf("failed to check existing batch: %w", err)
-					} else {
-						// Update existing batch quantity
-						existingBatch.Quantity = existingBatch.Quantity.Add(item.AcceptedQty)
-						// Update expiry date if provided and newer
-						if item.ExpiryDate != nil && (existingBatch.ExpiryDate == nil || item.ExpiryDate.After(*existingBatch.ExpiryDate)) {
-							existingBatch.ExpiryDate = item.ExpiryDate
-						}
-						if err := tx.Save(&existingBatch).Error; err != nil {
-							return fmt.Errorf("failed to update product batch quantity: %w", err)
-						}
+				result, err := s.stockPostingService.Post(tx, posting)
+				if err != nil {
+					return err
+				}
+
+				// Held stock waits in quarantine for a quality release or scrap
+				if item.QualityHold && result.Batch != nil && result.Batch.Status == models.BatchStatusQuarantine {
+					reason := ""
+					if item.QualityNote != nil {
+						reason = *item.QualityNote
+					}
+					if _, err := s.qualityHoldService.HoldReceipt(tx, &qualityhold.ReceiptHold{
+						TenantID:        tenantID,
+						CompanyID:       companyID,
+						WarehouseID:     goodsReceipt.WarehouseID,
+						ProductID:       item.ProductID,
+						BatchID:         result.Batch.ID,
+						Source:          models.QualityHoldSourceGoodsReceipt,
+						GoodsReceiptID:  &goodsReceipt.ID,
+						ReferenceNumber: goodsReceipt.GRNNumber,
+						Quantity:        item.BaseAcceptedQty,
+						Reason:          reason,
+						CreatedBy:       userID,
+					}); err != nil {
+						return err
+					}
+				}
+
+				// Register one serial per accepted unit
+				if product.IsSerialTracked {
+					receipt := &serial.SerialReceipt{
+						TenantID:        tenantID,
+						CompanyID:       companyID,
+						ProductID:       item.ProductID,
+						WarehouseID:     goodsReceipt.WarehouseID,
+						SupplierID:      &goodsReceipt.SupplierID,
+						GoodsReceiptID:  goodsReceipt.ID,
+						ReferenceNumber: goodsReceipt.GRNNumber,
+						Quantity:        item.BaseAcceptedQty,
+						SerialNumbers:   serialNumbers[item.ID],
+						ReceivedAt:      time.Now(),
+						CreatedBy:       userID,
+					}
+					if result.Batch != nil {
+						receipt.BatchID = &result.Batch.ID
+					}
+					if err := s.serialService.Receive(tx, receipt); err != nil {
+						return err
+					}
+				} else if len(serialNumbers[item.ID]) > 0 {
+					return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not serial-tracked", product.Code))
+				}
+
+				if posting.BinID != nil {
+					if err := tx.Model(&models.GoodsReceiptItem{}).
+						Where("id = ?", item.ID).
+						Update("put_away_bin_id", *posting.BinID).Error; err != nil {
+						return fmt.Errorf("failed to record put-away bin: %w", err)
 					}
 				}
 
@@ -1126,6 +1283,9 @@ func (s *GoodsReceiptService) AcceptGoods(ctx context.Context, tenantID, company
 	})
 
 	if err != nil {
+		if appErr, ok := err.(*pkgerrors.AppError); ok {
+			return nil, appErr
+		}
 		return nil, fmt.Errorf("failed to accept goods: %w", err)
 	}
 
@@ -1192,6 +1352,7 @@ func (s *GoodsReceiptService) RejectGoods(ctx context.Context, tenantID, company
 		for _, item := range goodsReceipt.Items {
 			item.RejectedQty = item.ReceivedQty
 			item.AcceptedQty = decimal.Zero
+			item.BaseAcceptedQty = decimal.Zero
 			item.RejectionReason = &req.RejectionReason
 			if err := tx.Save(&item).Error; err != nil {
 				return err
@@ -1552,6 +1713,8 @@ func (s *GoodsReceiptService) MapToResponse(ctx context.Context, gr *models.Good
 				OrderedQty:            item.OrderedQty.String(),
 				ReceivedQty:           item.ReceivedQty.String(),
 				AcceptedQty:           item.AcceptedQty.String(),
+				BaseReceivedQty:       item.BaseReceivedQty.String(),
+				BaseAcceptedQty:       item.BaseAcceptedQty.String(),
 				InvoicedQty:           fmt.Sprintf("%.4f", invoicedQty),
 				RejectedQty:           item.RejectedQty.String(),
 				RejectionReason:       item.RejectionReason,
@@ -1562,6 +1725,8 @@ func (s *GoodsReceiptService) MapToResponse(ctx context.Context, gr *models.Good
 				DispositionNotes:         item.DispositionNotes,
 				DispositionResolvedNotes: item.DispositionResolvedNotes,
 				QualityNote:              item.QualityNote,
+				QualityHold:              item.QualityHold,
+				PutAwayBinID:             item.PutAwayBinID,
 				Notes:                 item.Notes,
 				CreatedAt:             item.CreatedAt,
 				UpdatedAt:             item.UpdatedAt,
@@ -1671,3 +1836,26 @@ func (s *GoodsReceiptService) MapToResponse(ctx context.Context, gr *models.Good
 
 	return response
 }
+
+// purchaseUnitCost returns the net (after line discount) price of a PO line per base unit
+func purchaseUnitCost(poItem *models.PurchaseOrderItem) decimal.Decimal {
+	price := poItem.UnitPrice
+	if poItem.Quantity.IsPositive() && poItem.Subtotal.IsPositive() {
+		price = poItem.Subtotal.Div(poItem.Quantity)
+	}
+
+	return uom.FromProductUnit(poItem.ProductUnit).PriceToBase(price)
+}
+
+// setBaseQuantities converts the received and accepted quantities of a goods receipt line
+// to the product base unit. The line keeps the unit of its purchase order line.
+func (s *GoodsReceiptService) setBaseQuantities(tx *gorm.DB, item *models.GoodsReceiptItem) error {
+	unit, err := s.uomService.LineUnit(tx, item.ProductUnitID)
+	if err != nil {
+		return err
+	}
+
+	item.BaseReceivedQty = unit.ToBase(item.ReceivedQty)
+	item.BaseAcceptedQty = unit.ToBase(item.AcceptedQty)
+	return nil
+}
@@ -0,0 +1,187 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// BatchExpiryService - Batch expiry monitoring and near-expiry reporting
+type BatchExpiryService struct {
+	db *gorm.DB
+}
+
+// NewBatchExpiryService creates a new batch expiry service instance
+func NewBatchExpiryService(db *gorm.DB) *BatchExpiryService {
+	return &BatchExpiryService{
+		db: db,
+	}
+}
+
+// ExpireBatches marks every available batch whose expiry date has passed as EXPIRED,
+// which takes it out of FEFO picking and out of available stock. Runs across all companies (scheduled job).
+// A batch that fails is logged and left AVAILABLE, so it is retried on the next run.
+func (s *BatchExpiryService) ExpireBatches(ctx context.Context, now time.Time) (int64, error) {
+	var batchIDs []string
+	if err := s.db.WithContext(ctx).Model(&models.ProductBatch{}).
+		Where("status = ? AND expiry_date IS NOT NULL AND expiry_date < ?", models.BatchStatusAvailable, now).
+		Pluck("id", &batchIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to find expired batches: %w", err)
+	}
+
+	// Each batch goes through the posting service so its stock row's held quantity follows
+	postingService := NewStockPostingService(s.db)
+	var expired int64
+	for _, batchID := range batchIDs {
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			_, err := postingService.SetBatchStatus(tx, batchID, models.BatchStatusExpired, nil)
+			return err
+		}); err != nil {
+			log.Printf("[ERROR][INVENTORY] Failed to expire batch %s: %v", batchID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// GetNearExpiryReport lists batches with stock that are expired or expire within the company's
+// longest horizon, bucketed by horizon (e.g. 1-30, 31-60, 61-90 days). Batches expiring today count as expired.
+func (s *BatchExpiryService) GetNearExpiryReport(ctx context.Context, tenantID, companyID string, query *dto.NearExpiryQuery) (*dto.NearExpiryResponse, error) {
+	var company models.Company
+	if err := s.db.WithContext(ctx).Select("id", "expiry_alert_days").Where("id = ?", companyID).First(&company).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Company")
+		}
+		return nil, fmt.Errorf("failed to load company: %w", err)
+	}
+
+	horizons := company.ExpiryAlertHorizons()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Include the whole last day of the longest horizon
+	until := today.AddDate(0, 0, horizons[len(horizons)-1]+1)
+
+	type batchRow struct {
+		BatchID       string
+		BatchNumber   string
+		ProductID     string
+		ProductCode   string
+		ProductName   string
+		WarehouseID   string
+		WarehouseCode string
+		WarehouseName string
+		ExpiryDate    time.Time
+		Status        string
+		Quantity      decimal.Decimal
+		AverageCost   decimal.Decimal
+	}
+
+	dbQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Table("product_batches").
+		Select(`
+			product_batches.id as batch_id,
+			product_batches.batch_number as batch_number,
+			products.id as product_id,
+			products.code as product_code,
+			products.name as product_name,
+			warehouses.id as warehouse_id,
+			warehouses.code as warehouse_code,
+			warehouses.name as warehouse_name,
+			product_batches.expiry_date as expiry_date,
+			product_batches.status as status,
+			product_batches.quantity as quantity,
+			warehouse_stocks.average_cost as average_cost
+		`).
+		Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Joins("JOIN products ON products.id = product_batches.product_id").
+		Where("products.tenant_id = ? AND warehouses.company_id = ?", tenantID, companyID).
+		Where("product_batches.quantity > 0 AND product_batches.expiry_date IS NOT NULL AND product_batches.expiry_date < ?", until).
+		Where("product_batches.status IN ?", []models.BatchStatus{models.BatchStatusAvailable, models.BatchStatusExpired})
+
+	if query.WarehouseID != nil && *query.WarehouseID != "" {
+		dbQuery = dbQuery.Where("warehouses.id = ?", *query.WarehouseID)
+	}
+	if query.ProductID != nil && *query.ProductID != "" {
+		dbQuery = dbQuery.Where("products.id = ?", *query.ProductID)
+	}
+
+	var rows []batchRow
+	if err := dbQuery.Order("product_batches.expiry_date ASC, products.code ASC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query near-expiry batches: %w", err)
+	}
+
+	// Bucket 0 holds expired batches, bucket i+1 the batches within horizons[i]
+	buckets := make([]dto.NearExpiryBucket, len(horizons)+1)
+	buckets[0] = dto.NearExpiryBucket{Label: "EXPIRED", Batches: []dto.NearExpiryBatch{}}
+	lower := 1
+	for i, horizon := range horizons {
+		buckets[i+1] = dto.NearExpiryBucket{
+			Label:       fmt.Sprintf("%d-%d", lower, horizon),
+			HorizonDays: horizon,
+			Batches:     []dto.NearExpiryBatch{},
+		}
+		lower = horizon + 1
+	}
+
+	quantities := make([]decimal.Decimal, len(buckets))
+	values := make([]decimal.Decimal, len(buckets))
+	grandTotal := decimal.Zero
+	for _, row := range rows {
+		expiry := time.Date(row.ExpiryDate.Year(), row.ExpiryDate.Month(), row.ExpiryDate.Day(), 0, 0, 0, 0, today.Location())
+		days := int(expiry.Sub(today).Hours() / 24)
+
+		index := 0
+		if days > 0 && row.Status != string(models.BatchStatusExpired) {
+			for i, horizon := range horizons {
+				if days <= horizon {
+					index = i + 1
+					break
+				}
+			}
+		}
+
+		value := row.Quantity.Mul(row.AverageCost).Round(2)
+		buckets[index].Batches = append(buckets[index].Batches, dto.NearExpiryBatch{
+			BatchID:       row.BatchID,
+			BatchNumber:   row.BatchNumber,
+			ProductID:     row.ProductID,
+			ProductCode:   row.ProductCode,
+			ProductName:   row.ProductName,
+			WarehouseID:   row.WarehouseID,
+			WarehouseCode: row.WarehouseCode,
+			WarehouseName: row.WarehouseName,
+			ExpiryDate:    row.ExpiryDate.Format("2006-01-02"),
+			DaysToExpiry:  days,
+			Status:        row.Status,
+			Quantity:      row.Quantity.String(),
+			UnitCost:      row.AverageCost.String(),
+			ValueAtRisk:   value.StringFixed(2),
+		})
+		quantities[index] = quantities[index].Add(row.Quantity)
+		values[index] = values[index].Add(value)
+		grandTotal = grandTotal.Add(value)
+	}
+
+	for i := range buckets {
+		buckets[i].TotalQuantity = quantities[i].String()
+		buckets[i].TotalValue = values[i].StringFixed(2)
+	}
+
+	return &dto.NearExpiryResponse{
+		AsOf:       today.Format("2006-01-02"),
+		Horizons:   horizons,
+		TotalValue: grandTotal.StringFixed(2),
+		Buckets:    buckets,
+	}, nil
+}
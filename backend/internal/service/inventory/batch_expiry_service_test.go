@@ -0,0 +1,132 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchExpiryService(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	postingService := NewStockPostingService(db)
+	service := NewBatchExpiryService(db)
+	ctx := context.Background()
+
+	cost := decimal.NewFromInt(1000)
+	receive := func(batchNumber string, qty int64, expiry time.Time) *models.ProductBatch {
+		result, err := postingService.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			UnitCost:     &cost,
+			Batch:        &BatchDetails{BatchNumber: batchNumber, ExpiryDate: &expiry},
+		})
+		require.NoError(t, err)
+		return result.Batch
+	}
+
+	now := time.Now()
+	expired := receive("EXPIRED", 3, now.AddDate(0, 0, -2))
+	receive("SOON", 5, now.AddDate(0, 0, 10))
+	receive("LATER", 2, now.AddDate(0, 0, 45))
+	receive("SAFE", 7, now.AddDate(0, 0, 200))
+
+	t.Run("error - past expiry batch cannot be sold before the job runs", func(t *testing.T) {
+		_, err := postingService.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeOut,
+			Quantity:     decimal.NewFromInt(-1),
+			BatchID:      &expired.ID,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("success - expire batches", func(t *testing.T) {
+		count, err := service.ExpireBatches(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		var batch models.ProductBatch
+		require.NoError(t, db.First(&batch, "id = ?", expired.ID).Error)
+		assert.Equal(t, models.BatchStatusExpired, batch.Status)
+
+		// Expired stock is still on hand but no longer available
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "3", stock.QuarantineQuantity.String())
+		assert.Equal(t, stock.Quantity.Sub(decimal.NewFromInt(3)).String(), stock.AvailableQuantity().String())
+	})
+
+	t.Run("success - adjustment can still write off an expired batch", func(t *testing.T) {
+		_, err := postingService.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeAdjustment,
+			Quantity:     decimal.NewFromInt(-1),
+			BatchID:      &expired.ID,
+		})
+		require.NoError(t, err)
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "2", stock.QuarantineQuantity.String())
+	})
+
+	t.Run("success - near-expiry report buckets", func(t *testing.T) {
+		report, err := service.GetNearExpiryReport(ctx, company.TenantID, company.ID, &dto.NearExpiryQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{30, 60, 90}, report.Horizons)
+		require.Len(t, report.Buckets, 4)
+
+		assert.Equal(t, "EXPIRED", report.Buckets[0].Label)
+		require.Len(t, report.Buckets[0].Batches, 1)
+		assert.Equal(t, "2", report.Buckets[0].TotalQuantity)
+		assert.Equal(t, "2000.00", report.Buckets[0].TotalValue)
+
+		assert.Equal(t, "1-30", report.Buckets[1].Label)
+		require.Len(t, report.Buckets[1].Batches, 1)
+		assert.Equal(t, "SOON", report.Buckets[1].Batches[0].BatchNumber)
+		assert.Equal(t, 10, report.Buckets[1].Batches[0].DaysToExpiry)
+
+		assert.Equal(t, "31-60", report.Buckets[2].Label)
+		require.Len(t, report.Buckets[2].Batches, 1)
+		assert.Equal(t, "LATER", report.Buckets[2].Batches[0].BatchNumber)
+
+		assert.Empty(t, report.Buckets[3].Batches)
+		assert.Equal(t, "9000.00", report.TotalValue)
+	})
+
+	t.Run("success - company horizons", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.Company{}).Where("id = ?", company.ID).
+			Update("expiry_alert_days", "14,7").Error)
+
+		report, err := service.GetNearExpiryReport(ctx, company.TenantID, company.ID, &dto.NearExpiryQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{7, 14}, report.Horizons)
+		require.Len(t, report.Buckets, 3)
+		assert.Equal(t, "8-14", report.Buckets[2].Label)
+		require.Len(t, report.Buckets[2].Batches, 1)
+		assert.Equal(t, "SOON", report.Buckets[2].Batches[0].BatchNumber)
+	})
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// BatchAllocation is the quantity taken from a single batch by a pick
+type BatchAllocation struct {
+	Batch    models.ProductBatch
+	Quantity decimal.Decimal // Base unit, always positive
+}
+
+// PickBatchesFEFO allocates qty from the available batches of a product in a warehouse,
+// earliest expiry first (First Expired, First Out). Batches without an expiry date are
+// picked last, oldest receipt first. Expired batches are never picked.
+func (s *StockPostingService) PickBatchesFEFO(tx *gorm.DB, warehouseID, productID string, qty decimal.Decimal) ([]BatchAllocation, error) {
+	if !qty.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("pick quantity must be greater than zero")
+	}
+
+	var batches []models.ProductBatch
+	err := tx.Model(&models.ProductBatch{}).
+		Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+		Where("warehouse_stocks.warehouse_id = ? AND product_batches.product_id = ?", warehouseID, productID).
+		Where("product_batches.status = ? AND product_batches.quantity > 0", models.BatchStatusAvailable).
+		Where("product_batches.expiry_date IS NULL OR product_batches.expiry_date >= ?", time.Now()).
+		Order("product_batches.expiry_date IS NULL, product_batches.expiry_date ASC, product_batches.receipt_date ASC").
+		Find(&batches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product batches: %w", err)
+	}
+
+	var allocations []BatchAllocation
+	remaining := qty
+	for _, batch := range batches {
+		if remaining.IsZero() {
+			break
+		}
+
+		take := decimal.Min(batch.Quantity, remaining)
+		allocations = append(allocations, BatchAllocation{
+			Batch:    batch,
+			Quantity: take,
+		})
+		remaining = remaining.Sub(take)
+	}
+
+	if remaining.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient batch stock for product %s. Available: %s, Required: %s",
+			productID, qty.Sub(remaining).String(), qty.String()))
+	}
+
+	return allocations, nil
+}
+
+// unpickableStatus returns why a batch cannot be picked for sale or transfer,
+// or an empty status when it can. Batches past their expiry date count as expired
+// even before the expiry job has marked them.
+func unpickableStatus(batch *models.ProductBatch, now time.Time) models.BatchStatus {
+	if batch.Status.IsHeld() {
+		return batch.Status
+	}
+	if batch.ExpiryDate != nil && batch.ExpiryDate.Before(now) {
+		return models.BatchStatusExpired
+	}
+	return ""
+}
@@ -0,0 +1,447 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// BinAllocation is the quantity taken from a single bin by a pick.
+// A nil Bin is stock of the warehouse that is not held in any bin.
+type BinAllocation struct {
+	Bin      *models.WarehouseBin
+	Quantity decimal.Decimal // Base unit, always positive
+}
+
+// BinMove moves stock between bins of one warehouse without changing the warehouse quantity
+type BinMove struct {
+	TenantID     string
+	CompanyID    string
+	WarehouseID  string
+	ProductID    string
+	BatchID      *string
+	FromBinID    *string // nil = unbinned stock
+	ToBinID      *string // nil = take the stock out of its bin
+	Quantity     decimal.Decimal
+	MovementDate time.Time
+
+	ReferenceNumber string
+	Notes           *string
+	CreatedBy       string
+}
+
+// applyToBins keeps bin stock in step with a posting and returns the bin written to the movement.
+// Postings that name a bin change that bin. Outbound postings without a bin take unbinned stock
+// first; when that is not enough, the shortfall is taken from the bins in pick order so bin
+// quantities never exceed what is on hand.
+func (s *StockPostingService) applyToBins(tx *gorm.DB, stock *models.WarehouseStock, batch *models.ProductBatch, posting *StockPosting) (*string, error) {
+	if posting.BinID != nil && *posting.BinID != "" {
+		if err := s.applyToBin(tx, stock, batch, *posting.BinID, posting.Quantity); err != nil {
+			return nil, err
+		}
+		return posting.BinID, nil
+	}
+
+	if posting.Quantity.IsNegative() {
+		if batch != nil {
+			if err := s.drainBins(tx, stock.ID, &batch.ID, batch.Quantity); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.drainBins(tx, stock.ID, nil, stock.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// applyToBin adds qty to the bin stock row of a bin (per batch for batch postings).
+// Stock can only be put away to active bins; stock already in an inactive bin can still leave it.
+func (s *StockPostingService) applyToBin(tx *gorm.DB, stock *models.WarehouseStock, batch *models.ProductBatch, binID string, qty decimal.Decimal) error {
+	bin, err := s.loadBin(tx, stock.WarehouseID, binID)
+	if err != nil {
+		return err
+	}
+	if qty.IsPositive() && !bin.IsActive {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("bin %s is inactive", bin.Code))
+	}
+
+	query := tx.Where("warehouse_bin_id = ? AND warehouse_stock_id = ?", bin.ID, stock.ID)
+	if batch != nil {
+		query = query.Where("batch_id = ?", batch.ID)
+	} else {
+		query = query.Where("batch_id IS NULL")
+	}
+
+	var binStock models.BinStock
+	err = query.First(&binStock).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to get bin stock: %w", err)
+	}
+
+	newQty := binStock.Quantity.Add(qty)
+	if newQty.IsNegative() {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient stock in bin %s for product %s. Available: %s, Required: %s",
+			bin.Code, stock.ProductID, binStock.Quantity.String(), qty.Abs().String()))
+	}
+
+	if err == gorm.ErrRecordNotFound {
+		binStock = models.BinStock{
+			WarehouseBinID:   bin.ID,
+			WarehouseStockID: stock.ID,
+			ProductID:        stock.ProductID,
+			Quantity:         newQty,
+		}
+		if batch != nil {
+			binStock.BatchID = &batch.ID
+		}
+		if err := tx.Create(&binStock).Error; err != nil {
+			return fmt.Errorf("failed to create bin stock: %w", err)
+		}
+		return nil
+	}
+
+	if err := tx.Model(&binStock).Update("quantity", newQty).Error; err != nil {
+		return fmt.Errorf("failed to update bin stock: %w", err)
+	}
+	return nil
+}
+
+// drainBins takes quantity out of the bins of a stock row (or of one batch) until the bins
+// hold no more than onHand
+func (s *StockPostingService) drainBins(tx *gorm.DB, stockID string, batchID *string, onHand decimal.Decimal) error {
+	query := tx.Model(&models.BinStock{}).
+		Joins("JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.warehouse_bin_id").
+		Where("bin_stocks.warehouse_stock_id = ? AND bin_stocks.quantity > 0", stockID)
+	if batchID != nil {
+		query = query.Where("bin_stocks.batch_id = ?", *batchID)
+	}
+
+	var binStocks []models.BinStock
+	if err := query.Order("warehouse_bins.code ASC").Find(&binStocks).Error; err != nil {
+		return fmt.Errorf("failed to load bin stock: %w", err)
+	}
+
+	excess := decimal.Zero
+	for _, binStock := range binStocks {
+		excess = excess.Add(binStock.Quantity)
+	}
+	excess = excess.Sub(onHand)
+
+	for i := range binStocks {
+		if !excess.IsPositive() {
+			break
+		}
+
+		take := decimal.Min(binStocks[i].Quantity, excess)
+		if err := tx.Model(&binStocks[i]).Update("quantity", binStocks[i].Quantity.Sub(take)).Error; err != nil {
+			return fmt.Errorf("failed to update bin stock: %w", err)
+		}
+		excess = excess.Sub(take)
+	}
+
+	return nil
+}
+
+// loadBin loads a bin and checks it belongs to the warehouse
+func (s *StockPostingService) loadBin(tx *gorm.DB, warehouseID, binID string) (*models.WarehouseBin, error) {
+	var bin models.WarehouseBin
+	if err := tx.Where("id = ?", binID).First(&bin).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Bin")
+		}
+		return nil, fmt.Errorf("failed to get bin: %w", err)
+	}
+
+	if bin.WarehouseID != warehouseID {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("bin %s does not belong to this warehouse", bin.Code))
+	}
+
+	return &bin, nil
+}
+
+// unbinnedQuantity returns the stock of a stock row (or of one batch) that is not held in any bin
+func (s *StockPostingService) unbinnedQuantity(tx *gorm.DB, stock *models.WarehouseStock, batch *models.ProductBatch) (decimal.Decimal, error) {
+	onHand := stock.Quantity
+	query := tx.Model(&models.BinStock{}).Where("warehouse_stock_id = ?", stock.ID)
+	if batch != nil {
+		onHand = batch.Quantity
+		query = query.Where("batch_id = ?", batch.ID)
+	}
+
+	var binned decimal.NullDecimal
+	if err := query.Select("SUM(quantity)").Scan(&binned).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum bin stock: %w", err)
+	}
+
+	return decimal.Max(onHand.Sub(binned.Decimal), decimal.Zero), nil
+}
+
+// PickFromBins allocates qty of a product (or of one batch) from the bins of a warehouse in
+// bin code order, which is the walking order of the pick path. Quantity the bins cannot cover
+// is allocated to unbinned stock; the posting itself checks it is on hand.
+func (s *StockPostingService) PickFromBins(tx *gorm.DB, warehouseID, productID string, batchID *string, qty decimal.Decimal) ([]BinAllocation, error) {
+	if !qty.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("pick quantity must be greater than zero")
+	}
+
+	query := tx.Preload("WarehouseBin").
+		Joins("JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.warehouse_bin_id").
+		Where("warehouse_bins.warehouse_id = ? AND bin_stocks.product_id = ? AND bin_stocks.quantity > 0", warehouseID, productID)
+	if batchID != nil {
+		query = query.Where("bin_stocks.batch_id = ?", *batchID)
+	} else {
+		query = query.Where("bin_stocks.batch_id IS NULL")
+	}
+
+	var binStocks []models.BinStock
+	if err := query.Order("warehouse_bins.code ASC").Find(&binStocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load bin stock: %w", err)
+	}
+
+	var allocations []BinAllocation
+	remaining := qty
+	for i := range binStocks {
+		if remaining.IsZero() {
+			break
+		}
+
+		take := decimal.Min(binStocks[i].Quantity, remaining)
+		allocations = append(allocations, BinAllocation{
+			Bin:      &binStocks[i].WarehouseBin,
+			Quantity: take,
+		})
+		remaining = remaining.Sub(take)
+	}
+
+	if remaining.IsPositive() {
+		allocations = append(allocations, BinAllocation{Quantity: remaining})
+	}
+
+	return allocations, nil
+}
+
+// PostFromBins posts an outbound posting split by the bins it is picked from, one movement per bin.
+// Inbound postings and postings that already name a bin are posted as they are.
+func (s *StockPostingService) PostFromBins(tx *gorm.DB, posting *StockPosting) ([]*PostingResult, error) {
+	if !posting.Quantity.IsNegative() || (posting.BinID != nil && *posting.BinID != "") {
+		result, err := s.Post(tx, posting)
+		if err != nil {
+			return nil, err
+		}
+		return []*PostingResult{result}, nil
+	}
+
+	// Lock the stock row before reading bin stock so the picks cannot be taken concurrently
+	if _, err := lockStock(tx, posting.WarehouseID, posting.ProductID); err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	allocations, err := s.PickFromBins(tx, posting.WarehouseID, posting.ProductID, posting.BatchID, posting.Quantity.Abs())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*PostingResult, 0, len(allocations))
+	for _, alloc := range allocations {
+		binPosting := *posting
+		binPosting.Quantity = alloc.Quantity.Neg()
+		binPosting.BinID = nil
+		if alloc.Bin != nil {
+			binID := alloc.Bin.ID
+			binPosting.BinID = &binID
+		}
+
+		result, err := s.Post(tx, &binPosting)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// SuggestPutAwayBin proposes the bin to put received stock of a product away to:
+// the active bin already holding most of the product, else the warehouse's default bin,
+// else the first empty active bin. Returns nil when the warehouse has no usable bins.
+func (s *StockPostingService) SuggestPutAwayBin(tx *gorm.DB, warehouseID, productID string) (*models.WarehouseBin, error) {
+	var bin models.WarehouseBin
+
+	err := tx.Model(&models.WarehouseBin{}).
+		Joins("JOIN bin_stocks ON bin_stocks.warehouse_bin_id = warehouse_bins.id").
+		Where("warehouse_bins.warehouse_id = ? AND warehouse_bins.is_active = ?", warehouseID, true).
+		Where("bin_stocks.product_id = ? AND bin_stocks.quantity > 0", productID).
+		Order("bin_stocks.quantity DESC, warehouse_bins.code ASC").
+		First(&bin).Error
+	if err == nil {
+		return &bin, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to find bin holding product: %w", err)
+	}
+
+	err = tx.Where("warehouse_id = ? AND is_active = ? AND is_default = ?", warehouseID, true, true).
+		Order("code ASC").
+		First(&bin).Error
+	if err == nil {
+		return &bin, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to find default bin: %w", err)
+	}
+
+	err = tx.Where("warehouse_id = ? AND is_active = ?", warehouseID, true).
+		Where("NOT EXISTS (SELECT 1 FROM bin_stocks WHERE bin_stocks.warehouse_bin_id = warehouse_bins.id AND bin_stocks.quantity > 0)").
+		Order("code ASC").
+		First(&bin).Error
+	if err == nil {
+		return &bin, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to find empty bin: %w", err)
+	}
+
+	return nil, nil
+}
+
+// MoveBinStock moves stock from one bin to another inside a warehouse. The warehouse (and batch)
+// quantity does not change, so no cost is consumed; the move is written as a pair of TRANSFER
+// movements, out of the source bin and into the destination bin, to keep the audit trail per bin.
+func (s *StockPostingService) MoveBinStock(tx *gorm.DB, move *BinMove) ([]*models.InventoryMovement, error) {
+	if !move.Quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("move quantity must be greater than zero")
+	}
+	if move.FromBinID == nil && move.ToBinID == nil {
+		return nil, pkgerrors.NewBadRequestError("a bin move needs a source or destination bin")
+	}
+	if move.FromBinID != nil && move.ToBinID != nil && *move.FromBinID == *move.ToBinID {
+		return nil, pkgerrors.NewBadRequestError("source and destination bin must be different")
+	}
+	if err := s.checkStockFreeze(tx, &StockPosting{WarehouseID: move.WarehouseID, ProductID: move.ProductID, ReferenceType: ReferenceTypeBinMove}); err != nil {
+		return nil, err
+	}
+
+	stock, err := lockStock(tx, move.WarehouseID, move.ProductID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Product %s not found in warehouse", move.ProductID))
+		}
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	var batch *models.ProductBatch
+	if move.BatchID != nil && *move.BatchID != "" {
+		batch = &models.ProductBatch{}
+		if err := tx.Where("id = ? AND warehouse_stock_id = ?", *move.BatchID, stock.ID).First(batch).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, pkgerrors.NewNotFoundError("Batch")
+			}
+			return nil, fmt.Errorf("failed to get product batch: %w", err)
+		}
+	}
+
+	if move.FromBinID != nil {
+		if err := s.applyToBin(tx, stock, batch, *move.FromBinID, move.Quantity.Neg()); err != nil {
+			return nil, err
+		}
+	} else {
+		unbinned, err := s.unbinnedQuantity(tx, stock, batch)
+		if err != nil {
+			return nil, err
+		}
+		if unbinned.LessThan(move.Quantity) {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient unbinned stock for product %s. Available: %s, Required: %s",
+				move.ProductID, unbinned.String(), move.Quantity.String()))
+		}
+	}
+
+	if move.ToBinID != nil {
+		if err := s.applyToBin(tx, stock, batch, *move.ToBinID, move.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	movementDate := move.MovementDate
+	if movementDate.IsZero() {
+		movementDate = time.Now()
+	}
+
+	referenceType := ReferenceTypeBinMove
+	newMovement := func(binID *string, qty, before decimal.Decimal) *models.InventoryMovement {
+		movement := &models.InventoryMovement{
+			TenantID:      move.TenantID,
+			CompanyID:     move.CompanyID,
+			MovementDate:  movementDate,
+			WarehouseID:   move.WarehouseID,
+			ProductID:     move.ProductID,
+			BatchID:       move.BatchID,
+			BinID:         binID,
+			MovementType:  models.MovementTypeTransfer,
+			Quantity:      qty,
+			StockBefore:   before,
+			StockAfter:    before.Add(qty),
+			UnitCost:      stock.AverageCost,
+			TotalCost:     qty.Mul(stock.AverageCost).Round(2),
+			ReferenceType: &referenceType,
+			Notes:         move.Notes,
+		}
+		if move.ReferenceNumber != "" {
+			movement.ReferenceNumber = &move.ReferenceNumber
+		}
+		if move.CreatedBy != "" {
+			movement.CreatedBy = &move.CreatedBy
+		}
+		return movement
+	}
+
+	movements := []*models.InventoryMovement{
+		newMovement(move.FromBinID, move.Quantity.Neg(), stock.Quantity),
+		newMovement(move.ToBinID, move.Quantity, stock.Quantity.Sub(move.Quantity)),
+	}
+	if err := tx.Create(&movements).Error; err != nil {
+		return nil, fmt.Errorf("failed to create inventory movement: %w", err)
+	}
+
+	return movements, nil
+}
+
+// PickedBins returns the bins the outbound postings of a document took a product (or one batch)
+// from, so a cancellation can put the stock back where it was picked. Bins deactivated since
+// are left out; stock for them goes back unbinned.
+func (s *StockPostingService) PickedBins(tx *gorm.DB, referenceType, referenceID, warehouseID, productID string, batchID *string) ([]BinAllocation, error) {
+	query := tx.Model(&models.InventoryMovement{}).
+		Select("inventory_movements.bin_id AS bin_id, SUM(inventory_movements.quantity) AS quantity").
+		Joins("JOIN warehouse_bins ON warehouse_bins.id = inventory_movements.bin_id").
+		Where("inventory_movements.reference_type = ? AND inventory_movements.reference_id = ?", referenceType, referenceID).
+		Where("inventory_movements.warehouse_id = ? AND inventory_movements.product_id = ?", warehouseID, productID).
+		Where("inventory_movements.quantity < 0 AND warehouse_bins.is_active = ?", true)
+	if batchID != nil {
+		query = query.Where("inventory_movements.batch_id = ?", *batchID)
+	}
+
+	var rows []struct {
+		BinID    string
+		Quantity decimal.Decimal
+	}
+	if err := query.Group("inventory_movements.bin_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load picked bins: %w", err)
+	}
+
+	allocations := make([]BinAllocation, 0, len(rows))
+	for _, row := range rows {
+		var bin models.WarehouseBin
+		if err := tx.Where("id = ?", row.BinID).First(&bin).Error; err != nil {
+			return nil, fmt.Errorf("failed to get bin: %w", err)
+		}
+		allocations = append(allocations, BinAllocation{
+			Bin:      &bin,
+			Quantity: row.Quantity.Abs(),
+		})
+	}
+
+	return allocations, nil
+}
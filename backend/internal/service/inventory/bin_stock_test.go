@@ -0,0 +1,172 @@
+package inventory
+
+import (
+	"testing"
+
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestStockPostingService_Bins(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	createBin := func(code string, isDefault bool) *models.WarehouseBin {
+		bin := &models.WarehouseBin{
+			TenantID:    company.TenantID,
+			WarehouseID: warehouse.ID,
+			Code:        code,
+			IsDefault:   isDefault,
+			IsActive:    true,
+		}
+		require.NoError(t, db.Create(bin).Error)
+		return bin
+	}
+	posting := func(qty string, binID *string) *StockPosting {
+		return &StockPosting{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			WarehouseID:   warehouse.ID,
+			ProductID:     product.ID,
+			MovementType:  models.MovementTypeAdjustment,
+			Quantity:      decimal.RequireFromString(qty),
+			BinID:         binID,
+			ReferenceType: ReferenceTypeInventoryAdjustment,
+			ReferenceID:   "adj-1",
+		}
+	}
+	binQty := func(bin *models.WarehouseBin) string {
+		var binStock models.BinStock
+		err := db.Where("warehouse_bin_id = ? AND product_id = ?", bin.ID, product.ID).First(&binStock).Error
+		if err == gorm.ErrRecordNotFound {
+			return "0"
+		}
+		require.NoError(t, err)
+		return binStock.Quantity.String()
+	}
+
+	binA := createBin("A-01", false)
+	binB := createBin("B-01", true)
+
+	t.Run("suggest - default bin when no bin holds the product", func(t *testing.T) {
+		bin, err := service.SuggestPutAwayBin(db, warehouse.ID, product.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, bin)
+		assert.Equal(t, binB.ID, bin.ID)
+	})
+
+	t.Run("success - inbound puts stock away to the bin", func(t *testing.T) {
+		_, err := service.Post(db, posting("5", &binA.ID))
+		require.NoError(t, err)
+		_, err = service.Post(db, posting("5", &binB.ID))
+		require.NoError(t, err)
+		result, err := service.Post(db, posting("5", nil))
+		require.NoError(t, err)
+
+		assert.Nil(t, result.Movement.BinID)
+		assert.Equal(t, "15", result.Stock.Quantity.String())
+		assert.Equal(t, "5", binQty(binA))
+		assert.Equal(t, "5", binQty(binB))
+	})
+
+	t.Run("suggest - bin already holding the product", func(t *testing.T) {
+		_, err := service.Post(db, posting("1", &binA.ID))
+		require.NoError(t, err)
+
+		bin, err := service.SuggestPutAwayBin(db, warehouse.ID, product.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, binA.ID, bin.ID)
+		_, err = service.Post(db, posting("-1", &binA.ID))
+		require.NoError(t, err)
+	})
+
+	t.Run("success - move between bins keeps warehouse quantity", func(t *testing.T) {
+		movements, err := service.MoveBinStock(db, &BinMove{
+			TenantID:    company.TenantID,
+			CompanyID:   company.ID,
+			WarehouseID: warehouse.ID,
+			ProductID:   product.ID,
+			FromBinID:   &binB.ID,
+			ToBinID:     &binA.ID,
+			Quantity:    decimal.NewFromInt(2),
+		})
+
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		assert.Equal(t, "-2", movements[0].Quantity.String())
+		assert.Equal(t, binB.ID, *movements[0].BinID)
+		assert.Equal(t, "2", movements[1].Quantity.String())
+		assert.Equal(t, binA.ID, *movements[1].BinID)
+		assert.Equal(t, "7", binQty(binA))
+		assert.Equal(t, "3", binQty(binB))
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "15", stock.Quantity.String())
+	})
+
+	t.Run("error - move more than the bin holds", func(t *testing.T) {
+		_, err := service.MoveBinStock(db, &BinMove{
+			TenantID:    company.TenantID,
+			CompanyID:   company.ID,
+			WarehouseID: warehouse.ID,
+			ProductID:   product.ID,
+			FromBinID:   &binB.ID,
+			ToBinID:     &binA.ID,
+			Quantity:    decimal.NewFromInt(4),
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Insufficient stock in bin B-01")
+	})
+
+	t.Run("error - put away to an inactive bin", func(t *testing.T) {
+		inactive := createBin("C-01", false)
+		require.NoError(t, db.Model(inactive).Update("is_active", false).Error)
+
+		// Callers post inside their own transaction, so the failed posting rolls back
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := service.Post(tx, posting("1", &inactive.ID))
+			return err
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bin C-01 is inactive")
+	})
+
+	t.Run("success - pick from bins in bin order, then unbinned", func(t *testing.T) {
+		results, err := service.PostFromBins(db, posting("-12", nil))
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, binA.ID, *results[0].Movement.BinID)
+		assert.Equal(t, "-7", results[0].Movement.Quantity.String())
+		assert.Equal(t, binB.ID, *results[1].Movement.BinID)
+		assert.Equal(t, "-3", results[1].Movement.Quantity.String())
+		assert.Nil(t, results[2].Movement.BinID)
+		assert.Equal(t, "-2", results[2].Movement.Quantity.String())
+		assert.Equal(t, "3", results[2].Stock.Quantity.String())
+		assert.Equal(t, "0", binQty(binA))
+		assert.Equal(t, "0", binQty(binB))
+	})
+
+	t.Run("success - unbinned outbound drains bins it cannot cover", func(t *testing.T) {
+		_, err := service.Post(db, posting("2", &binB.ID))
+		require.NoError(t, err)
+
+		// 5 on hand: 2 in B-01, 3 unbinned
+		_, err = service.Post(db, posting("-4", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, "1", binQty(binB))
+	})
+}
@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+)
+
+// costingMethod returns the valuation method configured for a company (AVERAGE when unset)
+func (s *StockPostingService) costingMethod(tx *gorm.DB, companyID string) (models.CostingMethod, error) {
+	var company models.Company
+	if err := tx.Select("id", "costing_method").Where("id = ?", companyID).First(&company).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return models.CostingMethodAverage, nil
+		}
+		return "", fmt.Errorf("failed to get company costing method: %w", err)
+	}
+
+	if company.CostingMethod == models.CostingMethodFIFO {
+		return models.CostingMethodFIFO, nil
+	}
+	return models.CostingMethodAverage, nil
+}
+
+// consumeCostLayers takes qty from the oldest FIFO layers of a stock row and returns the
+// total cost consumed. Quantity not covered by layers (stock received before FIFO was
+// enabled) is costed at the current average cost.
+func (s *StockPostingService) consumeCostLayers(tx *gorm.DB, stock *models.WarehouseStock, qty decimal.Decimal) (decimal.Decimal, error) {
+	var layers []models.CostLayer
+	if err := tx.Where("warehouse_id = ? AND product_id = ? AND remaining_quantity > 0", stock.WarehouseID, stock.ProductID).
+		Order("layer_date ASC, created_at ASC").
+		Find(&layers).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to load cost layers: %w", err)
+	}
+
+	totalCost := decimal.Zero
+	remaining := qty
+	for i := range layers {
+		if remaining.IsZero() {
+			break
+		}
+
+		layer := &layers[i]
+		take := decimal.Min(layer.RemainingQuantity, remaining)
+		if err := tx.Model(layer).Update("remaining_quantity", layer.RemainingQuantity.Sub(take)).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to consume cost layer: %w", err)
+		}
+
+		totalCost = totalCost.Add(take.Mul(layer.UnitCost))
+		remaining = remaining.Sub(take)
+	}
+
+	if remaining.IsPositive() {
+		totalCost = totalCost.Add(remaining.Mul(stock.AverageCost))
+	}
+
+	return totalCost, nil
+}
+
+// createCostLayer opens a FIFO layer for an inbound movement
+func (s *StockPostingService) createCostLayer(tx *gorm.DB, movement *models.InventoryMovement) error {
+	layer := &models.CostLayer{
+		TenantID:          movement.TenantID,
+		CompanyID:         movement.CompanyID,
+		WarehouseID:       movement.WarehouseID,
+		ProductID:         movement.ProductID,
+		MovementID:        movement.ID,
+		LayerDate:         movement.MovementDate,
+		OriginalQuantity:  movement.Quantity,
+		RemainingQuantity: movement.Quantity,
+		UnitCost:          movement.UnitCost,
+		ReferenceType:     movement.ReferenceType,
+		ReferenceNumber:   movement.ReferenceNumber,
+	}
+
+	if err := tx.Create(layer).Error; err != nil {
+		return fmt.Errorf("failed to create cost layer: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+)
+
+// AdjustInTransit changes the quantity shown as in transit to a warehouse.
+// Shipped stock transfers add to the destination and receipt or cancellation takes it off again,
+// so stock that has left the source stays visible until it arrives. The warehouse stock row is
+// created when the product has never been stocked at the destination.
+func (s *StockPostingService) AdjustInTransit(tx *gorm.DB, warehouseID, productID string, delta decimal.Decimal) error {
+	stock, err := lockStock(tx, warehouseID, productID)
+	if err == gorm.ErrRecordNotFound {
+		if !delta.IsPositive() {
+			return nil
+		}
+
+		stock = &models.WarehouseStock{
+			WarehouseID: warehouseID,
+			ProductID:   productID,
+			Quantity:    decimal.Zero,
+		}
+		if err := tx.Create(stock).Error; err != nil {
+			return fmt.Errorf("failed to create warehouse stock: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	inTransit := decimal.Max(stock.InTransitQuantity.Add(delta), decimal.Zero)
+	return updateStock(tx, stock, map[string]interface{}{
+		"in_transit_quantity": inTransit,
+	})
+}
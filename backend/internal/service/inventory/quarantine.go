@@ -0,0 +1,164 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// QuarantineRelease describes handing a quarantined batch back to available stock
+type QuarantineRelease struct {
+	TenantID        string
+	CompanyID       string
+	BatchID         string
+	ReferenceID     string
+	ReferenceNumber string
+	Notes           *string
+	CreatedBy       string
+	MovementDate    time.Time // Defaults to now when zero
+}
+
+// adjustQuarantine moves qty in or out of the stock row's quarantine quantity
+func adjustQuarantine(tx *gorm.DB, stock *models.WarehouseStock, delta decimal.Decimal) error {
+	if delta.IsZero() {
+		return nil
+	}
+
+	quarantine := stock.QuarantineQuantity.Add(delta)
+	if err := updateStock(tx, stock, map[string]interface{}{
+		"quarantine_quantity": quarantine,
+	}); err != nil {
+		return err
+	}
+	stock.QuarantineQuantity = quarantine
+	return nil
+}
+
+// SetBatchStatus changes the status (and optionally the quality status) of a batch.
+// Stock moving into or out of a held status (quarantine, recalled, expired, damaged) is added
+// to or taken off the warehouse stock's quarantine quantity, so it stops or starts counting as available.
+func (s *StockPostingService) SetBatchStatus(tx *gorm.DB, batchID string, status models.BatchStatus, qualityStatus *string) (*models.ProductBatch, error) {
+	var batch models.ProductBatch
+	if err := tx.Preload("WarehouseStock").Where("id = ?", batchID).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Batch")
+		}
+		return nil, fmt.Errorf("failed to get product batch: %w", err)
+	}
+
+	stock, err := lockStock(tx, batch.WarehouseStock.WarehouseID, batch.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	// Re-read the batch under the stock lock
+	if err := tx.Where("id = ?", batchID).First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("failed to get product batch: %w", err)
+	}
+
+	delta := decimal.Zero
+	if batch.Status.IsHeld() && !status.IsHeld() {
+		delta = batch.Quantity.Neg()
+	} else if !batch.Status.IsHeld() && status.IsHeld() {
+		delta = batch.Quantity
+	}
+	if err := adjustQuarantine(tx, stock, delta); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if qualityStatus != nil {
+		updates["quality_status"] = *qualityStatus
+		batch.QualityStatus = qualityStatus
+	}
+	if err := tx.Model(&models.ProductBatch{}).Where("id = ?", batch.ID).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update batch status: %w", err)
+	}
+	batch.Status = status
+	batch.WarehouseStock = *stock
+
+	return &batch, nil
+}
+
+// ReleaseQuarantine returns a QUARANTINE batch to AVAILABLE. On-hand quantity does not change;
+// the release is written as a pair of TRANSFER movements, out of quarantine and into available
+// stock, so the stock card shows when held stock became sellable again.
+func (s *StockPostingService) ReleaseQuarantine(tx *gorm.DB, release *QuarantineRelease) ([]*models.InventoryMovement, error) {
+	var batch models.ProductBatch
+	if err := tx.Preload("WarehouseStock").Where("id = ?", release.BatchID).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Batch")
+		}
+		return nil, fmt.Errorf("failed to get product batch: %w", err)
+	}
+	if err := s.checkStockFreeze(tx, &StockPosting{WarehouseID: batch.WarehouseStock.WarehouseID, ProductID: batch.ProductID, ReferenceType: ReferenceTypeQualityHold}); err != nil {
+		return nil, err
+	}
+	if batch.Status != models.BatchStatusQuarantine {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("batch %s is not in quarantine: %s", batch.BatchNumber, batch.Status))
+	}
+
+	status := models.BatchStatusAvailable
+	if batch.Quantity.IsZero() {
+		status = models.BatchStatusSold
+	}
+	qualityStatus := models.BatchQualityGood
+	released, err := s.SetBatchStatus(tx, batch.ID, status, &qualityStatus)
+	if err != nil {
+		return nil, err
+	}
+	if released.Quantity.IsZero() {
+		return nil, nil
+	}
+
+	movementDate := release.MovementDate
+	if movementDate.IsZero() {
+		movementDate = time.Now()
+	}
+
+	stock := released.WarehouseStock
+	referenceType := ReferenceTypeQualityHold
+	newMovement := func(qty, before decimal.Decimal) *models.InventoryMovement {
+		movement := &models.InventoryMovement{
+			TenantID:      release.TenantID,
+			CompanyID:     release.CompanyID,
+			MovementDate:  movementDate,
+			WarehouseID:   stock.WarehouseID,
+			ProductID:     stock.ProductID,
+			BatchID:       &released.ID,
+			MovementType:  models.MovementTypeTransfer,
+			Quantity:      qty,
+			StockBefore:   before,
+			StockAfter:    before.Add(qty),
+			UnitCost:      stock.AverageCost,
+			TotalCost:     qty.Mul(stock.AverageCost).Round(2),
+			ReferenceType: &referenceType,
+			Notes:         release.Notes,
+		}
+		if release.ReferenceID != "" {
+			movement.ReferenceID = &release.ReferenceID
+		}
+		if release.ReferenceNumber != "" {
+			movement.ReferenceNumber = &release.ReferenceNumber
+		}
+		if release.CreatedBy != "" {
+			movement.CreatedBy = &release.CreatedBy
+		}
+		return movement
+	}
+
+	movements := []*models.InventoryMovement{
+		newMovement(released.Quantity.Neg(), stock.Quantity),
+		newMovement(released.Quantity, stock.Quantity.Sub(released.Quantity)),
+	}
+	if err := tx.Create(&movements).Error; err != nil {
+		return nil, fmt.Errorf("failed to create inventory movement: %w", err)
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,155 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// Revaluation adds cost to stock that was received by an earlier inbound document,
+// e.g. landed cost charged on a purchase invoice after the goods receipt was posted.
+type Revaluation struct {
+	TenantID    string
+	CompanyID   string
+	WarehouseID string
+	ProductID   string
+	Quantity    decimal.Decimal // Quantity the amount was incurred for (base unit)
+	Amount      decimal.Decimal // Extra cost for the whole quantity
+
+	// Inbound document that brought the stock in (used to find FIFO layers)
+	SourceReferenceType string
+	SourceReferenceID   string
+
+	// Document causing the revaluation, written to the movement
+	ReferenceType   string
+	ReferenceID     string
+	ReferenceNumber string
+	MovementDate    time.Time
+	Notes           *string
+	CreatedBy       string
+}
+
+// RevaluationResult splits the amount into the part added to stock still on hand
+// and the part belonging to quantity that had already been consumed.
+type RevaluationResult struct {
+	Movement    *models.InventoryMovement // nil when nothing was capitalized
+	Capitalized decimal.Decimal
+	Expensed    decimal.Decimal
+}
+
+// Revalue raises the cost of on-hand stock without changing its quantity.
+// Under AVERAGE the on-hand share of the amount is folded into the average cost;
+// under FIFO the unit cost of the source document's open layers is raised as well.
+// The capitalized amount is written as a zero-quantity movement so valuation
+// reports built from movements stay in step.
+func (s *StockPostingService) Revalue(tx *gorm.DB, revaluation *Revaluation) (*RevaluationResult, error) {
+	if !revaluation.Quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("revaluation quantity must be greater than zero")
+	}
+	if revaluation.Amount.IsNegative() {
+		return nil, pkgerrors.NewBadRequestError("revaluation amount cannot be negative")
+	}
+
+	result := &RevaluationResult{
+		Capitalized: decimal.Zero,
+		Expensed:    revaluation.Amount,
+	}
+	if revaluation.Amount.IsZero() {
+		return result, nil
+	}
+
+	stock, err := lockStock(tx, revaluation.WarehouseID, revaluation.ProductID)
+	if err == gorm.ErrRecordNotFound {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+	if !stock.Quantity.IsPositive() {
+		return result, nil
+	}
+
+	costingMethod, err := s.costingMethod(tx, revaluation.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	perUnit := revaluation.Amount.Div(revaluation.Quantity)
+	capitalized := decimal.Zero
+	if costingMethod == models.CostingMethodFIFO {
+		var layers []models.CostLayer
+		if err := tx.Where("warehouse_id = ? AND product_id = ? AND remaining_quantity > 0", revaluation.WarehouseID, revaluation.ProductID).
+			Where("movement_id IN (?)", tx.Model(&models.InventoryMovement{}).Select("id").
+				Where("reference_type = ? AND reference_id = ?", revaluation.SourceReferenceType, revaluation.SourceReferenceID)).
+			Find(&layers).Error; err != nil {
+			return nil, fmt.Errorf("failed to load cost layers: %w", err)
+		}
+
+		for i := range layers {
+			layer := &layers[i]
+			if err := tx.Model(layer).Update("unit_cost", layer.UnitCost.Add(perUnit).Round(4)).Error; err != nil {
+				return nil, fmt.Errorf("failed to update cost layer: %w", err)
+			}
+			capitalized = capitalized.Add(layer.RemainingQuantity.Mul(perUnit))
+		}
+	} else {
+		onHand := decimal.Min(stock.Quantity, revaluation.Quantity)
+		capitalized = onHand.Mul(perUnit)
+	}
+
+	capitalized = decimal.Min(capitalized.Round(4), revaluation.Amount)
+	result.Capitalized = capitalized
+	result.Expensed = revaluation.Amount.Sub(capitalized)
+	if !capitalized.IsPositive() {
+		return result, nil
+	}
+
+	averageCost := stock.Quantity.Mul(stock.AverageCost).Add(capitalized).Div(stock.Quantity).Round(4)
+	if err := updateStock(tx, stock, map[string]interface{}{"average_cost": averageCost}); err != nil {
+		return nil, err
+	}
+
+	movementDate := revaluation.MovementDate
+	if movementDate.IsZero() {
+		movementDate = time.Now()
+	}
+
+	movement := &models.InventoryMovement{
+		TenantID:     revaluation.TenantID,
+		CompanyID:    revaluation.CompanyID,
+		MovementDate: movementDate,
+		WarehouseID:  revaluation.WarehouseID,
+		ProductID:    revaluation.ProductID,
+		MovementType: models.MovementTypeAdjustment,
+		Quantity:     decimal.Zero,
+		StockBefore:  stock.Quantity,
+		StockAfter:   stock.Quantity,
+		UnitCost:     decimal.Zero,
+		TotalCost:    capitalized.Round(2),
+		Notes:        revaluation.Notes,
+	}
+	if revaluation.ReferenceType != "" {
+		movement.ReferenceType = &revaluation.ReferenceType
+	}
+	if revaluation.ReferenceID != "" {
+		movement.ReferenceID = &revaluation.ReferenceID
+	}
+	if revaluation.ReferenceNumber != "" {
+		movement.ReferenceNumber = &revaluation.ReferenceNumber
+	}
+	if revaluation.CreatedBy != "" {
+		movement.CreatedBy = &revaluation.CreatedBy
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, fmt.Errorf("failed to create inventory movement: %w", err)
+	}
+	result.Movement = movement
+
+	return result, nil
+}
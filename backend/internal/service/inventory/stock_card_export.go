@@ -0,0 +1,225 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"backend/internal/dto"
+)
+
+// GenerateStockCardCSV writes the stock card as CSV: opening balance, one row per movement, closing balance
+func (s *StockCardService) GenerateStockCardCSV(card *dto.StockCardResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"Date", "Movement Type", "Reference Type", "Reference Number", "Batch", "Bin", "Qty In", "Qty Out", "Balance"}
+	if card.Batches != nil {
+		header = append(header, "Batch Balance")
+	}
+	header = append(header, "Unit Cost", "Posted By", "Notes")
+
+	records := [][]string{
+		{"Warehouse", card.WarehouseCode, card.WarehouseName},
+		{"Product", card.ProductCode, card.ProductName, card.BaseUnit},
+		{"Period", card.DateFrom, card.DateTo},
+		{},
+		header,
+		{card.DateFrom, "OPENING", "", "", "", "", "", "", card.OpeningBalance},
+	}
+
+	for _, entry := range card.Data {
+		record := []string{
+			formatStockCardDate(entry.MovementDate),
+			entry.MovementType,
+			stringValue(entry.ReferenceType),
+			stringValue(entry.ReferenceNumber),
+			entry.BatchNumber,
+			entry.BinCode,
+			entry.QuantityIn,
+			entry.QuantityOut,
+			entry.Balance,
+		}
+		if card.Batches != nil {
+			record = append(record, entry.BatchBalance)
+		}
+		record = append(record, entry.UnitCost, entry.PostedByName, stringValue(entry.Notes))
+		records = append(records, record)
+	}
+
+	records = append(records, []string{card.DateTo, "CLOSING", "", "", "", "", card.TotalIn, card.TotalOut, card.ClosingBalance})
+
+	if card.Batches != nil {
+		records = append(records, []string{}, []string{"Batch", "Expiry Date", "Opening", "Qty In", "Qty Out", "Closing"})
+		for _, batch := range card.Batches {
+			records = append(records, []string{batch.BatchNumber, batch.ExpiryDate, batch.OpeningBalance, batch.TotalIn, batch.TotalOut, batch.ClosingBalance})
+		}
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write stock card CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GenerateStockCardPDF generates a PDF stock card (kartu stok)
+func (s *StockCardService) GenerateStockCardPDF(card *dto.StockCardResponse) ([]byte, error) {
+	// Landscape leaves room for the reference and running balance columns
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddPage()
+
+	// Set margins
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+
+	// ============================================================================
+	// HEADER - KARTU STOK TITLE
+	// ============================================================================
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "KARTU STOK", "", 1, "C", false, 0, "")
+	pdf.Ln(5)
+
+	// ============================================================================
+	// PRODUCT AND WAREHOUSE INFO SECTION
+	// ============================================================================
+	info := [][2]string{
+		{"Gudang:", fmt.Sprintf("%s - %s", card.WarehouseCode, card.WarehouseName)},
+		{"Produk:", fmt.Sprintf("%s - %s", card.ProductCode, card.ProductName)},
+		{"Satuan:", card.BaseUnit},
+		{"Periode:", fmt.Sprintf("%s s/d %s", card.DateFrom, card.DateTo)},
+	}
+	for _, line := range info {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(30, 6, line[0])
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(0, 6, line[1])
+		pdf.Ln(6)
+	}
+
+	pdf.Ln(5)
+
+	// ============================================================================
+	// MOVEMENTS TABLE
+	// ============================================================================
+	byBatch := card.Batches != nil
+
+	// Table Header
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(240, 240, 240)
+	pdf.CellFormat(22, 8, "Tanggal", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(28, 8, "Jenis", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(40, 8, "No. Referensi", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(30, 8, "Batch", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(22, 8, "Masuk", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(22, 8, "Keluar", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(24, 8, "Saldo", "1", 0, "C", true, 0, "")
+	if byBatch {
+		pdf.CellFormat(24, 8, "Saldo Batch", "1", 0, "C", true, 0, "")
+	} else {
+		pdf.CellFormat(24, 8, "", "1", 0, "C", true, 0, "")
+	}
+	pdf.CellFormat(55, 8, "Diposting Oleh", "1", 1, "C", true, 0, "")
+
+	// Opening Balance
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(164, 7, "Saldo Awal", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, card.OpeningBalance, "1", 0, "R", false, 0, "")
+	pdf.CellFormat(79, 7, "", "1", 1, "L", false, 0, "")
+
+	// Table Body
+	pdf.SetFont("Arial", "", 8)
+	for _, entry := range card.Data {
+		reference := stringValue(entry.ReferenceNumber)
+		if reference == "" {
+			reference = stringValue(entry.ReferenceType)
+		}
+
+		pdf.CellFormat(22, 7, formatStockCardDate(entry.MovementDate), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(28, 7, entry.MovementType, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 7, dashIfEmpty(reference), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 7, dashIfEmpty(entry.BatchNumber), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(22, 7, entry.QuantityIn, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(22, 7, entry.QuantityOut, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(24, 7, entry.Balance, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(24, 7, entry.BatchBalance, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(55, 7, dashIfEmpty(entry.PostedByName), "1", 1, "L", false, 0, "")
+	}
+
+	// Closing Balance
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(120, 7, "Saldo Akhir", "1", 0, "L", false, 0, "")
+	pdf.CellFormat(22, 7, card.TotalIn, "1", 0, "R", false, 0, "")
+	pdf.CellFormat(22, 7, card.TotalOut, "1", 0, "R", false, 0, "")
+	pdf.CellFormat(24, 7, card.ClosingBalance, "1", 0, "R", false, 0, "")
+	pdf.CellFormat(79, 7, "", "1", 1, "L", false, 0, "")
+
+	// ============================================================================
+	// BATCH SUMMARY TABLE
+	// ============================================================================
+	if byBatch && len(card.Batches) > 0 {
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(0, 7, "RINGKASAN PER BATCH:")
+		pdf.Ln(9)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(50, 8, "Batch", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Kedaluwarsa", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Saldo Awal", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Masuk", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Keluar", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Saldo Akhir", "1", 1, "C", true, 0, "")
+
+		pdf.SetFont("Arial", "", 8)
+		for _, batch := range card.Batches {
+			pdf.CellFormat(50, 7, dashIfEmpty(batch.BatchNumber), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(30, 7, dashIfEmpty(batch.ExpiryDate), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(30, 7, batch.OpeningBalance, "1", 0, "R", false, 0, "")
+			pdf.CellFormat(30, 7, batch.TotalIn, "1", 0, "R", false, 0, "")
+			pdf.CellFormat(30, 7, batch.TotalOut, "1", 0, "R", false, 0, "")
+			pdf.CellFormat(30, 7, batch.ClosingBalance, "1", 1, "R", false, 0, "")
+		}
+	}
+
+	// ============================================================================
+	// FOOTER - PRINT DATE
+	// ============================================================================
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.Cell(0, 5, "Dicetak: "+time.Now().Format("02 January 2006 15:04"))
+
+	// Output PDF to buffer
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate stock card PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formatStockCardDate shortens an RFC3339 movement date to YYYY-MM-DD
+func formatStockCardDate(value string) string {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return date.Format("2006-01-02")
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,399 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// StockCardService - Stock card (kartu stok) built from InventoryMovement history
+type StockCardService struct {
+	db *gorm.DB
+}
+
+// NewStockCardService creates a new stock card service instance
+func NewStockCardService(db *gorm.DB) *StockCardService {
+	return &StockCardService{
+		db: db,
+	}
+}
+
+// stockCardBalance - Summed movement quantity of one batch (nil batch = no batch)
+type stockCardBalance struct {
+	BatchID     *string
+	Quantity    decimal.Decimal
+	QuantityIn  decimal.Decimal
+	QuantityOut decimal.Decimal
+}
+
+// GetStockCard returns one page of the stock card of a product in a warehouse.
+// The running balance of the page continues from the opening balance and all earlier movements in the period.
+func (s *StockCardService) GetStockCard(ctx context.Context, tenantID, companyID string, query *dto.StockCardQuery) (*dto.StockCardResponse, error) {
+	return s.buildStockCard(ctx, tenantID, companyID, query, true)
+}
+
+// GetStockCardForExport returns the stock card with every movement in the period on a single page
+func (s *StockCardService) GetStockCardForExport(ctx context.Context, tenantID, companyID string, query *dto.StockCardQuery) (*dto.StockCardResponse, error) {
+	return s.buildStockCard(ctx, tenantID, companyID, query, false)
+}
+
+func (s *StockCardService) buildStockCard(ctx context.Context, tenantID, companyID string, query *dto.StockCardQuery, paginate bool) (*dto.StockCardResponse, error) {
+	now := time.Now()
+	dateFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if query.DateFrom != "" {
+		date, err := time.Parse("2006-01-02", query.DateFrom)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid dateFrom format (use YYYY-MM-DD)")
+		}
+		dateFrom = date
+	}
+	dateTo := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if query.DateTo != "" {
+		date, err := time.Parse("2006-01-02", query.DateTo)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid dateTo format (use YYYY-MM-DD)")
+		}
+		dateTo = date
+	}
+	if dateTo.Before(dateFrom) {
+		return nil, pkgerrors.NewBadRequestError("dateTo must not be before dateFrom")
+	}
+	// Movements before the start of the day after dateTo are included
+	cutoff := time.Date(dateTo.Year(), dateTo.Month(), dateTo.Day()+1, 0, 0, 0, 0, dateTo.Location())
+
+	var warehouse models.Warehouse
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("id = ? AND company_id = ?", query.WarehouseID, companyID).
+		First(&warehouse).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("warehouse")
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	var product models.Product
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("id = ? AND tenant_id = ? AND company_id = ?", query.ProductID, tenantID, companyID).
+		First(&product).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("product")
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if query.BatchID != nil && *query.BatchID != "" {
+		var count int64
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.ProductBatch{}).
+			Where("id = ? AND product_id = ?", *query.BatchID, product.ID).
+			Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to get batch: %w", err)
+		}
+		if count == 0 {
+			return nil, pkgerrors.NewNotFoundError("batch")
+		}
+	}
+
+	// Bin moves only relocate stock inside the warehouse, so they are left off the card
+	movements := func() *gorm.DB {
+		dbQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.InventoryMovement{}).
+			Where("inventory_movements.tenant_id = ? AND inventory_movements.company_id = ?", tenantID, companyID).
+			Where("inventory_movements.warehouse_id = ? AND inventory_movements.product_id = ?", warehouse.ID, product.ID).
+			Where("(inventory_movements.reference_type IS NULL OR inventory_movements.reference_type <> ?)", ReferenceTypeBinMove)
+		if query.BatchID != nil && *query.BatchID != "" {
+			dbQuery = dbQuery.Where("inventory_movements.batch_id = ?", *query.BatchID)
+		}
+		return dbQuery
+	}
+	inPeriod := func() *gorm.DB {
+		return movements().Where("inventory_movements.movement_date >= ? AND inventory_movements.movement_date < ?", dateFrom, cutoff)
+	}
+
+	var opening []stockCardBalance
+	if err := movements().
+		Select("batch_id, COALESCE(SUM(quantity), 0) as quantity").
+		Where("movement_date < ?", dateFrom).
+		Group("batch_id").
+		Scan(&opening).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum opening balance: %w", err)
+	}
+
+	var period []stockCardBalance
+	if err := inPeriod().
+		Select(`batch_id,
+			COALESCE(SUM(quantity), 0) as quantity,
+			COALESCE(SUM(CASE WHEN quantity > 0 THEN quantity ELSE 0 END), 0) as quantity_in,
+			COALESCE(SUM(CASE WHEN quantity < 0 THEN -quantity ELSE 0 END), 0) as quantity_out`).
+		Group("batch_id").
+		Scan(&period).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum stock card period: %w", err)
+	}
+
+	var totalCount int64
+	if err := inPeriod().Count(&totalCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count stock card movements: %w", err)
+	}
+
+	page := 1
+	pageSize := int(totalCount)
+	if paginate {
+		if query.Page > 0 {
+			page = query.Page
+		}
+		pageSize = 20
+		if query.PageSize > 0 {
+			pageSize = query.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	// Running balances start from the opening balance plus the movements on earlier pages
+	balance := decimal.Zero
+	batchBalances := map[string]decimal.Decimal{}
+	for _, row := range opening {
+		balance = balance.Add(row.Quantity)
+		batchBalances[stockCardBatchKey(row.BatchID)] = batchBalances[stockCardBatchKey(row.BatchID)].Add(row.Quantity)
+	}
+	openingBalance := balance
+
+	if offset > 0 {
+		earlierRows := inPeriod().
+			Select("batch_id, quantity").
+			Order("movement_date ASC, created_at ASC, id ASC").
+			Limit(offset)
+
+		var earlier []stockCardBalance
+		if err := s.db.WithContext(ctx).Table("(?) AS earlier", earlierRows).
+			Select("batch_id, COALESCE(SUM(quantity), 0) as quantity").
+			Group("batch_id").
+			Scan(&earlier).Error; err != nil {
+			return nil, fmt.Errorf("failed to sum earlier stock card movements: %w", err)
+		}
+		for _, row := range earlier {
+			balance = balance.Add(row.Quantity)
+			batchBalances[stockCardBatchKey(row.BatchID)] = batchBalances[stockCardBatchKey(row.BatchID)].Add(row.Quantity)
+		}
+	}
+
+	var rows []models.InventoryMovement
+	if pageSize > 0 {
+		if err := inPeriod().
+			Preload("Batch").
+			Preload("Bin").
+			Order("movement_date ASC, created_at ASC, id ASC").
+			Limit(pageSize).
+			Offset(offset).
+			Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to list stock card movements: %w", err)
+		}
+	}
+
+	userNames, err := s.userNames(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dto.StockCardEntry, len(rows))
+	for i, movement := range rows {
+		balance = balance.Add(movement.Quantity)
+
+		entry := dto.StockCardEntry{
+			MovementID:      movement.ID,
+			MovementDate:    movement.MovementDate.Format(time.RFC3339),
+			MovementType:    string(movement.MovementType),
+			ReferenceType:   movement.ReferenceType,
+			ReferenceID:     movement.ReferenceID,
+			ReferenceNumber: movement.ReferenceNumber,
+			BatchID:         movement.BatchID,
+			QuantityIn:      "0",
+			QuantityOut:     "0",
+			Balance:         balance.String(),
+			UnitCost:        movement.UnitCost.String(),
+			Notes:           movement.Notes,
+			PostedBy:        movement.CreatedBy,
+		}
+		if movement.Quantity.IsPositive() {
+			entry.QuantityIn = movement.Quantity.String()
+		} else {
+			entry.QuantityOut = movement.Quantity.Neg().String()
+		}
+		if movement.Batch != nil {
+			entry.BatchNumber = movement.Batch.BatchNumber
+		}
+		if movement.Bin != nil {
+			entry.BinCode = movement.Bin.Code
+		}
+		if movement.CreatedBy != nil {
+			entry.PostedByName = userNames[*movement.CreatedBy]
+		}
+		if query.ByBatch {
+			key := stockCardBatchKey(movement.BatchID)
+			batchBalances[key] = batchBalances[key].Add(movement.Quantity)
+			entry.BatchBalance = batchBalances[key].String()
+		}
+		data[i] = entry
+	}
+
+	totalIn := decimal.Zero
+	totalOut := decimal.Zero
+	closingBalance := openingBalance
+	for _, row := range period {
+		totalIn = totalIn.Add(row.QuantityIn)
+		totalOut = totalOut.Add(row.QuantityOut)
+		closingBalance = closingBalance.Add(row.Quantity)
+	}
+
+	response := &dto.StockCardResponse{
+		Success:        true,
+		WarehouseID:    warehouse.ID,
+		WarehouseCode:  warehouse.Code,
+		WarehouseName:  warehouse.Name,
+		ProductID:      product.ID,
+		ProductCode:    product.Code,
+		ProductName:    product.Name,
+		BaseUnit:       product.BaseUnit,
+		DateFrom:       dateFrom.Format("2006-01-02"),
+		DateTo:         dateTo.Format("2006-01-02"),
+		OpeningBalance: openingBalance.String(),
+		TotalIn:        totalIn.String(),
+		TotalOut:       totalOut.String(),
+		ClosingBalance: closingBalance.String(),
+		Data:           data,
+		Pagination: dto.PaginationInfo{
+			Page:  page,
+			Limit: pageSize,
+			Total: int(totalCount),
+		},
+	}
+	if pageSize > 0 {
+		response.Pagination.TotalPages = int(math.Ceil(float64(totalCount) / float64(pageSize)))
+	}
+
+	if query.ByBatch {
+		batches, err := s.batchSummaries(ctx, tenantID, opening, period)
+		if err != nil {
+			return nil, err
+		}
+		response.Batches = batches
+	}
+
+	return response, nil
+}
+
+// batchSummaries merges opening and period balances per batch, ordered by batch number with unbatched stock last.
+// Batches with no opening balance and no movement in the period are left out.
+func (s *StockCardService) batchSummaries(ctx context.Context, tenantID string, opening, period []stockCardBalance) ([]dto.StockCardBatchSummary, error) {
+	summaries := map[string]*stockCardBalance{}
+	openings := map[string]decimal.Decimal{}
+	batchIDs := []string{}
+
+	summary := func(batchID *string) *stockCardBalance {
+		key := stockCardBatchKey(batchID)
+		if _, ok := summaries[key]; !ok {
+			summaries[key] = &stockCardBalance{BatchID: batchID}
+			if batchID != nil {
+				batchIDs = append(batchIDs, *batchID)
+			}
+		}
+		return summaries[key]
+	}
+	for _, row := range opening {
+		if row.Quantity.IsZero() {
+			continue
+		}
+		summary(row.BatchID)
+		openings[stockCardBatchKey(row.BatchID)] = row.Quantity
+	}
+	for _, row := range period {
+		current := summary(row.BatchID)
+		current.Quantity = row.Quantity
+		current.QuantityIn = row.QuantityIn
+		current.QuantityOut = row.QuantityOut
+	}
+
+	batches := map[string]models.ProductBatch{}
+	if len(batchIDs) > 0 {
+		var rows []models.ProductBatch
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+			Where("id IN ?", batchIDs).
+			Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to load batches: %w", err)
+		}
+		for _, batch := range rows {
+			batches[batch.ID] = batch
+		}
+	}
+
+	result := make([]dto.StockCardBatchSummary, 0, len(summaries))
+	for key, current := range summaries {
+		openingBalance := openings[key]
+		item := dto.StockCardBatchSummary{
+			BatchID:        current.BatchID,
+			OpeningBalance: openingBalance.String(),
+			TotalIn:        current.QuantityIn.String(),
+			TotalOut:       current.QuantityOut.String(),
+			ClosingBalance: openingBalance.Add(current.Quantity).String(),
+		}
+		if batch, ok := batches[key]; ok {
+			item.BatchNumber = batch.BatchNumber
+			if batch.ExpiryDate != nil {
+				item.ExpiryDate = batch.ExpiryDate.Format("2006-01-02")
+			}
+		}
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].BatchID == nil) != (result[j].BatchID == nil) {
+			return result[j].BatchID == nil
+		}
+		return result[i].BatchNumber < result[j].BatchNumber
+	})
+
+	return result, nil
+}
+
+// userNames maps the users who posted the movements to their names
+func (s *StockCardService) userNames(ctx context.Context, movements []models.InventoryMovement) (map[string]string, error) {
+	names := map[string]string{}
+
+	userIDs := []string{}
+	for _, movement := range movements {
+		if movement.CreatedBy == nil || *movement.CreatedBy == "" {
+			continue
+		}
+		if _, ok := names[*movement.CreatedBy]; !ok {
+			names[*movement.CreatedBy] = ""
+			userIDs = append(userIDs, *movement.CreatedBy)
+		}
+	}
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	var users []models.User
+	if err := s.db.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load posting users: %w", err)
+	}
+	for _, user := range users {
+		names[user.ID] = user.FullName
+	}
+
+	return names, nil
+}
+
+// stockCardBatchKey returns the map key of a batch ("" for movements without a batch)
+func stockCardBatchKey(batchID *string) string {
+	if batchID == nil {
+		return ""
+	}
+	return *batchID
+}
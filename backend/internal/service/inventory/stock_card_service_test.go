@@ -0,0 +1,152 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCardService_GetStockCard(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	user := &models.User{ID: "user-1", Email: "gudang@example.com", Username: "gudang", PasswordHash: "x", FullName: "Staf Gudang"}
+	require.NoError(t, db.Create(user).Error)
+
+	stock := &models.WarehouseStock{WarehouseID: warehouse.ID, ProductID: product.ID}
+	require.NoError(t, db.Create(stock).Error)
+	batchA := &models.ProductBatch{BatchNumber: "B-A", ProductID: product.ID, WarehouseStockID: stock.ID, ReceiptDate: time.Now()}
+	require.NoError(t, db.Create(batchA).Error)
+	batchB := &models.ProductBatch{BatchNumber: "B-B", ProductID: product.ID, WarehouseStockID: stock.ID, ReceiptDate: time.Now()}
+	require.NoError(t, db.Create(batchB).Error)
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 10, 0, 0, 0, time.UTC)
+	}
+	movement := func(date time.Time, qty int64, batch *models.ProductBatch, refType, refNumber string) {
+		m := &models.InventoryMovement{
+			TenantID:        company.TenantID,
+			CompanyID:       company.ID,
+			MovementDate:    date,
+			WarehouseID:     warehouse.ID,
+			ProductID:       product.ID,
+			MovementType:    models.MovementTypeIn,
+			Quantity:        decimal.NewFromInt(qty),
+			ReferenceType:   &refType,
+			ReferenceNumber: &refNumber,
+			CreatedBy:       &user.ID,
+		}
+		if qty < 0 {
+			m.MovementType = models.MovementTypeOut
+		}
+		if batch != nil {
+			m.BatchID = &batch.ID
+		}
+		require.NoError(t, db.Create(m).Error)
+	}
+
+	// Before the period: opening balance 10 (A) + 5 (B)
+	movement(day(1), 10, batchA, ReferenceTypeGoodsReceipt, "GRN-001")
+	movement(day(2), 5, batchB, ReferenceTypeGoodsReceipt, "GRN-002")
+	// In the period
+	movement(day(10), -4, batchA, ReferenceTypeDelivery, "DEL-001")
+	movement(day(11), 8, batchB, ReferenceTypeGoodsReceipt, "GRN-003")
+	movement(day(12), -3, batchB, ReferenceTypeDelivery, "DEL-002")
+	// Bin moves net to zero and stay off the card
+	movement(day(12), -2, batchA, ReferenceTypeBinMove, "BIN-001")
+	movement(day(12), 2, batchA, ReferenceTypeBinMove, "BIN-001")
+	// After the period
+	movement(day(25), -1, batchA, ReferenceTypeDelivery, "DEL-003")
+
+	service := NewStockCardService(db)
+	query := func(page, pageSize int, byBatch bool) *dto.StockCardQuery {
+		return &dto.StockCardQuery{
+			WarehouseID: warehouse.ID,
+			ProductID:   product.ID,
+			DateFrom:    "2026-03-05",
+			DateTo:      "2026-03-20",
+			ByBatch:     byBatch,
+			Page:        page,
+			PageSize:    pageSize,
+		}
+	}
+
+	t.Run("success - opening, running and closing balance", func(t *testing.T) {
+		card, err := service.GetStockCard(context.Background(), company.TenantID, company.ID, query(1, 20, false))
+
+		require.NoError(t, err)
+		assert.Equal(t, "15", card.OpeningBalance)
+		assert.Equal(t, "8", card.TotalIn)
+		assert.Equal(t, "7", card.TotalOut)
+		assert.Equal(t, "16", card.ClosingBalance)
+		assert.Nil(t, card.Batches)
+		require.Len(t, card.Data, 3)
+		assert.Equal(t, "DEL-001", *card.Data[0].ReferenceNumber)
+		assert.Equal(t, "4", card.Data[0].QuantityOut)
+		assert.Equal(t, "11", card.Data[0].Balance)
+		assert.Equal(t, "B-A", card.Data[0].BatchNumber)
+		assert.Equal(t, "Staf Gudang", card.Data[0].PostedByName)
+		assert.Equal(t, "19", card.Data[1].Balance)
+		assert.Equal(t, "16", card.Data[2].Balance)
+		assert.Equal(t, 3, card.Pagination.Total)
+	})
+
+	t.Run("success - later page continues the running balance", func(t *testing.T) {
+		card, err := service.GetStockCard(context.Background(), company.TenantID, company.ID, query(2, 2, true))
+
+		require.NoError(t, err)
+		require.Len(t, card.Data, 1)
+		assert.Equal(t, "DEL-002", *card.Data[0].ReferenceNumber)
+		assert.Equal(t, "16", card.Data[0].Balance)
+		assert.Equal(t, "10", card.Data[0].BatchBalance)
+		assert.Equal(t, 2, card.Pagination.TotalPages)
+	})
+
+	t.Run("success - per batch summary", func(t *testing.T) {
+		card, err := service.GetStockCard(context.Background(), company.TenantID, company.ID, query(1, 20, true))
+
+		require.NoError(t, err)
+		require.Len(t, card.Batches, 2)
+		assert.Equal(t, "B-A", card.Batches[0].BatchNumber)
+		assert.Equal(t, "10", card.Batches[0].OpeningBalance)
+		assert.Equal(t, "4", card.Batches[0].TotalOut)
+		assert.Equal(t, "6", card.Batches[0].ClosingBalance)
+		assert.Equal(t, "B-B", card.Batches[1].BatchNumber)
+		assert.Equal(t, "10", card.Batches[1].ClosingBalance)
+		assert.Equal(t, "6", card.Data[0].BatchBalance)
+	})
+
+	t.Run("success - CSV and PDF export", func(t *testing.T) {
+		card, err := service.GetStockCardForExport(context.Background(), company.TenantID, company.ID, query(0, 0, false))
+		require.NoError(t, err)
+		require.Len(t, card.Data, 3)
+
+		csvContent, err := service.GenerateStockCardCSV(card)
+		require.NoError(t, err)
+		assert.Contains(t, string(csvContent), "2026-03-10,OUT,DELIVERY,DEL-001,B-A,,0,4,11")
+		assert.True(t, strings.HasSuffix(strings.TrimSpace(string(csvContent)), "2026-03-20,CLOSING,,,,,8,7,16"))
+
+		pdfContent, err := service.GenerateStockCardPDF(card)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(pdfContent), "%PDF"))
+	})
+
+	t.Run("error - dateTo before dateFrom", func(t *testing.T) {
+		q := query(1, 20, false)
+		q.DateTo = "2026-03-01"
+
+		_, err := service.GetStockCard(context.Background(), company.TenantID, company.ID, q)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dateTo must not be before dateFrom")
+	})
+}
@@ -0,0 +1,34 @@
+package inventory
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// checkStockFreeze rejects a posting that falls inside the freeze scope of a stock opname being counted.
+// The opname's own adjustments are let through.
+func (s *StockPostingService) checkStockFreeze(tx *gorm.DB, posting *StockPosting) error {
+	if posting.ReferenceType == ReferenceTypeStockOpname {
+		return nil
+	}
+
+	var opnameNumbers []string
+	if err := tx.Model(&models.StockOpname{}).
+		Where("warehouse_id = ? AND status = ?", posting.WarehouseID, models.StockOpnameStatusInProgress).
+		Where("freeze_scope = ? OR (freeze_scope = ? AND EXISTS (SELECT 1 FROM stock_opname_items WHERE stock_opname_items.stock_opname_id = stock_opnames.id AND stock_opname_items.product_id = ?))",
+			models.OpnameFreezeScopeWarehouse, models.OpnameFreezeScopeProducts, posting.ProductID).
+		Limit(1).
+		Pluck("opname_number", &opnameNumbers).Error; err != nil {
+		return fmt.Errorf("failed to check stock opname freeze: %w", err)
+	}
+
+	if len(opnameNumbers) > 0 {
+		return pkgerrors.NewConflictError(fmt.Sprintf("Stock of product %s is frozen by stock opname %s in progress",
+			posting.ProductID, opnameNumbers[0]))
+	}
+	return nil
+}
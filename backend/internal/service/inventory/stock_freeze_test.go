@@ -0,0 +1,81 @@
+package inventory
+
+import (
+	"testing"
+
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockPostingService_OpnameFreeze(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	other := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD002", Name: "Other Product", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(other).Error)
+
+	opname := &models.StockOpname{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		OpnameNumber: "OPN-20260301-001",
+		WarehouseID:  warehouse.ID,
+		Status:       models.StockOpnameStatusInProgress,
+		FreezeScope:  models.OpnameFreezeScopeProducts,
+	}
+	require.NoError(t, db.Create(opname).Error)
+	require.NoError(t, db.Create(&models.StockOpnameItem{StockOpnameID: opname.ID, ProductID: product.ID}).Error)
+
+	service := NewStockPostingService(db)
+	post := func(productID, referenceType string) error {
+		_, err := service.Post(db, &StockPosting{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			WarehouseID:   warehouse.ID,
+			ProductID:     productID,
+			MovementType:  models.MovementTypeAdjustment,
+			Quantity:      decimal.NewFromInt(5),
+			ReferenceType: referenceType,
+		})
+		return err
+	}
+
+	t.Run("error - product in the opname is frozen", func(t *testing.T) {
+		err := post(product.ID, ReferenceTypeGoodsReceipt)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "frozen by stock opname OPN-20260301-001")
+	})
+
+	t.Run("success - products outside the opname still post", func(t *testing.T) {
+		assert.NoError(t, post(other.ID, ReferenceTypeGoodsReceipt))
+	})
+
+	t.Run("success - the opname's own adjustment posts", func(t *testing.T) {
+		assert.NoError(t, post(product.ID, ReferenceTypeStockOpname))
+	})
+
+	t.Run("error - warehouse scope freezes every product", func(t *testing.T) {
+		require.NoError(t, db.Model(opname).Update("freeze_scope", models.OpnameFreezeScopeWarehouse).Error)
+
+		assert.Error(t, post(other.ID, ReferenceTypeInventoryAdjustment))
+	})
+
+	t.Run("error - bin moves are frozen too", func(t *testing.T) {
+		binID := "bin-1"
+		_, err := service.MoveBinStock(db, &BinMove{
+			TenantID:    company.TenantID,
+			CompanyID:   company.ID,
+			WarehouseID: warehouse.ID,
+			ProductID:   other.ID,
+			ToBinID:     &binID,
+			Quantity:    decimal.NewFromInt(1),
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "frozen by stock opname OPN-20260301-001")
+	})
+}
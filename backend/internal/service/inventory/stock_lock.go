@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// lockStock loads the stock row for warehouse + product with SELECT ... FOR UPDATE.
+// Every stock write goes through this row first, so locking it serialises all changes to
+// the position (batches, bins, reservations, cost layers) until the transaction ends.
+// Returns gorm.ErrRecordNotFound when the product has never been stocked in the warehouse.
+func lockStock(tx *gorm.DB, warehouseID, productID string) (*models.WarehouseStock, error) {
+	var stock models.WarehouseStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).
+		First(&stock).Error; err != nil {
+		return nil, err
+	}
+
+	return &stock, nil
+}
+
+// updateStock writes columns of a stock row and bumps its version.
+// The update only applies when the version is still the one that was read, so a writer
+// that did not take the row lock cannot silently overwrite a newer quantity.
+func updateStock(tx *gorm.DB, stock *models.WarehouseStock, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+
+	result := tx.Model(&models.WarehouseStock{}).
+		Where("id = ? AND version = ?", stock.ID, stock.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update warehouse stock: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return pkgerrors.NewConflictError("Stock was changed by another transaction, please retry")
+	}
+
+	stock.Version++
+	return nil
+}
+
+// negativeStockPolicy returns how a company treats postings that drive on-hand below zero (BLOCK when unset)
+func (s *StockPostingService) negativeStockPolicy(tx *gorm.DB, companyID string) (models.NegativeStockPolicy, error) {
+	var company models.Company
+	if err := tx.Select("id", "negative_stock_policy").Where("id = ?", companyID).First(&company).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return models.NegativeStockPolicyBlock, nil
+		}
+		return "", fmt.Errorf("failed to get company negative stock policy: %w", err)
+	}
+
+	switch company.NegativeStockPolicy {
+	case models.NegativeStockPolicyAllow, models.NegativeStockPolicyWarn:
+		return company.NegativeStockPolicy, nil
+	}
+	return models.NegativeStockPolicyBlock, nil
+}
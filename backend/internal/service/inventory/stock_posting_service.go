@@ -0,0 +1,435 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// Reference types written to InventoryMovement.ReferenceType
+const (
+	ReferenceTypeInitialStock          = "INITIAL_STOCK"
+	ReferenceTypeGoodsReceipt          = "GOODS_RECEIPT"
+	ReferenceTypeDelivery              = "DELIVERY"
+	ReferenceTypeStockTransfer         = "STOCK_TRANSFER"
+	ReferenceTypeStockOpname           = "STOCK_OPNAME"
+	ReferenceTypeInventoryAdjustment   = "INVENTORY_ADJUSTMENT"
+	ReferenceTypePurchaseInvoice       = "PURCHASE_INVOICE"
+	ReferenceTypeBinMove               = "BIN_MOVE"
+	ReferenceTypeConsignmentSettlement = "CONSIGNMENT_SETTLEMENT"
+	ReferenceTypeAssemblyOrder         = "ASSEMBLY_ORDER"
+	ReferenceTypeQualityHold           = "QUALITY_HOLD"
+	ReferenceTypeCustomerReturn        = "CUSTOMER_RETURN"
+)
+
+// StockPostingService is the single entry point for changing stock quantities.
+// Every posting updates WarehouseStock (and ProductBatch when a batch is given)
+// and writes the matching InventoryMovement inside the caller's transaction,
+// so the movements table stays a complete audit trail for stock.
+type StockPostingService struct {
+	db *gorm.DB
+}
+
+// NewStockPostingService creates a new stock posting service instance
+func NewStockPostingService(db *gorm.DB) *StockPostingService {
+	return &StockPostingService{
+		db: db,
+	}
+}
+
+// BatchDetails identifies the batch an inbound posting goes into.
+// The batch is looked up by product + batch number and created when missing.
+type BatchDetails struct {
+	BatchNumber     string
+	ManufactureDate *time.Time
+	ExpiryDate      *time.Time
+	SupplierID      *string
+	GoodsReceiptID  *string
+	ReferenceNumber *string // Supplier's batch/lot number
+	Quarantine      bool    // Put the batch on quality hold (QUARANTINE) instead of making it available
+}
+
+// StockPosting describes a single stock change for one product in one warehouse
+type StockPosting struct {
+	TenantID     string
+	CompanyID    string
+	WarehouseID  string
+	ProductID    string
+	MovementType models.MovementType
+	Quantity     decimal.Decimal // Positive = IN, Negative = OUT (base unit)
+	MovementDate time.Time       // Defaults to now when zero
+
+	// UnitCost is the cost per base unit of inbound stock (e.g. PO price).
+	// When nil, inbound stock is valued at the current average cost. Outbound
+	// postings consume the current average cost, or the oldest cost layers under FIFO.
+	UnitCost *decimal.Decimal
+
+	// Batch handling - use BatchID for an existing batch, or Batch to find/create one (inbound only)
+	BatchID *string
+	Batch   *BatchDetails
+
+	// BinID puts inbound stock away to, or takes outbound stock from, a bin of the warehouse.
+	// Outbound postings without a bin take unbinned stock first (see PostFromBins to pick by bin).
+	BinID *string
+
+	// RespectReservations stops outbound postings from taking stock reserved for sales orders.
+	// Leave false for physical corrections (opname, adjustment) that must always apply.
+	RespectReservations bool
+
+	ReferenceType   string
+	ReferenceID     string
+	ReferenceNumber string
+	Notes           *string
+	CreatedBy       string
+}
+
+// PostingResult holds the rows touched by a posting
+type PostingResult struct {
+	Movement *models.InventoryMovement
+	Stock    *models.WarehouseStock
+	Batch    *models.ProductBatch
+
+	// Warning is set when the posting took on-hand below zero under the WARN negative stock policy
+	Warning string
+}
+
+// Post applies a stock posting using the given transaction.
+// Callers must pass their own *gorm.DB transaction so the stock change and the
+// document status change commit or roll back together. The stock row is locked
+// for the rest of the transaction, and whether on-hand may go below zero follows
+// the company's negative stock policy.
+func (s *StockPostingService) Post(tx *gorm.DB, posting *StockPosting) (*PostingResult, error) {
+	if posting.Quantity.IsZero() {
+		return nil, pkgerrors.NewBadRequestError("posting quantity cannot be zero")
+	}
+	if posting.BatchID != nil && posting.Batch != nil {
+		return nil, pkgerrors.NewBadRequestError("posting cannot specify both batchId and batch details")
+	}
+	if posting.Batch != nil && posting.Quantity.IsNegative() {
+		return nil, pkgerrors.NewBadRequestError("new batches can only be created by inbound postings")
+	}
+	if err := s.checkStockFreeze(tx, posting); err != nil {
+		return nil, err
+	}
+
+	movementDate := posting.MovementDate
+	if movementDate.IsZero() {
+		movementDate = time.Now()
+	}
+
+	policy, err := s.negativeStockPolicy(tx, posting.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Find (locked) or create the warehouse stock row
+	stock, err := s.findOrCreateStock(tx, posting, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	stockBefore := stock.Quantity
+	stockAfter := stockBefore.Add(posting.Quantity)
+	warning := ""
+	if posting.Quantity.IsNegative() && stockAfter.IsNegative() {
+		switch policy {
+		case models.NegativeStockPolicyBlock:
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient stock for product %s. Available: %s, Required: %s",
+				posting.ProductID, stockBefore.String(), posting.Quantity.Abs().String()))
+		case models.NegativeStockPolicyWarn:
+			warning = fmt.Sprintf("Stock of product %s in warehouse %s is now negative: %s",
+				posting.ProductID, posting.WarehouseID, stockAfter.String())
+		}
+	}
+	if posting.RespectReservations && posting.Quantity.IsNegative() && stockAfter.Sub(stock.QuarantineQuantity).LessThan(stock.ReservedQuantity) {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient available stock for product %s. Available: %s, Required: %s",
+			posting.ProductID, decimal.Max(stock.AvailableQuantity(), decimal.Zero).String(), posting.Quantity.Abs().String()))
+	}
+
+	costingMethod, err := s.costingMethod(tx, posting.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Value the posting and roll the moving average for inbound stock.
+	// Under FIFO, outbound stock is valued at the exact cost of the layers it consumes.
+	unitCost := stock.AverageCost
+	averageCost := stock.AverageCost
+	totalCost := decimal.Zero
+	if posting.Quantity.IsPositive() {
+		if posting.UnitCost != nil {
+			unitCost = *posting.UnitCost
+		}
+		averageCost = movingAverageCost(stockBefore, stock.AverageCost, posting.Quantity, unitCost)
+		totalCost = posting.Quantity.Mul(unitCost).Round(2)
+	} else if costingMethod == models.CostingMethodFIFO {
+		consumed, err := s.consumeCostLayers(tx, stock, posting.Quantity.Abs())
+		if err != nil {
+			return nil, err
+		}
+		unitCost = consumed.Div(posting.Quantity.Abs()).Round(4)
+		totalCost = consumed.Neg().Round(2)
+	} else {
+		totalCost = posting.Quantity.Mul(unitCost).Round(2)
+	}
+
+	if err := updateStock(tx, stock, map[string]interface{}{
+		"quantity":     stockAfter,
+		"average_cost": averageCost,
+	}); err != nil {
+		return nil, err
+	}
+	stock.Quantity = stockAfter
+	stock.AverageCost = averageCost
+
+	// 2. Update batch quantity when the posting is batch-specific
+	var batch *models.ProductBatch
+	if posting.BatchID != nil && *posting.BatchID != "" {
+		batch, err = s.applyToExistingBatch(tx, stock, *posting.BatchID, posting.Quantity, posting.MovementType)
+		if err != nil {
+			return nil, err
+		}
+	} else if posting.Batch != nil && posting.Batch.BatchNumber != "" {
+		batch, err = s.applyToNewOrExistingBatch(tx, stock, posting.Batch, posting.Quantity, movementDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Keep bin stock in step with the posting
+	binID, err := s.applyToBins(tx, stock, batch, posting)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Write the movement
+	movement := &models.InventoryMovement{
+		TenantID:     posting.TenantID,
+		CompanyID:    posting.CompanyID,
+		MovementDate: movementDate,
+		WarehouseID:  posting.WarehouseID,
+		ProductID:    posting.ProductID,
+		MovementType: posting.MovementType,
+		Quantity:     posting.Quantity,
+		StockBefore:  stockBefore,
+		StockAfter:   stockAfter,
+		UnitCost:     unitCost,
+		TotalCost:    totalCost,
+		Notes:        posting.Notes,
+		BinID:        binID,
+	}
+	if batch != nil {
+		movement.BatchID = &batch.ID
+	}
+	if posting.ReferenceType != "" {
+		movement.ReferenceType = &posting.ReferenceType
+	}
+	if posting.ReferenceID != "" {
+		movement.ReferenceID = &posting.ReferenceID
+	}
+	if posting.ReferenceNumber != "" {
+		movement.ReferenceNumber = &posting.ReferenceNumber
+	}
+	if posting.CreatedBy != "" {
+		movement.CreatedBy = &posting.CreatedBy
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, fmt.Errorf("failed to create inventory movement: %w", err)
+	}
+
+	// 5. Open a cost layer for inbound stock under FIFO
+	if costingMethod == models.CostingMethodFIFO && posting.Quantity.IsPositive() {
+		if err := s.createCostLayer(tx, movement); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PostingResult{
+		Movement: movement,
+		Stock:    stock,
+		Batch:    batch,
+		Warning:  warning,
+	}, nil
+}
+
+// movingAverageCost returns the weighted-average unit cost after receiving qty at unitCost.
+// Stock that was empty (or negative) takes the incoming cost as is.
+func movingAverageCost(qtyBefore, costBefore, qty, unitCost decimal.Decimal) decimal.Decimal {
+	if !qtyBefore.IsPositive() {
+		return unitCost.Round(4)
+	}
+
+	valueBefore := qtyBefore.Mul(costBefore)
+	valueIn := qty.Mul(unitCost)
+	return valueBefore.Add(valueIn).Div(qtyBefore.Add(qty)).Round(4)
+}
+
+// findOrCreateStock locks the stock row for warehouse + product.
+// A missing row is created for inbound postings, and for outbound postings when the
+// company allows negative stock.
+func (s *StockPostingService) findOrCreateStock(tx *gorm.DB, posting *StockPosting, policy models.NegativeStockPolicy) (*models.WarehouseStock, error) {
+	stock, err := lockStock(tx, posting.WarehouseID, posting.ProductID)
+	if err == gorm.ErrRecordNotFound {
+		if posting.Quantity.IsNegative() && policy == models.NegativeStockPolicyBlock {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Product %s not found in warehouse", posting.ProductID))
+		}
+
+		stock = &models.WarehouseStock{
+			WarehouseID: posting.WarehouseID,
+			ProductID:   posting.ProductID,
+			Quantity:    decimal.Zero,
+		}
+		if err := tx.Create(stock).Error; err != nil {
+			return nil, fmt.Errorf("failed to create warehouse stock: %w", err)
+		}
+		return stock, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	return stock, nil
+}
+
+// applyToExistingBatch adjusts the quantity of a known batch.
+// Expired, damaged, recalled or quarantined batches cannot leave through sales or transfers;
+// adjustments and opname can still write them off.
+func (s *StockPostingService) applyToExistingBatch(tx *gorm.DB, stock *models.WarehouseStock, batchID string, qty decimal.Decimal, movementType models.MovementType) (*models.ProductBatch, error) {
+	var batch models.ProductBatch
+	if err := tx.Where("id = ? AND product_id = ?", batchID, stock.ProductID).First(&batch).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError(fmt.Sprintf("batch %s not found", batchID))
+		}
+		return nil, fmt.Errorf("failed to get product batch: %w", err)
+	}
+
+	if batch.WarehouseStockID != stock.ID {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("batch %s does not belong to this warehouse", batch.BatchNumber))
+	}
+
+	if qty.IsNegative() && (movementType == models.MovementTypeOut || movementType == models.MovementTypeTransfer || movementType == models.MovementTypeAssembly) {
+		if status := unpickableStatus(&batch, time.Now()); status != "" {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("batch %s cannot be picked: %s", batch.BatchNumber, status))
+		}
+	}
+
+	newQty := batch.Quantity.Add(qty)
+	if newQty.IsNegative() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient batch quantity for batch %s. Available: %s, Required: %s",
+			batch.BatchNumber, batch.Quantity.String(), qty.Abs().String()))
+	}
+
+	updates := map[string]interface{}{
+		"quantity": newQty,
+	}
+	// Keep status in step with quantity so sold-out batches drop out of picking
+	if newQty.IsZero() && batch.Status == models.BatchStatusAvailable {
+		updates["status"] = models.BatchStatusSold
+	} else if newQty.IsPositive() && batch.Status == models.BatchStatusSold {
+		updates["status"] = models.BatchStatusAvailable
+	}
+
+	if err := tx.Model(&batch).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update product batch quantity: %w", err)
+	}
+	batch.Quantity = newQty
+
+	// Held stock stays out of available stock whichever way it moves
+	if batch.Status.IsHeld() {
+		if err := adjustQuarantine(tx, stock, qty); err != nil {
+			return nil, err
+		}
+	}
+
+	return &batch, nil
+}
+
+// applyToNewOrExistingBatch finds a batch by product + batch number in the stock row's
+// warehouse (creating it if needed) and adds the inbound quantity
+func (s *StockPostingService) applyToNewOrExistingBatch(tx *gorm.DB, stock *models.WarehouseStock, details *BatchDetails, qty decimal.Decimal, receiptDate time.Time) (*models.ProductBatch, error) {
+	var batch models.ProductBatch
+	err := tx.Where("product_id = ? AND batch_number = ? AND warehouse_stock_id = ?", stock.ProductID, details.BatchNumber, stock.ID).
+		First(&batch).Error
+
+	if err == gorm.ErrRecordNotFound {
+		status := models.BatchStatusAvailable
+		qualityStatus := models.BatchQualityGood
+		if details.Quarantine {
+			status = models.BatchStatusQuarantine
+			qualityStatus = models.BatchQualityQuarantine
+		}
+		batch = models.ProductBatch{
+			BatchNumber:      details.BatchNumber,
+			ProductID:        stock.ProductID,
+			WarehouseStockID: stock.ID,
+			ManufactureDate:  details.ManufactureDate,
+			ExpiryDate:       details.ExpiryDate,
+			Quantity:         qty,
+			SupplierID:       details.SupplierID,
+			GoodsReceiptID:   details.GoodsReceiptID,
+			ReceiptDate:      receiptDate,
+			Status:           status,
+			QualityStatus:    &qualityStatus,
+			ReferenceNumber:  details.ReferenceNumber,
+		}
+		if err := tx.Create(&batch).Error; err != nil {
+			return nil, fmt.Errorf("failed to create product batch: %w", err)
+		}
+		if details.Quarantine {
+			if err := adjustQuarantine(tx, stock, qty); err != nil {
+				return nil, err
+			}
+		}
+		return &batch, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing batch: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"quantity": batch.Quantity.Add(qty),
+	}
+	// Update expiry date if provided and newer
+	if details.ExpiryDate != nil && (batch.ExpiryDate == nil || details.ExpiryDate.After(*batch.ExpiryDate)) {
+		updates["expiry_date"] = details.ExpiryDate
+		batch.ExpiryDate = details.ExpiryDate
+	}
+	if batch.Status == models.BatchStatusSold {
+		updates["status"] = models.BatchStatusAvailable
+		batch.Status = models.BatchStatusAvailable
+	}
+
+	// A held receipt into a batch already in stock puts the whole batch on hold:
+	// the new stock cannot be told apart from what was there
+	quarantined := decimal.Zero
+	switch {
+	case batch.Status.IsHeld():
+		quarantined = qty
+	case details.Quarantine && batch.Status == models.BatchStatusAvailable:
+		qualityStatus := models.BatchQualityQuarantine
+		updates["status"] = models.BatchStatusQuarantine
+		updates["quality_status"] = qualityStatus
+		batch.Status = models.BatchStatusQuarantine
+		batch.QualityStatus = &qualityStatus
+		quarantined = batch.Quantity.Add(qty)
+	}
+
+	if err := tx.Model(&batch).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update product batch quantity: %w", err)
+	}
+	batch.Quantity = batch.Quantity.Add(qty)
+
+	if err := adjustQuarantine(tx, stock, quarantined); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
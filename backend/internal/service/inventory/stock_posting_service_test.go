@@ -0,0 +1,554 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupPostingTest(t *testing.T) (*gorm.DB, *models.Company, *models.Warehouse, *models.Product) {
+	db := testutil.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.InventoryMovement{}, &models.StockReservation{}, &models.CostLayer{}))
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+
+	product := &models.Product{
+		TenantID:  company.TenantID,
+		CompanyID: company.ID,
+		Code:      "PROD001",
+		Name:      "Test Product",
+		BaseUnit:  "PCS",
+		IsActive:  true,
+	}
+	require.NoError(t, db.Create(product).Error)
+
+	return db, company, warehouse, product
+}
+
+func TestStockPostingService_Post(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	posting := func(qty string) *StockPosting {
+		return &StockPosting{
+			TenantID:        company.TenantID,
+			CompanyID:       company.ID,
+			WarehouseID:     warehouse.ID,
+			ProductID:       product.ID,
+			MovementType:    models.MovementTypeAdjustment,
+			Quantity:        decimal.RequireFromString(qty),
+			ReferenceType:   ReferenceTypeInventoryAdjustment,
+			ReferenceID:     "adj-1",
+			ReferenceNumber: "ADJ-001",
+			CreatedBy:       "user-1",
+		}
+	}
+
+	t.Run("success - inbound creates stock and movement", func(t *testing.T) {
+		result, err := service.Post(db, posting("10"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "10", result.Stock.Quantity.String())
+		assert.Equal(t, "0", result.Movement.StockBefore.String())
+		assert.Equal(t, "10", result.Movement.StockAfter.String())
+		assert.Equal(t, ReferenceTypeInventoryAdjustment, *result.Movement.ReferenceType)
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "10", stock.Quantity.String())
+	})
+
+	t.Run("success - outbound reduces stock", func(t *testing.T) {
+		result, err := service.Post(db, posting("-4"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "10", result.Movement.StockBefore.String())
+		assert.Equal(t, "6", result.Movement.StockAfter.String())
+
+		var count int64
+		db.Model(&models.InventoryMovement{}).Where("product_id = ?", product.ID).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("error - insufficient stock", func(t *testing.T) {
+		_, err := service.Post(db, posting("-7"))
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	})
+
+	t.Run("error - zero quantity", func(t *testing.T) {
+		_, err := service.Post(db, posting("0"))
+		assert.Error(t, err)
+	})
+}
+
+func TestStockPostingService_PostBatch(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	inbound := &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeIn,
+		Quantity:     decimal.NewFromInt(5),
+		Batch:        &BatchDetails{BatchNumber: "B-001"},
+	}
+
+	result, err := service.Post(db, inbound)
+	require.NoError(t, err)
+	require.NotNil(t, result.Batch)
+	assert.Equal(t, "5", result.Batch.Quantity.String())
+	assert.Equal(t, models.BatchStatusAvailable, result.Batch.Status)
+	assert.Equal(t, result.Batch.ID, *result.Movement.BatchID)
+
+	t.Run("success - outbound empties batch", func(t *testing.T) {
+		out, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeOut,
+			Quantity:     decimal.NewFromInt(-5),
+			BatchID:      &result.Batch.ID,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, out.Batch.Quantity.IsZero())
+
+		var batch models.ProductBatch
+		require.NoError(t, db.First(&batch, "id = ?", result.Batch.ID).Error)
+		assert.Equal(t, models.BatchStatusSold, batch.Status)
+	})
+
+	t.Run("error - batch details on outbound", func(t *testing.T) {
+		_, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeOut,
+			Quantity:     decimal.NewFromInt(-1),
+			Batch:        &BatchDetails{BatchNumber: "B-002"},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestStockPostingService_PickBatchesFEFO(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	receive := func(batchNumber string, qty int64, expiry time.Time) {
+		_, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			Batch:        &BatchDetails{BatchNumber: batchNumber, ExpiryDate: &expiry},
+		})
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	receive("LATE", 10, now.AddDate(0, 6, 0))
+	receive("EARLY", 4, now.AddDate(0, 1, 0))
+	receive("EXPIRED", 50, now.AddDate(0, 0, -1))
+
+	t.Run("success - earliest expiry first", func(t *testing.T) {
+		allocations, err := service.PickBatchesFEFO(db, warehouse.ID, product.ID, decimal.NewFromInt(6))
+
+		require.NoError(t, err)
+		require.Len(t, allocations, 2)
+		assert.Equal(t, "EARLY", allocations[0].Batch.BatchNumber)
+		assert.Equal(t, "4", allocations[0].Quantity.String())
+		assert.Equal(t, "LATE", allocations[1].Batch.BatchNumber)
+		assert.Equal(t, "2", allocations[1].Quantity.String())
+	})
+
+	t.Run("error - expired batches are not picked", func(t *testing.T) {
+		_, err := service.PickBatchesFEFO(db, warehouse.ID, product.ID, decimal.NewFromInt(20))
+		assert.Error(t, err)
+	})
+}
+
+func TestStockPostingService_Reservations(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	_, err := service.Post(db, &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeIn,
+		Quantity:     decimal.NewFromInt(10),
+	})
+	require.NoError(t, err)
+
+	reserve := func(orderItemID string, qty int64) error {
+		_, err := service.Reserve(db, &Reservation{
+			TenantID:         company.TenantID,
+			CompanyID:        company.ID,
+			WarehouseID:      warehouse.ID,
+			ProductID:        product.ID,
+			SalesOrderID:     "so-" + orderItemID,
+			SalesOrderItemID: orderItemID,
+			Quantity:         decimal.NewFromInt(qty),
+		})
+		return err
+	}
+
+	getStock := func() models.WarehouseStock {
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		return stock
+	}
+
+	t.Run("success - reserve reduces available", func(t *testing.T) {
+		require.NoError(t, reserve("item-1", 7))
+
+		stock := getStock()
+		assert.Equal(t, "10", stock.Quantity.String())
+		assert.Equal(t, "7", stock.ReservedQuantity.String())
+		assert.Equal(t, "3", stock.AvailableQuantity().String())
+	})
+
+	t.Run("error - cannot reserve more than available", func(t *testing.T) {
+		assert.Error(t, reserve("item-2", 4))
+	})
+
+	t.Run("error - outbound cannot take reserved stock", func(t *testing.T) {
+		_, err := service.Post(db, &StockPosting{
+			TenantID:            company.TenantID,
+			CompanyID:           company.ID,
+			WarehouseID:         warehouse.ID,
+			ProductID:           product.ID,
+			MovementType:        models.MovementTypeOut,
+			Quantity:            decimal.NewFromInt(-5),
+			RespectReservations: true,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("success - consume and release", func(t *testing.T) {
+		require.NoError(t, service.ConsumeReservation(db, "item-1", decimal.NewFromInt(5)))
+		assert.Equal(t, "2", getStock().ReservedQuantity.String())
+
+		require.NoError(t, service.ReleaseReservations(db, "so-item-1"))
+		assert.Equal(t, "0", getStock().ReservedQuantity.String())
+
+		var reservation models.StockReservation
+		require.NoError(t, db.Where("sales_order_item_id = ?", "item-1").First(&reservation).Error)
+		assert.Equal(t, models.StockReservationStatusReleased, reservation.Status)
+		assert.Equal(t, "5", reservation.ConsumedQuantity.String())
+	})
+}
+
+func TestStockPostingService_AverageCost(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	post := func(qty int64, unitCost *decimal.Decimal) *PostingResult {
+		result, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			UnitCost:     unitCost,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	cost1000 := decimal.NewFromInt(1000)
+	cost1600 := decimal.NewFromInt(1600)
+
+	post(10, &cost1000)
+	result := post(5, &cost1600)
+	// (10 x 1000 + 5 x 1600) / 15 = 1200
+	assert.Equal(t, "1200", result.Stock.AverageCost.String())
+
+	t.Run("outbound consumes average cost", func(t *testing.T) {
+		out := post(-3, nil)
+		assert.Equal(t, "1200", out.Movement.UnitCost.String())
+		assert.Equal(t, "-3600", out.Movement.TotalCost.String())
+		assert.Equal(t, "1200", out.Stock.AverageCost.String())
+	})
+}
+
+func TestStockPostingService_FIFOCost(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	require.NoError(t, db.Model(company).Update("costing_method", models.CostingMethodFIFO).Error)
+
+	service := NewStockPostingService(db)
+
+	post := func(qty int64, unitCost *decimal.Decimal, date time.Time) *PostingResult {
+		result, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			UnitCost:     unitCost,
+			MovementDate: date,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	cost1000 := decimal.NewFromInt(1000)
+	cost1600 := decimal.NewFromInt(1600)
+	now := time.Now()
+
+	post(10, &cost1000, now.AddDate(0, 0, -2))
+	post(5, &cost1600, now.AddDate(0, 0, -1))
+
+	var count int64
+	db.Model(&models.CostLayer{}).Where("product_id = ?", product.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	t.Run("outbound consumes oldest layers first", func(t *testing.T) {
+		// 10 x 1000 + 2 x 1600 = 13200
+		out := post(-12, nil, now)
+		assert.Equal(t, "-13200", out.Movement.TotalCost.String())
+		assert.Equal(t, "1100", out.Movement.UnitCost.String())
+
+		var layers []models.CostLayer
+		require.NoError(t, db.Where("product_id = ?", product.ID).Order("layer_date ASC").Find(&layers).Error)
+		require.Len(t, layers, 2)
+		assert.True(t, layers[0].RemainingQuantity.IsZero())
+		assert.Equal(t, "3", layers[1].RemainingQuantity.String())
+	})
+}
+
+func TestStockPostingService_Revalue(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	cost1000 := decimal.NewFromInt(1000)
+	_, err := service.Post(db, &StockPosting{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		WarehouseID:   warehouse.ID,
+		ProductID:     product.ID,
+		MovementType:  models.MovementTypeIn,
+		Quantity:      decimal.NewFromInt(10),
+		UnitCost:      &cost1000,
+		ReferenceType: ReferenceTypeGoodsReceipt,
+		ReferenceID:   "gr-1",
+	})
+	require.NoError(t, err)
+
+	_, err = service.Post(db, &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeOut,
+		Quantity:     decimal.NewFromInt(-6),
+	})
+	require.NoError(t, err)
+
+	t.Run("only the on-hand share is capitalized", func(t *testing.T) {
+		result, err := service.Revalue(db, &Revaluation{
+			TenantID:            company.TenantID,
+			CompanyID:           company.ID,
+			WarehouseID:         warehouse.ID,
+			ProductID:           product.ID,
+			Quantity:            decimal.NewFromInt(10),
+			Amount:              decimal.NewFromInt(2000),
+			SourceReferenceType: ReferenceTypeGoodsReceipt,
+			SourceReferenceID:   "gr-1",
+			ReferenceType:       ReferenceTypePurchaseInvoice,
+			ReferenceID:         "pi-1",
+		})
+
+		require.NoError(t, err)
+		// 4 of 10 units still on hand: 2000 x 4/10 = 800
+		assert.Equal(t, "800", result.Capitalized.String())
+		assert.Equal(t, "1200", result.Expensed.String())
+		require.NotNil(t, result.Movement)
+		assert.True(t, result.Movement.Quantity.IsZero())
+		assert.Equal(t, "800", result.Movement.TotalCost.String())
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		// (4 x 1000 + 800) / 4 = 1200
+		assert.Equal(t, "1200", stock.AverageCost.String())
+	})
+}
+
+func TestStockPostingService_BatchAcrossWarehouses(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	branch := testutil.CreateTestWarehouse(t, db, company.ID, "WH002")
+	service := NewStockPostingService(db)
+
+	receive := func(warehouseID string, qty int64) *PostingResult {
+		result, err := service.Post(db, &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouseID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeTransfer,
+			Quantity:     decimal.NewFromInt(qty),
+			Batch:        &BatchDetails{BatchNumber: "B-001"},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("same batch number is kept per warehouse", func(t *testing.T) {
+		source := receive(warehouse.ID, 10)
+		moved := receive(branch.ID, 4)
+
+		assert.NotEqual(t, source.Batch.ID, moved.Batch.ID)
+		assert.Equal(t, "10", source.Batch.Quantity.String())
+		assert.Equal(t, "4", moved.Batch.Quantity.String())
+	})
+
+	t.Run("in-transit quantity", func(t *testing.T) {
+		require.NoError(t, service.AdjustInTransit(db, branch.ID, product.ID, decimal.NewFromInt(6)))
+		require.NoError(t, service.AdjustInTransit(db, branch.ID, product.ID, decimal.NewFromInt(-2)))
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", branch.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "4", stock.InTransitQuantity.String())
+		assert.Equal(t, "4", stock.Quantity.String())
+	})
+}
+
+func TestStockPostingService_NegativeStockPolicy(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+
+	posting := func(qty string) *StockPosting {
+		return &StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeAdjustment,
+			Quantity:     decimal.RequireFromString(qty),
+		}
+	}
+	setPolicy := func(policy models.NegativeStockPolicy) {
+		require.NoError(t, db.Model(company).Update("negative_stock_policy", policy).Error)
+	}
+
+	t.Run("BLOCK - outbound without stock row is rejected", func(t *testing.T) {
+		setPolicy(models.NegativeStockPolicyBlock)
+		_, err := service.Post(db, posting("-1"))
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	})
+
+	t.Run("WARN - outbound goes negative with a warning", func(t *testing.T) {
+		setPolicy(models.NegativeStockPolicyWarn)
+		_, err := service.Post(db, posting("5"))
+		require.NoError(t, err)
+
+		result, err := service.Post(db, posting("-8"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "-3", result.Stock.Quantity.String())
+		assert.Equal(t, "-3", result.Movement.StockAfter.String())
+		assert.NotEmpty(t, result.Warning)
+	})
+
+	t.Run("ALLOW - outbound goes further negative silently", func(t *testing.T) {
+		setPolicy(models.NegativeStockPolicyAllow)
+		result, err := service.Post(db, posting("-2"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "-5", result.Stock.Quantity.String())
+		assert.Empty(t, result.Warning)
+	})
+
+	t.Run("BLOCK - inbound into negative stock is still accepted", func(t *testing.T) {
+		setPolicy(models.NegativeStockPolicyBlock)
+		result, err := service.Post(db, posting("2"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "-3", result.Stock.Quantity.String())
+
+		_, err = service.Post(db, posting("-1"))
+		assert.Error(t, err)
+	})
+}
+
+func TestStockPostingService_StockVersion(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	service := NewStockPostingService(db)
+	result, err := service.Post(db, &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeIn,
+		Quantity:     decimal.NewFromInt(10),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Stock.Version)
+
+	t.Run("stale write is rejected", func(t *testing.T) {
+		stale, err := lockStock(db, warehouse.ID, product.ID)
+		require.NoError(t, err)
+
+		fresh, err := lockStock(db, warehouse.ID, product.ID)
+		require.NoError(t, err)
+		require.NoError(t, updateStock(db, fresh, map[string]interface{}{"quantity": decimal.NewFromInt(7)}))
+		assert.Equal(t, int64(2), fresh.Version)
+
+		err = updateStock(db, stale, map[string]interface{}{"quantity": decimal.NewFromInt(12)})
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 409, appErr.StatusCode)
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.First(&stock, "id = ?", fresh.ID).Error)
+		assert.Equal(t, "7", stock.Quantity.String())
+	})
+}
@@ -0,0 +1,501 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/audit"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ReferenceTypeStockReconciliation marks the correcting movements posted by a reconciliation run
+const ReferenceTypeStockReconciliation = "STOCK_RECONCILIATION"
+
+// Discrepancy types reported by the stock reconciliation
+const (
+	DiscrepancyLedger       = "LEDGER"                // WarehouseStock.Quantity vs sum of InventoryMovement
+	DiscrepancyBatch        = "BATCH"                 // WarehouseStock.Quantity vs sum of ProductBatch.Quantity
+	DiscrepancyCurrentStock = "PRODUCT_CURRENT_STOCK" // Deprecated Product.CurrentStock vs sum of WarehouseStock
+)
+
+// StockReconciliationService - Detects drift between stock rows, batches, the deprecated
+// Product.CurrentStock and the movement history, and repairs what can be repaired safely.
+//
+// Warehouse stock is taken as the figure of record: bins, batches, reservations and costing
+// all hang off it. Ledger drift is repaired by posting an ADJUSTMENT movement that brings the
+// movement history up to the stock row, without touching the stock itself. Batch drift needs
+// a physical recount (stock opname) and is only reported.
+type StockReconciliationService struct {
+	db                  *gorm.DB
+	stockPostingService *StockPostingService
+	auditService        *audit.AuditService
+}
+
+// NewStockReconciliationService creates a new stock reconciliation service instance
+func NewStockReconciliationService(db *gorm.DB, stockPostingService *StockPostingService, auditService *audit.AuditService) *StockReconciliationService {
+	return &StockReconciliationService{
+		db:                  db,
+		stockPostingService: stockPostingService,
+		auditService:        auditService,
+	}
+}
+
+// ReconciliationOptions controls the scope of a reconciliation run and whether it repairs
+type ReconciliationOptions struct {
+	WarehouseID *string
+	ProductID   *string
+	Repair      bool // Post correcting movements and re-sync Product.CurrentStock
+	DryRun      bool // With Repair: report what would be corrected without writing
+	Notes       *string
+
+	// Audit trail; UserID is nil when run from cmd/maintenance
+	UserID    *string
+	IPAddress *string
+	UserAgent *string
+}
+
+// stockFigure is one warehouse/product position with the figures compared against it
+type stockFigure struct {
+	StockID        *string
+	WarehouseID    string
+	WarehouseCode  string
+	ProductID      string
+	ProductCode    string
+	ProductName    string
+	IsBatchTracked bool
+	Quantity       decimal.Decimal // WarehouseStock.Quantity (zero when the row is missing)
+	AverageCost    decimal.Decimal
+	Ledger         decimal.Decimal // Sum of movements
+	BatchTotal     decimal.Decimal
+	BatchCount     int64
+}
+
+func positionKey(warehouseID, productID string) string {
+	return warehouseID + "|" + productID
+}
+
+// Reconcile compares the company's stock figures and, when asked, repairs the drift it finds
+func (s *StockReconciliationService) Reconcile(ctx context.Context, tenantID, companyID string, opts *ReconciliationOptions) (*dto.StockReconciliationResponse, error) {
+	if opts == nil {
+		opts = &ReconciliationOptions{}
+	}
+	checkedAt := time.Now()
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).Session(&gorm.Session{})
+
+	figures, err := s.loadFigures(db, companyID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies := make([]dto.StockDiscrepancy, 0)
+	ledgerFigures := make(map[int]*stockFigure)
+	for _, figure := range figures {
+		warehouseID := figure.WarehouseID
+
+		if !figure.Quantity.Equal(figure.Ledger) {
+			cause, err := s.ledgerCause(db, figure)
+			if err != nil {
+				return nil, err
+			}
+			ledgerFigures[len(discrepancies)] = figure
+			discrepancies = append(discrepancies, dto.StockDiscrepancy{
+				Type:          DiscrepancyLedger,
+				WarehouseID:   &warehouseID,
+				WarehouseCode: figure.WarehouseCode,
+				ProductID:     figure.ProductID,
+				ProductCode:   figure.ProductCode,
+				ProductName:   figure.ProductName,
+				Expected:      figure.Quantity.String(),
+				Actual:        figure.Ledger.String(),
+				Difference:    figure.Quantity.Sub(figure.Ledger).String(),
+				LikelyCause:   cause,
+				// Without a stock row there is no figure to correct the history to
+				Repairable: figure.StockID != nil,
+			})
+		}
+
+		if (figure.IsBatchTracked || figure.BatchCount > 0) && !figure.Quantity.Equal(figure.BatchTotal) {
+			discrepancies = append(discrepancies, dto.StockDiscrepancy{
+				Type:          DiscrepancyBatch,
+				WarehouseID:   &warehouseID,
+				WarehouseCode: figure.WarehouseCode,
+				ProductID:     figure.ProductID,
+				ProductCode:   figure.ProductCode,
+				ProductName:   figure.ProductName,
+				Expected:      figure.Quantity.String(),
+				Actual:        figure.BatchTotal.String(),
+				Difference:    figure.Quantity.Sub(figure.BatchTotal).String(),
+				LikelyCause:   batchCause(figure),
+			})
+		}
+	}
+
+	// Product.CurrentStock is company-wide, so it is only checked when all warehouses are in scope
+	if opts.WarehouseID == nil || *opts.WarehouseID == "" {
+		productDiscrepancies, err := s.currentStockDiscrepancies(db, companyID, opts.ProductID, figures)
+		if err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, productDiscrepancies...)
+	}
+
+	// Correcting movements would shift the "stock at count time" of an opname being counted
+	for i, figure := range ledgerFigures {
+		if !discrepancies[i].Repairable {
+			continue
+		}
+		if err := s.stockPostingService.checkStockFreeze(db, &StockPosting{WarehouseID: figure.WarehouseID, ProductID: figure.ProductID}); err != nil {
+			discrepancies[i].Repairable = false
+			discrepancies[i].LikelyCause = fmt.Sprintf("%s; cannot be repaired now: %s", discrepancies[i].LikelyCause, err.Error())
+		}
+	}
+
+	response := &dto.StockReconciliationResponse{
+		CheckedAt:     checkedAt.Format(time.RFC3339),
+		DryRun:        opts.Repair && opts.DryRun,
+		StockRows:     len(figures),
+		Discrepancies: discrepancies,
+	}
+	if !opts.Repair || opts.DryRun {
+		return response, nil
+	}
+
+	runID := uuid.New().String()
+	referenceNumber := fmt.Sprintf("RECON-%s", checkedAt.Format("20060102-150405"))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i := range discrepancies {
+			discrepancy := &discrepancies[i]
+			if !discrepancy.Repairable {
+				continue
+			}
+
+			switch discrepancy.Type {
+			case DiscrepancyLedger:
+				movement, err := s.postLedgerCorrection(tx, tenantID, companyID, runID, referenceNumber, ledgerFigures[i], checkedAt, opts)
+				if err != nil {
+					return err
+				}
+				discrepancy.MovementID = &movement.ID
+			case DiscrepancyCurrentStock:
+				if err := tx.Model(&models.Product{}).
+					Where("id = ? AND company_id = ?", discrepancy.ProductID, companyID).
+					Update("current_stock", decimal.RequireFromString(discrepancy.Expected)).Error; err != nil {
+					return fmt.Errorf("failed to sync product current stock: %w", err)
+				}
+			}
+			discrepancy.Repaired = true
+			response.RepairedCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if response.RepairedCount == 0 {
+		return response, nil
+	}
+	response.ReferenceNumber = &referenceNumber
+
+	// === AUDIT LOGGING ===
+	if s.auditService != nil {
+		auditCtx := &audit.AuditContext{
+			TenantID:  &tenantID,
+			CompanyID: &companyID,
+			UserID:    opts.UserID,
+			RequestID: &runID,
+			IPAddress: opts.IPAddress,
+			UserAgent: opts.UserAgent,
+		}
+
+		corrections := make([]map[string]interface{}, 0, response.RepairedCount)
+		for _, discrepancy := range discrepancies {
+			if !discrepancy.Repaired {
+				continue
+			}
+			correction := map[string]interface{}{
+				"type":       discrepancy.Type,
+				"product_id": discrepancy.ProductID,
+				"from":       discrepancy.Actual,
+				"to":         discrepancy.Expected,
+			}
+			if discrepancy.WarehouseID != nil {
+				correction["warehouse_id"] = *discrepancy.WarehouseID
+			}
+			if discrepancy.MovementID != nil {
+				correction["movement_id"] = *discrepancy.MovementID
+			}
+			corrections = append(corrections, correction)
+		}
+
+		if err := s.auditService.LogStockReconciliationRepaired(ctx, auditCtx, runID, referenceNumber, corrections, opts.Notes); err != nil {
+			log.Printf("WARNING: Failed to create audit log for stock reconciliation %s: %v", referenceNumber, err)
+		}
+	}
+
+	return response, nil
+}
+
+// loadFigures returns every warehouse/product position of the company that has a stock row
+// or movements, with its ledger and batch totals, ordered by warehouse and product code
+func (s *StockReconciliationService) loadFigures(db *gorm.DB, companyID string, opts *ReconciliationOptions) ([]*stockFigure, error) {
+	scope := func(query *gorm.DB, warehouseColumn, productColumn string) *gorm.DB {
+		if opts.WarehouseID != nil && *opts.WarehouseID != "" {
+			query = query.Where(warehouseColumn+" = ?", *opts.WarehouseID)
+		}
+		if opts.ProductID != nil && *opts.ProductID != "" {
+			query = query.Where(productColumn+" = ?", *opts.ProductID)
+		}
+		return query
+	}
+
+	var stocks []struct {
+		ID             string
+		WarehouseID    string
+		WarehouseCode  string
+		ProductID      string
+		ProductCode    string
+		ProductName    string
+		IsBatchTracked bool
+		Quantity       decimal.Decimal
+		AverageCost    decimal.Decimal
+	}
+	if err := scope(db.Table("warehouse_stocks").
+		Select("warehouse_stocks.id, warehouse_stocks.warehouse_id, warehouses.code AS warehouse_code, "+
+			"warehouse_stocks.product_id, products.code AS product_code, products.name AS product_name, "+
+			"products.is_batch_tracked, warehouse_stocks.quantity, warehouse_stocks.average_cost").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Joins("JOIN products ON products.id = warehouse_stocks.product_id").
+		Where("warehouses.company_id = ?", companyID),
+		"warehouse_stocks.warehouse_id", "warehouse_stocks.product_id").
+		Scan(&stocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load warehouse stocks: %w", err)
+	}
+
+	figures := make(map[string]*stockFigure, len(stocks))
+	for _, stock := range stocks {
+		stockID := stock.ID
+		figures[positionKey(stock.WarehouseID, stock.ProductID)] = &stockFigure{
+			StockID:        &stockID,
+			WarehouseID:    stock.WarehouseID,
+			WarehouseCode:  stock.WarehouseCode,
+			ProductID:      stock.ProductID,
+			ProductCode:    stock.ProductCode,
+			ProductName:    stock.ProductName,
+			IsBatchTracked: stock.IsBatchTracked,
+			Quantity:       stock.Quantity.Round(3),
+			AverageCost:    stock.AverageCost,
+		}
+	}
+
+	var ledgers []struct {
+		WarehouseID    string
+		WarehouseCode  string
+		ProductID      string
+		ProductCode    string
+		ProductName    string
+		IsBatchTracked bool
+		Quantity       decimal.Decimal
+	}
+	if err := scope(db.Table("inventory_movements").
+		Select("inventory_movements.warehouse_id, warehouses.code AS warehouse_code, "+
+			"inventory_movements.product_id, products.code AS product_code, products.name AS product_name, "+
+			"products.is_batch_tracked, COALESCE(SUM(inventory_movements.quantity), 0) AS quantity").
+		Joins("JOIN warehouses ON warehouses.id = inventory_movements.warehouse_id").
+		Joins("JOIN products ON products.id = inventory_movements.product_id").
+		Where("inventory_movements.company_id = ?", companyID),
+		"inventory_movements.warehouse_id", "inventory_movements.product_id").
+		Group("inventory_movements.warehouse_id, warehouses.code, inventory_movements.product_id, products.code, products.name, products.is_batch_tracked").
+		Scan(&ledgers).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum inventory movements: %w", err)
+	}
+	for _, ledger := range ledgers {
+		key := positionKey(ledger.WarehouseID, ledger.ProductID)
+		figure, ok := figures[key]
+		if !ok {
+			// Movements without a stock row: the row was deleted
+			figure = &stockFigure{
+				WarehouseID:    ledger.WarehouseID,
+				WarehouseCode:  ledger.WarehouseCode,
+				ProductID:      ledger.ProductID,
+				ProductCode:    ledger.ProductCode,
+				ProductName:    ledger.ProductName,
+				IsBatchTracked: ledger.IsBatchTracked,
+			}
+			figures[key] = figure
+		}
+		figure.Ledger = ledger.Quantity.Round(3)
+	}
+
+	var batches []struct {
+		WarehouseID string
+		ProductID   string
+		Quantity    decimal.Decimal
+		Batches     int64
+	}
+	if err := scope(db.Table("product_batches").
+		Select("warehouse_stocks.warehouse_id, warehouse_stocks.product_id, "+
+			"COALESCE(SUM(product_batches.quantity), 0) AS quantity, COUNT(*) AS batches").
+		Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Where("warehouses.company_id = ?", companyID),
+		"warehouse_stocks.warehouse_id", "warehouse_stocks.product_id").
+		Group("warehouse_stocks.warehouse_id, warehouse_stocks.product_id").
+		Scan(&batches).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum product batches: %w", err)
+	}
+	for _, batch := range batches {
+		if figure, ok := figures[positionKey(batch.WarehouseID, batch.ProductID)]; ok {
+			figure.BatchTotal = batch.Quantity.Round(3)
+			figure.BatchCount = batch.Batches
+		}
+	}
+
+	result := make([]*stockFigure, 0, len(figures))
+	for _, figure := range figures {
+		result = append(result, figure)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].WarehouseCode != result[j].WarehouseCode {
+			return result[i].WarehouseCode < result[j].WarehouseCode
+		}
+		return result[i].ProductCode < result[j].ProductCode
+	})
+
+	return result, nil
+}
+
+// ledgerCause explains a ledger discrepancy from the last movement of the position
+func (s *StockReconciliationService) ledgerCause(db *gorm.DB, figure *stockFigure) (string, error) {
+	if figure.StockID == nil {
+		return "movements exist but the warehouse stock row is missing (row deleted outside stock postings); recount with a stock opname", nil
+	}
+
+	var last models.InventoryMovement
+	err := db.Where("warehouse_id = ? AND product_id = ?", figure.WarehouseID, figure.ProductID).
+		Order("created_at DESC, id DESC").
+		First(&last).Error
+	if err == gorm.ErrRecordNotFound {
+		return "stock exists without any movement (set directly in the database or imported without a posting)", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load last movement: %w", err)
+	}
+
+	if !last.StockAfter.Round(3).Equal(figure.Quantity) {
+		return fmt.Sprintf("stock changed after its last movement (stock after %s, stock now %s): updated outside stock postings",
+			last.StockAfter.Round(3).String(), figure.Quantity.String()), nil
+	}
+	return "stock matches its last movement but the history does not add up: movements deleted or edited", nil
+}
+
+// batchCause explains a batch discrepancy
+func batchCause(figure *stockFigure) string {
+	switch {
+	case figure.IsBatchTracked && figure.BatchCount == 0:
+		return "stock posted without batches (batch tracking enabled after stock was received?); recount with a stock opname"
+	case !figure.IsBatchTracked:
+		return "batches left on a product that is no longer batch-tracked"
+	case figure.BatchTotal.GreaterThan(figure.Quantity):
+		return "batch quantities exceed stock: batches changed without a stock posting; recount with a stock opname"
+	default:
+		return "part of the stock is in no batch: posted without a batch; recount with a stock opname"
+	}
+}
+
+// currentStockDiscrepancies compares the deprecated Product.CurrentStock with the stock across all warehouses
+func (s *StockReconciliationService) currentStockDiscrepancies(db *gorm.DB, companyID string, productID *string, figures []*stockFigure) ([]dto.StockDiscrepancy, error) {
+	totals := make(map[string]decimal.Decimal)
+	for _, figure := range figures {
+		totals[figure.ProductID] = totals[figure.ProductID].Add(figure.Quantity)
+	}
+
+	query := db.Model(&models.Product{}).Where("company_id = ?", companyID)
+	if productID != nil && *productID != "" {
+		query = query.Where("id = ?", *productID)
+	}
+	var products []models.Product
+	if err := query.Order("code ASC").Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+
+	discrepancies := make([]dto.StockDiscrepancy, 0)
+	for _, product := range products {
+		total := totals[product.ID]
+		current := product.CurrentStock.Round(3)
+		if current.Equal(total) {
+			continue
+		}
+		discrepancies = append(discrepancies, dto.StockDiscrepancy{
+			Type:        DiscrepancyCurrentStock,
+			ProductID:   product.ID,
+			ProductCode: product.Code,
+			ProductName: product.Name,
+			Expected:    total.String(),
+			Actual:      current.String(),
+			Difference:  total.Sub(current).String(),
+			LikelyCause: "deprecated Product.CurrentStock is not maintained by stock postings",
+			Repairable:  true,
+		})
+	}
+	return discrepancies, nil
+}
+
+// postLedgerCorrection writes an ADJUSTMENT movement that carries the ledger from its sum to the
+// stock row. Warehouse stock, batches and bins are left as they are.
+func (s *StockReconciliationService) postLedgerCorrection(
+	tx *gorm.DB,
+	tenantID, companyID, runID, referenceNumber string,
+	figure *stockFigure,
+	movementDate time.Time,
+	opts *ReconciliationOptions,
+) (*models.InventoryMovement, error) {
+	// Hold the stock row so no posting lands between the check and the correction
+	stock, err := lockStock(tx, figure.WarehouseID, figure.ProductID)
+	if err != nil {
+		return nil, pkgerrors.NewInternalError(fmt.Errorf("failed to lock warehouse stock: %w", err))
+	}
+	if !stock.Quantity.Equal(figure.Quantity) {
+		return nil, pkgerrors.NewConflictError("Stock was changed while reconciling, please run the reconciliation again")
+	}
+
+	quantity := figure.Quantity.Sub(figure.Ledger)
+	notes := fmt.Sprintf("Stock reconciliation: movement history corrected from %s to warehouse stock %s",
+		figure.Ledger.String(), figure.Quantity.String())
+	if opts.Notes != nil && *opts.Notes != "" {
+		notes = fmt.Sprintf("%s. Notes: %s", notes, *opts.Notes)
+	}
+
+	referenceType := ReferenceTypeStockReconciliation
+	movement := &models.InventoryMovement{
+		TenantID:        tenantID,
+		CompanyID:       companyID,
+		MovementDate:    movementDate,
+		WarehouseID:     figure.WarehouseID,
+		ProductID:       figure.ProductID,
+		MovementType:    models.MovementTypeAdjustment,
+		Quantity:        quantity,
+		StockBefore:     figure.Ledger,
+		StockAfter:      figure.Quantity,
+		UnitCost:        figure.AverageCost,
+		TotalCost:       quantity.Mul(figure.AverageCost).Round(2),
+		ReferenceType:   &referenceType,
+		ReferenceID:     &runID,
+		ReferenceNumber: &referenceNumber,
+		Notes:           &notes,
+		CreatedBy:       opts.UserID,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(fmt.Errorf("failed to create correcting movement: %w", err))
+	}
+	return movement, nil
+}
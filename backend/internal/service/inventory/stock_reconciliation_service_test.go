@@ -0,0 +1,170 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/service/audit"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockReconciliationService(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(&models.AuditLog{}))
+
+	ctx := context.Background()
+	batchProduct := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD002", Name: "Susu UHT", BaseUnit: "PCS", IsBatchTracked: true, IsActive: true}
+	require.NoError(t, db.Create(batchProduct).Error)
+
+	postingService := NewStockPostingService(db)
+	_, err := postingService.Post(db, &StockPosting{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		WarehouseID:   warehouse.ID,
+		ProductID:     product.ID,
+		MovementType:  models.MovementTypeIn,
+		Quantity:      decimal.NewFromInt(10),
+		ReferenceType: ReferenceTypeGoodsReceipt,
+		ReferenceID:   "grn-1",
+	})
+	require.NoError(t, err)
+	result, err := postingService.Post(db, &StockPosting{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		WarehouseID:   warehouse.ID,
+		ProductID:     batchProduct.ID,
+		MovementType:  models.MovementTypeIn,
+		Quantity:      decimal.NewFromInt(5),
+		Batch:         &BatchDetails{BatchNumber: "UHT-01"},
+		ReferenceType: ReferenceTypeGoodsReceipt,
+		ReferenceID:   "grn-1",
+	})
+	require.NoError(t, err)
+
+	// Drift: stock edited directly, a batch edited directly, CurrentStock never maintained
+	require.NoError(t, db.Model(&models.WarehouseStock{}).
+		Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).
+		Update("quantity", decimal.NewFromInt(12)).Error)
+	require.NoError(t, db.Model(result.Batch).Update("quantity", decimal.NewFromInt(4)).Error)
+
+	service := NewStockReconciliationService(db, postingService, audit.NewAuditService(db))
+	countMovements := func() int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.InventoryMovement{}).Where("reference_type = ?", ReferenceTypeStockReconciliation).Count(&count).Error)
+		return count
+	}
+
+	t.Run("success - report lists each kind of drift with a likely cause", func(t *testing.T) {
+		report, err := service.Reconcile(ctx, company.TenantID, company.ID, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, report.StockRows)
+		require.Len(t, report.Discrepancies, 4)
+
+		ledger := report.Discrepancies[0]
+		assert.Equal(t, DiscrepancyLedger, ledger.Type)
+		assert.Equal(t, "PROD001", ledger.ProductCode)
+		assert.Equal(t, "12", ledger.Expected)
+		assert.Equal(t, "10", ledger.Actual)
+		assert.Equal(t, "2", ledger.Difference)
+		assert.Contains(t, ledger.LikelyCause, "stock changed after its last movement")
+		assert.True(t, ledger.Repairable)
+
+		batch := report.Discrepancies[1]
+		assert.Equal(t, DiscrepancyBatch, batch.Type)
+		assert.Equal(t, "PROD002", batch.ProductCode)
+		assert.Equal(t, "1", batch.Difference)
+		assert.False(t, batch.Repairable)
+
+		assert.Equal(t, DiscrepancyCurrentStock, report.Discrepancies[2].Type)
+		assert.Equal(t, "12", report.Discrepancies[2].Expected)
+		assert.Equal(t, DiscrepancyCurrentStock, report.Discrepancies[3].Type)
+	})
+
+	t.Run("success - dry run writes nothing", func(t *testing.T) {
+		report, err := service.Reconcile(ctx, company.TenantID, company.ID, &ReconciliationOptions{Repair: true, DryRun: true})
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 0, report.RepairedCount)
+		assert.Nil(t, report.ReferenceNumber)
+		assert.Equal(t, int64(0), countMovements())
+	})
+
+	t.Run("success - repair corrects the ledger without touching stock", func(t *testing.T) {
+		userID := "user-1"
+		report, err := service.Reconcile(ctx, company.TenantID, company.ID, &ReconciliationOptions{Repair: true, UserID: &userID})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, report.RepairedCount)
+		require.NotNil(t, report.ReferenceNumber)
+		require.NotNil(t, report.Discrepancies[0].MovementID)
+		assert.False(t, report.Discrepancies[1].Repaired)
+
+		var movement models.InventoryMovement
+		require.NoError(t, db.First(&movement, "id = ?", *report.Discrepancies[0].MovementID).Error)
+		assert.Equal(t, models.MovementTypeAdjustment, movement.MovementType)
+		assert.Equal(t, "2", movement.Quantity.String())
+		assert.Equal(t, "10", movement.StockBefore.String())
+		assert.Equal(t, "12", movement.StockAfter.String())
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "12", stock.Quantity.String())
+
+		var synced models.Product
+		require.NoError(t, db.First(&synced, "id = ?", product.ID).Error)
+		assert.Equal(t, "12", synced.CurrentStock.String())
+
+		var auditLogs int64
+		require.NoError(t, db.Model(&models.AuditLog{}).Where("action = ?", "STOCK_RECONCILIATION_REPAIRED").Count(&auditLogs).Error)
+		assert.Equal(t, int64(1), auditLogs)
+
+		report, err = service.Reconcile(ctx, company.TenantID, company.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, report.Discrepancies, 1)
+		assert.Equal(t, DiscrepancyBatch, report.Discrepancies[0].Type)
+	})
+
+	t.Run("success - missing stock row is reported but does not block the repair", func(t *testing.T) {
+		orphan := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD003", Name: "Gula Pasir 1kg", BaseUnit: "PCS", IsActive: true}
+		require.NoError(t, db.Create(orphan).Error)
+		_, err := postingService.Post(db, &StockPosting{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			WarehouseID:   warehouse.ID,
+			ProductID:     orphan.ID,
+			MovementType:  models.MovementTypeIn,
+			Quantity:      decimal.NewFromInt(3),
+			ReferenceType: ReferenceTypeGoodsReceipt,
+			ReferenceID:   "grn-2",
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, orphan.ID).Delete(&models.WarehouseStock{}).Error)
+		before := countMovements()
+
+		report, err := service.Reconcile(ctx, company.TenantID, company.ID, &ReconciliationOptions{Repair: true})
+		require.NoError(t, err)
+
+		var ledger *dto.StockDiscrepancy
+		for i := range report.Discrepancies {
+			if report.Discrepancies[i].Type == DiscrepancyLedger {
+				ledger = &report.Discrepancies[i]
+			}
+		}
+		require.NotNil(t, ledger)
+		assert.Equal(t, "PROD003", ledger.ProductCode)
+		assert.Equal(t, "-3", ledger.Difference)
+		assert.Contains(t, ledger.LikelyCause, "warehouse stock row is missing")
+		assert.False(t, ledger.Repairable)
+		assert.False(t, ledger.Repaired)
+		assert.Equal(t, before, countMovements())
+	})
+}
@@ -0,0 +1,180 @@
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// Reservation describes the stock to hold for a single sales order line
+type Reservation struct {
+	TenantID         string
+	CompanyID        string
+	WarehouseID      string
+	ProductID        string
+	SalesOrderID     string
+	SalesOrderItemID string
+	Quantity         decimal.Decimal // Base unit
+}
+
+// Reserve holds available stock for a sales order line.
+// Available stock is on-hand quantity minus what other orders already reserved.
+func (s *StockPostingService) Reserve(tx *gorm.DB, reservation *Reservation) (*models.StockReservation, error) {
+	if !reservation.Quantity.IsPositive() {
+		return nil, pkgerrors.NewBadRequestError("reservation quantity must be greater than zero")
+	}
+
+	stock, err := lockStock(tx, reservation.WarehouseID, reservation.ProductID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+	if err == gorm.ErrRecordNotFound {
+		stock = &models.WarehouseStock{}
+	}
+
+	available := stock.AvailableQuantity()
+	if err == gorm.ErrRecordNotFound || available.LessThan(reservation.Quantity) {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient available stock for product %s. Available: %s, Required: %s",
+			reservation.ProductID, decimal.Max(available, decimal.Zero).String(), reservation.Quantity.String()))
+	}
+
+	if err := updateStock(tx, stock, map[string]interface{}{
+		"reserved_quantity": stock.ReservedQuantity.Add(reservation.Quantity),
+	}); err != nil {
+		return nil, err
+	}
+
+	record := &models.StockReservation{
+		TenantID:         reservation.TenantID,
+		CompanyID:        reservation.CompanyID,
+		WarehouseID:      reservation.WarehouseID,
+		ProductID:        reservation.ProductID,
+		SalesOrderID:     reservation.SalesOrderID,
+		SalesOrderItemID: reservation.SalesOrderItemID,
+		Quantity:         reservation.Quantity,
+		ConsumedQuantity: decimal.Zero,
+		Status:           models.StockReservationStatusActive,
+	}
+	if err := tx.Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create stock reservation: %w", err)
+	}
+
+	return record, nil
+}
+
+// ConsumeReservation moves delivered quantity out of a sales order line's reservation.
+// Lines without a reservation (e.g. orders approved before reservations existed) are ignored.
+func (s *StockPostingService) ConsumeReservation(tx *gorm.DB, salesOrderItemID string, qty decimal.Decimal) error {
+	var reservation models.StockReservation
+	err := tx.Where("sales_order_item_id = ? AND status = ?", salesOrderItemID, models.StockReservationStatusActive).
+		First(&reservation).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get stock reservation: %w", err)
+	}
+
+	take := decimal.Min(reservation.RemainingQuantity(), qty)
+	if !take.IsPositive() {
+		return nil
+	}
+
+	if err := s.adjustReservedQuantity(tx, &reservation, take.Neg()); err != nil {
+		return err
+	}
+
+	reservation.ConsumedQuantity = reservation.ConsumedQuantity.Add(take)
+	updates := map[string]interface{}{
+		"consumed_quantity": reservation.ConsumedQuantity,
+	}
+	if !reservation.RemainingQuantity().IsPositive() {
+		updates["status"] = models.StockReservationStatusFulfilled
+	}
+
+	if err := tx.Model(&reservation).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update stock reservation: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreReservation puts quantity back on a sales order line's reservation
+// when a delivery that consumed it is cancelled. Released reservations stay released.
+func (s *StockPostingService) RestoreReservation(tx *gorm.DB, salesOrderItemID string, qty decimal.Decimal) error {
+	var reservation models.StockReservation
+	err := tx.Where("sales_order_item_id = ? AND status IN ?", salesOrderItemID,
+		[]models.StockReservationStatus{models.StockReservationStatusActive, models.StockReservationStatusFulfilled}).
+		First(&reservation).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get stock reservation: %w", err)
+	}
+
+	restore := decimal.Min(reservation.ConsumedQuantity, qty)
+	if !restore.IsPositive() {
+		return nil
+	}
+
+	if err := s.adjustReservedQuantity(tx, &reservation, restore); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&reservation).Updates(map[string]interface{}{
+		"consumed_quantity": reservation.ConsumedQuantity.Sub(restore),
+		"status":            models.StockReservationStatusActive,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update stock reservation: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseReservations releases whatever is still reserved for a sales order
+func (s *StockPostingService) ReleaseReservations(tx *gorm.DB, salesOrderID string) error {
+	var reservations []models.StockReservation
+	if err := tx.Where("sales_order_id = ? AND status = ?", salesOrderID, models.StockReservationStatusActive).
+		Find(&reservations).Error; err != nil {
+		return fmt.Errorf("failed to load stock reservations: %w", err)
+	}
+
+	now := time.Now()
+	for i := range reservations {
+		reservation := &reservations[i]
+
+		if remaining := reservation.RemainingQuantity(); remaining.IsPositive() {
+			if err := s.adjustReservedQuantity(tx, reservation, remaining.Neg()); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(reservation).Updates(map[string]interface{}{
+			"status":      models.StockReservationStatusReleased,
+			"released_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to release stock reservation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// adjustReservedQuantity changes WarehouseStock.ReservedQuantity for the reservation's product
+func (s *StockPostingService) adjustReservedQuantity(tx *gorm.DB, reservation *models.StockReservation, delta decimal.Decimal) error {
+	stock, err := lockStock(tx, reservation.WarehouseID, reservation.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouse stock: %w", err)
+	}
+
+	reserved := decimal.Max(stock.ReservedQuantity.Add(delta), decimal.Zero)
+	return updateStock(tx, stock, map[string]interface{}{
+		"reserved_quantity": reserved,
+	})
+}
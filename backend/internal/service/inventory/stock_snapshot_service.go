@@ -0,0 +1,394 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// StockSnapshotService - Point-in-time stock rebuilt from InventoryMovement history and month-end snapshots
+type StockSnapshotService struct {
+	db *gorm.DB
+}
+
+// NewStockSnapshotService creates a new stock snapshot service instance
+func NewStockSnapshotService(db *gorm.DB) *StockSnapshotService {
+	return &StockSnapshotService{
+		db: db,
+	}
+}
+
+// stockPosition - Summed quantity and value of one product (and batch) in one warehouse
+type stockPosition struct {
+	WarehouseID string
+	ProductID   string
+	BatchID     *string
+	Quantity    decimal.Decimal
+	Value       decimal.Decimal
+}
+
+func (p stockPosition) key() string {
+	return p.WarehouseID + "|" + p.ProductID + "|" + stockCardBatchKey(p.BatchID)
+}
+
+// GetStockAsOf returns quantity and value per product, warehouse and batch held before the asOf cutoff.
+// A date means the end of that day; a timestamp includes movements strictly before it.
+func (s *StockSnapshotService) GetStockAsOf(ctx context.Context, tenantID, companyID string, query *dto.StockAsOfQuery) (*dto.StockAsOfResponse, error) {
+	cutoff, err := parseAsOf(query.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, snapshot, err := s.positions(s.db.WithContext(ctx).Set("tenant_id", tenantID), tenantID, companyID, cutoff, query.WarehouseID, query.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.StockAsOfResponse{
+		AsOf:  cutoff.Format(time.RFC3339),
+		Items: []dto.StockAsOfItem{},
+	}
+	if snapshot != nil {
+		snapshotAt := snapshot.SnapshotAt.Format(time.RFC3339)
+		response.SnapshotID = &snapshot.ID
+		response.SnapshotAt = &snapshotAt
+	}
+
+	if len(positions) == 0 {
+		response.TotalValue = decimal.Zero.StringFixed(2)
+		return response, nil
+	}
+
+	warehouseIDs := []string{}
+	productIDs := []string{}
+	batchIDs := []string{}
+	seen := map[string]bool{}
+	for _, position := range positions {
+		if !seen["w"+position.WarehouseID] {
+			seen["w"+position.WarehouseID] = true
+			warehouseIDs = append(warehouseIDs, position.WarehouseID)
+		}
+		if !seen["p"+position.ProductID] {
+			seen["p"+position.ProductID] = true
+			productIDs = append(productIDs, position.ProductID)
+		}
+		if position.BatchID != nil && !seen["b"+*position.BatchID] {
+			seen["b"+*position.BatchID] = true
+			batchIDs = append(batchIDs, *position.BatchID)
+		}
+	}
+
+	var warehouses []models.Warehouse
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Where("id IN ?", warehouseIDs).Find(&warehouses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load warehouses: %w", err)
+	}
+	var products []models.Product
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+	var batches []models.ProductBatch
+	if len(batchIDs) > 0 {
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Where("id IN ?", batchIDs).Find(&batches).Error; err != nil {
+			return nil, fmt.Errorf("failed to load batches: %w", err)
+		}
+	}
+
+	warehouseByID := map[string]models.Warehouse{}
+	for _, warehouse := range warehouses {
+		warehouseByID[warehouse.ID] = warehouse
+	}
+	productByID := map[string]models.Product{}
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+	batchNumbers := map[string]string{}
+	for _, batch := range batches {
+		batchNumbers[batch.ID] = batch.BatchNumber
+	}
+
+	totalValue := decimal.Zero
+	for _, position := range positions {
+		warehouse := warehouseByID[position.WarehouseID]
+		product := productByID[position.ProductID]
+		item := dto.StockAsOfItem{
+			WarehouseID:   position.WarehouseID,
+			WarehouseCode: warehouse.Code,
+			WarehouseName: warehouse.Name,
+			ProductID:     position.ProductID,
+			ProductCode:   product.Code,
+			ProductName:   product.Name,
+			BaseUnit:      product.BaseUnit,
+			BatchID:       position.BatchID,
+			Quantity:      position.Quantity.String(),
+			Value:         position.Value.StringFixed(2),
+		}
+		if position.BatchID != nil {
+			item.BatchNumber = batchNumbers[*position.BatchID]
+		}
+		response.Items = append(response.Items, item)
+		totalValue = totalValue.Add(position.Value)
+	}
+	response.TotalValue = totalValue.StringFixed(2)
+
+	sort.SliceStable(response.Items, func(i, j int) bool {
+		a, b := response.Items[i], response.Items[j]
+		if a.WarehouseName != b.WarehouseName {
+			return a.WarehouseName < b.WarehouseName
+		}
+		if a.ProductCode != b.ProductCode {
+			return a.ProductCode < b.ProductCode
+		}
+		// Stock without a batch last
+		if (a.BatchID == nil) != (b.BatchID == nil) {
+			return b.BatchID == nil
+		}
+		return a.BatchNumber < b.BatchNumber
+	})
+
+	return response, nil
+}
+
+// ListSnapshots returns the stored stock snapshots of a company, newest first
+func (s *StockSnapshotService) ListSnapshots(ctx context.Context, tenantID, companyID string) ([]dto.StockSnapshotResponse, error) {
+	var snapshots []models.StockSnapshot
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("tenant_id = ? AND company_id = ?", tenantID, companyID).
+		Order("snapshot_at DESC").
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stock snapshots: %w", err)
+	}
+
+	response := make([]dto.StockSnapshotResponse, len(snapshots))
+	for i, snapshot := range snapshots {
+		response[i] = MapStockSnapshotToResponse(&snapshot)
+	}
+	return response, nil
+}
+
+// CreateMonthEndSnapshot stores the stock of a company at the end of a completed month (period YYYY-MM)
+func (s *StockSnapshotService) CreateMonthEndSnapshot(ctx context.Context, tenantID, companyID, period string, userID *string) (*models.StockSnapshot, error) {
+	month, err := time.Parse("2006-01", period)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid period format (use YYYY-MM)")
+	}
+	cutoff := month.AddDate(0, 1, 0)
+	if cutoff.After(time.Now()) {
+		return nil, pkgerrors.NewBadRequestError("period must be a completed month")
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.StockSnapshot{}).
+		Where("company_id = ? AND snapshot_at = ?", companyID, cutoff).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check stock snapshot: %w", err)
+	}
+	if count > 0 {
+		return nil, pkgerrors.NewConflictError(fmt.Sprintf("stock snapshot for %s already exists", period))
+	}
+
+	return s.createSnapshot(ctx, tenantID, companyID, cutoff, userID)
+}
+
+// CreateMonthEndSnapshots stores the previous month's snapshot for every active company that lacks one.
+// Safe to run daily: companies that already have the snapshot are skipped. Months end at UTC midnight.
+// A company that fails is logged and retried on the next run; an error is returned only when
+// snapshots were due and none could be created.
+func (s *StockSnapshotService) CreateMonthEndSnapshots(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// System job across all tenants
+	var companies []models.Company
+	if err := s.db.WithContext(ctx).Set("bypass_tenant", true).
+		Where("is_active = ?", true).
+		Where("id NOT IN (?)", s.db.Model(&models.StockSnapshot{}).Select("company_id").Where("snapshot_at = ?", cutoff)).
+		Find(&companies).Error; err != nil {
+		return 0, fmt.Errorf("failed to list companies for stock snapshot: %w", err)
+	}
+
+	created := 0
+	var firstErr error
+	for _, company := range companies {
+		if _, err := s.createSnapshot(ctx, company.TenantID, company.ID, cutoff, nil); err != nil {
+			log.Printf("[ERROR][INVENTORY] Failed to create stock snapshot for company %s: %v", company.ID, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to create stock snapshot for company %s: %w", company.ID, err)
+			}
+			continue
+		}
+		created++
+	}
+
+	if created == 0 && firstErr != nil {
+		return 0, firstErr
+	}
+	return created, nil
+}
+
+// createSnapshot rebuilds the stock at cutoff and stores it with its items in one transaction
+func (s *StockSnapshotService) createSnapshot(ctx context.Context, tenantID, companyID string, cutoff time.Time, userID *string) (*models.StockSnapshot, error) {
+	snapshot := &models.StockSnapshot{
+		TenantID:   tenantID,
+		CompanyID:  companyID,
+		SnapshotAt: cutoff,
+		Period:     cutoff.AddDate(0, 0, -1).Format("2006-01"),
+		CreatedBy:  userID,
+		// Taken before reading movements so back-dated postings made meanwhile are replayed later
+		CreatedAt: time.Now(),
+	}
+
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		positions, _, err := s.positions(tx, tenantID, companyID, cutoff, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		items := make([]models.StockSnapshotItem, len(positions))
+		for i, position := range positions {
+			items[i] = models.StockSnapshotItem{
+				WarehouseID: position.WarehouseID,
+				ProductID:   position.ProductID,
+				BatchID:     position.BatchID,
+				Quantity:    position.Quantity,
+				Value:       position.Value,
+			}
+			snapshot.TotalValue = snapshot.TotalValue.Add(position.Value)
+		}
+		snapshot.ItemCount = len(items)
+
+		if err := tx.Create(snapshot).Error; err != nil {
+			return fmt.Errorf("failed to create stock snapshot: %w", err)
+		}
+		for i := range items {
+			items[i].StockSnapshotID = snapshot.ID
+		}
+		if len(items) > 0 {
+			if err := tx.CreateInBatches(&items, 500).Error; err != nil {
+				return fmt.Errorf("failed to create stock snapshot items: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// positions sums stock before cutoff: the latest snapshot at or before cutoff, plus movements dated
+// between the snapshot and cutoff, plus movements back-dated before the snapshot after it was taken.
+// Positions with zero quantity and zero value are dropped.
+func (s *StockSnapshotService) positions(db *gorm.DB, tenantID, companyID string, cutoff time.Time, warehouseID, productID *string) ([]stockPosition, *models.StockSnapshot, error) {
+	var snapshot *models.StockSnapshot
+	var latest models.StockSnapshot
+	err := db.Session(&gorm.Session{}).
+		Where("tenant_id = ? AND company_id = ? AND snapshot_at <= ?", tenantID, companyID, cutoff).
+		Order("snapshot_at DESC").
+		First(&latest).Error
+	if err == nil {
+		snapshot = &latest
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, nil, fmt.Errorf("failed to find stock snapshot: %w", err)
+	}
+
+	totals := map[string]*stockPosition{}
+	order := []string{}
+	add := func(position stockPosition) {
+		key := position.key()
+		current, ok := totals[key]
+		if !ok {
+			current = &stockPosition{WarehouseID: position.WarehouseID, ProductID: position.ProductID, BatchID: position.BatchID}
+			totals[key] = current
+			order = append(order, key)
+		}
+		current.Quantity = current.Quantity.Add(position.Quantity)
+		current.Value = current.Value.Add(position.Value)
+	}
+
+	if snapshot != nil {
+		itemQuery := db.Session(&gorm.Session{}).Model(&models.StockSnapshotItem{}).
+			Select("warehouse_id, product_id, batch_id, quantity, value").
+			Where("stock_snapshot_id = ?", snapshot.ID)
+		if warehouseID != nil && *warehouseID != "" {
+			itemQuery = itemQuery.Where("warehouse_id = ?", *warehouseID)
+		}
+		if productID != nil && *productID != "" {
+			itemQuery = itemQuery.Where("product_id = ?", *productID)
+		}
+
+		var items []stockPosition
+		if err := itemQuery.Scan(&items).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to load stock snapshot items: %w", err)
+		}
+		for _, item := range items {
+			add(item)
+		}
+	}
+
+	movementQuery := db.Session(&gorm.Session{}).Model(&models.InventoryMovement{}).
+		Select("warehouse_id, product_id, batch_id, COALESCE(SUM(quantity), 0) as quantity, COALESCE(SUM(total_cost), 0) as value").
+		Where("tenant_id = ? AND company_id = ?", tenantID, companyID).
+		Where("movement_date < ?", cutoff)
+	if snapshot != nil {
+		movementQuery = movementQuery.Where("(movement_date >= ? OR created_at >= ?)", snapshot.SnapshotAt, snapshot.CreatedAt)
+	}
+	if warehouseID != nil && *warehouseID != "" {
+		movementQuery = movementQuery.Where("warehouse_id = ?", *warehouseID)
+	}
+	if productID != nil && *productID != "" {
+		movementQuery = movementQuery.Where("product_id = ?", *productID)
+	}
+
+	var movements []stockPosition
+	if err := movementQuery.Group("warehouse_id, product_id, batch_id").Scan(&movements).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to sum inventory movements: %w", err)
+	}
+	for _, movement := range movements {
+		add(movement)
+	}
+
+	positions := make([]stockPosition, 0, len(order))
+	for _, key := range order {
+		position := totals[key]
+		if position.Quantity.IsZero() && position.Value.IsZero() {
+			continue
+		}
+		positions = append(positions, *position)
+	}
+
+	return positions, snapshot, nil
+}
+
+// MapStockSnapshotToResponse converts a stock snapshot to its response DTO
+func MapStockSnapshotToResponse(snapshot *models.StockSnapshot) dto.StockSnapshotResponse {
+	return dto.StockSnapshotResponse{
+		ID:         snapshot.ID,
+		Period:     snapshot.Period,
+		SnapshotAt: snapshot.SnapshotAt.Format(time.RFC3339),
+		ItemCount:  snapshot.ItemCount,
+		TotalValue: snapshot.TotalValue.StringFixed(2),
+		CreatedBy:  snapshot.CreatedBy,
+		CreatedAt:  snapshot.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// parseAsOf turns YYYY-MM-DD into the start of the next day, or parses an RFC3339 timestamp
+func parseAsOf(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	return time.Time{}, pkgerrors.NewBadRequestError("invalid asOf format (use YYYY-MM-DD or RFC3339)")
+}
@@ -0,0 +1,135 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockSnapshotService(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(&models.StockSnapshot{}, &models.StockSnapshotItem{}))
+
+	stock := &models.WarehouseStock{WarehouseID: warehouse.ID, ProductID: product.ID}
+	require.NoError(t, db.Create(stock).Error)
+	batch := &models.ProductBatch{BatchNumber: "B-001", ProductID: product.ID, WarehouseStockID: stock.ID, ReceiptDate: time.Now()}
+	require.NoError(t, db.Create(batch).Error)
+
+	movement := func(date time.Time, qty int64, batchID *string) {
+		require.NoError(t, db.Create(&models.InventoryMovement{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			MovementDate: date,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			BatchID:      batchID,
+			MovementType: models.MovementTypeAdjustment,
+			Quantity:     decimal.NewFromInt(qty),
+			UnitCost:     decimal.NewFromInt(100),
+			TotalCost:    decimal.NewFromInt(qty * 100),
+		}).Error)
+	}
+
+	movement(time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC), 10, &batch.ID)
+	movement(time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC), -3, &batch.ID)
+	movement(time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), 5, nil)
+
+	service := NewStockSnapshotService(db)
+	asOf := func(value string) *dto.StockAsOfResponse {
+		response, err := service.GetStockAsOf(context.Background(), company.TenantID, company.ID, &dto.StockAsOfQuery{AsOf: value})
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("success - rebuilt from full movement history", func(t *testing.T) {
+		response := asOf("2026-01-31")
+
+		assert.Nil(t, response.SnapshotID)
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "B-001", response.Items[0].BatchNumber)
+		assert.Equal(t, "10", response.Items[0].Quantity)
+		assert.Equal(t, "1000.00", response.TotalValue)
+	})
+
+	t.Run("success - month-end snapshot", func(t *testing.T) {
+		snapshot, err := service.CreateMonthEndSnapshot(context.Background(), company.TenantID, company.ID, "2026-01", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2026-01", snapshot.Period)
+		assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), snapshot.SnapshotAt)
+		assert.Equal(t, 1, snapshot.ItemCount)
+		assert.Equal(t, "1000", snapshot.TotalValue.String())
+	})
+
+	t.Run("success - replays movements after the snapshot", func(t *testing.T) {
+		response := asOf("2026-03-15")
+
+		require.NotNil(t, response.SnapshotID)
+		assert.Equal(t, "2026-02-01T00:00:00Z", *response.SnapshotAt)
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, "B-001", response.Items[0].BatchNumber)
+		assert.Equal(t, "7", response.Items[0].Quantity)
+		assert.Nil(t, response.Items[1].BatchID)
+		assert.Equal(t, "5", response.Items[1].Quantity)
+		assert.Equal(t, "1200.00", response.TotalValue)
+	})
+
+	t.Run("success - timestamp excludes movements at or after it", func(t *testing.T) {
+		response := asOf("2026-03-03T09:00:00Z")
+
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "7", response.Items[0].Quantity)
+	})
+
+	t.Run("success - back-dated movement posted after the snapshot is included", func(t *testing.T) {
+		movement(time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC), 2, &batch.ID)
+
+		response := asOf("2026-01-31")
+
+		require.NotNil(t, response.SnapshotID)
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "12", response.Items[0].Quantity)
+	})
+
+	t.Run("error - snapshot already exists", func(t *testing.T) {
+		_, err := service.CreateMonthEndSnapshot(context.Background(), company.TenantID, company.ID, "2026-01", nil)
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 409, appErr.StatusCode)
+	})
+
+	t.Run("error - month not completed", func(t *testing.T) {
+		_, err := service.CreateMonthEndSnapshot(context.Background(), company.TenantID, company.ID, time.Now().Format("2006-01"), nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "period must be a completed month")
+	})
+
+	t.Run("success - scheduled snapshots skip companies that have one", func(t *testing.T) {
+		now := time.Date(2026, 3, 1, 0, 45, 0, 0, time.UTC)
+
+		created, err := service.CreateMonthEndSnapshots(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		created, err = service.CreateMonthEndSnapshots(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, created)
+
+		var snapshot models.StockSnapshot
+		require.NoError(t, db.Where("company_id = ? AND period = ?", company.ID, "2026-02").First(&snapshot).Error)
+		assert.Equal(t, "900", snapshot.TotalValue.String())
+	})
+}
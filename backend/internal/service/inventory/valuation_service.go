@@ -0,0 +1,243 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ValuationService - Inventory valuation reporting built from InventoryMovement history
+type ValuationService struct {
+	db *gorm.DB
+}
+
+// NewValuationService creates a new inventory valuation service instance
+func NewValuationService(db *gorm.DB) *ValuationService {
+	return &ValuationService{
+		db: db,
+	}
+}
+
+// GetInventoryValuation returns quantity and value per product per warehouse at the end of asOf.
+// Quantity and value are summed from movements, so any past date can be reported.
+func (s *ValuationService) GetInventoryValuation(ctx context.Context, tenantID, companyID string, query *dto.InventoryValuationQuery) (*dto.InventoryValuationResponse, error) {
+	asOf := time.Now()
+	if query.AsOf != "" {
+		date, err := time.Parse("2006-01-02", query.AsOf)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid asOf format (use YYYY-MM-DD)")
+		}
+		asOf = date
+	}
+	// Movements before the start of the next day are included
+	cutoff := time.Date(asOf.Year(), asOf.Month(), asOf.Day()+1, 0, 0, 0, 0, asOf.Location())
+
+	type valuationRow struct {
+		WarehouseID   string
+		WarehouseCode string
+		WarehouseName string
+		ProductID     string
+		ProductCode   string
+		ProductName   string
+		BaseUnit      string
+		Quantity      decimal.Decimal
+		Value         decimal.Decimal
+	}
+
+	dbQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Table("inventory_movements").
+		Select(`
+			inventory_movements.warehouse_id as warehouse_id,
+			warehouses.code as warehouse_code,
+			warehouses.name as warehouse_name,
+			inventory_movements.product_id as product_id,
+			products.code as product_code,
+			products.name as product_name,
+			products.base_unit as base_unit,
+			SUM(inventory_movements.quantity) as quantity,
+			SUM(inventory_movements.total_cost) as value
+		`).
+		Joins("JOIN warehouses ON warehouses.id = inventory_movements.warehouse_id").
+		Joins("JOIN products ON products.id = inventory_movements.product_id").
+		Where("inventory_movements.tenant_id = ? AND inventory_movements.company_id = ?", tenantID, companyID).
+		Where("inventory_movements.movement_date < ?", cutoff)
+
+	if query.WarehouseID != nil && *query.WarehouseID != "" {
+		dbQuery = dbQuery.Where("inventory_movements.warehouse_id = ?", *query.WarehouseID)
+	}
+
+	var rows []valuationRow
+	if err := dbQuery.
+		Group("inventory_movements.warehouse_id, warehouses.code, warehouses.name, inventory_movements.product_id, products.code, products.name, products.base_unit").
+		Having("SUM(inventory_movements.quantity) <> 0").
+		Order("warehouses.name ASC, products.code ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query inventory valuation: %w", err)
+	}
+
+	response := &dto.InventoryValuationResponse{
+		AsOf:       asOf.Format("2006-01-02"),
+		Warehouses: []dto.WarehouseValuation{},
+	}
+
+	grandTotal := decimal.Zero
+	index := map[string]int{}
+	totals := map[string]decimal.Decimal{}
+	for _, row := range rows {
+		i, ok := index[row.WarehouseID]
+		if !ok {
+			response.Warehouses = append(response.Warehouses, dto.WarehouseValuation{
+				WarehouseID:   row.WarehouseID,
+				WarehouseCode: row.WarehouseCode,
+				WarehouseName: row.WarehouseName,
+				Items:         []dto.ProductValuation{},
+			})
+			i = len(response.Warehouses) - 1
+			index[row.WarehouseID] = i
+		}
+
+		unitCost := decimal.Zero
+		if !row.Quantity.IsZero() {
+			unitCost = row.Value.Div(row.Quantity).Round(4)
+		}
+
+		response.Warehouses[i].Items = append(response.Warehouses[i].Items, dto.ProductValuation{
+			ProductID:   row.ProductID,
+			ProductCode: row.ProductCode,
+			ProductName: row.ProductName,
+			BaseUnit:    row.BaseUnit,
+			Quantity:    row.Quantity.String(),
+			UnitCost:    unitCost.String(),
+			Value:       row.Value.StringFixed(2),
+		})
+		totals[row.WarehouseID] = totals[row.WarehouseID].Add(row.Value)
+		grandTotal = grandTotal.Add(row.Value)
+	}
+
+	for i := range response.Warehouses {
+		response.Warehouses[i].TotalValue = totals[response.Warehouses[i].WarehouseID].StringFixed(2)
+	}
+	response.TotalValue = grandTotal.StringFixed(2)
+
+	return response, nil
+}
+
+// ListCostLayers returns FIFO cost layers for inspection, oldest first.
+// Totals cover every matching layer, not only the current page.
+func (s *ValuationService) ListCostLayers(ctx context.Context, tenantID, companyID string, query *dto.CostLayerListQuery) (*dto.CostLayerListResponse, error) {
+	page := 1
+	if query.Page > 0 {
+		page = query.Page
+	}
+
+	pageSize := 20
+	if query.PageSize > 0 {
+		pageSize = query.PageSize
+	}
+
+	baseQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.CostLayer{}).
+		Where("company_id = ?", companyID)
+
+	if query.WarehouseID != nil && *query.WarehouseID != "" {
+		baseQuery = baseQuery.Where("warehouse_id = ?", *query.WarehouseID)
+	}
+	if query.ProductID != nil && *query.ProductID != "" {
+		baseQuery = baseQuery.Where("product_id = ?", *query.ProductID)
+	}
+	if !query.IncludeConsumed {
+		baseQuery = baseQuery.Where("remaining_quantity > 0")
+	}
+
+	var totalCount int64
+	if err := baseQuery.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cost layers: %w", err)
+	}
+
+	var totals struct {
+		Quantity decimal.Decimal
+		Value    decimal.Decimal
+	}
+	if err := baseQuery.Session(&gorm.Session{}).
+		Select("COALESCE(SUM(remaining_quantity), 0) as quantity, COALESCE(SUM(remaining_quantity * unit_cost), 0) as value").
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum cost layers: %w", err)
+	}
+
+	var layers []models.CostLayer
+	if err := baseQuery.Session(&gorm.Session{}).
+		Preload("Warehouse").
+		Preload("Product").
+		Order("layer_date ASC, created_at ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&layers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list cost layers: %w", err)
+	}
+
+	data := make([]dto.CostLayerResponse, len(layers))
+	for i, layer := range layers {
+		data[i] = dto.CostLayerResponse{
+			ID:                layer.ID,
+			WarehouseID:       layer.WarehouseID,
+			WarehouseCode:     layer.Warehouse.Code,
+			WarehouseName:     layer.Warehouse.Name,
+			ProductID:         layer.ProductID,
+			ProductCode:       layer.Product.Code,
+			ProductName:       layer.Product.Name,
+			MovementID:        layer.MovementID,
+			LayerDate:         layer.LayerDate.Format(time.RFC3339),
+			OriginalQuantity:  layer.OriginalQuantity.String(),
+			RemainingQuantity: layer.RemainingQuantity.String(),
+			UnitCost:          layer.UnitCost.String(),
+			RemainingValue:    layer.RemainingQuantity.Mul(layer.UnitCost).StringFixed(2),
+			ReferenceType:     layer.ReferenceType,
+			ReferenceNumber:   layer.ReferenceNumber,
+		}
+	}
+
+	return &dto.CostLayerListResponse{
+		Success:           true,
+		Data:              data,
+		RemainingQuantity: totals.Quantity.String(),
+		RemainingValue:    totals.Value.StringFixed(2),
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      pageSize,
+			Total:      int(totalCount),
+			TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+		},
+	}, nil
+}
+
+// OutboundUnitCost returns the average unit cost consumed by the outbound movements of a document
+// for one product in one warehouse. Used to bring stock back (or onward) at the cost it left with.
+// Returns nil when the document has no outbound movement for the product.
+func (s *StockPostingService) OutboundUnitCost(tx *gorm.DB, referenceType, referenceID, warehouseID, productID string) (*decimal.Decimal, error) {
+	var result struct {
+		Quantity  decimal.Decimal
+		TotalCost decimal.Decimal
+	}
+
+	if err := tx.Model(&models.InventoryMovement{}).
+		Select("COALESCE(SUM(quantity), 0) as quantity, COALESCE(SUM(total_cost), 0) as total_cost").
+		Where("reference_type = ? AND reference_id = ? AND warehouse_id = ? AND product_id = ? AND quantity < 0",
+			referenceType, referenceID, warehouseID, productID).
+		Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("failed to get outbound movement cost: %w", err)
+	}
+
+	if result.Quantity.IsZero() {
+		return nil, nil
+	}
+
+	unitCost := result.TotalCost.Div(result.Quantity).Round(4)
+	return &unitCost, nil
+}
@@ -0,0 +1,75 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuationService_GetInventoryValuation(t *testing.T) {
+	db, company, warehouse, product := setupPostingTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	postingService := NewStockPostingService(db)
+	service := NewValuationService(db)
+	ctx := context.Background()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	cost := decimal.NewFromInt(2500)
+
+	_, err := postingService.Post(db, &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeIn,
+		Quantity:     decimal.NewFromInt(8),
+		UnitCost:     &cost,
+		MovementDate: yesterday,
+	})
+	require.NoError(t, err)
+
+	_, err = postingService.Post(db, &StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  warehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeOut,
+		Quantity:     decimal.NewFromInt(-2),
+	})
+	require.NoError(t, err)
+
+	t.Run("success - current valuation", func(t *testing.T) {
+		result, err := service.GetInventoryValuation(ctx, company.TenantID, company.ID, &dto.InventoryValuationQuery{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Warehouses, 1)
+		require.Len(t, result.Warehouses[0].Items, 1)
+		assert.Equal(t, "6", result.Warehouses[0].Items[0].Quantity)
+		assert.Equal(t, "15000.00", result.TotalValue)
+	})
+
+	t.Run("success - valuation as of yesterday", func(t *testing.T) {
+		result, err := service.GetInventoryValuation(ctx, company.TenantID, company.ID, &dto.InventoryValuationQuery{
+			AsOf: yesterday.Format("2006-01-02"),
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Warehouses, 1)
+		assert.Equal(t, "8", result.Warehouses[0].Items[0].Quantity)
+		assert.Equal(t, "20000.00", result.TotalValue)
+	})
+
+	t.Run("error - invalid date", func(t *testing.T) {
+		_, err := service.GetInventoryValuation(ctx, company.TenantID, company.ID, &dto.InventoryValuationQuery{AsOf: "31-12-2024"})
+		assert.Error(t, err)
+	})
+}
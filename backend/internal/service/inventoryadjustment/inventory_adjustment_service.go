@@ -12,21 +12,24 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/audit"
+	"backend/internal/service/inventory"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 // InventoryAdjustmentService handles business logic for inventory adjustments
 type InventoryAdjustmentService struct {
-	db           *gorm.DB
-	auditService *audit.AuditService
+	db                  *gorm.DB
+	auditService        *audit.AuditService
+	stockPostingService *inventory.StockPostingService
 }
 
 // NewInventoryAdjustmentService creates a new inventory adjustment service instance
-func NewInventoryAdjustmentService(db *gorm.DB, auditService *audit.AuditService) *InventoryAdjustmentService {
+func NewInventoryAdjustmentService(db *gorm.DB, auditService *audit.AuditService, stockPostingService *inventory.StockPostingService) *InventoryAdjustmentService {
 	return &InventoryAdjustmentService{
-		db:           db,
-		auditService: auditService,
+		db:                  db,
+		auditService:        auditService,
+		stockPostingService: stockPostingService,
 	}
 }
 
@@ -787,28 +790,8 @@ func (s *InventoryAdjustmentService) ApproveInventoryAdjustment(
 		return nil, pkgerrors.NewBadRequestError("Cannot approve adjustment with no items")
 	}
 
-	// Validate stock for DECREASE adjustments
-	if adjustment.AdjustmentType == models.InventoryAdjustmentTypeDecrease {
-		for _, item := range items {
-			var stock models.WarehouseStock
-			err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
-				Where("warehouse_id = ? AND product_id = ?", adjustment.WarehouseID, item.ProductID).
-				First(&stock).Error
-
-			if err == gorm.ErrRecordNotFound {
-				return nil, pkgerrors.NewBadRequestError("Product not found in warehouse")
-			}
-			if err != nil {
-				return nil, pkgerrors.NewInternalError(err)
-			}
-
-			// QuantityAdjusted is negative for DECREASE, so use Abs
-			if stock.Quantity.LessThan(item.QuantityAdjusted.Abs()) {
-				return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient stock. Available: %s, Required: %s",
-					stock.Quantity.String(), item.QuantityAdjusted.Abs().String()))
-			}
-		}
-	}
+	// Stock for DECREASE adjustments is checked when posting, under the stock row lock
+	// and following the company's negative stock policy
 
 	tx := s.db.WithContext(ctx).Set("tenant_id", tenantID).Begin()
 
@@ -825,50 +808,54 @@ func (s *InventoryAdjustmentService) ApproveInventoryAdjustment(
 		updateFields["notes"] = *req.Notes
 	}
 
-	// Use fresh model to prevent GORM from saving preloaded associations
-	if err := tx.Model(&models.InventoryAdjustment{}).Where("id = ?", adjustment.ID).Updates(updateFields).Error; err != nil {
+	// Use fresh model to prevent GORM from saving preloaded associations.
+	// Matching on status makes a concurrent approve/cancel of the same adjustment lose.
+	result := tx.Model(&models.InventoryAdjustment{}).
+		Where("id = ? AND status = ?", adjustment.ID, models.InventoryAdjustmentStatusDraft).
+		Updates(updateFields)
+	if result.Error != nil {
 		tx.Rollback()
-		return nil, pkgerrors.NewInternalError(err)
+		return nil, pkgerrors.NewInternalError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, pkgerrors.NewConflictError("Inventory adjustment was changed by another request, please retry")
 	}
 
 	// Apply stock changes
+	// QuantityAdjusted is positive for INCREASE, negative for DECREASE
 	for _, item := range items {
-		var stock models.WarehouseStock
+		if item.QuantityAdjusted.IsZero() {
+			continue
+		}
 
-		err := tx.Where("warehouse_id = ? AND product_id = ?", adjustment.WarehouseID, item.ProductID).
-			First(&stock).Error
+		// Increases are valued at the entered unit cost; decreases consume the average cost
+		var unitCost *decimal.Decimal
+		if item.UnitCost.IsPositive() {
+			unitCost = &item.UnitCost
+		}
 
-		if err == gorm.ErrRecordNotFound {
-			// Create new stock record for INCREASE
-			if adjustment.AdjustmentType == models.InventoryAdjustmentTypeIncrease {
-				stock = models.WarehouseStock{
-					ID:          uuid.New().String(),
-					WarehouseID: adjustment.WarehouseID,
-					ProductID:   item.ProductID,
-					Quantity:    item.QuantityAdjusted,
-					CreatedAt:   now,
-					UpdatedAt:   now,
-				}
-				if err := tx.Create(&stock).Error; err != nil {
-					tx.Rollback()
-					return nil, pkgerrors.NewInternalError(err)
-				}
-			} else {
-				// Should not happen due to validation above
-				tx.Rollback()
-				return nil, pkgerrors.NewBadRequestError("Product not found in warehouse")
-			}
-		} else if err != nil {
+		if _, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:        tenantID,
+			CompanyID:       companyID,
+			WarehouseID:     adjustment.WarehouseID,
+			ProductID:       item.ProductID,
+			BatchID:         item.BatchID,
+			MovementType:    models.MovementTypeAdjustment,
+			Quantity:        item.QuantityAdjusted,
+			UnitCost:        unitCost,
+			MovementDate:    now,
+			ReferenceType:   inventory.ReferenceTypeInventoryAdjustment,
+			ReferenceID:     adjustment.ID,
+			ReferenceNumber: adjustment.AdjustmentNumber,
+			Notes:           item.Notes,
+			CreatedBy:       userID,
+		}); err != nil {
 			tx.Rollback()
-			return nil, pkgerrors.NewInternalError(err)
-		} else {
-			// Update existing stock
-			// QuantityAdjusted is positive for INCREASE, negative for DECREASE
-			newQuantity := stock.Quantity.Add(item.QuantityAdjusted)
-			if err := tx.Model(&stock).Update("quantity", newQuantity).Error; err != nil {
-				tx.Rollback()
-				return nil, pkgerrors.NewInternalError(err)
+			if appErr, ok := err.(*pkgerrors.AppError); ok {
+				return nil, appErr
 			}
+			return nil, pkgerrors.NewInternalError(err)
 		}
 	}
 
@@ -944,10 +931,18 @@ func (s *InventoryAdjustmentService) CancelInventoryAdjustment(
 		"updated_at":    now,
 	}
 
-	// Use fresh model to prevent GORM from saving preloaded associations
-	if err := tx.Model(&models.InventoryAdjustment{}).Where("id = ?", adjustment.ID).Updates(updateFields).Error; err != nil {
+	// Use fresh model to prevent GORM from saving preloaded associations.
+	// Matching on status makes a concurrent approve/cancel of the same adjustment lose.
+	result := tx.Model(&models.InventoryAdjustment{}).
+		Where("id = ? AND status = ?", adjustment.ID, models.InventoryAdjustmentStatusDraft).
+		Updates(updateFields)
+	if result.Error != nil {
 		tx.Rollback()
-		return nil, pkgerrors.NewInternalError(err)
+		return nil, pkgerrors.NewInternalError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, pkgerrors.NewConflictError("Inventory adjustment was changed by another request, please retry")
 	}
 
 	if err := tx.Commit().Error; err != nil {
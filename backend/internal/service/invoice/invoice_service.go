@@ -3,7 +3,9 @@ package invoice
 import (
 	"backend/internal/dto"
 	"backend/internal/service/document"
+	"backend/internal/service/uom"
 	"backend/models"
+	pkgerrors "backend/pkg/errors"
 	"context"
 	"errors"
 	"fmt"
@@ -17,6 +19,7 @@ import (
 type InvoiceService struct {
 	db            *gorm.DB
 	docNumberGen  *document.DocumentNumberGenerator
+	uomService    *uom.UOMService
 }
 
 // NewInvoiceService creates a new invoice service
@@ -24,6 +27,7 @@ func NewInvoiceService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerat
 	return &InvoiceService{
 		db:           db,
 		docNumberGen: docNumberGen,
+		uomService:   uom.NewUOMService(db),
 	}
 }
 
@@ -283,13 +287,21 @@ func (s *InvoiceService) CreateInvoice(companyID, tenantID string, req dto.Creat
 
 		itemSubtotal := qty.Mul(unitPrice).Sub(discountAmt)
 
+		// Validate the entered unit and keep the base-unit quantity alongside
+		unit, baseQty, err := s.uomService.ConvertQuantity(tx, itemReq.ProductID, itemReq.ProductUnitID, qty)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
 		item := models.InvoiceItem{
 			InvoiceID:        invoice.ID,
 			SalesOrderItemID: itemReq.SalesOrderItemID,
 			DeliveryItemID:   itemReq.DeliveryItemID,
 			ProductID:        itemReq.ProductID,
-			ProductUnitID:    itemReq.ProductUnitID,
+			ProductUnitID:    unit.ProductUnitID,
 			Quantity:         qty,
+			BaseQuantity:     baseQty,
 			UnitPrice:        unitPrice,
 			DiscountPct:      discountPct,
 			DiscountAmt:      discountAmt,
@@ -334,6 +346,17 @@ func (s *InvoiceService) UpdateInvoice(tenantID, companyID, invoiceID string, re
 		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
 	}
 
+	// Amounts of an invoice with credit or debit notes are changed through further notes
+	if req.CustomerID != nil || req.DiscountAmount != nil || req.TaxAmount != nil {
+		hasNotes, err := s.hasSalesNotes(tenantID, invoice.ID)
+		if err != nil {
+			return nil, err
+		}
+		if hasNotes {
+			return nil, pkgerrors.NewBadRequestError("invoice has credit or debit notes; issue a credit or debit note to change its amounts")
+		}
+	}
+
 	// Update fields
 	if req.InvoiceDate != nil {
 		invoiceDate, err := time.Parse("2006-01-02", *req.InvoiceDate)
@@ -410,8 +433,30 @@ func (s *InvoiceService) UpdateInvoice(tenantID, companyID, invoiceID string, re
 	return &response, nil
 }
 
+// hasSalesNotes reports whether credit or debit notes were issued against the invoice
+func (s *InvoiceService) hasSalesNotes(tenantID, invoiceID string) (bool, error) {
+	var creditNotes, debitNotes int64
+	if err := s.db.Set("tenant_id", tenantID).Model(&models.SalesCreditNote{}).
+		Where("invoice_id = ?", invoiceID).Count(&creditNotes).Error; err != nil {
+		return false, fmt.Errorf("failed to check credit notes: %w", err)
+	}
+	if err := s.db.Set("tenant_id", tenantID).Model(&models.SalesDebitNote{}).
+		Where("invoice_id = ?", invoiceID).Count(&debitNotes).Error; err != nil {
+		return false, fmt.Errorf("failed to check debit notes: %w", err)
+	}
+	return creditNotes+debitNotes > 0, nil
+}
+
 // DeleteInvoice soft deletes an invoice
 func (s *InvoiceService) DeleteInvoice(tenantID, companyID, invoiceID string) error {
+	hasNotes, err := s.hasSalesNotes(tenantID, invoiceID)
+	if err != nil {
+		return err
+	}
+	if hasNotes {
+		return pkgerrors.NewBadRequestError("cannot delete an invoice with credit or debit notes")
+	}
+
 	result := s.db.Set("tenant_id", tenantID).
 		Where("id = ? AND company_id = ?", invoiceID, companyID).
 		Delete(&models.Invoice{})
@@ -457,7 +502,7 @@ func (s *InvoiceService) RecordPayment(tenantID, companyID, invoiceID string, re
 		return nil, fmt.Errorf("payment amount must be greater than zero")
 	}
 
-	remainingAmount := invoice.TotalAmount.Sub(invoice.PaidAmount)
+	remainingAmount := invoice.RemainingAmount()
 	if amount.GreaterThan(remainingAmount) {
 		return nil, fmt.Errorf("payment amount exceeds remaining balance")
 	}
@@ -522,16 +567,12 @@ func (s *InvoiceService) RecordPayment(tenantID, companyID, invoiceID string, re
 	}
 
 	// Update invoice paid amount and payment status
-	newPaidAmount := invoice.PaidAmount.Add(amount)
-	newPaymentStatus := models.PaymentStatusPartial
-
-	if newPaidAmount.GreaterThanOrEqual(invoice.TotalAmount) {
-		newPaymentStatus = models.PaymentStatusPaid
-	}
+	invoice.PaidAmount = invoice.PaidAmount.Add(amount)
+	invoice.UpdatePaymentStatus()
 
 	if err := tx.Set("tenant_id", tenantID).Model(&invoice).Updates(map[string]interface{}{
-		"paid_amount":    newPaidAmount,
-		"payment_status": newPaymentStatus,
+		"paid_amount":    invoice.PaidAmount,
+		"payment_status": invoice.PaymentStatus,
 	}).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to update invoice: %w", err)
@@ -570,7 +611,9 @@ func (s *InvoiceService) toInvoiceResponse(invoice models.Invoice) dto.InvoiceRe
 		TaxAmount:       invoice.TaxAmount.String(),
 		TotalAmount:     invoice.TotalAmount.String(),
 		PaidAmount:      invoice.PaidAmount.String(),
-		RemainingAmount: invoice.TotalAmount.Sub(invoice.PaidAmount).String(),
+		CreditedAmount:  invoice.CreditedAmount.String(),
+		DebitedAmount:   invoice.DebitedAmount.String(),
+		RemainingAmount: invoice.RemainingAmount().String(),
 		PaymentStatus:   string(invoice.PaymentStatus),
 		Notes:           invoice.Notes,
 		FakturPajakNo:   invoice.FakturPajakNo,
@@ -613,6 +656,7 @@ func (s *InvoiceService) toInvoiceResponseWithDetails(invoice models.Invoice) dt
 				ProductUnitID:    item.ProductUnitID,
 				UnitName:         unitName,
 				Quantity:         item.Quantity.String(),
+				BaseQuantity:     item.BaseQuantity.String(),
 				UnitPrice:        item.UnitPrice.String(),
 				DiscountPct:      item.DiscountPct.String(),
 				DiscountAmt:      item.DiscountAmt.String(),
@@ -231,7 +231,7 @@ func (s *PaymentService) CreatePayment(ctx context.Context, companyID, tenantID,
 	}
 
 	// Verify payment doesn't exceed remaining balance
-	remaining := invoice.TotalAmount.Sub(invoice.PaidAmount)
+	remaining := invoice.RemainingAmount()
 	if amount.GreaterThan(remaining) {
 		tx.Rollback()
 		return nil, fmt.Errorf("payment amount (%s) exceeds remaining invoice balance (%s)", amount.String(), remaining.String())
@@ -290,16 +290,12 @@ func (s *PaymentService) CreatePayment(ctx context.Context, companyID, tenantID,
 	}
 
 	// Update invoice paid amount and status
-	newPaidAmount := invoice.PaidAmount.Add(amount)
-	newPaymentStatus := models.PaymentStatusPartial
-
-	if newPaidAmount.GreaterThanOrEqual(invoice.TotalAmount) {
-		newPaymentStatus = models.PaymentStatusPaid
-	}
+	invoice.PaidAmount = invoice.PaidAmount.Add(amount)
+	invoice.UpdatePaymentStatus()
 
 	if err := tx.Model(&invoice).Updates(map[string]interface{}{
-		"paid_amount":    newPaidAmount,
-		"payment_status": newPaymentStatus,
+		"paid_amount":    invoice.PaidAmount,
+		"payment_status": invoice.PaymentStatus,
 	}).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to update invoice: %w", err)
@@ -394,25 +390,19 @@ func (s *PaymentService) UpdatePayment(ctx context.Context, companyID, tenantID,
 		newAmount, _ := decimal.NewFromString(*req.Amount)
 		amountDiff := newAmount.Sub(oldAmount)
 
-		// Update invoice paid amount
-		newPaidAmount := payment.Invoice.PaidAmount.Add(amountDiff)
-
-		// Verify new paid amount doesn't exceed total
-		if newPaidAmount.GreaterThan(payment.Invoice.TotalAmount) {
+		// Verify new paid amount doesn't exceed what is still open (credit notes included)
+		if amountDiff.GreaterThan(payment.Invoice.RemainingAmount()) {
 			tx.Rollback()
 			return nil, errors.New("new payment amount would exceed invoice total")
 		}
 
-		newPaymentStatus := models.PaymentStatusPartial
-		if newPaidAmount.GreaterThanOrEqual(payment.Invoice.TotalAmount) {
-			newPaymentStatus = models.PaymentStatusPaid
-		} else if newPaidAmount.LessThanOrEqual(decimal.Zero) {
-			newPaymentStatus = models.PaymentStatusUnpaid
-		}
+		// Update invoice paid amount
+		payment.Invoice.PaidAmount = payment.Invoice.PaidAmount.Add(amountDiff)
+		payment.Invoice.UpdatePaymentStatus()
 
 		if err := tx.Model(&payment.Invoice).Updates(map[string]interface{}{
-			"paid_amount":    newPaidAmount,
-			"payment_status": newPaymentStatus,
+			"paid_amount":    payment.Invoice.PaidAmount,
+			"payment_status": payment.Invoice.PaymentStatus,
 		}).Error; err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to update invoice: %w", err)
@@ -460,16 +450,12 @@ func (s *PaymentService) VoidPayment(ctx context.Context, companyID, tenantID, p
 	}
 
 	// Update invoice paid amount and status
-	newPaidAmount := payment.Invoice.PaidAmount.Sub(payment.Amount)
-
-	newPaymentStatus := models.PaymentStatusPartial
-	if newPaidAmount.LessThanOrEqual(decimal.Zero) {
-		newPaymentStatus = models.PaymentStatusUnpaid
-	}
+	payment.Invoice.PaidAmount = payment.Invoice.PaidAmount.Sub(payment.Amount)
+	payment.Invoice.UpdatePaymentStatus()
 
 	if err := tx.Model(&payment.Invoice).Updates(map[string]interface{}{
-		"paid_amount":    newPaidAmount,
-		"payment_status": newPaymentStatus,
+		"paid_amount":    payment.Invoice.PaidAmount,
+		"payment_status": payment.Invoice.PaymentStatus,
 	}).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update invoice: %w", err)
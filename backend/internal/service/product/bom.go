@@ -0,0 +1,134 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/uom"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// GetProductBOM returns the bill of materials of a product (empty when it is not assembled)
+func (s *ProductService) GetProductBOM(ctx context.Context, companyID, tenantID, productID string) ([]models.ProductBOMComponent, error) {
+	if _, err := s.GetProduct(ctx, companyID, tenantID, productID); err != nil {
+		return nil, err
+	}
+
+	var components []models.ProductBOMComponent
+	if err := s.db.WithContext(ctx).
+		Preload("ComponentProduct").
+		Preload("ProductUnit").
+		Where("product_id = ?", productID).
+		Order("created_at ASC").
+		Find(&components).Error; err != nil {
+		return nil, fmt.Errorf("failed to load bill of materials: %w", err)
+	}
+
+	return components, nil
+}
+
+// SetProductBOM replaces the bill of materials of a product.
+// Components must be other products of the company; a component may not (indirectly)
+// contain the product itself.
+func (s *ProductService) SetProductBOM(ctx context.Context, companyID, tenantID, productID string, req *dto.SetProductBOMRequest) ([]models.ProductBOMComponent, error) {
+	product, err := s.GetProduct(ctx, companyID, tenantID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	uomService := uom.NewUOMService(s.db)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductBOMComponent{}).Error; err != nil {
+			return fmt.Errorf("failed to clear bill of materials: %w", err)
+		}
+
+		seen := make(map[string]bool, len(req.Components))
+		for _, componentReq := range req.Components {
+			if componentReq.ComponentProductID == product.ID {
+				return pkgerrors.NewBadRequestError("a product cannot be a component of itself")
+			}
+			if seen[componentReq.ComponentProductID] {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("component %s is listed more than once", componentReq.ComponentProductID))
+			}
+			seen[componentReq.ComponentProductID] = true
+
+			quantity, err := decimal.NewFromString(componentReq.Quantity)
+			if err != nil || !quantity.IsPositive() {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("invalid quantity for component %s", componentReq.ComponentProductID))
+			}
+
+			var component models.Product
+			if err := tx.Where("id = ? AND company_id = ?", componentReq.ComponentProductID, companyID).First(&component).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return pkgerrors.NewNotFoundError(fmt.Sprintf("component product %s not found", componentReq.ComponentProductID))
+				}
+				return fmt.Errorf("failed to load component product: %w", err)
+			}
+			if !component.IsActive {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("component product %s is inactive", component.Code))
+			}
+
+			contains, err := bomContains(tx, component.ID, product.ID, map[string]bool{})
+			if err != nil {
+				return err
+			}
+			if contains {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("component %s is itself assembled from %s", component.Code, product.Code))
+			}
+
+			unit, baseQty, err := uomService.ConvertQuantity(tx, component.ID, componentReq.ProductUnitID, quantity)
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Create(&models.ProductBOMComponent{
+				ProductID:          product.ID,
+				ComponentProductID: component.ID,
+				ProductUnitID:      unit.ProductUnitID,
+				Quantity:           quantity,
+				BaseQuantity:       baseQty,
+				Notes:              componentReq.Notes,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to create bill of materials component: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProductBOM(ctx, companyID, tenantID, productID)
+}
+
+// bomContains reports whether productID appears anywhere in the bill of materials of rootID
+func bomContains(tx *gorm.DB, rootID, productID string, visited map[string]bool) (bool, error) {
+	if visited[rootID] {
+		return false, nil
+	}
+	visited[rootID] = true
+
+	var componentIDs []string
+	if err := tx.Model(&models.ProductBOMComponent{}).
+		Where("product_id = ?", rootID).
+		Pluck("component_product_id", &componentIDs).Error; err != nil {
+		return false, fmt.Errorf("failed to load bill of materials: %w", err)
+	}
+
+	for _, componentID := range componentIDs {
+		if componentID == productID {
+			return true, nil
+		}
+		contains, err := bomContains(tx, componentID, productID, visited)
+		if err != nil || contains {
+			return contains, err
+		}
+	}
+	return false, nil
+}
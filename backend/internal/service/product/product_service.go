@@ -64,21 +64,23 @@ func (s *ProductService) CreateProduct(ctx context.Context, companyID string, te
 	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
 		// 1. Create product
 		product = &models.Product{
-			CompanyID:      companyID,
-			TenantID:       tenantID,
-			Code:           req.Code,
-			Name:           req.Name,
-			Category:       req.Category,
-			BaseUnit:       req.BaseUnit,
-			BaseCost:       baseCost,
-			BasePrice:      basePrice,
-			CurrentStock:   decimal.Zero, // DEPRECATED: Never update this field
-			MinimumStock:   minimumStock,
-			Description:    req.Description,
-			Barcode:        req.Barcode,
-			IsBatchTracked: req.IsBatchTracked,
-			IsPerishable:   req.IsPerishable,
-			IsActive:       true,
+			CompanyID:       companyID,
+			TenantID:        tenantID,
+			Code:            req.Code,
+			Name:            req.Name,
+			Category:        req.Category,
+			BaseUnit:        req.BaseUnit,
+			BaseCost:        baseCost,
+			BasePrice:       basePrice,
+			CurrentStock:    decimal.Zero, // DEPRECATED: Never update this field
+			MinimumStock:    minimumStock,
+			Description:     req.Description,
+			Barcode:         req.Barcode,
+			IsBatchTracked:  req.IsBatchTracked,
+			IsSerialTracked: req.IsSerialTracked,
+			HoldOnReceipt:   req.HoldOnReceipt,
+			IsPerishable:    req.IsPerishable,
+			IsActive:        true,
 		}
 
 		if err := tx.Create(product).Error; err != nil {
@@ -207,17 +209,19 @@ func (s *ProductService) CreateProduct(ctx context.Context, companyID string, te
 	}
 
 	productData := map[string]interface{}{
-		"code":             product.Code,
-		"name":             product.Name,
-		"description":      stringPtrToValue(product.Description),
-		"barcode":          stringPtrToValue(product.Barcode),
-		"category":         stringPtrToValue(product.Category),
-		"base_unit":        product.BaseUnit,
-		"base_cost":        product.BaseCost.String(),
-		"base_price":       product.BasePrice.String(),
-		"minimum_stock":    product.MinimumStock.String(),
-		"is_batch_tracked": product.IsBatchTracked,
-		"is_perishable":    product.IsPerishable,
+		"code":              product.Code,
+		"name":              product.Name,
+		"description":       stringPtrToValue(product.Description),
+		"barcode":           stringPtrToValue(product.Barcode),
+		"category":          stringPtrToValue(product.Category),
+		"base_unit":         product.BaseUnit,
+		"base_cost":         product.BaseCost.String(),
+		"base_price":        product.BasePrice.String(),
+		"minimum_stock":     product.MinimumStock.String(),
+		"is_batch_tracked":  product.IsBatchTracked,
+		"is_serial_tracked": product.IsSerialTracked,
+		"hold_on_receipt":   product.HoldOnReceipt,
+		"is_perishable":     product.IsPerishable,
 	}
 
 	if err := s.auditService.LogProductCreated(ctx, auditCtx, product.ID, productData); err != nil {
@@ -297,6 +301,10 @@ func (s *ProductService) ListProducts(ctx context.Context, tenantID, companyID s
 		query = query.Where("is_batch_tracked = ?", *filters.IsBatchTracked)
 	}
 
+	if filters.IsSerialTracked != nil {
+		query = query.Where("is_serial_tracked = ?", *filters.IsSerialTracked)
+	}
+
 	if filters.IsPerishable != nil {
 		query = query.Where("is_perishable = ?", *filters.IsPerishable)
 	}
@@ -399,6 +407,14 @@ func (s *ProductService) UpdateProduct(ctx context.Context, companyID, tenantID,
 		updates["is_batch_tracked"] = *req.IsBatchTracked
 	}
 
+	if req.IsSerialTracked != nil {
+		updates["is_serial_tracked"] = *req.IsSerialTracked
+	}
+
+	if req.HoldOnReceipt != nil {
+		updates["hold_on_receipt"] = *req.HoldOnReceipt
+	}
+
 	if req.IsPerishable != nil {
 		updates["is_perishable"] = *req.IsPerishable
 	}
@@ -439,20 +455,22 @@ func (s *ProductService) UpdateProduct(ctx context.Context, companyID, tenantID,
 	}
 
 	oldValues := map[string]interface{}{
-		"code":             product.Code,
-		"name":             product.Name,
-		"description":      stringPtrToValue(product.Description),
-		"barcode":          stringPtrToValue(product.Barcode),
-		"category":         stringPtrToValue(product.Category),
-		"base_unit":        product.BaseUnit,
-		"base_cost":        product.BaseCost.String(),
-		"base_price":       product.BasePrice.String(),
-		"minimum_stock":    product.MinimumStock.String(),
-		"is_batch_tracked": product.IsBatchTracked,
-		"is_perishable":    product.IsPerishable,
-		"is_active":        product.IsActive,
-		"suppliers":        oldSuppliers,
-		"units":            oldUnits,
+		"code":              product.Code,
+		"name":              product.Name,
+		"description":       stringPtrToValue(product.Description),
+		"barcode":           stringPtrToValue(product.Barcode),
+		"category":          stringPtrToValue(product.Category),
+		"base_unit":         product.BaseUnit,
+		"base_cost":         product.BaseCost.String(),
+		"base_price":        product.BasePrice.String(),
+		"minimum_stock":     product.MinimumStock.String(),
+		"is_batch_tracked":  product.IsBatchTracked,
+		"is_serial_tracked": product.IsSerialTracked,
+		"hold_on_receipt":   product.HoldOnReceipt,
+		"is_perishable":     product.IsPerishable,
+		"is_active":         product.IsActive,
+		"suppliers":         oldSuppliers,
+		"units":             oldUnits,
 	}
 
 	// Update product directly without preloaded associations
@@ -775,20 +793,22 @@ func (s *ProductService) UpdateProduct(ctx context.Context, companyID, tenantID,
 	}
 
 	newValues := map[string]interface{}{
-		"code":             updatedProduct.Code,
-		"name":             updatedProduct.Name,
-		"description":      stringPtrToValue(updatedProduct.Description),
-		"barcode":          stringPtrToValue(updatedProduct.Barcode),
-		"category":         stringPtrToValue(updatedProduct.Category),
-		"base_unit":        updatedProduct.BaseUnit,
-		"base_cost":        updatedProduct.BaseCost.String(),
-		"base_price":       updatedProduct.BasePrice.String(),
-		"minimum_stock":    updatedProduct.MinimumStock.String(),
-		"is_batch_tracked": updatedProduct.IsBatchTracked,
-		"is_perishable":    updatedProduct.IsPerishable,
-		"is_active":        updatedProduct.IsActive,
-		"suppliers":        newSuppliers,
-		"units":            newUnits,
+		"code":              updatedProduct.Code,
+		"name":              updatedProduct.Name,
+		"description":       stringPtrToValue(updatedProduct.Description),
+		"barcode":           stringPtrToValue(updatedProduct.Barcode),
+		"category":          stringPtrToValue(updatedProduct.Category),
+		"base_unit":         updatedProduct.BaseUnit,
+		"base_cost":         updatedProduct.BaseCost.String(),
+		"base_price":        updatedProduct.BasePrice.String(),
+		"minimum_stock":     updatedProduct.MinimumStock.String(),
+		"is_batch_tracked":  updatedProduct.IsBatchTracked,
+		"is_serial_tracked": updatedProduct.IsSerialTracked,
+		"hold_on_receipt":   updatedProduct.HoldOnReceipt,
+		"is_perishable":     updatedProduct.IsPerishable,
+		"is_active":         updatedProduct.IsActive,
+		"suppliers":         newSuppliers,
+		"units":             newUnits,
 	}
 
 	// 🔍 DEBUG: Check if auditService is nil
@@ -824,14 +844,15 @@ func (s *ProductService) DeleteProduct(ctx context.Context, companyID, productID
 
 	// Capture product data for audit
 	productData := map[string]interface{}{
-		"code":             product.Code,
-		"name":             product.Name,
-		"category":         product.Category,
-		"base_unit":        product.BaseUnit,
-		"base_cost":        product.BaseCost.String(),
-		"base_price":       product.BasePrice.String(),
-		"is_batch_tracked": product.IsBatchTracked,
-		"is_perishable":    product.IsPerishable,
+		"code":              product.Code,
+		"name":              product.Name,
+		"category":          product.Category,
+		"base_unit":         product.BaseUnit,
+		"base_cost":         product.BaseCost.String(),
+		"base_price":        product.BasePrice.String(),
+		"is_batch_tracked":  product.IsBatchTracked,
+		"is_serial_tracked": product.IsSerialTracked,
+		"is_perishable":     product.IsPerishable,
 	}
 
 	// Soft delete
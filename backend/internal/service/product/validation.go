@@ -154,6 +154,21 @@ func (s *ProductService) validateUpdateProduct(ctx context.Context, companyID, t
 		}
 	}
 
+	// 5. Serial tracking can only change while the product has no stock:
+	// existing units have no serials, and serials of stocked units would be orphaned
+	if req.IsSerialTracked != nil && *req.IsSerialTracked != product.IsSerialTracked {
+		var totalStock decimal.Decimal
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.WarehouseStock{}).
+			Where("product_id = ?", productID).
+			Select("COALESCE(SUM(quantity), 0)").
+			Scan(&totalStock).Error; err != nil {
+			return fmt.Errorf("failed to check product stock: %w", err)
+		}
+		if !totalStock.IsZero() {
+			return pkgerrors.NewBadRequestError("serial tracking can only be changed while the product has no stock")
+		}
+	}
+
 	return nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"backend/internal/dto"
 	"backend/internal/service/audit"
 	"backend/internal/service/document"
+	"backend/internal/service/uom"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
@@ -23,6 +24,7 @@ type PurchaseOrderService struct {
 	db           *gorm.DB
 	docNumberGen *document.DocumentNumberGenerator
 	auditService *audit.AuditService
+	uomService   *uom.UOMService
 }
 
 // NewPurchaseOrderService creates a new purchase order service instance
@@ -31,6 +33,7 @@ func NewPurchaseOrderService(db *gorm.DB, docNumberGen *document.DocumentNumberG
 		db:           db,
 		docNumberGen: docNumberGen,
 		auditService: auditService,
+		uomService:   uom.NewUOMService(db),
 	}
 }
 
@@ -296,17 +299,6 @@ func (s *PurchaseOrderService) createPurchaseOrderItem(ctx context.Context, tx *
 		return nil, decimal.Zero, fmt.Errorf("failed to validate product: %w", err)
 	}
 
-	// Validate product unit if provided
-	if req.ProductUnitID != nil && *req.ProductUnitID != "" {
-		var productUnit models.ProductUnit
-		if err := tx.Where("id = ? AND product_id = ?", *req.ProductUnitID, req.ProductID).First(&productUnit).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return nil, decimal.Zero, pkgerrors.NewBadRequestError("product unit not found")
-			}
-			return nil, decimal.Zero, fmt.Errorf("failed to validate product unit: %w", err)
-		}
-	}
-
 	// Parse quantities and prices
 	quantity, err := decimal.NewFromString(req.Quantity)
 	if err != nil {
@@ -316,6 +308,12 @@ func (s *PurchaseOrderService) createPurchaseOrderItem(ctx context.Context, tx *
 		return nil, decimal.Zero, pkgerrors.NewBadRequestError("quantity must be positive")
 	}
 
+	// Validate the entered unit and keep the base-unit quantity alongside
+	unit, baseQty, err := s.uomService.ConvertQuantity(tx, req.ProductID, req.ProductUnitID, quantity)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
 	unitPrice, err := decimal.NewFromString(req.UnitPrice)
 	if err != nil {
 		return nil, decimal.Zero, pkgerrors.NewBadRequestError("invalid unitPrice format")
@@ -344,8 +342,9 @@ func (s *PurchaseOrderService) createPurchaseOrderItem(ctx context.Context, tx *
 	item := &models.PurchaseOrderItem{
 		PurchaseOrderID: purchaseOrderID,
 		ProductID:       req.ProductID,
-		ProductUnitID:   req.ProductUnitID,
+		ProductUnitID:   unit.ProductUnitID,
 		Quantity:        quantity,
+		BaseQuantity:    baseQty,
 		UnitPrice:       unitPrice,
 		DiscountPct:     discountPct,
 		DiscountAmt:     discountAmt,
@@ -1202,18 +1201,19 @@ func (s *PurchaseOrderService) mapPurchaseOrderToResponse(po *models.PurchaseOrd
 // mapPurchaseOrderItemToResponse converts PurchaseOrderItem model to PurchaseOrderItemResponse DTO
 func (s *PurchaseOrderService) mapPurchaseOrderItemToResponse(item *models.PurchaseOrderItem) dto.PurchaseOrderItemResponse {
 	response := dto.PurchaseOrderItemResponse{
-		ID:          item.ID,
-		ProductID:   item.ProductID,
-		Quantity:    item.Quantity.String(),
-		UnitPrice:   item.UnitPrice.String(),
-		DiscountPct: item.DiscountPct.String(),
-		DiscountAmt: item.DiscountAmt.String(),
-		Subtotal:    item.Subtotal.String(),
-		ReceivedQty: item.ReceivedQty.String(),
-		InvoicedQty: item.InvoicedQty.String(),
-		Notes:       item.Notes,
-		CreatedAt:   item.CreatedAt,
-		UpdatedAt:   item.UpdatedAt,
+		ID:           item.ID,
+		ProductID:    item.ProductID,
+		Quantity:     item.Quantity.String(),
+		BaseQuantity: item.BaseQuantity.String(),
+		UnitPrice:    item.UnitPrice.String(),
+		DiscountPct:  item.DiscountPct.String(),
+		DiscountAmt:  item.DiscountAmt.String(),
+		Subtotal:     item.Subtotal.String(),
+		ReceivedQty:  item.ReceivedQty.String(),
+		InvoicedQty:  item.InvoicedQty.String(),
+		Notes:        item.Notes,
+		CreatedAt:    item.CreatedAt,
+		UpdatedAt:    item.UpdatedAt,
 	}
 
 	// Map product if loaded (including tracking flags for goods receipt validation)
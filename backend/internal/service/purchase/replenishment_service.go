@@ -0,0 +1,406 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/uom"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// defaultUsageDays is the sales history used for average daily usage when a run does not set one
+const defaultUsageDays = 30
+
+// ReplenishmentService proposes purchase quantities from stock levels and turns reviewed
+// proposals into DRAFT purchase orders.
+//
+// For every stocked product in a warehouse:
+//
+//	projected    = on hand - reserved - quarantine + in transit + open PO quantity
+//	reorderPoint = minimum stock + daily usage x supplier lead time
+//	target       = maximum stock, or reorderPoint + daily usage x lead time when no higher maximum is set
+//
+// and a suggestion of target - projected (rounded up) is made when projected <= reorderPoint.
+type ReplenishmentService struct {
+	db                   *gorm.DB
+	purchaseOrderService *PurchaseOrderService
+}
+
+// NewReplenishmentService creates a new replenishment service instance
+func NewReplenishmentService(db *gorm.DB, purchaseOrderService *PurchaseOrderService) *ReplenishmentService {
+	return &ReplenishmentService{
+		db:                   db,
+		purchaseOrderService: purchaseOrderService,
+	}
+}
+
+// stockKey identifies a product in a warehouse
+type stockKey struct {
+	WarehouseID string
+	ProductID   string
+}
+
+// GetSuggestions runs replenishment for a company (or one warehouse) and returns the reorder
+// suggestions grouped by the product's primary supplier
+func (s *ReplenishmentService) GetSuggestions(ctx context.Context, tenantID, companyID string, query *dto.ReplenishmentQuery) (*dto.ReplenishmentResponse, error) {
+	usageDays := defaultUsageDays
+	if query.UsageDays > 0 {
+		usageDays = query.UsageDays
+	}
+	now := time.Now()
+
+	// 1. Stocked products of active warehouses
+	stockQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.WarehouseStock{}).
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Joins("JOIN products ON products.id = warehouse_stocks.product_id").
+		Where("warehouses.company_id = ? AND warehouses.is_active = ?", companyID, true).
+		Where("products.company_id = ? AND products.is_active = ?", companyID, true)
+	if query.WarehouseID != nil {
+		stockQuery = stockQuery.Where("warehouse_stocks.warehouse_id = ?", *query.WarehouseID)
+	}
+
+	var stocks []models.WarehouseStock
+	if err := stockQuery.Preload("Warehouse").Preload("Product").Find(&stocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load warehouse stocks: %w", err)
+	}
+
+	suppliers, err := s.preferredSuppliers(ctx, tenantID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	onOrder, err := s.openOrderQuantities(ctx, tenantID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.dailyUsage(ctx, tenantID, companyID, now.AddDate(0, 0, -usageDays), usageDays)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Compute the need per warehouse stock row and group by supplier
+	groups := make(map[string]*dto.ReplenishmentSupplierGroup)
+	groupTotals := make(map[string]decimal.Decimal)
+	for _, stock := range stocks {
+		supplier := suppliers[stock.ProductID]
+		if query.SupplierID != nil && (supplier == nil || supplier.SupplierID != *query.SupplierID) {
+			continue
+		}
+
+		key := stockKey{WarehouseID: stock.WarehouseID, ProductID: stock.ProductID}
+		dailyUsage := usage[key]
+		minimumStock := stock.MinimumStock
+		if !minimumStock.IsPositive() {
+			minimumStock = stock.Product.MinimumStock
+		}
+		if !minimumStock.IsPositive() && !stock.MaximumStock.IsPositive() && !dailyUsage.IsPositive() {
+			continue // Not managed by replenishment
+		}
+
+		leadTime := 0
+		unitPrice := decimal.Zero
+		if supplier != nil {
+			leadTime = supplier.LeadTime
+			unitPrice = supplier.SupplierPrice
+		}
+
+		leadTimeDemand := dailyUsage.Mul(decimal.NewFromInt(int64(leadTime)))
+		reorderPoint := minimumStock.Add(leadTimeDemand)
+		projected := stock.Quantity.Sub(stock.ReservedQuantity).Sub(stock.QuarantineQuantity).Add(stock.InTransitQuantity).Add(onOrder[key])
+		if projected.GreaterThan(reorderPoint) {
+			continue
+		}
+
+		target := stock.MaximumStock
+		if !target.GreaterThan(reorderPoint) {
+			target = reorderPoint.Add(leadTimeDemand)
+		}
+		suggested := target.Sub(projected).Ceil()
+		if !suggested.IsPositive() {
+			continue
+		}
+
+		amount := suggested.Mul(unitPrice).Round(2)
+		line := dto.ReplenishmentLine{
+			WarehouseID:   stock.WarehouseID,
+			WarehouseCode: stock.Warehouse.Code,
+			WarehouseName: stock.Warehouse.Name,
+			ProductID:     stock.ProductID,
+			ProductCode:   stock.Product.Code,
+			ProductName:   stock.Product.Name,
+			BaseUnit:      stock.Product.BaseUnit,
+			OnHand:        stock.Quantity.String(),
+			Reserved:      stock.ReservedQuantity.String(),
+			InTransit:     stock.InTransitQuantity.String(),
+			OnOrder:       onOrder[key].String(),
+			Projected:     projected.String(),
+			DailyUsage:    dailyUsage.Round(3).String(),
+			LeadTimeDays:  leadTime,
+			MinimumStock:  minimumStock.String(),
+			MaximumStock:  stock.MaximumStock.String(),
+			ReorderPoint:  reorderPoint.Round(3).String(),
+			TargetStock:   target.Round(3).String(),
+			SuggestedQty:  suggested.String(),
+			UnitPrice:     unitPrice.String(),
+			Amount:        amount.String(),
+		}
+
+		groupKey := ""
+		if supplier != nil {
+			groupKey = supplier.SupplierID
+		}
+		group, ok := groups[groupKey]
+		if !ok {
+			group = &dto.ReplenishmentSupplierGroup{}
+			if supplier != nil {
+				group.SupplierID = &supplier.SupplierID
+				group.SupplierCode = supplier.Supplier.Code
+				group.SupplierName = supplier.Supplier.Name
+			}
+			groups[groupKey] = group
+		}
+		group.Lines = append(group.Lines, line)
+		groupTotals[groupKey] = groupTotals[groupKey].Add(amount)
+	}
+
+	// 3. Suppliers by name (products without a supplier last), lines by warehouse and product
+	response := &dto.ReplenishmentResponse{
+		GeneratedAt: now,
+		UsageDays:   usageDays,
+		Groups:      make([]dto.ReplenishmentSupplierGroup, 0, len(groups)),
+	}
+	for groupKey, group := range groups {
+		group.TotalAmount = groupTotals[groupKey].String()
+		sort.Slice(group.Lines, func(i, j int) bool {
+			if group.Lines[i].WarehouseCode != group.Lines[j].WarehouseCode {
+				return group.Lines[i].WarehouseCode < group.Lines[j].WarehouseCode
+			}
+			return group.Lines[i].ProductCode < group.Lines[j].ProductCode
+		})
+		response.Groups = append(response.Groups, *group)
+	}
+	sort.Slice(response.Groups, func(i, j int) bool {
+		if (response.Groups[i].SupplierID == nil) != (response.Groups[j].SupplierID == nil) {
+			return response.Groups[j].SupplierID == nil
+		}
+		return response.Groups[i].SupplierName < response.Groups[j].SupplierName
+	})
+
+	return response, nil
+}
+
+// preferredSuppliers returns the supplier to buy each product from: the primary supplier,
+// else the cheapest active supplier of the product
+func (s *ReplenishmentService) preferredSuppliers(ctx context.Context, tenantID, companyID string) (map[string]*models.ProductSupplier, error) {
+	var productSuppliers []models.ProductSupplier
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Joins("JOIN suppliers ON suppliers.id = product_suppliers.supplier_id").
+		Where("suppliers.company_id = ? AND suppliers.is_active = ?", companyID, true).
+		Preload("Supplier").
+		Order("product_suppliers.is_primary DESC, product_suppliers.supplier_price ASC").
+		Find(&productSuppliers).Error; err != nil {
+		return nil, fmt.Errorf("failed to load product suppliers: %w", err)
+	}
+
+	suppliers := make(map[string]*models.ProductSupplier)
+	for i := range productSuppliers {
+		if _, ok := suppliers[productSuppliers[i].ProductID]; !ok {
+			suppliers[productSuppliers[i].ProductID] = &productSuppliers[i]
+		}
+	}
+
+	return suppliers, nil
+}
+
+// openOrderQuantities returns the quantity (base unit) still to be received on DRAFT and
+// CONFIRMED purchase orders. Drafts count so a second run does not propose the same order again.
+func (s *ReplenishmentService) openOrderQuantities(ctx context.Context, tenantID, companyID string) (map[stockKey]decimal.Decimal, error) {
+	var items []models.PurchaseOrderItem
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_items.purchase_order_id").
+		Where("purchase_orders.company_id = ? AND purchase_orders.status IN ?", companyID,
+			[]models.PurchaseOrderStatus{models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusConfirmed}).
+		Preload("PurchaseOrder").
+		Preload("ProductUnit").
+		Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to load open purchase order items: %w", err)
+	}
+
+	onOrder := make(map[stockKey]decimal.Decimal)
+	for _, item := range items {
+		// Received quantity is kept in the line's unit
+		open := uom.FromProductUnit(item.ProductUnit).ToBase(item.Quantity.Sub(item.ReceivedQty))
+		if !open.IsPositive() {
+			continue
+		}
+
+		key := stockKey{WarehouseID: item.PurchaseOrder.WarehouseID, ProductID: item.ProductID}
+		onOrder[key] = onOrder[key].Add(open)
+	}
+
+	return onOrder, nil
+}
+
+// dailyUsage returns the average quantity (base unit) shipped per day since the given date,
+// from OUT movements per warehouse and product
+func (s *ReplenishmentService) dailyUsage(ctx context.Context, tenantID, companyID string, since time.Time, days int) (map[stockKey]decimal.Decimal, error) {
+	var rows []struct {
+		WarehouseID string
+		ProductID   string
+		Quantity    decimal.Decimal
+	}
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.InventoryMovement{}).
+		Select("warehouse_id, product_id, SUM(quantity) AS quantity").
+		Where("company_id = ? AND movement_type = ? AND movement_date >= ?", companyID, models.MovementTypeOut, since).
+		Group("warehouse_id, product_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load stock usage: %w", err)
+	}
+
+	usage := make(map[stockKey]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		usage[stockKey{WarehouseID: row.WarehouseID, ProductID: row.ProductID}] = row.Quantity.Abs().Div(decimal.NewFromInt(int64(days)))
+	}
+
+	return usage, nil
+}
+
+// CreatePurchaseOrders turns reviewed suggestion lines into DRAFT purchase orders,
+// one per supplier and warehouse. Lines are validated before any order is created.
+// Each order is created on its own: the orders that were created are returned together with
+// an error per supplier and warehouse group that failed. When no order could be created the
+// first failure is returned as the error.
+func (s *ReplenishmentService) CreatePurchaseOrders(ctx context.Context, tenantID, companyID, userID string, req *dto.CreateReplenishmentOrdersRequest, ipAddress, userAgent string) ([]*models.PurchaseOrder, []dto.ReplenishmentOrderError, error) {
+	poDate := time.Now()
+	if req.PODate != nil && *req.PODate != "" {
+		parsed, err := time.Parse("2006-01-02", *req.PODate)
+		if err != nil {
+			return nil, nil, pkgerrors.NewBadRequestError("invalid poDate format, expected YYYY-MM-DD")
+		}
+		poDate = parsed
+	}
+
+	type orderGroup struct {
+		request  *dto.CreatePurchaseOrderRequest
+		leadTime int
+	}
+	var orders []*orderGroup
+	byKey := make(map[string]*orderGroup)
+
+	for _, line := range req.Lines {
+		quantity, err := decimal.NewFromString(line.Quantity)
+		if err != nil || !quantity.IsPositive() {
+			return nil, nil, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid quantity for product %s", line.ProductID))
+		}
+
+		var productSupplier models.ProductSupplier
+		err = s.db.WithContext(ctx).Set("tenant_id", tenantID).
+			Where("product_id = ? AND supplier_id = ?", line.ProductID, line.SupplierID).
+			First(&productSupplier).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, nil, fmt.Errorf("failed to load product supplier: %w", err)
+		}
+
+		unitPrice := productSupplier.SupplierPrice.String()
+		if line.UnitPrice != nil && *line.UnitPrice != "" {
+			unitPrice = *line.UnitPrice
+		} else if err == gorm.ErrRecordNotFound {
+			return nil, nil, pkgerrors.NewBadRequestError(fmt.Sprintf("product %s has no price for this supplier; set unitPrice", line.ProductID))
+		}
+
+		key := line.SupplierID + "/" + line.WarehouseID
+		order, ok := byKey[key]
+		if !ok {
+			if err := s.validateOrderParties(ctx, tenantID, companyID, line.SupplierID, line.WarehouseID); err != nil {
+				return nil, nil, err
+			}
+			order = &orderGroup{
+				request: &dto.CreatePurchaseOrderRequest{
+					SupplierID:  line.SupplierID,
+					WarehouseID: line.WarehouseID,
+					PODate:      poDate.Format("2006-01-02"),
+					Notes:       req.Notes,
+				},
+			}
+			byKey[key] = order
+			orders = append(orders, order)
+		}
+		if productSupplier.LeadTime > order.leadTime {
+			order.leadTime = productSupplier.LeadTime
+		}
+		order.request.Items = append(order.request.Items, dto.CreatePurchaseOrderItemRequest{
+			ProductID: line.ProductID,
+			Quantity:  quantity.String(),
+			UnitPrice: unitPrice,
+			Notes:     line.Notes,
+		})
+	}
+
+	purchaseOrders := make([]*models.PurchaseOrder, 0, len(orders))
+	failures := make([]dto.ReplenishmentOrderError, 0)
+	var firstErr error
+	for _, order := range orders {
+		if order.leadTime > 0 {
+			expected := poDate.AddDate(0, 0, order.leadTime).Format("2006-01-02")
+			order.request.ExpectedDeliveryAt = &expected
+		}
+
+		purchaseOrder, err := s.purchaseOrderService.CreatePurchaseOrder(ctx, tenantID, companyID, userID, order.request, ipAddress, userAgent)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			message := "failed to create purchase order"
+			if appErr, ok := err.(*pkgerrors.AppError); ok {
+				message = appErr.Error()
+			} else {
+				log.Printf("[ERROR][REPLENISHMENT] Purchase order for supplier %s, warehouse %s failed: %v",
+					order.request.SupplierID, order.request.WarehouseID, err)
+			}
+			failures = append(failures, dto.ReplenishmentOrderError{
+				SupplierID:  order.request.SupplierID,
+				WarehouseID: order.request.WarehouseID,
+				Message:     message,
+			})
+			continue
+		}
+		purchaseOrders = append(purchaseOrders, purchaseOrder)
+	}
+
+	if len(purchaseOrders) == 0 && firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	return purchaseOrders, failures, nil
+}
+
+// validateOrderParties checks the supplier and warehouse of a replenishment order are active in the company
+func (s *ReplenishmentService) validateOrderParties(ctx context.Context, tenantID, companyID, supplierID, warehouseID string) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.Supplier{}).
+		Where("id = ? AND company_id = ? AND is_active = ?", supplierID, companyID, true).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to validate supplier: %w", err)
+	}
+	if count == 0 {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("supplier %s not found or inactive", supplierID))
+	}
+
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.Warehouse{}).
+		Where("id = ? AND company_id = ? AND is_active = ?", warehouseID, companyID, true).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to validate warehouse: %w", err)
+	}
+	if count == 0 {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("warehouse %s not found or inactive", warehouseID))
+	}
+
+	return nil
+}
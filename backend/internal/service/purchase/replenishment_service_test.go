@@ -0,0 +1,110 @@
+package purchase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplenishmentService_GetSuggestions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(&models.PurchaseOrder{}, &models.PurchaseOrderItem{}, &models.InventoryMovement{}))
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+
+	createSupplier := func(code string) *models.Supplier {
+		supplier := &models.Supplier{TenantID: company.TenantID, CompanyID: company.ID, Code: code, Name: "Supplier " + code, IsActive: true}
+		require.NoError(t, db.Create(supplier).Error)
+		return supplier
+	}
+	createProduct := func(code string) *models.Product {
+		product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: code, Name: "Product " + code, BaseUnit: "PCS", IsActive: true}
+		require.NoError(t, db.Create(product).Error)
+		return product
+	}
+	createStock := func(product *models.Product, qty, reserved, minimum, maximum int64) {
+		require.NoError(t, db.Create(&models.WarehouseStock{
+			WarehouseID:      warehouse.ID,
+			ProductID:        product.ID,
+			Quantity:         decimal.NewFromInt(qty),
+			ReservedQuantity: decimal.NewFromInt(reserved),
+			MinimumStock:     decimal.NewFromInt(minimum),
+			MaximumStock:     decimal.NewFromInt(maximum),
+		}).Error)
+	}
+
+	primary := createSupplier("SUP-A")
+	cheaper := createSupplier("SUP-B")
+
+	// Reorder point 10 + 1/day x 7 days = 17; projected 5 - 1 + 4 on order = 8; target = max 50
+	managed := createProduct("P-001")
+	createStock(managed, 5, 1, 10, 50)
+	require.NoError(t, db.Create(&models.ProductSupplier{ProductID: managed.ID, SupplierID: primary.ID, SupplierPrice: decimal.NewFromInt(1000), LeadTime: 7, IsPrimary: true}).Error)
+	require.NoError(t, db.Create(&models.ProductSupplier{ProductID: managed.ID, SupplierID: cheaper.ID, SupplierPrice: decimal.NewFromInt(900), LeadTime: 3}).Error)
+	require.NoError(t, db.Create(&models.InventoryMovement{
+		TenantID: company.TenantID, CompanyID: company.ID, WarehouseID: warehouse.ID, ProductID: managed.ID,
+		MovementDate: time.Now().AddDate(0, 0, -3), MovementType: models.MovementTypeOut, Quantity: decimal.NewFromInt(-30),
+	}).Error)
+	po := &models.PurchaseOrder{TenantID: company.TenantID, CompanyID: company.ID, PONumber: "PO-001", PODate: time.Now(), SupplierID: primary.ID, WarehouseID: warehouse.ID, Status: models.PurchaseOrderStatusConfirmed}
+	require.NoError(t, db.Create(po).Error)
+	require.NoError(t, db.Create(&models.PurchaseOrderItem{PurchaseOrderID: po.ID, ProductID: managed.ID, Quantity: decimal.NewFromInt(4), UnitPrice: decimal.NewFromInt(1000)}).Error)
+
+	// No supplier: reorder point 5, no usage, target = reorder point
+	unsourced := createProduct("P-002")
+	createStock(unsourced, 0, 0, 5, 0)
+
+	// Above its reorder point: no suggestion
+	healthy := createProduct("P-003")
+	createStock(healthy, 100, 0, 10, 50)
+
+	// Not managed: no minimum, maximum or usage
+	unmanaged := createProduct("P-004")
+	createStock(unmanaged, 0, 0, 0, 0)
+
+	service := NewReplenishmentService(db, nil)
+
+	t.Run("success - suggestions grouped by primary supplier", func(t *testing.T) {
+		response, err := service.GetSuggestions(context.Background(), company.TenantID, company.ID, &dto.ReplenishmentQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 30, response.UsageDays)
+		require.Len(t, response.Groups, 2)
+
+		group := response.Groups[0]
+		require.NotNil(t, group.SupplierID)
+		assert.Equal(t, primary.ID, *group.SupplierID)
+		require.Len(t, group.Lines, 1)
+		line := group.Lines[0]
+		assert.Equal(t, managed.ID, line.ProductID)
+		assert.Equal(t, "4", line.OnOrder)
+		assert.Equal(t, "8", line.Projected)
+		assert.Equal(t, "1", line.DailyUsage)
+		assert.Equal(t, 7, line.LeadTimeDays)
+		assert.Equal(t, "17", line.ReorderPoint)
+		assert.Equal(t, "42", line.SuggestedQty)
+		assert.Equal(t, "42000", line.Amount)
+		assert.Equal(t, "42000", group.TotalAmount)
+
+		assert.Nil(t, response.Groups[1].SupplierID)
+		require.Len(t, response.Groups[1].Lines, 1)
+		assert.Equal(t, unsourced.ID, response.Groups[1].Lines[0].ProductID)
+		assert.Equal(t, "5", response.Groups[1].Lines[0].SuggestedQty)
+	})
+
+	t.Run("success - supplier filter", func(t *testing.T) {
+		response, err := service.GetSuggestions(context.Background(), company.TenantID, company.ID, &dto.ReplenishmentQuery{SupplierID: &cheaper.ID})
+
+		require.NoError(t, err)
+		assert.Empty(t, response.Groups)
+	})
+}
@@ -0,0 +1,188 @@
+package purchaseinvoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/service/inventory"
+	"backend/models"
+)
+
+// ============================================================================
+// LANDED COST Allocation
+// ============================================================================
+
+// allocateLandedCost spreads the invoice's shipping, handling and other costs over the
+// accepted lines of its goods receipt and adds them to the cost of stock still on hand.
+// Invoices without a goods receipt or without extra charges are left untouched.
+func (s *PurchaseInvoiceService) allocateLandedCost(
+	tx *gorm.DB,
+	invoice *models.PurchaseInvoice,
+	method models.LandedCostAllocationMethod,
+	userID string,
+) ([]models.LandedCostAllocation, error) {
+	landedCost := invoice.GetTotalNonGoodsCost()
+	if invoice.GoodsReceiptID == nil || *invoice.GoodsReceiptID == "" || !landedCost.IsPositive() {
+		return nil, nil
+	}
+
+	var goodsReceipt models.GoodsReceipt
+	if err := tx.Preload("Items.ProductUnit").
+		Preload("Items.PurchaseOrderItem").
+		Where("id = ? AND company_id = ?", *invoice.GoodsReceiptID, invoice.CompanyID).
+		First(&goodsReceipt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("goods receipt not found")
+		}
+		return nil, fmt.Errorf("failed to load goods receipt: %w", err)
+	}
+
+	if goodsReceipt.Status != models.GoodsReceiptStatusAccepted && goodsReceipt.Status != models.GoodsReceiptStatusPartial {
+		return nil, errors.New("goods receipt must be accepted before landed cost can be allocated")
+	}
+
+	// Collect the allocation basis per accepted line
+	var lines []models.GoodsReceiptItem
+	var bases []decimal.Decimal
+	totalBasis := decimal.Zero
+	for _, item := range goodsReceipt.Items {
+		if !item.BaseAcceptedQty.IsPositive() {
+			continue
+		}
+
+		basis, err := s.landedCostBasis(tx, &item, method)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, item)
+		bases = append(bases, basis)
+		totalBasis = totalBasis.Add(basis)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("goods receipt has no accepted items to allocate landed cost to")
+	}
+	if !totalBasis.IsPositive() {
+		return nil, fmt.Errorf("cannot allocate landed cost by %s: allocation basis is zero", method)
+	}
+
+	// Allocate proportionally; the last line takes the rounding remainder
+	allocations := make([]models.LandedCostAllocation, 0, len(lines))
+	remaining := landedCost
+	for i, item := range lines {
+		amount := remaining
+		if i < len(lines)-1 {
+			amount = landedCost.Mul(bases[i]).Div(totalBasis).Round(4)
+			remaining = remaining.Sub(amount)
+		}
+
+		result, err := s.stockPostingService.Revalue(tx, &inventory.Revaluation{
+			TenantID:            invoice.TenantID,
+			CompanyID:           invoice.CompanyID,
+			WarehouseID:         goodsReceipt.WarehouseID,
+			ProductID:           item.ProductID,
+			Quantity:            item.BaseAcceptedQty,
+			Amount:              amount,
+			SourceReferenceType: inventory.ReferenceTypeGoodsReceipt,
+			SourceReferenceID:   goodsReceipt.ID,
+			ReferenceType:       inventory.ReferenceTypePurchaseInvoice,
+			ReferenceID:         invoice.ID,
+			ReferenceNumber:     invoice.InvoiceNumber,
+			CreatedBy:           userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		allocation := models.LandedCostAllocation{
+			TenantID:           invoice.TenantID,
+			CompanyID:          invoice.CompanyID,
+			PurchaseInvoiceID:  invoice.ID,
+			GoodsReceiptID:     goodsReceipt.ID,
+			GoodsReceiptItemID: item.ID,
+			WarehouseID:        goodsReceipt.WarehouseID,
+			ProductID:          item.ProductID,
+			AllocationMethod:   method,
+			Quantity:           item.BaseAcceptedQty,
+			BasisValue:         bases[i],
+			AllocatedAmount:    amount,
+			CapitalizedAmount:  result.Capitalized,
+			ExpensedAmount:     result.Expensed,
+			CreatedBy:          &userID,
+		}
+		if result.Movement != nil {
+			allocation.MovementID = &result.Movement.ID
+		}
+
+		if err := tx.Create(&allocation).Error; err != nil {
+			return nil, fmt.Errorf("failed to save landed cost allocation: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	return allocations, nil
+}
+
+// landedCostBasis returns the value, quantity or weight of a goods receipt line
+func (s *PurchaseInvoiceService) landedCostBasis(
+	tx *gorm.DB,
+	item *models.GoodsReceiptItem,
+	method models.LandedCostAllocationMethod,
+) (decimal.Decimal, error) {
+	switch method {
+	case models.LandedCostAllocationByQuantity:
+		return item.BaseAcceptedQty, nil
+
+	case models.LandedCostAllocationByWeight:
+		unit := item.ProductUnit
+		if unit == nil {
+			var baseUnit models.ProductUnit
+			err := tx.Where("product_id = ? AND is_base_unit = ?", item.ProductID, true).First(&baseUnit).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return decimal.Zero, fmt.Errorf("failed to load product unit: %w", err)
+			}
+			if err == nil {
+				unit = &baseUnit
+			}
+		}
+		if unit == nil || unit.Weight == nil || !unit.Weight.IsPositive() {
+			return decimal.Zero, fmt.Errorf("cannot allocate landed cost by weight: no unit weight set for product %s", item.ProductID)
+		}
+		return item.AcceptedQty.Mul(*unit.Weight), nil
+
+	default:
+		// Net PO line price (after discount) per ordered unit
+		poItem := item.PurchaseOrderItem
+		price := poItem.UnitPrice
+		if poItem.Quantity.IsPositive() && poItem.Subtotal.IsPositive() {
+			price = poItem.Subtotal.Div(poItem.Quantity)
+		}
+		return item.AcceptedQty.Mul(price), nil
+	}
+}
+
+// GetLandedCostAllocations returns the landed cost allocated by an approved invoice
+func (s *PurchaseInvoiceService) GetLandedCostAllocations(
+	ctx context.Context,
+	tenantID, companyID, invoiceID string,
+) ([]models.LandedCostAllocation, error) {
+	if _, err := s.GetPurchaseInvoice(ctx, tenantID, companyID, invoiceID); err != nil {
+		return nil, err
+	}
+
+	var allocations []models.LandedCostAllocation
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Product").
+		Where("purchase_invoice_id = ? AND company_id = ?", invoiceID, companyID).
+		Order("created_at ASC").
+		Find(&allocations).Error; err != nil {
+		return nil, fmt.Errorf("failed to load landed cost allocations: %w", err)
+	}
+
+	return allocations, nil
+}
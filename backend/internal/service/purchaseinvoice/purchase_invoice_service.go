@@ -13,22 +13,25 @@ import (
 	"backend/internal/dto"
 	"backend/internal/service/audit"
 	"backend/internal/service/document"
+	"backend/internal/service/inventory"
 	"backend/models"
 )
 
 // PurchaseInvoiceService handles business logic for purchase invoices
 type PurchaseInvoiceService struct {
-	db           *gorm.DB
-	docNumberGen *document.DocumentNumberGenerator
-	auditService *audit.AuditService
+	db                  *gorm.DB
+	docNumberGen        *document.DocumentNumberGenerator
+	auditService        *audit.AuditService
+	stockPostingService *inventory.StockPostingService
 }
 
 // NewPurchaseInvoiceService creates a new purchase invoice service
-func NewPurchaseInvoiceService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator, auditService *audit.AuditService) *PurchaseInvoiceService {
+func NewPurchaseInvoiceService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator, auditService *audit.AuditService, stockPostingService *inventory.StockPostingService) *PurchaseInvoiceService {
 	return &PurchaseInvoiceService{
-		db:           db,
-		docNumberGen: docNumberGen,
-		auditService: auditService,
+		db:                  db,
+		docNumberGen:        docNumberGen,
+		auditService:        auditService,
+		stockPostingService: stockPostingService,
 	}
 }
 
@@ -1183,7 +1186,26 @@ func (s *PurchaseInvoiceService) ApprovePurchaseInvoice(
 		invoice.Notes = req.Notes
 	}
 
-	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Save(invoice).Error; err != nil {
+	landedCostMethod := models.LandedCostAllocationByValue
+	if req.LandedCostMethod != nil && *req.LandedCostMethod != "" {
+		landedCostMethod = models.LandedCostAllocationMethod(*req.LandedCostMethod)
+	}
+	switch landedCostMethod {
+	case models.LandedCostAllocationByValue, models.LandedCostAllocationByQuantity, models.LandedCostAllocationByWeight:
+	default:
+		return nil, fmt.Errorf("invalid landed cost allocation method: %s", landedCostMethod)
+	}
+
+	// Save approval and allocate landed cost to the received goods atomically
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(invoice).Error; err != nil {
+			return err
+		}
+
+		_, err := s.allocateLandedCost(tx, invoice, landedCostMethod, approverID)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
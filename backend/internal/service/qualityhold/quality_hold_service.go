@@ -0,0 +1,454 @@
+package qualityhold
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// QualityHoldService - Quarantine of batches pending a quality decision.
+// A hold puts a batch in QUARANTINE, which keeps its stock on hand but out of available
+// stock and out of picking. Quality then releases the stock back to AVAILABLE or scraps it
+// (written off with a DAMAGED movement); each decision records its approver and reason.
+type QualityHoldService struct {
+	db                  *gorm.DB
+	stockPostingService *inventory.StockPostingService
+}
+
+// NewQualityHoldService creates a new quality hold service instance
+func NewQualityHoldService(db *gorm.DB, stockPostingService *inventory.StockPostingService) *QualityHoldService {
+	return &QualityHoldService{
+		db:                  db,
+		stockPostingService: stockPostingService,
+	}
+}
+
+// ReceiptHold describes stock placed on hold as it is booked in, either by goods
+// receipt inspection or by inspection of a customer return
+type ReceiptHold struct {
+	TenantID         string
+	CompanyID        string
+	WarehouseID      string
+	ProductID        string
+	BatchID          string
+	Source           models.QualityHoldSource // GOODS_RECEIPT (default) or CUSTOMER_RETURN
+	GoodsReceiptID   *string
+	CustomerReturnID *string
+	ReferenceNumber  string          // GRN or return number, used in the default reason
+	Quantity         decimal.Decimal // Base unit
+	Reason           string
+	CreatedBy        string
+}
+
+// ============================================================================
+// HOLD OPERATIONS
+// ============================================================================
+
+// HoldBatch puts an available batch on quality hold (AVAILABLE → QUARANTINE)
+func (s *QualityHoldService) HoldBatch(
+	ctx context.Context,
+	tenantID, companyID, userID string,
+	req *dto.CreateQualityHoldRequest,
+) (*models.QualityHold, error) {
+	var hold *models.QualityHold
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var batch models.ProductBatch
+		if err := tx.Model(&models.ProductBatch{}).
+			Select("product_batches.*").
+			Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+			Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+			Where("product_batches.id = ? AND warehouses.company_id = ?", req.BatchID, companyID).
+			First(&batch).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return pkgerrors.NewNotFoundError("Batch")
+			}
+			return fmt.Errorf("failed to load batch: %w", err)
+		}
+		if batch.Status != models.BatchStatusAvailable {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Only AVAILABLE batches can be put on hold, batch %s is %s", batch.BatchNumber, batch.Status))
+		}
+		if !batch.Quantity.IsPositive() {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Batch %s has no stock to hold", batch.BatchNumber))
+		}
+
+		qualityStatus := models.BatchQualityQuarantine
+		held, err := s.stockPostingService.SetBatchStatus(tx, batch.ID, models.BatchStatusQuarantine, &qualityStatus)
+		if err != nil {
+			return err
+		}
+
+		hold = &models.QualityHold{
+			TenantID:       tenantID,
+			CompanyID:      companyID,
+			HoldDate:       time.Now(),
+			WarehouseID:    held.WarehouseStock.WarehouseID,
+			ProductID:      held.ProductID,
+			BatchID:        held.ID,
+			Source:         models.QualityHoldSourceManual,
+			Status:         models.QualityHoldStatusOpen,
+			PreviousStatus: batch.Status,
+			HeldQuantity:   held.Quantity,
+			Reason:         req.Reason,
+			Notes:          req.Notes,
+		}
+		if userID != "" {
+			hold.CreatedBy = &userID
+		}
+		return s.createHold(tx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQualityHoldByID(ctx, tenantID, companyID, hold.ID)
+}
+
+// HoldReceipt records the hold for received stock posted into a QUARANTINE batch.
+// Runs inside the caller's receipt transaction. A further receipt into a batch that is
+// already on hold adds to the open hold instead of opening another one.
+func (s *QualityHoldService) HoldReceipt(tx *gorm.DB, receipt *ReceiptHold) (*models.QualityHold, error) {
+	var existing models.QualityHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("company_id = ? AND batch_id = ? AND status = ?", receipt.CompanyID, receipt.BatchID, models.QualityHoldStatusOpen).
+		First(&existing).Error
+	if err == nil {
+		heldQty := existing.HeldQuantity.Add(receipt.Quantity)
+		if err := tx.Model(&models.QualityHold{}).Where("id = ?", existing.ID).
+			Update("held_quantity", heldQty).Error; err != nil {
+			return nil, fmt.Errorf("failed to update quality hold: %w", err)
+		}
+		existing.HeldQuantity = heldQty
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check open quality holds: %w", err)
+	}
+
+	reason := receipt.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Held on receipt by inspection of %s", receipt.ReferenceNumber)
+	}
+	source := receipt.Source
+	if source == "" {
+		source = models.QualityHoldSourceGoodsReceipt
+	}
+	hold := &models.QualityHold{
+		TenantID:         receipt.TenantID,
+		CompanyID:        receipt.CompanyID,
+		HoldDate:         time.Now(),
+		WarehouseID:      receipt.WarehouseID,
+		ProductID:        receipt.ProductID,
+		BatchID:          receipt.BatchID,
+		Source:           source,
+		GoodsReceiptID:   receipt.GoodsReceiptID,
+		CustomerReturnID: receipt.CustomerReturnID,
+		Status:           models.QualityHoldStatusOpen,
+		PreviousStatus:   models.BatchStatusAvailable,
+		HeldQuantity:     receipt.Quantity,
+		Reason:           reason,
+	}
+	if receipt.CreatedBy != "" {
+		hold.CreatedBy = &receipt.CreatedBy
+	}
+	if err := s.createHold(tx, hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ReleaseHold returns everything still on hold to available stock (QUARANTINE → AVAILABLE)
+// and closes the hold. The approver is the user releasing.
+func (s *QualityHoldService) ReleaseHold(
+	ctx context.Context,
+	tenantID, companyID, holdID, userID string,
+	req *dto.ReleaseQualityHoldRequest,
+) (*models.QualityHold, error) {
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		hold, batch, err := s.lockOpenHold(tx, companyID, holdID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if _, err := s.stockPostingService.ReleaseQuarantine(tx, &inventory.QuarantineRelease{
+			TenantID:        tenantID,
+			CompanyID:       companyID,
+			BatchID:         batch.ID,
+			ReferenceID:     hold.ID,
+			ReferenceNumber: hold.HoldNumber,
+			Notes:           actionNotes("Quality release", req.Reason, req.Notes),
+			CreatedBy:       userID,
+			MovementDate:    now,
+		}); err != nil {
+			return err
+		}
+
+		action := &models.QualityHoldAction{
+			QualityHoldID: hold.ID,
+			Action:        models.QualityHoldActionRelease,
+			Quantity:      batch.Quantity,
+			Reason:        req.Reason,
+			Notes:         req.Notes,
+			ApprovedBy:    userID,
+			ApprovedAt:    now,
+		}
+		if err := tx.Create(action).Error; err != nil {
+			return fmt.Errorf("failed to record quality release: %w", err)
+		}
+
+		return tx.Model(&models.QualityHold{}).Where("id = ?", hold.ID).Updates(map[string]interface{}{
+			"status":            models.QualityHoldStatusReleased,
+			"released_quantity": hold.ReleasedQuantity.Add(batch.Quantity),
+			"closed_at":         now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQualityHoldByID(ctx, tenantID, companyID, holdID)
+}
+
+// ScrapHold writes off held stock with a DAMAGED movement. Without a quantity everything
+// still on hold is scrapped; a partial scrap leaves the rest on hold for a later decision.
+// Once the batch is empty the hold closes as SCRAPPED and the batch is marked DAMAGED.
+func (s *QualityHoldService) ScrapHold(
+	ctx context.Context,
+	tenantID, companyID, holdID, userID string,
+	req *dto.ScrapQualityHoldRequest,
+) (*models.QualityHold, error) {
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		hold, batch, err := s.lockOpenHold(tx, companyID, holdID)
+		if err != nil {
+			return err
+		}
+
+		quantity := batch.Quantity
+		if req.Quantity != nil && *req.Quantity != "" {
+			quantity, err = decimal.NewFromString(*req.Quantity)
+			if err != nil {
+				return pkgerrors.NewBadRequestError("Invalid scrap quantity")
+			}
+		}
+		if !quantity.IsPositive() {
+			return pkgerrors.NewBadRequestError("Scrap quantity must be greater than zero")
+		}
+		if quantity.GreaterThan(batch.Quantity) {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Scrap quantity %s exceeds the %s still on hold", quantity.String(), batch.Quantity.String()))
+		}
+
+		now := time.Now()
+		result, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:        tenantID,
+			CompanyID:       companyID,
+			WarehouseID:     hold.WarehouseID,
+			ProductID:       hold.ProductID,
+			BatchID:         &batch.ID,
+			MovementType:    models.MovementTypeDamaged,
+			Quantity:        quantity.Neg(),
+			MovementDate:    now,
+			ReferenceType:   inventory.ReferenceTypeQualityHold,
+			ReferenceID:     hold.ID,
+			ReferenceNumber: hold.HoldNumber,
+			Notes:           actionNotes("Quality scrap", req.Reason, req.Notes),
+			CreatedBy:       userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		action := &models.QualityHoldAction{
+			QualityHoldID: hold.ID,
+			Action:        models.QualityHoldActionScrap,
+			Quantity:      quantity,
+			Reason:        req.Reason,
+			Notes:         req.Notes,
+			MovementID:    &result.Movement.ID,
+			ApprovedBy:    userID,
+			ApprovedAt:    now,
+		}
+		if err := tx.Create(action).Error; err != nil {
+			return fmt.Errorf("failed to record quality scrap: %w", err)
+		}
+
+		updates := map[string]interface{}{
+			"scrapped_quantity": hold.ScrappedQuantity.Add(quantity),
+		}
+		if result.Batch.Quantity.IsZero() {
+			qualityStatus := models.BatchQualityDamaged
+			if _, err := s.stockPostingService.SetBatchStatus(tx, batch.ID, models.BatchStatusDamaged, &qualityStatus); err != nil {
+				return err
+			}
+			updates["status"] = models.QualityHoldStatusScrapped
+			updates["closed_at"] = now
+		}
+
+		return tx.Model(&models.QualityHold{}).Where("id = ?", hold.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQualityHoldByID(ctx, tenantID, companyID, holdID)
+}
+
+// GetQualityHoldByID retrieves a quality hold with its batch and decisions
+func (s *QualityHoldService) GetQualityHoldByID(ctx context.Context, tenantID, companyID, holdID string) (*models.QualityHold, error) {
+	var hold models.QualityHold
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Warehouse").
+		Preload("Product").
+		Preload("Batch").
+		Preload("Actions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("approved_at ASC")
+		}).
+		Preload("Actions.Approver").
+		Where("id = ? AND company_id = ?", holdID, companyID).
+		First(&hold).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Quality hold")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	return &hold, nil
+}
+
+// ListQualityHolds retrieves quality holds with filtering and pagination
+func (s *QualityHoldService) ListQualityHolds(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.QualityHoldQuery,
+) ([]models.QualityHold, *dto.PaginationInfo, error) {
+	var holds []models.QualityHold
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("quality_holds.company_id = ?", companyID)
+
+	if query.Status != nil {
+		db = db.Where("quality_holds.status = ?", *query.Status)
+	}
+	if query.Source != nil {
+		db = db.Where("quality_holds.source = ?", *query.Source)
+	}
+	if query.WarehouseID != nil {
+		db = db.Where("quality_holds.warehouse_id = ?", *query.WarehouseID)
+	}
+	if query.ProductID != nil {
+		db = db.Where("quality_holds.product_id = ?", *query.ProductID)
+	}
+	if query.Search != "" {
+		search := "%" + query.Search + "%"
+		db = db.Joins("JOIN product_batches ON product_batches.id = quality_holds.batch_id").
+			Where("quality_holds.hold_number LIKE ? OR product_batches.batch_number LIKE ?", search, search)
+	}
+
+	if err := db.Session(&gorm.Session{}).Model(&models.QualityHold{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Session(&gorm.Session{}).
+		Order("quality_holds.hold_date DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Warehouse").
+		Preload("Product").
+		Preload("Batch").
+		Preload("Actions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("approved_at ASC")
+		}).
+		Preload("Actions.Approver").
+		Find(&holds).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return holds, pagination, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// createHold numbers and saves a new hold
+func (s *QualityHoldService) createHold(tx *gorm.DB, hold *models.QualityHold) error {
+	holdNumber, err := s.generateHoldNumber(tx, hold.TenantID, hold.CompanyID)
+	if err != nil {
+		return err
+	}
+	hold.HoldNumber = holdNumber
+
+	if err := tx.Create(hold).Error; err != nil {
+		return fmt.Errorf("failed to create quality hold: %w", err)
+	}
+	return nil
+}
+
+// lockOpenHold loads an open hold for update, together with its batch.
+// The batch must still be in quarantine: a recall takes over a held batch.
+func (s *QualityHoldService) lockOpenHold(tx *gorm.DB, companyID, holdID string) (*models.QualityHold, *models.ProductBatch, error) {
+	var hold models.QualityHold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND company_id = ?", holdID, companyID).
+		First(&hold).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, pkgerrors.NewNotFoundError("Quality hold")
+		}
+		return nil, nil, fmt.Errorf("failed to load quality hold: %w", err)
+	}
+	if hold.Status != models.QualityHoldStatusOpen {
+		return nil, nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Quality hold is already %s", hold.Status))
+	}
+
+	var batch models.ProductBatch
+	if err := tx.Where("id = ?", hold.BatchID).First(&batch).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load batch: %w", err)
+	}
+	if batch.Status != models.BatchStatusQuarantine {
+		return nil, nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Batch %s is no longer in quarantine: %s", batch.BatchNumber, batch.Status))
+	}
+
+	return &hold, &batch, nil
+}
+
+// actionNotes builds the movement notes for a release or scrap
+func actionNotes(action, reason string, notes *string) *string {
+	text := fmt.Sprintf("%s: %s", action, reason)
+	if notes != nil && *notes != "" {
+		text = fmt.Sprintf("%s. Notes: %s", text, *notes)
+	}
+	return &text
+}
+
+// generateHoldNumber generates unique quality hold number for company
+func (s *QualityHoldService) generateHoldNumber(tx *gorm.DB, tenantID, companyID string) (string, error) {
+	var count int64
+	currentYear := time.Now().Year()
+	prefix := fmt.Sprintf("QH-%d-", currentYear)
+
+	if err := tx.Model(&models.QualityHold{}).
+		Where("company_id = ? AND tenant_id = ? AND hold_number LIKE ?", companyID, tenantID, prefix+"%").
+		Count(&count).Error; err != nil {
+		return "", pkgerrors.NewInternalError(err)
+	}
+
+	return fmt.Sprintf("%s%05d", prefix, count+1), nil
+}
@@ -0,0 +1,232 @@
+package qualityhold
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestQualityHoldService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.QualityHold{},
+		&models.QualityHoldAction{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	approver := testutil.CreateTestUser(t, db, "qc@example.com")
+
+	product := &models.Product{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		Code:           "PROD001",
+		Name:           "Susu UHT 1L",
+		BaseUnit:       "PCS",
+		IsBatchTracked: true,
+		IsActive:       true,
+	}
+	require.NoError(t, db.Create(product).Error)
+
+	postingService := inventory.NewStockPostingService(db)
+	service := NewQualityHoldService(db, postingService)
+
+	receive := func(batchNumber string, qty int64, quarantine bool) *models.ProductBatch {
+		result, err := postingService.Post(db, &inventory.StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			Batch:        &inventory.BatchDetails{BatchNumber: batchNumber, Quarantine: quarantine},
+		})
+		require.NoError(t, err)
+		return result.Batch
+	}
+	stock := func() models.WarehouseStock {
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		return stock
+	}
+
+	goodBatch := receive("B-001", 10, false)
+
+	var manualHoldID string
+	t.Run("success - manual hold quarantines the batch", func(t *testing.T) {
+		hold, err := service.HoldBatch(ctx, company.TenantID, company.ID, approver.ID, &dto.CreateQualityHoldRequest{
+			BatchID: goodBatch.ID,
+			Reason:  "Customer complaint on taste",
+		})
+
+		require.NoError(t, err)
+		manualHoldID = hold.ID
+		assert.Equal(t, models.QualityHoldStatusOpen, hold.Status)
+		assert.Equal(t, models.QualityHoldSourceManual, hold.Source)
+		assert.Equal(t, "10", hold.HeldQuantity.String())
+		assert.Equal(t, models.BatchStatusQuarantine, hold.Batch.Status)
+
+		current := stock()
+		assert.Equal(t, "10", current.Quantity.String())
+		assert.Equal(t, "10", current.QuarantineQuantity.String())
+		assert.True(t, current.AvailableQuantity().IsZero())
+	})
+
+	t.Run("error - quarantined batch cannot be sold", func(t *testing.T) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := postingService.Post(tx, &inventory.StockPosting{
+				TenantID:     company.TenantID,
+				CompanyID:    company.ID,
+				WarehouseID:  warehouse.ID,
+				ProductID:    product.ID,
+				MovementType: models.MovementTypeOut,
+				Quantity:     decimal.NewFromInt(-1),
+				BatchID:      &goodBatch.ID,
+			})
+			return err
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("error - batch already on hold", func(t *testing.T) {
+		_, err := service.HoldBatch(ctx, company.TenantID, company.ID, approver.ID, &dto.CreateQualityHoldRequest{
+			BatchID: goodBatch.ID,
+			Reason:  "Second hold",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("success - release returns stock to available", func(t *testing.T) {
+		hold, err := service.ReleaseHold(ctx, company.TenantID, company.ID, manualHoldID, approver.ID, &dto.ReleaseQualityHoldRequest{
+			Reason: "Lab test passed",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.QualityHoldStatusReleased, hold.Status)
+		assert.Equal(t, "10", hold.ReleasedQuantity.String())
+		assert.NotNil(t, hold.ClosedAt)
+		require.Len(t, hold.Actions, 1)
+		assert.Equal(t, models.QualityHoldActionRelease, hold.Actions[0].Action)
+		assert.Equal(t, approver.ID, hold.Actions[0].ApprovedBy)
+		assert.Equal(t, models.BatchStatusAvailable, hold.Batch.Status)
+
+		current := stock()
+		assert.Equal(t, "10", current.Quantity.String())
+		assert.True(t, current.QuarantineQuantity.IsZero())
+		assert.Equal(t, "10", current.AvailableQuantity().String())
+
+		var movements []models.InventoryMovement
+		require.NoError(t, db.Where("reference_type = ? AND reference_id = ?", inventory.ReferenceTypeQualityHold, manualHoldID).
+			Find(&movements).Error)
+		assert.Len(t, movements, 2)
+	})
+
+	t.Run("error - release a closed hold", func(t *testing.T) {
+		_, err := service.ReleaseHold(ctx, company.TenantID, company.ID, manualHoldID, approver.ID, &dto.ReleaseQualityHoldRequest{
+			Reason: "Again",
+		})
+		assert.Error(t, err)
+	})
+
+	// Receipt into quarantine, as goods receipt does for products held on receipt
+	heldBatch := receive("B-002", 6, true)
+	grnID := "grn-1"
+	receiptHold, err := service.HoldReceipt(db, &ReceiptHold{
+		TenantID:        company.TenantID,
+		CompanyID:       company.ID,
+		WarehouseID:     warehouse.ID,
+		ProductID:       product.ID,
+		BatchID:         heldBatch.ID,
+		GoodsReceiptID:  &grnID,
+		ReferenceNumber: "GRN-2026-00001",
+		Quantity:        decimal.NewFromInt(6),
+	})
+	require.NoError(t, err)
+
+	t.Run("success - receipt posted into quarantine", func(t *testing.T) {
+		assert.Equal(t, models.BatchStatusQuarantine, heldBatch.Status)
+		assert.Equal(t, models.QualityHoldSourceGoodsReceipt, receiptHold.Source)
+
+		current := stock()
+		assert.Equal(t, "16", current.Quantity.String())
+		assert.Equal(t, "6", current.QuarantineQuantity.String())
+		assert.Equal(t, "10", current.AvailableQuantity().String())
+	})
+
+	t.Run("error - scrap more than held", func(t *testing.T) {
+		quantity := "7"
+		_, err := service.ScrapHold(ctx, company.TenantID, company.ID, receiptHold.ID, approver.ID, &dto.ScrapQualityHoldRequest{
+			Quantity: &quantity,
+			Reason:   "Leaking packs",
+		})
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 400, appErr.StatusCode)
+	})
+
+	t.Run("success - partial scrap keeps the rest on hold", func(t *testing.T) {
+		quantity := "2"
+		hold, err := service.ScrapHold(ctx, company.TenantID, company.ID, receiptHold.ID, approver.ID, &dto.ScrapQualityHoldRequest{
+			Quantity: &quantity,
+			Reason:   "Leaking packs",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.QualityHoldStatusOpen, hold.Status)
+		assert.Equal(t, "2", hold.ScrappedQuantity.String())
+		assert.Equal(t, "4", hold.RemainingQuantity().String())
+		require.Len(t, hold.Actions, 1)
+		require.NotNil(t, hold.Actions[0].MovementID)
+
+		current := stock()
+		assert.Equal(t, "14", current.Quantity.String())
+		assert.Equal(t, "4", current.QuarantineQuantity.String())
+	})
+
+	t.Run("success - scrapping the rest closes the hold", func(t *testing.T) {
+		hold, err := service.ScrapHold(ctx, company.TenantID, company.ID, receiptHold.ID, approver.ID, &dto.ScrapQualityHoldRequest{
+			Reason: "Whole lot failed inspection",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.QualityHoldStatusScrapped, hold.Status)
+		assert.Equal(t, "6", hold.ScrappedQuantity.String())
+		assert.Equal(t, models.BatchStatusDamaged, hold.Batch.Status)
+		assert.Len(t, hold.Actions, 2)
+
+		current := stock()
+		assert.Equal(t, "10", current.Quantity.String())
+		assert.True(t, current.QuarantineQuantity.IsZero())
+	})
+
+	t.Run("success - list open holds", func(t *testing.T) {
+		status := string(models.QualityHoldStatusScrapped)
+		holds, pagination, err := service.ListQualityHolds(ctx, company.TenantID, company.ID, &dto.QualityHoldQuery{
+			Page:     1,
+			PageSize: 20,
+			Status:   &status,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, pagination.Total)
+		require.Len(t, holds, 1)
+		assert.Equal(t, receiptHold.ID, holds[0].ID)
+	})
+}
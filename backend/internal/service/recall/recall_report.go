@@ -0,0 +1,174 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+
+	"backend/internal/dto"
+	"backend/models"
+)
+
+// ============================================================================
+// RECALL REPORT
+// ============================================================================
+
+// GetRecallReport builds the recall report: stock held per warehouse, the goods receipts
+// and suppliers the lot came from, and the customers it was delivered to with their returns
+func (s *RecallService) GetRecallReport(ctx context.Context, tenantID, companyID, recallID string) (*models.ProductRecall, *dto.RecallReportResponse, error) {
+	recall, err := s.GetRecallByID(ctx, tenantID, companyID, recallID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recallBatches []models.ProductRecallBatch
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Batch").
+		Preload("Product").
+		Preload("Warehouse").
+		Preload("GoodsReceipt").
+		Preload("Supplier").
+		Where("product_recall_id = ?", recall.ID).
+		Order("created_at ASC").
+		Find(&recallBatches).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load recalled batches: %w", err)
+	}
+
+	report := &dto.RecallReportResponse{
+		Holds:     make([]dto.RecallHoldLine, 0, len(recallBatches)),
+		Sources:   []dto.RecallSourceLine{},
+		Customers: []dto.RecallCustomerLine{},
+	}
+
+	// Stock on hold and backward trace, one source line per goods receipt
+	sourceIndex := make(map[string]int)
+	productsBySource := make(map[string][]string)
+	for _, rb := range recallBatches {
+		report.Holds = append(report.Holds, dto.RecallHoldLine{
+			BatchID:         rb.BatchID,
+			BatchNumber:     rb.BatchNumber,
+			ProductID:       rb.ProductID,
+			ProductCode:     rb.Product.Code,
+			ProductName:     rb.Product.Name,
+			WarehouseID:     rb.WarehouseID,
+			WarehouseCode:   rb.Warehouse.Code,
+			WarehouseName:   rb.Warehouse.Name,
+			ExpiryDate:      rb.Batch.ExpiryDate,
+			HeldQuantity:    rb.HeldQuantity.String(),
+			CurrentQuantity: rb.Batch.Quantity.String(),
+		})
+
+		key := ""
+		if rb.GoodsReceiptID != nil {
+			key = *rb.GoodsReceiptID
+		} else if rb.SupplierID != nil {
+			key = "supplier:" + *rb.SupplierID
+		}
+
+		index, ok := sourceIndex[key]
+		if !ok {
+			source := dto.RecallSourceLine{
+				GoodsReceiptID:    rb.GoodsReceiptID,
+				SupplierID:        rb.SupplierID,
+				SupplierReference: rb.Batch.ReferenceNumber,
+				BatchNumbers:      []string{},
+				ReceivedQuantity:  "0",
+			}
+			if rb.GoodsReceipt != nil {
+				grnDate := rb.GoodsReceipt.GRNDate.Format("2006-01-02")
+				source.GRNNumber = &rb.GoodsReceipt.GRNNumber
+				source.GRNDate = &grnDate
+			}
+			if rb.Supplier != nil {
+				source.SupplierCode = &rb.Supplier.Code
+				source.SupplierName = &rb.Supplier.Name
+			}
+			report.Sources = append(report.Sources, source)
+			index = len(report.Sources) - 1
+			sourceIndex[key] = index
+		}
+
+		if !slices.Contains(report.Sources[index].BatchNumbers, rb.BatchNumber) {
+			report.Sources[index].BatchNumbers = append(report.Sources[index].BatchNumbers, rb.BatchNumber)
+		}
+		if rb.GoodsReceiptID != nil && !slices.Contains(productsBySource[key], rb.ProductID) {
+			productsBySource[key] = append(productsBySource[key], rb.ProductID)
+		}
+	}
+
+	// Quantity accepted on each source goods receipt for the recalled products
+	for key, index := range sourceIndex {
+		source := &report.Sources[index]
+		if source.GoodsReceiptID == nil {
+			continue
+		}
+
+		var received decimal.Decimal
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+			Model(&models.GoodsReceiptItem{}).
+			Select("COALESCE(SUM(base_accepted_qty), 0)").
+			Where("goods_receipt_id = ? AND product_id IN ?", *source.GoodsReceiptID, productsBySource[key]).
+			Scan(&received).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to load goods receipt quantity: %w", err)
+		}
+		source.ReceivedQuantity = received.String()
+	}
+
+	// Forward trace, grouped by customer
+	var recallDeliveries []models.ProductRecallDelivery
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Delivery").
+		Preload("Customer").
+		Preload("Batch").
+		Preload("Product").
+		Preload("ReturnDelivery").
+		Where("product_recall_id = ?", recall.ID).
+		Order("created_at ASC").
+		Find(&recallDeliveries).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load recalled deliveries: %w", err)
+	}
+
+	customerIndex := make(map[string]int)
+	customerTotals := make(map[string]decimal.Decimal)
+	for _, rd := range recallDeliveries {
+		index, ok := customerIndex[rd.CustomerID]
+		if !ok {
+			report.Customers = append(report.Customers, dto.RecallCustomerLine{
+				CustomerID:   rd.CustomerID,
+				CustomerCode: rd.Customer.Code,
+				CustomerName: rd.Customer.Name,
+				Phone:        rd.Customer.Phone,
+				Deliveries:   []dto.RecallDeliveryLine{},
+			})
+			index = len(report.Customers) - 1
+			customerIndex[rd.CustomerID] = index
+		}
+
+		line := dto.RecallDeliveryLine{
+			DeliveryID:       rd.DeliveryID,
+			DeliveryNumber:   rd.Delivery.DeliveryNumber,
+			DeliveryDate:     rd.Delivery.DeliveryDate.Format("2006-01-02"),
+			ProductID:        rd.ProductID,
+			ProductCode:      rd.Product.Code,
+			BatchNumber:      rd.Batch.BatchNumber,
+			Quantity:         rd.Quantity.String(),
+			ReturnDeliveryID: rd.ReturnDeliveryID,
+		}
+		if rd.ReturnDelivery != nil {
+			status := string(rd.ReturnDelivery.Status)
+			line.ReturnDeliveryNumber = &rd.ReturnDelivery.DeliveryNumber
+			line.ReturnStatus = &status
+		}
+
+		report.Customers[index].Deliveries = append(report.Customers[index].Deliveries, line)
+		customerTotals[rd.CustomerID] = customerTotals[rd.CustomerID].Add(rd.Quantity)
+	}
+
+	for i := range report.Customers {
+		report.Customers[i].TotalQuantity = customerTotals[report.Customers[i].CustomerID].String()
+	}
+
+	return recall, report, nil
+}
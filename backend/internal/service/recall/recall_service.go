@@ -0,0 +1,442 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/service/serial"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// RecallService - Product recall workflow.
+// A recall puts every batch of the recalled lot on hold (RECALLED) across warehouses,
+// traces the lot forward to the customers it was delivered to and back to the goods
+// receipt and supplier it came from, and opens a RETURN delivery per affected delivery.
+type RecallService struct {
+	db                  *gorm.DB
+	docNumberGen        *document.DocumentNumberGenerator
+	serialService       *serial.SerialService
+	stockPostingService *inventory.StockPostingService
+}
+
+// NewRecallService creates a new product recall service instance
+func NewRecallService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator) *RecallService {
+	return &RecallService{
+		db:                  db,
+		docNumberGen:        docNumberGen,
+		serialService:       serial.NewSerialService(db),
+		stockPostingService: inventory.NewStockPostingService(db),
+	}
+}
+
+// ============================================================================
+// RECALL OPERATIONS
+// ============================================================================
+
+// CreateRecall opens a recall for a lot, holds its stock and opens customer returns
+func (s *RecallService) CreateRecall(
+	ctx context.Context,
+	tenantID, companyID, userID string,
+	req *dto.CreateProductRecallRequest,
+) (*models.ProductRecall, error) {
+	hasBatchID := req.BatchID != nil && *req.BatchID != ""
+	hasBatchNumber := req.ProductID != nil && *req.ProductID != "" && req.BatchNumber != nil && *req.BatchNumber != ""
+	hasSupplierReference := req.SupplierReference != nil && *req.SupplierReference != ""
+	if !hasBatchID && !hasBatchNumber && !hasSupplierReference {
+		return nil, pkgerrors.NewBadRequestError("Specify batchId, productId with batchNumber, or supplierReference")
+	}
+
+	var recall *models.ProductRecall
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		recall = &models.ProductRecall{
+			TenantID:   tenantID,
+			CompanyID:  companyID,
+			RecallDate: time.Now(),
+			Reason:     req.Reason,
+			Status:     models.ProductRecallStatusOpen,
+			Notes:      req.Notes,
+		}
+		if userID != "" {
+			recall.CreatedBy = &userID
+		}
+
+		// 1. Resolve the lot to batches in every warehouse of the company
+		lotQuery := tx.Model(&models.ProductBatch{}).
+			Select("product_batches.*").
+			Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+			Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+			Where("warehouses.company_id = ?", companyID)
+
+		switch {
+		case hasBatchID:
+			var batch models.ProductBatch
+			if err := tx.Model(&models.ProductBatch{}).
+				Select("product_batches.*").
+				Joins("JOIN warehouse_stocks ON warehouse_stocks.id = product_batches.warehouse_stock_id").
+				Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+				Where("product_batches.id = ? AND warehouses.company_id = ?", *req.BatchID, companyID).
+				First(&batch).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return pkgerrors.NewNotFoundError("Batch")
+				}
+				return fmt.Errorf("failed to load batch: %w", err)
+			}
+			recall.ProductID = &batch.ProductID
+			recall.BatchNumber = &batch.BatchNumber
+			recall.SupplierID = batch.SupplierID
+			recall.SupplierReference = batch.ReferenceNumber
+			lotQuery = lotQuery.Where("product_batches.product_id = ? AND product_batches.batch_number = ?", batch.ProductID, batch.BatchNumber)
+
+		case hasBatchNumber:
+			recall.ProductID = req.ProductID
+			recall.BatchNumber = req.BatchNumber
+			lotQuery = lotQuery.Where("product_batches.product_id = ? AND product_batches.batch_number = ?", *req.ProductID, *req.BatchNumber)
+
+		default:
+			recall.SupplierReference = req.SupplierReference
+			lotQuery = lotQuery.Where("product_batches.reference_number = ?", *req.SupplierReference)
+			if req.SupplierID != nil && *req.SupplierID != "" {
+				recall.SupplierID = req.SupplierID
+				lotQuery = lotQuery.Where("product_batches.supplier_id = ?", *req.SupplierID)
+			}
+			if req.ProductID != nil && *req.ProductID != "" {
+				recall.ProductID = req.ProductID
+				lotQuery = lotQuery.Where("product_batches.product_id = ?", *req.ProductID)
+			}
+		}
+
+		var batches []models.ProductBatch
+		if err := lotQuery.Preload("WarehouseStock").Order("product_batches.created_at ASC").Find(&batches).Error; err != nil {
+			return fmt.Errorf("failed to resolve recalled batches: %w", err)
+		}
+		if len(batches) == 0 {
+			return pkgerrors.NewNotFoundError("Batches for the recalled lot")
+		}
+
+		batchIDs := make([]string, len(batches))
+		for i, batch := range batches {
+			batchIDs[i] = batch.ID
+		}
+
+		// 2. A batch can only be under one open recall
+		var existing models.ProductRecall
+		err := tx.Model(&models.ProductRecall{}).
+			Joins("JOIN product_recall_batches ON product_recall_batches.product_recall_id = product_recalls.id").
+			Where("product_recalls.company_id = ? AND product_recalls.status = ?", companyID, models.ProductRecallStatusOpen).
+			Where("product_recall_batches.batch_id IN ?", batchIDs).
+			First(&existing).Error
+		if err == nil {
+			return pkgerrors.NewConflictError(fmt.Sprintf("Lot is already under open recall %s", existing.RecallNumber))
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to check open recalls: %w", err)
+		}
+
+		// Numbered through the transaction: the return deliveries below are numbered in it too
+		recallNumber, err := s.docNumberGen.GenerateNumberTx(ctx, tx, tenantID, companyID, document.DocTypeProductRecall)
+		if err != nil {
+			return fmt.Errorf("failed to generate recall number: %w", err)
+		}
+		recall.RecallNumber = recallNumber
+
+		if err := tx.Create(recall).Error; err != nil {
+			return fmt.Errorf("failed to create recall: %w", err)
+		}
+
+		// 3. Hold stock: recalled batches can no longer be picked for sale or transfer
+		heldQty := decimal.Zero
+		for _, batch := range batches {
+			recallBatch := &models.ProductRecallBatch{
+				ProductRecallID: recall.ID,
+				BatchID:         batch.ID,
+				ProductID:       batch.ProductID,
+				WarehouseID:     batch.WarehouseStock.WarehouseID,
+				BatchNumber:     batch.BatchNumber,
+				PreviousStatus:  batch.Status,
+				HeldQuantity:    batch.Quantity,
+				GoodsReceiptID:  batch.GoodsReceiptID,
+				SupplierID:      batch.SupplierID,
+			}
+			if err := tx.Create(recallBatch).Error; err != nil {
+				return fmt.Errorf("failed to record recalled batch: %w", err)
+			}
+
+			// Through the posting service so the recalled quantity is held out of available stock
+			if _, err := s.stockPostingService.SetBatchStatus(tx, batch.ID, models.BatchStatusRecalled, nil); err != nil {
+				return fmt.Errorf("failed to hold batch %s: %w", batch.BatchNumber, err)
+			}
+			heldQty = heldQty.Add(batch.Quantity)
+		}
+
+		// 4. Trace forward to customers and open returns
+		deliveredQty, err := s.openCustomerReturns(ctx, tx, recall, batchIDs)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(recall).Updates(map[string]interface{}{
+			"held_quantity":      heldQty,
+			"delivered_quantity": deliveredQty,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRecallByID(ctx, tenantID, companyID, recall.ID)
+}
+
+// openCustomerReturns finds every shipped delivery line carrying a recalled batch and opens
+// one RETURN delivery per affected delivery for what the customer still holds: units
+// already on a customer return are left out.
+// Returns the quantity still at customers in base units.
+func (s *RecallService) openCustomerReturns(ctx context.Context, tx *gorm.DB, recall *models.ProductRecall, batchIDs []string) (decimal.Decimal, error) {
+	var items []models.DeliveryItem
+	if err := tx.Model(&models.DeliveryItem{}).
+		Select("delivery_items.*").
+		Joins("JOIN deliveries ON deliveries.id = delivery_items.delivery_id").
+		Where("deliveries.company_id = ? AND delivery_items.batch_id IN ?", recall.CompanyID, batchIDs).
+		Where("deliveries.type <> ? AND deliveries.status IN ?", models.DeliveryTypeReturn, []models.DeliveryStatus{
+			models.DeliveryStatusInTransit,
+			models.DeliveryStatusDelivered,
+			models.DeliveryStatusConfirmed,
+		}).
+		Preload("Delivery").
+		Order("deliveries.delivery_date ASC, delivery_items.created_at ASC").
+		Find(&items).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to trace recalled deliveries: %w", err)
+	}
+
+	// Base quantity still at the customer per delivery line
+	remaining := make(map[string]decimal.Decimal, len(items))
+	for _, item := range items {
+		var customerReturned decimal.Decimal
+		if err := tx.Model(&models.CustomerReturnItem{}).
+			Joins("JOIN customer_returns ON customer_returns.id = customer_return_items.customer_return_id").
+			Where("customer_return_items.delivery_item_id = ? AND customer_returns.status <> ?", item.ID, models.CustomerReturnStatusCancelled).
+			Select("COALESCE(SUM(customer_return_items.base_quantity), 0)").
+			Scan(&customerReturned).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to check customer returned quantity: %w", err)
+		}
+		deliveredBase := item.BaseQuantity
+		if deliveredBase.IsZero() {
+			deliveredBase = item.Quantity
+		}
+		remaining[item.ID] = deliveredBase.Sub(customerReturned)
+	}
+
+	// Group lines by delivery, keeping delivery order
+	var deliveryOrder []string
+	itemsByDelivery := make(map[string][]models.DeliveryItem)
+	for _, item := range items {
+		if !remaining[item.ID].IsPositive() {
+			continue
+		}
+		if _, ok := itemsByDelivery[item.DeliveryID]; !ok {
+			deliveryOrder = append(deliveryOrder, item.DeliveryID)
+		}
+		itemsByDelivery[item.DeliveryID] = append(itemsByDelivery[item.DeliveryID], item)
+	}
+
+	deliveredQty := decimal.Zero
+	for _, deliveryID := range deliveryOrder {
+		lines := itemsByDelivery[deliveryID]
+		original := lines[0].Delivery
+
+		// Same numbering as other deliveries, counting the returns already opened by this recall
+		returnNumber, err := s.docNumberGen.GenerateNumberTx(ctx, tx, recall.TenantID, recall.CompanyID, document.DocTypeDelivery)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to generate return delivery number: %w", err)
+		}
+
+		notes := fmt.Sprintf("Recall %s: return of %s delivered on %s", recall.RecallNumber, original.DeliveryNumber, original.DeliveryDate.Format("2006-01-02"))
+		returnDelivery := &models.Delivery{
+			TenantID:        recall.TenantID,
+			CompanyID:       recall.CompanyID,
+			DeliveryNumber:  returnNumber,
+			DeliveryDate:    recall.RecallDate,
+			SalesOrderID:    original.SalesOrderID,
+			WarehouseID:     original.WarehouseID,
+			CustomerID:      original.CustomerID,
+			Type:            models.DeliveryTypeReturn,
+			Status:          models.DeliveryStatusPrepared,
+			DeliveryAddress: original.DeliveryAddress,
+			Notes:           &notes,
+		}
+		if err := tx.Create(returnDelivery).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to create return delivery: %w", err)
+		}
+
+		for _, line := range lines {
+			baseQty := remaining[line.ID]
+			quantity := line.Quantity
+			if !line.BaseQuantity.IsZero() && !baseQty.Equal(line.BaseQuantity) {
+				quantity = baseQty.Mul(line.Quantity).Div(line.BaseQuantity).Round(3)
+			}
+			returnItem := &models.DeliveryItem{
+				DeliveryID:       returnDelivery.ID,
+				SalesOrderItemID: line.SalesOrderItemID,
+				ProductID:        line.ProductID,
+				ProductUnitID:    line.ProductUnitID,
+				BatchID:          line.BatchID,
+				Quantity:         quantity,
+				BaseQuantity:     baseQty,
+			}
+			if err := tx.Create(returnItem).Error; err != nil {
+				return decimal.Zero, fmt.Errorf("failed to create return delivery item: %w", err)
+			}
+
+			// Serial-tracked units come back with the serials they were delivered under,
+			// less those a customer return already took back
+			serialTracked, err := serial.RequiresSerials(tx, line.ProductID)
+			if err != nil {
+				return decimal.Zero, err
+			}
+			if serialTracked {
+				var returnedSerials []string
+				if err := tx.Model(&models.DocumentLineSerial{}).
+					Joins("JOIN customer_return_items ON customer_return_items.id = document_line_serials.line_id").
+					Joins("JOIN customer_returns ON customer_returns.id = customer_return_items.customer_return_id").
+					Where("document_line_serials.reference_type = ? AND customer_return_items.delivery_item_id = ?", inventory.ReferenceTypeCustomerReturn, line.ID).
+					Where("customer_returns.status <> ?", models.CustomerReturnStatusCancelled).
+					Pluck("document_line_serials.serial_id", &returnedSerials).Error; err != nil {
+					return decimal.Zero, fmt.Errorf("failed to load customer returned serials: %w", err)
+				}
+				if err := s.serialService.CopyLine(tx, inventory.ReferenceTypeDelivery, line.ID, inventory.ReferenceTypeDelivery, returnDelivery.ID, returnItem.ID, returnedSerials...); err != nil {
+					return decimal.Zero, err
+				}
+			}
+
+			recallDelivery := &models.ProductRecallDelivery{
+				ProductRecallID:  recall.ID,
+				DeliveryID:       line.DeliveryID,
+				DeliveryItemID:   line.ID,
+				CustomerID:       original.CustomerID,
+				BatchID:          *line.BatchID,
+				ProductID:        line.ProductID,
+				Quantity:         baseQty,
+				ReturnDeliveryID: &returnDelivery.ID,
+			}
+			if err := tx.Create(recallDelivery).Error; err != nil {
+				return decimal.Zero, fmt.Errorf("failed to record recalled delivery: %w", err)
+			}
+			deliveredQty = deliveredQty.Add(baseQty)
+		}
+	}
+
+	return deliveredQty, nil
+}
+
+// CloseRecall closes an open recall (OPEN → CLOSED).
+// Recalled batches stay on hold; remaining stock is disposed of through adjustments.
+func (s *RecallService) CloseRecall(
+	ctx context.Context,
+	tenantID, companyID, recallID, userID string,
+	req *dto.CloseProductRecallRequest,
+) (*models.ProductRecall, error) {
+	recall, err := s.GetRecallByID(ctx, tenantID, companyID, recallID)
+	if err != nil {
+		return nil, err
+	}
+	if recall.Status != models.ProductRecallStatusOpen {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Recall is already %s", recall.Status))
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":    models.ProductRecallStatusClosed,
+		"closed_at": now,
+	}
+	if userID != "" {
+		updates["closed_by"] = userID
+	}
+	if req != nil && req.Notes != nil && *req.Notes != "" {
+		updates["notes"] = *req.Notes
+	}
+
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Model(&models.ProductRecall{}).Where("id = ?", recall.ID).
+		Updates(updates).Error; err != nil {
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	return s.GetRecallByID(ctx, tenantID, companyID, recall.ID)
+}
+
+// GetRecallByID retrieves a recall with its batches and traced deliveries
+func (s *RecallService) GetRecallByID(ctx context.Context, tenantID, companyID, recallID string) (*models.ProductRecall, error) {
+	var recall models.ProductRecall
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Product").
+		Preload("Batches").
+		Preload("Deliveries").
+		Where("id = ? AND company_id = ?", recallID, companyID).
+		First(&recall).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("Recall")
+		}
+		return nil, pkgerrors.NewInternalError(err)
+	}
+
+	return &recall, nil
+}
+
+// ListRecalls retrieves recalls with filtering and pagination
+func (s *RecallService) ListRecalls(
+	ctx context.Context,
+	tenantID, companyID string,
+	query *dto.ProductRecallQuery,
+) ([]models.ProductRecall, *dto.PaginationInfo, error) {
+	var recalls []models.ProductRecall
+	var total int64
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("company_id = ?", companyID)
+
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+	if query.ProductID != nil {
+		db = db.Where("product_id = ?", *query.ProductID)
+	}
+	if query.Search != "" {
+		search := "%" + query.Search + "%"
+		db = db.Where("recall_number LIKE ? OR batch_number LIKE ? OR supplier_reference LIKE ?", search, search, search)
+	}
+
+	if err := db.Model(&models.ProductRecall{}).Count(&total).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Order("recall_date DESC").
+		Offset(offset).Limit(query.PageSize).
+		Preload("Product").
+		Preload("Batches").
+		Preload("Deliveries").
+		Find(&recalls).Error; err != nil {
+		return nil, nil, pkgerrors.NewInternalError(err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	pagination := &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return recalls, pagination, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
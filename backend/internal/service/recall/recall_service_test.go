@@ -0,0 +1,233 @@
+package recall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecallService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.StockReservation{},
+		&models.CostLayer{},
+		&models.GoodsReceipt{},
+		&models.GoodsReceiptItem{},
+		&models.Delivery{},
+		&models.DeliveryItem{},
+		&models.ProductRecall{},
+		&models.ProductRecallBatch{},
+		&models.ProductRecallDelivery{},
+		&models.CustomerReturn{},
+		&models.CustomerReturnItem{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	mainWarehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	branchWarehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH002")
+
+	product := &models.Product{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		Code:           "PROD001",
+		Name:           "Minyak Goreng 1L",
+		BaseUnit:       "PCS",
+		IsBatchTracked: true,
+		IsActive:       true,
+	}
+	require.NoError(t, db.Create(product).Error)
+
+	supplier := &models.Supplier{TenantID: company.TenantID, CompanyID: company.ID, Code: "SUP001", Name: "PT Sumber Minyak", IsActive: true}
+	require.NoError(t, db.Create(supplier).Error)
+
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+
+	goodsReceipt := &models.GoodsReceipt{
+		TenantID:        company.TenantID,
+		CompanyID:       company.ID,
+		GRNNumber:       "GRN-2026-00001",
+		GRNDate:         time.Now().AddDate(0, 0, -10),
+		PurchaseOrderID: "po-1",
+		WarehouseID:     mainWarehouse.ID,
+		SupplierID:      supplier.ID,
+		Status:          models.GoodsReceiptStatusAccepted,
+	}
+	require.NoError(t, db.Create(goodsReceipt).Error)
+	require.NoError(t, db.Create(&models.GoodsReceiptItem{
+		GoodsReceiptID:      goodsReceipt.ID,
+		PurchaseOrderItemID: "po-item-1",
+		ProductID:           product.ID,
+		OrderedQty:          decimal.NewFromInt(15),
+		AcceptedQty:         decimal.NewFromInt(15),
+		BaseAcceptedQty:     decimal.NewFromInt(15),
+	}).Error)
+
+	// Lot received in the main warehouse, part of it moved to the branch
+	postingService := inventory.NewStockPostingService(db)
+	lotNumber := "SUP-LOT-77"
+	receive := func(warehouseID string, qty int64) *models.ProductBatch {
+		result, err := postingService.Post(db, &inventory.StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  warehouseID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeIn,
+			Quantity:     decimal.NewFromInt(qty),
+			Batch: &inventory.BatchDetails{
+				BatchNumber:     "B-001",
+				SupplierID:      &supplier.ID,
+				GoodsReceiptID:  &goodsReceipt.ID,
+				ReferenceNumber: &lotNumber,
+			},
+		})
+		require.NoError(t, err)
+		return result.Batch
+	}
+	mainBatch := receive(mainWarehouse.ID, 10)
+	branchBatch := receive(branchWarehouse.ID, 5)
+
+	// 3 units already delivered to a customer from the main warehouse
+	delivery := &models.Delivery{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		DeliveryNumber: "DEL/2026/10/0001",
+		DeliveryDate:   time.Now().AddDate(0, 0, -2),
+		SalesOrderID:   "so-1",
+		WarehouseID:    mainWarehouse.ID,
+		CustomerID:     customer.ID,
+		Type:           models.DeliveryTypeNormal,
+		Status:         models.DeliveryStatusDelivered,
+	}
+	require.NoError(t, db.Create(delivery).Error)
+	require.NoError(t, db.Create(&models.DeliveryItem{
+		DeliveryID:       delivery.ID,
+		SalesOrderItemID: "so-item-1",
+		ProductID:        product.ID,
+		BatchID:          &mainBatch.ID,
+		Quantity:         decimal.NewFromInt(3),
+		BaseQuantity:     decimal.NewFromInt(3),
+	}).Error)
+	_, err := postingService.Post(db, &inventory.StockPosting{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		WarehouseID:  mainWarehouse.ID,
+		ProductID:    product.ID,
+		MovementType: models.MovementTypeOut,
+		Quantity:     decimal.NewFromInt(-3),
+		BatchID:      &mainBatch.ID,
+	})
+	require.NoError(t, err)
+
+	service := NewRecallService(db, document.NewDocumentNumberGenerator(db))
+
+	t.Run("error - no lot given", func(t *testing.T) {
+		_, err := service.CreateRecall(ctx, company.TenantID, company.ID, "user1", &dto.CreateProductRecallRequest{Reason: "Contamination"})
+		assert.Error(t, err)
+	})
+
+	var recallID string
+	t.Run("success - recall by supplier lot holds stock and opens returns", func(t *testing.T) {
+		recall, err := service.CreateRecall(ctx, company.TenantID, company.ID, "user1", &dto.CreateProductRecallRequest{
+			SupplierReference: &lotNumber,
+			Reason:            "Supplier contamination notice",
+		})
+
+		require.NoError(t, err)
+		recallID = recall.ID
+		assert.Equal(t, models.ProductRecallStatusOpen, recall.Status)
+		assert.Equal(t, "RCL/"+time.Now().Format("2006/01")+"/0001", recall.RecallNumber)
+		assert.Len(t, recall.Batches, 2)
+		assert.Equal(t, "12", recall.HeldQuantity.String())
+		assert.Equal(t, "3", recall.DeliveredQuantity.String())
+		require.Len(t, recall.Deliveries, 1)
+		require.NotNil(t, recall.Deliveries[0].ReturnDeliveryID)
+
+		for _, batchID := range []string{mainBatch.ID, branchBatch.ID} {
+			var batch models.ProductBatch
+			require.NoError(t, db.First(&batch, "id = ?", batchID).Error)
+			assert.Equal(t, models.BatchStatusRecalled, batch.Status)
+		}
+
+		var returnDelivery models.Delivery
+		require.NoError(t, db.Preload("Items").First(&returnDelivery, "id = ?", *recall.Deliveries[0].ReturnDeliveryID).Error)
+		assert.Equal(t, models.DeliveryTypeReturn, returnDelivery.Type)
+		assert.Equal(t, models.DeliveryStatusPrepared, returnDelivery.Status)
+		// Numbered after the delivery it returns
+		assert.Equal(t, "DEL/"+time.Now().Format("2006/01")+"/0002", returnDelivery.DeliveryNumber)
+		assert.Equal(t, customer.ID, returnDelivery.CustomerID)
+		require.Len(t, returnDelivery.Items, 1)
+		assert.Equal(t, mainBatch.ID, *returnDelivery.Items[0].BatchID)
+		assert.Equal(t, "3", returnDelivery.Items[0].Quantity.String())
+	})
+
+	t.Run("error - recalled batch cannot be sold", func(t *testing.T) {
+		_, err := postingService.Post(db, &inventory.StockPosting{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			WarehouseID:  branchWarehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeOut,
+			Quantity:     decimal.NewFromInt(-1),
+			BatchID:      &branchBatch.ID,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("error - lot already under open recall", func(t *testing.T) {
+		_, err := service.CreateRecall(ctx, company.TenantID, company.ID, "user1", &dto.CreateProductRecallRequest{
+			BatchID: &branchBatch.ID,
+			Reason:  "Duplicate",
+		})
+
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 409, appErr.StatusCode)
+	})
+
+	t.Run("success - recall report", func(t *testing.T) {
+		_, report, err := service.GetRecallReport(ctx, company.TenantID, company.ID, recallID)
+
+		require.NoError(t, err)
+		assert.Len(t, report.Holds, 2)
+
+		require.Len(t, report.Sources, 1)
+		assert.Equal(t, "GRN-2026-00001", *report.Sources[0].GRNNumber)
+		assert.Equal(t, "PT Sumber Minyak", *report.Sources[0].SupplierName)
+		assert.Equal(t, []string{"B-001"}, report.Sources[0].BatchNumbers)
+		assert.Equal(t, "15", report.Sources[0].ReceivedQuantity)
+
+		require.Len(t, report.Customers, 1)
+		assert.Equal(t, "Toko Makmur", report.Customers[0].CustomerName)
+		assert.Equal(t, "3", report.Customers[0].TotalQuantity)
+		require.Len(t, report.Customers[0].Deliveries, 1)
+		assert.Equal(t, "DEL/2026/10/0001", report.Customers[0].Deliveries[0].DeliveryNumber)
+		assert.Equal(t, "PREPARED", *report.Customers[0].Deliveries[0].ReturnStatus)
+	})
+
+	t.Run("success - close recall", func(t *testing.T) {
+		recall, err := service.CloseRecall(ctx, company.TenantID, company.ID, recallID, "user1", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ProductRecallStatusClosed, recall.Status)
+		assert.NotNil(t, recall.ClosedAt)
+
+		_, err = service.CloseRecall(ctx, company.TenantID, company.ID, recallID, "user1", nil)
+		assert.Error(t, err)
+	})
+}
@@ -10,19 +10,28 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/service/serial"
+	"backend/internal/service/uom"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 type DeliveryService struct {
-	db           *gorm.DB
-	docNumberGen *document.DocumentNumberGenerator
+	db                  *gorm.DB
+	docNumberGen        *document.DocumentNumberGenerator
+	stockPostingService *inventory.StockPostingService
+	uomService          *uom.UOMService
+	serialService       *serial.SerialService
 }
 
-func NewDeliveryService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator) *DeliveryService {
+func NewDeliveryService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator, stockPostingService *inventory.StockPostingService) *DeliveryService {
 	return &DeliveryService{
-		db:           db,
-		docNumberGen: docNumberGen,
+		db:                  db,
+		docNumberGen:        docNumberGen,
+		stockPostingService: stockPostingService,
+		uomService:          uom.NewUOMService(db),
+		serialService:       serial.NewSerialService(db),
 	}
 }
 
@@ -85,6 +94,9 @@ func (s *DeliveryService) CreateDelivery(ctx context.Context, companyID string,
 			}
 			return fmt.Errorf("failed to verify warehouse: %w", err)
 		}
+		if warehouse.Type == models.WarehouseTypeConsignment {
+			return pkgerrors.NewBadRequestError("stock in a CONSIGNMENT warehouse is sold through consignment settlements, not deliveries")
+		}
 
 		// 4. Generate delivery number
 		deliveryNumber, err := s.docNumberGen.GenerateNumber(ctx, tenantID, companyID, document.DocTypeDelivery)
@@ -152,15 +164,10 @@ func (s *DeliveryService) CreateDelivery(ctx context.Context, companyID string,
 				salesOrderItemID = soItem.ID
 			}
 
-			// Verify product unit if provided
-			if itemReq.ProductUnitId != nil && *itemReq.ProductUnitId != "" {
-				var productUnit models.ProductUnit
-				if err := tx.Where("id = ? AND product_id = ?", *itemReq.ProductUnitId, itemReq.ProductId).First(&productUnit).Error; err != nil {
-					if err == gorm.ErrRecordNotFound {
-						return pkgerrors.NewNotFoundError(fmt.Sprintf("Product unit %s not found", *itemReq.ProductUnitId))
-					}
-					return fmt.Errorf("failed to verify product unit: %w", err)
-				}
+			// Validate the entered unit and keep the base-unit quantity alongside
+			unit, baseQty, err := s.uomService.ConvertQuantity(tx, itemReq.ProductId, itemReq.ProductUnitId, quantity)
+			if err != nil {
+				return err
 			}
 
 			// Create delivery item
@@ -168,15 +175,20 @@ func (s *DeliveryService) CreateDelivery(ctx context.Context, companyID string,
 				DeliveryID:       delivery.ID,
 				SalesOrderItemID: salesOrderItemID,
 				ProductID:        itemReq.ProductId,
-				ProductUnitID:    itemReq.ProductUnitId,
+				ProductUnitID:    unit.ProductUnitID,
 				BatchID:          itemReq.BatchId,
 				Quantity:         quantity,
+				BaseQuantity:     baseQty,
 				Notes:            itemReq.Notes,
 			}
 
 			if err := tx.Create(deliveryItem).Error; err != nil {
 				return fmt.Errorf("failed to create delivery item: %w", err)
 			}
+
+			if err := s.assignSerials(tx, delivery, &product, deliveryItem, itemReq.SerialNumbers); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -325,7 +337,7 @@ func (s *DeliveryService) ListDeliveries(ctx context.Context, companyID string,
 // ============================================================================
 
 // UpdateDeliveryStatus updates delivery status and related fields
-func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, companyID string, tenantID string, deliveryID string, req *dto.UpdateDeliveryStatusRequest) (*models.Delivery, error) {
+func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, companyID string, tenantID string, userID string, deliveryID string, req *dto.UpdateDeliveryStatusRequest) (*models.Delivery, error) {
 	var delivery *models.Delivery
 
 	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
@@ -372,6 +384,17 @@ func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, companyID st
 			}
 
 			updates["status"] = newStatus
+
+			// Stock leaves the warehouse once the delivery departs, and comes back if cancelled afterwards
+			if !isStockPosted(delivery.Status) && isStockPosted(newStatus) {
+				if err := s.postStockOut(tx, delivery, time.Now(), userID); err != nil {
+					return err
+				}
+			} else if isStockPosted(delivery.Status) && newStatus == models.DeliveryStatusCancelled {
+				if err := s.postStockBack(tx, delivery, userID); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Update timestamps
@@ -427,7 +450,7 @@ func (s *DeliveryService) UpdateDeliveryStatus(ctx context.Context, companyID st
 }
 
 // StartDelivery moves delivery from PREPARED to IN_TRANSIT
-func (s *DeliveryService) StartDelivery(ctx context.Context, companyID string, tenantID string, deliveryID string, req *dto.StartDeliveryRequest) (*models.Delivery, error) {
+func (s *DeliveryService) StartDelivery(ctx context.Context, companyID string, tenantID string, userID string, deliveryID string, req *dto.StartDeliveryRequest) (*models.Delivery, error) {
 	// Parse departure time
 	departureTime, err := time.Parse(time.RFC3339, req.DepartureTime)
 	if err != nil {
@@ -450,6 +473,11 @@ func (s *DeliveryService) StartDelivery(ctx context.Context, companyID string, t
 			return pkgerrors.NewBadRequestError("delivery must be in PREPARED status to start")
 		}
 
+		// Deduct stock from the source warehouse
+		if err := s.postStockOut(tx, delivery, departureTime, userID); err != nil {
+			return err
+		}
+
 		// Update status and departure time
 		updates := map[string]interface{}{
 			"status":         models.DeliveryStatusInTransit,
@@ -559,7 +587,7 @@ func (s *DeliveryService) ConfirmDelivery(ctx context.Context, companyID string,
 }
 
 // CancelDelivery cancels a delivery
-func (s *DeliveryService) CancelDelivery(ctx context.Context, companyID string, tenantID string, deliveryID string, req *dto.CancelDeliveryRequest) (*models.Delivery, error) {
+func (s *DeliveryService) CancelDelivery(ctx context.Context, companyID string, tenantID string, userID string, deliveryID string, req *dto.CancelDeliveryRequest) (*models.Delivery, error) {
 	var delivery *models.Delivery
 
 	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
@@ -580,6 +608,13 @@ func (s *DeliveryService) CancelDelivery(ctx context.Context, companyID string,
 			return pkgerrors.NewBadRequestError("cannot cancel confirmed delivery")
 		}
 
+		// Return stock to the source warehouse if it already left
+		if isStockPosted(delivery.Status) {
+			if err := s.postStockBack(tx, delivery, userID); err != nil {
+				return err
+			}
+		}
+
 		// Update status and notes
 		updates := map[string]interface{}{
 			"status": models.DeliveryStatusCancelled,
@@ -604,6 +639,354 @@ func (s *DeliveryService) CancelDelivery(ctx context.Context, companyID string,
 	return s.GetDeliveryByID(ctx, companyID, tenantID, deliveryID)
 }
 
+// ============================================================================
+// STOCK POSTING
+// ============================================================================
+
+// isStockPosted reports whether stock has already been deducted for a delivery in this status
+func isStockPosted(status models.DeliveryStatus) bool {
+	switch status {
+	case models.DeliveryStatusInTransit, models.DeliveryStatusDelivered, models.DeliveryStatusConfirmed:
+		return true
+	}
+	return false
+}
+
+// postStockOut deducts stock for every delivery item from the source warehouse,
+// consuming the sales order reservation for the line.
+// Batch-tracked items without a batch are picked FEFO; when more than one batch is needed
+// the item is split so each DeliveryItem row points at exactly one batch.
+// Stock is picked from the warehouse bins in pick order before unbinned stock.
+// RETURN deliveries bring the goods back in instead (see postReturnStock).
+func (s *DeliveryService) postStockOut(tx *gorm.DB, delivery *models.Delivery, movementDate time.Time, userID string) error {
+	if delivery.Type == models.DeliveryTypeReturn {
+		return s.postReturnStock(tx, delivery, movementDate, false, userID)
+	}
+
+	var items []models.DeliveryItem
+	if err := tx.Preload("Product").Preload("ProductUnit").
+		Where("delivery_id = ?", delivery.ID).
+		Find(&items).Error; err != nil {
+		return fmt.Errorf("failed to load delivery items: %w", err)
+	}
+
+	for _, item := range items {
+		baseQty := item.BaseQuantity
+
+		// Free this line's reservation first so the delivery can take the reserved stock
+		if err := s.stockPostingService.ConsumeReservation(tx, item.SalesOrderItemID, baseQty); err != nil {
+			return err
+		}
+
+		posting := &inventory.StockPosting{
+			TenantID:        delivery.TenantID,
+			CompanyID:       delivery.CompanyID,
+			WarehouseID:     delivery.WarehouseID,
+			ProductID:       item.ProductID,
+			MovementType:    models.MovementTypeOut,
+			Quantity:        baseQty.Neg(),
+			MovementDate:    movementDate,
+			BatchID:         item.BatchID,
+			ReferenceType:   inventory.ReferenceTypeDelivery,
+			ReferenceID:     delivery.ID,
+			ReferenceNumber: delivery.DeliveryNumber,
+			Notes:           item.Notes,
+			CreatedBy:       userID,
+
+			RespectReservations: true,
+		}
+
+		if item.BatchID != nil || !item.Product.IsBatchTracked {
+			if _, err := s.stockPostingService.PostFromBins(tx, posting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// FEFO batch picking
+		allocations, err := s.stockPostingService.PickBatchesFEFO(tx, delivery.WarehouseID, item.ProductID, baseQty)
+		if err != nil {
+			return err
+		}
+
+		for i, alloc := range allocations {
+			batchID := alloc.Batch.ID
+			posting.BatchID = &batchID
+			posting.Quantity = alloc.Quantity.Neg()
+			if _, err := s.stockPostingService.PostFromBins(tx, posting); err != nil {
+				return err
+			}
+
+			itemQty := uom.FromProductUnit(item.ProductUnit).FromBase(alloc.Quantity)
+			if i == 0 {
+				if err := tx.Model(&models.DeliveryItem{}).Where("id = ?", item.ID).
+					Updates(map[string]interface{}{
+						"batch_id":      batchID,
+						"quantity":      itemQty,
+						"base_quantity": alloc.Quantity,
+					}).Error; err != nil {
+					return fmt.Errorf("failed to assign batch to delivery item: %w", err)
+				}
+				continue
+			}
+
+			splitItem := &models.DeliveryItem{
+				DeliveryID:       item.DeliveryID,
+				SalesOrderItemID: item.SalesOrderItemID,
+				ProductID:        item.ProductID,
+				ProductUnitID:    item.ProductUnitID,
+				BatchID:          &batchID,
+				Quantity:         itemQty,
+				BaseQuantity:     alloc.Quantity,
+				Notes:            item.Notes,
+			}
+			if err := tx.Create(splitItem).Error; err != nil {
+				return fmt.Errorf("failed to split delivery item by batch: %w", err)
+			}
+		}
+	}
+
+	// The customer now holds the units; their warranty starts at the delivery date
+	deliveryDate := delivery.DeliveryDate
+	return s.moveSerials(tx, delivery, items, userID, &serial.SerialMove{
+		EventType:         models.SerialEventDelivered,
+		EventDate:         movementDate,
+		FromStatus:        models.SerialStatusInStock,
+		FromWarehouseID:   &delivery.WarehouseID,
+		Status:            models.SerialStatusDelivered,
+		CustomerID:        &delivery.CustomerID,
+		DeliveryID:        &delivery.ID,
+		WarrantyStartDate: &deliveryDate,
+	})
+}
+
+// postStockBack returns the stock of a cancelled delivery to the source warehouse
+// and re-reserves it for the sales order. Stock goes back to the bins it was picked from.
+func (s *DeliveryService) postStockBack(tx *gorm.DB, delivery *models.Delivery, userID string) error {
+	if delivery.Type == models.DeliveryTypeReturn {
+		return s.postReturnStock(tx, delivery, time.Now(), true, userID)
+	}
+
+	var items []models.DeliveryItem
+	if err := tx.Preload("Product").Where("delivery_id = ?", delivery.ID).
+		Find(&items).Error; err != nil {
+		return fmt.Errorf("failed to load delivery items: %w", err)
+	}
+
+	notes := fmt.Sprintf("Delivery %s cancelled", delivery.DeliveryNumber)
+	pickedBins := make(map[string][]inventory.BinAllocation)
+	for _, item := range items {
+		baseQty := item.BaseQuantity
+
+		// Bring the stock back at the cost the delivery consumed
+		unitCost, err := s.stockPostingService.OutboundUnitCost(tx, inventory.ReferenceTypeDelivery, delivery.ID, delivery.WarehouseID, item.ProductID)
+		if err != nil {
+			return err
+		}
+
+		binKey := item.ProductID
+		if item.BatchID != nil {
+			binKey += "/" + *item.BatchID
+		}
+		bins, ok := pickedBins[binKey]
+		if !ok {
+			bins, err = s.stockPostingService.PickedBins(tx, inventory.ReferenceTypeDelivery, delivery.ID, delivery.WarehouseID, item.ProductID, item.BatchID)
+			if err != nil {
+				return err
+			}
+		}
+
+		var returned []inventory.BinAllocation
+		returned, pickedBins[binKey] = takeBins(bins, baseQty)
+		for _, alloc := range returned {
+			posting := &inventory.StockPosting{
+				TenantID:        delivery.TenantID,
+				CompanyID:       delivery.CompanyID,
+				WarehouseID:     delivery.WarehouseID,
+				ProductID:       item.ProductID,
+				MovementType:    models.MovementTypeReturn,
+				Quantity:        alloc.Quantity,
+				UnitCost:        unitCost,
+				BatchID:         item.BatchID,
+				ReferenceType:   inventory.ReferenceTypeDelivery,
+				ReferenceID:     delivery.ID,
+				ReferenceNumber: delivery.DeliveryNumber,
+				Notes:           &notes,
+				CreatedBy:       userID,
+			}
+			if alloc.Bin != nil {
+				posting.BinID = &alloc.Bin.ID
+			}
+			if _, err := s.stockPostingService.Post(tx, posting); err != nil {
+				return err
+			}
+		}
+
+		// Hold the returned stock for the sales order again
+		if err := s.stockPostingService.RestoreReservation(tx, item.SalesOrderItemID, baseQty); err != nil {
+			return err
+		}
+	}
+
+	return s.moveSerials(tx, delivery, items, userID, &serial.SerialMove{
+		EventType:     models.SerialEventDeliveryCancelled,
+		EventDate:     time.Now(),
+		FromStatus:    models.SerialStatusDelivered,
+		Status:        models.SerialStatusInStock,
+		WarehouseID:   &delivery.WarehouseID,
+		Notes:         &notes,
+		ClearDelivery: true,
+	})
+}
+
+// takeBins splits qty over the given bins in order and returns the split plus the bin quantity
+// left over. Quantity the bins cannot cover comes back as an unbinned allocation.
+func takeBins(bins []inventory.BinAllocation, qty decimal.Decimal) ([]inventory.BinAllocation, []inventory.BinAllocation) {
+	var taken []inventory.BinAllocation
+	remaining := qty
+	rest := make([]inventory.BinAllocation, 0, len(bins))
+	for _, bin := range bins {
+		take := decimal.Min(bin.Quantity, remaining)
+		if take.IsPositive() {
+			taken = append(taken, inventory.BinAllocation{Bin: bin.Bin, Quantity: take})
+			remaining = remaining.Sub(take)
+		}
+		if left := bin.Quantity.Sub(take); left.IsPositive() {
+			rest = append(rest, inventory.BinAllocation{Bin: bin.Bin, Quantity: left})
+		}
+	}
+
+	if remaining.IsPositive() {
+		taken = append(taken, inventory.BinAllocation{Quantity: remaining})
+	}
+	return taken, rest
+}
+
+// postReturnStock books the goods of a RETURN delivery back into the warehouse, or takes
+// them out again when the return is cancelled. Returned batches keep their status, so
+// recalled or damaged stock stays blocked from picking.
+func (s *DeliveryService) postReturnStock(tx *gorm.DB, delivery *models.Delivery, movementDate time.Time, reverse bool, userID string) error {
+	var items []models.DeliveryItem
+	if err := tx.Preload("Product").Where("delivery_id = ?", delivery.ID).
+		Find(&items).Error; err != nil {
+		return fmt.Errorf("failed to load delivery items: %w", err)
+	}
+
+	for _, item := range items {
+		baseQty := item.BaseQuantity
+		notes := item.Notes
+		if reverse {
+			baseQty = baseQty.Neg()
+			cancelNote := fmt.Sprintf("Return %s cancelled", delivery.DeliveryNumber)
+			notes = &cancelNote
+		}
+
+		if _, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:        delivery.TenantID,
+			CompanyID:       delivery.CompanyID,
+			WarehouseID:     delivery.WarehouseID,
+			ProductID:       item.ProductID,
+			MovementType:    models.MovementTypeReturn,
+			Quantity:        baseQty,
+			MovementDate:    movementDate,
+			BatchID:         item.BatchID,
+			ReferenceType:   inventory.ReferenceTypeDelivery,
+			ReferenceID:     delivery.ID,
+			ReferenceNumber: delivery.DeliveryNumber,
+			Notes:           notes,
+			CreatedBy:       userID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Returned units are back in stock; a cancelled return leaves them with the customer again
+	move := &serial.SerialMove{
+		EventType:   models.SerialEventReturned,
+		EventDate:   movementDate,
+		FromStatus:  models.SerialStatusDelivered,
+		Status:      models.SerialStatusInStock,
+		WarehouseID: &delivery.WarehouseID,
+	}
+	if reverse {
+		move = &serial.SerialMove{
+			EventType:  models.SerialEventReturnCancelled,
+			EventDate:  movementDate,
+			FromStatus: models.SerialStatusInStock,
+			Status:     models.SerialStatusDelivered,
+			CustomerID: &delivery.CustomerID,
+		}
+	}
+	return s.moveSerials(tx, delivery, items, userID, move)
+}
+
+// assignSerials links the serials entered for a delivery line to it.
+// Outgoing deliveries take units from the delivery warehouse; returns take units the
+// customer holds. A batch-tracked line without a batch is pinned to the serials' batch.
+func (s *DeliveryService) assignSerials(tx *gorm.DB, delivery *models.Delivery, product *models.Product, item *models.DeliveryItem, serialNumbers []string) error {
+	if !product.IsSerialTracked {
+		if len(serialNumbers) > 0 {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("product %s is not serial-tracked", product.Code))
+		}
+		return nil
+	}
+
+	assignment := &serial.SerialAssignment{
+		CompanyID:     delivery.CompanyID,
+		ProductID:     item.ProductID,
+		ReferenceType: inventory.ReferenceTypeDelivery,
+		ReferenceID:   delivery.ID,
+		LineID:        item.ID,
+		Quantity:      item.BaseQuantity,
+		SerialNumbers: serialNumbers,
+		BatchID:       item.BatchID,
+	}
+	if delivery.Type == models.DeliveryTypeReturn {
+		assignment.Status = models.SerialStatusDelivered
+		assignment.CustomerID = &delivery.CustomerID
+	} else {
+		assignment.Status = models.SerialStatusInStock
+		assignment.WarehouseID = &delivery.WarehouseID
+	}
+
+	batchID, err := s.serialService.Assign(tx, assignment)
+	if err != nil {
+		return err
+	}
+
+	if product.IsBatchTracked && item.BatchID == nil {
+		if batchID == nil {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("serials of product %s come from several batches; enter one line per batch", product.Code))
+		}
+		if err := tx.Model(item).Update("batch_id", *batchID).Error; err != nil {
+			return fmt.Errorf("failed to assign batch to delivery item: %w", err)
+		}
+	}
+	return nil
+}
+
+// moveSerials moves the serials of the serial-tracked lines after the delivery's stock is posted.
+// Items must have Product loaded.
+func (s *DeliveryService) moveSerials(tx *gorm.DB, delivery *models.Delivery, items []models.DeliveryItem, userID string, move *serial.SerialMove) error {
+	move.TenantID = delivery.TenantID
+	move.CompanyID = delivery.CompanyID
+	move.ReferenceType = inventory.ReferenceTypeDelivery
+	move.ReferenceID = delivery.ID
+	move.ReferenceNumber = delivery.DeliveryNumber
+	if userID != "" {
+		move.CreatedBy = &userID
+	}
+	for _, item := range items {
+		if !item.Product.IsSerialTracked {
+			continue
+		}
+		if err := s.serialService.MoveLine(tx, item.ID, move); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ============================================================================
 // HELPER FUNCTIONS
 // ============================================================================
@@ -10,19 +10,25 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/document"
+	"backend/internal/service/inventory"
+	"backend/internal/service/uom"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 type SalesOrderService struct {
-	db           *gorm.DB
-	docNumberGen *document.DocumentNumberGenerator
+	db                  *gorm.DB
+	docNumberGen        *document.DocumentNumberGenerator
+	stockPostingService *inventory.StockPostingService
+	uomService          *uom.UOMService
 }
 
-func NewSalesOrderService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator) *SalesOrderService {
+func NewSalesOrderService(db *gorm.DB, docNumberGen *document.DocumentNumberGenerator, stockPostingService *inventory.StockPostingService) *SalesOrderService {
 	return &SalesOrderService{
-		db:           db,
-		docNumberGen: docNumberGen,
+		db:                  db,
+		docNumberGen:        docNumberGen,
+		stockPostingService: stockPostingService,
+		uomService:          uom.NewUOMService(db),
 	}
 }
 
@@ -155,26 +161,22 @@ func (s *SalesOrderService) CreateSalesOrder(ctx context.Context, companyID stri
 				return fmt.Errorf("failed to verify product: %w", err)
 			}
 
+			// Validate the entered unit and keep the base-unit quantity alongside
+			unit, baseQty, err := s.uomService.ConvertQuantity(tx, itemReq.ProductId, &itemReq.UnitId, quantity)
+			if err != nil {
+				return err
+			}
+
 			item := &models.SalesOrderItem{
-				SalesOrderID: salesOrder.ID,
-				ProductID:    itemReq.ProductId,
-				Quantity:     quantity,
-				UnitPrice:    unitPrice,
-				DiscountAmt:  itemDiscount,
-				Subtotal:     lineTotal,
-				Notes:        itemReq.Notes,
-			}
-
-			// Handle unit if specified
-			if itemReq.UnitId != "" {
-				var productUnit models.ProductUnit
-				if err := tx.Where("id = ? AND product_id = ?", itemReq.UnitId, itemReq.ProductId).First(&productUnit).Error; err != nil {
-					if err == gorm.ErrRecordNotFound {
-						return pkgerrors.NewNotFoundError(fmt.Sprintf("Product unit %s not found", itemReq.UnitId))
-					}
-					return fmt.Errorf("failed to verify product unit: %w", err)
-				}
-				item.ProductUnitID = &itemReq.UnitId
+				SalesOrderID:  salesOrder.ID,
+				ProductID:     itemReq.ProductId,
+				ProductUnitID: unit.ProductUnitID,
+				Quantity:      quantity,
+				BaseQuantity:  baseQty,
+				UnitPrice:     unitPrice,
+				DiscountAmt:   itemDiscount,
+				Subtotal:      lineTotal,
+				Notes:         itemReq.Notes,
 			}
 
 			if err := tx.Create(item).Error; err != nil {
@@ -444,18 +446,21 @@ func (s *SalesOrderService) UpdateSalesOrder(ctx context.Context, companyID stri
 					return pkgerrors.NewBadRequestError(fmt.Sprintf("invalid lineTotal"))
 				}
 
-				item := &models.SalesOrderItem{
-					SalesOrderID: salesOrderID,
-					ProductID:    *itemReq.ProductId,
-					Quantity:     quantity,
-					UnitPrice:    unitPrice,
-					DiscountAmt:  itemDiscount,
-					Subtotal:     lineTotal,
-					Notes:        itemReq.Notes,
+				unit, baseQty, err := s.uomService.ConvertQuantity(tx, *itemReq.ProductId, itemReq.UnitId, quantity)
+				if err != nil {
+					return err
 				}
 
-				if itemReq.UnitId != nil {
-					item.ProductUnitID = itemReq.UnitId
+				item := &models.SalesOrderItem{
+					SalesOrderID:  salesOrderID,
+					ProductID:     *itemReq.ProductId,
+					ProductUnitID: unit.ProductUnitID,
+					Quantity:      quantity,
+					BaseQuantity:  baseQty,
+					UnitPrice:     unitPrice,
+					DiscountAmt:   itemDiscount,
+					Subtotal:      lineTotal,
+					Notes:         itemReq.Notes,
 				}
 
 				if err := tx.Create(item).Error; err != nil {
@@ -507,7 +512,7 @@ func (s *SalesOrderService) DeleteSalesOrder(ctx context.Context, companyID stri
 
 // SubmitSalesOrder transitions from DRAFT to PENDING
 func (s *SalesOrderService) SubmitSalesOrder(ctx context.Context, companyID string, tenantID string, userID string, ipAddress string, userAgent string, salesOrderID string) (*models.SalesOrder, error) {
-	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusDraft, models.SalesOrderStatusPending, "Submitted sales order")
+	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusDraft, models.SalesOrderStatusPending, "Submitted sales order", nil)
 }
 
 // ApproveSalesOrder transitions from PENDING to APPROVED
@@ -528,6 +533,26 @@ func (s *SalesOrderService) ApproveSalesOrder(ctx context.Context, companyID str
 			return pkgerrors.NewBadRequestError(fmt.Sprintf("Cannot approve sales order with status %s", salesOrder.Status))
 		}
 
+		// Reserve stock for each line in the order's warehouse
+		var items []models.SalesOrderItem
+		if err := tx.Where("sales_order_id = ?", salesOrder.ID).Find(&items).Error; err != nil {
+			return fmt.Errorf("failed to load sales order items: %w", err)
+		}
+
+		for _, item := range items {
+			if _, err := s.stockPostingService.Reserve(tx, &inventory.Reservation{
+				TenantID:         tenantID,
+				CompanyID:        companyID,
+				WarehouseID:      salesOrder.WarehouseID,
+				ProductID:        item.ProductID,
+				SalesOrderID:     salesOrder.ID,
+				SalesOrderItemID: item.ID,
+				Quantity:         item.BaseQuantity,
+			}); err != nil {
+				return err
+			}
+		}
+
 		// Update status and approval info
 		now := time.Now()
 		updates := map[string]interface{}{
@@ -552,7 +577,7 @@ func (s *SalesOrderService) ApproveSalesOrder(ctx context.Context, companyID str
 
 // StartProcessingSalesOrder transitions from APPROVED to PROCESSING
 func (s *SalesOrderService) StartProcessingSalesOrder(ctx context.Context, companyID string, tenantID string, userID string, ipAddress string, userAgent string, salesOrderID string) (*models.SalesOrder, error) {
-	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusApproved, models.SalesOrderStatusProcessing, "Started processing sales order")
+	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusApproved, models.SalesOrderStatusProcessing, "Started processing sales order", nil)
 }
 
 // ShipSalesOrder transitions from PROCESSING to SHIPPED
@@ -662,8 +687,13 @@ func (s *SalesOrderService) DeliverSalesOrder(ctx context.Context, companyID str
 }
 
 // CompleteSalesOrder transitions from DELIVERED to COMPLETED
+// Any quantity still reserved (short delivery) is released.
 func (s *SalesOrderService) CompleteSalesOrder(ctx context.Context, companyID string, tenantID string, userID string, ipAddress string, userAgent string, salesOrderID string) (*models.SalesOrder, error) {
-	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusDelivered, models.SalesOrderStatusCompleted, "Completed sales order")
+	return s.transitionStatus(ctx, companyID, tenantID, userID, ipAddress, userAgent, salesOrderID, models.SalesOrderStatusDelivered, models.SalesOrderStatusCompleted, "Completed sales order",
+		func(tx *gorm.DB, salesOrder *models.SalesOrder) error {
+			// Release reservations left over from short deliveries
+			return s.stockPostingService.ReleaseReservations(tx, salesOrder.ID)
+		})
 }
 
 // CancelSalesOrder transitions to CANCELLED from any non-final status
@@ -697,6 +727,11 @@ func (s *SalesOrderService) CancelSalesOrder(ctx context.Context, companyID stri
 			return fmt.Errorf("failed to cancel sales order: %w", err)
 		}
 
+		// Release stock still reserved for the order
+		if err := s.stockPostingService.ReleaseReservations(tx, salesOrder.ID); err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -711,8 +746,9 @@ func (s *SalesOrderService) CancelSalesOrder(ctx context.Context, companyID stri
 // HELPER FUNCTIONS
 // ============================================================================
 
-// transitionStatus is a helper function for simple status transitions
-func (s *SalesOrderService) transitionStatus(ctx context.Context, companyID string, tenantID string, userID string, ipAddress string, userAgent string, salesOrderID string, fromStatus models.SalesOrderStatus, toStatus models.SalesOrderStatus, auditMessage string) (*models.SalesOrder, error) {
+// transitionStatus is a helper function for simple status transitions.
+// afterUpdate, when set, runs in the same transaction once the status is updated.
+func (s *SalesOrderService) transitionStatus(ctx context.Context, companyID string, tenantID string, userID string, ipAddress string, userAgent string, salesOrderID string, fromStatus models.SalesOrderStatus, toStatus models.SalesOrderStatus, auditMessage string, afterUpdate func(tx *gorm.DB, salesOrder *models.SalesOrder) error) (*models.SalesOrder, error) {
 	var salesOrder *models.SalesOrder
 
 	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
@@ -734,6 +770,9 @@ func (s *SalesOrderService) transitionStatus(ctx context.Context, companyID stri
 			return fmt.Errorf("failed to update sales order status: %w", err)
 		}
 
+		if afterUpdate != nil {
+			return afterUpdate(tx, salesOrder)
+		}
 		return nil
 	})
 
@@ -0,0 +1,447 @@
+package serial
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// SerialService keeps the unit-level history of serial-tracked products.
+// Goods receipts register serials; deliveries and transfers pick serials on their lines
+// (DocumentLineSerial) when they are created and move them when their stock is posted,
+// writing one ProductSerialEvent per step.
+type SerialService struct {
+	db *gorm.DB
+}
+
+// NewSerialService creates a new serial service instance
+func NewSerialService(db *gorm.DB) *SerialService {
+	return &SerialService{
+		db: db,
+	}
+}
+
+// SerialReceipt - Serials captured for one accepted goods receipt line
+type SerialReceipt struct {
+	TenantID        string
+	CompanyID       string
+	ProductID       string
+	WarehouseID     string
+	BatchID         *string
+	SupplierID      *string
+	GoodsReceiptID  string
+	ReferenceNumber string
+	Quantity        decimal.Decimal // Accepted base quantity; one serial per unit
+	SerialNumbers   []string
+	ReceivedAt      time.Time
+	CreatedBy       string
+}
+
+// SerialAssignment - Serials picked for a delivery or transfer line
+type SerialAssignment struct {
+	CompanyID     string
+	ProductID     string
+	ReferenceType string
+	ReferenceID   string
+	LineID        string
+	Quantity      decimal.Decimal // Base quantity of the line; one serial per unit
+	SerialNumbers []string
+
+	// Where the serials must be now
+	Status      models.SerialStatus
+	WarehouseID *string // Required location when Status is IN_STOCK
+	CustomerID  *string // Required holder when Status is DELIVERED
+	BatchID     *string // When the line has a batch, the serials must belong to it
+}
+
+// SerialMove - New position of the serials of a line after its stock is posted
+type SerialMove struct {
+	TenantID        string
+	CompanyID       string
+	EventType       models.SerialEventType
+	EventDate       time.Time
+	FromStatus      models.SerialStatus // Serials must still be in this status
+	FromWarehouseID *string             // ...and, when set, in this warehouse
+	Status          models.SerialStatus
+	WarehouseID     *string // nil = not in a warehouse
+	CustomerID      *string // nil = not with a customer
+	BatchID         *string // When set, the batch the units are now in (batches are per warehouse)
+	ReferenceType   string
+	ReferenceID     string
+	ReferenceNumber string
+	Notes           *string
+	CreatedBy       *string
+
+	// DeliveryID records the delivery that handed the unit to the customer;
+	// the warranty starts at WarrantyStartDate (the delivery date)
+	DeliveryID        *string
+	WarrantyStartDate *time.Time
+	ClearDelivery     bool // Delivery cancelled: forget the delivery and warranty start
+}
+
+// RequiresSerials reports whether the product is serial-tracked
+func RequiresSerials(tx *gorm.DB, productID string) (bool, error) {
+	var product models.Product
+	if err := tx.Select("id", "is_serial_tracked").Where("id = ?", productID).First(&product).Error; err != nil {
+		return false, fmt.Errorf("failed to load product: %w", err)
+	}
+	return product.IsSerialTracked, nil
+}
+
+// Receive registers the serials of an accepted goods receipt line as IN_STOCK
+func (s *SerialService) Receive(tx *gorm.DB, receipt *SerialReceipt) error {
+	serialNumbers, err := normalizeSerials(receipt.SerialNumbers, receipt.Quantity)
+	if err != nil {
+		return err
+	}
+
+	var existing []string
+	if err := tx.Model(&models.ProductSerial{}).
+		Where("company_id = ? AND product_id = ? AND serial_number IN ?", receipt.CompanyID, receipt.ProductID, serialNumbers).
+		Pluck("serial_number", &existing).Error; err != nil {
+		return fmt.Errorf("failed to check existing serials: %w", err)
+	}
+	if len(existing) > 0 {
+		return pkgerrors.NewConflictError(fmt.Sprintf("serial numbers already registered: %s", strings.Join(existing, ", ")))
+	}
+
+	warehouseID := receipt.WarehouseID
+	for _, serialNumber := range serialNumbers {
+		serial := &models.ProductSerial{
+			TenantID:       receipt.TenantID,
+			CompanyID:      receipt.CompanyID,
+			ProductID:      receipt.ProductID,
+			SerialNumber:   serialNumber,
+			Status:         models.SerialStatusInStock,
+			WarehouseID:    &warehouseID,
+			BatchID:        receipt.BatchID,
+			SupplierID:     receipt.SupplierID,
+			GoodsReceiptID: &receipt.GoodsReceiptID,
+			ReceivedAt:     receipt.ReceivedAt,
+		}
+		if err := tx.Create(serial).Error; err != nil {
+			return fmt.Errorf("failed to register serial %s: %w", serialNumber, err)
+		}
+
+		event := &models.ProductSerialEvent{
+			TenantID:        receipt.TenantID,
+			CompanyID:       receipt.CompanyID,
+			SerialID:        serial.ID,
+			EventType:       models.SerialEventReceived,
+			EventDate:       receipt.ReceivedAt,
+			Status:          models.SerialStatusInStock,
+			WarehouseID:     &warehouseID,
+			SupplierID:      receipt.SupplierID,
+			ReferenceType:   inventory.ReferenceTypeGoodsReceipt,
+			ReferenceID:     receipt.GoodsReceiptID,
+			ReferenceNumber: receipt.ReferenceNumber,
+		}
+		if receipt.CreatedBy != "" {
+			event.CreatedBy = &receipt.CreatedBy
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record serial event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Assign validates the serials picked for a document line and links them to it.
+// Returns the batch all serials share (nil when they span batches or have none), so the
+// caller can pin a batch-tracked line to it.
+func (s *SerialService) Assign(tx *gorm.DB, assignment *SerialAssignment) (*string, error) {
+	serialNumbers, err := normalizeSerials(assignment.SerialNumbers, assignment.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	var serials []models.ProductSerial
+	if err := tx.Where("company_id = ? AND product_id = ? AND serial_number IN ?", assignment.CompanyID, assignment.ProductID, serialNumbers).
+		Find(&serials).Error; err != nil {
+		return nil, fmt.Errorf("failed to load serials: %w", err)
+	}
+
+	found := make(map[string]models.ProductSerial, len(serials))
+	for _, serial := range serials {
+		found[serial.SerialNumber] = serial
+	}
+
+	var batchID *string
+	for i, serialNumber := range serialNumbers {
+		serial, ok := found[serialNumber]
+		if !ok {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s not found for product %s", serialNumber, assignment.ProductID))
+		}
+		if err := checkPosition(&serial, assignment.Status, assignment.WarehouseID, assignment.CustomerID); err != nil {
+			return nil, err
+		}
+		if assignment.BatchID != nil && (serial.BatchID == nil || *serial.BatchID != *assignment.BatchID) {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s does not belong to the line's batch", serialNumber))
+		}
+
+		if i == 0 {
+			batchID = serial.BatchID
+		} else if batchID != nil && (serial.BatchID == nil || *serial.BatchID != *batchID) {
+			batchID = nil
+		}
+
+		if err := tx.Create(&models.DocumentLineSerial{
+			SerialID:      serial.ID,
+			ReferenceType: assignment.ReferenceType,
+			ReferenceID:   assignment.ReferenceID,
+			LineID:        assignment.LineID,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("failed to assign serial %s: %w", serialNumber, err)
+		}
+	}
+
+	return batchID, nil
+}
+
+// Release removes the serial picks of a document whose lines are replaced or deleted
+func (s *SerialService) Release(tx *gorm.DB, referenceType, referenceID string) error {
+	if err := tx.Where("reference_type = ? AND reference_id = ?", referenceType, referenceID).
+		Delete(&models.DocumentLineSerial{}).Error; err != nil {
+		return fmt.Errorf("failed to release serials: %w", err)
+	}
+	return nil
+}
+
+// CopyLine links the serials of one document line to another (e.g. a return of a delivery line),
+// leaving out exceptSerialIDs
+func (s *SerialService) CopyLine(tx *gorm.DB, fromReferenceType, fromLineID, toReferenceType, toReferenceID, toLineID string, exceptSerialIDs ...string) error {
+	query := tx.Where("reference_type = ? AND line_id = ?", fromReferenceType, fromLineID)
+	if len(exceptSerialIDs) > 0 {
+		query = query.Where("serial_id NOT IN ?", exceptSerialIDs)
+	}
+	var links []models.DocumentLineSerial
+	if err := query.Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to load line serials: %w", err)
+	}
+
+	for _, link := range links {
+		if err := tx.Create(&models.DocumentLineSerial{
+			SerialID:      link.SerialID,
+			ReferenceType: toReferenceType,
+			ReferenceID:   toReferenceID,
+			LineID:        toLineID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to copy line serial: %w", err)
+		}
+	}
+	return nil
+}
+
+// LineSerials returns the serials picked for a document line
+func (s *SerialService) LineSerials(tx *gorm.DB, referenceType, lineID string) ([]models.ProductSerial, error) {
+	var serials []models.ProductSerial
+	if err := tx.Joins("JOIN document_line_serials ON document_line_serials.serial_id = product_serials.id").
+		Where("document_line_serials.reference_type = ? AND document_line_serials.line_id = ?", referenceType, lineID).
+		Order("product_serials.serial_number ASC").
+		Find(&serials).Error; err != nil {
+		return nil, fmt.Errorf("failed to load line serials: %w", err)
+	}
+	return serials, nil
+}
+
+// MoveLine moves every serial picked for a document line (see Move)
+func (s *SerialService) MoveLine(tx *gorm.DB, lineID string, move *SerialMove) error {
+	serials, err := s.LineSerials(tx, move.ReferenceType, lineID)
+	if err != nil {
+		return err
+	}
+	return s.Move(tx, serials, move)
+}
+
+// Move updates the position of the given serials and records the event for each.
+// Serials no longer in move.FromStatus were taken by another document in the meantime.
+func (s *SerialService) Move(tx *gorm.DB, serials []models.ProductSerial, move *SerialMove) error {
+	for _, serial := range serials {
+		if err := checkPosition(&serial, move.FromStatus, move.FromWarehouseID, nil); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       move.Status,
+			"warehouse_id": move.WarehouseID,
+			"customer_id":  move.CustomerID,
+		}
+		if move.BatchID != nil {
+			updates["batch_id"] = *move.BatchID
+		}
+		if move.DeliveryID != nil {
+			updates["delivery_id"] = move.DeliveryID
+			updates["warranty_start_date"] = move.WarrantyStartDate
+		} else if move.ClearDelivery {
+			updates["delivery_id"] = nil
+			updates["warranty_start_date"] = nil
+		}
+		// Guarded by the expected status so a concurrent move of the same serial cannot also succeed
+		result := tx.Model(&models.ProductSerial{}).Where("id = ? AND status = ?", serial.ID, move.FromStatus).Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move serial %s: %w", serial.SerialNumber, result.Error)
+		}
+		if result.RowsAffected != 1 {
+			return pkgerrors.NewConflictError(fmt.Sprintf("serial %s was moved by another document", serial.SerialNumber))
+		}
+
+		if err := tx.Create(&models.ProductSerialEvent{
+			TenantID:        move.TenantID,
+			CompanyID:       move.CompanyID,
+			SerialID:        serial.ID,
+			EventType:       move.EventType,
+			EventDate:       move.EventDate,
+			Status:          move.Status,
+			WarehouseID:     move.WarehouseID,
+			CustomerID:      move.CustomerID,
+			ReferenceType:   move.ReferenceType,
+			ReferenceID:     move.ReferenceID,
+			ReferenceNumber: move.ReferenceNumber,
+			Notes:           move.Notes,
+			CreatedBy:       move.CreatedBy,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record serial event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ============================================================================
+// QUERIES
+// ============================================================================
+
+// ListSerials lists serials with filters and pagination
+func (s *SerialService) ListSerials(ctx context.Context, tenantID, companyID string, query *dto.SerialListQuery) ([]models.ProductSerial, *dto.PaginationInfo, error) {
+	baseQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.ProductSerial{}).
+		Where("company_id = ?", companyID)
+
+	if query.Search != "" {
+		baseQuery = baseQuery.Where("LOWER(serial_number) LIKE ?", "%"+strings.ToLower(query.Search)+"%")
+	}
+	if query.ProductID != "" {
+		baseQuery = baseQuery.Where("product_id = ?", query.ProductID)
+	}
+	if query.Status != "" {
+		baseQuery = baseQuery.Where("status = ?", query.Status)
+	}
+	if query.WarehouseID != "" {
+		baseQuery = baseQuery.Where("warehouse_id = ?", query.WarehouseID)
+	}
+	if query.CustomerID != "" {
+		baseQuery = baseQuery.Where("customer_id = ?", query.CustomerID)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to count serials: %w", err)
+	}
+
+	var serials []models.ProductSerial
+	offset := (query.Page - 1) * query.PageSize
+	if err := baseQuery.
+		Preload("Product").
+		Preload("Warehouse").
+		Preload("Customer").
+		Order("serial_number ASC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Find(&serials).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list serials: %w", err)
+	}
+
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	return serials, &dto.PaginationInfo{
+		Page:       query.Page,
+		Limit:      query.PageSize,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetSerialHistory returns a serial with its path from supplier to customer, oldest event first
+func (s *SerialService) GetSerialHistory(ctx context.Context, tenantID, companyID, serialID string) (*models.ProductSerial, []models.ProductSerialEvent, error) {
+	var serial models.ProductSerial
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Product").
+		Preload("Warehouse").
+		Preload("Batch").
+		Preload("Supplier").
+		Preload("GoodsReceipt").
+		Preload("Customer").
+		Preload("Delivery").
+		Where("id = ? AND company_id = ?", serialID, companyID).
+		First(&serial).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, pkgerrors.NewNotFoundError("serial not found")
+		}
+		return nil, nil, fmt.Errorf("failed to get serial: %w", err)
+	}
+
+	var events []models.ProductSerialEvent
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Warehouse").
+		Preload("Customer").
+		Preload("Supplier").
+		Where("serial_id = ?", serial.ID).
+		Order("event_date ASC, created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load serial history: %w", err)
+	}
+
+	return &serial, events, nil
+}
+
+// ============================================================================
+// HELPER FUNCTIONS
+// ============================================================================
+
+// normalizeSerials trims the serials and checks there is exactly one unique serial per unit
+func normalizeSerials(serialNumbers []string, quantity decimal.Decimal) ([]string, error) {
+	if !quantity.Equal(quantity.Truncate(0)) {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("serial-tracked quantity must be a whole number of units, got %s", quantity.String()))
+	}
+
+	seen := make(map[string]bool, len(serialNumbers))
+	normalized := make([]string, 0, len(serialNumbers))
+	for _, serialNumber := range serialNumbers {
+		serialNumber = strings.TrimSpace(serialNumber)
+		if serialNumber == "" {
+			return nil, pkgerrors.NewBadRequestError("serial number cannot be empty")
+		}
+		if seen[serialNumber] {
+			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s is listed more than once", serialNumber))
+		}
+		seen[serialNumber] = true
+		normalized = append(normalized, serialNumber)
+	}
+
+	if int64(len(normalized)) != quantity.IntPart() {
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("%s serial numbers required, got %d", quantity.String(), len(normalized)))
+	}
+	return normalized, nil
+}
+
+// checkPosition verifies a serial is where a document expects to take it from
+func checkPosition(serial *models.ProductSerial, status models.SerialStatus, warehouseID, customerID *string) error {
+	if serial.Status != status {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s is %s, expected %s", serial.SerialNumber, serial.Status, status))
+	}
+	if warehouseID != nil && (serial.WarehouseID == nil || *serial.WarehouseID != *warehouseID) {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s is not in the expected warehouse", serial.SerialNumber))
+	}
+	if customerID != nil && (serial.CustomerID == nil || *serial.CustomerID != *customerID) {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("serial %s was not delivered to this customer", serial.SerialNumber))
+	}
+	return nil
+}
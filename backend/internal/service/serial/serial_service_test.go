@@ -0,0 +1,179 @@
+package serial
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.ProductSerial{},
+		&models.ProductSerialEvent{},
+		&models.DocumentLineSerial{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	otherWarehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH002")
+
+	supplier := &models.Supplier{TenantID: company.TenantID, CompanyID: company.ID, Code: "SUP001", Name: "PT Elektronik Jaya", IsActive: true}
+	require.NoError(t, db.Create(supplier).Error)
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD001", Name: "Kulkas 2 Pintu", BaseUnit: "UNIT", IsSerialTracked: true, IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+
+	service := NewSerialService(db)
+	receivedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	receive := func(quantity int64, serialNumbers ...string) error {
+		return service.Receive(db, &SerialReceipt{
+			TenantID:        company.TenantID,
+			CompanyID:       company.ID,
+			ProductID:       product.ID,
+			WarehouseID:     warehouse.ID,
+			SupplierID:      &supplier.ID,
+			GoodsReceiptID:  "grn-1",
+			ReferenceNumber: "GRN-2026-00001",
+			Quantity:        decimal.NewFromInt(quantity),
+			SerialNumbers:   serialNumbers,
+			ReceivedAt:      receivedAt,
+		})
+	}
+
+	assign := func(lineID string, warehouseID string, serialNumbers ...string) error {
+		_, err := service.Assign(db, &SerialAssignment{
+			CompanyID:     company.ID,
+			ProductID:     product.ID,
+			ReferenceType: inventory.ReferenceTypeDelivery,
+			ReferenceID:   "delivery-1",
+			LineID:        lineID,
+			Quantity:      decimal.NewFromInt(int64(len(serialNumbers))),
+			SerialNumbers: serialNumbers,
+			Status:        models.SerialStatusInStock,
+			WarehouseID:   &warehouseID,
+		})
+		return err
+	}
+
+	t.Run("success - goods receipt registers one serial per unit", func(t *testing.T) {
+		require.NoError(t, receive(2, "SN-001", " SN-002 "))
+
+		var serials []models.ProductSerial
+		require.NoError(t, db.Where("product_id = ?", product.ID).Order("serial_number").Find(&serials).Error)
+		require.Len(t, serials, 2)
+		assert.Equal(t, "SN-002", serials[1].SerialNumber)
+		assert.Equal(t, models.SerialStatusInStock, serials[0].Status)
+		assert.Equal(t, warehouse.ID, *serials[0].WarehouseID)
+	})
+
+	t.Run("error - serial count must match the accepted quantity", func(t *testing.T) {
+		err := receive(2, "SN-003")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 serial numbers required")
+	})
+
+	t.Run("error - serial already registered", func(t *testing.T) {
+		err := receive(1, "SN-001")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already registered")
+	})
+
+	t.Run("error - serial not in the delivery warehouse", func(t *testing.T) {
+		err := assign("line-x", otherWarehouse.ID, "SN-001")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not in the expected warehouse")
+	})
+
+	t.Run("error - unknown serial", func(t *testing.T) {
+		err := assign("line-x", warehouse.ID, "SN-999")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("success - delivery moves the serial to the customer and starts the warranty", func(t *testing.T) {
+		require.NoError(t, assign("line-1", warehouse.ID, "SN-001"))
+
+		deliveryDate := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		deliveryID := "delivery-1"
+		require.NoError(t, service.MoveLine(db, "line-1", &SerialMove{
+			TenantID:          company.TenantID,
+			CompanyID:         company.ID,
+			EventType:         models.SerialEventDelivered,
+			EventDate:         deliveryDate,
+			FromStatus:        models.SerialStatusInStock,
+			FromWarehouseID:   &warehouse.ID,
+			Status:            models.SerialStatusDelivered,
+			CustomerID:        &customer.ID,
+			ReferenceType:     inventory.ReferenceTypeDelivery,
+			ReferenceID:       deliveryID,
+			ReferenceNumber:   "DO-2026-00001",
+			DeliveryID:        &deliveryID,
+			WarrantyStartDate: &deliveryDate,
+		}))
+
+		var delivered models.ProductSerial
+		require.NoError(t, db.Where("serial_number = ?", "SN-001").First(&delivered).Error)
+		serial, events, err := service.GetSerialHistory(ctx, company.TenantID, company.ID, delivered.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SerialStatusDelivered, serial.Status)
+		assert.Nil(t, serial.WarehouseID)
+		require.NotNil(t, serial.Customer)
+		assert.Equal(t, "Toko Makmur", serial.Customer.Name)
+		require.NotNil(t, serial.Supplier)
+		assert.Equal(t, "PT Elektronik Jaya", serial.Supplier.Name)
+		require.NotNil(t, serial.WarrantyStartDate)
+		assert.Equal(t, "2026-03-10", serial.WarrantyStartDate.Format("2006-01-02"))
+		require.Len(t, events, 2)
+		assert.Equal(t, models.SerialEventReceived, events[0].EventType)
+		assert.Equal(t, "GRN-2026-00001", events[0].ReferenceNumber)
+		assert.Equal(t, models.SerialEventDelivered, events[1].EventType)
+		assert.Equal(t, customer.ID, *events[1].CustomerID)
+	})
+
+	t.Run("error - delivered serial cannot be picked from stock again", func(t *testing.T) {
+		err := assign("line-2", warehouse.ID, "SN-001")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is DELIVERED, expected IN_STOCK")
+	})
+
+	t.Run("error - move from a stale read loses to the move that got there first", func(t *testing.T) {
+		var stale models.ProductSerial
+		require.NoError(t, db.Where("serial_number = ?", "SN-001").First(&stale).Error)
+		// Read before the delivery above moved it
+		stale.Status = models.SerialStatusInStock
+		stale.WarehouseID = &warehouse.ID
+
+		err := service.Move(db, []models.ProductSerial{stale}, &SerialMove{
+			TenantID:        company.TenantID,
+			CompanyID:       company.ID,
+			EventType:       models.SerialEventDelivered,
+			EventDate:       time.Now(),
+			FromStatus:      models.SerialStatusInStock,
+			FromWarehouseID: &warehouse.ID,
+			Status:          models.SerialStatusDelivered,
+			CustomerID:      &customer.ID,
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "was moved by another document")
+	})
+}
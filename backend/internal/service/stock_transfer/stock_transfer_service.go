@@ -11,21 +11,27 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/audit"
+	"backend/internal/service/inventory"
+	"backend/internal/service/serial"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 // StockTransferService handles business logic for stock transfers
 type StockTransferService struct {
-	db           *gorm.DB
-	auditService *audit.AuditService
+	db                  *gorm.DB
+	auditService        *audit.AuditService
+	stockPostingService *inventory.StockPostingService
+	serialService       *serial.SerialService
 }
 
 // NewStockTransferService creates a new stock transfer service instance
-func NewStockTransferService(db *gorm.DB, auditService *audit.AuditService) *StockTransferService {
+func NewStockTransferService(db *gorm.DB, auditService *audit.AuditService, stockPostingService *inventory.StockPostingService) *StockTransferService {
 	return &StockTransferService{
-		db:           db,
-		auditService: auditService,
+		db:                  db,
+		auditService:        auditService,
+		stockPostingService: stockPostingService,
+		serialService:       serial.NewSerialService(db),
 	}
 }
 
@@ -131,6 +137,11 @@ func (s *StockTransferService) CreateStockTransfer(
 			tx.Rollback()
 			return nil, pkgerrors.NewInternalError(err)
 		}
+
+		if err := s.assignSerials(tx, transfer, item, itemReq.SerialNumbers); err != nil {
+			tx.Rollback()
+			return nil, wrapPostingError(err)
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -322,6 +333,7 @@ func (s *StockTransferService) GetStockTransferByID(
 		Preload("SourceWarehouse").
 		Preload("DestWarehouse").
 		Preload("Items.Product").
+		Preload("Items.Batch").
 		First(&transfer).Error
 
 	if err != nil {
@@ -442,11 +454,15 @@ func (s *StockTransferService) UpdateStockTransfer(
 
 	// Update items if provided
 	if req.Items != nil {
-		// Delete existing items
+		// Delete existing items and their serial picks
 		if err := tx.Where("stock_transfer_id = ?", transferID).Delete(&models.StockTransferItem{}).Error; err != nil {
 			tx.Rollback()
 			return nil, pkgerrors.NewInternalError(err)
 		}
+		if err := s.serialService.Release(tx, inventory.ReferenceTypeStockTransfer, transferID); err != nil {
+			tx.Rollback()
+			return nil, pkgerrors.NewInternalError(err)
+		}
 
 		// Create new items
 		for _, itemReq := range *req.Items {
@@ -468,6 +484,11 @@ func (s *StockTransferService) UpdateStockTransfer(
 				tx.Rollback()
 				return nil, pkgerrors.NewInternalError(err)
 			}
+
+			if err := s.assignSerials(tx, &transfer, item, itemReq.SerialNumbers); err != nil {
+				tx.Rollback()
+				return nil, wrapPostingError(err)
+			}
 		}
 	}
 
@@ -536,6 +557,10 @@ func (s *StockTransferService) DeleteStockTransfer(
 		tx.Rollback()
 		return pkgerrors.NewInternalError(err)
 	}
+	if err := s.serialService.Release(tx, inventory.ReferenceTypeStockTransfer, transferID); err != nil {
+		tx.Rollback()
+		return pkgerrors.NewInternalError(err)
+	}
 
 	// Delete transfer
 	if err := tx.Delete(transfer).Error; err != nil {
@@ -606,24 +631,8 @@ func (s *StockTransferService) ShipStockTransfer(
 		return nil, pkgerrors.NewInternalError(err)
 	}
 
-	// Check stock availability in source warehouse
-	for _, item := range items {
-		var stock models.WarehouseStock
-		err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
-			Where("warehouse_id = ? AND product_id = ?", transfer.SourceWarehouseID, item.ProductID).
-			First(&stock).Error
-
-		if err == gorm.ErrRecordNotFound {
-			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Product not found in source warehouse"))
-		}
-		if err != nil {
-			return nil, pkgerrors.NewInternalError(err)
-		}
-
-		if stock.Quantity.LessThan(item.Quantity) {
-			return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("Insufficient stock for product. Available: %s, Required: %s", stock.Quantity.String(), item.Quantity.String()))
-		}
-	}
+	// Stock availability in the source warehouse is checked when posting, under the stock
+	// row lock and following the company's negative stock policy
 
 	tx := s.db.WithContext(ctx).Set("tenant_id", tenantID).Begin()
 
@@ -641,26 +650,15 @@ func (s *StockTransferService) ShipStockTransfer(
 		updateFields["notes"] = *req.Notes
 	}
 
-	if err := tx.Model(&transfer).Updates(updateFields).Error; err != nil {
+	if err := updateTransferStatus(tx, &transfer, models.StockTransferStatusDraft, updateFields); err != nil {
 		tx.Rollback()
-		return nil, pkgerrors.NewInternalError(err)
+		return nil, err
 	}
 
-	// Reduce stock at source warehouse
-	for _, item := range items {
-		var stock models.WarehouseStock
-		if err := tx.Where("warehouse_id = ? AND product_id = ?", transfer.SourceWarehouseID, item.ProductID).
-			First(&stock).Error; err != nil {
-			tx.Rollback()
-			return nil, pkgerrors.NewInternalError(err)
-		}
-
-		// Decrease quantity
-		newQuantity := stock.Quantity.Sub(item.Quantity)
-		if err := tx.Model(&stock).Update("quantity", newQuantity).Error; err != nil {
-			tx.Rollback()
-			return nil, pkgerrors.NewInternalError(err)
-		}
+	// Reduce stock at source warehouse and show it as in transit at the destination
+	if err := s.shipItems(tx, &transfer, items, now, userID); err != nil {
+		tx.Rollback()
+		return nil, wrapPostingError(err)
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -758,44 +756,16 @@ func (s *StockTransferService) ReceiveStockTransfer(
 		updateFields["notes"] = *req.Notes
 	}
 
-	if err := tx.Model(&transfer).Updates(updateFields).Error; err != nil {
+	if err := updateTransferStatus(tx, &transfer, models.StockTransferStatusShipped, updateFields); err != nil {
 		tx.Rollback()
-		return nil, pkgerrors.NewInternalError(err)
+		return nil, err
 	}
 
-	// Add stock at destination warehouse
-	for _, item := range items {
-		var stock models.WarehouseStock
-
-		// Try to find existing stock record
-		err := tx.Where("warehouse_id = ? AND product_id = ?", transfer.DestWarehouseID, item.ProductID).
-			First(&stock).Error
-
-		if err == gorm.ErrRecordNotFound {
-			// Create new stock record if it doesn't exist
-			stock = models.WarehouseStock{
-				ID:          uuid.New().String(),
-				WarehouseID: transfer.DestWarehouseID,
-				ProductID:   item.ProductID,
-				Quantity:    item.Quantity,
-				CreatedAt:   now,
-				UpdatedAt:   now,
-			}
-			if err := tx.Create(&stock).Error; err != nil {
-				tx.Rollback()
-				return nil, pkgerrors.NewInternalError(err)
-			}
-		} else if err != nil {
-			tx.Rollback()
-			return nil, pkgerrors.NewInternalError(err)
-		} else {
-			// Update existing stock by adding the transferred quantity
-			newQuantity := stock.Quantity.Add(item.Quantity)
-			if err := tx.Model(&stock).Update("quantity", newQuantity).Error; err != nil {
-				tx.Rollback()
-				return nil, pkgerrors.NewInternalError(err)
-			}
-		}
+	// Add stock at destination warehouse, carrying the cost it left the source with,
+	// and write off any shortage reported by the receiver
+	if err := s.receiveItems(tx, &transfer, items, req.Items, now, userID); err != nil {
+		tx.Rollback()
+		return nil, wrapPostingError(err)
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -879,12 +849,63 @@ func (s *StockTransferService) CancelStockTransfer(
 		"updated_at": time.Now(),
 	}
 
-	if err := tx.Model(&transfer).Updates(updateFields).Error; err != nil {
+	if err := updateTransferStatus(tx, &transfer, models.StockTransferStatusShipped, updateFields); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Reverse inventory movements (return stock to source warehouse)
+	var items []models.StockTransferItem
+	if err := tx.Where("stock_transfer_id = ?", transferID).Find(&items).Error; err != nil {
 		tx.Rollback()
 		return nil, pkgerrors.NewInternalError(err)
 	}
 
-	// TODO: Reverse inventory movements (return stock to source warehouse)
+	for _, item := range items {
+		unitCost, err := s.stockPostingService.OutboundUnitCost(tx, inventory.ReferenceTypeStockTransfer, transfer.ID, transfer.SourceWarehouseID, item.ProductID)
+		if err != nil {
+			tx.Rollback()
+			return nil, pkgerrors.NewInternalError(err)
+		}
+
+		if _, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:        tenantID,
+			CompanyID:       companyID,
+			WarehouseID:     transfer.SourceWarehouseID,
+			ProductID:       item.ProductID,
+			BatchID:         item.BatchID,
+			MovementType:    models.MovementTypeTransfer,
+			Quantity:        item.Quantity,
+			UnitCost:        unitCost,
+			ReferenceType:   inventory.ReferenceTypeStockTransfer,
+			ReferenceID:     transfer.ID,
+			ReferenceNumber: transfer.TransferNumber,
+			Notes:           &cancelNote,
+			CreatedBy:       userID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, wrapPostingError(err)
+		}
+
+		if err := s.stockPostingService.AdjustInTransit(tx, transfer.DestWarehouseID, item.ProductID, item.Quantity.Neg()); err != nil {
+			tx.Rollback()
+			return nil, pkgerrors.NewInternalError(err)
+		}
+
+		// Serials in transit go back to the source warehouse
+		if err := s.moveItemSerials(tx, &transfer, item.ID, item.ProductID, &serial.SerialMove{
+			EventType:   models.SerialEventTransferCancelled,
+			EventDate:   time.Now(),
+			FromStatus:  models.SerialStatusInTransit,
+			Status:      models.SerialStatusInStock,
+			WarehouseID: &transfer.SourceWarehouseID,
+			Notes:       &cancelNote,
+			CreatedBy:   &userID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, wrapPostingError(err)
+		}
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, pkgerrors.NewInternalError(err)
@@ -917,6 +938,28 @@ func (s *StockTransferService) CancelStockTransfer(
 	return s.GetStockTransferByID(ctx, tenantID, companyID, transferID)
 }
 
+// updateTransferStatus applies a status change only while the transfer is still in the expected
+// status, so two requests racing to ship, receive or cancel the same transfer cannot both post stock
+func updateTransferStatus(tx *gorm.DB, transfer *models.StockTransfer, expected models.StockTransferStatus, updates map[string]interface{}) error {
+	result := tx.Model(transfer).Where("status = ?", expected).Updates(updates)
+	if result.Error != nil {
+		return pkgerrors.NewInternalError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return pkgerrors.NewConflictError("Stock transfer was changed by another request, please retry")
+	}
+	return nil
+}
+
+// wrapPostingError keeps AppErrors from the posting service (e.g. insufficient stock)
+// and wraps anything else as an internal error
+func wrapPostingError(err error) error {
+	if appErr, ok := err.(*pkgerrors.AppError); ok {
+		return appErr
+	}
+	return pkgerrors.NewInternalError(err)
+}
+
 // generateTransferNumber generates unique transfer number for company
 func (s *StockTransferService) generateTransferNumber(tx *gorm.DB, tenantID, companyID string) (string, error) {
 	var count int64
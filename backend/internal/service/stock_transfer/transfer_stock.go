@@ -0,0 +1,410 @@
+package stock_transfer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/internal/service/serial"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// shipItems takes the transfer lines out of the source warehouse and shows them as in transit
+// at the destination. Batch-tracked lines without a batch are picked FEFO and split per batch.
+func (s *StockTransferService) shipItems(tx *gorm.DB, transfer *models.StockTransfer, items []models.StockTransferItem, shippedAt time.Time, userID string) error {
+	for _, item := range items {
+		var product models.Product
+		if err := tx.Select("id", "is_batch_tracked", "is_serial_tracked").Where("id = ?", item.ProductID).First(&product).Error; err != nil {
+			return fmt.Errorf("failed to load product: %w", err)
+		}
+
+		posting := &inventory.StockPosting{
+			TenantID:        transfer.TenantID,
+			CompanyID:       transfer.CompanyID,
+			WarehouseID:     transfer.SourceWarehouseID,
+			ProductID:       item.ProductID,
+			BatchID:         item.BatchID,
+			MovementType:    models.MovementTypeTransfer,
+			Quantity:        item.Quantity.Neg(),
+			MovementDate:    shippedAt,
+			ReferenceType:   inventory.ReferenceTypeStockTransfer,
+			ReferenceID:     transfer.ID,
+			ReferenceNumber: transfer.TransferNumber,
+			Notes:           item.Notes,
+			CreatedBy:       userID,
+
+			RespectReservations: true,
+		}
+
+		if item.BatchID != nil || !product.IsBatchTracked {
+			if _, err := s.stockPostingService.Post(tx, posting); err != nil {
+				return err
+			}
+		} else {
+			// FEFO batch picking
+			allocations, err := s.stockPostingService.PickBatchesFEFO(tx, transfer.SourceWarehouseID, item.ProductID, item.Quantity)
+			if err != nil {
+				return err
+			}
+
+			for i, alloc := range allocations {
+				batchID := alloc.Batch.ID
+				posting.BatchID = &batchID
+				posting.Quantity = alloc.Quantity.Neg()
+				if _, err := s.stockPostingService.Post(tx, posting); err != nil {
+					return err
+				}
+
+				if i == 0 {
+					if err := tx.Model(&models.StockTransferItem{}).Where("id = ?", item.ID).
+						Updates(map[string]interface{}{
+							"batch_id": batchID,
+							"quantity": alloc.Quantity,
+						}).Error; err != nil {
+						return fmt.Errorf("failed to assign batch to transfer item: %w", err)
+					}
+					continue
+				}
+
+				splitItem := &models.StockTransferItem{
+					StockTransferID: item.StockTransferID,
+					ProductID:       item.ProductID,
+					BatchID:         &batchID,
+					Quantity:        alloc.Quantity,
+					Notes:           item.Notes,
+				}
+				if err := tx.Create(splitItem).Error; err != nil {
+					return fmt.Errorf("failed to split transfer item by batch: %w", err)
+				}
+			}
+		}
+
+		if err := s.stockPostingService.AdjustInTransit(tx, transfer.DestWarehouseID, item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+
+		if product.IsSerialTracked {
+			if err := s.serialService.MoveLine(tx, item.ID, &serial.SerialMove{
+				TenantID:        transfer.TenantID,
+				CompanyID:       transfer.CompanyID,
+				EventType:       models.SerialEventTransferShipped,
+				EventDate:       shippedAt,
+				FromStatus:      models.SerialStatusInStock,
+				FromWarehouseID: &transfer.SourceWarehouseID,
+				Status:          models.SerialStatusInTransit,
+				WarehouseID:     &transfer.DestWarehouseID,
+				ReferenceType:   inventory.ReferenceTypeStockTransfer,
+				ReferenceID:     transfer.ID,
+				ReferenceNumber: transfer.TransferNumber,
+				CreatedBy:       &userID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// receiveItems books the shipped lines into the destination warehouse.
+// Each line is received in full at the cost it left the source with; any quantity the
+// receiver did not get is then written off as a discrepancy under the loss reason, so the
+// shortage stays visible in the movement history. Batches are recreated at the destination
+// with the source batch's number and dates.
+func (s *StockTransferService) receiveItems(
+	tx *gorm.DB,
+	transfer *models.StockTransfer,
+	items []models.StockTransferItem,
+	receipts []dto.ReceiveTransferItemRequest,
+	receivedAt time.Time,
+	userID string,
+) error {
+	itemIDs := make(map[string]bool, len(items))
+	for _, item := range items {
+		itemIDs[item.ID] = true
+	}
+
+	receiptByItem := make(map[string]dto.ReceiveTransferItemRequest, len(receipts))
+	for _, receipt := range receipts {
+		if !itemIDs[receipt.ItemID] {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Item %s does not belong to this transfer", receipt.ItemID))
+		}
+		receiptByItem[receipt.ItemID] = receipt
+	}
+
+	defaultReason, err := s.transferLossReason(tx, transfer.CompanyID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		receivedQty := item.Quantity
+		reason := defaultReason
+		if receipt, ok := receiptByItem[item.ID]; ok {
+			receivedQty, err = decimal.NewFromString(receipt.ReceivedQuantity)
+			if err != nil || receivedQty.IsNegative() || receivedQty.GreaterThan(item.Quantity) {
+				return pkgerrors.NewBadRequestError(fmt.Sprintf("Invalid received quantity for item %s (must be between 0 and %s)", item.ID, item.Quantity.String()))
+			}
+			if receipt.Reason != nil && *receipt.Reason != "" {
+				reason = models.InventoryAdjustmentReason(*receipt.Reason)
+			}
+		}
+
+		unitCost, err := s.stockPostingService.OutboundUnitCost(tx, inventory.ReferenceTypeStockTransfer, transfer.ID, transfer.SourceWarehouseID, item.ProductID)
+		if err != nil {
+			return err
+		}
+
+		posting := &inventory.StockPosting{
+			TenantID:        transfer.TenantID,
+			CompanyID:       transfer.CompanyID,
+			WarehouseID:     transfer.DestWarehouseID,
+			ProductID:       item.ProductID,
+			MovementType:    models.MovementTypeTransfer,
+			Quantity:        item.Quantity,
+			UnitCost:        unitCost,
+			MovementDate:    receivedAt,
+			ReferenceType:   inventory.ReferenceTypeStockTransfer,
+			ReferenceID:     transfer.ID,
+			ReferenceNumber: transfer.TransferNumber,
+			Notes:           item.Notes,
+			CreatedBy:       userID,
+		}
+		if item.BatchID != nil {
+			var sourceBatch models.ProductBatch
+			if err := tx.Where("id = ?", *item.BatchID).First(&sourceBatch).Error; err != nil {
+				return fmt.Errorf("failed to load source batch: %w", err)
+			}
+			posting.Batch = &inventory.BatchDetails{
+				BatchNumber:     sourceBatch.BatchNumber,
+				ManufactureDate: sourceBatch.ManufactureDate,
+				ExpiryDate:      sourceBatch.ExpiryDate,
+				SupplierID:      sourceBatch.SupplierID,
+				GoodsReceiptID:  sourceBatch.GoodsReceiptID,
+				ReferenceNumber: sourceBatch.ReferenceNumber,
+			}
+		}
+
+		result, err := s.stockPostingService.Post(tx, posting)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"received_quantity":    receivedQty,
+			"discrepancy_quantity": decimal.Zero,
+		}
+		if result.Batch != nil {
+			updates["dest_batch_id"] = result.Batch.ID
+		}
+
+		discrepancy := item.Quantity.Sub(receivedQty)
+		if discrepancy.IsPositive() {
+			lossNote := fmt.Sprintf("Transfer discrepancy (%s): shipped %s, received %s", reason, item.Quantity.String(), receivedQty.String())
+			lossPosting := &inventory.StockPosting{
+				TenantID:        transfer.TenantID,
+				CompanyID:       transfer.CompanyID,
+				WarehouseID:     transfer.DestWarehouseID,
+				ProductID:       item.ProductID,
+				MovementType:    lossMovementType(reason),
+				Quantity:        discrepancy.Neg(),
+				MovementDate:    receivedAt,
+				ReferenceType:   inventory.ReferenceTypeStockTransfer,
+				ReferenceID:     transfer.ID,
+				ReferenceNumber: transfer.TransferNumber,
+				Notes:           &lossNote,
+				CreatedBy:       userID,
+			}
+			if result.Batch != nil {
+				lossPosting.BatchID = &result.Batch.ID
+			}
+			if _, err := s.stockPostingService.Post(tx, lossPosting); err != nil {
+				return err
+			}
+
+			updates["discrepancy_quantity"] = discrepancy
+			updates["discrepancy_reason"] = reason
+		}
+
+		if err := tx.Model(&models.StockTransferItem{}).Where("id = ?", item.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update transfer item receipt: %w", err)
+		}
+
+		var destBatchID *string
+		if result.Batch != nil {
+			destBatchID = &result.Batch.ID
+		}
+		if err := s.receiveSerials(tx, transfer, &item, discrepancy, receiptByItem[item.ID].MissingSerialNumbers, destBatchID, receivedAt, userID); err != nil {
+			return err
+		}
+
+		if err := s.stockPostingService.AdjustInTransit(tx, transfer.DestWarehouseID, item.ProductID, item.Quantity.Neg()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assignSerials links the serials entered for a transfer line to it. They must be in stock at
+// the source warehouse; a batch-tracked line without a batch is pinned to the serials' batch.
+func (s *StockTransferService) assignSerials(tx *gorm.DB, transfer *models.StockTransfer, item *models.StockTransferItem, serialNumbers []string) error {
+	var product models.Product
+	if err := tx.Select("id", "code", "is_batch_tracked", "is_serial_tracked").Where("id = ?", item.ProductID).First(&product).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Product %s not found", item.ProductID))
+		}
+		return fmt.Errorf("failed to load product: %w", err)
+	}
+
+	if !product.IsSerialTracked {
+		if len(serialNumbers) > 0 {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Product %s is not serial-tracked", product.Code))
+		}
+		return nil
+	}
+
+	batchID, err := s.serialService.Assign(tx, &serial.SerialAssignment{
+		CompanyID:     transfer.CompanyID,
+		ProductID:     item.ProductID,
+		ReferenceType: inventory.ReferenceTypeStockTransfer,
+		ReferenceID:   transfer.ID,
+		LineID:        item.ID,
+		Quantity:      item.Quantity,
+		SerialNumbers: serialNumbers,
+		Status:        models.SerialStatusInStock,
+		WarehouseID:   &transfer.SourceWarehouseID,
+		BatchID:       item.BatchID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if product.IsBatchTracked && item.BatchID == nil {
+		if batchID == nil {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Serials of product %s come from several batches; enter one line per batch", product.Code))
+		}
+		if err := tx.Model(item).Update("batch_id", *batchID).Error; err != nil {
+			return fmt.Errorf("failed to assign batch to transfer item: %w", err)
+		}
+	}
+	return nil
+}
+
+// receiveSerials puts the serials of a received line in stock at the destination.
+// A shortfall on a serial-tracked line must name the missing serials, which are marked LOST.
+func (s *StockTransferService) receiveSerials(
+	tx *gorm.DB,
+	transfer *models.StockTransfer,
+	item *models.StockTransferItem,
+	discrepancy decimal.Decimal,
+	missingSerialNumbers []string,
+	destBatchID *string,
+	receivedAt time.Time,
+	userID string,
+) error {
+	serialTracked, err := serial.RequiresSerials(tx, item.ProductID)
+	if err != nil {
+		return err
+	}
+	if !serialTracked {
+		return nil
+	}
+
+	serials, err := s.serialService.LineSerials(tx, inventory.ReferenceTypeStockTransfer, item.ID)
+	if err != nil {
+		return err
+	}
+
+	onLine := make(map[string]bool, len(serials))
+	for _, productSerial := range serials {
+		onLine[productSerial.SerialNumber] = true
+	}
+	missing := make(map[string]bool, len(missingSerialNumbers))
+	for _, serialNumber := range missingSerialNumbers {
+		if !onLine[serialNumber] {
+			return pkgerrors.NewBadRequestError(fmt.Sprintf("Serial %s is not on transfer item %s", serialNumber, item.ID))
+		}
+		missing[serialNumber] = true
+	}
+	if !decimal.NewFromInt(int64(len(missing))).Equal(discrepancy) {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("Item %s: %s missing serial numbers required, got %d", item.ID, discrepancy.String(), len(missing)))
+	}
+
+	var arrived, lost []models.ProductSerial
+	for _, productSerial := range serials {
+		if missing[productSerial.SerialNumber] {
+			lost = append(lost, productSerial)
+		} else {
+			arrived = append(arrived, productSerial)
+		}
+	}
+
+	move := &serial.SerialMove{
+		TenantID:        transfer.TenantID,
+		CompanyID:       transfer.CompanyID,
+		EventType:       models.SerialEventTransferReceived,
+		EventDate:       receivedAt,
+		FromStatus:      models.SerialStatusInTransit,
+		Status:          models.SerialStatusInStock,
+		WarehouseID:     &transfer.DestWarehouseID,
+		BatchID:         destBatchID,
+		ReferenceType:   inventory.ReferenceTypeStockTransfer,
+		ReferenceID:     transfer.ID,
+		ReferenceNumber: transfer.TransferNumber,
+		CreatedBy:       &userID,
+	}
+	if err := s.serialService.Move(tx, arrived, move); err != nil {
+		return err
+	}
+
+	lostMove := *move
+	lostMove.EventType = models.SerialEventLost
+	lostMove.Status = models.SerialStatusLost
+	lostMove.WarehouseID = nil
+	return s.serialService.Move(tx, lost, &lostMove)
+}
+
+// moveItemSerials moves the serials of a serial-tracked transfer line
+func (s *StockTransferService) moveItemSerials(tx *gorm.DB, transfer *models.StockTransfer, itemID, productID string, move *serial.SerialMove) error {
+	serialTracked, err := serial.RequiresSerials(tx, productID)
+	if err != nil {
+		return err
+	}
+	if !serialTracked {
+		return nil
+	}
+
+	move.TenantID = transfer.TenantID
+	move.CompanyID = transfer.CompanyID
+	move.ReferenceType = inventory.ReferenceTypeStockTransfer
+	move.ReferenceID = transfer.ID
+	move.ReferenceNumber = transfer.TransferNumber
+	return s.serialService.MoveLine(tx, itemID, move)
+}
+
+// transferLossReason returns the company's reason for transfer discrepancies (SHRINKAGE when unset)
+func (s *StockTransferService) transferLossReason(tx *gorm.DB, companyID string) (models.InventoryAdjustmentReason, error) {
+	var company models.Company
+	if err := tx.Select("id", "transfer_loss_reason").Where("id = ?", companyID).First(&company).Error; err != nil {
+		return "", fmt.Errorf("failed to load company transfer settings: %w", err)
+	}
+
+	if company.TransferLossReason == "" {
+		return models.InventoryAdjustmentReasonShrinkage, nil
+	}
+	return company.TransferLossReason, nil
+}
+
+// lossMovementType maps a loss reason to the movement type written for it
+func lossMovementType(reason models.InventoryAdjustmentReason) models.MovementType {
+	if reason == models.InventoryAdjustmentReasonDamage {
+		return models.MovementTypeDamaged
+	}
+	return models.MovementTypeAdjustment
+}
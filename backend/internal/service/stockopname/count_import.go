@@ -0,0 +1,299 @@
+package stockopname
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// Accepted CSV header names per column (lower case)
+var countImportColumns = map[string][]string{
+	"identifier": {"product_code", "productcode", "code", "kode", "sku", "barcode", "product"},
+	"qty":        {"qty", "quantity", "counted_qty", "actual_qty", "actualqty", "count", "jumlah"},
+	"batch":      {"batch", "batch_number", "batchnumber", "batch_no"},
+	"bin":        {"bin", "bin_code", "location", "lokasi"},
+	"notes":      {"notes", "note", "catatan"},
+}
+
+// countImportMatch - Product (and base-unit multiplier) an identifier resolves to
+type countImportMatch struct {
+	ProductID  string
+	Multiplier decimal.Decimal
+}
+
+// ImportCountsCSV reads counted quantities from a CSV (spreadsheet or handheld scanner export) and applies them
+// to the opname's items through BatchUpdateStockOpnameItems.
+// Rows identify the product by code, product barcode or unit barcode (counted in that unit), optionally with a
+// batch number and bin code. Rows for the same item are added up (one row per scan). Rows that cannot be matched
+// are skipped and returned in the error report; the other rows are still applied.
+func (s *StockOpnameService) ImportCountsCSV(ctx context.Context, companyID, tenantID, opnameID, userID string, reader io.Reader, ipAddress, userAgent string) (*dto.ImportStockOpnameCountsResponse, error) {
+	opname, err := s.GetStockOpname(ctx, companyID, tenantID, opnameID)
+	if err != nil {
+		return nil, err
+	}
+	if opname.Status == models.StockOpnameStatusApproved {
+		return nil, pkgerrors.NewBadRequestError("cannot import counts into approved stock opname")
+	}
+	if opname.Status == models.StockOpnameStatusCancelled {
+		return nil, pkgerrors.NewBadRequestError("cannot import counts into cancelled stock opname")
+	}
+
+	records, lines, decimalComma, err := readCountImportCSV(reader)
+	if err != nil {
+		return nil, err
+	}
+	parseQty := func(text string) (decimal.Decimal, error) {
+		if decimalComma {
+			text = strings.ReplaceAll(text, ",", ".")
+		}
+		return decimal.NewFromString(text)
+	}
+
+	columns, dataStart, err := countImportLayout(records, parseQty)
+	if err != nil {
+		return nil, err
+	}
+
+	identifiers, err := s.countImportIdentifiers(ctx, tenantID, opname)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ImportStockOpnameCountsResponse{
+		Errors: []dto.StockOpnameCountImportError{},
+	}
+	counted := map[string]decimal.Decimal{}
+	notes := map[string]string{}
+
+	for i := dataStart; i < len(records); i++ {
+		record := records[i]
+		field := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		identifier := field("identifier")
+		qtyText := field("qty")
+		if identifier == "" && qtyText == "" {
+			continue // blank line
+		}
+		response.TotalRows++
+
+		reject := func(message string) {
+			response.Errors = append(response.Errors, dto.StockOpnameCountImportError{
+				Row:        lines[i],
+				Identifier: identifier,
+				Message:    message,
+			})
+		}
+
+		if identifier == "" {
+			reject("product code or barcode is required")
+			continue
+		}
+		qty, err := parseQty(qtyText)
+		if err != nil {
+			reject(fmt.Sprintf("invalid qty %q", qtyText))
+			continue
+		}
+		if qty.IsNegative() {
+			reject("qty cannot be negative")
+			continue
+		}
+
+		match, ok := identifiers[strings.ToLower(identifier)]
+		if !ok {
+			reject("no product with this code or barcode in the stock opname")
+			continue
+		}
+
+		item, message := matchCountImportItem(opname.Items, match.ProductID, field("batch"), field("bin"))
+		if item == nil {
+			reject(message)
+			continue
+		}
+
+		counted[item.ID] = counted[item.ID].Add(qty.Mul(match.Multiplier))
+		if rowNotes := field("notes"); rowNotes != "" {
+			notes[item.ID] = rowNotes
+		}
+		response.ImportedRows++
+	}
+
+	if len(counted) == 0 {
+		return response, nil
+	}
+
+	itemIDs := make([]string, 0, len(counted))
+	for itemID := range counted {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Strings(itemIDs)
+
+	req := &dto.BatchUpdateStockOpnameItemsRequest{
+		Items: make([]dto.BatchUpdateStockOpnameItemRequest, len(itemIDs)),
+	}
+	for i, itemID := range itemIDs {
+		actualQty := counted[itemID].String()
+		req.Items[i] = dto.BatchUpdateStockOpnameItemRequest{
+			ItemID:    itemID,
+			ActualQty: &actualQty,
+		}
+		if itemNotes, ok := notes[itemID]; ok {
+			req.Items[i].Notes = &itemNotes
+		}
+	}
+
+	if _, err := s.BatchUpdateStockOpnameItems(ctx, companyID, tenantID, opnameID, userID, req, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+	response.UpdatedItems = len(itemIDs)
+
+	return response, nil
+}
+
+// readCountImportCSV reads all records with their line numbers, accepting a UTF-8 BOM and semicolon-separated
+// files. Semicolon files come from spreadsheets using a decimal comma, reported by decimalComma.
+func readCountImportCSV(reader io.Reader) (records [][]string, lines []int, decimalComma bool, err error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	firstLine, _ := bufio.NewReader(bytes.NewReader(content)).ReadString('\n')
+	if strings.Count(firstLine, ";") > 0 && strings.Count(firstLine, ";") >= strings.Count(firstLine, ",") {
+		csvReader.Comma = ';'
+		decimalComma = true
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, false, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid CSV: %v", err))
+		}
+		line, _ := csvReader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if len(records) == 0 {
+		return nil, nil, false, pkgerrors.NewBadRequestError("CSV file is empty")
+	}
+	return records, lines, decimalComma, nil
+}
+
+// countImportLayout finds the column of each field from the header row. Files without a header
+// (plain handheld exports) are read as identifier, qty, batch.
+func countImportLayout(records [][]string, parseQty func(string) (decimal.Decimal, error)) (map[string]int, int, error) {
+	columns := map[string]int{}
+	for index, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for column, aliases := range countImportColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					if _, exists := columns[column]; !exists {
+						columns[column] = index
+					}
+				}
+			}
+		}
+	}
+
+	_, hasIdentifier := columns["identifier"]
+	_, hasQty := columns["qty"]
+	if hasIdentifier && hasQty {
+		return columns, 1, nil
+	}
+
+	if len(records[0]) >= 2 {
+		if _, err := parseQty(strings.TrimSpace(records[0][1])); err == nil {
+			return map[string]int{"identifier": 0, "qty": 1, "batch": 2}, 0, nil
+		}
+	}
+
+	return nil, 0, pkgerrors.NewBadRequestError("CSV needs a header with a product code or barcode column and a qty column")
+}
+
+// countImportIdentifiers maps product codes, product barcodes and unit barcodes (lower case) of the opname's products
+func (s *StockOpnameService) countImportIdentifiers(ctx context.Context, tenantID string, opname *models.StockOpname) (map[string]countImportMatch, error) {
+	identifiers := map[string]countImportMatch{}
+	productIDs := make([]string, 0, len(opname.Items))
+	for _, item := range opname.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var units []models.ProductUnit
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("product_id IN ? AND barcode IS NOT NULL AND barcode <> '' AND is_active = ?", productIDs, true).
+		Find(&units).Error; err != nil {
+		return nil, fmt.Errorf("failed to get product units: %w", err)
+	}
+	for _, unit := range units {
+		identifiers[strings.ToLower(*unit.Barcode)] = countImportMatch{ProductID: unit.ProductID, Multiplier: unit.ConversionRate}
+	}
+
+	// Product code and barcode win over unit barcodes
+	for _, item := range opname.Items {
+		match := countImportMatch{ProductID: item.ProductID, Multiplier: decimal.NewFromInt(1)}
+		if item.Product.Barcode != nil && *item.Product.Barcode != "" {
+			identifiers[strings.ToLower(*item.Product.Barcode)] = match
+		}
+		if item.Product.Code != "" {
+			identifiers[strings.ToLower(item.Product.Code)] = match
+		}
+	}
+
+	return identifiers, nil
+}
+
+// matchCountImportItem picks the opname item for a product, narrowed by batch number and bin code when given.
+// Returns nil and the reason when no single item matches.
+func matchCountImportItem(items []models.StockOpnameItem, productID, batchNumber, binCode string) (*models.StockOpnameItem, string) {
+	var candidates []*models.StockOpnameItem
+	for i := range items {
+		item := &items[i]
+		if item.ProductID != productID {
+			continue
+		}
+		if batchNumber != "" && (item.Batch == nil || !strings.EqualFold(item.Batch.BatchNumber, batchNumber)) {
+			continue
+		}
+		if binCode != "" && (item.Bin == nil || !strings.EqualFold(item.Bin.Code, binCode)) {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	switch len(candidates) {
+	case 1:
+		return candidates[0], ""
+	case 0:
+		if batchNumber != "" || binCode != "" {
+			return nil, "no line for this product with the given batch/bin in the stock opname"
+		}
+		return nil, "product is not in the stock opname"
+	default:
+		return nil, fmt.Sprintf("product has %d lines in the stock opname, give the batch or bin", len(candidates))
+	}
+}
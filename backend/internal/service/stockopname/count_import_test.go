@@ -0,0 +1,122 @@
+package stockopname
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockOpnameService_CountSheetAndImport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+
+	barcode := "8990001"
+	boxBarcode := "8990024"
+	loose := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "P1", Name: "Gula 1kg", BaseUnit: "PCS", Barcode: &barcode, IsActive: true}
+	require.NoError(t, db.Create(loose).Error)
+	require.NoError(t, db.Create(&models.ProductUnit{ProductID: loose.ID, UnitName: "KARTON", ConversionRate: decimal.NewFromInt(24), Barcode: &boxBarcode, IsActive: true}).Error)
+	batched := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "P2", Name: "Minyak 2L", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(batched).Error)
+
+	stock := &models.WarehouseStock{WarehouseID: warehouse.ID, ProductID: batched.ID}
+	require.NoError(t, db.Create(stock).Error)
+	batchA := &models.ProductBatch{BatchNumber: "B-1", ProductID: batched.ID, WarehouseStockID: stock.ID, ReceiptDate: time.Now()}
+	require.NoError(t, db.Create(batchA).Error)
+	batchB := &models.ProductBatch{BatchNumber: "B-2", ProductID: batched.ID, WarehouseStockID: stock.ID, ReceiptDate: time.Now()}
+	require.NoError(t, db.Create(batchB).Error)
+
+	opname := &models.StockOpname{
+		TenantID:     company.TenantID,
+		CompanyID:    company.ID,
+		OpnameNumber: "OPN-20260301-001",
+		OpnameDate:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		WarehouseID:  warehouse.ID,
+		Status:       models.StockOpnameStatusInProgress,
+	}
+	require.NoError(t, db.Create(opname).Error)
+	for _, item := range []*models.StockOpnameItem{
+		{StockOpnameID: opname.ID, ProductID: loose.ID, SystemQty: decimal.NewFromInt(30)},
+		{StockOpnameID: opname.ID, ProductID: batched.ID, BatchID: &batchA.ID, SystemQty: decimal.NewFromInt(4)},
+		{StockOpnameID: opname.ID, ProductID: batched.ID, BatchID: &batchB.ID, SystemQty: decimal.NewFromInt(6)},
+	} {
+		require.NoError(t, db.Create(item).Error)
+	}
+
+	service := NewStockOpnameService(db, nil, nil)
+	ctx := context.Background()
+	physicalQty := func(productID string, batchID *string) string {
+		var item models.StockOpnameItem
+		query := db.Where("stock_opname_id = ? AND product_id = ?", opname.ID, productID)
+		if batchID != nil {
+			query = query.Where("batch_id = ?", *batchID)
+		}
+		require.NoError(t, query.First(&item).Error)
+		return item.PhysicalQty.String()
+	}
+
+	t.Run("success - count sheet PDF", func(t *testing.T) {
+		loaded, err := service.GetStockOpname(ctx, company.ID, company.TenantID, opname.ID)
+		require.NoError(t, err)
+
+		pdfContent, err := service.GenerateCountSheetPDF(ctx, company.TenantID, loaded)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(pdfContent), "%PDF"))
+	})
+
+	t.Run("success - rows by code, barcode and unit barcode with a per-row error report", func(t *testing.T) {
+		csvContent := "\xef\xbb\xbfKode,Qty,Batch\n" +
+			"P1,10\n" +
+			"8990024,1\n" + // one carton = 24 PCS, added to the row above
+			"P2,5\n" +
+			"p2,3,b-1\n" +
+			"\n" +
+			"UNKNOWN,1\n" +
+			"P1,abc\n"
+
+		result, err := service.ImportCountsCSV(ctx, company.ID, company.TenantID, opname.ID, "user-1", strings.NewReader(csvContent), "", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, 6, result.TotalRows)
+		assert.Equal(t, 3, result.ImportedRows)
+		assert.Equal(t, 2, result.UpdatedItems)
+		require.Len(t, result.Errors, 3)
+		assert.Equal(t, 4, result.Errors[0].Row)
+		assert.Contains(t, result.Errors[0].Message, "give the batch or bin")
+		assert.Equal(t, 7, result.Errors[1].Row)
+		assert.Equal(t, "UNKNOWN", result.Errors[1].Identifier)
+		assert.Equal(t, 8, result.Errors[2].Row)
+		assert.Contains(t, result.Errors[2].Message, "invalid qty")
+
+		assert.Equal(t, "34", physicalQty(loose.ID, nil))
+		assert.Equal(t, "3", physicalQty(batched.ID, &batchA.ID))
+		assert.Equal(t, "0", physicalQty(batched.ID, &batchB.ID))
+	})
+
+	t.Run("success - headerless handheld file with semicolons and decimal comma", func(t *testing.T) {
+		result, err := service.ImportCountsCSV(ctx, company.ID, company.TenantID, opname.ID, "user-1", strings.NewReader("8990001;2,5\nP2;7;B-2\n"), "", "")
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, "2.5", physicalQty(loose.ID, nil))
+		assert.Equal(t, "7", physicalQty(batched.ID, &batchB.ID))
+	})
+
+	t.Run("error - file without qty column", func(t *testing.T) {
+		_, err := service.ImportCountsCSV(ctx, company.ID, company.TenantID, opname.ID, "user-1", strings.NewReader("code,name\nP1,Gula\n"), "", "")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "qty column")
+	})
+}
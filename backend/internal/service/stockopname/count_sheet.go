@@ -0,0 +1,244 @@
+package stockopname
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"backend/models"
+)
+
+// countSheetLine - One printed line of a count sheet
+type countSheetLine struct {
+	Location    string
+	ProductCode string
+	ProductName string
+	BatchNumber string
+	Unit        string
+	SystemQty   string
+}
+
+// GenerateCountSheetPDF generates a printable count sheet (lembar hitung) for counters, sorted by location.
+// Lines without a bin show the bins that hold the product in the warehouse; the actual qty column is left blank.
+// System quantities are printed only when the opname does not hide them (blind count).
+func (s *StockOpnameService) GenerateCountSheetPDF(ctx context.Context, tenantID string, opname *models.StockOpname) ([]byte, error) {
+	lines, err := s.countSheetLines(ctx, tenantID, opname)
+	if err != nil {
+		return nil, err
+	}
+	showSystemQty := !opname.HidesSystemQty()
+
+	// Initialize PDF with A4 size, portrait orientation
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s - Hal. %d/{nb}", opname.OpnameNumber, pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	// ============================================================================
+	// HEADER - COUNT SHEET TITLE
+	// ============================================================================
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "LEMBAR HITUNG STOK OPNAME", "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+
+	// ============================================================================
+	// OPNAME INFO SECTION
+	// ============================================================================
+	info := [][2]string{
+		{"No. Opname:", opname.OpnameNumber},
+		{"Tanggal:", opname.OpnameDate.Format("02 January 2006")},
+		{"Gudang:", opname.Warehouse.Name},
+		{"Dicetak:", time.Now().Format("02 January 2006 15:04")},
+	}
+	if opname.IsBlindCount {
+		info = append(info, [2]string{"Metode:", "Blind count (qty sistem disembunyikan)"})
+	}
+	for _, row := range info {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(35, 6, row[0])
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(0, 6, row[1])
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	// ============================================================================
+	// ITEMS TABLE
+	// ============================================================================
+	headers := []string{"No", "Lokasi", "Kode", "Nama Produk", "Batch", "Unit"}
+	widths := []float64{9, 24, 24, 52, 22, 13}
+	if showSystemQty {
+		headers = append(headers, "Qty Sistem")
+		widths = append(widths, 18)
+	} else {
+		widths[3] += 18
+	}
+	headers = append(headers, "Qty Fisik", "Catatan")
+	widths = append(widths, 18, 0)
+
+	// Notes column takes the remaining width
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	used := 0.0
+	for _, w := range widths[:len(widths)-1] {
+		used += w
+	}
+	widths[len(widths)-1] = pageWidth - left - right - used
+
+	printHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(240, 240, 240)
+		for i, header := range headers {
+			ln := 0
+			if i == len(headers)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(widths[i], 8, header, "1", ln, "C", true, 0, "")
+		}
+		pdf.SetFont("Arial", "", 8)
+	}
+	printHeader()
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	for i, line := range lines {
+		// Repeat the table header on every page
+		if pdf.GetY()+8 > pageHeight-bottom {
+			pdf.AddPage()
+			printHeader()
+		}
+
+		cells := []string{fmt.Sprintf("%d", i+1), line.Location, line.ProductCode, line.ProductName, line.BatchNumber, line.Unit}
+		aligns := []string{"C", "L", "L", "L", "C", "C"}
+		if showSystemQty {
+			cells = append(cells, line.SystemQty)
+			aligns = append(aligns, "R")
+		}
+		cells = append(cells, "", "")
+		aligns = append(aligns, "R", "L")
+
+		for j, cell := range cells {
+			ln := 0
+			if j == len(cells)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(widths[j], 8, fitText(pdf, cell, widths[j]), "1", ln, aligns[j], false, 0, "")
+		}
+	}
+
+	// ============================================================================
+	// SIGNATURES
+	// ============================================================================
+	if pdf.GetY()+35 > pageHeight-bottom {
+		pdf.AddPage()
+	}
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "", 10)
+	columnWidth := (pageWidth - left - right) / 2
+	pdf.CellFormat(columnWidth, 6, "Dihitung oleh,", "", 0, "C", false, 0, "")
+	pdf.CellFormat(columnWidth, 6, "Diperiksa oleh,", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.CellFormat(columnWidth, 6, "(____________________)", "", 0, "C", false, 0, "")
+	pdf.CellFormat(columnWidth, 6, "(____________________)", "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate count sheet PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// countSheetLines builds the printed lines sorted by location then product code; lines without a location come last
+func (s *StockOpnameService) countSheetLines(ctx context.Context, tenantID string, opname *models.StockOpname) ([]countSheetLine, error) {
+	// Bins holding each unbinned product, for counters to know where to look
+	var productIDs []string
+	for _, item := range opname.Items {
+		if item.BinID == nil {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	binsByProduct := map[string][]string{}
+	if len(productIDs) > 0 {
+		var rows []struct {
+			ProductID string
+			Code      string
+		}
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+			Model(&models.BinStock{}).
+			Select("DISTINCT bin_stocks.product_id, warehouse_bins.code").
+			Joins("JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.warehouse_bin_id").
+			Where("warehouse_bins.warehouse_id = ? AND bin_stocks.product_id IN ? AND bin_stocks.quantity > 0", opname.WarehouseID, productIDs).
+			Order("warehouse_bins.code ASC").
+			Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to get product bins: %w", err)
+		}
+		for _, row := range rows {
+			binsByProduct[row.ProductID] = append(binsByProduct[row.ProductID], row.Code)
+		}
+	}
+
+	lines := make([]countSheetLine, len(opname.Items))
+	for i, item := range opname.Items {
+		line := countSheetLine{
+			ProductCode: item.Product.Code,
+			ProductName: item.Product.Name,
+			BatchNumber: "-",
+			Unit:        item.Product.BaseUnit,
+			SystemQty:   item.SystemQty.String(),
+		}
+		if item.Bin != nil {
+			line.Location = item.Bin.Code
+		} else {
+			line.Location = strings.Join(binsByProduct[item.ProductID], ", ")
+		}
+		if item.Batch != nil {
+			line.BatchNumber = item.Batch.BatchNumber
+		}
+		lines[i] = line
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if (a.Location == "") != (b.Location == "") {
+			return a.Location != ""
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.ProductCode != b.ProductCode {
+			return a.ProductCode < b.ProductCode
+		}
+		return a.BatchNumber < b.BatchNumber
+	})
+
+	for i := range lines {
+		if lines[i].Location == "" {
+			lines[i].Location = "-"
+		}
+	}
+	return lines, nil
+}
+
+// fitText shortens text with "..." so it fits in a table cell of the given width
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	maxWidth := width - 2
+	if pdf.GetStringWidth(text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,593 @@
+package stockopname
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ABC classes by share of usage value: A up to 80%, B up to 95%, C the rest
+var (
+	abcClassALimit = decimal.NewFromFloat(0.80)
+	abcClassBLimit = decimal.NewFromFloat(0.95)
+)
+
+// abcUsageDays is the outbound history used to rank products for ABC plans
+const abcUsageDays = 365
+
+// CycleCountService - Cycle-count plans that create DRAFT stock opnames on a rolling calendar
+type CycleCountService struct {
+	db                 *gorm.DB
+	stockOpnameService *StockOpnameService
+}
+
+// NewCycleCountService creates a new cycle-count service instance
+func NewCycleCountService(db *gorm.DB, stockOpnameService *StockOpnameService) *CycleCountService {
+	return &CycleCountService{
+		db:                 db,
+		stockOpnameService: stockOpnameService,
+	}
+}
+
+// cycleCountLine - A line to count: a product (ABC), or a product/batch in one bin (LOCATION)
+type cycleCountLine struct {
+	ProductID string
+	BatchID   *string
+	BinID     *string
+	SystemQty decimal.Decimal
+}
+
+// ============================================================================
+// PLAN CRUD
+// ============================================================================
+
+// ListPlans returns the cycle-count plans of a company
+func (s *CycleCountService) ListPlans(ctx context.Context, tenantID, companyID string) ([]models.CycleCountPlan, error) {
+	var plans []models.CycleCountPlan
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Warehouse").
+		Where("company_id = ?", companyID).
+		Order("name ASC").
+		Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list cycle count plans: %w", err)
+	}
+	return plans, nil
+}
+
+// GetPlan returns a cycle-count plan by ID
+func (s *CycleCountService) GetPlan(ctx context.Context, tenantID, companyID, planID string) (*models.CycleCountPlan, error) {
+	var plan models.CycleCountPlan
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Preload("Warehouse").
+		Where("id = ? AND company_id = ?", planID, companyID).
+		First(&plan).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("cycle count plan")
+		}
+		return nil, fmt.Errorf("failed to get cycle count plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// CreatePlan creates a cycle-count plan; the first count is created on startDate (default today)
+func (s *CycleCountService) CreatePlan(ctx context.Context, tenantID, companyID, userID string, req *dto.CreateCycleCountPlanRequest) (*models.CycleCountPlan, error) {
+	var warehouse models.Warehouse
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("id = ? AND company_id = ?", req.WarehouseID, companyID).
+		First(&warehouse).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkgerrors.NewNotFoundError("warehouse")
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	if models.CycleCountMethod(req.Method) == models.CycleCountMethodLocation {
+		var binCount int64
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.WarehouseBin{}).
+			Where("warehouse_id = ? AND is_active = ?", warehouse.ID, true).
+			Count(&binCount).Error; err != nil {
+			return nil, fmt.Errorf("failed to count warehouse bins: %w", err)
+		}
+		if binCount == 0 {
+			return nil, pkgerrors.NewBadRequestError("LOCATION plans need a warehouse with active bins")
+		}
+	}
+
+	now := time.Now()
+	nextRunDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.StartDate != nil && *req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid startDate format, expected YYYY-MM-DD")
+		}
+		nextRunDate = startDate
+	}
+
+	plan := &models.CycleCountPlan{
+		TenantID:      tenantID,
+		CompanyID:     companyID,
+		Name:          req.Name,
+		WarehouseID:   warehouse.ID,
+		Method:        models.CycleCountMethod(req.Method),
+		FrequencyDays: defaultDays(req.FrequencyDays, 7),
+		ClassADays:    defaultDays(req.ClassADays, 30),
+		ClassBDays:    defaultDays(req.ClassBDays, 90),
+		ClassCDays:    defaultDays(req.ClassCDays, 180),
+		CycleDays:     defaultDays(req.CycleDays, 90),
+		IsActive:      true,
+		NextRunDate:   nextRunDate,
+		Notes:         req.Notes,
+	}
+	blindCount := req.BlindCount == nil || *req.BlindCount
+	plan.BlindCount = blindCount
+	if userID != "" {
+		plan.CreatedBy = &userID
+	}
+
+	// Explicit false would be replaced by the column default on create
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(plan).Error; err != nil {
+			return fmt.Errorf("failed to create cycle count plan: %w", err)
+		}
+		if !blindCount {
+			if err := tx.Model(plan).Update("blind_count", false).Error; err != nil {
+				return fmt.Errorf("failed to create cycle count plan: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlan(ctx, tenantID, companyID, plan.ID)
+}
+
+// UpdatePlan updates the schedule and settings of a cycle-count plan
+func (s *CycleCountService) UpdatePlan(ctx context.Context, tenantID, companyID, planID string, req *dto.UpdateCycleCountPlanRequest) (*models.CycleCountPlan, error) {
+	plan, err := s.GetPlan(ctx, tenantID, companyID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.FrequencyDays != nil {
+		updates["frequency_days"] = *req.FrequencyDays
+	}
+	if req.ClassADays != nil {
+		updates["class_a_days"] = *req.ClassADays
+	}
+	if req.ClassBDays != nil {
+		updates["class_b_days"] = *req.ClassBDays
+	}
+	if req.ClassCDays != nil {
+		updates["class_c_days"] = *req.ClassCDays
+	}
+	if req.CycleDays != nil {
+		updates["cycle_days"] = *req.CycleDays
+	}
+	if req.BlindCount != nil {
+		updates["blind_count"] = *req.BlindCount
+	}
+	if req.NextRunDate != nil {
+		nextRunDate, err := time.Parse("2006-01-02", *req.NextRunDate)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid nextRunDate format, expected YYYY-MM-DD")
+		}
+		updates["next_run_date"] = nextRunDate
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.Notes != nil {
+		updates["notes"] = req.Notes
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(plan).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update cycle count plan: %w", err)
+		}
+	}
+
+	return s.GetPlan(ctx, tenantID, companyID, planID)
+}
+
+// DeletePlan deletes a cycle-count plan; opnames it created are kept
+func (s *CycleCountService) DeletePlan(ctx context.Context, tenantID, companyID, planID string) error {
+	plan, err := s.GetPlan(ctx, tenantID, companyID, planID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cycle_count_plan_id = ?", plan.ID).Delete(&models.CycleCountCoverage{}).Error; err != nil {
+			return fmt.Errorf("failed to delete cycle count coverage: %w", err)
+		}
+		if err := tx.Model(&models.StockOpname{}).Where("cycle_count_plan_id = ?", plan.ID).Update("cycle_count_plan_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to detach stock opnames: %w", err)
+		}
+		if err := tx.Delete(plan).Error; err != nil {
+			return fmt.Errorf("failed to delete cycle count plan: %w", err)
+		}
+		return nil
+	})
+}
+
+// ============================================================================
+// SCHEDULING
+// ============================================================================
+
+// RunPlan creates the plan's next count now and schedules the following one FrequencyDays from today.
+// Returns a nil opname when nothing is due (e.g. an empty warehouse).
+func (s *CycleCountService) RunPlan(ctx context.Context, tenantID, companyID, planID string) (*models.StockOpname, *models.CycleCountPlan, error) {
+	plan, err := s.GetPlan(ctx, tenantID, companyID, planID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var opname *models.StockOpname
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		opname, err = s.generate(tx, plan, now)
+		if err != nil {
+			return err
+		}
+		return tx.Model(plan).Updates(map[string]interface{}{
+			"next_run_date": today.AddDate(0, 0, plan.FrequencyDays),
+			"last_run_at":   now,
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan, err = s.GetPlan(ctx, tenantID, companyID, planID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opname, plan, nil
+}
+
+// RunDuePlans runs every active plan whose next run date has arrived and advances it by
+// FrequencyDays until it is in the future. Returns the number of stock opnames created.
+// A plan that fails is logged and left due, so it is retried on the next run.
+func (s *CycleCountService) RunDuePlans(ctx context.Context, now time.Time) (int, error) {
+	// System job across all tenants
+	var plans []models.CycleCountPlan
+	if err := s.db.WithContext(ctx).Set("bypass_tenant", true).
+		Where("is_active = ? AND next_run_date <= ?", true, now).
+		Find(&plans).Error; err != nil {
+		return 0, fmt.Errorf("failed to list due cycle count plans: %w", err)
+	}
+
+	created := 0
+	for i := range plans {
+		plan := &plans[i]
+
+		nextRunDate := plan.NextRunDate
+		for !nextRunDate.After(now) {
+			nextRunDate = nextRunDate.AddDate(0, 0, plan.FrequencyDays)
+		}
+
+		var opname *models.StockOpname
+		err := s.db.WithContext(ctx).Set("tenant_id", plan.TenantID).Transaction(func(tx *gorm.DB) error {
+			var err error
+			opname, err = s.generate(tx, plan, now)
+			if err != nil {
+				return err
+			}
+			return tx.Model(plan).Updates(map[string]interface{}{
+				"next_run_date": nextRunDate,
+				"last_run_at":   now,
+			}).Error
+		})
+		if err != nil {
+			log.Printf("[ERROR][INVENTORY] Cycle count plan %s (%s) failed: %v", plan.ID, plan.Name, err)
+			continue
+		}
+		if opname != nil {
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// generate selects the lines due under the plan and creates a DRAFT stock opname for them
+func (s *CycleCountService) generate(tx *gorm.DB, plan *models.CycleCountPlan, now time.Time) (*models.StockOpname, error) {
+	var lines []cycleCountLine
+	var subjectIDs []string
+	var summary string
+	var err error
+
+	switch plan.Method {
+	case models.CycleCountMethodABC:
+		lines, subjectIDs, summary, err = s.selectABC(tx, plan, now)
+	case models.CycleCountMethodLocation:
+		lines, subjectIDs, summary, err = s.selectLocations(tx, plan)
+	default:
+		return nil, pkgerrors.NewBadRequestError(fmt.Sprintf("unsupported cycle count method %s", plan.Method))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	opnameDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	opnameNumber, err := s.stockOpnameService.generateOpnameNumber(tx, plan.CompanyID, opnameDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate opname number: %w", err)
+	}
+
+	notes := fmt.Sprintf("Cycle count %s: %s", plan.Name, summary)
+	opname := &models.StockOpname{
+		TenantID:         plan.TenantID,
+		CompanyID:        plan.CompanyID,
+		OpnameNumber:     opnameNumber,
+		OpnameDate:       opnameDate,
+		WarehouseID:      plan.WarehouseID,
+		Status:           models.StockOpnameStatusDraft,
+		IsBlindCount:     plan.BlindCount,
+		CycleCountPlanID: &plan.ID,
+		Notes:            &notes,
+	}
+	if err := tx.Create(opname).Error; err != nil {
+		return nil, fmt.Errorf("failed to create stock opname: %w", err)
+	}
+
+	for _, line := range lines {
+		item := &models.StockOpnameItem{
+			StockOpnameID: opname.ID,
+			ProductID:     line.ProductID,
+			BatchID:       line.BatchID,
+			BinID:         line.BinID,
+			SystemQty:     line.SystemQty,
+			PhysicalQty:   decimal.Zero,                     // Counter will fill this
+			DifferenceQty: decimal.Zero.Sub(line.SystemQty), // Negative until counted
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return nil, fmt.Errorf("failed to create stock opname item: %w", err)
+		}
+		opname.Items = append(opname.Items, *item)
+	}
+
+	for _, subjectID := range subjectIDs {
+		var coverage models.CycleCountCoverage
+		err := tx.Where("cycle_count_plan_id = ? AND subject_id = ?", plan.ID, subjectID).First(&coverage).Error
+		if err == gorm.ErrRecordNotFound {
+			coverage = models.CycleCountCoverage{CycleCountPlanID: plan.ID, SubjectID: subjectID}
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get cycle count coverage: %w", err)
+		}
+		coverage.StockOpnameID = opname.ID
+		coverage.LastScheduledAt = now
+		if err := tx.Save(&coverage).Error; err != nil {
+			return nil, fmt.Errorf("failed to save cycle count coverage: %w", err)
+		}
+	}
+
+	return opname, nil
+}
+
+// selectABC ranks the warehouse's products by outbound value over the last year into A/B/C classes
+// and picks from each class the products counted longest ago, enough to cover the class every
+// ClassXDays when a count is created every FrequencyDays
+func (s *CycleCountService) selectABC(tx *gorm.DB, plan *models.CycleCountPlan, now time.Time) ([]cycleCountLine, []string, string, error) {
+	type candidate struct {
+		ProductID   string
+		ProductCode string
+		Quantity    decimal.Decimal
+		UsageValue  decimal.Decimal
+	}
+
+	var candidates []candidate
+	if err := tx.Model(&models.WarehouseStock{}).
+		Select("warehouse_stocks.product_id as product_id, products.code as product_code, warehouse_stocks.quantity as quantity").
+		Joins("JOIN products ON products.id = warehouse_stocks.product_id").
+		Where("warehouse_stocks.warehouse_id = ? AND products.is_active = ?", plan.WarehouseID, true).
+		Scan(&candidates).Error; err != nil {
+		return nil, nil, "", fmt.Errorf("failed to get warehouse stocks: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, "", nil
+	}
+
+	var usage []struct {
+		ProductID string
+		Value     decimal.Decimal
+	}
+	if err := tx.Model(&models.InventoryMovement{}).
+		Select("product_id, COALESCE(SUM(ABS(total_cost)), 0) as value").
+		Where("warehouse_id = ? AND quantity < 0 AND movement_type = ? AND movement_date >= ?",
+			plan.WarehouseID, models.MovementTypeOut, now.AddDate(0, 0, -abcUsageDays)).
+		Group("product_id").
+		Scan(&usage).Error; err != nil {
+		return nil, nil, "", fmt.Errorf("failed to sum product usage: %w", err)
+	}
+	usageByProduct := map[string]decimal.Decimal{}
+	for _, row := range usage {
+		usageByProduct[row.ProductID] = row.Value
+	}
+
+	totalUsage := decimal.Zero
+	for i := range candidates {
+		candidates[i].UsageValue = usageByProduct[candidates[i].ProductID]
+		totalUsage = totalUsage.Add(candidates[i].UsageValue)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if !candidates[i].UsageValue.Equal(candidates[j].UsageValue) {
+			return candidates[i].UsageValue.GreaterThan(candidates[j].UsageValue)
+		}
+		return candidates[i].ProductCode < candidates[j].ProductCode
+	})
+
+	// A product belongs to the class its cumulative share starts in; no usage means class C
+	classes := map[string][]candidate{}
+	cumulative := decimal.Zero
+	for _, c := range candidates {
+		class := "C"
+		if totalUsage.IsPositive() && c.UsageValue.IsPositive() {
+			share := cumulative.Div(totalUsage)
+			if share.LessThan(abcClassALimit) {
+				class = "A"
+			} else if share.LessThan(abcClassBLimit) {
+				class = "B"
+			}
+		}
+		cumulative = cumulative.Add(c.UsageValue)
+		classes[class] = append(classes[class], c)
+	}
+
+	lastCounted, err := s.lastScheduled(tx, plan.ID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	cycleDays := map[string]int{"A": plan.ClassADays, "B": plan.ClassBDays, "C": plan.ClassCDays}
+	var lines []cycleCountLine
+	var subjectIDs []string
+	var summary []string
+	for _, class := range []string{"A", "B", "C"} {
+		members := classes[class]
+		if len(members) == 0 {
+			continue
+		}
+
+		ids := make([]string, len(members))
+		for i, member := range members {
+			ids[i] = member.ProductID
+		}
+		due := pickOldest(ids, lastCounted, quota(len(members), plan.FrequencyDays, cycleDays[class]))
+
+		byID := map[string]candidate{}
+		for _, member := range members {
+			byID[member.ProductID] = member
+		}
+		for _, productID := range due {
+			lines = append(lines, cycleCountLine{ProductID: productID, SystemQty: byID[productID].Quantity})
+			subjectIDs = append(subjectIDs, productID)
+		}
+		summary = append(summary, fmt.Sprintf("%s %d/%d", class, len(due), len(members)))
+	}
+
+	return lines, subjectIDs, "class " + strings.Join(summary, ", "), nil
+}
+
+// selectLocations picks the stocked bins counted longest ago, enough to cover every bin each
+// CycleDays when a count is created every FrequencyDays, and lists their contents per product and batch.
+// Empty bins have nothing to list and are left out of the rotation.
+func (s *CycleCountService) selectLocations(tx *gorm.DB, plan *models.CycleCountPlan) ([]cycleCountLine, []string, string, error) {
+	var bins []models.WarehouseBin
+	if err := tx.Where("warehouse_id = ? AND is_active = ?", plan.WarehouseID, true).
+		Where("EXISTS (SELECT 1 FROM bin_stocks WHERE bin_stocks.warehouse_bin_id = warehouse_bins.id AND bin_stocks.quantity > 0)").
+		Order("code ASC").
+		Find(&bins).Error; err != nil {
+		return nil, nil, "", fmt.Errorf("failed to list warehouse bins: %w", err)
+	}
+	if len(bins) == 0 {
+		return nil, nil, "", nil
+	}
+
+	lastCounted, err := s.lastScheduled(tx, plan.ID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	ids := make([]string, len(bins))
+	codes := map[string]string{}
+	for i, bin := range bins {
+		ids[i] = bin.ID
+		codes[bin.ID] = bin.Code
+	}
+	due := pickOldest(ids, lastCounted, quota(len(bins), plan.FrequencyDays, plan.CycleDays))
+
+	var binStocks []models.BinStock
+	if err := tx.Where("warehouse_bin_id IN ? AND quantity > 0", due).
+		Order("warehouse_bin_id ASC, product_id ASC").
+		Find(&binStocks).Error; err != nil {
+		return nil, nil, "", fmt.Errorf("failed to list bin stocks: %w", err)
+	}
+
+	lines := make([]cycleCountLine, len(binStocks))
+	for i, binStock := range binStocks {
+		binID := binStock.WarehouseBinID
+		lines[i] = cycleCountLine{
+			ProductID: binStock.ProductID,
+			BatchID:   binStock.BatchID,
+			BinID:     &binID,
+			SystemQty: binStock.Quantity,
+		}
+	}
+
+	dueCodes := make([]string, len(due))
+	for i, binID := range due {
+		dueCodes[i] = codes[binID]
+	}
+	return lines, due, "bins " + strings.Join(dueCodes, ", "), nil
+}
+
+// lastScheduled maps the plan's products or bins to when they were last scheduled for counting
+func (s *CycleCountService) lastScheduled(tx *gorm.DB, planID string) (map[string]time.Time, error) {
+	var coverages []models.CycleCountCoverage
+	if err := tx.Where("cycle_count_plan_id = ?", planID).Find(&coverages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cycle count coverage: %w", err)
+	}
+
+	lastCounted := make(map[string]time.Time, len(coverages))
+	for _, coverage := range coverages {
+		lastCounted[coverage.SubjectID] = coverage.LastScheduledAt
+	}
+	return lastCounted, nil
+}
+
+// pickOldest returns up to n IDs, never-counted first, then the longest since their last count.
+// Ties keep the given order.
+func pickOldest(ids []string, lastCounted map[string]time.Time, n int) []string {
+	ordered := make([]string, len(ids))
+	copy(ordered, ids)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, aCounted := lastCounted[ordered[i]]
+		b, bCounted := lastCounted[ordered[j]]
+		if aCounted != bCounted {
+			return !aCounted
+		}
+		return a.Before(b)
+	})
+
+	if n > len(ordered) {
+		n = len(ordered)
+	}
+	return ordered[:n]
+}
+
+// quota is how many of size subjects to count per run so all are covered every cycleDays
+func quota(size, frequencyDays, cycleDays int) int {
+	if cycleDays <= 0 {
+		return size
+	}
+	return int(math.Ceil(float64(size) * float64(frequencyDays) / float64(cycleDays)))
+}
+
+func defaultDays(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,231 @@
+package stockopname
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCycleCountTest(t *testing.T) (*gorm.DB, *models.Company, *models.Warehouse, *CycleCountService) {
+	db := testutil.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&models.InventoryMovement{},
+		&models.CycleCountPlan{},
+		&models.CycleCountCoverage{},
+	))
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+
+	service := NewCycleCountService(db, NewStockOpnameService(db, nil, nil))
+	return db, company, warehouse, service
+}
+
+func createCycleCountProduct(t *testing.T, db *gorm.DB, company *models.Company, warehouse *models.Warehouse, code string, qty int64) (*models.Product, *models.WarehouseStock) {
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: code, Name: code, BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+	stock := &models.WarehouseStock{WarehouseID: warehouse.ID, ProductID: product.ID, Quantity: decimal.NewFromInt(qty)}
+	require.NoError(t, db.Create(stock).Error)
+	return product, stock
+}
+
+func opnameItems(t *testing.T, db *gorm.DB, opnameID string) []models.StockOpnameItem {
+	var items []models.StockOpnameItem
+	require.NoError(t, db.Preload("Product").Where("stock_opname_id = ?", opnameID).Find(&items).Error)
+	return items
+}
+
+func TestCycleCountService_ABC(t *testing.T) {
+	db, company, warehouse, service := setupCycleCountTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	fast, _ := createCycleCountProduct(t, db, company, warehouse, "P1", 50)
+	slow, _ := createCycleCountProduct(t, db, company, warehouse, "P2", 20)
+	createCycleCountProduct(t, db, company, warehouse, "P3", 5)
+	createCycleCountProduct(t, db, company, warehouse, "P4", 8)
+
+	outbound := func(product *models.Product, value int64) {
+		require.NoError(t, db.Create(&models.InventoryMovement{
+			TenantID:     company.TenantID,
+			CompanyID:    company.ID,
+			MovementDate: time.Now().AddDate(0, -1, 0),
+			WarehouseID:  warehouse.ID,
+			ProductID:    product.ID,
+			MovementType: models.MovementTypeOut,
+			Quantity:     decimal.NewFromInt(-1),
+			TotalCost:    decimal.NewFromInt(-value),
+		}).Error)
+	}
+	// P1 is 90% of usage (A), P2 the remaining 10% (B), P3 and P4 unused (C)
+	outbound(fast, 900)
+	outbound(slow, 100)
+
+	classDays := 14
+	plan, err := service.CreatePlan(context.Background(), company.TenantID, company.ID, "user-1", &dto.CreateCycleCountPlanRequest{
+		Name:          "Weekly ABC",
+		WarehouseID:   warehouse.ID,
+		Method:        string(models.CycleCountMethodABC),
+		FrequencyDays: 7,
+		ClassADays:    7,
+		ClassBDays:    classDays,
+		ClassCDays:    classDays,
+	})
+	require.NoError(t, err)
+	assert.True(t, plan.BlindCount)
+	assert.Equal(t, "Test Warehouse WH001", plan.Warehouse.Name)
+
+	t.Run("success - first run counts A, B and the first of two C products", func(t *testing.T) {
+		opname, plan, err := service.RunPlan(context.Background(), company.TenantID, company.ID, plan.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, opname)
+		assert.Equal(t, models.StockOpnameStatusDraft, opname.Status)
+		assert.True(t, opname.IsBlindCount)
+		assert.True(t, opname.HidesSystemQty())
+		assert.Equal(t, plan.ID, *opname.CycleCountPlanID)
+		assert.Equal(t, "Cycle count Weekly ABC: class A 1/1, B 1/1, C 1/2", *opname.Notes)
+
+		items := opnameItems(t, db, opname.ID)
+		require.Len(t, items, 3)
+		codes := map[string]string{}
+		for _, item := range items {
+			codes[item.Product.Code] = item.SystemQty.String()
+		}
+		assert.Equal(t, map[string]string{"P1": "50", "P2": "20", "P3": "5"}, codes)
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		assert.Equal(t, today.AddDate(0, 0, 7).Format("2006-01-02"), plan.NextRunDate.Format("2006-01-02"))
+		assert.NotNil(t, plan.LastRunAt)
+	})
+
+	t.Run("success - second run rotates to the C product not yet counted", func(t *testing.T) {
+		opname, _, err := service.RunPlan(context.Background(), company.TenantID, company.ID, plan.ID)
+
+		require.NoError(t, err)
+		require.NotNil(t, opname)
+		var codes []string
+		for _, item := range opnameItems(t, db, opname.ID) {
+			codes = append(codes, item.Product.Code)
+		}
+		assert.ElementsMatch(t, []string{"P1", "P2", "P4"}, codes)
+	})
+}
+
+func TestCycleCountService_Location(t *testing.T) {
+	db, company, warehouse, service := setupCycleCountTest(t)
+	defer testutil.CleanupTestDB(db)
+
+	product, stock := createCycleCountProduct(t, db, company, warehouse, "P1", 12)
+
+	bins := make([]*models.WarehouseBin, 3)
+	for i, code := range []string{"A-01", "A-02", "A-03"} {
+		bins[i] = &models.WarehouseBin{TenantID: company.TenantID, WarehouseID: warehouse.ID, Code: code, IsActive: true}
+		require.NoError(t, db.Create(bins[i]).Error)
+	}
+	require.NoError(t, db.Create(&models.BinStock{WarehouseBinID: bins[0].ID, WarehouseStockID: stock.ID, ProductID: product.ID, Quantity: decimal.NewFromInt(7)}).Error)
+	require.NoError(t, db.Create(&models.BinStock{WarehouseBinID: bins[1].ID, WarehouseStockID: stock.ID, ProductID: product.ID, Quantity: decimal.NewFromInt(5)}).Error)
+
+	blind := false
+	startDate := time.Now().UTC().AddDate(0, 0, -10).Format("2006-01-02")
+	plan, err := service.CreatePlan(context.Background(), company.TenantID, company.ID, "user-1", &dto.CreateCycleCountPlanRequest{
+		Name:          "Bin rotation",
+		WarehouseID:   warehouse.ID,
+		Method:        string(models.CycleCountMethodLocation),
+		FrequencyDays: 7,
+		CycleDays:     21,
+		BlindCount:    &blind,
+		StartDate:     &startDate,
+	})
+	require.NoError(t, err)
+	assert.False(t, plan.BlindCount)
+
+	t.Run("success - due plan counts the next bin and moves past today", func(t *testing.T) {
+		now := time.Now().UTC()
+
+		created, err := service.RunDuePlans(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		var opname models.StockOpname
+		require.NoError(t, db.Where("cycle_count_plan_id = ?", plan.ID).First(&opname).Error)
+		assert.False(t, opname.IsBlindCount)
+
+		items := opnameItems(t, db, opname.ID)
+		require.Len(t, items, 1)
+		assert.Equal(t, bins[0].ID, *items[0].BinID)
+		assert.Equal(t, "7", items[0].SystemQty.String())
+		assert.Equal(t, "-7", items[0].DifferenceQty.String())
+
+		var updated models.CycleCountPlan
+		require.NoError(t, db.First(&updated, "id = ?", plan.ID).Error)
+		assert.True(t, updated.NextRunDate.After(now))
+		assert.False(t, updated.NextRunDate.After(now.AddDate(0, 0, 7)))
+
+		created, err = service.RunDuePlans(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, created)
+	})
+
+	t.Run("success - empty bin is skipped and the rotation starts over", func(t *testing.T) {
+		opname, _, err := service.RunPlan(context.Background(), company.TenantID, company.ID, plan.ID)
+		require.NoError(t, err)
+		require.NotNil(t, opname)
+		assert.Equal(t, bins[1].ID, *opnameItems(t, db, opname.ID)[0].BinID)
+
+		opname, _, err = service.RunPlan(context.Background(), company.TenantID, company.ID, plan.ID)
+		require.NoError(t, err)
+		require.NotNil(t, opname)
+		assert.Equal(t, bins[0].ID, *opnameItems(t, db, opname.ID)[0].BinID)
+	})
+
+	t.Run("error - location plan without bins", func(t *testing.T) {
+		other := testutil.CreateTestWarehouse(t, db, company.ID, "WH002")
+
+		_, err := service.CreatePlan(context.Background(), company.TenantID, company.ID, "user-1", &dto.CreateCycleCountPlanRequest{
+			Name:        "No bins",
+			WarehouseID: other.ID,
+			Method:      string(models.CycleCountMethodLocation),
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "active bins")
+	})
+
+	t.Run("success - a failing plan is left due and the others still run", func(t *testing.T) {
+		now := time.Now().UTC()
+		due := now.AddDate(0, 0, -1)
+		broken := &models.CycleCountPlan{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			Name:          "Broken",
+			WarehouseID:   warehouse.ID,
+			Method:        models.CycleCountMethod("UNKNOWN"),
+			FrequencyDays: 7,
+			IsActive:      true,
+			NextRunDate:   due,
+		}
+		require.NoError(t, db.Create(broken).Error)
+		require.NoError(t, db.Model(&models.CycleCountPlan{}).Where("id = ?", plan.ID).Update("next_run_date", due).Error)
+
+		created, err := service.RunDuePlans(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		var updated models.CycleCountPlan
+		require.NoError(t, db.First(&updated, "id = ?", plan.ID).Error)
+		assert.True(t, updated.NextRunDate.After(now))
+		var skipped models.CycleCountPlan
+		require.NoError(t, db.First(&skipped, "id = ?", broken.ID).Error)
+		assert.False(t, skipped.NextRunDate.After(now))
+		assert.Nil(t, skipped.LastRunAt)
+	})
+}
@@ -12,13 +12,15 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/audit"
+	"backend/internal/service/inventory"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
 
 type StockOpnameService struct {
-	db           *gorm.DB
-	auditService *audit.AuditService
+	db                  *gorm.DB
+	auditService        *audit.AuditService
+	stockPostingService *inventory.StockPostingService
 }
 
 // Audit log structs for ordered JSON serialization
@@ -50,10 +52,11 @@ type opnameDeleteAuditData struct {
 	Status        string `json:"status"`
 }
 
-func NewStockOpnameService(db *gorm.DB, auditService *audit.AuditService) *StockOpnameService {
+func NewStockOpnameService(db *gorm.DB, auditService *audit.AuditService, stockPostingService *inventory.StockPostingService) *StockOpnameService {
 	return &StockOpnameService{
-		db:           db,
-		auditService: auditService,
+		db:                  db,
+		auditService:        auditService,
+		stockPostingService: stockPostingService,
 	}
 }
 
@@ -98,8 +101,13 @@ func (s *StockOpnameService) CreateStockOpname(ctx context.Context, companyID st
 			OpnameDate:   opnameDate,
 			WarehouseID:  req.WarehouseID,
 			Status:       models.StockOpnameStatusDraft,
+			IsBlindCount: req.BlindCount,
+			FreezeScope:  models.OpnameFreezeScopeNone,
 			Notes:        req.Notes,
 		}
+		if req.FreezeScope != "" {
+			opname.FreezeScope = models.OpnameFreezeScope(req.FreezeScope)
+		}
 
 		if userID != "" {
 			opname.CountedBy = &userID
@@ -110,11 +118,12 @@ func (s *StockOpnameService) CreateStockOpname(ctx context.Context, companyID st
 		}
 
 		// Create stock opname items
+		countedAt := time.Now()
 		for _, itemReq := range req.Items {
-			// Parse quantities
-			expectedQty, err := decimal.NewFromString(itemReq.ExpectedQty)
+			// Parse quantities (blind counts take the system quantity from stock)
+			expectedQty, err := s.expectedQty(tx, opname, itemReq.ProductID, itemReq.ExpectedQty)
 			if err != nil {
-				return pkgerrors.NewBadRequestError(fmt.Sprintf("invalid expectedQty for product %s", itemReq.ProductID))
+				return err
 			}
 
 			actualQty, err := decimal.NewFromString(itemReq.ActualQty)
@@ -141,6 +150,7 @@ func (s *StockOpnameService) CreateStockOpname(ctx context.Context, companyID st
 				SystemQty:     expectedQty,
 				PhysicalQty:   actualQty,
 				DifferenceQty: difference,
+				CountedAt:     &countedAt,
 				Notes:         itemReq.Notes,
 			}
 
@@ -231,6 +241,8 @@ func (s *StockOpnameService) GetStockOpname(ctx context.Context, companyID, tena
 	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
 		Preload("Warehouse").
 		Preload("Items.Product").
+		Preload("Items.Batch").
+		Preload("Items.Bin").
 		Where("company_id = ? AND id = ?", companyID, opnameID).
 		First(&opname).Error
 
@@ -454,6 +466,12 @@ func (s *StockOpnameService) UpdateStockOpname(ctx context.Context, companyID, t
 		}
 	}
 
+	if req.FreezeScope != nil && models.OpnameFreezeScope(*req.FreezeScope) != opname.FreezeScope {
+		updates["freeze_scope"] = *req.FreezeScope
+		oldValues["freeze_scope"] = string(opname.FreezeScope)
+		newValues["freeze_scope"] = *req.FreezeScope
+	}
+
 	if req.Notes != nil {
 		newNotes := *req.Notes
 		if newNotes != oldNotes {
@@ -606,10 +624,21 @@ func (s *StockOpnameService) ApproveStockOpname(ctx context.Context, companyID,
 			updates["notes"] = req.Notes
 		}
 
-		if err := tx.Model(&models.StockOpname{}).
-			Where("id = ? AND company_id = ?", opnameID, companyID).
-			Updates(updates).Error; err != nil {
-			return fmt.Errorf("failed to update stock opname: %w", err)
+		// Matching on status makes a second, concurrent approval of the same opname fail
+		// instead of posting the count differences twice
+		result := tx.Model(&models.StockOpname{}).
+			Where("id = ? AND company_id = ? AND status = ?", opnameID, companyID, models.StockOpnameStatusCompleted).
+			Updates(updates)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update stock opname: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return pkgerrors.NewConflictError("Stock opname was changed by another request, please retry")
+		}
+
+		// Value each line against the stock at the moment it was counted
+		if err := s.recountVariances(tx, opname); err != nil {
+			return err
 		}
 
 		// Post stock adjustments
@@ -619,43 +648,25 @@ func (s *StockOpnameService) ApproveStockOpname(ctx context.Context, companyID,
 				continue
 			}
 
-			// Update warehouse stock
-			var whStock models.WarehouseStock
-			if err := tx.Where("warehouse_id = ? AND product_id = ?", opname.WarehouseID, item.ProductID).
-				First(&whStock).Error; err != nil {
-				if err == gorm.ErrRecordNotFound {
-					return pkgerrors.NewNotFoundError(fmt.Sprintf("warehouse stock not found for product %s", item.ProductID))
-				}
-				return fmt.Errorf("failed to get warehouse stock: %w", err)
-			}
-
-			// Adjust quantity
-			newQuantity := whStock.Quantity.Add(item.DifferenceQty)
-			if newQuantity.IsNegative() {
-				return pkgerrors.NewBadRequestError(fmt.Sprintf("adjustment would result in negative stock for product %s", item.ProductID))
-			}
-
-			if err := tx.Model(&whStock).Update("quantity", newQuantity).Error; err != nil {
-				return fmt.Errorf("failed to update warehouse stock: %w", err)
+			// Post the count difference as an adjustment movement
+			if _, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+				TenantID:        tenantID,
+				CompanyID:       companyID,
+				WarehouseID:     opname.WarehouseID,
+				ProductID:       item.ProductID,
+				BatchID:         item.BatchID,
+				BinID:           item.BinID,
+				MovementType:    models.MovementTypeAdjustment,
+				Quantity:        item.DifferenceQty,
+				MovementDate:    now,
+				ReferenceType:   inventory.ReferenceTypeStockOpname,
+				ReferenceID:     opname.ID,
+				ReferenceNumber: opname.OpnameNumber,
+				Notes:           item.Notes,
+				CreatedBy:       userID,
+			}); err != nil {
+				return err
 			}
-
-			// TODO: Create stock movement record when StockMovement model is available
-			// movement := &models.StockMovement{
-			// 	TenantID:          tenantID,
-			// 	CompanyID:         companyID,
-			// 	MovementDate:      now,
-			// 	MovementType:      models.MovementTypeAdjustment,
-			// 	ProductID:         item.ProductID,
-			// 	SourceWarehouseID: &opname.WarehouseID,
-			// 	Quantity:          item.DifferenceQty.Abs(),
-			// 	ReferenceType:     "stock_opname",
-			// 	ReferenceID:       &opnameID,
-			// 	Notes:             item.Notes,
-			// }
-			//
-			// if err := tx.Create(movement).Error; err != nil {
-			// 	return fmt.Errorf("failed to create stock movement: %w", err)
-			// }
 		}
 
 		return nil
@@ -747,10 +758,10 @@ func (s *StockOpnameService) AddStockOpnameItem(ctx context.Context, companyID,
 		return nil, pkgerrors.NewBadRequestError("cannot add items to cancelled stock opname")
 	}
 
-	// Parse quantities
-	expectedQty, err := decimal.NewFromString(req.ExpectedQty)
+	// Parse quantities (blind counts take the system quantity from stock)
+	expectedQty, err := s.expectedQty(s.db.WithContext(ctx).Set("tenant_id", tenantID), opname, req.ProductID, req.ExpectedQty)
 	if err != nil {
-		return nil, pkgerrors.NewBadRequestError("invalid expectedQty format")
+		return nil, err
 	}
 
 	actualQty, err := decimal.NewFromString(req.ActualQty)
@@ -762,12 +773,14 @@ func (s *StockOpnameService) AddStockOpnameItem(ctx context.Context, companyID,
 	difference := actualQty.Sub(expectedQty)
 
 	// Create item
+	countedAt := time.Now()
 	item := &models.StockOpnameItem{
 		StockOpnameID: opnameID,
 		ProductID:     req.ProductID,
 		SystemQty:     expectedQty,
 		PhysicalQty:   actualQty,
 		DifferenceQty: difference,
+		CountedAt:     &countedAt,
 		Notes:         req.Notes,
 	}
 
@@ -778,6 +791,8 @@ func (s *StockOpnameService) AddStockOpnameItem(ctx context.Context, companyID,
 	// Reload with product info
 	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
 		Preload("Product").
+		Preload("Bin").
+		Preload("StockOpname").
 		First(item, "id = ?", item.ID).Error; err != nil {
 		return nil, fmt.Errorf("failed to reload stock opname item: %w", err)
 	}
@@ -841,6 +856,7 @@ func (s *StockOpnameService) UpdateStockOpnameItem(ctx context.Context, companyI
 
 			updates["physical_qty"] = actualQty
 			updates["difference_qty"] = difference
+			updates["counted_at"] = time.Now()
 			hasActualQtyChange = true
 		}
 	}
@@ -865,6 +881,8 @@ func (s *StockOpnameService) UpdateStockOpnameItem(ctx context.Context, companyI
 	// Reload with product info
 	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
 		Preload("Product").
+		Preload("Bin").
+		Preload("StockOpname").
 		First(&item, "id = ?", itemID).Error; err != nil {
 		return nil, fmt.Errorf("failed to reload stock opname item: %w", err)
 	}
@@ -984,6 +1002,7 @@ func (s *StockOpnameService) BatchUpdateStockOpnameItems(ctx context.Context, co
 					difference := actualQty.Sub(item.SystemQty)
 					updates["physical_qty"] = actualQty
 					updates["difference_qty"] = difference
+					updates["counted_at"] = time.Now()
 					hasChanges = true
 				}
 			}
@@ -1027,6 +1046,8 @@ func (s *StockOpnameService) BatchUpdateStockOpnameItems(ctx context.Context, co
 			// Reload item with product info
 			if err := tx.Set("tenant_id", tenantID).
 				Preload("Product").
+				Preload("Bin").
+				Preload("StockOpname").
 				First(&item, "id = ?", itemReq.ItemID).Error; err != nil {
 				return fmt.Errorf("failed to reload stock opname item: %w", err)
 			}
@@ -1164,6 +1185,23 @@ func (s *StockOpnameService) ImportWarehouseProducts(ctx context.Context, compan
 // HELPER FUNCTIONS
 // ============================================================================
 
+// expectedQty returns the system quantity of a new line: the current warehouse stock for blind counts
+// (counters cannot know it), otherwise the quantity given in the request
+func (s *StockOpnameService) expectedQty(tx *gorm.DB, opname *models.StockOpname, productID, requested string) (decimal.Decimal, error) {
+	if opname.IsBlindCount {
+		return s.currentQty(tx, opname.WarehouseID, &models.StockOpnameItem{ProductID: productID})
+	}
+
+	if requested == "" {
+		return decimal.Zero, pkgerrors.NewBadRequestError(fmt.Sprintf("expectedQty is required for product %s", productID))
+	}
+	expected, err := decimal.NewFromString(requested)
+	if err != nil {
+		return decimal.Zero, pkgerrors.NewBadRequestError(fmt.Sprintf("invalid expectedQty for product %s", productID))
+	}
+	return expected, nil
+}
+
 // generateOpnameNumber generates a unique opname number
 func (s *StockOpnameService) generateOpnameNumber(tx *gorm.DB, companyID string, opnameDate time.Time) (string, error) {
 	// Format: OPN-YYYYMMDD-XXX
@@ -1187,3 +1225,86 @@ func (s *StockOpnameService) generateOpnameNumber(tx *gorm.DB, companyID string,
 
 	return fmt.Sprintf("%s%03d", prefix, sequence), nil
 }
+
+// recountVariances sets each item's system quantity to the stock at the moment the item was counted
+// (current stock less what was posted since), so receipts, transfers and adjustments posted during
+// or after the count do not show up as count differences. Items never counted take the current stock.
+func (s *StockOpnameService) recountVariances(tx *gorm.DB, opname *models.StockOpname) error {
+	for i := range opname.Items {
+		item := &opname.Items[i]
+
+		systemQty, err := s.currentQty(tx, opname.WarehouseID, item)
+		if err != nil {
+			return err
+		}
+		if item.CountedAt != nil {
+			posted, err := s.postedSince(tx, opname.WarehouseID, item, *item.CountedAt)
+			if err != nil {
+				return err
+			}
+			systemQty = systemQty.Sub(posted)
+		}
+		if systemQty.Equal(item.SystemQty) {
+			continue
+		}
+
+		item.SystemQty = systemQty
+		item.DifferenceQty = item.PhysicalQty.Sub(systemQty)
+		if err := tx.Model(&models.StockOpnameItem{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"system_qty":     item.SystemQty,
+			"difference_qty": item.DifferenceQty,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update stock opname item: %w", err)
+		}
+	}
+	return nil
+}
+
+// currentQty returns the stock an item counts: its bin, its batch, or the whole warehouse
+func (s *StockOpnameService) currentQty(tx *gorm.DB, warehouseID string, item *models.StockOpnameItem) (decimal.Decimal, error) {
+	var quantity decimal.Decimal
+
+	switch {
+	case item.BinID != nil:
+		query := tx.Model(&models.BinStock{}).
+			Where("warehouse_bin_id = ? AND product_id = ?", *item.BinID, item.ProductID)
+		if item.BatchID != nil {
+			query = query.Where("batch_id = ?", *item.BatchID)
+		}
+		if err := query.Select("COALESCE(SUM(quantity), 0)").Scan(&quantity).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get bin stock: %w", err)
+		}
+	case item.BatchID != nil:
+		if err := tx.Model(&models.ProductBatch{}).
+			Where("id = ?", *item.BatchID).
+			Select("COALESCE(SUM(quantity), 0)").Scan(&quantity).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get batch stock: %w", err)
+		}
+	default:
+		if err := tx.Model(&models.WarehouseStock{}).
+			Where("warehouse_id = ? AND product_id = ?", warehouseID, item.ProductID).
+			Select("COALESCE(SUM(quantity), 0)").Scan(&quantity).Error; err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get warehouse stock: %w", err)
+		}
+	}
+
+	return quantity, nil
+}
+
+// postedSince sums the movements posted (by posting time, not movement date) to an item's stock since the given instant
+func (s *StockOpnameService) postedSince(tx *gorm.DB, warehouseID string, item *models.StockOpnameItem, since time.Time) (decimal.Decimal, error) {
+	query := tx.Model(&models.InventoryMovement{}).
+		Where("warehouse_id = ? AND product_id = ? AND created_at >= ?", warehouseID, item.ProductID, since)
+	if item.BatchID != nil {
+		query = query.Where("batch_id = ?", *item.BatchID)
+	}
+	if item.BinID != nil {
+		query = query.Where("bin_id = ?", *item.BinID)
+	}
+
+	var posted decimal.Decimal
+	if err := query.Select("COALESCE(SUM(quantity), 0)").Scan(&posted).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum movements since count: %w", err)
+	}
+	return posted, nil
+}
@@ -0,0 +1,97 @@
+package stockopname
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/internal/testutil"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockOpnameService_CountSnapshot(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(&models.InventoryMovement{}, &models.StockReservation{}, &models.CostLayer{}))
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	warehouse := testutil.CreateTestWarehouse(t, db, company.ID, "WH001")
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "P1", Name: "P1", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+
+	postingService := inventory.NewStockPostingService(db)
+	service := NewStockOpnameService(db, nil, postingService)
+	ctx := context.Background()
+
+	post := func(qty int64) error {
+		_, err := postingService.Post(db, &inventory.StockPosting{
+			TenantID:      company.TenantID,
+			CompanyID:     company.ID,
+			WarehouseID:   warehouse.ID,
+			ProductID:     product.ID,
+			MovementType:  models.MovementTypeIn,
+			Quantity:      decimal.NewFromInt(qty),
+			ReferenceType: inventory.ReferenceTypeGoodsReceipt,
+		})
+		return err
+	}
+	setStatus := func(opnameID, status string) *models.StockOpname {
+		opname, err := service.UpdateStockOpname(ctx, company.ID, company.TenantID, opnameID, "user-1", &dto.UpdateStockOpnameRequest{Status: &status}, "", "")
+		require.NoError(t, err)
+		return opname
+	}
+	createOpname := func(freezeScope string) *models.StockOpname {
+		opname, err := service.CreateStockOpname(ctx, company.ID, company.TenantID, "user-1", &dto.CreateStockOpnameRequest{
+			OpnameDate:  "2026-03-01",
+			WarehouseID: warehouse.ID,
+			FreezeScope: freezeScope,
+			Items:       []dto.CreateStockOpnameItemRequest{{ProductID: product.ID, ExpectedQty: "10", ActualQty: "11"}},
+		}, "", "")
+		require.NoError(t, err)
+		return opname
+	}
+
+	require.NoError(t, post(12))
+
+	t.Run("success - approval values counts against stock at count time", func(t *testing.T) {
+		// Entered expected qty 10 is stale: stock was 12 when 11 was counted
+		opname := createOpname("")
+		assert.Equal(t, models.OpnameFreezeScopeNone, opname.FreezeScope)
+		require.NotNil(t, opname.Items[0].CountedAt)
+		setStatus(opname.ID, "in_progress")
+
+		// A receipt after the count is not a count difference
+		require.NoError(t, post(5))
+		setStatus(opname.ID, "completed")
+
+		approved, err := service.ApproveStockOpname(ctx, company.ID, company.TenantID, opname.ID, "user-1", &dto.ApproveStockOpnameRequest{}, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "12", approved.Items[0].SystemQty.String())
+		assert.Equal(t, "-1", approved.Items[0].DifferenceQty.String())
+
+		var stock models.WarehouseStock
+		require.NoError(t, db.Where("warehouse_id = ? AND product_id = ?", warehouse.ID, product.ID).First(&stock).Error)
+		assert.Equal(t, "16", stock.Quantity.String())
+	})
+
+	t.Run("error - postings rejected while a frozen opname is in progress", func(t *testing.T) {
+		opname := createOpname(string(models.OpnameFreezeScopeProducts))
+		setStatus(opname.ID, "in_progress")
+
+		err := post(1)
+		require.Error(t, err)
+		appErr, ok := err.(*pkgerrors.AppError)
+		require.True(t, ok)
+		assert.Equal(t, 409, appErr.StatusCode)
+		assert.Contains(t, err.Error(), opname.OpnameNumber)
+
+		setStatus(opname.ID, "completed")
+		require.NoError(t, post(1))
+	})
+}
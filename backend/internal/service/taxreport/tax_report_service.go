@@ -0,0 +1,205 @@
+package taxreport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// Document types on the sales tax report
+const (
+	DocumentTypeInvoice    = "INVOICE"
+	DocumentTypeDebitNote  = "DEBIT_NOTE"
+	DocumentTypeCreditNote = "CREDIT_NOTE"
+)
+
+// TaxReportService - Output tax (PPN Keluaran) reporting.
+// Invoices and sales debit notes add to the tax base and tax of the period they are dated
+// in; sales credit notes reduce it in theirs, not in the period of the invoice they credit.
+type TaxReportService struct {
+	db *gorm.DB
+}
+
+// NewTaxReportService creates a new tax report service instance
+func NewTaxReportService(db *gorm.DB) *TaxReportService {
+	return &TaxReportService{
+		db: db,
+	}
+}
+
+// GetSalesTaxReport lists the tax base and tax of every invoice, debit note and credit
+// note dated in the period, with totals per document type and net totals
+func (s *TaxReportService) GetSalesTaxReport(ctx context.Context, tenantID, companyID string, query *dto.SalesTaxReportQuery) (*dto.SalesTaxReportResponse, error) {
+	now := time.Now()
+	dateFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if query.DateFrom != "" {
+		date, err := time.Parse("2006-01-02", query.DateFrom)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid dateFrom format (use YYYY-MM-DD)")
+		}
+		dateFrom = date
+	}
+	dateTo := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if query.DateTo != "" {
+		date, err := time.Parse("2006-01-02", query.DateTo)
+		if err != nil {
+			return nil, pkgerrors.NewBadRequestError("invalid dateTo format (use YYYY-MM-DD)")
+		}
+		dateTo = date
+	}
+	if dateTo.Before(dateFrom) {
+		return nil, pkgerrors.NewBadRequestError("dateTo must not be before dateFrom")
+	}
+	// Documents before the start of the day after dateTo are included
+	cutoff := time.Date(dateTo.Year(), dateTo.Month(), dateTo.Day()+1, 0, 0, 0, 0, dateTo.Location())
+
+	db := s.db.WithContext(ctx).Set("tenant_id", tenantID)
+
+	var invoices []models.Invoice
+	invoiceQuery := db.Session(&gorm.Session{}).Preload("Customer").
+		Where("company_id = ? AND invoice_date >= ? AND invoice_date < ?", companyID, dateFrom, cutoff)
+	if query.CustomerID != nil {
+		invoiceQuery = invoiceQuery.Where("customer_id = ?", *query.CustomerID)
+	}
+	if err := invoiceQuery.Find(&invoices).Error; err != nil {
+		return nil, fmt.Errorf("failed to load invoices: %w", err)
+	}
+
+	var debitNotes []models.SalesDebitNote
+	debitNoteQuery := db.Session(&gorm.Session{}).Preload("Customer").Preload("Invoice").
+		Where("company_id = ? AND debit_note_date >= ? AND debit_note_date < ?", companyID, dateFrom, cutoff)
+	if query.CustomerID != nil {
+		debitNoteQuery = debitNoteQuery.Where("customer_id = ?", *query.CustomerID)
+	}
+	if err := debitNoteQuery.Find(&debitNotes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load debit notes: %w", err)
+	}
+
+	var creditNotes []models.SalesCreditNote
+	creditNoteQuery := db.Session(&gorm.Session{}).Preload("Customer").Preload("Invoice").
+		Where("company_id = ? AND credit_note_date >= ? AND credit_note_date < ?", companyID, dateFrom, cutoff)
+	if query.CustomerID != nil {
+		creditNoteQuery = creditNoteQuery.Where("customer_id = ?", *query.CustomerID)
+	}
+	if err := creditNoteQuery.Find(&creditNotes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load credit notes: %w", err)
+	}
+
+	type reportLine struct {
+		date      time.Time
+		taxBase   decimal.Decimal
+		taxAmount decimal.Decimal
+		line      dto.SalesTaxReportLine
+	}
+	lines := make([]reportLine, 0, len(invoices)+len(debitNotes)+len(creditNotes))
+
+	for _, invoice := range invoices {
+		l := reportLine{
+			date:      invoice.InvoiceDate,
+			taxBase:   invoice.Subtotal.Sub(invoice.DiscountAmount),
+			taxAmount: invoice.TaxAmount,
+		}
+		l.line = newLine(DocumentTypeInvoice, invoice.ID, invoice.InvoiceNumber, &invoice.Customer, l.date, l.taxBase, l.taxAmount, invoice.TotalAmount)
+		l.line.FakturPajakNo = invoice.FakturPajakNo
+		lines = append(lines, l)
+	}
+	for _, debitNote := range debitNotes {
+		l := reportLine{
+			date:      debitNote.DebitNoteDate,
+			taxBase:   debitNote.Subtotal.Sub(debitNote.DiscountAmount),
+			taxAmount: debitNote.TaxAmount,
+		}
+		l.line = newLine(DocumentTypeDebitNote, debitNote.ID, debitNote.DebitNoteNumber, &debitNote.Customer, l.date, l.taxBase, l.taxAmount, debitNote.TotalAmount)
+		l.line.InvoiceNumber = debitNote.Invoice.InvoiceNumber
+		l.line.FakturPajakNo = debitNote.Invoice.FakturPajakNo
+		lines = append(lines, l)
+	}
+	for _, creditNote := range creditNotes {
+		l := reportLine{
+			date:      creditNote.CreditNoteDate,
+			taxBase:   creditNote.Subtotal.Sub(creditNote.DiscountAmount).Neg(),
+			taxAmount: creditNote.TaxAmount.Neg(),
+		}
+		l.line = newLine(DocumentTypeCreditNote, creditNote.ID, creditNote.CreditNoteNumber, &creditNote.Customer, l.date, l.taxBase, l.taxAmount, creditNote.TotalAmount.Neg())
+		if creditNote.Invoice != nil {
+			l.line.InvoiceNumber = creditNote.Invoice.InvoiceNumber
+			l.line.FakturPajakNo = creditNote.Invoice.FakturPajakNo
+		}
+		lines = append(lines, l)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].date.Equal(lines[j].date) {
+			return lines[i].date.Before(lines[j].date)
+		}
+		return lines[i].line.DocumentNumber < lines[j].line.DocumentNumber
+	})
+
+	type summaryTotals struct {
+		count     int
+		taxBase   decimal.Decimal
+		taxAmount decimal.Decimal
+	}
+	totals := map[string]*summaryTotals{
+		DocumentTypeInvoice:    {},
+		DocumentTypeDebitNote:  {},
+		DocumentTypeCreditNote: {},
+	}
+	totalTaxBase := decimal.Zero
+	totalTax := decimal.Zero
+
+	response := &dto.SalesTaxReportResponse{
+		Success:  true,
+		DateFrom: dateFrom.Format("2006-01-02"),
+		DateTo:   dateTo.Format("2006-01-02"),
+		Data:     make([]dto.SalesTaxReportLine, 0, len(lines)),
+	}
+	for _, l := range lines {
+		t := totals[l.line.DocumentType]
+		t.count++
+		t.taxBase = t.taxBase.Add(l.taxBase)
+		t.taxAmount = t.taxAmount.Add(l.taxAmount)
+		totalTaxBase = totalTaxBase.Add(l.taxBase)
+		totalTax = totalTax.Add(l.taxAmount)
+		response.Data = append(response.Data, l.line)
+	}
+
+	for _, documentType := range []string{DocumentTypeInvoice, DocumentTypeDebitNote, DocumentTypeCreditNote} {
+		t := totals[documentType]
+		response.Summary = append(response.Summary, dto.SalesTaxReportSummary{
+			DocumentType: documentType,
+			Count:        t.count,
+			TaxBase:      t.taxBase.StringFixed(2),
+			TaxAmount:    t.taxAmount.StringFixed(2),
+		})
+	}
+	response.TotalTaxBase = totalTaxBase.StringFixed(2)
+	response.TotalTax = totalTax.StringFixed(2)
+
+	return response, nil
+}
+
+// newLine builds a report line; amounts are already signed
+func newLine(documentType, id, number string, customer *models.Customer, date time.Time, taxBase, taxAmount, totalAmount decimal.Decimal) dto.SalesTaxReportLine {
+	return dto.SalesTaxReportLine{
+		DocumentType:   documentType,
+		DocumentID:     id,
+		DocumentNumber: number,
+		DocumentDate:   date.Format("2006-01-02"),
+		CustomerID:     customer.ID,
+		CustomerCode:   customer.Code,
+		CustomerName:   customer.Name,
+		CustomerNPWP:   customer.NPWP,
+		TaxBase:        taxBase.StringFixed(2),
+		TaxAmount:      taxAmount.StringFixed(2),
+		TotalAmount:    totalAmount.StringFixed(2),
+	}
+}
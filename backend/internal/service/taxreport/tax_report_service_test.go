@@ -0,0 +1,121 @@
+package taxreport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxReportService_GetSalesTaxReport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+	require.NoError(t, db.AutoMigrate(
+		&models.Invoice{},
+		&models.SalesCreditNote{},
+		&models.SalesDebitNote{},
+	))
+
+	ctx := context.Background()
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	npwp := "01.234.567.8-901.000"
+	customer := &models.Customer{TenantID: company.TenantID, CompanyID: company.ID, Code: "CUST001", Name: "Toko Makmur", NPWP: &npwp, IsActive: true}
+	require.NoError(t, db.Create(customer).Error)
+
+	service := NewTaxReportService(db)
+
+	periodStart := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	fakturPajak := "010.000-26.00000001"
+	invoice := &models.Invoice{
+		TenantID:       company.TenantID,
+		CompanyID:      company.ID,
+		InvoiceNumber:  "INV/0001",
+		InvoiceDate:    periodStart.AddDate(0, 0, 2),
+		DueDate:        periodStart.AddDate(0, 0, 32),
+		CustomerID:     customer.ID,
+		Subtotal:       decimal.NewFromInt(1000000),
+		DiscountAmount: decimal.NewFromInt(100000),
+		TaxAmount:      decimal.NewFromInt(99000),
+		TotalAmount:    decimal.NewFromInt(999000),
+		FakturPajakNo:  &fakturPajak,
+	}
+	require.NoError(t, db.Create(invoice).Error)
+	// Invoiced in the previous period, credited in this one
+	earlier := &models.Invoice{
+		TenantID:      company.TenantID,
+		CompanyID:     company.ID,
+		InvoiceNumber: "INV/0000",
+		InvoiceDate:   periodStart.AddDate(0, 0, -10),
+		DueDate:       periodStart.AddDate(0, 0, 20),
+		CustomerID:    customer.ID,
+		Subtotal:      decimal.NewFromInt(200000),
+		TaxAmount:     decimal.NewFromInt(22000),
+		TotalAmount:   decimal.NewFromInt(222000),
+	}
+	require.NoError(t, db.Create(earlier).Error)
+
+	require.NoError(t, db.Create(&models.SalesDebitNote{
+		TenantID:        company.TenantID,
+		CompanyID:       company.ID,
+		DebitNoteNumber: "DN/0001",
+		DebitNoteDate:   periodStart.AddDate(0, 0, 10),
+		CustomerID:      customer.ID,
+		InvoiceID:       invoice.ID,
+		Reason:          "Harga kurang tagih",
+		Subtotal:        decimal.NewFromInt(20000),
+		DiscountAmount:  decimal.NewFromInt(2000),
+		TaxAmount:       decimal.NewFromInt(1980),
+		TotalAmount:     decimal.NewFromInt(19980),
+	}).Error)
+	require.NoError(t, db.Create(&models.SalesCreditNote{
+		TenantID:         company.TenantID,
+		CompanyID:        company.ID,
+		CreditNoteNumber: "CN/0001",
+		CreditNoteDate:   periodStart.AddDate(0, 0, 12),
+		CustomerID:       customer.ID,
+		InvoiceID:        &earlier.ID,
+		Reason:           "Kurang kirim",
+		Subtotal:         decimal.NewFromInt(50000),
+		TaxAmount:        decimal.NewFromInt(5500),
+		TotalAmount:      decimal.NewFromInt(55500),
+	}).Error)
+
+	t.Run("success - notes adjust output tax in the period they are dated", func(t *testing.T) {
+		report, err := service.GetSalesTaxReport(ctx, company.TenantID, company.ID, &dto.SalesTaxReportQuery{
+			DateFrom: "2026-09-01",
+			DateTo:   "2026-09-30",
+		})
+
+		require.NoError(t, err)
+		require.Len(t, report.Data, 3)
+		assert.Equal(t, "INV/0001", report.Data[0].DocumentNumber)
+		assert.Equal(t, "900000.00", report.Data[0].TaxBase)
+		assert.Equal(t, npwp, *report.Data[0].CustomerNPWP)
+		assert.Equal(t, DocumentTypeDebitNote, report.Data[1].DocumentType)
+		assert.Equal(t, fakturPajak, *report.Data[1].FakturPajakNo)
+		assert.Equal(t, DocumentTypeCreditNote, report.Data[2].DocumentType)
+		assert.Equal(t, "INV/0000", report.Data[2].InvoiceNumber)
+		assert.Equal(t, "-5500.00", report.Data[2].TaxAmount)
+
+		assert.Equal(t, "868000.00", report.TotalTaxBase)
+		assert.Equal(t, "95480.00", report.TotalTax)
+		require.Len(t, report.Summary, 3)
+		assert.Equal(t, 1, report.Summary[2].Count)
+		assert.Equal(t, "-50000.00", report.Summary[2].TaxBase)
+	})
+
+	t.Run("error - dateTo before dateFrom", func(t *testing.T) {
+		_, err := service.GetSalesTaxReport(ctx, company.TenantID, company.ID, &dto.SalesTaxReportQuery{
+			DateFrom: "2026-09-30",
+			DateTo:   "2026-09-01",
+		})
+		assert.Error(t, err)
+	})
+}
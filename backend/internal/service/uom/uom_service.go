@@ -0,0 +1,124 @@
+package uom
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// UOMService is the single place where document line units are resolved.
+// Sales orders, purchase orders, goods receipts, deliveries and invoices keep the
+// quantity and unit as entered next to the base-unit quantity; stock postings,
+// reservations, tolerance checks and reports work on the base quantity only.
+type UOMService struct {
+	db *gorm.DB
+}
+
+// NewUOMService creates a new unit of measure service instance
+func NewUOMService(db *gorm.DB) *UOMService {
+	return &UOMService{
+		db: db,
+	}
+}
+
+// Unit is the unit a document line was entered in and its conversion to the product's base unit
+type Unit struct {
+	ProductUnitID  *string         // nil = product base unit
+	UnitName       string          // Empty for the product base unit
+	ConversionRate decimal.Decimal // Base units per entered unit (1 KARTON = 24 PCS)
+}
+
+// BaseUnit is the unit of lines entered without a ProductUnitID
+func BaseUnit() Unit {
+	return Unit{ConversionRate: decimal.NewFromInt(1)}
+}
+
+// FromProductUnit returns the unit of a stored line (nil = base unit).
+// Stored lines keep their unit even after it is deactivated, so no active check here.
+func FromProductUnit(unit *models.ProductUnit) Unit {
+	if unit == nil || unit.ID == "" || !unit.ConversionRate.IsPositive() {
+		return BaseUnit()
+	}
+
+	id := unit.ID
+	return Unit{
+		ProductUnitID:  &id,
+		UnitName:       unit.UnitName,
+		ConversionRate: unit.ConversionRate,
+	}
+}
+
+// ToBase converts a quantity in this unit to the base unit
+func (u Unit) ToBase(qty decimal.Decimal) decimal.Decimal {
+	return qty.Mul(u.ConversionRate)
+}
+
+// FromBase converts a base-unit quantity to this unit
+func (u Unit) FromBase(baseQty decimal.Decimal) decimal.Decimal {
+	return baseQty.Div(u.ConversionRate)
+}
+
+// PriceToBase converts a price per this unit to a price per base unit
+func (u Unit) PriceToBase(price decimal.Decimal) decimal.Decimal {
+	return price.Div(u.ConversionRate).Round(4)
+}
+
+// PriceFromBase converts a price per base unit to a price per this unit
+func (u Unit) PriceFromBase(basePrice decimal.Decimal) decimal.Decimal {
+	return basePrice.Mul(u.ConversionRate).Round(2)
+}
+
+// ResolveUnit validates the unit entered on a new or changed document line.
+// An empty unit means the product base unit. The unit must belong to the product and be active.
+func (s *UOMService) ResolveUnit(tx *gorm.DB, productID string, productUnitID *string) (Unit, error) {
+	if productUnitID == nil || *productUnitID == "" {
+		return BaseUnit(), nil
+	}
+
+	var unit models.ProductUnit
+	if err := tx.Where("id = ? AND product_id = ?", *productUnitID, productID).First(&unit).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return Unit{}, pkgerrors.NewBadRequestError(fmt.Sprintf("product unit %s not found for product %s", *productUnitID, productID))
+		}
+		return Unit{}, fmt.Errorf("failed to load product unit: %w", err)
+	}
+
+	if !unit.IsActive {
+		return Unit{}, pkgerrors.NewBadRequestError(fmt.Sprintf("product unit %s is inactive", unit.UnitName))
+	}
+	if !unit.ConversionRate.IsPositive() {
+		return Unit{}, pkgerrors.NewBadRequestError(fmt.Sprintf("product unit %s has no valid conversion rate", unit.UnitName))
+	}
+
+	return FromProductUnit(&unit), nil
+}
+
+// LineUnit loads the unit of a stored line without the active check (see FromProductUnit)
+func (s *UOMService) LineUnit(tx *gorm.DB, productUnitID *string) (Unit, error) {
+	if productUnitID == nil || *productUnitID == "" {
+		return BaseUnit(), nil
+	}
+
+	var unit models.ProductUnit
+	if err := tx.Where("id = ?", *productUnitID).First(&unit).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return Unit{}, pkgerrors.NewNotFoundError(fmt.Sprintf("product unit %s not found", *productUnitID))
+		}
+		return Unit{}, fmt.Errorf("failed to load product unit: %w", err)
+	}
+
+	return FromProductUnit(&unit), nil
+}
+
+// ConvertQuantity validates the entered unit and returns it with the base-unit quantity
+func (s *UOMService) ConvertQuantity(tx *gorm.DB, productID string, productUnitID *string, qty decimal.Decimal) (Unit, decimal.Decimal, error) {
+	unit, err := s.ResolveUnit(tx, productID, productUnitID)
+	if err != nil {
+		return Unit{}, decimal.Zero, err
+	}
+	return unit, unit.ToBase(qty), nil
+}
@@ -0,0 +1,75 @@
+package uom
+
+import (
+	"testing"
+
+	"backend/internal/testutil"
+	"backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUOMService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(db)
+
+	company := testutil.CreateTestCompany(t, db, "tenant1", "COMP1")
+	product := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD001", Name: "Air Mineral 600ml", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(product).Error)
+	other := &models.Product{TenantID: company.TenantID, CompanyID: company.ID, Code: "PROD002", Name: "Teh Botol", BaseUnit: "PCS", IsActive: true}
+	require.NoError(t, db.Create(other).Error)
+
+	karton := &models.ProductUnit{ProductID: product.ID, UnitName: "KARTON", ConversionRate: decimal.NewFromInt(24), IsActive: true}
+	require.NoError(t, db.Create(karton).Error)
+	lusin := &models.ProductUnit{ProductID: product.ID, UnitName: "LUSIN", ConversionRate: decimal.NewFromInt(12), IsActive: true}
+	require.NoError(t, db.Create(lusin).Error)
+	// IsActive has a gorm default, so false must be written after create
+	require.NoError(t, db.Model(lusin).Update("is_active", false).Error)
+
+	service := NewUOMService(db)
+
+	t.Run("success - no unit is the base unit", func(t *testing.T) {
+		empty := ""
+		unit, baseQty, err := service.ConvertQuantity(db, product.ID, &empty, decimal.NewFromInt(5))
+
+		require.NoError(t, err)
+		assert.Nil(t, unit.ProductUnitID)
+		assert.Equal(t, "5", baseQty.String())
+	})
+
+	t.Run("success - converts quantity and price to the base unit", func(t *testing.T) {
+		unit, baseQty, err := service.ConvertQuantity(db, product.ID, &karton.ID, decimal.NewFromInt(2))
+
+		require.NoError(t, err)
+		require.NotNil(t, unit.ProductUnitID)
+		assert.Equal(t, karton.ID, *unit.ProductUnitID)
+		assert.Equal(t, "KARTON", unit.UnitName)
+		assert.Equal(t, "48", baseQty.String())
+		assert.Equal(t, "3", unit.FromBase(decimal.NewFromInt(72)).String())
+		assert.Equal(t, "2500", unit.PriceToBase(decimal.NewFromInt(60000)).String())
+		assert.Equal(t, "60000", unit.PriceFromBase(decimal.NewFromInt(2500)).String())
+	})
+
+	t.Run("error - inactive unit is rejected on new lines", func(t *testing.T) {
+		_, _, err := service.ConvertQuantity(db, product.ID, &lusin.ID, decimal.NewFromInt(1))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "inactive")
+	})
+
+	t.Run("success - stored lines keep their inactive unit", func(t *testing.T) {
+		unit, err := service.LineUnit(db, &lusin.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "24", unit.ToBase(decimal.NewFromInt(2)).String())
+	})
+
+	t.Run("error - unit of another product", func(t *testing.T) {
+		_, err := service.ResolveUnit(db, other.ID, &karton.ID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found for product")
+	})
+}
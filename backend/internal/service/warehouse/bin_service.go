@@ -0,0 +1,365 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"backend/internal/dto"
+	"backend/internal/service/inventory"
+	"backend/models"
+	pkgerrors "backend/pkg/errors"
+)
+
+// ============================================================================
+// WAREHOUSE BINS
+// ============================================================================
+
+// ListWarehouseBins lists the bin locations of a warehouse in pick order (bin code)
+func (s *WarehouseService) ListWarehouseBins(ctx context.Context, tenantID, companyID, warehouseID string, query *dto.WarehouseBinListQuery) ([]models.WarehouseBin, error) {
+	if _, err := s.GetWarehouseByID(ctx, tenantID, companyID, warehouseID); err != nil {
+		return nil, err
+	}
+
+	binQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("warehouse_id = ?", warehouseID)
+	if query.Search != "" {
+		binQuery = binQuery.Where("code LIKE ?", "%"+query.Search+"%")
+	}
+	if query.Zone != nil {
+		binQuery = binQuery.Where("zone = ?", *query.Zone)
+	}
+	if query.IsActive != nil {
+		binQuery = binQuery.Where("is_active = ?", *query.IsActive)
+	}
+
+	var bins []models.WarehouseBin
+	if err := binQuery.Order("code ASC").Find(&bins).Error; err != nil {
+		return nil, fmt.Errorf("failed to list warehouse bins: %w", err)
+	}
+
+	return bins, nil
+}
+
+// GetWarehouseBinByID retrieves a bin of a warehouse
+func (s *WarehouseService) GetWarehouseBinByID(ctx context.Context, tenantID, companyID, warehouseID, binID string) (*models.WarehouseBin, error) {
+	if _, err := s.GetWarehouseByID(ctx, tenantID, companyID, warehouseID); err != nil {
+		return nil, err
+	}
+
+	var bin models.WarehouseBin
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("warehouse_id = ? AND id = ?", warehouseID, binID).
+		First(&bin).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, pkgerrors.NewNotFoundError("bin")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse bin: %w", err)
+	}
+
+	return &bin, nil
+}
+
+// CreateWarehouseBin creates a bin location in a warehouse
+func (s *WarehouseService) CreateWarehouseBin(ctx context.Context, tenantID, companyID, warehouseID string, req *dto.CreateWarehouseBinRequest) (*models.WarehouseBin, error) {
+	if _, err := s.GetWarehouseByID(ctx, tenantID, companyID, warehouseID); err != nil {
+		return nil, err
+	}
+
+	code := strings.TrimSpace(req.Code)
+	if err := s.validateBinCodeUniqueness(ctx, tenantID, warehouseID, code, ""); err != nil {
+		return nil, err
+	}
+
+	bin := &models.WarehouseBin{
+		TenantID:    tenantID,
+		WarehouseID: warehouseID,
+		Code:        code,
+		Zone:        req.Zone,
+		Aisle:       req.Aisle,
+		Rack:        req.Rack,
+		Level:       req.Level,
+		IsDefault:   req.IsDefault,
+		IsActive:    true,
+		Notes:       req.Notes,
+	}
+
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if bin.IsDefault {
+			if err := clearDefaultBin(tx, warehouseID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(bin).Error; err != nil {
+			return fmt.Errorf("failed to create warehouse bin: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bin, nil
+}
+
+// UpdateWarehouseBin updates a bin location. A bin still holding stock cannot be deactivated.
+func (s *WarehouseService) UpdateWarehouseBin(ctx context.Context, tenantID, companyID, warehouseID, binID string, req *dto.UpdateWarehouseBinRequest) (*models.WarehouseBin, error) {
+	bin, err := s.GetWarehouseBinByID(ctx, tenantID, companyID, warehouseID, binID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		code := strings.TrimSpace(*req.Code)
+		if code != bin.Code {
+			if err := s.validateBinCodeUniqueness(ctx, tenantID, warehouseID, code, bin.ID); err != nil {
+				return nil, err
+			}
+			bin.Code = code
+		}
+	}
+	if req.Zone != nil {
+		bin.Zone = req.Zone
+	}
+	if req.Aisle != nil {
+		bin.Aisle = req.Aisle
+	}
+	if req.Rack != nil {
+		bin.Rack = req.Rack
+	}
+	if req.Level != nil {
+		bin.Level = req.Level
+	}
+	if req.Notes != nil {
+		bin.Notes = req.Notes
+	}
+	if req.IsActive != nil && !*req.IsActive && bin.IsActive {
+		if err := s.validateBinEmpty(ctx, tenantID, bin); err != nil {
+			return nil, err
+		}
+		bin.IsActive = false
+	} else if req.IsActive != nil {
+		bin.IsActive = *req.IsActive
+	}
+	if req.IsDefault != nil {
+		bin.IsDefault = *req.IsDefault
+	}
+
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		if bin.IsDefault {
+			if err := clearDefaultBin(tx, warehouseID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(bin).Error; err != nil {
+			return fmt.Errorf("failed to update warehouse bin: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bin, nil
+}
+
+// DeleteWarehouseBin soft deletes an empty bin (sets IsActive = false).
+// Bins are kept so the movements that reference them stay readable.
+func (s *WarehouseService) DeleteWarehouseBin(ctx context.Context, tenantID, companyID, warehouseID, binID string) error {
+	bin, err := s.GetWarehouseBinByID(ctx, tenantID, companyID, warehouseID, binID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateBinEmpty(ctx, tenantID, bin); err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(bin).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"is_default": false,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to delete warehouse bin: %w", err)
+	}
+
+	return nil
+}
+
+// validateBinCodeUniqueness validates bin code uniqueness per warehouse
+func (s *WarehouseService) validateBinCodeUniqueness(ctx context.Context, tenantID, warehouseID, code, excludeBinID string) error {
+	query := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.WarehouseBin{}).
+		Where("warehouse_id = ? AND code = ?", warehouseID, code)
+	if excludeBinID != "" {
+		query = query.Where("id != ?", excludeBinID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check bin code uniqueness: %w", err)
+	}
+	if count > 0 {
+		return pkgerrors.NewConflictError(fmt.Sprintf("bin code %s already exists in this warehouse", code))
+	}
+
+	return nil
+}
+
+// validateBinEmpty checks that a bin holds no stock
+func (s *WarehouseService) validateBinEmpty(ctx context.Context, tenantID string, bin *models.WarehouseBin) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.BinStock{}).
+		Where("warehouse_bin_id = ? AND quantity > 0", bin.ID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check bin stock: %w", err)
+	}
+	if count > 0 {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("bin %s still holds stock; move it to another bin first", bin.Code))
+	}
+
+	return nil
+}
+
+// clearDefaultBin unsets the default put-away bin of a warehouse
+func clearDefaultBin(tx *gorm.DB, warehouseID string) error {
+	if err := tx.Model(&models.WarehouseBin{}).
+		Where("warehouse_id = ? AND is_default = ?", warehouseID, true).
+		Update("is_default", false).Error; err != nil {
+		return fmt.Errorf("failed to clear default bin: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// BIN STOCK
+// ============================================================================
+
+// ListBinStocks lists the stock held in the bins of a warehouse, in bin code order
+func (s *WarehouseService) ListBinStocks(ctx context.Context, tenantID, companyID, warehouseID string, query *dto.BinStockListQuery) (*dto.BinStockListResponse, error) {
+	if _, err := s.GetWarehouseByID(ctx, tenantID, companyID, warehouseID); err != nil {
+		return nil, err
+	}
+
+	page := 1
+	if query.Page > 0 {
+		page = query.Page
+	}
+
+	pageSize := 20
+	if query.PageSize > 0 {
+		pageSize = query.PageSize
+	}
+
+	baseQuery := s.db.WithContext(ctx).Set("tenant_id", tenantID).Model(&models.BinStock{}).
+		Joins("JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.warehouse_bin_id").
+		Joins("JOIN products ON products.id = bin_stocks.product_id").
+		Where("warehouse_bins.warehouse_id = ? AND bin_stocks.quantity > 0", warehouseID)
+
+	if query.BinID != nil {
+		baseQuery = baseQuery.Where("bin_stocks.warehouse_bin_id = ?", *query.BinID)
+	}
+	if query.ProductID != nil {
+		baseQuery = baseQuery.Where("bin_stocks.product_id = ?", *query.ProductID)
+	}
+	if query.Search != "" {
+		searchPattern := "%" + query.Search + "%"
+		baseQuery = baseQuery.Where("products.code LIKE ? OR products.name LIKE ?", searchPattern, searchPattern)
+	}
+
+	var totalCount int64
+	if err := baseQuery.Count(&totalCount).Error; err != nil {
+		return nil, fmt.Errorf("failed to count bin stocks: %w", err)
+	}
+
+	var stocks []models.BinStock
+	if err := baseQuery.Order("warehouse_bins.code ASC, products.code ASC").
+		Preload("WarehouseBin").
+		Preload("Product").
+		Preload("Batch").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&stocks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bin stocks: %w", err)
+	}
+
+	stockResponses := make([]dto.BinStockResponse, len(stocks))
+	for i, stock := range stocks {
+		stockResponses[i] = dto.BinStockResponse{
+			ID:          stock.ID,
+			BinID:       stock.WarehouseBinID,
+			BinCode:     stock.WarehouseBin.Code,
+			ProductID:   stock.ProductID,
+			ProductCode: stock.Product.Code,
+			ProductName: stock.Product.Name,
+			BatchID:     stock.BatchID,
+			Quantity:    stock.Quantity.String(),
+			UpdatedAt:   stock.UpdatedAt,
+		}
+		if stock.Batch != nil {
+			stockResponses[i].BatchNumber = &stock.Batch.BatchNumber
+		}
+	}
+
+	return &dto.BinStockListResponse{
+		Stocks:     stockResponses,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
+
+// MoveBinStock moves stock between bins of a warehouse (bin-to-bin move).
+// The warehouse quantity is unchanged; the move is written as TRANSFER movements per bin.
+func (s *WarehouseService) MoveBinStock(ctx context.Context, tenantID, companyID, warehouseID, userID string, req *dto.MoveBinStockRequest) (*dto.BinMoveResponse, error) {
+	if _, err := s.GetWarehouseByID(ctx, tenantID, companyID, warehouseID); err != nil {
+		return nil, err
+	}
+
+	qty, err := decimal.NewFromString(req.Quantity)
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid quantity format")
+	}
+
+	var movements []*models.InventoryMovement
+	err = s.db.WithContext(ctx).Set("tenant_id", tenantID).Transaction(func(tx *gorm.DB) error {
+		var err error
+		movements, err = s.stockPostingService.MoveBinStock(tx, &inventory.BinMove{
+			TenantID:    tenantID,
+			CompanyID:   companyID,
+			WarehouseID: warehouseID,
+			ProductID:   req.ProductID,
+			BatchID:     req.BatchID,
+			FromBinID:   req.FromBinID,
+			ToBinID:     req.ToBinID,
+			Quantity:    qty,
+			Notes:       req.Notes,
+			CreatedBy:   userID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.BinMoveResponse{
+		ProductID:   req.ProductID,
+		BatchID:     req.BatchID,
+		FromBinID:   req.FromBinID,
+		ToBinID:     req.ToBinID,
+		Quantity:    qty.String(),
+		MovementIDs: make([]string, len(movements)),
+	}
+	for i, movement := range movements {
+		response.MovementIDs[i] = movement.ID
+	}
+
+	return response, nil
+}
@@ -49,6 +49,33 @@ func (s *WarehouseService) validateManagerExists(managerID string) error {
 	return nil
 }
 
+// validateConsignmentCustomer validates the customer link of a warehouse: CONSIGNMENT warehouses
+// hold stock at a customer of the company, other warehouse types have no customer
+func (s *WarehouseService) validateConsignmentCustomer(ctx context.Context, tenantID, companyID string, warehouseType models.WarehouseType, customerID *string) error {
+	hasCustomer := customerID != nil && *customerID != ""
+	if warehouseType != models.WarehouseTypeConsignment {
+		if hasCustomer {
+			return pkgerrors.NewBadRequestError("only CONSIGNMENT warehouses can be linked to a customer")
+		}
+		return nil
+	}
+	if !hasCustomer {
+		return pkgerrors.NewBadRequestError("CONSIGNMENT warehouse requires a customer")
+	}
+
+	var customer models.Customer
+	err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Where("id = ? AND company_id = ?", *customerID, companyID).
+		First(&customer).Error
+	if err == gorm.ErrRecordNotFound {
+		return pkgerrors.NewBadRequestError("customer not found")
+	} else if err != nil {
+		return fmt.Errorf("failed to check customer existence: %w", err)
+	}
+
+	return nil
+}
+
 // validateDeleteWarehouse validates warehouse deletion
 // Reference: ANALYSIS-02-MASTER-DATA-MANAGEMENT.md Section 5.3 (Soft Delete Rules)
 func (s *WarehouseService) validateDeleteWarehouse(ctx context.Context, tenantID string, warehouse *models.Warehouse) error {
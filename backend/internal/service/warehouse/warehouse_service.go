@@ -12,6 +12,7 @@ import (
 
 	"backend/internal/dto"
 	"backend/internal/service/audit"
+	"backend/internal/service/inventory"
 	"backend/models"
 	pkgerrors "backend/pkg/errors"
 )
@@ -19,15 +20,17 @@ import (
 // WarehouseService - Business logic for warehouse management
 // Reference: ANALYSIS-02-MASTER-DATA-MANAGEMENT.md Module 4
 type WarehouseService struct {
-	db           *gorm.DB
-	auditService *audit.AuditService
+	db                  *gorm.DB
+	auditService        *audit.AuditService
+	stockPostingService *inventory.StockPostingService
 }
 
 // NewWarehouseService creates a new warehouse service instance
-func NewWarehouseService(db *gorm.DB, auditService *audit.AuditService) *WarehouseService {
+func NewWarehouseService(db *gorm.DB, auditService *audit.AuditService, stockPostingService *inventory.StockPostingService) *WarehouseService {
 	return &WarehouseService{
-		db:           db,
-		auditService: auditService,
+		db:                  db,
+		auditService:        auditService,
+		stockPostingService: stockPostingService,
 	}
 }
 
@@ -63,6 +66,11 @@ func (s *WarehouseService) CreateWarehouse(ctx context.Context, tenantID, compan
 		}
 	}
 
+	// Validate customer of consignment warehouse
+	if err := s.validateConsignmentCustomer(ctx, tenantID, companyID, models.WarehouseType(req.Type), req.CustomerID); err != nil {
+		return nil, err
+	}
+
 	// Create warehouse
 	warehouse := &models.Warehouse{
 		TenantID:   tenantID,
@@ -77,6 +85,7 @@ func (s *WarehouseService) CreateWarehouse(ctx context.Context, tenantID, compan
 		Phone:      req.Phone,
 		Email:      req.Email,
 		ManagerID:  req.ManagerID,
+		CustomerID: req.CustomerID,
 		Capacity:   capacity,
 		IsActive:   true,
 	}
@@ -107,6 +116,7 @@ func (s *WarehouseService) CreateWarehouse(ctx context.Context, tenantID, compan
 		"phone":       warehouse.Phone,
 		"email":       warehouse.Email,
 		"manager_id":  warehouse.ManagerID,
+		"customer_id": warehouse.CustomerID,
 		"is_active":   warehouse.IsActive,
 	}
 
@@ -266,6 +276,7 @@ func (s *WarehouseService) UpdateWarehouse(ctx context.Context, tenantID, compan
 		"phone":       warehouse.Phone,
 		"email":       warehouse.Email,
 		"manager_id":  warehouse.ManagerID,
+		"customer_id": warehouse.CustomerID,
 		"is_active":   warehouse.IsActive,
 	}
 	if warehouse.Capacity != nil {
@@ -295,6 +306,23 @@ func (s *WarehouseService) UpdateWarehouse(ctx context.Context, tenantID, compan
 
 	if req.Type != nil {
 		warehouse.Type = models.WarehouseType(*req.Type)
+		if warehouse.Type != models.WarehouseTypeConsignment && req.CustomerID == nil {
+			warehouse.CustomerID = nil
+		}
+	}
+
+	if req.CustomerID != nil {
+		if *req.CustomerID == "" {
+			warehouse.CustomerID = nil
+		} else {
+			warehouse.CustomerID = req.CustomerID
+		}
+	}
+
+	if req.Type != nil || req.CustomerID != nil {
+		if err := s.validateConsignmentCustomer(ctx, tenantID, companyID, warehouse.Type, warehouse.CustomerID); err != nil {
+			return nil, err
+		}
 	}
 
 	if req.Address != nil {
@@ -363,6 +391,7 @@ func (s *WarehouseService) UpdateWarehouse(ctx context.Context, tenantID, compan
 		"phone":       warehouse.Phone,
 		"email":       warehouse.Email,
 		"manager_id":  warehouse.ManagerID,
+		"customer_id": warehouse.CustomerID,
 		"is_active":   warehouse.IsActive,
 	}
 	if warehouse.Capacity != nil {
@@ -401,6 +430,7 @@ func (s *WarehouseService) DeleteWarehouse(ctx context.Context, tenantID, compan
 		"phone":       warehouse.Phone,
 		"email":       warehouse.Email,
 		"manager_id":  warehouse.ManagerID,
+		"customer_id": warehouse.CustomerID,
 		"is_active":   warehouse.IsActive,
 	}
 	if warehouse.Capacity != nil {
@@ -586,8 +616,16 @@ func (s *WarehouseService) UpdateWarehouseStock(ctx context.Context, tenantID, c
 		stock.Location = req.Location
 	}
 
-	// Save updates
-	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).Save(&stock).Error; err != nil {
+	// Save updates - only the settings columns, so quantities, cost and version written by
+	// concurrent stock postings are left alone
+	if err := s.db.WithContext(ctx).Set("tenant_id", tenantID).
+		Model(&models.WarehouseStock{}).
+		Where("id = ?", stock.ID).
+		Updates(map[string]interface{}{
+			"minimum_stock": stock.MinimumStock,
+			"maximum_stock": stock.MaximumStock,
+			"location":      stock.Location,
+		}).Error; err != nil {
 		return nil, fmt.Errorf("failed to update warehouse stock: %w", err)
 	}
 
@@ -636,6 +674,10 @@ func (s *WarehouseService) GetWarehouseStockStatus(ctx context.Context, tenantID
 		WarehouseCode string
 		TotalProducts int
 		TotalValue    string
+		OnHandQty     decimal.Decimal
+		ReservedQty   decimal.Decimal
+		QuarantineQty decimal.Decimal
+		InTransitQty  decimal.Decimal
 		LastUpdated   *time.Time
 	}
 
@@ -650,6 +692,10 @@ func (s *WarehouseService) GetWarehouseStockStatus(ctx context.Context, tenantID
 			warehouses.code as warehouse_code,
 			COUNT(DISTINCT warehouse_stocks.product_id) as total_products,
 			COALESCE(SUM(warehouse_stocks.quantity * 0), '0') as total_value,
+			COALESCE(SUM(warehouse_stocks.quantity), 0) as on_hand_qty,
+			COALESCE(SUM(warehouse_stocks.reserved_quantity), 0) as reserved_qty,
+			COALESCE(SUM(warehouse_stocks.quarantine_quantity), 0) as quarantine_qty,
+			COALESCE(SUM(warehouse_stocks.in_transit_quantity), 0) as in_transit_qty,
 			MAX(warehouse_stocks.updated_at) as last_updated
 		`).
 		Joins("LEFT JOIN warehouse_stocks ON warehouse_stocks.warehouse_id = warehouses.id").
@@ -672,6 +718,11 @@ func (s *WarehouseService) GetWarehouseStockStatus(ctx context.Context, tenantID
 			HasInitialStock: result.TotalProducts > 0,
 			TotalProducts:   result.TotalProducts,
 			TotalValue:      result.TotalValue,
+			OnHandQty:       result.OnHandQty.String(),
+			ReservedQty:     result.ReservedQty.String(),
+			AvailableQty:    result.OnHandQty.Sub(result.ReservedQty).Sub(result.QuarantineQty).String(),
+			QuarantineQty:   result.QuarantineQty.String(),
+			InTransitQty:    result.InTransitQty.String(),
 			LastUpdated:     result.LastUpdated,
 		})
 	}
@@ -827,137 +878,97 @@ func (s *WarehouseService) CreateInitialStock(
 
 		// Check if warehouse stock already exists
 		var existingStock models.WarehouseStock
+		action := "updated"
 		err = tx.Where("warehouse_id = ? AND product_id = ?", req.WarehouseID, item.ProductID).
 			First(&existingStock).Error
 
 		if err == gorm.ErrRecordNotFound {
-			// Create new warehouse stock
-			newStock := &models.WarehouseStock{
+			// Create new warehouse stock with settings; quantity is posted below
+			existingStock = models.WarehouseStock{
 				WarehouseID:  req.WarehouseID,
 				ProductID:    item.ProductID,
-				Quantity:     qty,
+				Quantity:     decimal.Zero,
 				MinimumStock: minStock,
 				MaximumStock: maxStock,
 				Location:     item.Location,
 			}
 
-			if err := tx.Create(newStock).Error; err != nil {
+			if err := tx.Create(&existingStock).Error; err != nil {
 				tx.Rollback()
 				logFailure(fmt.Sprintf("Failed to create warehouse stock for product %s: %v", item.ProductID, err))
 				return nil, fmt.Errorf("failed to create warehouse stock: %w", err)
 			}
-
-			// Create inventory movement
-			refType := "INITIAL_STOCK"
-			movement := &models.InventoryMovement{
-				TenantID:      tenantID,
-				CompanyID:     companyID,
-				MovementDate:  time.Now(),
-				WarehouseID:   req.WarehouseID,
-				ProductID:     item.ProductID,
-				MovementType:  models.MovementTypeInitial,
-				Quantity:      qty,
-				StockBefore:   decimal.Zero,
-				StockAfter:    qty,
-				ReferenceType: &refType,
-				ReferenceID:   &newStock.ID,
-				Notes:         req.Notes,
-				CreatedBy:     &userID,
-			}
-
-			if err := tx.Create(movement).Error; err != nil {
-				tx.Rollback()
-				logFailure(fmt.Sprintf("Failed to create inventory movement for product %s: %v", item.ProductID, err))
-				return nil, fmt.Errorf("failed to create inventory movement: %w", err)
-			}
-
-			// Collect audit data for created item
-			product := productMap[item.ProductID]
-			itemValue := qty.Mul(cost)
-			itemsAuditData = append(itemsAuditData, itemAuditData{
-				ProductID:    item.ProductID,
-				ProductCode:  product.Code,
-				ProductName:  product.Name,
-				Quantity:     qty.String(),
-				CostPerUnit:  cost.String(),
-				Value:        itemValue.String(),
-				Location:     item.Location,
-				MinimumStock: minStock.String(),
-				MaximumStock: maxStock.String(),
-				Notes:        item.Notes,
-				Action:       "created",
-				StockBefore:  "0",
-				StockAfter:   qty.String(),
-			})
-
-			createdCount++
+			action = "created"
 		} else if err != nil {
 			tx.Rollback()
 			logFailure(fmt.Sprintf("Failed to check existing stock for product %s: %v", item.ProductID, err))
 			return nil, fmt.Errorf("failed to check existing stock: %w", err)
 		} else {
-			// Update existing warehouse stock
-			stockBefore := existingStock.Quantity
-			existingStock.Quantity = existingStock.Quantity.Add(qty)
+			// Update existing warehouse stock settings
+			settings := map[string]interface{}{}
 			if item.MinimumStock != nil && *item.MinimumStock != "" {
-				existingStock.MinimumStock = minStock
+				settings["minimum_stock"] = minStock
 			}
 			if item.MaximumStock != nil && *item.MaximumStock != "" {
-				existingStock.MaximumStock = maxStock
+				settings["maximum_stock"] = maxStock
 			}
 			if item.Location != nil {
-				existingStock.Location = item.Location
+				settings["location"] = item.Location
 			}
 
-			if err := tx.Save(&existingStock).Error; err != nil {
-				tx.Rollback()
-				logFailure(fmt.Sprintf("Failed to update warehouse stock for product %s: %v", item.ProductID, err))
-				return nil, fmt.Errorf("failed to update warehouse stock: %w", err)
-			}
-
-			// Create inventory movement
-			refType := "INITIAL_STOCK"
-			movement := &models.InventoryMovement{
-				TenantID:      tenantID,
-				CompanyID:     companyID,
-				MovementDate:  time.Now(),
-				WarehouseID:   req.WarehouseID,
-				ProductID:     item.ProductID,
-				MovementType:  models.MovementTypeInitial,
-				Quantity:      qty,
-				StockBefore:   stockBefore,
-				StockAfter:    existingStock.Quantity,
-				ReferenceType: &refType,
-				ReferenceID:   &existingStock.ID,
-				Notes:         req.Notes,
-				CreatedBy:     &userID,
+			if len(settings) > 0 {
+				if err := tx.Model(&existingStock).Updates(settings).Error; err != nil {
+					tx.Rollback()
+					logFailure(fmt.Sprintf("Failed to update warehouse stock for product %s: %v", item.ProductID, err))
+					return nil, fmt.Errorf("failed to update warehouse stock: %w", err)
+				}
 			}
+		}
 
-			if err := tx.Create(movement).Error; err != nil {
-				tx.Rollback()
-				logFailure(fmt.Sprintf("Failed to create inventory movement for product %s: %v", item.ProductID, err))
-				return nil, fmt.Errorf("failed to create inventory movement: %w", err)
+		// Post quantity and inventory movement
+		result, err := s.stockPostingService.Post(tx, &inventory.StockPosting{
+			TenantID:      tenantID,
+			CompanyID:     companyID,
+			WarehouseID:   req.WarehouseID,
+			ProductID:     item.ProductID,
+			MovementType:  models.MovementTypeInitial,
+			Quantity:      qty,
+			UnitCost:      &cost,
+			ReferenceType: inventory.ReferenceTypeInitialStock,
+			ReferenceID:   existingStock.ID,
+			Notes:         req.Notes,
+			CreatedBy:     userID,
+		})
+		if err != nil {
+			tx.Rollback()
+			logFailure(fmt.Sprintf("Failed to post initial stock for product %s: %v", item.ProductID, err))
+			if appErr, ok := err.(*pkgerrors.AppError); ok {
+				return nil, appErr
 			}
+			return nil, fmt.Errorf("failed to post initial stock: %w", err)
+		}
 
-			// Collect audit data for updated item
-			product := productMap[item.ProductID]
-			itemValue := qty.Mul(cost)
-			itemsAuditData = append(itemsAuditData, itemAuditData{
-				ProductID:    item.ProductID,
-				ProductCode:  product.Code,
-				ProductName:  product.Name,
-				Quantity:     qty.String(),
-				CostPerUnit:  cost.String(),
-				Value:        itemValue.String(),
-				Location:     item.Location,
-				MinimumStock: minStock.String(),
-				MaximumStock: maxStock.String(),
-				Notes:        item.Notes,
-				Action:       "updated",
-				StockBefore:  stockBefore.String(),
-				StockAfter:   existingStock.Quantity.String(),
-			})
+		// Collect audit data for item
+		product := productMap[item.ProductID]
+		itemsAuditData = append(itemsAuditData, itemAuditData{
+			ProductID:    item.ProductID,
+			ProductCode:  product.Code,
+			ProductName:  product.Name,
+			Quantity:     qty.String(),
+			CostPerUnit:  cost.String(),
+			Value:        qty.Mul(cost).String(),
+			Location:     item.Location,
+			MinimumStock: minStock.String(),
+			MaximumStock: maxStock.String(),
+			Notes:        item.Notes,
+			Action:       action,
+			StockBefore:  result.Movement.StockBefore.String(),
+			StockAfter:   result.Movement.StockAfter.String(),
+		})
 
+		if action == "created" {
+			createdCount++
+		} else {
 			updatedCount++
 		}
 
@@ -1035,6 +1046,7 @@ func mapWarehouseToResponse(warehouse *models.Warehouse) dto.WarehouseResponse {
 		Phone:      warehouse.Phone,
 		Email:      warehouse.Email,
 		ManagerID:  warehouse.ManagerID,
+		CustomerID: warehouse.CustomerID,
 		Capacity:   capacity,
 		IsActive:   warehouse.IsActive,
 		CreatedAt:  warehouse.CreatedAt,
@@ -1073,6 +1085,10 @@ func mapWarehouseStockToResponse(stock *models.WarehouseStock) dto.WarehouseStoc
 		ProductCode:   productCode,
 		ProductName:   productName,
 		Quantity:      stock.Quantity.String(),
+		ReservedQty:   stock.ReservedQuantity.String(),
+		AvailableQty:  stock.AvailableQuantity().String(),
+		QuarantineQty: stock.QuarantineQuantity.String(),
+		InTransitQty:  stock.InTransitQuantity.String(),
 		MinimumStock:  stock.MinimumStock.String(),
 		MaximumStock:  stock.MaximumStock.String(),
 		Location:      stock.Location,
@@ -38,6 +38,12 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		&models.Supplier{},
 		&models.Warehouse{},
 		&models.WarehouseStock{},
+		&models.WarehouseBin{},
+		&models.BinStock{},
+
+		// Stock opnames are checked by every stock posting (freeze scope)
+		&models.StockOpname{},
+		&models.StockOpnameItem{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
@@ -0,0 +1,127 @@
+// Package models - Bill of materials and assembly order models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ProductBOMComponent - One component of a product's bill of materials
+// (e.g. a gift parcel made of 2 bottles of syrup and 1 tin of biscuits).
+// Quantities are per one base unit of the finished product.
+type ProductBOMComponent struct {
+	ID                 string          `gorm:"type:varchar(255);primaryKey"`
+	ProductID          string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_bom_product_component"` // Finished product
+	ComponentProductID string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_bom_product_component"`
+	ProductUnitID      *string         `gorm:"type:varchar(255);index"`      // Unit of the component quantity (nil = base unit)
+	Quantity           decimal.Decimal `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity       decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Quantity in component base unit
+	Notes              *string         `gorm:"type:text"`
+	CreatedAt          time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	Product          Product      `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	ComponentProduct Product      `gorm:"foreignKey:ComponentProductID;constraint:OnDelete:RESTRICT"`
+	ProductUnit      *ProductUnit `gorm:"foreignKey:ProductUnitID"`
+}
+
+// TableName specifies the table name for ProductBOMComponent model
+func (ProductBOMComponent) TableName() string {
+	return "product_bom_components"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (bc *ProductBOMComponent) BeforeCreate(tx *gorm.DB) error {
+	if bc.ID == "" {
+		bc.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// AssemblyOrder - Builds a product from its bill of materials (ASSEMBLY) or breaks it back
+// into its components (DISASSEMBLY) in one warehouse.
+// Completing the order posts the component and finished product movements; the finished
+// product is valued at the rolled-up cost of the components consumed.
+type AssemblyOrder struct {
+	ID          string              `gorm:"type:varchar(255);primaryKey"`
+	TenantID    string              `gorm:"type:varchar(255);not null;index"`
+	CompanyID   string              `gorm:"type:varchar(255);not null;index:idx_company_assembly_order;uniqueIndex:idx_company_assembly_order_number"`
+	OrderNumber string              `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_assembly_order_number"`
+	OrderDate   time.Time           `gorm:"type:timestamp;not null;index"`
+	Type        AssemblyOrderType   `gorm:"type:varchar(20);not null;index"`
+	Status      AssemblyOrderStatus `gorm:"type:varchar(20);not null;default:'DRAFT';index"`
+	WarehouseID string              `gorm:"type:varchar(255);not null;index"`
+	ProductID   string              `gorm:"type:varchar(255);not null;index"` // Finished product
+	Quantity    decimal.Decimal     `gorm:"type:decimal(15,3);not null"`      // Finished product, base unit
+	BatchID     *string             `gorm:"type:varchar(255);index"`          // Finished batch produced (ASSEMBLY) or taken apart (DISASSEMBLY)
+	BatchNumber *string             `gorm:"type:varchar(100)"`                // Batch number for a batch-tracked finished product (ASSEMBLY)
+	ExpiryDate  *time.Time          `gorm:"type:timestamp"`                   // Default: earliest expiry of the components consumed
+	UnitCost    decimal.Decimal     `gorm:"type:decimal(15,4);default:0"`     // Rolled-up cost per finished base unit
+	TotalCost   decimal.Decimal     `gorm:"type:decimal(15,2);default:0"`
+	Notes       *string             `gorm:"type:text"`
+	CompletedBy *string             `gorm:"type:varchar(255)"`
+	CompletedAt *time.Time          `gorm:"type:timestamp"`
+	CreatedBy   *string             `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time           `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time           `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant    Tenant              `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company   Company             `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Warehouse Warehouse           `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product   Product             `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Batch     *ProductBatch       `gorm:"foreignKey:BatchID"`
+	Items     []AssemblyOrderItem `gorm:"foreignKey:AssemblyOrderID"`
+}
+
+// TableName specifies the table name for AssemblyOrder model
+func (AssemblyOrder) TableName() string {
+	return "assembly_orders"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ao *AssemblyOrder) BeforeCreate(tx *gorm.DB) error {
+	if ao.ID == "" {
+		ao.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// AssemblyOrderItem - Component consumed (ASSEMBLY) or recovered (DISASSEMBLY) by an order.
+// Batch-tracked components are split per batch on completion, like delivery items.
+type AssemblyOrderItem struct {
+	ID              string          `gorm:"type:varchar(255);primaryKey"`
+	AssemblyOrderID string          `gorm:"type:varchar(255);not null;index"`
+	ProductID       string          `gorm:"type:varchar(255);not null;index"` // Component
+	ProductUnitID   *string         `gorm:"type:varchar(255);index"`
+	BatchID         *string         `gorm:"type:varchar(255);index"`
+	Quantity        decimal.Decimal `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity    decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Quantity in component base unit
+	UnitCost        decimal.Decimal `gorm:"type:decimal(15,4);default:0"` // Per base unit, set on completion
+	TotalCost       decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	AssemblyOrder AssemblyOrder `gorm:"foreignKey:AssemblyOrderID;constraint:OnDelete:CASCADE"`
+	Product       Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	ProductUnit   *ProductUnit  `gorm:"foreignKey:ProductUnitID"`
+	Batch         *ProductBatch `gorm:"foreignKey:BatchID"`
+}
+
+// TableName specifies the table name for AssemblyOrderItem model
+func (AssemblyOrderItem) TableName() string {
+	return "assembly_order_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (aoi *AssemblyOrderItem) BeforeCreate(tx *gorm.DB) error {
+	if aoi.ID == "" {
+		aoi.ID = uuid.New().String()
+	}
+	return nil
+}
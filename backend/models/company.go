@@ -2,6 +2,9 @@
 package models
 
 import (
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -63,6 +66,18 @@ type Company struct {
 	InvoiceControlPolicy InvoiceControlPolicy `gorm:"type:varchar(20);default:'ORDERED'"` // ORDERED = invoice based on PO qty, RECEIVED = invoice based on GRN qty
 	InvoiceTolerancePct  decimal.Decimal      `gorm:"type:decimal(5,2);default:0"`        // Tolerance % for over-invoicing (e.g., 5.00 = 5%)
 
+	// Inventory Valuation Settings
+	CostingMethod CostingMethod `gorm:"type:varchar(20);default:'AVERAGE'"` // AVERAGE = moving average, FIFO = cost layers
+
+	// Negative Stock Settings
+	NegativeStockPolicy NegativeStockPolicy `gorm:"type:varchar(10);default:'BLOCK'"` // ALLOW, WARN or BLOCK postings that drive on-hand below zero
+
+	// Stock Transfer Settings
+	TransferLossReason InventoryAdjustmentReason `gorm:"type:varchar(20);default:'SHRINKAGE'"` // Default reason for transfer receipt discrepancies
+
+	// Batch Expiry Settings
+	ExpiryAlertDays string `gorm:"type:varchar(50);default:'30,60,90'"` // Near-expiry horizons in days, comma separated
+
 	// System Settings
 	Currency string `gorm:"type:varchar(10);default:'IDR'"`
 	Timezone string `gorm:"type:varchar(50);default:'Asia/Jakarta'"`
@@ -126,3 +141,22 @@ func (cb *CompanyBank) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// ExpiryAlertHorizons returns the near-expiry horizons in days, ascending.
+// Falls back to 30, 60 and 90 days when the setting is empty or invalid.
+func (c *Company) ExpiryAlertHorizons() []int {
+	var horizons []int
+	for _, part := range strings.Split(c.ExpiryAlertDays, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && days > 0 {
+			horizons = append(horizons, days)
+		}
+	}
+
+	if len(horizons) == 0 {
+		return []int{30, 60, 90}
+	}
+
+	sort.Ints(horizons)
+	return horizons
+}
@@ -0,0 +1,87 @@
+// Package models - Consignment stock models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ConsignmentSettlement - Customer's sell-through report for a consignment warehouse.
+// Posting it invoices the sold quantity and takes it out of the consignment stock.
+type ConsignmentSettlement struct {
+	ID               string                `gorm:"type:varchar(255);primaryKey"`
+	TenantID         string                `gorm:"type:varchar(255);not null;index"`
+	CompanyID        string                `gorm:"type:varchar(255);not null;index:idx_company_consignment_settlement;uniqueIndex:idx_company_consignment_settlement_number"`
+	SettlementNumber string                `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_consignment_settlement_number"`
+	SettlementDate   time.Time             `gorm:"type:timestamp;not null;index"`
+	CustomerID       string                `gorm:"type:varchar(255);not null;index"`
+	WarehouseID      string                `gorm:"type:varchar(255);not null;index"`
+	ReportType       ConsignmentReportType `gorm:"type:varchar(20);not null"`
+	InvoiceID        *string               `gorm:"type:varchar(255);index"` // Settlement sales invoice (nil when nothing was sold)
+	Subtotal         decimal.Decimal       `gorm:"type:decimal(15,2);default:0"`
+	TaxAmount        decimal.Decimal       `gorm:"type:decimal(15,2);default:0"`
+	TotalAmount      decimal.Decimal       `gorm:"type:decimal(15,2);default:0"`
+	CostAmount       decimal.Decimal       `gorm:"type:decimal(15,2);default:0"` // Inventory cost of the sold quantity
+	Notes            *string               `gorm:"type:text"`
+	CreatedBy        *string               `gorm:"type:varchar(255)"`
+	CreatedAt        time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time             `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant    Tenant                      `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company   Company                     `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Customer  Customer                    `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	Warehouse Warehouse                   `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Invoice   *Invoice                    `gorm:"foreignKey:InvoiceID"`
+	Items     []ConsignmentSettlementItem `gorm:"foreignKey:ConsignmentSettlementID"`
+}
+
+// TableName specifies the table name for ConsignmentSettlement model
+func (ConsignmentSettlement) TableName() string {
+	return "consignment_settlements"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (cs *ConsignmentSettlement) BeforeCreate(tx *gorm.DB) error {
+	if cs.ID == "" {
+		cs.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ConsignmentSettlementItem - Reported and sold quantity of one product (and batch)
+type ConsignmentSettlementItem struct {
+	ID                      string          `gorm:"type:varchar(255);primaryKey"`
+	ConsignmentSettlementID string          `gorm:"type:varchar(255);not null;index"`
+	ProductID               string          `gorm:"type:varchar(255);not null;index"`
+	BatchID                 *string         `gorm:"type:varchar(255);index"`
+	ReportedQty             decimal.Decimal `gorm:"type:decimal(15,3);not null"` // As reported: sold qty (SOLD) or qty still held (ON_HAND)
+	StockQty                decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Consignment stock before the settlement (base unit)
+	SoldQty                 decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Invoiced and taken out of stock (base unit)
+	UnitPrice               decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	Subtotal                decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	UnitCost                decimal.Decimal `gorm:"type:decimal(15,4);default:0"` // Cost per base unit taken out of stock
+	InvoiceItemID           *string         `gorm:"type:varchar(255);index"`
+	CreatedAt               time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	ConsignmentSettlement ConsignmentSettlement `gorm:"foreignKey:ConsignmentSettlementID;constraint:OnDelete:CASCADE"`
+	Product               Product               `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Batch                 *ProductBatch         `gorm:"foreignKey:BatchID"`
+}
+
+// TableName specifies the table name for ConsignmentSettlementItem model
+func (ConsignmentSettlementItem) TableName() string {
+	return "consignment_settlement_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (csi *ConsignmentSettlementItem) BeforeCreate(tx *gorm.DB) error {
+	if csi.ID == "" {
+		csi.ID = uuid.New().String()
+	}
+	return nil
+}
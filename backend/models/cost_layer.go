@@ -0,0 +1,50 @@
+// Package models - Inventory Cost Layer models (FIFO valuation)
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CostLayer - FIFO cost layer created by an inbound stock movement
+// Outbound movements consume the oldest layers first (by LayerDate).
+// Only maintained for companies using CostingMethodFIFO.
+type CostLayer struct {
+	ID                string          `gorm:"type:varchar(255);primaryKey"`
+	TenantID          string          `gorm:"type:varchar(255);not null;index"`
+	CompanyID         string          `gorm:"type:varchar(255);not null;index:idx_company_cost_layer"`
+	WarehouseID       string          `gorm:"type:varchar(255);not null;index:idx_cost_layer_stock"`
+	ProductID         string          `gorm:"type:varchar(255);not null;index:idx_cost_layer_stock"`
+	MovementID        string          `gorm:"type:varchar(255);not null;index"` // Inbound movement that created the layer
+	LayerDate         time.Time       `gorm:"type:timestamp;not null;index"`
+	OriginalQuantity  decimal.Decimal `gorm:"type:decimal(15,3);not null"`
+	RemainingQuantity decimal.Decimal `gorm:"type:decimal(15,3);not null"`
+	UnitCost          decimal.Decimal `gorm:"type:decimal(15,4);not null"`
+	ReferenceType     *string         `gorm:"type:varchar(50)"`
+	ReferenceNumber   *string         `gorm:"type:varchar(100)"`
+	CreatedAt         time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant    Tenant            `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company   Company           `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Warehouse Warehouse         `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product   Product           `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Movement  InventoryMovement `gorm:"foreignKey:MovementID;constraint:OnDelete:RESTRICT"`
+}
+
+// TableName specifies the table name for CostLayer model
+func (CostLayer) TableName() string {
+	return "cost_layers"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (cl *CostLayer) BeforeCreate(tx *gorm.DB) error {
+	if cl.ID == "" {
+		cl.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Package models - Customer return (RMA) models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CustomerReturn - Goods sent back by a customer against a delivery (and its invoice).
+// Each line is inspected into sellable stock, quarantine or scrap; completing the return
+// posts the stock and raises a sales credit note for the invoiced lines.
+type CustomerReturn struct {
+	ID           string               `gorm:"type:varchar(255);primaryKey"`
+	TenantID     string               `gorm:"type:varchar(255);not null;index"`
+	CompanyID    string               `gorm:"type:varchar(255);not null;index:idx_company_customer_return;uniqueIndex:idx_company_return_number"`
+	ReturnNumber string               `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_return_number"`
+	ReturnDate   time.Time            `gorm:"type:timestamp;not null;index"`
+	CustomerID   string               `gorm:"type:varchar(255);not null;index"`
+	DeliveryID   string               `gorm:"type:varchar(255);not null;index"`
+	InvoiceID    *string              `gorm:"type:varchar(255);index"`          // Invoice the returned lines were billed on
+	WarehouseID  string               `gorm:"type:varchar(255);not null;index"` // Warehouse the goods come back to (the delivery's)
+	Status       CustomerReturnStatus `gorm:"type:varchar(20);default:'DRAFT';index"`
+	Reason       string               `gorm:"type:text;not null"`
+	Notes        *string              `gorm:"type:text"`
+	CreditNoteID *string              `gorm:"type:varchar(255);index"` // Credit note raised on completion
+	InspectedBy  *string              `gorm:"type:varchar(255)"`
+	InspectedAt  *time.Time           `gorm:"type:timestamp"`
+	CompletedBy  *string              `gorm:"type:varchar(255)"`
+	CompletedAt  *time.Time           `gorm:"type:timestamp"`
+	CreatedBy    *string              `gorm:"type:varchar(255)"`
+	CreatedAt    time.Time            `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time            `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant     Tenant               `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company    Company              `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Customer   Customer             `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	Delivery   Delivery             `gorm:"foreignKey:DeliveryID;constraint:OnDelete:RESTRICT"`
+	Invoice    *Invoice             `gorm:"foreignKey:InvoiceID"`
+	Warehouse  Warehouse            `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	CreditNote *SalesCreditNote     `gorm:"foreignKey:CreditNoteID"`
+	Items      []CustomerReturnItem `gorm:"foreignKey:CustomerReturnID"`
+}
+
+// TableName specifies the table name for CustomerReturn model
+func (CustomerReturn) TableName() string {
+	return "customer_returns"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (cr *CustomerReturn) BeforeCreate(tx *gorm.DB) error {
+	if cr.ID == "" {
+		cr.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// CustomerReturnItem - A returned delivery line with its inspection outcome
+type CustomerReturnItem struct {
+	ID               string             `gorm:"type:varchar(255);primaryKey"`
+	CustomerReturnID string             `gorm:"type:varchar(255);not null;index"`
+	DeliveryItemID   string             `gorm:"type:varchar(255);not null;index"`
+	InvoiceItemID    *string            `gorm:"type:varchar(255);index"` // Invoice line credited for this return line
+	ProductID        string             `gorm:"type:varchar(255);not null;index"`
+	ProductUnitID    *string            `gorm:"type:varchar(255);index"`
+	BatchID          *string            `gorm:"type:varchar(255);index"`      // Batch delivered to the customer
+	Quantity         decimal.Decimal    `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity     decimal.Decimal    `gorm:"type:decimal(15,3);default:0"` // Quantity in product base unit
+	UnitPrice        decimal.Decimal    `gorm:"type:decimal(15,2);default:0"` // Net invoiced price per base unit
+	Amount           decimal.Decimal    `gorm:"type:decimal(15,2);default:0"` // BaseQuantity x UnitPrice, before invoice discount and tax
+	Disposition      *ReturnDisposition `gorm:"type:varchar(20)"`             // Set at inspection
+	InspectionNote   *string            `gorm:"type:text"`
+	ResultBatchID    *string            `gorm:"type:varchar(255);index"` // Batch the goods were booked into (quarantine gets its own batch)
+	Notes            *string            `gorm:"type:text"`
+	CreatedAt        time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time          `gorm:"autoUpdateTime"`
+
+	// Relations
+	CustomerReturn CustomerReturn `gorm:"foreignKey:CustomerReturnID;constraint:OnDelete:CASCADE"`
+	DeliveryItem   DeliveryItem   `gorm:"foreignKey:DeliveryItemID;constraint:OnDelete:RESTRICT"`
+	InvoiceItem    *InvoiceItem   `gorm:"foreignKey:InvoiceItemID"`
+	Product        Product        `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	ProductUnit    *ProductUnit   `gorm:"foreignKey:ProductUnitID"`
+	Batch          *ProductBatch  `gorm:"foreignKey:BatchID"`
+	ResultBatch    *ProductBatch  `gorm:"foreignKey:ResultBatchID"`
+}
+
+// TableName specifies the table name for CustomerReturnItem model
+func (CustomerReturnItem) TableName() string {
+	return "customer_return_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (cri *CustomerReturnItem) BeforeCreate(tx *gorm.DB) error {
+	if cri.ID == "" {
+		cri.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -66,18 +66,19 @@ type DeliveryItem struct {
 	SalesOrderItemID string          `gorm:"type:varchar(255);not null;index"`
 	ProductID        string          `gorm:"type:varchar(255);not null;index"`
 	ProductUnitID    *string         `gorm:"type:varchar(255);index"`
-	BatchID          *string         `gorm:"type:varchar(255);index"` // Required if product.isBatchTracked
-	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"`
+	BatchID          *string         `gorm:"type:varchar(255);index"`      // Required if product.isBatchTracked
+	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity     decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Quantity in product base unit
 	Notes            *string         `gorm:"type:text"`
 	CreatedAt        time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt        time.Time       `gorm:"autoUpdateTime"`
 
 	// Relations
-	Delivery       Delivery        `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE"`
-	SalesOrderItem SalesOrderItem  `gorm:"foreignKey:SalesOrderItemID;constraint:OnDelete:RESTRICT"`
-	Product        Product         `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
-	ProductUnit    *ProductUnit    `gorm:"foreignKey:ProductUnitID"`
-	Batch          *ProductBatch   `gorm:"foreignKey:BatchID"`
+	Delivery       Delivery       `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE"`
+	SalesOrderItem SalesOrderItem `gorm:"foreignKey:SalesOrderItemID;constraint:OnDelete:RESTRICT"`
+	Product        Product        `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	ProductUnit    *ProductUnit   `gorm:"foreignKey:ProductUnitID"`
+	Batch          *ProductBatch  `gorm:"foreignKey:BatchID"`
 }
 
 // TableName specifies the table name for DeliveryItem model
@@ -91,12 +91,39 @@ const (
 type BatchStatus string
 
 const (
-	BatchStatusAvailable BatchStatus = "AVAILABLE" // Available for sale
-	BatchStatusReserved  BatchStatus = "RESERVED"  // Reserved for sales order
-	BatchStatusExpired   BatchStatus = "EXPIRED"   // Past expiry date
-	BatchStatusDamaged   BatchStatus = "DAMAGED"   // Damaged/defective
-	BatchStatusRecalled  BatchStatus = "RECALLED"  // Product recall
-	BatchStatusSold      BatchStatus = "SOLD"      // Fully sold out
+	BatchStatusAvailable  BatchStatus = "AVAILABLE"  // Available for sale
+	BatchStatusReserved   BatchStatus = "RESERVED"   // Reserved for sales order
+	BatchStatusExpired    BatchStatus = "EXPIRED"    // Past expiry date
+	BatchStatusDamaged    BatchStatus = "DAMAGED"    // Damaged/defective
+	BatchStatusRecalled   BatchStatus = "RECALLED"   // Product recall
+	BatchStatusSold       BatchStatus = "SOLD"       // Fully sold out
+	BatchStatusQuarantine BatchStatus = "QUARANTINE" // Held for quality decision, not available
+)
+
+// IsHeld returns true if stock in a batch with this status is on hand but cannot be sold
+func (s BatchStatus) IsHeld() bool {
+	switch s {
+	case BatchStatusQuarantine, BatchStatusRecalled, BatchStatusExpired, BatchStatusDamaged:
+		return true
+	default:
+		return false
+	}
+}
+
+// Batch quality status values (ProductBatch.QualityStatus)
+const (
+	BatchQualityGood       = "GOOD"
+	BatchQualityDamaged    = "DAMAGED"
+	BatchQualityQuarantine = "QUARANTINE"
+)
+
+// StockReservationStatus - Sales order stock reservation lifecycle
+type StockReservationStatus string
+
+const (
+	StockReservationStatusActive    StockReservationStatus = "ACTIVE"    // Stok dipesan untuk SO
+	StockReservationStatusFulfilled StockReservationStatus = "FULFILLED" // Sudah dikirim seluruhnya
+	StockReservationStatusReleased  StockReservationStatus = "RELEASED"  // Dilepas (SO dibatalkan)
 )
 
 // SalesOrderStatus - Complete SO workflow with 8 statuses
@@ -141,6 +168,32 @@ const (
 	InvoiceControlPolicyReceived InvoiceControlPolicy = "RECEIVED" // Hanya bisa invoice berdasarkan qty yang sudah diterima (GRN)
 )
 
+// CostingMethod - Inventory valuation method per company
+type CostingMethod string
+
+const (
+	CostingMethodAverage CostingMethod = "AVERAGE" // Rata-rata tertimbang bergerak (moving average)
+	CostingMethodFIFO    CostingMethod = "FIFO"    // First In First Out (lapisan biaya)
+)
+
+// NegativeStockPolicy - How stock postings that would drive on-hand below zero are handled per company
+type NegativeStockPolicy string
+
+const (
+	NegativeStockPolicyAllow NegativeStockPolicy = "ALLOW" // Izinkan stok minus tanpa peringatan
+	NegativeStockPolicyWarn  NegativeStockPolicy = "WARN"  // Izinkan stok minus dengan peringatan
+	NegativeStockPolicyBlock NegativeStockPolicy = "BLOCK" // Tolak transaksi yang membuat stok minus
+)
+
+// LandedCostAllocationMethod - Basis for spreading invoice extra charges over received lines
+type LandedCostAllocationMethod string
+
+const (
+	LandedCostAllocationByValue    LandedCostAllocationMethod = "VALUE"    // Proporsional terhadap nilai barang
+	LandedCostAllocationByQuantity LandedCostAllocationMethod = "QUANTITY" // Proporsional terhadap jumlah barang
+	LandedCostAllocationByWeight   LandedCostAllocationMethod = "WEIGHT"   // Proporsional terhadap berat (ProductUnit.Weight)
+)
+
 // RejectionDisposition - Disposition for rejected goods (Odoo+M3 model)
 type RejectionDisposition string
 
@@ -208,6 +261,7 @@ const (
 	MovementTypeDamaged    MovementType = "DAMAGED"    // Barang rusak
 	MovementTypeTransfer   MovementType = "TRANSFER"   // Transfer antar gudang
 	MovementTypeInitial    MovementType = "INITIAL"    // Initial stock setup
+	MovementTypeAssembly   MovementType = "ASSEMBLY"   // Komponen dipakai / produk jadi dihasilkan (assembly/disassembly)
 )
 
 // StockOpnameStatus - Physical count workflow
@@ -221,6 +275,23 @@ const (
 	StockOpnameStatusCancelled  StockOpnameStatus = "CANCELLED"   // Cancelled
 )
 
+// OpnameFreezeScope - Stock postings blocked while a stock opname is IN_PROGRESS
+type OpnameFreezeScope string
+
+const (
+	OpnameFreezeScopeNone      OpnameFreezeScope = "NONE"      // Tidak dibekukan, selisih dihitung ulang saat approval
+	OpnameFreezeScopeWarehouse OpnameFreezeScope = "WAREHOUSE" // Seluruh gudang dibekukan
+	OpnameFreezeScopeProducts  OpnameFreezeScope = "PRODUCTS"  // Hanya produk yang ada di opname
+)
+
+// CycleCountMethod - How a cycle-count plan picks what to count
+type CycleCountMethod string
+
+const (
+	CycleCountMethodABC      CycleCountMethod = "ABC"      // Produk kelas A/B/C (nilai pemakaian) dengan siklus masing-masing
+	CycleCountMethodLocation CycleCountMethod = "LOCATION" // Bergiliran per lokasi bin gudang
+)
+
 // StockTransferStatus - Inter-warehouse transfer workflow
 type StockTransferStatus string
 
@@ -231,6 +302,130 @@ const (
 	StockTransferStatusCancelled StockTransferStatus = "CANCELLED" // Cancelled
 )
 
+// ProductRecallStatus - Product recall workflow
+type ProductRecallStatus string
+
+const (
+	ProductRecallStatusOpen   ProductRecallStatus = "OPEN"   // Stok ditahan, retur customer berjalan
+	ProductRecallStatusClosed ProductRecallStatus = "CLOSED" // Recall selesai
+)
+
+// QualityHoldStatus - Quality hold (quarantine) lifecycle
+type QualityHoldStatus string
+
+const (
+	QualityHoldStatusOpen     QualityHoldStatus = "OPEN"     // Batch di karantina, menunggu keputusan QC
+	QualityHoldStatusReleased QualityHoldStatus = "RELEASED" // Sisa stok dilepas ke stok tersedia
+	QualityHoldStatusScrapped QualityHoldStatus = "SCRAPPED" // Seluruh stok dihapusbukukan
+)
+
+// QualityHoldSource - What placed a batch on quality hold
+type QualityHoldSource string
+
+const (
+	QualityHoldSourceGoodsReceipt   QualityHoldSource = "GOODS_RECEIPT"   // Otomatis dari inspeksi GRN
+	QualityHoldSourceCustomerReturn QualityHoldSource = "CUSTOMER_RETURN" // Retur customer diinspeksi ke karantina
+	QualityHoldSourceManual         QualityHoldSource = "MANUAL"          // Ditahan manual oleh QC
+)
+
+// QualityHoldActionType - Decision taken on held stock
+type QualityHoldActionType string
+
+const (
+	QualityHoldActionRelease QualityHoldActionType = "RELEASE" // Lepas ke stok tersedia
+	QualityHoldActionScrap   QualityHoldActionType = "SCRAP"   // Hapus buku (barang rusak)
+)
+
+// CustomerReturnStatus - Customer return (RMA) lifecycle
+type CustomerReturnStatus string
+
+const (
+	CustomerReturnStatusDraft     CustomerReturnStatus = "DRAFT"     // Dicatat, barang belum diinspeksi
+	CustomerReturnStatusInspected CustomerReturnStatus = "INSPECTED" // Disposisi tiap baris sudah ditentukan
+	CustomerReturnStatusCompleted CustomerReturnStatus = "COMPLETED" // Stok diposting, nota kredit dibuat
+	CustomerReturnStatusCancelled CustomerReturnStatus = "CANCELLED" // Dibatalkan sebelum diposting
+)
+
+// ReturnDisposition - Where inspected customer return goods go
+type ReturnDisposition string
+
+const (
+	ReturnDispositionSellable   ReturnDisposition = "SELLABLE"   // Kembali ke stok tersedia
+	ReturnDispositionQuarantine ReturnDisposition = "QUARANTINE" // Masuk karantina, menunggu keputusan QC
+	ReturnDispositionScrap      ReturnDisposition = "SCRAP"      // Dihapusbukukan (rusak)
+)
+
+// SalesCreditNoteStatus - Sales credit note settlement status
+type SalesCreditNoteStatus string
+
+const (
+	SalesCreditNoteStatusOpen    SalesCreditNoteStatus = "OPEN"    // Masih ada sisa kredit customer
+	SalesCreditNoteStatusApplied SalesCreditNoteStatus = "APPLIED" // Seluruh kredit sudah dipakai atau dikembalikan
+)
+
+// SalesNoteCategory - Why a sales credit or debit note was issued
+type SalesNoteCategory string
+
+const (
+	SalesNoteCategoryReturn           SalesNoteCategory = "RETURN"            // Retur barang dari customer
+	SalesNoteCategoryPriceCorrection  SalesNoteCategory = "PRICE_CORRECTION"  // Koreksi harga invoice
+	SalesNoteCategoryShortShipment    SalesNoteCategory = "SHORT_SHIPMENT"    // Barang kurang kirim
+	SalesNoteCategoryAdditionalCharge SalesNoteCategory = "ADDITIONAL_CHARGE" // Biaya tambahan (ongkir, dll.)
+	SalesNoteCategoryOther            SalesNoteCategory = "OTHER"             // Lainnya
+)
+
+// ConsignmentReportType - What the customer reported in a consignment settlement
+type ConsignmentReportType string
+
+const (
+	ConsignmentReportTypeSold   ConsignmentReportType = "SOLD"    // Qty terjual sejak laporan terakhir
+	ConsignmentReportTypeOnHand ConsignmentReportType = "ON_HAND" // Qty yang masih ada di customer
+)
+
+// AssemblyOrderType - Direction of an assembly order
+type AssemblyOrderType string
+
+const (
+	AssemblyOrderTypeAssembly    AssemblyOrderType = "ASSEMBLY"    // Komponen dirakit menjadi produk jadi (parcel/hampers)
+	AssemblyOrderTypeDisassembly AssemblyOrderType = "DISASSEMBLY" // Produk jadi dibongkar kembali menjadi komponen
+)
+
+// AssemblyOrderStatus - Assembly order workflow
+type AssemblyOrderStatus string
+
+const (
+	AssemblyOrderStatusDraft     AssemblyOrderStatus = "DRAFT"     // Dibuat, stok belum diposting
+	AssemblyOrderStatusCompleted AssemblyOrderStatus = "COMPLETED" // Stok komponen dan produk jadi sudah diposting
+	AssemblyOrderStatusCancelled AssemblyOrderStatus = "CANCELLED" // Dibatalkan sebelum diposting
+)
+
+// SerialStatus - Where an individual serial-tracked unit currently is
+type SerialStatus string
+
+const (
+	SerialStatusInStock   SerialStatus = "IN_STOCK"   // Ada di gudang
+	SerialStatusInTransit SerialStatus = "IN_TRANSIT" // Dalam transfer antar gudang
+	SerialStatusDelivered SerialStatus = "DELIVERED"  // Sudah dikirim ke customer
+	SerialStatusLost      SerialStatus = "LOST"       // Hilang/rusak saat transfer
+	SerialStatusScrapped  SerialStatus = "SCRAPPED"   // Dihapusbukukan (retur rusak)
+)
+
+// SerialEventType - Step in the history of a serial number
+type SerialEventType string
+
+const (
+	SerialEventReceived          SerialEventType = "RECEIVED"           // Diterima dari supplier (GRN)
+	SerialEventTransferShipped   SerialEventType = "TRANSFER_SHIPPED"   // Dikirim ke gudang lain
+	SerialEventTransferReceived  SerialEventType = "TRANSFER_RECEIVED"  // Diterima di gudang tujuan
+	SerialEventTransferCancelled SerialEventType = "TRANSFER_CANCELLED" // Transfer dibatalkan, kembali ke gudang asal
+	SerialEventLost              SerialEventType = "LOST"               // Tidak sampai di gudang tujuan
+	SerialEventDelivered         SerialEventType = "DELIVERED"          // Dikirim ke customer
+	SerialEventDeliveryCancelled SerialEventType = "DELIVERY_CANCELLED" // Pengiriman dibatalkan, kembali ke gudang
+	SerialEventReturned          SerialEventType = "RETURNED"           // Retur dari customer
+	SerialEventReturnCancelled   SerialEventType = "RETURN_CANCELLED"   // Retur dibatalkan, kembali ke customer
+	SerialEventScrapped          SerialEventType = "SCRAPPED"           // Dihapusbukukan
+)
+
 // DeliveryType - Delivery classification
 type DeliveryType string
 
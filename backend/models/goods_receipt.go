@@ -70,6 +70,8 @@ type GoodsReceiptItem struct {
 	OrderedQty         decimal.Decimal `gorm:"type:decimal(15,3);not null"`
 	ReceivedQty        decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Physically received
 	AcceptedQty        decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Passed quality inspection
+	BaseReceivedQty    decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // ReceivedQty in product base unit
+	BaseAcceptedQty    decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // AcceptedQty in product base unit (posted to stock)
 	RejectedQty           decimal.Decimal      `gorm:"type:decimal(15,3);default:0"` // Failed quality inspection
 	RejectionReason       *string              `gorm:"type:text"`
 	RejectionDisposition  *RejectionDisposition `gorm:"type:varchar(30)"` // Disposition for rejected goods (Odoo+M3 model)
@@ -78,6 +80,8 @@ type GoodsReceiptItem struct {
 	DispositionNotes         *string              `gorm:"type:text"`         // Notes when setting disposition
 	DispositionResolvedNotes *string              `gorm:"type:text"`         // Notes when resolving disposition
 	QualityNote              *string              `gorm:"type:text"`
+	QualityHold              bool                 `gorm:"default:false"`           // Accepted quantity goes to quarantine (set at inspection)
+	PutAwayBinID             *string              `gorm:"type:varchar(255);index"` // Bin the accepted quantity was put away to
 	Notes              *string         `gorm:"type:text"`
 	CreatedAt          time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt          time.Time       `gorm:"autoUpdateTime"`
@@ -88,6 +92,7 @@ type GoodsReceiptItem struct {
 	Product             Product           `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
 	ProductUnit         *ProductUnit      `gorm:"foreignKey:ProductUnitID"`
 	DispositionResolver *User             `gorm:"foreignKey:DispositionResolvedBy"` // User who resolved the rejection disposition
+	PutAwayBin          *WarehouseBin     `gorm:"foreignKey:PutAwayBinID"`
 }
 
 // TableName specifies the table name for GoodsReceiptItem model
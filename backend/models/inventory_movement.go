@@ -19,10 +19,13 @@ type InventoryMovement struct {
 	WarehouseID     string          `gorm:"type:varchar(255);not null;index"`
 	ProductID       string          `gorm:"type:varchar(255);not null;index"`
 	BatchID         *string         `gorm:"type:varchar(255);index"` // Required if product.isBatchTracked
+	BinID           *string         `gorm:"type:varchar(255);index"` // Bin the stock entered or left, when bins are used
 	MovementType    MovementType    `gorm:"type:varchar(20);not null;index"`
 	Quantity        decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Positive = IN, Negative = OUT
 	StockBefore     decimal.Decimal `gorm:"type:decimal(15,3);not null"`
 	StockAfter      decimal.Decimal `gorm:"type:decimal(15,3);not null"`
+	UnitCost        decimal.Decimal `gorm:"type:decimal(15,4);default:0"` // Unit cost added (IN) or consumed (OUT)
+	TotalCost       decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Quantity * UnitCost, signed like Quantity
 	ReferenceType   *string         `gorm:"type:varchar(50)"` // GOODS_RECEIPT, DELIVERY, ADJUSTMENT, etc.
 	ReferenceID     *string         `gorm:"type:varchar(255);index"`
 	ReferenceNumber *string         `gorm:"type:varchar(100)"` // GRN-001, DEL-001, etc.
@@ -37,6 +40,7 @@ type InventoryMovement struct {
 	Warehouse Warehouse     `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
 	Product   Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
 	Batch     *ProductBatch `gorm:"foreignKey:BatchID"`
+	Bin       *WarehouseBin `gorm:"foreignKey:BinID"`
 }
 
 // TableName specifies the table name for InventoryMovement model
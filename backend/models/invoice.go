@@ -25,6 +25,8 @@ type Invoice struct {
 	TaxAmount       decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
 	TotalAmount     decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
 	PaidAmount      decimal.Decimal `gorm:"type:decimal(15,2);default:0;index"`
+	CreditedAmount  decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Settled by sales credit notes
+	DebitedAmount   decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Added by sales debit notes
 	PaymentStatus   PaymentStatus   `gorm:"type:varchar(20);default:'UNPAID';index"`
 	Notes           *string         `gorm:"type:text"`
 	FakturPajakNo   *string         `gorm:"type:varchar(100);uniqueIndex"` // Tax invoice number
@@ -55,6 +57,45 @@ func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// AmountDue returns the invoice total including sales debit notes
+func (i *Invoice) AmountDue() decimal.Decimal {
+	return i.TotalAmount.Add(i.DebitedAmount)
+}
+
+// RemainingAmount returns what the customer still owes after payments and credit notes
+func (i *Invoice) RemainingAmount() decimal.Decimal {
+	return i.AmountDue().Sub(i.PaidAmount).Sub(i.CreditedAmount)
+}
+
+// UpdatePaymentStatus updates payment status based on the paid and credited amounts
+func (i *Invoice) UpdatePaymentStatus() {
+	settled := i.PaidAmount.Add(i.CreditedAmount)
+	if settled.GreaterThanOrEqual(i.AmountDue()) {
+		i.PaymentStatus = PaymentStatusPaid
+	} else if settled.GreaterThan(decimal.Zero) {
+		i.PaymentStatus = PaymentStatusPartial
+	} else {
+		i.PaymentStatus = PaymentStatusUnpaid
+	}
+}
+
+// AdjustmentShare returns the invoice-level discount and tax that belong to a credited
+// or debited subtotal, in the same proportion as on the invoice
+func (i *Invoice) AdjustmentShare(subtotal decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	discountAmount := decimal.Zero
+	if i.Subtotal.IsPositive() && i.DiscountAmount.IsPositive() {
+		discountAmount = subtotal.Mul(i.DiscountAmount).Div(i.Subtotal).Round(2)
+	}
+
+	taxAmount := decimal.Zero
+	taxBase := i.Subtotal.Sub(i.DiscountAmount)
+	if taxBase.IsPositive() && i.TaxAmount.IsPositive() {
+		taxAmount = subtotal.Sub(discountAmount).Mul(i.TaxAmount).Div(taxBase).Round(2)
+	}
+
+	return discountAmount, taxAmount
+}
+
 // InvoiceItem - Invoice line items
 type InvoiceItem struct {
 	ID               string          `gorm:"type:varchar(255);primaryKey"`
@@ -63,8 +104,9 @@ type InvoiceItem struct {
 	DeliveryItemID   *string         `gorm:"type:varchar(255);index"`
 	ProductID        string          `gorm:"type:varchar(255);not null;index"`
 	ProductUnitID    *string         `gorm:"type:varchar(255);index"`
-	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"`
-	UnitPrice        decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity     decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Quantity in product base unit
+	UnitPrice        decimal.Decimal `gorm:"type:decimal(15,2);not null"`  // Per ProductUnit
 	DiscountPct      decimal.Decimal `gorm:"type:decimal(5,2);default:0"`
 	DiscountAmt      decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
 	Subtotal         decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
@@ -73,11 +115,11 @@ type InvoiceItem struct {
 	UpdatedAt        time.Time       `gorm:"autoUpdateTime"`
 
 	// Relations
-	Invoice         Invoice          `gorm:"foreignKey:InvoiceID;constraint:OnDelete:CASCADE"`
-	SalesOrderItem  *SalesOrderItem  `gorm:"foreignKey:SalesOrderItemID"`
-	DeliveryItem    *DeliveryItem    `gorm:"foreignKey:DeliveryItemID"`
-	Product         Product          `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
-	ProductUnit     *ProductUnit     `gorm:"foreignKey:ProductUnitID"`
+	Invoice        Invoice         `gorm:"foreignKey:InvoiceID;constraint:OnDelete:CASCADE"`
+	SalesOrderItem *SalesOrderItem `gorm:"foreignKey:SalesOrderItemID"`
+	DeliveryItem   *DeliveryItem   `gorm:"foreignKey:DeliveryItemID"`
+	Product        Product         `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	ProductUnit    *ProductUnit    `gorm:"foreignKey:ProductUnitID"`
 }
 
 // TableName specifies the table name for InvoiceItem model
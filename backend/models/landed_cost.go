@@ -0,0 +1,56 @@
+// Package models - Landed cost allocation models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// LandedCostAllocation - Share of a purchase invoice's extra charges (shipping, handling, other)
+// allocated to one goods receipt line when the invoice is approved.
+// CapitalizedAmount was added to the cost of stock still on hand; ExpensedAmount belongs to
+// quantity that had already left the warehouse and could not be revalued.
+type LandedCostAllocation struct {
+	ID                 string                     `gorm:"type:varchar(255);primaryKey"`
+	TenantID           string                     `gorm:"type:varchar(255);not null;index"`
+	CompanyID          string                     `gorm:"type:varchar(255);not null;index"`
+	PurchaseInvoiceID  string                     `gorm:"type:varchar(255);not null;index:idx_landed_cost_invoice"`
+	GoodsReceiptID     string                     `gorm:"type:varchar(255);not null;index"`
+	GoodsReceiptItemID string                     `gorm:"type:varchar(255);not null"`
+	WarehouseID        string                     `gorm:"type:varchar(255);not null"`
+	ProductID          string                     `gorm:"type:varchar(255);not null;index"`
+	AllocationMethod   LandedCostAllocationMethod `gorm:"type:varchar(20);not null"`
+	Quantity           decimal.Decimal            `gorm:"type:decimal(15,3);not null"`  // Accepted quantity on the GRN line (base unit)
+	BasisValue         decimal.Decimal            `gorm:"type:decimal(20,4);not null"`  // Value, quantity or weight used as the allocation basis
+	AllocatedAmount    decimal.Decimal            `gorm:"type:decimal(20,4);not null"`  // Share of the invoice's extra charges
+	CapitalizedAmount  decimal.Decimal            `gorm:"type:decimal(20,4);default:0"` // Added to inventory value
+	ExpensedAmount     decimal.Decimal            `gorm:"type:decimal(20,4);default:0"` // For quantity already consumed
+	MovementID         *string                    `gorm:"type:varchar(255)"`            // Zero-quantity revaluation movement
+	CreatedAt          time.Time                  `gorm:"autoCreateTime"`
+	CreatedBy          *string                    `gorm:"type:varchar(255)"`
+
+	// Relations
+	Tenant           Tenant           `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company          Company          `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	PurchaseInvoice  PurchaseInvoice  `gorm:"foreignKey:PurchaseInvoiceID;constraint:OnDelete:CASCADE"`
+	GoodsReceipt     GoodsReceipt     `gorm:"foreignKey:GoodsReceiptID;constraint:OnDelete:RESTRICT"`
+	GoodsReceiptItem GoodsReceiptItem `gorm:"foreignKey:GoodsReceiptItemID;constraint:OnDelete:RESTRICT"`
+	Warehouse        Warehouse        `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product          Product          `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+}
+
+// TableName specifies the table name for LandedCostAllocation model
+func (LandedCostAllocation) TableName() string {
+	return "landed_cost_allocations"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (lca *LandedCostAllocation) BeforeCreate(tx *gorm.DB) error {
+	if lca.ID == "" {
+		lca.ID = uuid.New().String()
+	}
+	return nil
+}
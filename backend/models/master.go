@@ -31,6 +31,7 @@ type Customer struct {
 	CreditLimit        decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
 	CurrentOutstanding decimal.Decimal `gorm:"type:decimal(15,2);default:0;index"`
 	OverdueAmount      decimal.Decimal `gorm:"type:decimal(15,2);default:0;index"`
+	CreditBalance      decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Unapplied sales credit notes
 	LastTransactionAt  *time.Time      `gorm:"type:timestamp"`
 	Notes              *string         `gorm:"type:text"`
 	IsActive           bool            `gorm:"default:true"`
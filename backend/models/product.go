@@ -11,33 +11,36 @@ import (
 
 // Product - Product master with multi-unit support
 type Product struct {
-	ID             string          `gorm:"type:varchar(255);primaryKey"`
-	TenantID       string          `gorm:"type:varchar(255);not null;index"`
-	CompanyID      string          `gorm:"type:varchar(255);not null;index:idx_company_product;uniqueIndex:idx_company_product_code"`
-	Code           string          `gorm:"type:varchar(100);not null;index;uniqueIndex:idx_company_product_code"` // SKU
-	Name           string          `gorm:"type:varchar(255);not null;index"`
-	Category       *string         `gorm:"type:varchar(100)"`
-	BaseUnit       string          `gorm:"type:varchar(20);default:'PCS'"` // Unit terkecil
-	BaseCost       decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
-	BasePrice      decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
-	CurrentStock   decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // DEPRECATED: Use WarehouseStock
-	MinimumStock   decimal.Decimal `gorm:"type:decimal(15,3);default:0"`
-	Description    *string         `gorm:"type:text"`
-	Barcode        *string         `gorm:"type:varchar(100);uniqueIndex"`
-	IsBatchTracked bool            `gorm:"default:false;index"` // Requires batch/lot tracking
-	IsPerishable   bool            `gorm:"default:false"`       // Has expiry date
-	IsActive       bool            `gorm:"default:true"`
-	CreatedAt      time.Time       `gorm:"autoCreateTime"`
-	UpdatedAt      time.Time       `gorm:"autoUpdateTime"`
+	ID              string          `gorm:"type:varchar(255);primaryKey"`
+	TenantID        string          `gorm:"type:varchar(255);not null;index"`
+	CompanyID       string          `gorm:"type:varchar(255);not null;index:idx_company_product;uniqueIndex:idx_company_product_code"`
+	Code            string          `gorm:"type:varchar(100);not null;index;uniqueIndex:idx_company_product_code"` // SKU
+	Name            string          `gorm:"type:varchar(255);not null;index"`
+	Category        *string         `gorm:"type:varchar(100)"`
+	BaseUnit        string          `gorm:"type:varchar(20);default:'PCS'"` // Unit terkecil
+	BaseCost        decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	BasePrice       decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	CurrentStock    decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // DEPRECATED: Use WarehouseStock
+	MinimumStock    decimal.Decimal `gorm:"type:decimal(15,3);default:0"`
+	Description     *string         `gorm:"type:text"`
+	Barcode         *string         `gorm:"type:varchar(100);uniqueIndex"`
+	IsBatchTracked  bool            `gorm:"default:false;index"` // Requires batch/lot tracking
+	IsSerialTracked bool            `gorm:"default:false;index"` // Requires a serial number per unit
+	HoldOnReceipt   bool            `gorm:"default:false"`       // Received batches go to quarantine until quality release
+	IsPerishable    bool            `gorm:"default:false"`       // Has expiry date
+	IsActive        bool            `gorm:"default:true"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`
 
 	// Relations
-	Tenant             Tenant            `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
-	Company            Company           `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
-	Units              []ProductUnit     `gorm:"foreignKey:ProductID"`
-	PriceList          []PriceList       `gorm:"foreignKey:ProductID"`
-	Batches            []ProductBatch    `gorm:"foreignKey:ProductID"`
-	ProductSuppliers   []ProductSupplier `gorm:"foreignKey:ProductID"`
-	WarehouseStocks    []WarehouseStock  `gorm:"foreignKey:ProductID"`
+	Tenant           Tenant                `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company          Company               `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Units            []ProductUnit         `gorm:"foreignKey:ProductID"`
+	PriceList        []PriceList           `gorm:"foreignKey:ProductID"`
+	Batches          []ProductBatch        `gorm:"foreignKey:ProductID"`
+	ProductSuppliers []ProductSupplier     `gorm:"foreignKey:ProductID"`
+	WarehouseStocks  []WarehouseStock      `gorm:"foreignKey:ProductID"`
+	BOMComponents    []ProductBOMComponent `gorm:"foreignKey:ProductID"` // Bill of materials (assembled products)
 	// Note: SalesOrderItems, InvoiceItems, etc. will be added in Phase 3
 }
 
@@ -58,9 +61,9 @@ func (p *Product) BeforeCreate(tx *gorm.DB) error {
 // CRITICAL for perishable items with expiry dates
 type ProductBatch struct {
 	ID               string          `gorm:"type:varchar(255);primaryKey"`
-	BatchNumber      string          `gorm:"type:varchar(100);not null;uniqueIndex:idx_batch_product"` // e.g., "BATCH-2025-001"
-	ProductID        string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_batch_product"`
-	WarehouseStockID string          `gorm:"type:varchar(255);not null;index"`
+	BatchNumber      string          `gorm:"type:varchar(100);not null;uniqueIndex:idx_batch_product_stock"` // e.g., "BATCH-2025-001"
+	ProductID        string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_batch_product_stock"`
+	WarehouseStockID string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_batch_product_stock"` // Same batch number may exist per warehouse (transfers)
 	ManufactureDate  *time.Time      `gorm:"type:timestamp"`
 	ExpiryDate       *time.Time      `gorm:"type:timestamp;index"` // CRITICAL for sembako
 	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"`
@@ -0,0 +1,97 @@
+// Package models - Quality hold (quarantine) models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// QualityHold - A batch held in quarantine until quality releases or scraps it.
+// While the hold is open the batch is QUARANTINE: its stock counts as on hand but not as available.
+type QualityHold struct {
+	ID               string            `gorm:"type:varchar(255);primaryKey"`
+	TenantID         string            `gorm:"type:varchar(255);not null;index"`
+	CompanyID        string            `gorm:"type:varchar(255);not null;index:idx_company_quality_hold;uniqueIndex:idx_company_hold_number"`
+	HoldNumber       string            `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_hold_number"`
+	HoldDate         time.Time         `gorm:"type:timestamp;not null;index"`
+	WarehouseID      string            `gorm:"type:varchar(255);not null;index"`
+	ProductID        string            `gorm:"type:varchar(255);not null;index"`
+	BatchID          string            `gorm:"type:varchar(255);not null;index"`
+	Source           QualityHoldSource `gorm:"type:varchar(20);not null"`
+	GoodsReceiptID   *string           `gorm:"type:varchar(255);index"` // Set for holds placed by GRN inspection
+	CustomerReturnID *string           `gorm:"type:varchar(255);index"` // Set for holds placed by customer return inspection
+	Status           QualityHoldStatus `gorm:"type:varchar(20);default:'OPEN';index"`
+	PreviousStatus   BatchStatus       `gorm:"type:varchar(20);not null"`    // Batch status before the hold
+	HeldQuantity     decimal.Decimal   `gorm:"type:decimal(15,3);default:0"` // Quantity placed on hold (base unit)
+	ReleasedQuantity decimal.Decimal   `gorm:"type:decimal(15,3);default:0"` // Returned to available stock (base unit)
+	ScrappedQuantity decimal.Decimal   `gorm:"type:decimal(15,3);default:0"` // Written off (base unit)
+	Reason           string            `gorm:"type:text;not null"`
+	Notes            *string           `gorm:"type:text"`
+	ClosedAt         *time.Time        `gorm:"type:timestamp"`
+	CreatedBy        *string           `gorm:"type:varchar(255)"`
+	CreatedAt        time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time         `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant         Tenant              `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company        Company             `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Warehouse      Warehouse           `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product        Product             `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Batch          ProductBatch        `gorm:"foreignKey:BatchID;constraint:OnDelete:RESTRICT"`
+	GoodsReceipt   *GoodsReceipt       `gorm:"foreignKey:GoodsReceiptID"`
+	CustomerReturn *CustomerReturn     `gorm:"foreignKey:CustomerReturnID"`
+	Actions        []QualityHoldAction `gorm:"foreignKey:QualityHoldID"`
+}
+
+// TableName specifies the table name for QualityHold model
+func (QualityHold) TableName() string {
+	return "quality_holds"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (qh *QualityHold) BeforeCreate(tx *gorm.DB) error {
+	if qh.ID == "" {
+		qh.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RemainingQuantity returns the quantity still on hold (base unit)
+func (qh *QualityHold) RemainingQuantity() decimal.Decimal {
+	return qh.HeldQuantity.Sub(qh.ReleasedQuantity).Sub(qh.ScrappedQuantity)
+}
+
+// QualityHoldAction - A release or scrap decision on a hold, with its approver and reason
+type QualityHoldAction struct {
+	ID            string                `gorm:"type:varchar(255);primaryKey"`
+	QualityHoldID string                `gorm:"type:varchar(255);not null;index"`
+	Action        QualityHoldActionType `gorm:"type:varchar(20);not null"`
+	Quantity      decimal.Decimal       `gorm:"type:decimal(15,3);not null"` // Base unit
+	Reason        string                `gorm:"type:text;not null"`
+	Notes         *string               `gorm:"type:text"`
+	MovementID    *string               `gorm:"type:varchar(255);index"` // Scrap: the write-off movement
+	ApprovedBy    string                `gorm:"type:varchar(255);not null"`
+	ApprovedAt    time.Time             `gorm:"type:timestamp;not null"`
+	CreatedAt     time.Time             `gorm:"autoCreateTime"`
+
+	// Relations
+	QualityHold QualityHold        `gorm:"foreignKey:QualityHoldID;constraint:OnDelete:CASCADE"`
+	Approver    *User              `gorm:"foreignKey:ApprovedBy"`
+	Movement    *InventoryMovement `gorm:"foreignKey:MovementID"`
+}
+
+// TableName specifies the table name for QualityHoldAction model
+func (QualityHoldAction) TableName() string {
+	return "quality_hold_actions"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (qha *QualityHoldAction) BeforeCreate(tx *gorm.DB) error {
+	if qha.ID == "" {
+		qha.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Package models - Product recall models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ProductRecall - Recall of a product lot, identified by batch or supplier lot number
+type ProductRecall struct {
+	ID                string              `gorm:"type:varchar(255);primaryKey"`
+	TenantID          string              `gorm:"type:varchar(255);not null;index"`
+	CompanyID         string              `gorm:"type:varchar(255);not null;index:idx_company_recall;uniqueIndex:idx_company_recall_number"`
+	RecallNumber      string              `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_recall_number"`
+	RecallDate        time.Time           `gorm:"type:timestamp;not null;index"`
+	ProductID         *string             `gorm:"type:varchar(255);index"` // Set when recalling a batch number of one product
+	BatchNumber       *string             `gorm:"type:varchar(100);index"` // Internal batch number
+	SupplierID        *string             `gorm:"type:varchar(255);index"` // Narrows a supplier lot recall
+	SupplierReference *string             `gorm:"type:varchar(100);index"` // Supplier's batch/lot number
+	Reason            string              `gorm:"type:text;not null"`
+	Status            ProductRecallStatus `gorm:"type:varchar(20);default:'OPEN';index"`
+	HeldQuantity      decimal.Decimal     `gorm:"type:decimal(15,3);default:0"` // Stock on hand placed on hold (base unit)
+	DeliveredQuantity decimal.Decimal     `gorm:"type:decimal(15,3);default:0"` // Quantity still at customers, to be returned (base unit)
+	ClosedBy          *string             `gorm:"type:varchar(255)"`
+	ClosedAt          *time.Time          `gorm:"type:timestamp"`
+	Notes             *string             `gorm:"type:text"`
+	CreatedBy         *string             `gorm:"type:varchar(255)"`
+	CreatedAt         time.Time           `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time           `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant     Tenant                  `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company    Company                 `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Product    *Product                `gorm:"foreignKey:ProductID"`
+	Supplier   *Supplier               `gorm:"foreignKey:SupplierID"`
+	Batches    []ProductRecallBatch    `gorm:"foreignKey:ProductRecallID"`
+	Deliveries []ProductRecallDelivery `gorm:"foreignKey:ProductRecallID"`
+}
+
+// TableName specifies the table name for ProductRecall model
+func (ProductRecall) TableName() string {
+	return "product_recalls"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (pr *ProductRecall) BeforeCreate(tx *gorm.DB) error {
+	if pr.ID == "" {
+		pr.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ProductRecallBatch - A recalled batch in one warehouse, with its source goods receipt
+type ProductRecallBatch struct {
+	ID              string          `gorm:"type:varchar(255);primaryKey"`
+	ProductRecallID string          `gorm:"type:varchar(255);not null;index"`
+	BatchID         string          `gorm:"type:varchar(255);not null;index"`
+	ProductID       string          `gorm:"type:varchar(255);not null;index"`
+	WarehouseID     string          `gorm:"type:varchar(255);not null;index"`
+	BatchNumber     string          `gorm:"type:varchar(100);not null"`
+	PreviousStatus  BatchStatus     `gorm:"type:varchar(20);not null"`   // Batch status before the hold
+	HeldQuantity    decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Quantity on hand when recalled (base unit)
+	GoodsReceiptID  *string         `gorm:"type:varchar(255);index"`     // Backward trace
+	SupplierID      *string         `gorm:"type:varchar(255);index"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	ProductRecall ProductRecall `gorm:"foreignKey:ProductRecallID;constraint:OnDelete:CASCADE"`
+	Batch         ProductBatch  `gorm:"foreignKey:BatchID;constraint:OnDelete:RESTRICT"`
+	Product       Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Warehouse     Warehouse     `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	GoodsReceipt  *GoodsReceipt `gorm:"foreignKey:GoodsReceiptID"`
+	Supplier      *Supplier     `gorm:"foreignKey:SupplierID"`
+}
+
+// TableName specifies the table name for ProductRecallBatch model
+func (ProductRecallBatch) TableName() string {
+	return "product_recall_batches"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (prb *ProductRecallBatch) BeforeCreate(tx *gorm.DB) error {
+	if prb.ID == "" {
+		prb.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ProductRecallDelivery - A delivery line that shipped a recalled batch to a customer (forward trace)
+type ProductRecallDelivery struct {
+	ID               string          `gorm:"type:varchar(255);primaryKey"`
+	ProductRecallID  string          `gorm:"type:varchar(255);not null;index"`
+	DeliveryID       string          `gorm:"type:varchar(255);not null;index"`
+	DeliveryItemID   string          `gorm:"type:varchar(255);not null;index"`
+	CustomerID       string          `gorm:"type:varchar(255);not null;index"`
+	BatchID          string          `gorm:"type:varchar(255);not null;index"`
+	ProductID        string          `gorm:"type:varchar(255);not null;index"`
+	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Quantity still at the customer, to be returned (base unit)
+	ReturnDeliveryID *string         `gorm:"type:varchar(255);index"`     // RETURN delivery opened for the customer
+	CreatedAt        time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	ProductRecall  ProductRecall `gorm:"foreignKey:ProductRecallID;constraint:OnDelete:CASCADE"`
+	Delivery       Delivery      `gorm:"foreignKey:DeliveryID;constraint:OnDelete:RESTRICT"`
+	DeliveryItem   DeliveryItem  `gorm:"foreignKey:DeliveryItemID;constraint:OnDelete:RESTRICT"`
+	Customer       Customer      `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	Batch          ProductBatch  `gorm:"foreignKey:BatchID;constraint:OnDelete:RESTRICT"`
+	Product        Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	ReturnDelivery *Delivery     `gorm:"foreignKey:ReturnDeliveryID"`
+}
+
+// TableName specifies the table name for ProductRecallDelivery model
+func (ProductRecallDelivery) TableName() string {
+	return "product_recall_deliveries"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (prd *ProductRecallDelivery) BeforeCreate(tx *gorm.DB) error {
+	if prd.ID == "" {
+		prd.ID = uuid.New().String()
+	}
+	return nil
+}
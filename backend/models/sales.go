@@ -65,9 +65,10 @@ type SalesOrderItem struct {
 	ID            string          `gorm:"type:varchar(255);primaryKey"`
 	SalesOrderID  string          `gorm:"type:varchar(255);not null;index"`
 	ProductID     string          `gorm:"type:varchar(255);not null;index"`
-	ProductUnitID *string         `gorm:"type:varchar(255);index"` // NULL = base unit
-	Quantity      decimal.Decimal `gorm:"type:decimal(15,3);not null"`
-	UnitPrice     decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	ProductUnitID *string         `gorm:"type:varchar(255);index"`      // NULL = base unit
+	Quantity      decimal.Decimal `gorm:"type:decimal(15,3);not null"`  // In ProductUnit
+	BaseQuantity  decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Quantity in product base unit
+	UnitPrice     decimal.Decimal `gorm:"type:decimal(15,2);not null"`  // Per ProductUnit
 	DiscountPct   decimal.Decimal `gorm:"type:decimal(5,2);default:0"`
 	DiscountAmt   decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
 	Subtotal      decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
@@ -0,0 +1,157 @@
+// Package models - Sales credit note models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SalesCreditNote - Credit given to a customer against an invoice (returned goods, price
+// corrections, short shipments). The credit settles the invoice's open balance first; what
+// is left stays with the customer as credit balance until it is applied or refunded.
+type SalesCreditNote struct {
+	ID               string                `gorm:"type:varchar(255);primaryKey"`
+	TenantID         string                `gorm:"type:varchar(255);not null;index"`
+	CompanyID        string                `gorm:"type:varchar(255);not null;index:idx_company_sales_credit_note;uniqueIndex:idx_company_credit_note_number"`
+	CreditNoteNumber string                `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_credit_note_number"`
+	CreditNoteDate   time.Time             `gorm:"type:timestamp;not null;index"`
+	CustomerID       string                `gorm:"type:varchar(255);not null;index"`
+	InvoiceID        *string               `gorm:"type:varchar(255);index"`
+	CustomerReturnID *string               `gorm:"type:varchar(255);index"`
+	Category         SalesNoteCategory     `gorm:"type:varchar(30);default:'OTHER';index"`
+	Reason           string                `gorm:"type:text;not null"`
+	Subtotal         decimal.Decimal       `gorm:"type:decimal(15,2);default:0"`
+	DiscountAmount   decimal.Decimal       `gorm:"type:decimal(15,2);default:0"` // Share of the invoice discount
+	TaxAmount        decimal.Decimal       `gorm:"type:decimal(15,2);default:0"` // At the invoice's tax rate
+	TotalAmount      decimal.Decimal       `gorm:"type:decimal(15,2);default:0"`
+	AppliedAmount    decimal.Decimal       `gorm:"type:decimal(15,2);default:0"` // Used against invoices
+	RefundedAmount   decimal.Decimal       `gorm:"type:decimal(15,2);default:0"` // Paid back to the customer
+	Status           SalesCreditNoteStatus `gorm:"type:varchar(20);default:'OPEN';index"`
+	Notes            *string               `gorm:"type:text"`
+	CreatedBy        *string               `gorm:"type:varchar(255)"`
+	CreatedAt        time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time             `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant         Tenant                       `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company        Company                      `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Customer       Customer                     `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	Invoice        *Invoice                     `gorm:"foreignKey:InvoiceID"`
+	CustomerReturn *CustomerReturn              `gorm:"foreignKey:CustomerReturnID"`
+	Items          []SalesCreditNoteItem        `gorm:"foreignKey:CreditNoteID"`
+	Applications   []SalesCreditNoteApplication `gorm:"foreignKey:CreditNoteID"`
+	Refunds        []SalesCreditNoteRefund      `gorm:"foreignKey:CreditNoteID"`
+}
+
+// TableName specifies the table name for SalesCreditNote model
+func (SalesCreditNote) TableName() string {
+	return "sales_credit_notes"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (scn *SalesCreditNote) BeforeCreate(tx *gorm.DB) error {
+	if scn.ID == "" {
+		scn.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// UnappliedAmount returns the credit not yet used against invoices or refunded
+func (scn *SalesCreditNote) UnappliedAmount() decimal.Decimal {
+	return scn.TotalAmount.Sub(scn.AppliedAmount).Sub(scn.RefundedAmount)
+}
+
+// SalesCreditNoteItem - Credited line
+type SalesCreditNoteItem struct {
+	ID                   string          `gorm:"type:varchar(255);primaryKey"`
+	CreditNoteID         string          `gorm:"type:varchar(255);not null;index"`
+	InvoiceItemID        *string         `gorm:"type:varchar(255);index"`
+	CustomerReturnItemID *string         `gorm:"type:varchar(255);index"`
+	ProductID            *string         `gorm:"type:varchar(255);index"`
+	Description          string          `gorm:"type:varchar(500);not null"`
+	Quantity             decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Base unit
+	UnitPrice            decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Per base unit
+	Subtotal             decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	CreatedAt            time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	CreditNote  SalesCreditNote `gorm:"foreignKey:CreditNoteID;constraint:OnDelete:CASCADE"`
+	InvoiceItem *InvoiceItem    `gorm:"foreignKey:InvoiceItemID"`
+	Product     *Product        `gorm:"foreignKey:ProductID"`
+}
+
+// TableName specifies the table name for SalesCreditNoteItem model
+func (SalesCreditNoteItem) TableName() string {
+	return "sales_credit_note_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (scni *SalesCreditNoteItem) BeforeCreate(tx *gorm.DB) error {
+	if scni.ID == "" {
+		scni.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SalesCreditNoteApplication - Part of a credit note used to settle an invoice
+type SalesCreditNoteApplication struct {
+	ID           string          `gorm:"type:varchar(255);primaryKey"`
+	CreditNoteID string          `gorm:"type:varchar(255);not null;index"`
+	InvoiceID    string          `gorm:"type:varchar(255);not null;index"`
+	Amount       decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	AppliedAt    time.Time       `gorm:"type:timestamp;not null"`
+	AppliedBy    *string         `gorm:"type:varchar(255)"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	CreditNote SalesCreditNote `gorm:"foreignKey:CreditNoteID;constraint:OnDelete:CASCADE"`
+	Invoice    Invoice         `gorm:"foreignKey:InvoiceID;constraint:OnDelete:RESTRICT"`
+}
+
+// TableName specifies the table name for SalesCreditNoteApplication model
+func (SalesCreditNoteApplication) TableName() string {
+	return "sales_credit_note_applications"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (scna *SalesCreditNoteApplication) BeforeCreate(tx *gorm.DB) error {
+	if scna.ID == "" {
+		scna.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SalesCreditNoteRefund - Unapplied credit paid back to the customer
+type SalesCreditNoteRefund struct {
+	ID            string          `gorm:"type:varchar(255);primaryKey"`
+	CreditNoteID  string          `gorm:"type:varchar(255);not null;index"`
+	RefundDate    time.Time       `gorm:"type:timestamp;not null;index"`
+	Amount        decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	PaymentMethod PaymentMethod   `gorm:"type:varchar(20);not null"`
+	Reference     *string         `gorm:"type:varchar(100)"` // Transfer reference, check number, etc.
+	BankAccountID *string         `gorm:"type:varchar(255);index"`
+	Notes         *string         `gorm:"type:text"`
+	CreatedBy     *string         `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	CreditNote  SalesCreditNote `gorm:"foreignKey:CreditNoteID;constraint:OnDelete:CASCADE"`
+	BankAccount *CompanyBank    `gorm:"foreignKey:BankAccountID"`
+}
+
+// TableName specifies the table name for SalesCreditNoteRefund model
+func (SalesCreditNoteRefund) TableName() string {
+	return "sales_credit_note_refunds"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (scnr *SalesCreditNoteRefund) BeforeCreate(tx *gorm.DB) error {
+	if scnr.ID == "" {
+		scnr.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package models - Sales debit note models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SalesDebitNote - Additional amount charged to a customer against an invoice (price
+// corrections, extra charges). The total is added to what is due on the invoice.
+type SalesDebitNote struct {
+	ID              string            `gorm:"type:varchar(255);primaryKey"`
+	TenantID        string            `gorm:"type:varchar(255);not null;index"`
+	CompanyID       string            `gorm:"type:varchar(255);not null;index:idx_company_sales_debit_note;uniqueIndex:idx_company_debit_note_number"`
+	DebitNoteNumber string            `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_debit_note_number"`
+	DebitNoteDate   time.Time         `gorm:"type:timestamp;not null;index"`
+	CustomerID      string            `gorm:"type:varchar(255);not null;index"`
+	InvoiceID       string            `gorm:"type:varchar(255);not null;index"`
+	Category        SalesNoteCategory `gorm:"type:varchar(30);default:'OTHER';index"`
+	Reason          string            `gorm:"type:text;not null"`
+	Subtotal        decimal.Decimal   `gorm:"type:decimal(15,2);default:0"`
+	DiscountAmount  decimal.Decimal   `gorm:"type:decimal(15,2);default:0"` // Share of the invoice discount
+	TaxAmount       decimal.Decimal   `gorm:"type:decimal(15,2);default:0"` // At the invoice's tax rate
+	TotalAmount     decimal.Decimal   `gorm:"type:decimal(15,2);default:0"`
+	Notes           *string           `gorm:"type:text"`
+	CreatedBy       *string           `gorm:"type:varchar(255)"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time         `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant   Tenant               `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company  Company              `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Customer Customer             `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	Invoice  Invoice              `gorm:"foreignKey:InvoiceID;constraint:OnDelete:RESTRICT"`
+	Items    []SalesDebitNoteItem `gorm:"foreignKey:DebitNoteID"`
+}
+
+// TableName specifies the table name for SalesDebitNote model
+func (SalesDebitNote) TableName() string {
+	return "sales_debit_notes"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (sdn *SalesDebitNote) BeforeCreate(tx *gorm.DB) error {
+	if sdn.ID == "" {
+		sdn.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SalesDebitNoteItem - Debited line
+type SalesDebitNoteItem struct {
+	ID            string          `gorm:"type:varchar(255);primaryKey"`
+	DebitNoteID   string          `gorm:"type:varchar(255);not null;index"`
+	InvoiceItemID *string         `gorm:"type:varchar(255);index"`
+	ProductID     *string         `gorm:"type:varchar(255);index"`
+	Description   string          `gorm:"type:varchar(500);not null"`
+	Quantity      decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Base unit
+	UnitPrice     decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Per base unit
+	Subtotal      decimal.Decimal `gorm:"type:decimal(15,2);default:0"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	DebitNote   SalesDebitNote `gorm:"foreignKey:DebitNoteID;constraint:OnDelete:CASCADE"`
+	InvoiceItem *InvoiceItem   `gorm:"foreignKey:InvoiceItemID"`
+	Product     *Product       `gorm:"foreignKey:ProductID"`
+}
+
+// TableName specifies the table name for SalesDebitNoteItem model
+func (SalesDebitNoteItem) TableName() string {
+	return "sales_debit_note_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (sdni *SalesDebitNoteItem) BeforeCreate(tx *gorm.DB) error {
+	if sdni.ID == "" {
+		sdni.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Package models - Serial number tracking models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductSerial - One unit of a serial-tracked product.
+// Registered on goods receipt approval and moved by transfers, deliveries and returns.
+type ProductSerial struct {
+	ID                string       `gorm:"type:varchar(255);primaryKey"`
+	TenantID          string       `gorm:"type:varchar(255);not null;index"`
+	CompanyID         string       `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_company_product_serial"`
+	ProductID         string       `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_company_product_serial"`
+	SerialNumber      string       `gorm:"type:varchar(100);not null;index;uniqueIndex:idx_company_product_serial"`
+	Status            SerialStatus `gorm:"type:varchar(20);not null;index"`
+	WarehouseID       *string      `gorm:"type:varchar(255);index"` // Current warehouse (IN_STOCK) or transfer destination (IN_TRANSIT)
+	BatchID           *string      `gorm:"type:varchar(255);index"` // Batch the unit was received in (batch-tracked products)
+	SupplierID        *string      `gorm:"type:varchar(255);index"`
+	GoodsReceiptID    *string      `gorm:"type:varchar(255);index"`
+	ReceivedAt        time.Time    `gorm:"type:timestamp;not null"`
+	CustomerID        *string      `gorm:"type:varchar(255);index"` // Customer holding the unit (DELIVERED)
+	DeliveryID        *string      `gorm:"type:varchar(255);index"` // Last delivery of the unit to a customer
+	WarrantyStartDate *time.Time   `gorm:"type:timestamp"`          // Delivery date of the last delivery
+	CreatedAt         time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time    `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant       Tenant        `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company      Company       `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Product      Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Warehouse    *Warehouse    `gorm:"foreignKey:WarehouseID"`
+	Batch        *ProductBatch `gorm:"foreignKey:BatchID"`
+	Supplier     *Supplier     `gorm:"foreignKey:SupplierID"`
+	GoodsReceipt *GoodsReceipt `gorm:"foreignKey:GoodsReceiptID"`
+	Customer     *Customer     `gorm:"foreignKey:CustomerID"`
+	Delivery     *Delivery     `gorm:"foreignKey:DeliveryID"`
+}
+
+// TableName specifies the table name for ProductSerial model
+func (ProductSerial) TableName() string {
+	return "product_serials"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ps *ProductSerial) BeforeCreate(tx *gorm.DB) error {
+	if ps.ID == "" {
+		ps.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ProductSerialEvent - One step in the path of a serial from supplier to customer
+type ProductSerialEvent struct {
+	ID              string          `gorm:"type:varchar(255);primaryKey"`
+	TenantID        string          `gorm:"type:varchar(255);not null;index"`
+	CompanyID       string          `gorm:"type:varchar(255);not null;index"`
+	SerialID        string          `gorm:"type:varchar(255);not null;index"`
+	EventType       SerialEventType `gorm:"type:varchar(30);not null"`
+	EventDate       time.Time       `gorm:"type:timestamp;not null;index"`
+	Status          SerialStatus    `gorm:"type:varchar(20);not null"` // Serial status after the event
+	WarehouseID     *string         `gorm:"type:varchar(255);index"`
+	CustomerID      *string         `gorm:"type:varchar(255);index"`
+	SupplierID      *string         `gorm:"type:varchar(255);index"`
+	ReferenceType   string          `gorm:"type:varchar(50);not null;index:idx_serial_event_reference"` // GOODS_RECEIPT, DELIVERY, STOCK_TRANSFER
+	ReferenceID     string          `gorm:"type:varchar(255);not null;index:idx_serial_event_reference"`
+	ReferenceNumber string          `gorm:"type:varchar(100)"`
+	Notes           *string         `gorm:"type:text"`
+	CreatedBy       *string         `gorm:"type:varchar(255)"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	Serial    ProductSerial `gorm:"foreignKey:SerialID;constraint:OnDelete:CASCADE"`
+	Warehouse *Warehouse    `gorm:"foreignKey:WarehouseID"`
+	Customer  *Customer     `gorm:"foreignKey:CustomerID"`
+	Supplier  *Supplier     `gorm:"foreignKey:SupplierID"`
+}
+
+// TableName specifies the table name for ProductSerialEvent model
+func (ProductSerialEvent) TableName() string {
+	return "product_serial_events"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (pse *ProductSerialEvent) BeforeCreate(tx *gorm.DB) error {
+	if pse.ID == "" {
+		pse.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// DocumentLineSerial - Serial picked on a delivery or transfer line.
+// Stored when the document is created; the serial moves when the line's stock is posted.
+type DocumentLineSerial struct {
+	ID            string    `gorm:"type:varchar(255);primaryKey"`
+	SerialID      string    `gorm:"type:varchar(255);not null;index"`
+	ReferenceType string    `gorm:"type:varchar(50);not null;index:idx_line_serial_reference"` // DELIVERY, STOCK_TRANSFER
+	ReferenceID   string    `gorm:"type:varchar(255);not null;index:idx_line_serial_reference"`
+	LineID        string    `gorm:"type:varchar(255);not null;index"` // DeliveryItem / StockTransferItem
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+
+	// Relations
+	Serial ProductSerial `gorm:"foreignKey:SerialID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for DocumentLineSerial model
+func (DocumentLineSerial) TableName() string {
+	return "document_line_serials"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (dls *DocumentLineSerial) BeforeCreate(tx *gorm.DB) error {
+	if dls.ID == "" {
+		dls.ID = uuid.New().String()
+	}
+	return nil
+}
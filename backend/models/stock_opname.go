@@ -18,6 +18,9 @@ type StockOpname struct {
 	OpnameDate   time.Time         `gorm:"type:timestamp;not null;index"`
 	WarehouseID  string            `gorm:"type:varchar(255);not null;index"`
 	Status       StockOpnameStatus `gorm:"type:varchar(20);default:'DRAFT';index"`
+	IsBlindCount bool              `gorm:"default:false"` // System quantities hidden from counters until COMPLETED
+	CycleCountPlanID *string       `gorm:"type:varchar(255);index"` // Set when created by a cycle-count plan
+	FreezeScope  OpnameFreezeScope `gorm:"type:varchar(20);default:'NONE'"` // Postings blocked while IN_PROGRESS
 	CountedBy   *string           `gorm:"type:varchar(255)"` // User who performed count
 	ApprovedBy  *string           `gorm:"type:varchar(255)"` // User who approved adjustments
 	ApprovedAt  *time.Time        `gorm:"type:timestamp"`
@@ -30,6 +33,7 @@ type StockOpname struct {
 	Company   Company           `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
 	Warehouse Warehouse         `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
 	Items     []StockOpnameItem `gorm:"foreignKey:StockOpnameID"`
+	CycleCountPlan *CycleCountPlan `gorm:"foreignKey:CycleCountPlanID"`
 }
 
 // TableName specifies the table name for StockOpname model
@@ -45,15 +49,26 @@ func (so *StockOpname) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// HidesSystemQty reports whether system quantities (and so differences) must be hidden:
+// blind counts reveal them only once counting is COMPLETED
+func (so *StockOpname) HidesSystemQty() bool {
+	if !so.IsBlindCount {
+		return false
+	}
+	return so.Status != StockOpnameStatusCompleted && so.Status != StockOpnameStatusApproved
+}
+
 // StockOpnameItem - Physical count line items
 type StockOpnameItem struct {
 	ID             string          `gorm:"type:varchar(255);primaryKey"`
 	StockOpnameID  string          `gorm:"type:varchar(255);not null;index"`
 	ProductID      string          `gorm:"type:varchar(255);not null;index"`
 	BatchID        *string         `gorm:"type:varchar(255);index"` // For batch-tracked products
+	BinID          *string         `gorm:"type:varchar(255);index"` // Set when the line counts one bin (location cycle counts)
 	SystemQty      decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Qty per system
 	PhysicalQty    decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Actual counted qty
 	DifferenceQty  decimal.Decimal `gorm:"type:decimal(15,3);not null"` // Physical - System
+	CountedAt      *time.Time      `gorm:"type:timestamp"`              // When PhysicalQty was entered; approval takes SystemQty as of this instant
 	Notes          *string         `gorm:"type:text"`
 	CreatedAt      time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt      time.Time       `gorm:"autoUpdateTime"`
@@ -62,6 +77,7 @@ type StockOpnameItem struct {
 	StockOpname StockOpname   `gorm:"foreignKey:StockOpnameID;constraint:OnDelete:CASCADE"`
 	Product     Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
 	Batch       *ProductBatch `gorm:"foreignKey:BatchID"`
+	Bin         *WarehouseBin `gorm:"foreignKey:BinID"`
 }
 
 // TableName specifies the table name for StockOpnameItem model
@@ -76,3 +92,70 @@ func (soi *StockOpnameItem) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// CycleCountPlan - Rolling schedule that creates DRAFT stock opnames for subsets of a warehouse
+// ABC plans count A/B/C products at their own cycle; LOCATION plans count bins in rotation.
+type CycleCountPlan struct {
+	ID            string           `gorm:"type:varchar(255);primaryKey"`
+	TenantID      string           `gorm:"type:varchar(255);not null;index"`
+	CompanyID     string           `gorm:"type:varchar(255);not null;index:idx_company_cycle_count_plan"`
+	Name          string           `gorm:"type:varchar(255);not null"`
+	WarehouseID   string           `gorm:"type:varchar(255);not null;index"`
+	Method        CycleCountMethod `gorm:"type:varchar(20);not null"`
+	FrequencyDays int              `gorm:"not null;default:7"`  // A count is created every N days
+	ClassADays    int              `gorm:"not null;default:30"` // ABC: every A product counted within N days
+	ClassBDays    int              `gorm:"not null;default:90"`
+	ClassCDays    int              `gorm:"not null;default:180"`
+	CycleDays     int              `gorm:"not null;default:90"` // LOCATION: every bin counted within N days
+	BlindCount    bool             `gorm:"default:true"`        // Created opnames are blind counts
+	IsActive      bool             `gorm:"default:true;index"`
+	NextRunDate   time.Time        `gorm:"type:timestamp;not null;index"`
+	LastRunAt     *time.Time       `gorm:"type:timestamp"`
+	Notes         *string          `gorm:"type:text"`
+	CreatedBy     *string          `gorm:"type:varchar(255)"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant    Tenant    `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company   Company   `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Warehouse Warehouse `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+}
+
+// TableName specifies the table name for CycleCountPlan model
+func (CycleCountPlan) TableName() string {
+	return "cycle_count_plans"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ccp *CycleCountPlan) BeforeCreate(tx *gorm.DB) error {
+	if ccp.ID == "" {
+		ccp.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// CycleCountCoverage - When a plan last scheduled a product (ABC) or bin (LOCATION) for counting
+type CycleCountCoverage struct {
+	ID               string    `gorm:"type:varchar(255);primaryKey"`
+	CycleCountPlanID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_cycle_count_coverage"`
+	SubjectID        string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_cycle_count_coverage"` // Product ID or bin ID
+	StockOpnameID    string    `gorm:"type:varchar(255);not null"`                                     // Opname that last covered it
+	LastScheduledAt  time.Time `gorm:"type:timestamp;not null"`
+
+	// Relations
+	CycleCountPlan CycleCountPlan `gorm:"foreignKey:CycleCountPlanID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for CycleCountCoverage model
+func (CycleCountCoverage) TableName() string {
+	return "cycle_count_coverages"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ccc *CycleCountCoverage) BeforeCreate(tx *gorm.DB) error {
+	if ccc.ID == "" {
+		ccc.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package models - Stock Reservation models
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// StockReservation - Stock reserved for an approved sales order line
+// Reserved quantity is held against WarehouseStock.ReservedQuantity until
+// deliveries consume it or the sales order is cancelled.
+type StockReservation struct {
+	ID               string                 `gorm:"type:varchar(255);primaryKey"`
+	TenantID         string                 `gorm:"type:varchar(255);not null;index"`
+	CompanyID        string                 `gorm:"type:varchar(255);not null;index:idx_company_stock_reservation"`
+	WarehouseID      string                 `gorm:"type:varchar(255);not null;index"`
+	ProductID        string                 `gorm:"type:varchar(255);not null;index"`
+	SalesOrderID     string                 `gorm:"type:varchar(255);not null;index"`
+	SalesOrderItemID string                 `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Quantity         decimal.Decimal        `gorm:"type:decimal(15,3);not null"`  // Reserved quantity (base unit)
+	ConsumedQuantity decimal.Decimal        `gorm:"type:decimal(15,3);default:0"` // Quantity already delivered (base unit)
+	Status           StockReservationStatus `gorm:"type:varchar(20);default:'ACTIVE';index"`
+	ReleasedAt       *time.Time             `gorm:"type:timestamp"`
+	CreatedAt        time.Time              `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time              `gorm:"autoUpdateTime"`
+
+	// Relations
+	Tenant         Tenant         `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company        Company        `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Warehouse      Warehouse      `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product        Product        `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	SalesOrder     SalesOrder     `gorm:"foreignKey:SalesOrderID;constraint:OnDelete:CASCADE"`
+	SalesOrderItem SalesOrderItem `gorm:"foreignKey:SalesOrderItemID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for StockReservation model
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (sr *StockReservation) BeforeCreate(tx *gorm.DB) error {
+	if sr.ID == "" {
+		sr.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RemainingQuantity returns the quantity still held by the reservation
+func (sr *StockReservation) RemainingQuantity() decimal.Decimal {
+	return sr.Quantity.Sub(sr.ConsumedQuantity)
+}
@@ -0,0 +1,73 @@
+// Package models - Stock Snapshot models (point-in-time stock)
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// StockSnapshot - Stored stock position of a company at a cutoff (usually month end)
+// Holds the sum of all InventoryMovement rows dated before SnapshotAt, so "as-of" queries
+// only need to replay movements after the latest snapshot.
+type StockSnapshot struct {
+	ID         string          `gorm:"type:varchar(255);primaryKey"`
+	TenantID   string          `gorm:"type:varchar(255);not null;index"`
+	CompanyID  string          `gorm:"type:varchar(255);not null;uniqueIndex:idx_company_snapshot_at"`
+	SnapshotAt time.Time       `gorm:"type:timestamp;not null;uniqueIndex:idx_company_snapshot_at"` // Exclusive cutoff: movements before this instant
+	Period     string          `gorm:"type:varchar(7);not null;index"`                              // Month covered, YYYY-MM
+	ItemCount  int             `gorm:"not null;default:0"`
+	TotalValue decimal.Decimal `gorm:"type:decimal(20,2);default:0"`
+	CreatedBy  *string         `gorm:"type:varchar(255)"` // nil when created by the scheduler
+	CreatedAt  time.Time       `gorm:"autoCreateTime"`
+
+	// Relations
+	Tenant  Tenant              `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
+	Company Company             `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+	Items   []StockSnapshotItem `gorm:"foreignKey:StockSnapshotID"`
+}
+
+// TableName specifies the table name for StockSnapshot model
+func (StockSnapshot) TableName() string {
+	return "stock_snapshots"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ss *StockSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if ss.ID == "" {
+		ss.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// StockSnapshotItem - Quantity and value of one product (and batch) in one warehouse at the snapshot cutoff
+type StockSnapshotItem struct {
+	ID              string          `gorm:"type:varchar(255);primaryKey"`
+	StockSnapshotID string          `gorm:"type:varchar(255);not null;index"`
+	WarehouseID     string          `gorm:"type:varchar(255);not null;index"`
+	ProductID       string          `gorm:"type:varchar(255);not null;index"`
+	BatchID         *string         `gorm:"type:varchar(255);index"` // nil = stock without a batch
+	Quantity        decimal.Decimal `gorm:"type:decimal(15,3);not null"`
+	Value           decimal.Decimal `gorm:"type:decimal(15,2);default:0"` // Sum of movement total cost
+
+	// Relations
+	StockSnapshot StockSnapshot `gorm:"foreignKey:StockSnapshotID;constraint:OnDelete:CASCADE"`
+	Warehouse     Warehouse     `gorm:"foreignKey:WarehouseID;constraint:OnDelete:RESTRICT"`
+	Product       Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
+	Batch         *ProductBatch `gorm:"foreignKey:BatchID"`
+}
+
+// TableName specifies the table name for StockSnapshotItem model
+func (StockSnapshotItem) TableName() string {
+	return "stock_snapshot_items"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (ssi *StockSnapshotItem) BeforeCreate(tx *gorm.DB) error {
+	if ssi.ID == "" {
+		ssi.ID = uuid.New().String()
+	}
+	return nil
+}
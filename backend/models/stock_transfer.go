@@ -53,16 +53,23 @@ type StockTransferItem struct {
 	ID              string          `gorm:"type:varchar(255);primaryKey"`
 	StockTransferID string          `gorm:"type:varchar(255);not null;index"`
 	ProductID       string          `gorm:"type:varchar(255);not null;index"`
-	BatchID         *string         `gorm:"type:varchar(255);index"` // For batch-tracked products
+	BatchID         *string         `gorm:"type:varchar(255);index"` // Source batch for batch-tracked products
+	DestBatchID     *string         `gorm:"type:varchar(255);index"` // Batch created/updated at the destination on receipt
 	Quantity        decimal.Decimal `gorm:"type:decimal(15,3);not null"`
 	Notes           *string         `gorm:"type:text"`
 	CreatedAt       time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`
 
+	// Receipt & discrepancy (set when the transfer is received)
+	ReceivedQuantity    *decimal.Decimal           `gorm:"type:decimal(15,3)"`
+	DiscrepancyQuantity decimal.Decimal            `gorm:"type:decimal(15,3);default:0"` // Shipped - received (shortage/damage)
+	DiscrepancyReason   *InventoryAdjustmentReason `gorm:"type:varchar(20)"`
+
 	// Relations
 	StockTransfer StockTransfer `gorm:"foreignKey:StockTransferID;constraint:OnDelete:CASCADE"`
 	Product       Product       `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT"`
 	Batch         *ProductBatch `gorm:"foreignKey:BatchID"`
+	DestBatch     *ProductBatch `gorm:"foreignKey:DestBatchID"`
 }
 
 // TableName specifies the table name for StockTransferItem model
@@ -24,6 +24,7 @@ type Warehouse struct {
 	Phone      *string         `gorm:"type:varchar(50)"`
 	Email      *string         `gorm:"type:varchar(255)"`
 	ManagerID  *string         `gorm:"type:varchar(255);index"`
+	CustomerID *string         `gorm:"type:varchar(255);index"` // Customer holding the stock (CONSIGNMENT only)
 	Capacity   *decimal.Decimal `gorm:"type:decimal(15,2)"` // Square meters or volume
 	IsActive   bool            `gorm:"default:true"`
 	CreatedAt  time.Time       `gorm:"autoCreateTime"`
@@ -33,6 +34,7 @@ type Warehouse struct {
 	Tenant  Tenant           `gorm:"foreignKey:TenantID;constraint:OnDelete:CASCADE"`
 	Company Company          `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
 	Manager *User            `gorm:"foreignKey:ManagerID"`
+	Customer *Customer       `gorm:"foreignKey:CustomerID"`
 	Stocks  []WarehouseStock `gorm:"foreignKey:WarehouseID"`
 	// Note: InventoryMovements, GoodsReceipts, Deliveries, StockOpnames, StockTransfers will be added in Phase 3-4
 }
@@ -53,17 +55,22 @@ func (w *Warehouse) BeforeCreate(tx *gorm.DB) error {
 // WarehouseStock - Stock per warehouse per product
 // This is the actual stock tracking table (Product.currentStock is deprecated)
 type WarehouseStock struct {
-	ID            string          `gorm:"type:varchar(255);primaryKey"`
-	WarehouseID   string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_warehouse_product"`
-	ProductID     string          `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_warehouse_product"`
-	Quantity      decimal.Decimal `gorm:"type:decimal(15,3);default:0;index"` // Stock quantity (base unit)
-	MinimumStock  decimal.Decimal `gorm:"type:decimal(15,3);default:0"`
-	MaximumStock  decimal.Decimal `gorm:"type:decimal(15,3);default:0"`
-	Location      *string         `gorm:"type:varchar(100)"` // e.g., "RAK-A-01", "ZONE-B"
-	LastCountDate *time.Time      `gorm:"type:timestamp"`
-	LastCountQty  *decimal.Decimal `gorm:"type:decimal(15,3)"`
-	CreatedAt     time.Time       `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time       `gorm:"autoUpdateTime"`
+	ID                 string           `gorm:"type:varchar(255);primaryKey"`
+	WarehouseID        string           `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_warehouse_product"`
+	ProductID          string           `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_warehouse_product"`
+	Quantity           decimal.Decimal  `gorm:"type:decimal(15,3);default:0;index"` // Stock quantity on hand (base unit)
+	ReservedQuantity   decimal.Decimal  `gorm:"type:decimal(15,3);default:0"`       // Reserved for approved sales orders (base unit)
+	InTransitQuantity  decimal.Decimal  `gorm:"type:decimal(15,3);default:0"`       // Shipped here by stock transfers, not yet received (base unit)
+	QuarantineQuantity decimal.Decimal  `gorm:"type:decimal(15,3);default:0"`       // On hand in held batches: quarantined, recalled, expired or damaged (base unit)
+	AverageCost        decimal.Decimal  `gorm:"type:decimal(15,4);default:0"`       // Moving weighted-average unit cost (base unit)
+	MinimumStock       decimal.Decimal  `gorm:"type:decimal(15,3);default:0"`
+	MaximumStock       decimal.Decimal  `gorm:"type:decimal(15,3);default:0"`
+	Location           *string          `gorm:"type:varchar(100)"` // e.g., "RAK-A-01", "ZONE-B"
+	LastCountDate      *time.Time       `gorm:"type:timestamp"`
+	LastCountQty       *decimal.Decimal `gorm:"type:decimal(15,3)"`
+	Version            int64            `gorm:"not null;default:0"` // Optimistic lock, incremented on every quantity change
+	CreatedAt          time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time        `gorm:"autoUpdateTime"`
 
 	// Relations
 	Warehouse Warehouse      `gorm:"foreignKey:WarehouseID;constraint:OnDelete:CASCADE"`
@@ -83,3 +90,74 @@ func (ws *WarehouseStock) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// AvailableQuantity returns on-hand quantity not reserved for sales orders or held in a batch that cannot be sold
+func (ws *WarehouseStock) AvailableQuantity() decimal.Decimal {
+	return ws.Quantity.Sub(ws.ReservedQuantity).Sub(ws.QuarantineQuantity)
+}
+
+// WarehouseBin - Bin location inside a warehouse (zone/aisle/rack/level)
+type WarehouseBin struct {
+	ID          string    `gorm:"type:varchar(255);primaryKey"`
+	TenantID    string    `gorm:"type:varchar(255);not null;index"`
+	WarehouseID string    `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_warehouse_bin_code"`
+	Code        string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_warehouse_bin_code"` // e.g., "A-01-03-2"
+	Zone        *string   `gorm:"type:varchar(50);index"`                                      // e.g., "A", "COLD"
+	Aisle       *string   `gorm:"type:varchar(20)"`
+	Rack        *string   `gorm:"type:varchar(20)"`
+	Level       *string   `gorm:"type:varchar(20)"`
+	IsDefault   bool      `gorm:"default:false"` // Default put-away bin for receipts
+	IsActive    bool      `gorm:"default:true"`
+	Notes       *string   `gorm:"type:text"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	// Relations
+	Warehouse Warehouse  `gorm:"foreignKey:WarehouseID;constraint:OnDelete:CASCADE"`
+	Stocks    []BinStock `gorm:"foreignKey:WarehouseBinID"`
+}
+
+// TableName specifies the table name for WarehouseBin model
+func (WarehouseBin) TableName() string {
+	return "warehouse_bins"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (wb *WarehouseBin) BeforeCreate(tx *gorm.DB) error {
+	if wb.ID == "" {
+		wb.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BinStock - Stock per bin per product (and per batch for batch-tracked products)
+// Stock of a WarehouseStock not held in any bin is unbinned (WarehouseStock.Quantity - bin quantities)
+type BinStock struct {
+	ID               string          `gorm:"type:varchar(255);primaryKey"`
+	WarehouseBinID   string          `gorm:"type:varchar(255);not null;index"`
+	WarehouseStockID string          `gorm:"type:varchar(255);not null;index"`
+	ProductID        string          `gorm:"type:varchar(255);not null;index"`
+	BatchID          *string         `gorm:"type:varchar(255);index"`
+	Quantity         decimal.Decimal `gorm:"type:decimal(15,3);default:0"` // Base unit
+	CreatedAt        time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time       `gorm:"autoUpdateTime"`
+
+	// Relations
+	WarehouseBin   WarehouseBin   `gorm:"foreignKey:WarehouseBinID;constraint:OnDelete:RESTRICT"`
+	WarehouseStock WarehouseStock `gorm:"foreignKey:WarehouseStockID;constraint:OnDelete:CASCADE"`
+	Product        Product        `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Batch          *ProductBatch  `gorm:"foreignKey:BatchID"`
+}
+
+// TableName specifies the table name for BinStock model
+func (BinStock) TableName() string {
+	return "bin_stocks"
+}
+
+// BeforeCreate hook to generate UUID for ID field
+func (bs *BinStock) BeforeCreate(tx *gorm.DB) error {
+	if bs.ID == "" {
+		bs.ID = uuid.New().String()
+	}
+	return nil
+}